@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/discogs"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+	"github.com/cehbz/classical-tagger/internal/tagging"
+	"github.com/cehbz/classical-tagger/internal/uploader"
+)
+
+// directoryTemplates are the directory naming choices offered, after the
+// built-in naming.
+var directoryTemplates = []string{
+	"{composer_sort} - {title} ({performers}) - {year} [{format}]",
+	"{composer_last} - {title} ({performers}) [{year}] [{format}]",
+	"{composer} - {title} [{year}] [{format}]",
+}
+
+// wizard asks the config init questions on out, reading answers from in.
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+	// discogs and redacted check an entered credential; nil skips the check
+	discogs  func(token string) check
+	redacted func(apiKey string) check
+}
+
+// runInit walks through creating the config file: the credentials, checked as
+// they are entered, the library roots and the naming conventions, previewed on
+// a sample album.
+func runInit(args []string) int {
+	flags := flag.NewFlagSet("init", flag.ExitOnError)
+	replace := flags.Bool("force", false, "Replace an existing config file without asking (it is kept as config.yaml.bak)")
+	offline := flags.Bool("offline", false, "Don't check the Discogs token and Redacted API key with Discogs and Redacted")
+	exitcode.ParseFlags(flags, args)
+
+	w := &wizard{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	if !*offline {
+		w.discogs = func(token string) check {
+			client := discogs.NewClient(token)
+			limits := config.LoadDiscogsLimits()
+			client.RateLimiter = ratelimit.NewRateLimiter(limits.Requests, limits.Window)
+			client.HTTPClient.Timeout = limits.Timeout
+			return discogsCheck(client)
+		}
+		w.redacted = func(apiKey string) check {
+			client := uploader.NewRedactedClient(apiKey)
+			limits := config.LoadRedactedLimits()
+			client.RateLimiter = ratelimit.NewRateLimiter(limits.Requests, limits.Window)
+			client.HTTPClient.Timeout = limits.Timeout
+			return redactedCheck(client)
+		}
+	}
+
+	path := config.GetConfigPathForDisplay()
+	fmt.Fprintf(w.out, "This creates the config file %s.\nPress Enter to accept the default in brackets or skip an optional setting.\n", path)
+	_, err := os.Stat(path)
+	exists := err == nil
+	if exists && !*replace {
+		ok, err := w.confirm("\nA config file already exists. Replace it (the old one is kept as config.yaml.bak)?", false)
+		if err != nil || !ok {
+			fmt.Fprintln(w.out, "Config file left unchanged.")
+			return exitcode.Abort
+		}
+	}
+
+	setup, err := w.run(context.Background())
+	if err != nil {
+		exitcode.Fail("Error", err)
+	}
+	content, err := setup.Render()
+	if err == nil {
+		err = config.WriteConfig(content, exists)
+	}
+	if err != nil {
+		exitcode.Fail("Error", err)
+	}
+
+	fmt.Fprintf(w.out, "\n✅ Wrote %s\n", path)
+	fmt.Fprintln(w.out, "Every other setting is documented there, commented out. Run config test to check it.")
+	return 0
+}
+
+// run asks every question and returns the answers. Running out of input aborts.
+func (w *wizard) run(ctx context.Context) (config.Setup, error) {
+	var setup config.Setup
+	var err error
+
+	fmt.Fprintf(w.out, "\n== Discogs ==\nextract looks releases up on Discogs with a personal access token;\ngenerate one at https://www.discogs.com/settings/developers.\n")
+	if setup.DiscogsToken, err = w.credential(ctx, "Discogs token", w.discogs); err != nil {
+		return setup, err
+	}
+
+	fmt.Fprintf(w.out, "\n== Redacted ==\nupload needs an API key with the Torrent scope (and Request to fill requests);\ncreate one under Settings → Access Settings on Redacted.\n")
+	if setup.RedactedAPIKey, err = w.credential(ctx, "Redacted API key", w.redacted); err != nil {
+		return setup, err
+	}
+
+	fmt.Fprintf(w.out, "\n== Library ==\nNamed roots let commands take a directory relative to them with -root NAME.\n")
+	incoming, err := w.directory("Directory new rips arrive in (the incoming root)")
+	if err != nil {
+		return setup, err
+	}
+	if incoming != "" {
+		root := config.NamedRoot{Name: "incoming", Root: config.Root{Path: incoming}}
+		if root.OutputRoot, err = w.directory("Directory tag writes tagged albums to (Enter: next to the source)"); err != nil {
+			return setup, err
+		}
+		setup.Roots = append(setup.Roots, root)
+	}
+	seeding, err := w.directory("Directory you seed uploads from (the seeding root)")
+	if err != nil {
+		return setup, err
+	}
+	if seeding != "" {
+		setup.Roots = append(setup.Roots, config.NamedRoot{Name: "seeding", Root: config.Root{Path: seeding}})
+	}
+
+	sample := sampleAlbum()
+	fmt.Fprintf(w.out, "\n== Naming ==\ntag names album directories and track files; previews are for a sample album.\n")
+	if setup.DirectoryTemplate, err = w.directoryTemplate(sample); err != nil {
+		return setup, err
+	}
+	policy, err := w.filenamePolicy(sample)
+	if err != nil {
+		return setup, err
+	}
+	if policy != domain.DefaultFilenamePolicy {
+		setup.FilenamePolicy = string(policy)
+	}
+	return setup, nil
+}
+
+// credential asks for a credential until the check passes, the user keeps
+// one that failed, or skips it ("", written as a placeholder).
+func (w *wizard) credential(ctx context.Context, name string, newCheck func(string) check) (string, error) {
+	for {
+		value, err := w.ask(name+" (Enter to skip): ", "")
+		if err != nil || value == "" {
+			if err == nil {
+				fmt.Fprintf(w.out, "Skipped; a placeholder is written for you to replace.\n")
+			}
+			return "", err
+		}
+		if newCheck == nil {
+			return value, nil
+		}
+		failed := runChecks(ctx, w.out, []check{newCheck(value)})
+		if len(failed) == 0 {
+			return value, nil
+		}
+		keep, err := w.confirm("Keep it anyway?", false)
+		if err != nil || keep {
+			return value, err
+		}
+	}
+}
+
+// directory asks for an optional directory, made absolute, warning when it
+// does not exist yet.
+func (w *wizard) directory(question string) (string, error) {
+	for {
+		answer, err := w.ask(question+": ", "")
+		if err != nil || answer == "" {
+			return "", err
+		}
+		dir := expandHome(answer)
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir, nil
+		}
+		ok, err := w.confirm(fmt.Sprintf("%s is not a directory yet. Use it anyway?", dir), true)
+		if err != nil || ok {
+			return dir, err
+		}
+	}
+}
+
+// directoryTemplate offers the directory naming choices with a preview of
+// each, and a custom template previewed as it is entered.
+func (w *wizard) directoryTemplate(sample *domain.Torrent) (string, error) {
+	fmt.Fprintf(w.out, "\nAlbum directory names:\n")
+	fmt.Fprintf(w.out, "  1) Built-in naming\n       %s\n", sample.DirectoryName())
+	for i, template := range directoryTemplates {
+		fmt.Fprintf(w.out, "  %d) %s\n       %s\n", i+2, template, sample.DirectoryNameFromTemplate(template))
+	}
+	custom := len(directoryTemplates) + 2
+	fmt.Fprintf(w.out, "  %d) Custom template\n", custom)
+
+	choice, err := w.choose(custom, 1)
+	if err != nil || choice == 1 {
+		return "", err
+	}
+	if choice < custom {
+		return directoryTemplates[choice-2], nil
+	}
+
+	fmt.Fprintf(w.out, "Placeholders: {composer}, {composer_last}, {composer_sort}, {title}, {performers}, {year}, {format}\n")
+	for {
+		template, err := w.ask("Template: ", "")
+		if err != nil || template == "" {
+			return "", err
+		}
+		if err := domain.ValidateDirectoryTemplate(template); err != nil {
+			fmt.Fprintf(w.out, "❌ %v\n", err)
+			continue
+		}
+		fmt.Fprintf(w.out, "       %s\n", sample.DirectoryNameFromTemplate(template))
+		ok, err := w.confirm("Use it?", true)
+		if err != nil || ok {
+			return template, err
+		}
+	}
+}
+
+// filenamePolicy offers the track filename conventions with a preview of
+// each on the sample album.
+func (w *wizard) filenamePolicy(sample *domain.Torrent) (domain.FilenamePolicy, error) {
+	policies := []domain.FilenamePolicy{domain.FilenamePolicyRedacted, domain.FilenamePolicyPlain}
+	fmt.Fprintf(w.out, "\nTrack filenames:\n")
+	for i, policy := range policies {
+		label := string(policy)
+		if policy == domain.DefaultFilenamePolicy {
+			label += " (checked by validation)"
+		}
+		fmt.Fprintf(w.out, "  %d) %s\n", i+1, label)
+		for _, track := range sample.Tracks()[:2] {
+			fmt.Fprintf(w.out, "       %s\n", tagging.GenerateFilename(track, sample, policy))
+		}
+	}
+	choice, err := w.choose(len(policies), 1)
+	if err != nil {
+		return "", err
+	}
+	return policies[choice-1], nil
+}
+
+// ask prints a prompt and reads a line, returning def for an empty one.
+// Running out of input aborts.
+func (w *wizard) ask(prompt, def string) (string, error) {
+	fmt.Fprint(w.out, prompt)
+	line, err := w.in.ReadString('\n')
+	answer := strings.TrimSpace(line)
+	if err != nil && answer == "" {
+		fmt.Fprintln(w.out)
+		return "", fmt.Errorf("%w: no more input", domain.ErrAborted)
+	}
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// confirm asks a yes/no question.
+func (w *wizard) confirm(question string, def bool) (bool, error) {
+	options := "[y/N]"
+	if def {
+		options = "[Y/n]"
+	}
+	for {
+		answer, err := w.ask(question+" "+options+" ", "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// choose reads a choice from 1 to n.
+func (w *wizard) choose(n, def int) (int, error) {
+	for {
+		answer, err := w.ask(fmt.Sprintf("Choice [1-%d, default %d]: ", n, def), strconv.Itoa(def))
+		if err != nil {
+			return 0, err
+		}
+		if choice, err := strconv.Atoi(answer); err == nil && choice >= 1 && choice <= n {
+			return choice, nil
+		}
+		fmt.Fprintf(w.out, "Please enter a number from 1 to %d.\n", n)
+	}
+}
+
+// expandHome replaces a leading ~ with the home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// sampleAlbum is the album the naming choices are previewed on: two composers,
+// so the filename conventions differ, with a soloist, orchestra and conductor.
+func sampleAlbum() *domain.Torrent {
+	performers := []domain.Artist{
+		{Name: "Anne-Sophie Mutter", Role: domain.RoleSoloist, Instrument: "violin"},
+		{Name: "Berliner Philharmoniker", Role: domain.RoleEnsemble},
+		{Name: "Herbert von Karajan", Role: domain.RoleConductor},
+	}
+	track := func(n int, composer, title string) *domain.Track {
+		return &domain.Track{
+			Disc: 1, Track: n, Title: title,
+			Artists: append([]domain.Artist{{Name: composer, Role: domain.RoleComposer}}, performers...),
+		}
+	}
+	return &domain.Torrent{
+		Title:        "Violin Concertos",
+		OriginalYear: 1981,
+		AlbumArtist: append([]domain.Artist{
+			{Name: "Felix Mendelssohn", Role: domain.RoleComposer},
+			{Name: "Max Bruch", Role: domain.RoleComposer},
+		}, performers...),
+		Files: []domain.FileLike{
+			track(1, "Felix Mendelssohn", "Violin Concerto in E minor, Op. 64: I. Allegro molto appassionato"),
+			track(2, "Max Bruch", "Violin Concerto No. 1 in G minor, Op. 26: I. Vorspiel. Allegro moderato"),
+		},
+	}
+}
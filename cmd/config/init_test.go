@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// stubCheck accepts only the credential "good".
+func stubCheck(name string) func(string) check {
+	return func(value string) check {
+		return check{name: name, run: func(context.Context) (string, error) {
+			if value != "good" {
+				return "", errors.New("rejected")
+			}
+			return "authenticated as listener", nil
+		}}
+	}
+}
+
+func TestWizard(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		input   []string
+		want    config.Setup
+		wantOut []string
+	}{
+		{
+			name:    "defaults",
+			input:   []string{"", "", "", "", "", ""},
+			want:    config.Setup{},
+			wantOut: []string{"Skipped", "Mendelssohn, Bruch - Violin Concertos", "01 - Mendelssohn - Violin Concerto"},
+		},
+		{
+			name: "everything chosen",
+			input: []string{
+				"bad", "n", "good", // Discogs: retry after a rejected token
+				"bad", "y", // Redacted: keep the rejected key
+				dir, "", dir, // incoming without an output root, seeding
+				"2", "2",
+			},
+			want: config.Setup{
+				DiscogsToken: "good", RedactedAPIKey: "bad",
+				DirectoryTemplate: directoryTemplates[0], FilenamePolicy: "plain",
+				Roots: []config.NamedRoot{
+					{Name: "incoming", Root: config.Root{Path: dir}},
+					{Name: "seeding", Root: config.Root{Path: dir}},
+				},
+			},
+			wantOut: []string{"❌ Discogs token: rejected", "✓ Discogs token: authenticated as listener", "❌ Redacted API key: rejected"},
+		},
+		{
+			name: "custom template and missing directories",
+			input: []string{
+				"", "",
+				filepath.Join(dir, "new"), "y", filepath.Join(dir, "tagged"), "n", "", "",
+				"5", "{title} [{label}]", "{composer_last} - {title} [{year}]", "", "1",
+			},
+			want: config.Setup{
+				DirectoryTemplate: "{composer_last} - {title} [{year}]",
+				Roots:             []config.NamedRoot{{Name: "incoming", Root: config.Root{Path: filepath.Join(dir, "new")}}},
+			},
+			wantOut: []string{"is not a directory yet", "{label}", "Mendelssohn, Bruch - Violin Concertos [1981]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			w := &wizard{
+				in:       bufio.NewReader(strings.NewReader(strings.Join(tt.input, "\n") + "\n")),
+				out:      &out,
+				discogs:  stubCheck("Discogs token"),
+				redacted: stubCheck("Redacted API key"),
+			}
+			got, err := w.run(context.Background())
+			if err != nil {
+				t.Fatalf("run() error = %v\n%s", err, out.String())
+			}
+			if got.DiscogsToken != tt.want.DiscogsToken || got.RedactedAPIKey != tt.want.RedactedAPIKey ||
+				got.DirectoryTemplate != tt.want.DirectoryTemplate || got.FilenamePolicy != tt.want.FilenamePolicy {
+				t.Errorf("run() = %+v, want %+v", got, tt.want)
+			}
+			if len(got.Roots) != len(tt.want.Roots) {
+				t.Fatalf("run() roots = %+v, want %+v", got.Roots, tt.want.Roots)
+			}
+			for i, root := range tt.want.Roots {
+				if got.Roots[i] != root {
+					t.Errorf("run() roots[%d] = %+v, want %+v", i, got.Roots[i], root)
+				}
+			}
+			for _, want := range tt.wantOut {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output lacks %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWizard_EndOfInput(t *testing.T) {
+	w := &wizard{in: bufio.NewReader(strings.NewReader("good\n")), out: &bytes.Buffer{}}
+	if _, err := w.run(context.Background()); !errors.Is(err, domain.ErrAborted) {
+		t.Errorf("run() error = %v, want ErrAborted", err)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/discogs"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+	"github.com/cehbz/classical-tagger/internal/uploader"
+)
+
+func main() {
+	flag.Usage = usage
+	exitcode.ParseFlags(flag.CommandLine, os.Args[1:])
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(exitcode.Failure)
+	}
+
+	switch flag.Arg(0) {
+	case "init":
+		os.Exit(runInit(flag.Args()[1:]))
+	case "test":
+		os.Exit(runTest(flag.Args()[1:]))
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(exitcode.Failure)
+	}
+}
+
+// check is one configured credential or tool, tested by run.
+type check struct {
+	name string
+	// run returns what was confirmed ("authenticated as ...") or why it failed
+	run func(ctx context.Context) (string, error)
+	// hint says how to fix a failure
+	hint func(err error) string
+}
+
+// runTest checks the config file and every credential in it with read-only requests.
+func runTest(args []string) int {
+	flags := flag.NewFlagSet("test", flag.ExitOnError)
+	console.AddFlag(flags)
+	exitcode.ParseFlags(flags, args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	console.Infof("Config file: %s\n\n", config.GetConfigPathForDisplay())
+	checks := []check{fileCheck(), discogsCheck(nil), redactedCheck(nil), mktorrentCheck()}
+	if failed := runChecks(ctx, os.Stdout, checks); len(failed) > 0 {
+		console.Infof("\n❌ %d of %d checks failed\n", len(failed), len(checks))
+		return failedCode(failed)
+	}
+	console.Infof("\n✅ All %d checks passed\n", len(checks))
+	return 0
+}
+
+// runChecks runs each check in turn, printing the outcome and a hint for
+// failures, and returns the errors of those that failed. Quiet, only the
+// failures are printed, one per line.
+func runChecks(ctx context.Context, w io.Writer, checks []check) []error {
+	var failed []error
+	for _, c := range checks {
+		detail, err := c.run(ctx)
+		if err == nil {
+			if !console.Quiet() {
+				fmt.Fprintf(w, "✓ %s: %s\n", c.name, detail)
+			}
+			continue
+		}
+		failed = append(failed, err)
+		if console.Quiet() {
+			fmt.Fprintf(w, "%s: %v\n", c.name, err)
+			continue
+		}
+		fmt.Fprintf(w, "❌ %s: %v\n", c.name, err)
+		if c.hint != nil {
+			if hint := c.hint(err); hint != "" {
+				fmt.Fprintf(w, "   Hint: %s\n", hint)
+			}
+		}
+	}
+	return failed
+}
+
+// failedCode is the exit code for the failed checks: the code their errors
+// share, e.g. Network when every failure was a rejected credential or an
+// unreachable service, otherwise Failure.
+func failedCode(failed []error) int {
+	code := exitcode.Code(failed[0])
+	for _, err := range failed[1:] {
+		if exitcode.Code(err) != code {
+			return exitcode.Failure
+		}
+	}
+	return code
+}
+
+// fileCheck checks that the config file exists and parses.
+func fileCheck() check {
+	return check{
+		name: "Config file",
+		run: func(context.Context) (string, error) {
+			if err := config.CheckFile(); err != nil {
+				return "", err
+			}
+			return "readable", nil
+		},
+		hint: func(err error) string {
+			if errors.Is(err, fs.ErrNotExist) {
+				return "create it with config init, or by hand (see the Configuration section of the README)"
+			}
+			return "fix the YAML syntax at the position given"
+		},
+	}
+}
+
+// discogsCheck checks the Discogs token by asking Discogs whose it is. A nil
+// client is built from the config file.
+func discogsCheck(client *discogs.Client) check {
+	return check{
+		name: "Discogs token",
+		run: func(ctx context.Context) (string, error) {
+			if client == nil {
+				token, err := config.LoadDiscogsToken()
+				if err != nil {
+					return "", err
+				}
+				client = discogs.NewClient(token)
+				limits := config.LoadDiscogsLimits()
+				client.RateLimiter = ratelimit.NewRateLimiter(limits.Requests, limits.Window)
+				client.HTTPClient.Timeout = limits.Timeout
+			}
+			if isPlaceholder(client.Token) {
+				return "", fmt.Errorf("still the sample value %q", client.Token)
+			}
+			user, err := client.Identity(ctx)
+			if err != nil {
+				return "", err
+			}
+			return "authenticated as " + user, nil
+		},
+		hint: func(err error) string {
+			return credentialHint(err, "Discogs", "generate a personal access token at https://www.discogs.com/settings/developers and set it as discogs.token")
+		},
+	}
+}
+
+// redactedCheck checks the Redacted API key by asking Redacted whose it is,
+// then that it has the Torrent scope upload needs. The Request scope, needed
+// only to fill requests, is reported but not required. A nil client is built
+// from the config file.
+func redactedCheck(client *uploader.RedactedClient) check {
+	return check{
+		name: "Redacted API key",
+		run: func(ctx context.Context) (string, error) {
+			if client == nil {
+				apiKey, err := config.LoadRedactedAPIKey()
+				if err != nil {
+					return "", err
+				}
+				client = uploader.NewRedactedClient(apiKey)
+				limits := config.LoadRedactedLimits()
+				client.RateLimiter = ratelimit.NewRateLimiter(limits.Requests, limits.Window)
+				client.HTTPClient.Timeout = limits.Timeout
+			}
+			if isPlaceholder(client.APIKey) {
+				return "", fmt.Errorf("still the sample value %q", client.APIKey)
+			}
+			user, err := client.Identity(ctx)
+			if err != nil {
+				return "", err
+			}
+			if err := client.CheckScope(ctx, "Torrent"); err != nil {
+				return "", err
+			}
+			detail := "authenticated as " + user + ", with the Torrent scope"
+			var missing *domain.ErrMissingScope
+			switch err := client.CheckScope(ctx, "Request"); {
+			case errors.As(err, &missing):
+				detail += " (no Request scope: upload -fill-request will fail)"
+			case err != nil:
+				return "", err
+			default:
+				detail += " and the Request scope"
+			}
+			return detail, nil
+		},
+		hint: func(err error) string {
+			return credentialHint(err, "Redacted", "create an API key with the Torrent scope (and Request to fill requests) under Settings → Access Settings on Redacted and set it as redacted.api_key")
+		},
+	}
+}
+
+// mktorrentCheck checks that mktorrent, which upload builds torrents with, is installed.
+func mktorrentCheck() check {
+	return check{
+		name: "mktorrent",
+		run: func(context.Context) (string, error) {
+			path, err := exec.LookPath("mktorrent")
+			if err != nil {
+				return "", errors.New("not found in PATH")
+			}
+			return path, nil
+		},
+		hint: func(error) string {
+			return "install it (apt-get install mktorrent, or brew install mktorrent); upload needs it to build .torrent files"
+		},
+	}
+}
+
+// credentialHint suggests a fix for a failed credential check: replacing a
+// rejected or missing credential, waiting out a rate limit, or checking the network.
+func credentialHint(err error, service, replace string) string {
+	var limited *ratelimit.ErrRateLimited
+	switch {
+	case errors.Is(err, discogs.ErrUnauthorized), errors.Is(err, uploader.ErrUnauthorized), errors.As(err, new(*domain.ErrMissingScope)),
+		strings.Contains(err.Error(), "not found"), strings.Contains(err.Error(), "sample value"):
+		return replace
+	case errors.As(err, &limited):
+		return fmt.Sprintf("%s is rate limiting requests; wait and run config test again", service)
+	default:
+		return fmt.Sprintf("check your network connection and that %s is up", service)
+	}
+}
+
+// isPlaceholder reports whether a credential is the sample config's placeholder ("your-...-here").
+func isPlaceholder(credential string) bool {
+	return strings.HasPrefix(credential, "your-") && strings.HasSuffix(credential, "-here")
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: config COMMAND\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "  init    Create the config file interactively: the Discogs token and Redacted API\n")
+	fmt.Fprintf(os.Stderr, "          key (checked as entered), library roots and naming, previewed on a\n")
+	fmt.Fprintf(os.Stderr, "          sample album (-force replaces an existing file, -offline skips checks)\n")
+	fmt.Fprintf(os.Stderr, "  test    Check the config file, the Discogs token and the Redacted API key with\n")
+	fmt.Fprintf(os.Stderr, "          read-only requests, and that mktorrent is installed (-quiet prints only\n")
+	fmt.Fprintf(os.Stderr, "          the failed checks)\n\n")
+	fmt.Fprintf(os.Stderr, "Config file location: %s\n", config.GetConfigPathForDisplay())
+	exitcode.PrintCodes(os.Stderr)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/discogs"
+	"github.com/cehbz/classical-tagger/internal/uploader"
+)
+
+func TestRunChecks_Discogs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/identity" {
+			t.Errorf("path = %q, want /oauth/identity", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Discogs token=good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id": 1, "username": "listener"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		token    string
+		want     string
+		wantHint bool
+	}{
+		{"valid", "good", "✓ Discogs token: authenticated as listener", false},
+		{"rejected", "bad", "❌ Discogs token: token rejected", true},
+		{"placeholder", "your-discogs-token-here", "still the sample value", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := discogs.NewClient(tt.token)
+			client.BaseURL = server.URL
+			var out bytes.Buffer
+			failed := runChecks(context.Background(), &out, []check{discogsCheck(client)})
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("output = %q, want it to contain %q", out.String(), tt.want)
+			}
+			if hinted := strings.Contains(out.String(), "discogs.com/settings/developers"); hinted != tt.wantHint {
+				t.Errorf("hinted = %v, want %v: %q", hinted, tt.wantHint, out.String())
+			}
+			if (len(failed) == 1) != tt.wantHint {
+				t.Errorf("failed = %v, want failure %v", failed, tt.wantHint)
+			}
+		})
+	}
+}
+
+func TestRunChecks_Redacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Authorization")
+		switch action := r.URL.Query().Get("action"); {
+		case action == "top10" && key == "no-torrent-scope", action == "requests" && key == "no-request-scope":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"status": "failure", "error": "This API key does not have access to this endpoint"}`))
+			return
+		case action == "top10", action == "requests":
+			w.Write([]byte(`{"status": "success", "response": []}`))
+			return
+		case action != "index":
+			t.Errorf("action = %q, want index, top10 or requests", action)
+		}
+		switch key {
+		case "good", "no-torrent-scope", "no-request-scope":
+			w.Write([]byte(`{"status": "success", "response": {"username": "uploader", "id": 7}}`))
+		case "down":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.Write([]byte(`{"status": "failure", "error": "bad credentials"}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		want     string
+		wantHint string
+	}{
+		{"valid", "good", "✓ Redacted API key: authenticated as uploader, with the Torrent scope and the Request scope", ""},
+		{"no Torrent scope", "no-torrent-scope", "❌ Redacted API key: Redacted API key lacks the Torrent scope needed for fetching torrents and uploading", "with the Torrent scope"},
+		{"no Request scope", "no-request-scope", "✓ Redacted API key: authenticated as uploader, with the Torrent scope (no Request scope: upload -fill-request will fail)", ""},
+		{"rejected", "bad", "API key rejected", "redacted.api_key"},
+		{"server error", "down", "API error 502", "check your network connection"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := uploader.NewRedactedClient(tt.apiKey)
+			client.BaseURL = server.URL
+			var out bytes.Buffer
+			runChecks(context.Background(), &out, []check{redactedCheck(client)})
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("output = %q, want it to contain %q", out.String(), tt.want)
+			}
+			if tt.wantHint == "" && strings.Contains(out.String(), "Hint:") {
+				t.Errorf("unexpected hint: %q", out.String())
+			}
+			if tt.wantHint != "" && !strings.Contains(out.String(), tt.wantHint) {
+				t.Errorf("output = %q, want hint containing %q", out.String(), tt.wantHint)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/discogs"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/enrich"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/filesystem"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+	"github.com/cehbz/classical-tagger/internal/review"
+	"github.com/cehbz/classical-tagger/internal/state"
+	"github.com/cehbz/classical-tagger/internal/storage"
+	"github.com/cehbz/classical-tagger/internal/validation"
+)
+
+// roleChoices are the roles offered for an artist no source gives a role for.
+var roleChoices = []domain.Role{domain.RoleSoloist, domain.RoleEnsemble, domain.RoleConductor, domain.RolePerformer, domain.RoleArranger}
+
+// runBatch extracts each album directory in turn, queueing the decisions the
+// extraction is unsure about instead of stopping, then reviews them all within
+// limit. With -quarantine, albums that are not ready for tagging are then moved
+// aside. It returns the exit code.
+func (x *extractor) runBatch(ctx context.Context, dirs []string, limit time.Duration) int {
+	x.queue = &review.Queue{}
+	failed := 0
+	problems := make(albumProblems)
+	for i, albumDir := range dirs {
+		if ctx.Err() != nil {
+			return exitcode.Abort
+		}
+		console.Infof("\n[%d/%d] %s\n", i+1, len(dirs), albumDir)
+		err := x.waitComplete(ctx, albumDir)
+		if err == nil {
+			err = x.extract(ctx, albumDir, "")
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return exitcode.Abort
+			}
+			exitcode.Print(os.Stderr, "", err)
+			problems.add(albumDir, "Extraction failed: "+err.Error())
+			failed++
+		}
+	}
+
+	console.Infof("\n✓ Extracted %d of %d albums", len(dirs)-failed, len(dirs))
+	queue := x.queue
+	if queue.Len() == 0 {
+		console.Infoln()
+		return batchCode(failed, x.quarantineAll(dirs, problems))
+	}
+	console.Infof("; %d decision(s) need review\n", queue.Len())
+
+	// Answers re-run lookups directly rather than queueing again
+	x.queue = nil
+	summary := queue.Review(x.stdin, os.Stderr, limit)
+
+	fmt.Fprintf(os.Stderr, "\nReview: %d accepted, %d skipped, %d unanswered\n", summary.Accepted, summary.Skipped, len(summary.Unreviewed))
+	for _, err := range summary.Failed {
+		exitcode.Print(os.Stderr, "", err)
+	}
+	if len(summary.Unreviewed) > 0 {
+		fmt.Fprintf(os.Stderr, "Unanswered (re-run extract -dir on these albums to decide):\n")
+		for _, d := range summary.Unreviewed {
+			fmt.Fprintf(os.Stderr, "  %s: %s\n", d.Album, d.Question)
+			problems.add(d.Album, "Undecided: "+d.Question)
+		}
+	}
+	return batchCode(failed+len(summary.Failed)+len(summary.Unreviewed), x.quarantineAll(dirs, problems))
+}
+
+// albumProblems records why each unfinished album of a batch is, keyed by the
+// album's directory as extract leaves it (see extractedDir), so that failures
+// recorded under the name given and decisions recorded under the renamed one
+// are found together.
+type albumProblems map[string][]string
+
+// add records a reason albumDir is unfinished.
+func (p albumProblems) add(albumDir, reason string) {
+	albumDir = extractedDir(albumDir)
+	p[albumDir] = append(p[albumDir], reason)
+}
+
+// extractedDir returns albumDir as extract leaves it: with -fix-nfc, renamed
+// to NFC, as decisions name it too.
+func extractedDir(albumDir string) string {
+	if !*fixNFC {
+		return albumDir
+	}
+	return filepath.Join(filepath.Dir(albumDir), normalize.NFC(filepath.Base(albumDir)))
+}
+
+// quarantineAll moves the batch's albums that have problems, or whose metadata
+// has issues blocking under the validation profile, into the -quarantine
+// directory with a report of why, so the rest can go on to tagging. It returns
+// the number quarantined for validation alone; the others are already counted.
+func (x *extractor) quarantineAll(dirs []string, problems albumProblems) int {
+	if *quarantine == "" {
+		return 0
+	}
+	invalid := 0
+	var quarantined []string
+	for _, albumDir := range dirs {
+		albumDir = extractedDir(albumDir)
+		reasons := problems[albumDir]
+		metadataFile, issues := x.validateExtracted(albumDir)
+		blocking := x.profile.Blocking(issues)
+		if len(reasons) == 0 && len(blocking) == 0 {
+			continue
+		}
+		if len(reasons) == 0 {
+			invalid++
+		}
+
+		dest, err := filesystem.Quarantine(albumDir, *quarantine, quarantineReport(albumDir, metadataFile, reasons, issues), *quarLink)
+		if err != nil {
+			exitcode.Print(os.Stderr, "", err)
+			continue
+		}
+		quarantined = append(quarantined, dest)
+	}
+
+	if len(quarantined) > 0 {
+		fmt.Fprintf(os.Stderr, "\n⚠️  Quarantined %d album(s) in %s (see each album's %s):\n", len(quarantined), *quarantine, filesystem.QuarantineReportSuffix)
+		for _, dest := range quarantined {
+			fmt.Fprintf(os.Stderr, "  %s\n", filepath.Base(dest))
+		}
+	}
+	return invalid
+}
+
+// validateExtracted validates the metadata extract saved for albumDir: the
+// merged JSON when sources were merged, otherwise the local one. It returns the
+// file validated ("" when there is none) and its issues.
+func (x *extractor) validateExtracted(albumDir string) (string, []domain.ValidationIssue) {
+	baseName := x.outputBase(albumDir, "")
+	for _, file := range []string{baseName + "_merged.json", baseName + ".json"} {
+		torrent, err := storage.NewRepository().LoadFromFile(file)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return file, []domain.ValidationIssue{{Level: domain.LevelError, Message: fmt.Sprintf("Cannot load metadata: %v", err)}}
+		}
+		return file, validation.Check(torrent, nil)
+	}
+	return "", nil
+}
+
+// quarantineReport is the report written beside a quarantined album: why it
+// was not finished and every validation issue of its metadata.
+func quarantineReport(albumDir, metadataFile string, reasons []string, issues []domain.ValidationIssue) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Album: %s\n", albumDir)
+	if metadataFile != "" {
+		fmt.Fprintf(&b, "Metadata: %s\n", metadataFile)
+	}
+	for _, reason := range reasons {
+		fmt.Fprintf(&b, "%s\n", reason)
+	}
+	if len(issues) > 0 {
+		fmt.Fprintf(&b, "\nValidation issues (%d):\n", len(issues))
+		for _, issue := range issues {
+			fmt.Fprintf(&b, "%s\n", issue)
+		}
+	}
+	return b.String()
+}
+
+// batchCode is the exit code of a batch run with the given numbers of albums
+// that failed or still await a decision, and of those quarantined only because
+// their metadata failed validation.
+func batchCode(unfinished, invalid int) int {
+	switch {
+	case unfinished > 0:
+		return exitcode.Failure
+	case invalid > 0:
+		return exitcode.Validation
+	}
+	return exitcode.OK
+}
+
+// deferred queues a review decision for an enrichment error that an answer can
+// resolve, reporting whether it did.
+func (x *extractor) deferred(ctx context.Context, albumDir, baseName string, err error) bool {
+	var ambiguous *enrich.AmbiguousError
+	var roleUnknown *domain.ErrRoleUnknown
+	switch {
+	case errors.As(err, &ambiguous) && len(ambiguous.IDs) == len(ambiguous.Candidates):
+		x.queue.Add(review.Decision{
+			Album:    albumDir,
+			Kind:     "release",
+			Question: fmt.Sprintf("Which %s release is this album?", ambiguous.Source),
+			Choices:  ambiguous.Candidates,
+			Apply: func(choice int) error {
+				return x.reenrich(ctx, albumDir, baseName, ambiguous.IDs[choice])
+			},
+		})
+		console.Infof("⏸  %d %s releases match; queued for review\n", len(ambiguous.Candidates), ambiguous.Source)
+	case errors.As(err, &roleUnknown):
+		question := fmt.Sprintf("No source gives a role for %s", roleUnknown.Artist)
+		if roleUnknown.Track != "" {
+			question += fmt.Sprintf(" on %q", roleUnknown.Track)
+		}
+		choices := make([]string, len(roleChoices))
+		for i, role := range roleChoices {
+			choices[i] = role.String()
+		}
+		x.queue.Add(review.Decision{
+			Album:    albumDir,
+			Kind:     "role",
+			Question: question + ". What is their role?",
+			Choices:  choices,
+			Apply: func(choice int) error {
+				// Crediting the artist locally lets the Discogs conversion pick the role up
+				local, err := loadLocal(baseName)
+				if err != nil {
+					return err
+				}
+				local.AlbumArtist = append(local.AlbumArtist, domain.Artist{Name: roleUnknown.Artist, Role: roleChoices[choice]})
+				// Remembering the role spares later albums by the artist the question
+				discogs.RememberRole(roleUnknown.Artist, roleChoices[choice])
+				if err := state.RememberArtistRole(roleUnknown.Artist, roleChoices[choice]); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+				if err := local.Save(baseName + ".json"); err != nil {
+					return err
+				}
+				return x.reenrich(ctx, albumDir, baseName, *releaseID)
+			},
+		})
+		console.Infof("⏸  No role for %s; queued for review\n", roleUnknown.Artist)
+	default:
+		return false
+	}
+	return true
+}
+
+// queuePropagation queues the -artist-propagation prompt question for artist.
+// The album is extracted without the artist on those tracks; accepting adds
+// them to the saved local and merged metadata.
+func (x *extractor) queuePropagation(albumDir, baseName string, artist domain.Artist, missing []*domain.Track) {
+	type position struct{ disc, track int }
+	positions := make([]position, len(missing))
+	labels := make([]string, len(missing))
+	for i, track := range missing {
+		positions[i] = position{track.Disc, track.Track}
+		labels[i] = fmt.Sprintf("%d-%02d", track.Disc, track.Track)
+	}
+
+	x.queue.Add(review.Decision{
+		Album: albumDir,
+		Kind:  "artist propagation",
+		Question: fmt.Sprintf("%s (%s) is an album artist but missing from %d track(s): %s",
+			artist.Name, artist.Role, len(missing), strings.Join(labels, ", ")),
+		Choices: []string{"Add them to these tracks", "Leave the tracks as tagged"},
+		Apply: func(choice int) error {
+			if choice != 0 {
+				return nil
+			}
+			for _, file := range []string{baseName + ".json", baseName + "_merged.json"} {
+				if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				torrent, err := storage.NewRepository().LoadFromFile(file)
+				if err != nil {
+					return err
+				}
+				for _, track := range torrent.Tracks() {
+					if slices.Contains(positions, position{track.Disc, track.Track}) && !slices.Contains(track.Artists, artist) {
+						track.Artists = append(track.Artists, artist)
+					}
+				}
+				if err := torrent.Save(file); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// queueGrouping queues a movement grouping the -work-grouping policy did not
+// apply. Accepting prefixes the tracks' titles in the merged metadata with the work.
+func (x *extractor) queueGrouping(albumDir, mergedFile string, g domain.MovementGrouping) {
+	x.queue.Add(review.Decision{
+		Album: albumDir,
+		Kind:  "work grouping",
+		Question: fmt.Sprintf("Disc %d tracks %d-%d have bare movement titles. Are they movements of %q (%s confidence, from %s)?",
+			g.Disc, g.First, g.Last, g.Work, g.Confidence, g.Source),
+		Choices: []string{"Prefix their titles with the work", "Leave the titles as tagged"},
+		Apply: func(choice int) error {
+			if choice != 0 {
+				return nil
+			}
+			torrent, err := storage.NewRepository().LoadFromFile(mergedFile)
+			if err != nil {
+				return err
+			}
+			torrent.ApplyMovementGrouping(g)
+			return torrent.Save(mergedFile)
+		},
+	})
+}
+
+// reenrich re-runs enrichment for an album from its saved local metadata, so
+// answers recorded there earlier in the review are kept.
+func (x *extractor) reenrich(ctx context.Context, albumDir, baseName string, releaseID int) error {
+	lock, err := state.AcquireDir(albumDir)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	local, err := loadLocal(baseName)
+	if err != nil {
+		return err
+	}
+	final, finalFile, err := x.enrich(ctx, albumDir, baseName, local, releaseID)
+	if err != nil {
+		return err
+	}
+	if finalFile == "" {
+		final, finalFile = local, baseName+".json"
+	}
+	return x.fetchCovers(ctx, albumDir, final, finalFile)
+}
+
+// loadLocal loads the local metadata saved by extract.
+func loadLocal(baseName string) (*domain.Torrent, error) {
+	return storage.NewRepository().LoadFromFile(baseName + ".json")
+}
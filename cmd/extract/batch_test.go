@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/filesystem"
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestQuarantineAll_NFDDirectory(t *testing.T) {
+	root := t.TempDir()
+	nfd := filepath.Join(root, norm.NFD.String("Dvořák - Symphonies"))
+	nfc := filepath.Join(root, "Dvořák - Symphonies")
+	// extract -fix-nfc has renamed the album; decisions name it in NFC
+	if err := os.Mkdir(nfc, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	oldFixNFC, oldQuarantine := *fixNFC, *quarantine
+	t.Cleanup(func() { *fixNFC, *quarantine = oldFixNFC, oldQuarantine })
+	*fixNFC, *quarantine = true, filepath.Join(root, "quarantine")
+
+	problems := make(albumProblems)
+	problems.add(nfd, "Extraction failed: no release found")
+	problems.add(nfc, "Undecided: which release?")
+
+	x := &extractor{workDir: t.TempDir()}
+	if invalid := x.quarantineAll([]string{nfd}, problems); invalid != 0 {
+		t.Errorf("quarantineAll() = %d quarantined for validation alone, want 0", invalid)
+	}
+
+	report, err := os.ReadFile(filepath.Join(*quarantine, "Dvořák - Symphonies") + filesystem.QuarantineReportSuffix)
+	if err != nil {
+		t.Fatalf("album not quarantined: %v", err)
+	}
+	for _, want := range []string{"Extraction failed: no release found", "Undecided: which release?"} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("report = %q, want it to contain %q", report, want)
+		}
+	}
+}
+
+func TestBatchCode(t *testing.T) {
+	tests := []struct {
+		unfinished, invalid, want int
+	}{
+		{0, 0, exitcode.OK},
+		{0, 2, exitcode.Validation},
+		{1, 0, exitcode.Failure},
+		{1, 2, exitcode.Failure},
+	}
+	for _, tt := range tests {
+		if got := batchCode(tt.unfinished, tt.invalid); got != tt.want {
+			t.Errorf("batchCode(%d, %d) = %d, want %d", tt.unfinished, tt.invalid, got, tt.want)
+		}
+	}
+}
@@ -1,208 +1,828 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
-	"text/template"
+	"syscall"
+	"time"
 
+	"github.com/cehbz/classical-tagger/internal/artwork"
 	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/console"
 	"github.com/cehbz/classical-tagger/internal/discogs"
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/enrich"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/filesystem"
+	"github.com/cehbz/classical-tagger/internal/profiling"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+	"github.com/cehbz/classical-tagger/internal/review"
+	"github.com/cehbz/classical-tagger/internal/rulepack"
 	"github.com/cehbz/classical-tagger/internal/scraping"
+	"github.com/cehbz/classical-tagger/internal/state"
+	"github.com/cehbz/classical-tagger/internal/titlecase"
+	"github.com/cehbz/classical-tagger/internal/uploader"
+	"github.com/cehbz/classical-tagger/internal/watch"
 )
 
 var (
-	dir        = flag.String("dir", "", "Directory containing FLAC files (required)")
-	releaseID  = flag.Int("release-id", 0, "Specific Discogs release ID to use")
-	outputFile = flag.String("output", "", "Base name for output files (default: directory name)")
-	verbose    = flag.Bool("verbose", false, "Enable verbose output")
-	force      = flag.Bool("force", false, "Create output even if required fields are missing")
-	noAPI      = flag.Bool("no-api", false, "Skip Discogs API lookup")
+	dir          = flag.String("dir", "", "Directory containing FLAC files (required)")
+	rootName     = flag.String("root", "", "Library root from config to resolve a relative -dir against")
+	releaseID    = flag.Int("release-id", 0, "Specific Discogs release ID to use")
+	enrichChain  = flag.String("enrich", "", "Comma-separated enrichment sources in order, later ones taking precedence: local, discogs, web, library, file, redacted (default: enrich.chain in config, or local,discogs,web,library,file)")
+	albumURL     = flag.String("url", "", "Album page (e.g. on the label's site) used by the \"web\" enrichment source")
+	enrichFile   = flag.String("enrich-file", "", "Hand-edited metadata JSON used by the \"file\" enrichment source")
+	library      = flag.String("library", "", "Roon (JSON) or JRiver (MPL XML) export used by the \"library\" enrichment source")
+	torrentID    = flag.Int("torrent", 0, "Redacted torrent ID whose group musicInfo is used by the \"redacted\" enrichment source")
+	hybrid       = flag.Bool("hybrid", false, "Build the metadata from the local files and the -torrent's Redacted group alone, skipping Discogs (same as -enrich local,redacted)")
+	discMap      = flag.String("disc-map", "", "Which Discogs disc is which local disc, as SOURCE=LOCAL pairs, e.g. \"1=2,2=1\" when the release lists the bonus disc first (default: matched by track durations when the discs don't line up)")
+	catno        = flag.String("catno", "", "Search Discogs by catalog number (default: CATALOGNUMBER tag or cue REM CATALOGNUMBER)")
+	barcode      = flag.String("barcode", "", "Search Discogs by UPC/EAN barcode (default: BARCODE tag or cue CATALOG)")
+	outputFile   = flag.String("output", "", "Base name for output files (default: directory name)")
+	workDir      = flag.String("workdir", "", "Directory to write output files in (default: the current directory, or with -read-only read_only.work_dir in config)")
+	readOnly     = flag.Bool("read-only", false, "Never write in album directories: refuse -fix-nfc, -fix-cover, -fetch-cover and moving -quarantine, and write output files to the work directory (default: read_only.enabled in config)")
+	verbose      = flag.Bool("verbose", false, "Enable verbose output")
+	force        = flag.Bool("force", false, "Create output even if required fields are missing")
+	noAPI        = flag.Bool("no-api", false, "Skip Discogs API lookup")
+	hiddenTracks = flag.String("hidden-tracks", "include", "Hidden pregap tracks (track 0): include (titled \"[Hidden Track]\" if untitled) or drop")
+	tracklist    = flag.String("tracklist", "", "Plain-text tracklist (e.g. typed from the booklet) to take track titles from")
+	noComposer   = flag.Bool("allow-missing-composer", false, "Keep tracks without a COMPOSER tag (crossover or recital discs awaiting composer research); missing composers become validation warnings. Implied by a non-classical GENRE tag")
+	fixNFC       = flag.Bool("fix-nfc", false, "Rename decomposed (NFD) file and folder names, as in many macOS rips, to composed Unicode (NFC) before extracting")
+	splitAlbums  = flag.Bool("split", false, "When the directory holds several albums (conflicting ALBUM tags or catalog numbers), move each album's files into its own directory beside it and extract them separately")
+	fetchCover   = flag.String("fetch-cover", "", "Download the missing cover images named, front and/or back (e.g. front,back), from the Discogs release or else the Cover Art Archive by MusicBrainz release ID, crediting them in the metadata")
+	coverSize    = flag.Int("cover-size", 1200, "With -fetch-cover, prefer images at least this many pixels on their longest side (0: the largest the source has)")
+	fixCover     = flag.Bool("fix-cover", false, "Downscale an oversized cover image and re-encode a large, progressive, CMYK or PNG one as baseline JPEG")
+	artistPolicy = flag.String("artist-propagation", "propagate", "Album performers missing from some tracks: propagate (add to every track), keep-sparse, or prompt")
+	workPolicy   = flag.String("work-grouping", "confident", "Bare movement titles (\"Allegro\") in the merged metadata: confident (prefix the work an online source names), all (also the album title of a single-work album), or off (report only)")
+
+	apiRequests = flag.Int("discogs-requests", 0, "Discogs requests allowed per window (default: discogs.rate_limit in config, or 60)")
+	apiWindow   = flag.Duration("discogs-window", 0, "Discogs rate limit window (default: discogs.rate_limit in config, or 1m)")
+	apiTimeout  = flag.Duration("timeout", 0, "Discogs HTTP timeout (default: discogs.timeout_seconds in config, or 30s)")
+
+	nonInteractive = flag.Bool("non-interactive", false, "When several releases match, list them and exit instead of asking which to use (the default when stdin is not a terminal)")
+
+	waitComplete = flag.Bool("wait-complete", false, "Wait until each album directory has finished being copied or downloaded (files unchanged and closed, no partial downloads, the marker present; see watch in config) before extracting it")
+
+	batch      = flag.Bool("batch", false, "Extract every album directory given as an argument, queueing low-confidence decisions (release matches, artist roles, artist propagation, work grouping) for one review at the end")
+	reviewTime = flag.Duration("review-time", 15*time.Minute, "With -batch, how long the end-of-run review may take before the remaining decisions are left unanswered (0: no limit)")
+	quarantine = flag.String("quarantine", "", "With -batch, move albums that fail extraction, still await a decision or fail validation into this directory, each with its validation report beside it")
+	quarLink   = flag.Bool("quarantine-link", false, "With -quarantine, leave the albums in place and symlink them into the quarantine directory")
+	profName   = flag.String("profile", "", "Validation profile deciding which albums -quarantine takes: default (errors), strict (warnings too) or lenient (none) (defaults to the root's, or default)")
+
+	cpuProfile = flag.String("cpuprofile", "", "Write a CPU profile to this file (inspect with go tool pprof)")
+	memProfile = flag.String("memprofile", "", "Write a heap profile to this file on completion")
 )
 
 func main() {
+	console.AddFlag(flag.CommandLine)
 	flag.Usage = usage
-	flag.Parse()
+	exitcode.ParseFlags(flag.CommandLine, os.Args[1:])
+	rules, err := rulepack.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	titlecase.Configure(rules.ProtectedWords)
+	discogs.ConfigureKnownRoles(rules.ArtistRoles)
 
 	// Validate required arguments
-	if *dir == "" {
+	dirs := flag.Args()
+	if *dir != "" {
+		dirs = append([]string{*dir}, dirs...)
+	}
+	switch {
+	case len(dirs) == 0:
 		fmt.Fprintf(os.Stderr, "Error: -dir is required\n\n")
 		usage()
 		os.Exit(1)
+	case !*batch && len(flag.Args()) > 0:
+		fmt.Fprintf(os.Stderr, "Error: album directories as arguments need -batch\n\n")
+		usage()
+		os.Exit(1)
+	case *batch && (*outputFile != "" || *releaseID != 0 || *catno != "" || *barcode != "" || *tracklist != "" || *albumURL != "" || *enrichFile != "" || *torrentID != 0 || *discMap != ""):
+		fmt.Fprintf(os.Stderr, "Error: -output, -release-id, -catno, -barcode, -tracklist, -url, -enrich-file, -torrent and -disc-map describe a single album and cannot be used with -batch\n")
+		os.Exit(1)
+	case !*batch && *quarantine != "":
+		fmt.Fprintf(os.Stderr, "Error: -quarantine needs -batch\n")
+		os.Exit(1)
+	case *hybrid && *torrentID == 0:
+		fmt.Fprintf(os.Stderr, "Error: -hybrid needs -torrent\n")
+		os.Exit(1)
+	case *hybrid && *enrichChain != "":
+		fmt.Fprintf(os.Stderr, "Error: -hybrid sets the enrichment chain and cannot be used with -enrich\n")
+		os.Exit(1)
 	}
 
-	// Verify directory exists
-	if info, err := os.Stat(*dir); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Cannot access directory %s: %v\n", *dir, err)
+	root, err := config.LoadRoot(*rootName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -root: %v\n", err)
 		os.Exit(1)
-	} else if !info.IsDir() {
-		fmt.Fprintf(os.Stderr, "Error: %s is not a directory\n", *dir)
+	}
+	for i := range dirs {
+		dirs[i] = root.Resolve(dirs[i])
+	}
+	outDir, err := outputDir(dirs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *profName == "" {
+		*profName = root.Validation
+	}
+	profile, err := domain.ParseValidationProfile(*profName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -profile: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Determine output base name
-	baseName := *outputFile
-	if baseName == "" {
-		baseName = filepath.Base(*dir)
-		// Clean up the name (remove common suffixes)
-		baseName = strings.TrimSuffix(baseName, " (FLAC)")
-		baseName = strings.TrimSuffix(baseName, " FLAC")
+	hiddenPolicy, err := domain.ParseHiddenTrackPolicy(*hiddenTracks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -hidden-tracks: %v\n", err)
+		os.Exit(1)
 	}
 
+	propagation, err := domain.ParseArtistPropagationPolicy(*artistPolicy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -artist-propagation: %v\n", err)
+		os.Exit(1)
+	}
+
+	workGrouping, err := domain.ParseWorkGroupingPolicy(*workPolicy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -work-grouping: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Enrich from the configured sources (local, Discogs, an album page, a hand-edited file)
+	names := config.LoadEnrichChain()
+	if *enrichChain != "" {
+		names = strings.Split(*enrichChain, ",")
+	}
+	if *hybrid {
+		names = []string{"local", "redacted"}
+	}
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+		switch names[i] {
+		case "local", "discogs", "web", "library", "file", "redacted":
+		default:
+			fmt.Fprintf(os.Stderr, "Error: %v %q (want local, discogs, web, library, file or redacted)\n", enrich.ErrUnknownEnricher, names[i])
+			os.Exit(1)
+		}
+	}
+	discs, err := domain.ParseDiscMap(*discMap)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -disc-map: %v\n", err)
+		os.Exit(1)
+	}
+	titleStyle, err := domain.ParseTitleStyle(config.LoadTitleStyle())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: titles in config: %v\n", err)
+		os.Exit(1)
+	}
+
+	precedence := config.LoadEnrichPrecedence()
+	if err := enrich.ValidatePrecedence(precedence); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: enrich.precedence: %v\n", err)
+		os.Exit(1)
+	}
+
+	x := &extractor{
+		chain:       names,
+		precedence:  precedence,
+		hidden:      hiddenPolicy,
+		propagation: propagation,
+		grouping:    workGrouping,
+		aliases:     domain.NewAliasTable(rules.ArtistAliases),
+		titles:      titleStyle,
+		discMap:     discs,
+		profile:     profile,
+		workDir:     outDir,
+		interactive: !*nonInteractive && isTerminal(os.Stdin),
+		stdin:       bufio.NewReader(os.Stdin),
+	}
+	if slices.Contains(names, "discogs") {
+		x.client = discogsClient()
+	}
+	if slices.Contains(names, "redacted") && *torrentID != 0 {
+		x.redacted = redactedClient()
+	}
+	if x.coverSides, err = artwork.ParseSides(*fetchCover); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -fetch-cover: %v\n", err)
+		os.Exit(1)
+	}
+	if len(x.coverSides) > 0 {
+		if x.client != nil {
+			x.coverSources = append(x.coverSources, artwork.Discogs{Client: x.client})
+		}
+		x.coverSources = append(x.coverSources, artwork.NewCoverArtArchive())
+	}
+
+	// Cancel rate limiter waits and in-flight requests on Ctrl-C
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		fmt.Fprintln(os.Stderr, "\nInterrupted, cancelling metadata lookups...")
+		cancel()
+	}()
+
+	stopProfiling := startProfiling()
+	defer stopProfiling()
+
+	if *batch {
+		code := x.runBatch(ctx, dirs, *reviewTime)
+		stopProfiling()
+		os.Exit(code)
+	}
+
+	err = x.waitComplete(ctx, dirs[0])
+	if err == nil {
+		err = x.extract(ctx, dirs[0], *outputFile)
+	}
+	stopProfiling()
+	var ambiguous *enrich.AmbiguousError
+	switch {
+	case errors.As(err, &ambiguous):
+		// chooseRelease has listed the releases
+		os.Exit(exitcode.Validation)
+	case ctx.Err() != nil:
+		os.Exit(exitcode.Abort)
+	case err != nil:
+		exitcode.Fail("", err)
+	}
+}
+
+// extractor runs the extraction steps for one album at a time.
+type extractor struct {
+	chain       []string // Enrichment source names, in order
+	precedence  map[string][]string
+	hidden      domain.HiddenTrackPolicy
+	propagation domain.ArtistPropagationPolicy
+	grouping    domain.WorkGroupingPolicy
+	aliases     domain.AliasTable
+	titles      domain.TitleStyle        // House style for key signatures and work numbers
+	discMap     domain.DiscMap           // Which Discogs disc is which local disc (nil: matched by duration)
+	profile     domain.ValidationProfile // Decides which batch albums are quarantined
+	client      *discogs.Client          // Shared so batch runs respect one rate limit (nil: no Discogs lookup)
+	redacted    *uploader.RedactedClient // nil: no Redacted lookup
+	workDir     string                   // Where output files are written ("": the current directory)
+	interactive bool                     // Ask which release to use when several match
+	stdin       *bufio.Reader            // Answers to every prompt, shared so none typed ahead are lost
+
+	coverSides   []artwork.Side   // Cover images to fetch when missing
+	coverSources []artwork.Source // Where to fetch them from, in order
+
+	// Batch runs defer low-confidence decisions here instead of asking or
+	// failing at once.
+	queue *review.Queue
+}
+
+// waitComplete waits, with -wait-complete, until albumDir is no longer being
+// copied into, so a half-copied album is not extracted.
+func (x *extractor) waitComplete(ctx context.Context, albumDir string) error {
+	if !*waitComplete {
+		return nil
+	}
+	detector := watch.NewDetector(albumDir, watch.LoadOptions())
+	status, err := detector.Check()
+	if err != nil || status.Complete {
+		return err
+	}
+	console.Infof("Waiting for %s to be complete (%s)...\n", albumDir, status.Reason)
+	return detector.Wait(ctx)
+}
+
+// extract writes the metadata JSON for the album in albumDir, with file names
+// starting with baseName (default: derived from the directory name).
+func (x *extractor) extract(ctx context.Context, albumDir, baseName string) error {
+	// Verify directory exists
+	if info, err := os.Stat(albumDir); err != nil {
+		return fmt.Errorf("cannot access directory %s: %w", albumDir, err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", albumDir)
+	}
+
+	// Compose decomposed names first, so the metadata records the renamed paths
+	if *fixNFC {
+		renames, err := filesystem.RenameToNFC(nil, albumDir, false)
+		for _, r := range renames {
+			console.Infof("✓ Renamed to NFC: %s\n", r.To)
+		}
+		if err != nil {
+			return fmt.Errorf("renaming to NFC: %w", err)
+		}
+		albumDir = extractedDir(albumDir)
+	}
+
+	// Prevent concurrent runs on the same album from clobbering each other's output
+	lock, err := state.AcquireDir(albumDir)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	return x.extractLocked(ctx, albumDir, baseName)
+}
+
+// extractLocked is extract for an albumDir whose lock the caller holds.
+func (x *extractor) extractLocked(ctx context.Context, albumDir, baseName string) error {
+	// A directory holding several albums is split, or refused, rather than
+	// extracted as one
+	groups, err := scraping.DetectMergedAlbums(albumDir)
+	if err != nil {
+		return err
+	}
+	if len(groups) > 0 {
+		return x.split(ctx, albumDir, groups)
+	}
+
+	// Determine output base name
+	baseName = x.outputBase(albumDir, baseName)
+
 	// Step 1: Extract local metadata
 	if *verbose {
-		fmt.Fprintf(os.Stderr, "Extracting metadata from: %s\n", *dir)
+		console.Infof("Extracting metadata from: %s\n", albumDir)
 	}
 
-	localTorrent := extractFromDirectory(*dir)
+	confirm := func(artist domain.Artist, missing []*domain.Track) bool {
+		return confirmPropagation(x.stdin, os.Stderr, artist, missing)
+	}
+	if x.queue != nil {
+		confirm = func(artist domain.Artist, missing []*domain.Track) bool {
+			x.queuePropagation(albumDir, baseName, artist, missing)
+			return false
+		}
+	}
+	localTorrent, err := extractFromDirectory(albumDir, scraping.ExtractOptions{
+		ArtistPropagation:    x.propagation,
+		ConfirmPropagation:   confirm,
+		AllowMissingComposer: *noComposer,
+	})
+	if err != nil {
+		return err
+	}
+	for _, path := range localTorrent.ApplyHiddenTrackPolicy(x.hidden) {
+		fmt.Fprintf(os.Stderr, "⚠️  Dropped hidden track %s; remove it from the torrent directory\n", path)
+	}
+	for _, note := range localTorrent.NormalizeArtistNames(x.aliases) {
+		fmt.Fprintf(os.Stderr, "⚠️  Normalized artist name %s\n", note)
+	}
+	for _, note := range localTorrent.NormalizeTitles(x.titles) {
+		console.Infof("✓ Normalized title %s\n", note)
+	}
+	checkCover(albumDir)
 
 	// Save local extraction
 	localFile := baseName + ".json"
 	if err := localTorrent.Save(localFile); err != nil {
-		fmt.Fprintf(os.Stderr, "Error saving local metadata: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("saving local metadata: %w", err)
 	}
 
-	fmt.Fprintf(os.Stderr, "✓ Local metadata saved to: %s\n", localFile)
+	console.Resultf(localFile, "✓ Local metadata saved to: %s\n", localFile)
 
-	// Step 2: Try Discogs API (unless disabled)
-	if *noAPI {
-		if *verbose {
-			fmt.Fprintf(os.Stderr, "Skipping Discogs API (--no-api specified)\n")
+	// Step 1b: Apply titles from a pasted tracklist (for untagged albums with a booklet)
+	if *tracklist != "" {
+		localFile = baseName + "_tracklist.json"
+		if err := applyTracklist(localTorrent, *tracklist, localFile); err != nil {
+			return fmt.Errorf("applying tracklist: %w", err)
 		}
-		return
+		console.Resultf(localFile, "✓ Tracklist metadata saved to: %s\n", localFile)
 	}
 
-	// Load Discogs token
-	token, err := config.LoadDiscogsToken()
+	final, finalFile, err := x.enrich(ctx, albumDir, baseName, localTorrent, *releaseID)
+	if x.queue != nil && x.deferred(ctx, albumDir, baseName, err) {
+		return nil
+	}
+	if x.queue == nil {
+		final, finalFile, err = x.chooseRelease(ctx, albumDir, baseName, localTorrent, final, finalFile, err)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: Cannot load Discogs token: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Continuing with local extraction only.\n")
-		fmt.Fprintf(os.Stderr, "To enable Discogs lookup, create ~/.config/classical-tagger/config.yaml with your token.\n")
-		return
+		return err
 	}
+	if finalFile == "" {
+		final, finalFile = localTorrent, localFile
+	}
+	return x.fetchCovers(ctx, albumDir, final, finalFile)
+}
 
-	client := discogs.NewClient(token)
+// checkCover warns about problems with the album's cover image, first fixing
+// what it can when -fix-cover is set.
+func checkCover(albumDir string) {
+	if *fixCover {
+		cover, err := artwork.Find(nil, albumDir)
+		if err == nil && cover != nil && cover.Fixable() {
+			if fixed, err := artwork.Fix(nil, albumDir, cover); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠️  Could not fix cover image: %v\n", err)
+			} else {
+				console.Infof("✓ Re-encoded cover image as %s (%d×%d)\n", fixed.Path, fixed.Width, fixed.Height)
+			}
+		}
+	}
+	for _, issue := range artwork.Check(nil, albumDir) {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", issue.Message)
+	}
+}
 
-	// get release(s)
-	releases := []*discogs.Release{}
-	if *releaseID != 0 {
-		release, err := client.GetRelease(*releaseID)
-		if err != nil || release == nil {
-			fmt.Fprintf(os.Stderr, "Error fetching release: %v\n", err)
-			os.Exit(1)
+// fetchCovers downloads the -fetch-cover sides the album has no image of,
+// re-encoding them as -fix-cover would, and credits them in album, saving it
+// to file. Finding no usable image only earns a warning.
+func (x *extractor) fetchCovers(ctx context.Context, albumDir string, album *domain.Torrent, file string) error {
+	fetched := false
+	for _, side := range x.coverSides {
+		find := artwork.Find
+		if side == artwork.Back {
+			find = artwork.FindBack
+		}
+		if existing, err := find(nil, albumDir); err != nil || existing != nil {
+			continue
+		}
+		f, err := artwork.Fetch(ctx, x.coverSources, album, side, *coverSize, func(format string, args ...any) {
+			fmt.Fprintf(os.Stderr, "⚠️  "+format+"\n", args...)
+		})
+		if err != nil {
+			return err
+		}
+		if f == nil {
+			fmt.Fprintf(os.Stderr, "⚠️  No usable %s cover found on Discogs or the Cover Art Archive (they need the release ID or MusicBrainz release ID)\n", side)
+			continue
 		}
-		releases = append(releases, release)
-	} else {
-		// Search using extracted metadata
-		artist := extractArtist(localTorrent)
-		album := localTorrent.Title
+		if err := f.Save(nil, albumDir); err != nil {
+			return fmt.Errorf("saving %s cover: %w", side, err)
+		}
+		cover := f.Cover
+		if fixed, err := artwork.Fix(nil, albumDir, cover); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Could not re-encode %s: %v\n", cover.Path, err)
+		} else {
+			cover = fixed
+		}
+		f.Credit.File = cover.Path
+		album.Artwork = append(album.Artwork, f.Credit)
+		console.Infof("✓ Fetched %s cover from %s: %s (%d×%d)\n", side, f.Credit.Source, cover.Path, cover.Width, cover.Height)
+		fetched = true
+	}
+	if !fetched {
+		return nil
+	}
+	if err := album.Save(file); err != nil {
+		return fmt.Errorf("saving cover credits: %w", err)
+	}
+	console.Infof("✓ Cover credits saved to: %s\n", file)
+	return nil
+}
 
-		if artist == "" || album == "" {
-			fmt.Fprintf(os.Stderr, "Warning: Cannot search Discogs without artist and album information\n")
-			return
+// enrich runs the enrichment chain over the local metadata (Step 2) and
+// merges the results (Step 3), saving each source's metadata. It returns the
+// final metadata and the file it was saved to: the merged metadata, or the one
+// remote source's; "" when only the local metadata contributed.
+func (x *extractor) enrich(ctx context.Context, albumDir, baseName string, localTorrent *domain.Torrent, releaseID int) (*domain.Torrent, string, error) {
+	chain := &enrich.Chain{Precedence: x.precedence, Log: logf}
+	for _, name := range x.chain {
+		switch name {
+		case "local":
+			chain.Enrichers = append(chain.Enrichers, enrich.Local{})
+		case "discogs":
+			if x.client != nil {
+				chain.Enrichers = append(chain.Enrichers, &enrich.Discogs{
+					Client:        x.client,
+					ReleaseID:     releaseID,
+					Barcode:       *barcode,
+					CatalogNumber: *catno,
+					// Use parent directory as rootPath so generated directory is a sibling of local directory
+					RootPath: filepath.Dir(albumDir),
+					DiscMap:  x.discMap,
+					Verbose:  *verbose,
+					Log:      logf,
+				})
+			}
+		case "web":
+			if *albumURL != "" {
+				chain.Enrichers = append(chain.Enrichers, enrich.Web{URL: *albumURL, Registry: scraping.DefaultRegistry(), Log: logf})
+			}
+		case "library":
+			if *library != "" {
+				chain.Enrichers = append(chain.Enrichers, enrich.Library{Path: *library, Log: logf})
+			}
+		case "file":
+			if *enrichFile != "" {
+				chain.Enrichers = append(chain.Enrichers, enrich.File{Path: *enrichFile})
+			}
+		case "redacted":
+			if x.redacted != nil {
+				chain.Enrichers = append(chain.Enrichers, enrich.Redacted{Client: x.redacted, TorrentID: *torrentID})
+			}
 		}
+	}
 
-		if *verbose {
-			fmt.Fprintf(os.Stderr, "Searching Discogs for: artist=%q album=%q\n", artist, album)
+	results, err := chain.Run(ctx, localTorrent)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Save each remote source's metadata on its own
+	sourceNames := map[string]string{"discogs": "Discogs", "web": "Album page", "library": "Library export", "redacted": "Redacted"}
+	var final *domain.Torrent
+	var finalFile string
+	for _, r := range results {
+		label, ok := sourceNames[r.Source]
+		if !ok {
+			continue
+		}
+		for _, note := range r.Torrent.NormalizeArtistNames(x.aliases) {
+			fmt.Fprintf(os.Stderr, "⚠️  Normalized %s artist name %s\n", label, note)
+		}
+		for _, note := range r.Torrent.NormalizeTitles(x.titles) {
+			console.Infof("✓ Normalized %s title %s\n", label, note)
+		}
+		sourceFile := baseName + "_" + r.Source + ".json"
+		if err := r.Torrent.Save(sourceFile); err != nil {
+			return nil, "", fmt.Errorf("saving %s data: %w", label, err)
 		}
+		console.Resultf(sourceFile, "✓ %s metadata saved to: %s\n", label, sourceFile)
+		final, finalFile = r.Torrent, sourceFile
+	}
 
-		releases, err = client.Search(artist, album)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Discogs search failed: %v\n", err)
-			return
+	// Step 3: Merge when more than one source contributed
+	if len(results) > 1 {
+		mergedFile := baseName + "_merged.json"
+		merged := chain.Merge(results)
+		// Placeholder titles ("Track 01") kept by precedence give way to any source's real ones
+		var candidates []*domain.Torrent
+		for i := len(results) - 1; i >= 0; i-- {
+			candidates = append(candidates, results[i].Torrent)
+		}
+		for _, note := range merged.FillPlaceholderTitles(candidates...) {
+			console.Infof("✓ Filled placeholder title %s\n", note)
+		}
+		// Placeholder artists ("Unknown", "Various") give way to a real credit in the same role
+		for _, note := range merged.DropPlaceholderArtists(candidates...) {
+			console.Infof("✓ Replaced placeholder artist %s\n", note)
+		}
+		// Bare movement titles kept by precedence get the work a source names
+		for _, g := range merged.GroupMovements(x.grouping, candidates...) {
+			switch {
+			case g.Applied:
+				console.Infof("✓ Grouped movements %s\n", g)
+			case g.Work != "" && x.queue != nil && x.grouping != domain.WorkGroupingOff:
+				x.queueGrouping(albumDir, mergedFile, g)
+				console.Infof("⏸  Movements %s; queued for review\n", g)
+			default:
+				fmt.Fprintf(os.Stderr, "⚠️  Movements %s\n", g)
+			}
 		}
-		if len(releases) == 0 {
-			// Try fallback simple search with combined query
-			if *verbose {
-				fmt.Fprintf(os.Stderr, "Advanced search found no results, trying simple search...\n")
+		// Track durations far from a source's mean it describes another recording or edition
+		for _, r := range results {
+			check := merged.CompareDurations(r.Torrent)
+			if len(check.Mismatches) == 0 {
+				continue
 			}
-			// Combine artist and album for simple query search
-			combinedQuery := artist + " " + album
-			releases, err = client.SearchSimple(combinedQuery)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Discogs fallback search failed: %v\n", err)
-				return
+			label := sourceNames[r.Source]
+			if label == "" {
+				label = r.Source
 			}
-			if len(releases) == 0 {
-				fmt.Fprintf(os.Stderr, "No Discogs releases found for: %s - %s\n", artist, album)
-				return
+			fmt.Fprintf(os.Stderr, "⚠️  %s: %s from the files (%s)\n", label, check, strings.Join(durationMismatches(check, 3), "; "))
+			if check.DifferentRecording() {
+				fmt.Fprintf(os.Stderr, "⚠️  %s may describe a different recording or edition; check the release before tagging\n", label)
 			}
 		}
+		if err := merged.Save(mergedFile); err != nil {
+			return nil, "", fmt.Errorf("saving merged metadata: %w", err)
+		}
+		sources := make([]string, len(results))
+		for i, r := range results {
+			sources[i] = r.Source
+		}
+		console.Resultf(mergedFile, "✓ Merged metadata (%s) saved to: %s\n", strings.Join(sources, " → "), mergedFile)
+		final, finalFile = merged, mergedFile
+	}
+	return final, finalFile, nil
+}
+
+// durationMismatches describes the first n mismatches of check, noting how many more there are.
+func durationMismatches(check domain.DurationCheck, n int) []string {
+	var lines []string
+	for _, m := range check.Mismatches[:min(n, len(check.Mismatches))] {
+		lines = append(lines, m.String())
+	}
+	if more := len(check.Mismatches) - n; more > 0 {
+		lines = append(lines, fmt.Sprintf("%d more", more))
 	}
+	return lines
+}
 
-	// Handle search results
-	if len(releases) > 1 {
-		// Multiple matches - display and exit
-		fmt.Fprintf(os.Stderr, "\nMultiple Discogs releases found:\n\n")
+// outputBase returns the path output files for albumDir start with: baseName,
+// or one derived from the directory name, in the work directory unless absolute.
+func (x *extractor) outputBase(albumDir, baseName string) string {
+	if baseName == "" {
+		baseName = defaultBaseName(albumDir)
+	}
+	if filepath.IsAbs(baseName) {
+		return baseName
+	}
+	return filepath.Join(x.workDir, baseName)
+}
 
-		releaseTemplate := `  [{{.ID}}] {{.Title}}{{if .Label}} - {{.Label}}{{end}}{{if .CatalogNumber}} {{.CatalogNumber}}{{end}}{{if gt .Year 0}} ({{.Year}}){{end}}{{if .Country}}, {{.Country}}{{end}}\n`
-		tmpl := template.Must(template.New("release").Parse(releaseTemplate))
-		for _, release := range releases {
-			if err := tmpl.Execute(os.Stderr, release); err != nil {
-				fmt.Fprintf(os.Stderr, "Error rendering template: %v\n", err)
-			}
+// outputDir returns the directory output files go in, creating it: -workdir,
+// else under -read-only the configured work directory, else "" for the
+// current directory. With -read-only it refuses the options that change
+// album directories and work or quarantine directories inside one of dirs.
+func outputDir(dirs []string) (string, error) {
+	ro := config.LoadReadOnly()
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "read-only" {
+			ro.Enabled = *readOnly
 		}
+	})
+	out := *workDir
+	if !ro.Enabled {
+		return out, mkdirIfSet(out)
+	}
 
-		fmt.Fprintf(os.Stderr, "\nPlease re-run with --release-id to select a specific release:\n")
-		fmt.Fprintf(os.Stderr, "  extract -dir %q --release-id XXXXXX\n\n", *dir)
-		os.Exit(1)
+	switch {
+	case *fixNFC || *fixCover || *fetchCover != "" || *splitAlbums:
+		return "", fmt.Errorf("-fix-nfc, -fix-cover, -fetch-cover and -split change album files and cannot be used with -read-only")
+	case *quarantine != "" && !*quarLink:
+		return "", fmt.Errorf("-quarantine moves albums; use -quarantine-link with -read-only")
+	}
+	if out == "" {
+		out = ro.WorkDir
+	}
+	if out == "" {
+		out = filepath.Join(state.Dir(), "work")
 	}
+	for _, albumDir := range dirs {
+		switch {
+		case filesystem.IsWithin(out, albumDir):
+			return "", fmt.Errorf("-read-only work directory %s is inside album directory %s", out, albumDir)
+		case *quarantine != "" && filesystem.IsWithin(*quarantine, albumDir):
+			return "", fmt.Errorf("-read-only quarantine directory %s is inside album directory %s", *quarantine, albumDir)
+		}
+	}
+	return out, mkdirIfSet(out)
+}
 
-	// Single match - fetch automatically
-	if *verbose {
-		fmt.Fprintf(os.Stderr, "Found single match: %s - %s [%d]\n",
-			releases[0].Label, releases[0].CatalogNumber, releases[0].ID)
+// mkdirIfSet creates dir unless it is "".
+func mkdirIfSet(dir string) error {
+	if dir == "" {
+		return nil
 	}
+	return os.MkdirAll(dir, 0755)
+}
+
+// defaultBaseName derives the output file base name from an album directory.
+func defaultBaseName(albumDir string) string {
+	baseName := filepath.Base(albumDir)
+	// Clean up the name (remove common suffixes)
+	baseName = strings.TrimSuffix(baseName, " (FLAC)")
+	return strings.TrimSuffix(baseName, " FLAC")
+}
 
-	release, err := client.GetRelease(releases[0].ID)
+// discogsClient returns the Discogs client, or nil when the API is disabled
+// or no token is configured.
+func discogsClient() *discogs.Client {
+	if *noAPI {
+		if *verbose {
+			console.Infof("Skipping Discogs API (--no-api specified)\n")
+		}
+		return nil
+	}
+
+	token, err := config.LoadDiscogsToken()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching release details: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Warning: Cannot load Discogs token: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Continuing without Discogs lookup.\n")
+		fmt.Fprintf(os.Stderr, "To enable Discogs lookup, create ~/.config/classical-tagger/config.yaml with your token.\n")
+		return nil
+	}
+
+	client := discogs.NewClient(token)
+	limits := config.LoadDiscogsLimits().Override(*apiRequests, *apiWindow, *apiTimeout)
+	client.RateLimiter = ratelimit.NewRateLimiter(limits.Requests, limits.Window)
+	client.HTTPClient.Timeout = limits.Timeout
+	return client
+}
+
+// redactedClient returns the Redacted client, or nil when no API key is configured.
+func redactedClient() *uploader.RedactedClient {
+	apiKey, err := config.LoadRedactedAPIKey()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Cannot load Redacted API key: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Continuing without Redacted lookup.\n")
+		return nil
 	}
 
-	discogsFile := baseName + "_discogs.json"
-	// Use parent directory as rootPath so generated directory is a sibling of local directory
-	parentDir := filepath.Dir(*dir)
-	if err := release.SaveToFile(discogsFile, parentDir, localTorrent); err != nil {
-		fmt.Fprintf(os.Stderr, "Error saving Discogs data: %v\n", err)
+	client := uploader.NewRedactedClient(apiKey)
+	limits := config.LoadRedactedLimits()
+	client.RateLimiter = ratelimit.NewRateLimiter(limits.Requests, limits.Window)
+	client.HTTPClient.Timeout = limits.Timeout
+	return client
+}
+
+// startProfiling starts the -cpuprofile and -memprofile profiles and returns
+// the function that writes them.
+func startProfiling() func() {
+	stop, err := profiling.Start(*cpuProfile, *memProfile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	return func() {
+		if err := stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+}
 
-	fmt.Fprintf(os.Stderr, "✓ Discogs metadata saved to: %s\n", discogsFile)
+// logf prints enrichment progress and warnings to stderr.
+func logf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
 }
 
 func usage() {
-	fmt.Fprintf(os.Stderr, "Usage: extract -dir DIRECTORY [options]\n\n")
+	fmt.Fprintf(os.Stderr, "Usage: extract -dir DIRECTORY [options]\n")
+	fmt.Fprintf(os.Stderr, "       extract -batch [options] DIRECTORY...\n\n")
 	fmt.Fprintf(os.Stderr, "Extract metadata from FLAC files and optionally enrich with Discogs data.\n\n")
 	fmt.Fprintf(os.Stderr, "Options:\n")
 	flag.PrintDefaults()
 	fmt.Fprintf(os.Stderr, "\nOutput:\n")
-	fmt.Fprintf(os.Stderr, "  Creates two files:\n")
+	fmt.Fprintf(os.Stderr, "  Creates, in the current directory (or -workdir; with -read-only, the work directory):\n")
 	fmt.Fprintf(os.Stderr, "    <name>.json         - Metadata extracted from FLAC files\n")
 	fmt.Fprintf(os.Stderr, "    <name>_discogs.json - Metadata from Discogs API (if available)\n")
+	fmt.Fprintf(os.Stderr, "    <name>_web.json     - Metadata scraped from the -url album page (if given)\n")
+	fmt.Fprintf(os.Stderr, "    <name>_redacted.json - Local files with the -torrent's Redacted group metadata (if given)\n")
+	fmt.Fprintf(os.Stderr, "    <name>_merged.json  - All sources merged by field precedence (when more than one contributed)\n")
+	fmt.Fprintf(os.Stderr, "  and, with -tracklist:\n")
+	fmt.Fprintf(os.Stderr, "    <name>_tracklist.json - Local metadata with titles from the tracklist\n")
+	fmt.Fprintf(os.Stderr, "\nTracklist format:\n")
+	fmt.Fprintf(os.Stderr, "  One track per line: \"1. Title\", \"01 - Title 4:32\", \"2-05 Title\" (disc-track).\n")
+	fmt.Fprintf(os.Stderr, "  \"CD 2\"/\"Disc 2\" lines start a new disc. \"Composer Name: Title\" sets the composer.\n")
 	fmt.Fprintf(os.Stderr, "\nExamples:\n")
 	fmt.Fprintf(os.Stderr, "  # Extract with automatic Discogs lookup:\n")
 	fmt.Fprintf(os.Stderr, "  extract -dir \"/music/Bach - Goldberg Variations\"\n\n")
+	fmt.Fprintf(os.Stderr, "  # Several matching releases are offered to choose from; in scripts, list them and exit:\n")
+	fmt.Fprintf(os.Stderr, "  extract -dir \"/music/Bach - Goldberg Variations\" --non-interactive\n\n")
+	fmt.Fprintf(os.Stderr, "  # In scripts: print only the metadata files written, the last to tag from:\n")
+	fmt.Fprintf(os.Stderr, "  extract -dir \"/music/Bach - Goldberg Variations\" --non-interactive --quiet | tail -n 1\n\n")
 	fmt.Fprintf(os.Stderr, "  # Use specific Discogs release:\n")
 	fmt.Fprintf(os.Stderr, "  extract -dir \"/music/Bach - Goldberg Variations\" --release-id 195873\n\n")
+	fmt.Fprintf(os.Stderr, "  # Search by catalog number or barcode (tags and cue sheets are tried automatically):\n")
+	fmt.Fprintf(os.Stderr, "  extract -dir \"/music/Bach - Goldberg Variations\" --catno \"479 1234\"\n\n")
 	fmt.Fprintf(os.Stderr, "  # Local extraction only:\n")
-	fmt.Fprintf(os.Stderr, "  extract -dir \"/music/Bach - Goldberg Variations\" --no-api\n")
+	fmt.Fprintf(os.Stderr, "  extract -dir \"/music/Bach - Goldberg Variations\" --no-api\n\n")
+	fmt.Fprintf(os.Stderr, "  # Take titles from the booklet:\n")
+	fmt.Fprintf(os.Stderr, "  extract -dir \"/music/Bach - Goldberg Variations\" --tracklist booklet.txt --no-api\n\n")
+	fmt.Fprintf(os.Stderr, "  # Album not on Discogs: combine the local files with an existing Redacted torrent's group:\n")
+	fmt.Fprintf(os.Stderr, "  extract -dir \"/music/Bach - Goldberg Variations\" --hybrid --torrent 1234567\n")
+	fmt.Fprintf(os.Stderr, "\n  # Two albums unpacked into one folder: give each its own directory and metadata:\n")
+	fmt.Fprintf(os.Stderr, "  extract -dir /music/incoming/Merged --split\n")
+	fmt.Fprintf(os.Stderr, "\n  # Extract a shelf of albums, answering uncertain matches at the end:\n")
+	fmt.Fprintf(os.Stderr, "  extract -batch -review-time 10m /music/incoming/*\n")
+	fmt.Fprintf(os.Stderr, "\n  # Set aside the albums that fail validation, with a report for each:\n")
+	fmt.Fprintf(os.Stderr, "  extract -batch -quarantine /music/quarantine /music/incoming/*\n")
+	exitcode.PrintCodes(os.Stderr)
+}
+
+// applyTracklist copies titles and composers from a text tracklist onto torrent and saves it.
+// torrent is updated in place so later steps see the tracklist titles.
+func applyTracklist(torrent *domain.Torrent, tracklistPath, outputFile string) error {
+	f, err := os.Open(tracklistPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	result, err := scraping.ParseTracklist(f, torrent.Composers())
+	if err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "⚠️  Tracklist: %s\n", warning)
+	}
+	for _, warning := range scraping.ApplyTracklist(torrent, result.Torrent) {
+		fmt.Fprintf(os.Stderr, "⚠️  Tracklist: %s\n", warning)
+	}
+	return torrent.Save(outputFile)
 }
 
 // extractFromDirectory extracts metadata from local FLAC files
-func extractFromDirectory(dirPath string) *domain.Torrent {
-	album, err := scraping.ExtractFromDirectory(dirPath)
+func extractFromDirectory(dirPath string, opts scraping.ExtractOptions) (*domain.Torrent, error) {
+	album, err := scraping.ExtractFromDirectoryWithOptions(dirPath, opts)
 
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error extracting from directory: %v\n", err)
 		if !*force {
-			os.Exit(1)
+			return nil, fmt.Errorf("extracting from directory: %w", err)
 		}
-		fmt.Fprintf(os.Stderr, "Forcing local extraction.\n")
+		exitcode.Print(os.Stderr, "Error extracting from directory", err)
+		console.Infof("Forcing local extraction.\n")
 		album = &domain.Album{
 			Title: filepath.Base(dirPath),
 		}
@@ -213,45 +833,27 @@ func extractFromDirectory(dirPath string) *domain.Torrent {
 
 	// Display extraction summary
 	if torrent != nil {
-		fmt.Fprintf(os.Stderr, "✓ Extracted: %s", torrent.Title)
+		console.Infof("✓ Extracted: %s", torrent.Title)
 		if torrent.OriginalYear > 0 {
-			fmt.Fprintf(os.Stderr, " (%d)", torrent.OriginalYear)
+			console.Infof(" (%d)", torrent.OriginalYear)
 		}
-		fmt.Fprintf(os.Stderr, " - %d tracks\n", len(torrent.Tracks()))
+		console.Infof(" - %d tracks\n", len(torrent.Tracks()))
 	}
 
-	return torrent
+	return torrent, nil
 }
 
-// extractArtist attempts to get a searchable artist from the torrent
-func extractArtist(t *domain.Torrent) string {
-	if t == nil {
-		return ""
-	}
-
-	// Try album artist first
-	if len(t.AlbumArtist) > 0 {
-		// Look for composer
-		for _, artist := range t.AlbumArtist {
-			if artist.Role == domain.RoleComposer {
-				return artist.Name
-			}
-		}
-		// Use first artist
-		return t.AlbumArtist[0].Name
-	}
-
-	// Try to find composer from tracks
-	tracks := t.Tracks()
-	if len(tracks) > 0 && len(tracks[0].Artists) > 0 {
-		for _, artist := range tracks[0].Artists {
-			if artist.Role == domain.RoleComposer {
-				return artist.Name
-			}
-		}
-		// Use first artist from first track
-		return tracks[0].Artists[0].Name
+// confirmPropagation asks on out whether to credit an album performer on the
+// tracks that don't already list them (-artist-propagation prompt), reading the
+// answer from in.
+func confirmPropagation(in *bufio.Reader, out io.Writer, artist domain.Artist, missing []*domain.Track) bool {
+	fmt.Fprintf(out, "%s (%s) is an album artist but missing from %d track(s):\n", artist.Name, artist.Role, len(missing))
+	for _, track := range missing {
+		fmt.Fprintf(out, "  %d-%02d %s\n", track.Disc, track.Track, track.Title)
 	}
+	fmt.Fprintf(out, "Add them to these tracks? [y/N] ")
 
-	return ""
+	answer, _ := in.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
 }
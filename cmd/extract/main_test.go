@@ -0,0 +1,28 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestConfirmPropagation_SharedReader(t *testing.T) {
+	artist := domain.Artist{Name: "Wiener Philharmoniker", Role: domain.RoleEnsemble}
+	missing := []*domain.Track{{Disc: 1, Track: 3, Title: "Finale"}}
+
+	// Both answers arrive at once (typed ahead or piped); the second prompt
+	// must still see its own
+	in := bufio.NewReader(strings.NewReader("y\nn\n"))
+	if !confirmPropagation(in, io.Discard, artist, missing) {
+		t.Error("first answer y: confirmPropagation() = false, want true")
+	}
+	if confirmPropagation(in, io.Discard, artist, missing) {
+		t.Error("second answer n: confirmPropagation() = true, want false")
+	}
+	if answer, _ := in.ReadString('\n'); answer != "" {
+		t.Errorf("input left = %q, want none", answer)
+	}
+}
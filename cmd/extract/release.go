@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/enrich"
+	"github.com/cehbz/classical-tagger/internal/review"
+)
+
+// chooseRelease picks the release when err reports several matches (see
+// selectRelease), asking on the terminal when extract is interactive, and
+// enriches again with the one chosen. Without a choice, or for any other
+// outcome, the enrichment's results are returned as they were.
+func (x *extractor) chooseRelease(ctx context.Context, albumDir, baseName string, local, final *domain.Torrent, finalFile string, err error) (*domain.Torrent, string, error) {
+	var ambiguous *enrich.AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		return final, finalFile, err
+	}
+	choice := selectRelease(x.stdin, os.Stderr, x.interactive, albumDir, ambiguous)
+	if choice < 0 {
+		return final, finalFile, err
+	}
+	console.Infof("✓ Using %s release %d\n", ambiguous.Source, ambiguous.IDs[choice])
+	return x.enrich(ctx, albumDir, baseName, local, ambiguous.IDs[choice])
+}
+
+// selectRelease picks which of the releases ambiguous lists the album in
+// albumDir is. Interactive, it asks on out and reads the answer from in;
+// otherwise, or when no release is chosen, it lists them on out with how to
+// pick one on the next run. It returns the index of the release chosen, or -1.
+func selectRelease(in io.Reader, out io.Writer, interactive bool, albumDir string, ambiguous *enrich.AmbiguousError) int {
+	if interactive && len(ambiguous.IDs) == len(ambiguous.Candidates) {
+		choice := -1
+		queue := &review.Queue{}
+		queue.Add(review.Decision{
+			Album:    albumDir,
+			Kind:     "release",
+			Question: fmt.Sprintf("Which %s release is this album?", ambiguous.Source),
+			Choices:  ambiguous.Candidates,
+			Apply: func(c int) error {
+				choice = c
+				return nil
+			},
+		})
+		queue.Review(in, out, 0)
+		if choice >= 0 {
+			return choice
+		}
+	}
+
+	fmt.Fprintf(out, "\nMultiple %s releases found:\n\n", ambiguous.Source)
+	for _, candidate := range ambiguous.Candidates {
+		fmt.Fprintf(out, "  %s\n", candidate)
+	}
+	fmt.Fprintf(out, "\nPlease %s:\n", ambiguous.Hint)
+	fmt.Fprintf(out, "  extract -dir %q --release-id XXXXXX\n\n", albumDir)
+	return -1
+}
+
+// isTerminal reports whether f is a terminal someone can answer questions on.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/enrich"
+)
+
+func TestSelectRelease(t *testing.T) {
+	ambiguous := &enrich.AmbiguousError{
+		Source:     "Discogs",
+		Candidates: []string{"[1111] Goldberg Variations (1982, CD)", "[2222] Goldberg Variations (1955, LP)"},
+		IDs:        []int{1111, 2222},
+		Hint:       "select a release with --release-id",
+	}
+
+	tests := []struct {
+		Name        string
+		Input       string
+		Interactive bool
+		Want        int
+		WantListed  bool // Releases listed with how to pick one
+	}{
+		{Name: "valid choice", Input: "2\n", Interactive: true, Want: 1},
+		{Name: "out of range, then valid", Input: "3\n1\n", Interactive: true, Want: 0},
+		{Name: "garbage, then valid", Input: "second\n2\n", Interactive: true, Want: 1},
+		{Name: "skipped", Input: "\n", Interactive: true, Want: -1, WantListed: true},
+		{Name: "garbage until EOF", Input: "0\nfoo", Interactive: true, Want: -1, WantListed: true},
+		{Name: "EOF", Input: "", Interactive: true, Want: -1, WantListed: true},
+		{Name: "non-interactive lists without reading", Input: "1\n", Want: -1, WantListed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			var out strings.Builder
+			got := selectRelease(strings.NewReader(tt.Input), &out, tt.Interactive, "/music/Goldberg", ambiguous)
+			if got != tt.Want {
+				t.Errorf("selectRelease() = %d, want %d\n%s", got, tt.Want, out.String())
+			}
+			listed := strings.Contains(out.String(), "Multiple Discogs releases found") &&
+				strings.Contains(out.String(), "[2222] Goldberg Variations (1955, LP)") &&
+				strings.Contains(out.String(), `extract -dir "/music/Goldberg" --release-id`)
+			if listed != tt.WantListed {
+				t.Errorf("listed = %v, want %v:\n%s", listed, tt.WantListed, out.String())
+			}
+			if asked := strings.Contains(out.String(), "Which Discogs release is this album?"); asked != tt.Interactive {
+				t.Errorf("asked = %v, want %v", asked, tt.Interactive)
+			}
+		})
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if isTerminal(r) {
+		t.Error("isTerminal(pipe) = true, want false")
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "answers"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if isTerminal(f) {
+		t.Error("isTerminal(file) = true, want false")
+	}
+	f.Close()
+	if isTerminal(f) {
+		t.Error("isTerminal(closed file) = true, want false")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/filesystem"
+	"github.com/cehbz/classical-tagger/internal/scraping"
+)
+
+// split handles a directory holding several albums: it prints the plan giving
+// each album its own directory beside albumDir, and with -split moves the
+// files there and extracts each album separately. Without -split it returns
+// ErrMergedAlbums rather than extracting one garbled album. The caller holds
+// albumDir's lock; each other album is extracted under its own.
+func (x *extractor) split(ctx context.Context, albumDir string, groups []scraping.AlbumGroup) error {
+	dests := splitDestinations(albumDir, groups)
+	fmt.Fprintf(os.Stderr, "⚠️  %s holds %d albums:\n", albumDir, len(groups))
+	for i, group := range groups {
+		fmt.Fprintf(os.Stderr, "  %s: %d files → %s\n", group, len(group.Files), dests[i])
+	}
+	if !*splitAlbums {
+		return fmt.Errorf("%w: %s", domain.ErrMergedAlbums, albumDir)
+	}
+
+	// Check every destination first so a refused split moves nothing
+	for _, dest := range dests {
+		if dest == albumDir {
+			continue
+		}
+		if _, err := os.Lstat(dest); err == nil {
+			return fmt.Errorf("cannot split %s: %s already exists", albumDir, dest)
+		}
+	}
+	for i, group := range groups {
+		if dests[i] == albumDir {
+			continue
+		}
+		if err := filesystem.MoveFiles(albumDir, dests[i], group.Files); err != nil {
+			return fmt.Errorf("splitting %s: %w", albumDir, err)
+		}
+		console.Infof("✓ Moved %d files to %s\n", len(group.Files), dests[i])
+	}
+	fmt.Fprintf(os.Stderr, "Other files (cover, log, cue) stay in %s; move them to their album by hand\n", albumDir)
+
+	var errs []error
+	for _, dest := range dests {
+		console.Infof("\nExtracting %s\n", filepath.Base(dest))
+		extract := x.extract
+		if dest == albumDir {
+			extract = x.extractLocked
+		}
+		if err := extract(ctx, dest, ""); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(dest), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// splitDestinations names the directory of each album in groups, beside
+// albumDir, as tag would name it from the album's own files. An album named
+// as albumDir already is stays in it; names two albums share get their
+// catalog number, or a counter, appended.
+func splitDestinations(albumDir string, groups []scraping.AlbumGroup) []string {
+	template := config.LoadDirectoryTemplate()
+	dests := make([]string, len(groups))
+	seen := make(map[string]bool)
+	for i, group := range groups {
+		name := group.Album
+		album, err := scraping.ExtractGroup(albumDir, group, scraping.ExtractOptions{
+			ArtistPropagation:    domain.ArtistKeepSparse,
+			AllowMissingComposer: true,
+		})
+		if err == nil {
+			name = album.ToTorrent(filepath.Base(albumDir)).DirectoryNameFromTemplate(template)
+		}
+		name = uniqueName(domain.SanitizeDirectoryName(name), group.CatalogNumber, seen)
+		seen[name] = true
+		dests[i] = filepath.Join(filepath.Dir(albumDir), name)
+	}
+	return dests
+}
+
+// uniqueName returns name, or when another group took it, name with the
+// group's catalog number, then numbered "name (2)", "name (3)" until unseen.
+func uniqueName(name, catalogNumber string, seen map[string]bool) string {
+	if !seen[name] {
+		return name
+	}
+	if catalogNumber != "" {
+		name = domain.SanitizeDirectoryName(fmt.Sprintf("%s [%s]", name, catalogNumber))
+	}
+	base := name
+	for n := 2; seen[name]; n++ {
+		name = domain.SanitizeDirectoryName(fmt.Sprintf("%s (%d)", base, n))
+	}
+	return name
+}
@@ -0,0 +1,20 @@
+package main
+
+import "testing"
+
+func TestUniqueName(t *testing.T) {
+	seen := make(map[string]bool)
+	var got []string
+	for _, catno := range []string{"", "", "", "", "CD-1"} {
+		name := uniqueName("Bach - Cantatas", catno, seen)
+		seen[name] = true
+		got = append(got, name)
+	}
+
+	want := []string{"Bach - Cantatas", "Bach - Cantatas (2)", "Bach - Cantatas (3)", "Bach - Cantatas (4)", "Bach - Cantatas [CD-1]"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("name %d = %q, want %q", i+1, got[i], want[i])
+		}
+	}
+}
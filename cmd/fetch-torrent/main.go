@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+	"github.com/cehbz/classical-tagger/internal/torrentfile"
+	"github.com/cehbz/classical-tagger/internal/uploader"
+)
+
+var (
+	torrentID   = flag.Int("id", 0, "ID of the torrent on Redacted to download (required)")
+	dir         = flag.String("dir", "", "Local tagged directory to check against the site's file list and piece hashes")
+	rootName    = flag.String("root", "", "Library root from config to resolve a relative -dir against")
+	localFile   = flag.String("torrent", "", "The .torrent file you uploaded, to compare with the site's")
+	output      = flag.String("output", "", "Save the downloaded .torrent to this file (it carries your passkey)")
+	apiKey      = flag.String("api-key", "", "Redacted API key (default: redacted.api_key in config)")
+	apiTimeout  = flag.Duration("timeout", 0, "Redacted HTTP timeout (default: redacted.timeout_seconds in config, or 30s)")
+	apiRequests = flag.Int("redacted-requests", 0, "Redacted requests allowed per window (default: redacted.rate_limit in config, or 10)")
+	apiWindow   = flag.Duration("redacted-window", 0, "Redacted rate limit window (default: redacted.rate_limit in config, or 10s)")
+)
+
+// CrossCheck is what the site's .torrent says compared with the local copies
+// of an upload.
+type CrossCheck struct {
+	Site  *torrentfile.MetaInfo
+	Local *torrentfile.MetaInfo // The .torrent uploaded, nil when not given
+	Dir   string                // The local directory, "" when not given
+	// SameAsLocal reports whether Local has the site's info hash;
+	// TorrentDiffs says how it differs otherwise
+	SameAsLocal  bool
+	TorrentDiffs []string
+	// Files and Extra check Dir against the site's torrent
+	Files []torrentfile.FileResult
+	Extra []string
+}
+
+// Diverges reports whether the local .torrent or directory differs from what
+// the site has.
+func (c *CrossCheck) Diverges() bool {
+	if len(c.TorrentDiffs) > 0 || len(c.Extra) > 0 {
+		return true
+	}
+	for _, f := range c.Files {
+		if !f.OK() {
+			return true
+		}
+	}
+	return false
+}
+
+func main() {
+	console.AddFlag(flag.CommandLine)
+	flag.Usage = usage
+	exitcode.ParseFlags(flag.CommandLine, os.Args[1:])
+
+	if *torrentID <= 0 {
+		usage()
+		os.Exit(exitcode.Failure)
+	}
+
+	root, err := config.LoadRoot(*rootName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -root: %v\n", err)
+		os.Exit(exitcode.Failure)
+	}
+	if *dir != "" {
+		*dir = root.Resolve(*dir)
+		if info, err := os.Stat(*dir); err != nil || !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "Error: directory %s does not exist\n", *dir)
+			os.Exit(exitcode.Load)
+		}
+	}
+	var local *torrentfile.MetaInfo
+	if *localFile != "" {
+		if local, err = torrentfile.Load(*localFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -torrent: %v\n", err)
+			os.Exit(exitcode.Load)
+		}
+	}
+
+	if *apiKey == "" {
+		if *apiKey, err = config.LoadRedactedAPIKey(); err != nil {
+			exitcode.Fail("Error loading API key from config", err)
+		}
+	}
+	client := uploader.NewRedactedClient(*apiKey)
+	limits := config.LoadRedactedLimits().Override(*apiRequests, *apiWindow, *apiTimeout)
+	client.RateLimiter = ratelimit.NewRateLimiter(limits.Requests, limits.Window)
+	client.HTTPClient.Timeout = limits.Timeout
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	data, err := client.DownloadTorrent(ctx, *torrentID)
+	if err != nil {
+		exitcode.Fail(fmt.Sprintf("Error downloading torrent %d", *torrentID), err)
+	}
+	site, err := torrentfile.Parse(data)
+	if err != nil {
+		exitcode.Fail(fmt.Sprintf("Error: torrent %d", *torrentID), err)
+	}
+	if *output != "" {
+		if err := os.WriteFile(*output, data, 0600); err != nil {
+			exitcode.Fail("Error", err)
+		}
+		console.Infof("✓ Saved torrent %d to %s\n", *torrentID, *output)
+	}
+
+	check, err := Check(site, local, *dir)
+	if err != nil {
+		exitcode.Fail("Error", err)
+	}
+	PrintCrossCheck(os.Stdout, check)
+	if check.Diverges() {
+		os.Exit(exitcode.Validation)
+	}
+}
+
+// Check compares the site's torrent with the local .torrent and the local
+// directory, either of which may be absent (nil, "").
+func Check(site, local *torrentfile.MetaInfo, dir string) (*CrossCheck, error) {
+	check := &CrossCheck{Site: site, Local: local, Dir: dir}
+	if local != nil {
+		check.SameAsLocal = local.InfoHash == site.InfoHash
+		if !check.SameAsLocal {
+			check.TorrentDiffs = compareTorrents(site, local)
+		}
+	}
+	if dir == "" {
+		return check, nil
+	}
+
+	// Single-file torrents are verified relative to the containing directory
+	contentDir := dir
+	if site.IsSingleFile() {
+		contentDir = filepath.Dir(dir)
+	}
+	var err error
+	if check.Files, err = torrentfile.Verify(contentDir, site); err != nil {
+		return nil, fmt.Errorf("failed to verify directory: %w", err)
+	}
+	if check.Extra, err = torrentfile.Extra(dir, site); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// compareTorrents lists how the local torrent's content differs from the
+// site's. The source field is left out: it changes the info hash but not the
+// content, and the site may rewrite it.
+func compareTorrents(site, local *torrentfile.MetaInfo) []string {
+	var diffs []string
+	if site.Name != local.Name {
+		diffs = append(diffs, fmt.Sprintf("name: site %q, local %q", site.Name, local.Name))
+	}
+	if site.PieceLength != local.PieceLength {
+		diffs = append(diffs, fmt.Sprintf("piece length: site %d, local %d", site.PieceLength, local.PieceLength))
+	}
+
+	localSizes := make(map[string]int64, len(local.Files))
+	for _, f := range local.Files {
+		localSizes[f.Path] = f.Length
+	}
+	siteSizes := make(map[string]int64, len(site.Files))
+	for _, f := range site.Files {
+		siteSizes[f.Path] = f.Length
+		size, ok := localSizes[f.Path]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("%s: only on the site", f.Path))
+		case size != f.Length:
+			diffs = append(diffs, fmt.Sprintf("%s: site %d bytes, local %d", f.Path, f.Length, size))
+		}
+	}
+	for _, f := range local.Files {
+		if _, ok := siteSizes[f.Path]; !ok {
+			diffs = append(diffs, fmt.Sprintf("%s: only in the local torrent", f.Path))
+		}
+	}
+
+	if len(diffs) == 0 && len(site.Pieces) == len(local.Pieces) {
+		differing := 0
+		for i := range site.Pieces {
+			if site.Pieces[i] != local.Pieces[i] {
+				differing++
+			}
+		}
+		if differing > 0 {
+			diffs = append(diffs, fmt.Sprintf("%d of %d pieces differ in content", differing, len(site.Pieces)))
+		}
+	}
+	return diffs
+}
+
+// PrintCrossCheck formats and prints a cross-check of the site's torrent. Quiet,
+// it prints one difference per line: the local .torrent's, then the files'.
+func PrintCrossCheck(w io.Writer, c *CrossCheck) {
+	if console.Quiet() {
+		for _, d := range c.TorrentDiffs {
+			fmt.Fprintf(w, "local torrent: %s\n", d)
+		}
+		for _, f := range c.Files {
+			if !f.OK() {
+				fmt.Fprintf(w, "%s: %s\n", f.Path, f.Problem())
+			}
+		}
+		for _, path := range c.Extra {
+			fmt.Fprintf(w, "%s: not in the site's torrent\n", path)
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "=== Site Torrent ===\n\n")
+	fmt.Fprintf(w, "Name:      %s\n", c.Site.Name)
+	fmt.Fprintf(w, "Info hash: %s\n", hex.EncodeToString(c.Site.InfoHash[:]))
+	fmt.Fprintf(w, "Files:     %d (%d bytes)\n", len(c.Site.Files), c.Site.TotalLength())
+	if c.Site.Source != "" {
+		fmt.Fprintf(w, "Source:    %s\n", c.Site.Source)
+	}
+
+	if c.Local != nil {
+		fmt.Fprintf(w, "\n=== Local Torrent ===\n\n")
+		switch {
+		case c.SameAsLocal:
+			fmt.Fprintf(w, "✓ Same info hash as the site's\n")
+		case len(c.TorrentDiffs) == 0:
+			fmt.Fprintf(w, "✓ Same content; the info hash differs (source: site %q, local %q)\n", c.Site.Source, c.Local.Source)
+		default:
+			fmt.Fprintf(w, "❌ Differs from the site's:\n")
+			for _, d := range c.TorrentDiffs {
+				fmt.Fprintf(w, "    %s\n", d)
+			}
+		}
+	}
+
+	if c.Dir == "" {
+		return
+	}
+	fmt.Fprintf(w, "\n=== Directory ===\n\n")
+	fmt.Fprintf(w, "Directory: %s\n\n", c.Dir)
+	failed := 0
+	for _, f := range c.Files {
+		if f.OK() {
+			fmt.Fprintf(w, "✓ %s\n", f.Path)
+			continue
+		}
+		failed++
+		fmt.Fprintf(w, "❌ %s: %s\n", f.Path, f.Problem())
+	}
+	for _, path := range c.Extra {
+		fmt.Fprintf(w, "❌ %s: not in the site's torrent\n", path)
+	}
+
+	fmt.Fprintln(w, "\n=== SUMMARY ===")
+	if failed+len(c.Extra) > 0 {
+		fmt.Fprintf(w, "❌ DIVERGES: %d of %d files differ from the site's, %d not on the site\n", failed, len(c.Files), len(c.Extra))
+	} else {
+		fmt.Fprintf(w, "✅ MATCHES: %d files as the site has them\n", len(c.Files))
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: fetch-torrent -id N [-dir DIRECTORY] [-torrent FILE] [-output FILE]\n\n")
+	fmt.Fprintf(os.Stderr, "Download a torrent's .torrent file from Redacted and check what the site has\n")
+	fmt.Fprintf(os.Stderr, "against your copies of an upload.\n\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nChecks:\n")
+	fmt.Fprintf(os.Stderr, "  -dir      file list, file sizes and piece hashes of the directory, and files\n")
+	fmt.Fprintf(os.Stderr, "            the site's torrent does not contain\n")
+	fmt.Fprintf(os.Stderr, "  -torrent  info hash, name, file list and pieces of the .torrent you uploaded\n")
+	fmt.Fprintf(os.Stderr, "\nConfig file location: %s\n", config.GetConfigPathForDisplay())
+	exitcode.PrintCodes(os.Stderr)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/torrentfile"
+)
+
+// buildTorrent returns a parsed torrent named Album of the given files, in
+// order, with 8-byte pieces.
+func buildTorrent(t *testing.T, source string, files map[string]string, order []string) *torrentfile.MetaInfo {
+	t.Helper()
+	var content []byte
+	fileList := []any{}
+	for _, name := range order {
+		content = append(content, files[name]...)
+		fileList = append(fileList, map[string]any{"length": len(files[name]), "path": []any{name}})
+	}
+	var pieces []byte
+	const pieceLength = 8
+	for i := 0; i < len(content); i += pieceLength {
+		h := sha1.Sum(content[i:min(i+pieceLength, len(content))])
+		pieces = append(pieces, h[:]...)
+	}
+	data, err := torrentfile.Encode(map[string]any{
+		"info": map[string]any{
+			"name":         "Album",
+			"piece length": pieceLength,
+			"pieces":       pieces,
+			"files":        fileList,
+			"source":       source,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := torrentfile.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestCheck_Directory(t *testing.T) {
+	files := map[string]string{"01.flac": "first track data", "02.flac": "second track data"}
+	site := buildTorrent(t, "RED", files, []string{"01.flac", "02.flac"})
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "01.flac"), []byte(files["01.flac"]), 0644)
+	os.WriteFile(filepath.Join(dir, "02.flac"), []byte(files["02.flac"]), 0644)
+	check, err := Check(site, nil, dir)
+	if err != nil {
+		t.Fatalf("Check error: %v", err)
+	}
+	if check.Diverges() {
+		t.Errorf("identical directory diverges: %+v", check)
+	}
+
+	// Retagged after uploading, with a file the upload left out
+	os.WriteFile(filepath.Join(dir, "02.flac"), []byte("second track DATA"), 0644)
+	os.WriteFile(filepath.Join(dir, "cover.jpg"), []byte("jpeg"), 0644)
+	if check, err = Check(site, nil, dir); err != nil {
+		t.Fatalf("Check error: %v", err)
+	}
+	if !check.Diverges() || check.Files[0].BadPieces != 0 || check.Files[1].BadPieces == 0 {
+		t.Errorf("Check = %+v, want 02.flac to fail its hash check", check.Files)
+	}
+	if !slices.Equal(check.Extra, []string{"cover.jpg"}) {
+		t.Errorf("Extra = %q, want cover.jpg", check.Extra)
+	}
+
+	var out bytes.Buffer
+	PrintCrossCheck(&out, check)
+	for _, want := range []string{"❌ 02.flac: 2 piece(s) failed hash check", "❌ cover.jpg: not in the site's torrent", "DIVERGES: 1 of 2 files"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestCheck_LocalTorrent(t *testing.T) {
+	files := map[string]string{"01.flac": "first track data", "02.flac": "second track data"}
+	site := buildTorrent(t, "RED", files, []string{"01.flac", "02.flac"})
+
+	tests := []struct {
+		name      string
+		local     *torrentfile.MetaInfo
+		wantDiffs []string
+		wantSame  bool
+	}{
+		{"same", buildTorrent(t, "RED", files, []string{"01.flac", "02.flac"}), nil, true},
+		{"other source", buildTorrent(t, "OPS", files, []string{"01.flac", "02.flac"}), nil, false},
+		{"retagged", buildTorrent(t, "RED", map[string]string{"01.flac": "first track data", "02.flac": "second track DATA"}, []string{"01.flac", "02.flac"}),
+			[]string{"2 of 5 pieces differ in content"}, false},
+		{"file added", buildTorrent(t, "RED", map[string]string{"01.flac": "first track data", "02.flac": "second track data", "03.flac": "third"}, []string{"01.flac", "02.flac", "03.flac"}),
+			[]string{"03.flac: only in the local torrent"}, false},
+		{"file dropped", buildTorrent(t, "RED", files, []string{"01.flac"}),
+			[]string{"02.flac: only on the site"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := Check(site, tt.local, "")
+			if err != nil {
+				t.Fatalf("Check error: %v", err)
+			}
+			if check.SameAsLocal != tt.wantSame || !slices.Equal(check.TorrentDiffs, tt.wantDiffs) {
+				t.Errorf("Check = same %v, diffs %q; want %v, %q", check.SameAsLocal, check.TorrentDiffs, tt.wantSame, tt.wantDiffs)
+			}
+			if check.Diverges() != (len(tt.wantDiffs) > 0) {
+				t.Errorf("Diverges() = %v, want %v", check.Diverges(), len(tt.wantDiffs) > 0)
+			}
+		})
+	}
+}
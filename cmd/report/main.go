@@ -0,0 +1,510 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/artwork"
+	"github.com/cehbz/classical-tagger/internal/audiocheck"
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/musicbrainz"
+	"github.com/cehbz/classical-tagger/internal/rulepack"
+	"github.com/cehbz/classical-tagger/internal/scraping"
+	"github.com/cehbz/classical-tagger/internal/storage"
+	"github.com/cehbz/classical-tagger/internal/tagging"
+	"github.com/cehbz/classical-tagger/internal/titlecase"
+	"github.com/cehbz/classical-tagger/internal/validation"
+)
+
+// sourceList collects repeated -source flags.
+type sourceList []string
+
+func (s *sourceList) String() string { return strings.Join(*s, ", ") }
+
+func (s *sourceList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+var (
+	dir          = flag.String("dir", "", "Album directory to report on (required)")
+	rootName     = flag.String("root", "", "Library root from config to resolve a relative -dir against")
+	metadataFile = flag.String("metadata", "", "Metadata JSON the album was tagged from (validation reference and source citations)")
+	originalFile = flag.String("original", "", "Metadata JSON extracted before tagging, to list the files renamed since")
+	format       = flag.String("format", "bbcode", "Output format: bbcode, markdown, or musicbrainz (an HTML page seeding the MusicBrainz release editor, with an edit note suggesting relationships)")
+	outputFile   = flag.String("output", "", "Write the report to this file instead of stdout")
+	audioCheck   = flag.Bool("audio-check", false, "Decode every track in full to find corrupt frames, MD5 mismatches, clipping and DC offset (slow)")
+	sources      sourceList
+)
+
+// Rename records a track file whose path changed.
+type Rename struct {
+	From string
+	To   string
+}
+
+// TrackAudio is the audio check of one track.
+type TrackAudio struct {
+	Path     string
+	Errors   []string // Corrupt frames, MD5 or sample count mismatches, unreadable files
+	Warnings []string // Clipping, DC offset
+}
+
+// AlbumReport is the summary of an album posted alongside a trump.
+type AlbumReport struct {
+	Torrent *domain.Torrent
+	Issues  []domain.ValidationIssue
+	Renames []Rename     // nil when no original metadata was given
+	Audio   []TrackAudio // nil unless the audio was checked
+	Sources []string
+	Artwork []domain.ArtworkCredit // Cover images fetched by extract, with their licensing
+}
+
+func main() {
+	flag.Var(&sources, "source", "Source URL to cite, e.g. a MusicBrainz release (repeatable)")
+	console.AddFlag(flag.CommandLine)
+	flag.Usage = usage
+	exitcode.ParseFlags(flag.CommandLine, os.Args[1:])
+	rules, err := rulepack.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	titlecase.Configure(rules.ProtectedWords)
+
+	if *dir == "" {
+		fmt.Fprintf(os.Stderr, "Error: -dir is required\n\n")
+		usage()
+		os.Exit(1)
+	}
+	root, err := config.LoadRoot(*rootName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -root: %v\n", err)
+		os.Exit(1)
+	}
+	*dir = root.Resolve(*dir)
+	if *format != "bbcode" && *format != "markdown" && *format != "musicbrainz" {
+		fmt.Fprintf(os.Stderr, "Error: -format must be bbcode, markdown or musicbrainz, got %q\n", *format)
+		os.Exit(1)
+	}
+
+	album, err := scraping.ExtractFromDirectory(*dir)
+	if err != nil {
+		exitcode.Fail("Error extracting from directory", err)
+	}
+	torrent := album.ToTorrent(filepath.Base(*dir))
+
+	repo := storage.NewRepository()
+	var reference, original *domain.Torrent
+	if *metadataFile != "" {
+		if reference, err = repo.LoadFromFile(*metadataFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading metadata: %v\n", err)
+			os.Exit(exitcode.Load)
+		}
+	}
+	if *originalFile != "" {
+		if original, err = repo.LoadFromFile(*originalFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading original metadata: %v\n", err)
+			os.Exit(exitcode.Load)
+		}
+	}
+
+	report := BuildReport(torrent, reference, original, sources)
+	report.Issues = append(report.Issues, artwork.Check(nil, *dir)...)
+	if *audioCheck {
+		report.Audio = CheckAudio(*dir, torrent)
+	}
+	var text string
+	switch *format {
+	case "markdown":
+		text = report.Markdown()
+	case "musicbrainz":
+		text = musicbrainz.SeedPage(torrent, report.Sources)
+	default:
+		text = report.BBCode()
+	}
+
+	if *outputFile == "" {
+		fmt.Print(text)
+		return
+	}
+	if err := os.WriteFile(*outputFile, []byte(text), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		os.Exit(1)
+	}
+	console.Resultf(*outputFile, "✓ Report saved to: %s\n", *outputFile)
+}
+
+// BuildReport validates torrent (against reference when given), lists the tracks
+// renamed since original (when given) and gathers the sources to cite: those
+// recorded in either metadata file, then extra.
+func BuildReport(torrent, reference, original *domain.Torrent, extra []string) *AlbumReport {
+	report := &AlbumReport{
+		Torrent: torrent,
+		Issues:  validation.Check(torrent, reference),
+	}
+	if original != nil {
+		report.Renames = findRenames(original, torrent)
+	}
+
+	var cited []string
+	if reference != nil {
+		cited = append(cited, reference.Sources...)
+	}
+	cited = append(cited, torrent.Sources...)
+	cited = append(cited, extra...)
+	for _, s := range cited {
+		if s = strings.TrimSpace(s); s != "" && !slices.Contains(report.Sources, s) {
+			report.Sources = append(report.Sources, s)
+		}
+	}
+
+	var credits []domain.ArtworkCredit
+	if reference != nil {
+		credits = append(credits, reference.Artwork...)
+	}
+	for _, a := range append(credits, torrent.Artwork...) {
+		if !slices.ContainsFunc(report.Artwork, func(b domain.ArtworkCredit) bool { return b.File == a.File }) {
+			report.Artwork = append(report.Artwork, a)
+		}
+	}
+	return report
+}
+
+// artworkLabel names a fetched image in the report, e.g. "Front cover (cover.jpg)".
+func artworkLabel(a domain.ArtworkCredit) string {
+	side := "Front"
+	if a.Side == "back" {
+		side = "Back"
+	}
+	return fmt.Sprintf("%s cover (%s)", side, a.File)
+}
+
+// CheckAudio decodes every track under dir, reporting progress to stderr.
+func CheckAudio(dir string, torrent *domain.Torrent) []TrackAudio {
+	tracks := torrent.Tracks()
+	audio := make([]TrackAudio, 0, len(tracks))
+	for i, track := range tracks {
+		ta := TrackAudio{Path: track.Path}
+		if tagging.IsDSD(track.Path) {
+			ta.Warnings = []string{"DSD audio is not decoded; only FLAC files are checked"}
+			audio = append(audio, ta)
+			continue
+		}
+		console.Infof("Decoding %d/%d: %s\n", i+1, len(tracks), track.Path)
+		res, err := audiocheck.Check(filepath.Join(dir, filepath.FromSlash(track.Path)))
+		if err != nil {
+			ta.Errors = []string{err.Error()}
+		} else {
+			ta.Errors, ta.Warnings = res.Errors(), res.Warnings()
+		}
+		audio = append(audio, ta)
+	}
+	return audio
+}
+
+// audioSummary counts the tracks with audio errors and warnings.
+func (r *AlbumReport) audioSummary() string {
+	var damaged, suspect int
+	for _, a := range r.Audio {
+		if len(a.Errors) > 0 {
+			damaged++
+		} else if len(a.Warnings) > 0 {
+			suspect++
+		}
+	}
+	if damaged+suspect == 0 {
+		return fmt.Sprintf("All %d tracks decoded cleanly", len(r.Audio))
+	}
+	return fmt.Sprintf("%d tracks damaged, %d with warnings, of %d", damaged, suspect, len(r.Audio))
+}
+
+// findRenames pairs tracks by disc and track number and lists those whose path changed.
+func findRenames(original, current *domain.Torrent) []Rename {
+	type key struct{ disc, track int }
+	before := make(map[key]string)
+	for _, t := range original.Tracks() {
+		before[key{t.Disc, t.Track}] = t.Path
+	}
+
+	renames := []Rename{}
+	for _, t := range current.Tracks() {
+		if from, ok := before[key{t.Disc, t.Track}]; ok && from != t.Path {
+			renames = append(renames, Rename{From: from, To: t.Path})
+		}
+	}
+	return renames
+}
+
+// field is one row of the album metadata table.
+type field struct {
+	Name  string
+	Value string
+}
+
+// fields returns the album metadata rows, skipping empty ones.
+func (r *AlbumReport) fields() []field {
+	t := r.Torrent
+	var rows []field
+	add := func(name, value string) {
+		if value != "" {
+			rows = append(rows, field{name, value})
+		}
+	}
+
+	add("Title", t.Title)
+	if t.OriginalYear > 0 {
+		add("Year", fmt.Sprint(t.OriginalYear))
+	}
+	add("Recorded", domain.FormatYears(t.RecordingYears))
+	if e := t.Edition; e != nil {
+		edition := e.Label
+		if e.CatalogNumber != "" {
+			edition += " " + e.CatalogNumber
+		}
+		if e.Year > 0 {
+			edition += fmt.Sprintf(" (%d)", e.Year)
+		}
+		add("Edition", strings.TrimSpace(edition))
+	}
+	add("Composers", strings.Join(r.composers(), ", "))
+	add("Performers", strings.Join(domain.PerformerCredits(r.performers()), "; "))
+	add("Tracks", fmt.Sprint(len(t.Tracks())))
+	return rows
+}
+
+// works lists the album's works with their playing times, naming the composer
+// on multi-composer albums. It is nil when no track's duration is known.
+func (r *AlbumReport) works() []field {
+	multiComposer := len(r.composers()) > 1
+	var rows []field
+	known := false
+	for _, w := range r.Torrent.Works() {
+		name := w.Title
+		if multiComposer && w.Composer != "" {
+			name = w.Composer + ": " + name
+		}
+		if len(w.Tracks) > 1 {
+			name += fmt.Sprintf(" (%d movements)", len(w.Tracks))
+		}
+		length := "?"
+		if d := w.Duration(); d > 0 {
+			length, known = domain.FormatDuration(d), true
+		}
+		rows = append(rows, field{name, length})
+	}
+	if !known {
+		return nil
+	}
+	return rows
+}
+
+// composers lists the album's composers in order of first appearance.
+func (r *AlbumReport) composers() []string {
+	var names []string
+	for _, a := range r.artists() {
+		if a.Role == domain.RoleComposer && !slices.Contains(names, a.Name) {
+			names = append(names, a.Name)
+		}
+	}
+	return names
+}
+
+// performers lists the album's performers in conventional order.
+func (r *AlbumReport) performers() []domain.Artist {
+	var performers []domain.Artist
+	for _, a := range r.artists() {
+		if a.Role.IsPerformer() {
+			performers = append(performers, a)
+		}
+	}
+	return domain.OrderArtists(performers)
+}
+
+// artists returns the album artists followed by every track's artists.
+func (r *AlbumReport) artists() []domain.Artist {
+	artists := slices.Clone(r.Torrent.AlbumArtist)
+	for _, t := range r.Torrent.Tracks() {
+		artists = append(artists, t.Artists...)
+	}
+	return artists
+}
+
+// summary counts the validation issues by level.
+func (r *AlbumReport) summary() string {
+	var errs, warnings, infos int
+	for _, issue := range r.Issues {
+		switch issue.Level {
+		case domain.LevelError:
+			errs++
+		case domain.LevelWarning:
+			warnings++
+		default:
+			infos++
+		}
+	}
+	if errs+warnings+infos == 0 {
+		return "Passed all checks"
+	}
+	return fmt.Sprintf("%d errors, %d warnings, %d notes", errs, warnings, infos)
+}
+
+// BBCode renders the report for site forums.
+func (r *AlbumReport) BBCode() string {
+	var b strings.Builder
+
+	b.WriteString("[size=3][b]Album[/b][/size]\n")
+	for _, f := range r.fields() {
+		fmt.Fprintf(&b, "[b]%s:[/b] %s\n", f.Name, f.Value)
+	}
+
+	if works := r.works(); works != nil {
+		b.WriteString("\n[size=3][b]Works[/b][/size]\n")
+		for _, w := range works {
+			fmt.Fprintf(&b, "[*]%s – %s\n", w.Name, w.Value)
+		}
+	}
+
+	b.WriteString("\n[size=3][b]Validation[/b][/size]\n")
+	b.WriteString(r.summary() + "\n")
+	for _, issue := range r.Issues {
+		fmt.Fprintf(&b, "[*]%s\n", issue)
+	}
+
+	if r.Audio != nil {
+		b.WriteString("\n[size=3][b]Audio Check[/b][/size]\n")
+		b.WriteString(r.audioSummary() + "\n")
+		for _, a := range r.Audio {
+			for _, e := range a.Errors {
+				fmt.Fprintf(&b, "[*][ERROR] %s: %s\n", a.Path, e)
+			}
+			for _, w := range a.Warnings {
+				fmt.Fprintf(&b, "[*][WARNING] %s: %s\n", a.Path, w)
+			}
+		}
+	}
+
+	if r.Renames != nil {
+		b.WriteString("\n[size=3][b]Renamed Files[/b][/size]\n")
+		if len(r.Renames) == 0 {
+			b.WriteString("No files renamed\n")
+		}
+		for _, rn := range r.Renames {
+			fmt.Fprintf(&b, "[*]%s → %s\n", rn.From, rn.To)
+		}
+	}
+
+	if len(r.Sources) > 0 {
+		b.WriteString("\n[size=3][b]Sources[/b][/size]\n")
+		for _, s := range r.Sources {
+			fmt.Fprintf(&b, "[*][url]%s[/url]\n", s)
+		}
+	}
+
+	if len(r.Artwork) > 0 {
+		b.WriteString("\n[size=3][b]Artwork[/b][/size]\n")
+		for _, a := range r.Artwork {
+			fmt.Fprintf(&b, "[*]%s: [url=%s]%s[/url], %s\n", artworkLabel(a), a.Page, a.Source, a.License)
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the report for Markdown forums and issue trackers.
+func (r *AlbumReport) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("## Album\n\n| Field | Value |\n| --- | --- |\n")
+	for _, f := range r.fields() {
+		fmt.Fprintf(&b, "| %s | %s |\n", f.Name, markdownCell(f.Value))
+	}
+
+	if works := r.works(); works != nil {
+		b.WriteString("\n## Works\n\n| Work | Length |\n| --- | --- |\n")
+		for _, w := range works {
+			fmt.Fprintf(&b, "| %s | %s |\n", markdownCell(w.Name), w.Value)
+		}
+	}
+
+	b.WriteString("\n## Validation\n\n")
+	b.WriteString(r.summary() + "\n")
+	if len(r.Issues) > 0 {
+		b.WriteString("\n")
+	}
+	for _, issue := range r.Issues {
+		fmt.Fprintf(&b, "- %s\n", issue)
+	}
+
+	if r.Audio != nil {
+		b.WriteString("\n## Audio Check\n\n")
+		b.WriteString(r.audioSummary() + "\n")
+		var lines []string
+		for _, a := range r.Audio {
+			for _, e := range a.Errors {
+				lines = append(lines, fmt.Sprintf("- [ERROR] `%s`: %s\n", a.Path, e))
+			}
+			for _, w := range a.Warnings {
+				lines = append(lines, fmt.Sprintf("- [WARNING] `%s`: %s\n", a.Path, w))
+			}
+		}
+		if len(lines) > 0 {
+			b.WriteString("\n" + strings.Join(lines, ""))
+		}
+	}
+
+	if r.Renames != nil {
+		b.WriteString("\n## Renamed Files\n\n")
+		if len(r.Renames) == 0 {
+			b.WriteString("No files renamed\n")
+		}
+		for _, rn := range r.Renames {
+			fmt.Fprintf(&b, "- `%s` → `%s`\n", rn.From, rn.To)
+		}
+	}
+
+	if len(r.Sources) > 0 {
+		b.WriteString("\n## Sources\n\n")
+		for _, s := range r.Sources {
+			fmt.Fprintf(&b, "- <%s>\n", s)
+		}
+	}
+
+	if len(r.Artwork) > 0 {
+		b.WriteString("\n## Artwork\n\n")
+		for _, a := range r.Artwork {
+			fmt.Fprintf(&b, "- %s: [%s](%s), %s\n", artworkLabel(a), a.Source, a.Page, a.License)
+		}
+	}
+	return b.String()
+}
+
+// markdownCell escapes pipes, which would otherwise end the table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: report -dir DIRECTORY [options]\n\n")
+	fmt.Fprintf(os.Stderr, "Summarize an album for a forum post or moderation thread: metadata,\n")
+	fmt.Fprintf(os.Stderr, "works with playing times, validation results, optional audio check, files renamed\n")
+	fmt.Fprintf(os.Stderr, "and sources, as BBCode or Markdown. -format musicbrainz instead prepares the album\n")
+	fmt.Fprintf(os.Stderr, "for submission to MusicBrainz.\n\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nExamples:\n")
+	fmt.Fprintf(os.Stderr, "  # Report on a tagged album, citing the Discogs release it was tagged from:\n")
+	fmt.Fprintf(os.Stderr, "  report -dir \"/music/Bach - Goldberg Variations [FLAC]\" -metadata goldberg_discogs.json \\\n")
+	fmt.Fprintf(os.Stderr, "    -original goldberg.json -source https://musicbrainz.org/release/...\n\n")
+	fmt.Fprintf(os.Stderr, "  # Decode every track first to catch damaged files before building a torrent:\n")
+	fmt.Fprintf(os.Stderr, "  report -dir \"/music/Bach - Goldberg Variations [FLAC]\" -audio-check -format markdown\n\n")
+	fmt.Fprintf(os.Stderr, "  # Send the corrections back upstream: open the page and submit its form to MusicBrainz:\n")
+	fmt.Fprintf(os.Stderr, "  report -dir \"/music/Bach - Goldberg Variations [FLAC]\" -metadata goldberg_discogs.json \\\n")
+	fmt.Fprintf(os.Stderr, "    -format musicbrainz -output goldberg_musicbrainz.html\n")
+	exitcode.PrintCodes(os.Stderr)
+}
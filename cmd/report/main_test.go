@@ -0,0 +1,176 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func reportTorrent(paths ...string) *domain.Torrent {
+	t := &domain.Torrent{
+		RootPath:     "Bach - Goldberg Variations (Glenn Gould) - 1982 [FLAC]",
+		Title:        "Goldberg Variations",
+		OriginalYear: 1982,
+		Edition:      &domain.Edition{Label: "Sony Classical", CatalogNumber: "SMK 52594", Year: 1992},
+	}
+	for i, p := range paths {
+		t.Files = append(t.Files, &domain.Track{
+			File:  domain.File{Path: p},
+			Disc:  1,
+			Track: i + 1,
+			Title: "Aria",
+			Artists: []domain.Artist{
+				{Name: "Johann Sebastian Bach", Role: domain.RoleComposer},
+				{Name: "Glenn Gould", Role: domain.RoleSoloist, Instrument: "piano"},
+			},
+		})
+	}
+	return t
+}
+
+func TestBuildReport(t *testing.T) {
+	current := reportTorrent("01 - Aria.flac", "02 - Variatio 1.flac")
+	original := reportTorrent("01 aria.flac", "02 - Variatio 1.flac")
+	reference := reportTorrent("01 - Aria.flac", "02 - Variatio 1.flac")
+	reference.Sources = []string{"https://www.discogs.com/release/123"}
+	reference.Artwork = []domain.ArtworkCredit{{File: "cover.jpg", Side: "front", Source: "Discogs"}}
+	current.Artwork = []domain.ArtworkCredit{{File: "cover.jpg", Side: "front", Source: "Cover Art Archive"}}
+
+	report := BuildReport(current, reference, original, []string{"https://musicbrainz.org/release/abc", "https://www.discogs.com/release/123"})
+
+	wantRenames := []Rename{{From: "01 aria.flac", To: "01 - Aria.flac"}}
+	if !slices.Equal(report.Renames, wantRenames) {
+		t.Errorf("Renames = %v, want %v", report.Renames, wantRenames)
+	}
+	wantSources := []string{"https://www.discogs.com/release/123", "https://musicbrainz.org/release/abc"}
+	if !slices.Equal(report.Sources, wantSources) {
+		t.Errorf("Sources = %v, want %v", report.Sources, wantSources)
+	}
+	if len(report.Artwork) != 1 || report.Artwork[0].Source != "Discogs" {
+		t.Errorf("Artwork = %+v, want the reference's credit", report.Artwork)
+	}
+
+	current.Artwork = nil
+	if r := BuildReport(current, nil, nil, nil); r.Renames != nil || r.Sources != nil || r.Artwork != nil {
+		t.Errorf("report without original or sources = %+v", r)
+	}
+}
+
+func TestAlbumReport_Render(t *testing.T) {
+	report := &AlbumReport{
+		Torrent: reportTorrent("01 - Aria.flac"),
+		Issues: []domain.ValidationIssue{
+			{Level: domain.LevelWarning, Track: 1, Rule: "2.3.11.1", Message: "Not Title Case"},
+		},
+		Renames: []Rename{{From: "01 aria.flac", To: "01 - Aria.flac"}},
+		Sources: []string{"https://www.discogs.com/release/123"},
+		Artwork: []domain.ArtworkCredit{{
+			File: "cover.jpg", Side: "front", Source: "Cover Art Archive",
+			Page: "https://musicbrainz.org/release/abc/cover-art", License: "copyright remains with the rights holders",
+		}},
+	}
+
+	bbcode := report.BBCode()
+	for _, want := range []string{
+		"[b]Title:[/b] Goldberg Variations\n",
+		"[b]Edition:[/b] Sony Classical SMK 52594 (1992)\n",
+		"[b]Composers:[/b] Johann Sebastian Bach\n",
+		"[b]Performers:[/b] Glenn Gould (piano)\n",
+		"0 errors, 1 warnings, 0 notes\n",
+		"[*][WARNING] Track 1: 2.3.11.1 - Not Title Case\n",
+		"[*]01 aria.flac → 01 - Aria.flac\n",
+		"[*][url]https://www.discogs.com/release/123[/url]\n",
+		"[*]Front cover (cover.jpg): [url=https://musicbrainz.org/release/abc/cover-art]Cover Art Archive[/url], copyright remains with the rights holders\n",
+	} {
+		if !strings.Contains(bbcode, want) {
+			t.Errorf("BBCode() missing %q:\n%s", want, bbcode)
+		}
+	}
+
+	markdown := report.Markdown()
+	for _, want := range []string{
+		"| Title | Goldberg Variations |\n",
+		"| Tracks | 1 |\n",
+		"- [WARNING] Track 1: 2.3.11.1 - Not Title Case\n",
+		"- `01 aria.flac` → `01 - Aria.flac`\n",
+		"- <https://www.discogs.com/release/123>\n",
+		"- Front cover (cover.jpg): [Cover Art Archive](https://musicbrainz.org/release/abc/cover-art), copyright remains with the rights holders\n",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, markdown)
+		}
+	}
+
+	report.Renames = []Rename{}
+	if !strings.Contains(report.BBCode(), "No files renamed") {
+		t.Error("BBCode() should say when nothing was renamed")
+	}
+	if strings.Contains(report.BBCode(), "Audio Check") {
+		t.Error("BBCode() should omit the audio check unless it ran")
+	}
+}
+
+func TestAlbumReport_Works(t *testing.T) {
+	torrent := reportTorrent("01 - Aria.flac", "02 - Variatio 1.flac")
+	if (&AlbumReport{Torrent: torrent}).works() != nil {
+		t.Fatal("works() without durations should be nil")
+	}
+	for i, track := range torrent.Tracks() {
+		track.Title = []string{"Goldberg Variations, BWV 988: Aria", "Goldberg Variations, BWV 988: Variatio 1"}[i]
+		track.Duration = []time.Duration{3*time.Minute + 5*time.Second, 45 * time.Second}[i]
+	}
+
+	report := &AlbumReport{Torrent: torrent}
+	if bbcode := report.BBCode(); !strings.Contains(bbcode, "[*]Goldberg Variations, BWV 988 (2 movements) – 3:50\n") {
+		t.Errorf("BBCode() missing the work's length:\n%s", bbcode)
+	}
+	if markdown := report.Markdown(); !strings.Contains(markdown, "| Goldberg Variations, BWV 988 (2 movements) | 3:50 |\n") {
+		t.Errorf("Markdown() missing the work's length:\n%s", markdown)
+	}
+}
+
+func TestAlbumReport_Audio(t *testing.T) {
+	report := &AlbumReport{
+		Torrent: reportTorrent("01 - Aria.flac", "02 - Variatio 1.flac"),
+		Audio: []TrackAudio{
+			{Path: "01 - Aria.flac"},
+			{Path: "02 - Variatio 1.flac", Errors: []string{"audio MD5 does not match STREAMINFO"}, Warnings: []string{"channel 1 clips 2 times"}},
+		},
+	}
+
+	bbcode := report.BBCode()
+	for _, want := range []string{
+		"1 tracks damaged, 0 with warnings, of 2\n",
+		"[*][ERROR] 02 - Variatio 1.flac: audio MD5 does not match STREAMINFO\n",
+		"[*][WARNING] 02 - Variatio 1.flac: channel 1 clips 2 times\n",
+	} {
+		if !strings.Contains(bbcode, want) {
+			t.Errorf("BBCode() missing %q:\n%s", want, bbcode)
+		}
+	}
+	if markdown := report.Markdown(); !strings.Contains(markdown, "- [ERROR] `02 - Variatio 1.flac`: audio MD5 does not match STREAMINFO\n") {
+		t.Errorf("Markdown() missing the audio error:\n%s", markdown)
+	}
+
+	report.Audio = report.Audio[:1]
+	if !strings.Contains(report.Markdown(), "All 1 tracks decoded cleanly") {
+		t.Errorf("Markdown() should summarize a clean check:\n%s", report.Markdown())
+	}
+}
+
+func TestCheckAudio_Unreadable(t *testing.T) {
+	audio := CheckAudio(t.TempDir(), reportTorrent("01 - Aria.flac"))
+	if len(audio) != 1 || len(audio[0].Errors) != 1 {
+		t.Errorf("CheckAudio() = %+v, want the missing file reported as an error", audio)
+	}
+}
+
+func TestCheckAudio_SkipsDSD(t *testing.T) {
+	audio := CheckAudio(t.TempDir(), reportTorrent("01 - Aria.dsf"))
+	if len(audio) != 1 || len(audio[0].Errors) != 0 || len(audio[0].Warnings) != 1 {
+		t.Errorf("CheckAudio() = %+v, want the DSD file skipped with a warning", audio)
+	}
+}
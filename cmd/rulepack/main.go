@@ -0,0 +1,252 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/rulepack"
+)
+
+func main() {
+	flag.Usage = usage
+	exitcode.ParseFlags(flag.CommandLine, os.Args[1:])
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(exitcode.Failure)
+	}
+
+	args := flag.Args()[1:]
+	switch flag.Arg(0) {
+	case "export":
+		os.Exit(runExport(args))
+	case "import":
+		os.Exit(runImport(args))
+	case "list":
+		os.Exit(runList(args))
+	case "show":
+		os.Exit(runShow(args))
+	case "remove":
+		os.Exit(runRemove(args))
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(exitcode.Failure)
+	}
+}
+
+// runExport writes the user's own rules as a pack.
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	name := flags.String("name", "my-rules", "Pack name: lowercase letters, digits, '.', '-' or '_'")
+	description := flags.String("description", "", "What the pack is for, shown to those who import it")
+	output := flags.String("output", "", "File to write the pack to (default: standard output)")
+	console.AddFlag(flags)
+	exitcode.ParseFlags(flags, args)
+
+	pack, err := rulepack.Export(*name, *description)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	data, err := pack.Marshal()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if *output == "" {
+		os.Stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	console.Infof("✓ Exported %s to %s\n", summary(pack), *output)
+	return 0
+}
+
+// runImport imports the pack in a file, then reports the rules it loses to
+// the user's own or to other packs.
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	name := flags.String("name", "", "Import under this name instead of the pack's own")
+	replace := flags.Bool("replace", false, "Replace an imported pack of the same name")
+	console.AddFlag(flags)
+	exitcode.ParseFlags(flags, args)
+	if flags.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Error: import needs one pack file\n")
+		return exitcode.Failure
+	}
+
+	pack, err := rulepack.ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitcode.Load
+	}
+	if *name != "" {
+		pack.Name = *name
+	}
+	if err := rulepack.Install(pack, *replace); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if !*replace {
+			fmt.Fprintf(os.Stderr, "Hint: pass -replace to update it, or -name to import it alongside\n")
+		}
+		return 1
+	}
+	console.Resultf(pack.Name, "✓ Imported %s\n", summary(pack))
+
+	rules, err := rulepack.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	printConflicts(os.Stderr, rules.Conflicts, pack.Name)
+	return 0
+}
+
+// runList lists the imported packs and the rules they lose in the merge.
+func runList(args []string) int {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	console.AddFlag(flags)
+	exitcode.ParseFlags(flags, args)
+
+	packs, err := rulepack.Installed()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if len(packs) == 0 {
+		console.Infof("No rule packs imported (%s)\n", rulepack.Dir())
+		return 0
+	}
+	for _, pack := range packs {
+		console.Resultf(pack.Name, "%s\n", summary(pack))
+		if pack.Description != "" {
+			console.Infof("   %s\n", pack.Description)
+		}
+	}
+
+	rules, err := rulepack.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	printConflicts(os.Stderr, rules.Conflicts, "")
+	return 0
+}
+
+// runShow prints every rule in effect with where it came from.
+func runShow(args []string) int {
+	flags := flag.NewFlagSet("show", flag.ExitOnError)
+	from := flags.String("from", "", "Only rules from this pack (\"own\": from the config file and remembered roles)")
+	console.AddFlag(flags)
+	exitcode.ParseFlags(flags, args)
+
+	rules, err := rulepack.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	printRules(os.Stdout, rules, *from)
+	return 0
+}
+
+// runRemove removes imported packs.
+func runRemove(args []string) int {
+	flags := flag.NewFlagSet("remove", flag.ExitOnError)
+	console.AddFlag(flags)
+	exitcode.ParseFlags(flags, args)
+	if flags.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Error: remove needs a pack name\n")
+		return exitcode.Failure
+	}
+
+	code := 0
+	for _, name := range flags.Args() {
+		if err := rulepack.Remove(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			code = 1
+			continue
+		}
+		console.Resultf(name, "✓ Removed %s\n", name)
+	}
+	return code
+}
+
+// summary names a pack and counts its rules, e.g. "baroque: 12 rules (2
+// protected words, 4 artist aliases, 6 artist roles)".
+func summary(pack *rulepack.Pack) string {
+	return fmt.Sprintf("%s: %d rules (%d protected words, %d artist aliases, %d artist roles)",
+		pack.Name, pack.Size(), len(pack.ProtectedWords), len(pack.ArtistAliases), len(pack.ArtistRoles))
+}
+
+// printRules writes the rules in effect, one per line with their origin,
+// limited to those from the pack named from unless it is "".
+func printRules(w io.Writer, rules *rulepack.Rules, from string) {
+	var lines []string
+	add := func(key rulepack.Key, value string) {
+		origin := rules.Origins[key]
+		if from != "" && origin != from {
+			return
+		}
+		lines = append(lines, fmt.Sprintf("%-15s %s  [%s]", key.Kind, value, origin))
+	}
+	for _, word := range rules.ProtectedWords {
+		add(rulepack.Key{Kind: rulepack.KindProtectedWord, Name: strings.ToLower(word)}, word)
+	}
+	for variant, canonical := range rules.ArtistAliases {
+		add(rulepack.Key{Kind: rulepack.KindArtistAlias, Name: variant}, fmt.Sprintf("%s → %s", variant, canonical))
+	}
+	for artist, role := range rules.ArtistRoles {
+		add(rulepack.Key{Kind: rulepack.KindArtistRole, Name: artist}, fmt.Sprintf("%s: %s", artist, role))
+	}
+	slices.Sort(lines)
+	for _, line := range lines {
+		fmt.Fprintln(w, line)
+	}
+	if len(lines) == 0 {
+		fmt.Fprintln(w, "No rules")
+	}
+}
+
+// printConflicts writes the pack rules dropped in the merge, limited to those
+// of the pack named pack unless it is "".
+func printConflicts(w io.Writer, conflicts []rulepack.Conflict, pack string) {
+	var shown []rulepack.Conflict
+	for _, c := range conflicts {
+		if pack == "" || c.Pack == pack {
+			shown = append(shown, c)
+		}
+	}
+	if len(shown) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n⚠️  %d rule(s) overridden by your own rules or an earlier pack:\n", len(shown))
+	for _, c := range shown {
+		fmt.Fprintf(w, "  %s\n", c)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: rulepack <command> [options]\n\n")
+	fmt.Fprintf(os.Stderr, "Share protected words, artist aliases and artist roles as YAML rule packs.\n\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "  export [-name NAME] [-description TEXT] [-output FILE]\n")
+	fmt.Fprintf(os.Stderr, "        Write your own rules (config file and remembered roles) as a pack\n")
+	fmt.Fprintf(os.Stderr, "  import [-name NAME] [-replace] FILE\n")
+	fmt.Fprintf(os.Stderr, "        Import a pack; your own rules, then packs in name order, win conflicts\n")
+	fmt.Fprintf(os.Stderr, "  list  List the imported packs and the rules they lose to others\n")
+	fmt.Fprintf(os.Stderr, "  show [-from PACK]\n")
+	fmt.Fprintf(os.Stderr, "        Print every rule in effect with the pack it came from\n")
+	fmt.Fprintf(os.Stderr, "  remove NAME...\n")
+	fmt.Fprintf(os.Stderr, "        Remove imported packs\n")
+	fmt.Fprintf(os.Stderr, "Each command takes -quiet, printing only pack names (list, import, remove) for scripts\n")
+	fmt.Fprintf(os.Stderr, "\nExamples:\n")
+	fmt.Fprintf(os.Stderr, "  rulepack export -name baroque -output baroque.yaml\n")
+	fmt.Fprintf(os.Stderr, "  rulepack import baroque.yaml\n")
+	fmt.Fprintf(os.Stderr, "  rulepack show -from baroque\n")
+	exitcode.PrintCodes(os.Stderr)
+}
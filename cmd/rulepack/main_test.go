@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/rulepack"
+)
+
+func TestPrintRules(t *testing.T) {
+	rules := rulepack.Merge(
+		&rulepack.Pack{ProtectedWords: []string{"NHK"}},
+		map[string]domain.Role{"Martha Argerich": domain.RoleSoloist},
+		[]*rulepack.Pack{{Name: "baroque", ArtistAliases: map[string]string{"Gardiner": "John Eliot Gardiner"}}},
+	)
+
+	var all bytes.Buffer
+	printRules(&all, rules, "")
+	want := []string{
+		"artist alias    Gardiner → John Eliot Gardiner  [baroque]",
+		"artist role     Martha Argerich: soloist  [own]",
+		"protected word  NHK  [own]",
+	}
+	if got := strings.Split(strings.TrimSpace(all.String()), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("printRules() =\n%s\nwant\n%s", all.String(), strings.Join(want, "\n"))
+	}
+
+	var fromPack bytes.Buffer
+	printRules(&fromPack, rules, "baroque")
+	if got := strings.TrimSpace(fromPack.String()); got != want[0] {
+		t.Errorf("printRules(baroque) = %q, want %q", got, want[0])
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/storage"
+)
+
+func main() {
+	flag.Usage = usage
+	exitcode.ParseFlags(flag.CommandLine, os.Args[1:])
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(exitcode.Failure)
+	}
+
+	switch flag.Arg(0) {
+	case "migrate":
+		os.Exit(runMigrate(flag.Args()[1:]))
+	case "disc":
+		os.Exit(runDisc(flag.Args()[1:]))
+	case "assemble":
+		os.Exit(runAssemble(flag.Args()[1:]))
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(exitcode.Failure)
+	}
+}
+
+// runMigrate upgrades metadata JSON files, given directly or found under directories.
+func runMigrate(args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "Report files that need migrating without rewriting them")
+	console.AddFlag(flags)
+	exitcode.ParseFlags(flags, args)
+
+	if flags.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "Error: migrate needs at least one file or directory\n")
+		return exitcode.Failure
+	}
+
+	paths, err := collectJSONFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitcode.Load
+	}
+
+	repo := storage.NewRepository()
+	var migrated, current, failed int
+	for _, path := range paths {
+		from, err := repo.MigrateFile(path, *dryRun)
+		switch {
+		case errors.Is(err, storage.ErrNotMetadata):
+			// Unrelated JSON found while walking a directory
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "❌ %s: %v\n", path, err)
+			failed++
+		case from == domain.SchemaVersion:
+			current++
+		default:
+			verb := "Migrated"
+			if *dryRun {
+				verb = "Would migrate"
+			}
+			console.Resultf(path, "✅ %s %s (schema %d → %d)\n", verb, path, from, domain.SchemaVersion)
+			migrated++
+		}
+	}
+
+	console.Infof("\n%d migrated, %d already current, %d failed\n", migrated, current, failed)
+	if failed > 0 {
+		return exitcode.Load
+	}
+	return exitcode.OK
+}
+
+// runDisc writes a disc metadata file for one disc of a set's metadata, so the
+// disc can be corrected on its own.
+func runDisc(args []string) int {
+	flags := flag.NewFlagSet("disc", flag.ExitOnError)
+	disc := flags.Int("disc", 0, "Disc to write a metadata file for (required)")
+	output := flags.String("o", "", "Where to write it (default: FILE with _discN before .json)")
+	console.AddFlag(flags)
+	exitcode.ParseFlags(flags, args)
+
+	if flags.NArg() != 1 || *disc < 1 {
+		fmt.Fprintf(os.Stderr, "Error: disc needs -disc N and the set's metadata file\n")
+		return exitcode.Failure
+	}
+	path := flags.Arg(0)
+	if *output == "" {
+		*output = fmt.Sprintf("%s_disc%d.json", strings.TrimSuffix(path, filepath.Ext(path)), *disc)
+	}
+
+	repo := storage.NewRepository()
+	set, err := repo.LoadFromFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+		return exitcode.Load
+	}
+	part, err := set.DiscMetadata(*disc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+		return exitcode.Failure
+	}
+	if err := repo.SaveToFile(part, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitcode.Failure
+	}
+	console.Resultf(*output, "✅ Disc %d (%d tracks) written to %s\n", *disc, len(part.Tracks()), *output)
+	return exitcode.OK
+}
+
+// runAssemble folds disc metadata files into the set's, writing the whole set
+// to one file.
+func runAssemble(args []string) int {
+	flags := flag.NewFlagSet("assemble", flag.ExitOnError)
+	output := flags.String("o", "", "Where to write the assembled set (required; may be the set's file)")
+	console.AddFlag(flags)
+	exitcode.ParseFlags(flags, args)
+
+	if flags.NArg() < 2 || *output == "" {
+		fmt.Fprintf(os.Stderr, "Error: assemble needs -o FILE, the set's metadata file and at least one disc metadata file\n")
+		return exitcode.Failure
+	}
+
+	repo := storage.NewRepository()
+	set, err := repo.LoadSet(flags.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitcode.Load
+	}
+	if err := repo.SaveToFile(set, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitcode.Failure
+	}
+	console.Resultf(*output, "✅ %d discs (%d tracks) written to %s\n", discCount(set), len(set.Tracks()), *output)
+	return exitcode.OK
+}
+
+// discCount counts the distinct discs of the torrent's tracks.
+func discCount(torrent *domain.Torrent) int {
+	discs := make(map[int]bool)
+	for _, track := range torrent.Tracks() {
+		discs[track.Disc] = true
+	}
+	return len(discs)
+}
+
+// collectJSONFiles expands directories into the .json files beneath them.
+func collectJSONFiles(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.EqualFold(filepath.Ext(path), ".json") {
+				paths = append(paths, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: storage migrate [-dry-run] FILE_OR_DIRECTORY...\n")
+	fmt.Fprintf(os.Stderr, "       storage disc -disc N [-o FILE] SET_FILE\n")
+	fmt.Fprintf(os.Stderr, "       storage assemble -o FILE SET_FILE DISC_FILE...\n\n")
+	fmt.Fprintf(os.Stderr, "Upgrade saved metadata JSON to the current schema version (%d).\n", domain.SchemaVersion)
+	fmt.Fprintf(os.Stderr, "Directories are searched recursively for .json files; other JSON is left alone.\n")
+	fmt.Fprintf(os.Stderr, "Older files are also migrated in memory whenever they are loaded, so this is\n")
+	fmt.Fprintf(os.Stderr, "only needed to rewrite them on disk.\n\n")
+	fmt.Fprintf(os.Stderr, "Migrate options:\n")
+	fmt.Fprintf(os.Stderr, "  -dry-run  Report files that need migrating without rewriting them\n\n")
+	fmt.Fprintf(os.Stderr, "disc writes a metadata file covering one disc of a set, titled with the disc's\n")
+	fmt.Fprintf(os.Stderr, "subtitle and carrying its edition, to correct on its own. tag and upload accept\n")
+	fmt.Fprintf(os.Stderr, "it after the set's file (-metadata set.json,set_disc3.json); assemble folds disc\n")
+	fmt.Fprintf(os.Stderr, "files back into one file for the whole set.\n\n")
+	fmt.Fprintf(os.Stderr, "Disc options:\n")
+	fmt.Fprintf(os.Stderr, "  -disc N   Disc to write a metadata file for\n")
+	fmt.Fprintf(os.Stderr, "  -o FILE   Where to write it (default: SET_FILE with _discN before .json)\n\n")
+	fmt.Fprintf(os.Stderr, "Assemble options:\n")
+	fmt.Fprintf(os.Stderr, "  -o FILE   Where to write the assembled set; may be SET_FILE\n")
+	fmt.Fprintf(os.Stderr, "\nEvery command takes -quiet to print only the files written (or, with\n")
+	fmt.Fprintf(os.Stderr, "-dry-run, to be written), one per line, for scripts.\n")
+	exitcode.PrintCodes(os.Stderr)
+}
@@ -7,116 +7,206 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/cehbz/classical-tagger/internal/artwork"
+	"github.com/cehbz/classical-tagger/internal/checksum"
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/console"
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/rulepack"
+	"github.com/cehbz/classical-tagger/internal/state"
 	"github.com/cehbz/classical-tagger/internal/storage"
 	"github.com/cehbz/classical-tagger/internal/tagging"
+	"github.com/cehbz/classical-tagger/internal/titlecase"
 	"github.com/cehbz/classical-tagger/internal/validation"
 )
 
 var (
-	metadataFile = flag.String("metadata", "", "Path to metadata JSON file (required)")
+	metadataFile = flag.String("metadata", "", "Path to metadata JSON file (required); a comma-separated list assembles a set from its metadata file and disc metadata files")
 	targetDir    = flag.String("dir", ".", "Target directory containing FLAC files")
+	rootName     = flag.String("root", "", "Library root from config: resolves a relative -dir and supplies its output root, templates and validation profile")
+	profileName  = flag.String("profile", "", "Validation profile: default (errors block), strict (warnings block too) or lenient (report only) (defaults to the root's, or default)")
 	outputDir    = flag.String("output", "", "Output directory for tagged files (defaults to <targetDir>_tagged)")
 	dryRun       = flag.Bool("dry-run", false, "Show what would be done without actually doing it")
-	force        = flag.Bool("force", false, "Skip validation and apply tags anyway")
+	noComposer   = flag.Bool("allow-missing-composer", false, "Treat tracks without a composer as warnings, not errors (crossover or recital discs); also set by extract in the metadata JSON")
+	dirTitle     = flag.String("dir-title", "", "Title variant to use for the output directory name (defaults to the primary title)")
+	tagTitle     = flag.String("tag-title", "", "Title variant to write to ALBUM tags (defaults to the primary title)")
+	dirTemplate  = flag.String("dir-template", "", "Output directory name template, e.g. \"{composer_sort} - {title} [{format}]\" (defaults to naming.directory_template in config)")
+	namingPolicy = flag.String("filename-policy", "", "Track filename conventions: redacted (\"01 - Title.flac\", composer named on multi-composer albums) or plain (\"1 - Title.flac\") (defaults to naming.filename_policy in config, or redacted)")
+	retain       = flag.String("retain", "", "Comma-separated PATTERN=keep|drop|overwrite rules for tags already in the files, e.g. \"ENCODER=keep,REPLAYGAIN_*=drop\"; tried before tagging.retention in config, first match wins")
+	checksums    = flag.String("checksums", "", "Comma-separated checksum files to write into the output: sha256 (SHA256SUMS) and/or ffp (ffp.txt FLAC fingerprints) (defaults to tagging.checksums in config)")
+	allow        domain.Overrides
+	discTemplate = flag.String("disc-template", "", "Disc subdirectory name template for multi-disc albums, e.g. \"CD{disc}\" or \"Disc {disc} - {subtitle}\" (defaults to naming.disc_template in config, or \"Disc {disc}\")")
 )
 
 func main() {
-	flag.Parse()
+	flag.Var(&allow, "allow", "Proceed past one known problem, leaving every other check in force: unmatched-tracks (tag the files that match), warnings (warnings don't block under -profile strict) or missing-year (repeatable or comma-separated)")
+	console.AddFlag(flag.CommandLine)
+	flag.Usage = usage
+	exitcode.ParseFlags(flag.CommandLine, os.Args[1:])
+	rules, err := rulepack.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	titlecase.Configure(rules.ProtectedWords)
 
 	if *metadataFile == "" {
-		fmt.Fprintf(os.Stderr, "Error: -metadata flag is required\n")
-		fmt.Fprintf(os.Stderr, "\nUsage: tag -metadata FILE [options]\n\n")
-		flag.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "Error: -metadata flag is required\n\n")
+		usage()
+		os.Exit(exitcode.Failure)
+	}
+
+	root, err := config.LoadRoot(*rootName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -root: %v\n", err)
+		os.Exit(1)
+	}
+	*targetDir = root.Resolve(*targetDir)
+
+	if *profileName == "" {
+		*profileName = root.Validation
+	}
+	profile, err := domain.ParseValidationProfile(*profileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -profile: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Load metadata JSON
-	fmt.Printf("Loading metadata from %s...\n", *metadataFile)
+	console.Infof("Loading metadata from %s...\n", *metadataFile)
 	torrent, err := LoadMetadataJSON(*metadataFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading metadata: %v\n", err)
+		os.Exit(exitcode.Load)
+	}
+
+	console.Infof("✓ Loaded torrent: %s (%d)\n", torrent.Title, torrent.OriginalYear)
+	if len(torrent.AlternateTitles) > 0 {
+		console.Infof("  Alternate titles: %s\n", strings.Join(torrent.AlternateTitles, " | "))
+	}
+	console.Infof("  Tracks: %d\n\n", len(torrent.Tracks()))
+
+	// Select title variants: one drives the directory name, the other the tags.
+	// The variants share the loaded torrent's tracks, so junk removals recorded
+	// while tagging reach the metadata files through it.
+	manifest := torrent
+	dirTorrent, err := torrent.WithTitle(*dirTitle)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -dir-title: %v\n", err)
+		os.Exit(1)
+	}
+	torrent, err = torrent.WithTitle(*tagTitle)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -tag-title: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("✓ Loaded torrent: %s (%d)\n", torrent.Title, torrent.OriginalYear)
-	fmt.Printf("  Tracks: %d\n\n", len(torrent.Tracks()))
+	if *noComposer {
+		torrent.AllowMissingComposer = true
+	}
 
-	// Validate metadata unless --force
-	if !*force {
-		fmt.Println("Validating metadata...")
-		issues := validation.Check(torrent, nil)
+	// Validate metadata; -allow lets individual kinds of issue through
+	console.Infoln("Validating metadata...")
+	issues := validation.Check(torrent, nil)
 
-		hasErrors := false
-		for _, issue := range issues {
-			switch issue.Level {
-			case domain.LevelError:
-				hasErrors = true
-				fmt.Printf("❌ %s\n", issue)
-			case domain.LevelWarning:
-				fmt.Printf("⚠️  %s\n", issue)
-			}
+	for _, issue := range issues {
+		switch issue.Level {
+		case domain.LevelError:
+			fmt.Fprintf(os.Stderr, "❌ %s\n", issue)
+		case domain.LevelWarning:
+			fmt.Fprintf(os.Stderr, "⚠️  %s\n", issue)
 		}
+	}
 
-		if hasErrors {
-			fmt.Fprintf(os.Stderr, "\n❌ Metadata has errors. Fix them or use --force to proceed anyway.\n")
-			os.Exit(1)
-		}
+	if blocking := allow.Blocking(profile, issues); len(blocking) > 0 {
+		fmt.Fprintf(os.Stderr, "\n❌ Metadata has %d blocking issues under the %s validation profile. Fix them, or pass -allow warnings or -allow missing-year if they are only those.\n", len(blocking), profile)
+		os.Exit(exitcode.Validation)
+	}
 
-		if len(issues) == 0 {
-			fmt.Println("✓ Metadata is valid")
-		} else {
-			fmt.Println("⚠️  Metadata has warnings but is usable")
-		}
+	switch {
+	case len(issues) == 0:
+		console.Infoln("✓ Metadata is valid")
+	case len(profile.Blocking(issues)) > 0:
+		console.Infof("⚠️  Metadata has issues allowed by -allow %s\n", allow)
+	default:
+		console.Infof("⚠️  Metadata has issues that the %s validation profile allows\n", profile)
 	}
 
-	// Find FLAC files in target directory
-	fmt.Printf("\nScanning directory: %s\n", *targetDir)
-	files, err := FindFLACFiles(*targetDir)
+	// Prevent concurrent runs on the same album from clobbering each other's output
+	lock, err := state.AcquireDir(*targetDir)
+	if err != nil {
+		exitcode.Fail("", err)
+	}
+	defer lock.Release()
+
+	// Find FLAC and DSD files in target directory
+	console.Infof("\nScanning directory: %s\n", *targetDir)
+	files, err := FindAudioFiles(*targetDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error scanning directory: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitcode.Load)
 	}
 
-	fmt.Printf("✓ Found %d FLAC files\n\n", len(files))
+	console.Infof("✓ Found %d audio files\n\n", len(files))
 
 	if len(files) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: No FLAC files found in directory\n")
-		os.Exit(1)
+		os.Exit(exitcode.Load)
 	}
 
 	// Match tracks to files
-	fmt.Println("Matching tracks to files...")
+	console.Infoln("Matching tracks to files...")
 	matches := MatchTracksToFiles(torrent, files)
 
-	unmatchedTracks := 0
-	for track, file := range matches {
-		if file == "" {
-			unmatchedTracks++
-			fmt.Printf("⚠️  No file found for track %d: %s\n", track.Track, track.Title)
+	for _, m := range matches.Pairs {
+		if m.Confidence == MatchByPath {
+			console.Infof("✓ Track %d -> %s\n", m.Track.Track, filepath.Base(m.File))
 		} else {
-			fmt.Printf("✓ Track %d -> %s\n", track.Track, filepath.Base(file))
+			console.Infof("✓ Track %d -> %s (by %s)\n", m.Track.Track, filepath.Base(m.File), m.Confidence)
 		}
 	}
+	for _, track := range matches.UnmatchedTracks {
+		fmt.Fprintf(os.Stderr, "⚠️  No file found for track %d: %s\n", track.Track, track.Title)
+	}
+	for _, file := range matches.UnmatchedFiles {
+		fmt.Fprintf(os.Stderr, "⚠️  %s is not in the metadata and will not be tagged\n", file)
+	}
 
-	if unmatchedTracks > 0 {
-		fmt.Fprintf(os.Stderr, "\n⚠️  %d tracks could not be matched to files\n", unmatchedTracks)
-		if !*force {
-			fmt.Fprintf(os.Stderr, "Use --force to proceed anyway\n")
-			os.Exit(1)
+	if len(matches.UnmatchedFiles) > 0 {
+		fmt.Fprintf(os.Stderr, "\n⚠️  %d audio files are not in the metadata and will be left out of the output\n", len(matches.UnmatchedFiles))
+	}
+	if len(matches.UnmatchedTracks) > 0 {
+		fmt.Fprintf(os.Stderr, "\n⚠️  %d tracks could not be matched to files\n", len(matches.UnmatchedTracks))
+		if !allow.Allows(domain.AllowUnmatchedTracks) {
+			fmt.Fprintf(os.Stderr, "Use -allow unmatched-tracks to tag the files that matched anyway\n")
+			os.Exit(exitcode.Validation)
 		}
 	}
 
 	// Determine output directory
 	outDir := *outputDir
 	if outDir == "" {
-		// Use parent directory of targetDir as base, or current directory
-		baseDir := filepath.Dir(*targetDir)
-		if baseDir == "." || baseDir == *targetDir {
-			baseDir = "."
+		// Use the root's output root, else the parent directory of targetDir, or current directory
+		baseDir := root.OutputRoot
+		if baseDir == "" {
+			baseDir = filepath.Dir(*targetDir)
+			if baseDir == "." || baseDir == *targetDir {
+				baseDir = "."
+			}
 		}
 		// Generate directory name from torrent metadata
-		dirName := torrent.DirectoryName()
+		template := *dirTemplate
+		if template == "" {
+			template = root.DirectoryTemplate
+		}
+		if template == "" {
+			template = config.LoadDirectoryTemplate()
+		}
+		if err := domain.ValidateDirectoryTemplate(template); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid directory template: %v\n", err)
+			os.Exit(1)
+		}
+		dirName := dirTorrent.DirectoryNameFromTemplate(template)
 		dir := filepath.Base(*targetDir)
 		if dir == dirName {
 			dirName = dirName + "_tagged"
@@ -124,117 +214,258 @@ func main() {
 		outDir = filepath.Join(baseDir, dirName)
 	}
 
-	fmt.Println()
+	console.Infoln()
 
 	// Check if multi-disc album
 	isMultiDisc := torrent.IsMultiDisc()
-	totalTracks := len(torrent.Tracks())
+	discDirTemplate := *discTemplate
+	if discDirTemplate == "" {
+		discDirTemplate = root.DiscTemplate
+	}
+	if discDirTemplate == "" {
+		discDirTemplate = config.LoadDiscTemplate()
+	}
+	if discDirTemplate != "" {
+		if err := domain.ValidateDiscTemplate(discDirTemplate); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid disc template: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *namingPolicy == "" {
+		*namingPolicy = config.LoadFilenamePolicy()
+	}
+	filenamePolicy, err := domain.ParseFilenamePolicy(*namingPolicy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -filename-policy: %v\n", err)
+		os.Exit(1)
+	}
+	preserve := config.LoadPreservedTags()
+	var retentionSpecs []string
+	if *retain != "" {
+		retentionSpecs = strings.Split(*retain, ",")
+	}
+	retention, err := domain.ParseTagRetentionRules(append(retentionSpecs, config.LoadTagRetention()...))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -retain: %v\n", err)
+		os.Exit(1)
+	}
+	checksumNames := config.LoadChecksums()
+	if *checksums != "" {
+		checksumNames = strings.Split(*checksums, ",")
+	}
+	checksumKinds, err := checksum.ParseKinds(checksumNames)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -checksums: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Apply tags
 	if *dryRun {
-		fmt.Println("=== DRY RUN MODE ===")
-		fmt.Printf("Would write tagged files to: %s\n", outDir)
+		console.Infoln("=== DRY RUN MODE ===")
+		console.Resultf(outDir, "Would write tagged files to: %s\n", outDir)
 		if isMultiDisc {
-			fmt.Println("Multi-disc album detected - will create disc subdirectories")
+			console.Infoln("Multi-disc album detected - will create disc subdirectories")
 		}
-		fmt.Println("Would apply tags to the following files:")
-		for track, file := range matches {
+		console.Infoln("Would apply tags to the following files:")
+		moved := make(map[string]string)
+		for _, m := range matches.Pairs {
+			track, file := m.Track, m.File
 			composers := track.Composers()
 			composerName := ""
 			if len(composers) > 0 {
 				composerName = composers[0].Name
 			}
-			if file != "" {
-				// Generate new filename
-				newFilename := tagging.GenerateFilename(track, totalTracks)
-				destPath := buildDestinationPath(outDir, track, newFilename, isMultiDisc)
-				fmt.Printf("  %s -> %s\n", filepath.Base(file), destPath)
-				fmt.Printf("    Title: %s\n", track.Title)
-				fmt.Printf("    Composer: %s\n", composerName)
+			// Generate new filename
+			newFilename := withExtension(tagging.GenerateFilename(track, torrent, filenamePolicy), file)
+			destPath := buildDestinationPath(outDir, torrent, track, newFilename, discDirTemplate, isMultiDisc)
+			console.Infof("  %s -> %s\n", filepath.Base(file), destPath)
+			recordMove(moved, *targetDir, file, outDir, destPath)
+			console.Infof("    Title: %s\n", track.Title)
+			console.Infof("    Composer: %s\n", composerName)
+			if junk, err := tagging.JunkTags(file, preserve, retention); err == nil {
+				for _, tag := range junk {
+					console.Infof("    Would remove: %s\n", tag)
+				}
+			}
+		}
+		if images, err := artwork.Images(nil, *targetDir); err == nil {
+			for _, image := range images {
+				console.Infof("Would copy %s\n", image.Path)
+			}
+		}
+		if ripFiles, err := tagging.RipFiles(nil, *targetDir, moved); err == nil {
+			for _, r := range ripFiles {
+				console.Infof("Would copy %s -> %s\n", r.From, r.To)
+				for _, name := range r.Unresolved {
+					console.Infof("    FILE %q names no tagged file; would be left as is\n", name)
+				}
 			}
 		}
-		fmt.Println("\nNo files were modified.")
+		for _, kind := range checksumKinds {
+			console.Infof("Would write %s\n", filepath.Join(outDir, kind.FileName()))
+		}
+		console.Infoln("\nNo files were modified.")
 		return
 	}
 
+	// The output directory may be shared with another album's run
+	outLock, err := lockOutput(lock, outDir)
+	if err != nil {
+		exitcode.Fail("", err)
+	}
+	defer outLock.Release()
+
 	// Create output directory
 	if err := os.MkdirAll(outDir, 0755); err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating output directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Writing tagged files to: %s\n", outDir)
+	console.Infof("Writing tagged files to: %s\n", outDir)
 	if isMultiDisc {
-		fmt.Println("Multi-disc album detected - creating disc subdirectories")
+		console.Infoln("Multi-disc album detected - creating disc subdirectories")
 	}
-	writer := tagging.NewFLACWriter()
-
 	successCount := 0
 	errorCount := 0
+	removedCount := 0
+	moved := make(map[string]string)
 
-	for track, file := range matches {
-		if file == "" {
-			continue
-		}
+	for _, m := range matches.Pairs {
+		track, file := m.Track, m.File
 
 		// Generate new filename
-		newFilename := tagging.GenerateFilename(track, totalTracks)
-		destPath := buildDestinationPath(outDir, track, newFilename, isMultiDisc)
+		newFilename := withExtension(tagging.GenerateFilename(track, torrent, filenamePolicy), file)
+		destPath := buildDestinationPath(outDir, torrent, track, newFilename, discDirTemplate, isMultiDisc)
 
 		// Create disc subdirectory if needed
 		if isMultiDisc {
 			discDir := filepath.Dir(destPath)
 			if err := os.MkdirAll(discDir, 0755); err != nil {
-				fmt.Printf("❌ Failed to create disc directory %s: %v\n", discDir, err)
+				fmt.Fprintf(os.Stderr, "❌ Failed to create disc directory %s: %v\n", discDir, err)
 				errorCount++
 				continue
 			}
 		}
 
 		// Write tags
-		err := writer.WriteTrack(file, destPath, track, torrent)
+		err := tagging.WriterFor(file, preserve, retention).WriteTrack(file, destPath, track, torrent)
 		if err != nil {
-			fmt.Printf("❌ Failed to write %s: %v\n", newFilename, err)
+			fmt.Fprintf(os.Stderr, "❌ Failed to write %s: %v\n", newFilename, err)
 			errorCount++
 			continue
 		}
 
-		fmt.Printf("✓ Created %s\n", destPath)
+		console.Infof("✓ Created %s\n", destPath)
+		recordMove(moved, *targetDir, file, outDir, destPath)
+		for _, tag := range track.RemovedTags {
+			console.Infof("  🧹 Removed %s\n", tag)
+			removedCount++
+		}
 		successCount++
 	}
 
+	// Cover images travel with the tagged files
+	copied, err := artwork.Copy(nil, *targetDir, outDir)
+	for _, name := range copied {
+		console.Infof("✓ Copied %s\n", name)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to copy cover images: %v\n", err)
+		errorCount++
+	}
+
+	// Rip logs and cue sheets too, the cue sheets pointing at the renamed files
+	ripFiles, err := tagging.RipFiles(nil, *targetDir, moved)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to read rip logs and cue sheets: %v\n", err)
+		errorCount++
+	}
+	for _, r := range ripFiles {
+		if err := r.Copy(nil, outDir); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to copy %s: %v\n", r.From, err)
+			errorCount++
+			continue
+		}
+		console.Infof("✓ Copied %s\n", r.To)
+		for _, name := range r.Unresolved {
+			fmt.Fprintf(os.Stderr, "⚠️  %s: FILE %q names no tagged file; left as is\n", r.To, name)
+		}
+	}
+
 	// Summary
-	fmt.Println()
-	fmt.Println("=== Summary ===")
-	fmt.Printf("✓ Successfully updated: %d files\n", successCount)
+	console.Infoln()
+	console.Infoln("=== Summary ===")
+	console.Infof("✓ Successfully updated: %d files\n", successCount)
 	if errorCount > 0 {
-		fmt.Printf("❌ Errors: %d files\n", errorCount)
+		fmt.Fprintf(os.Stderr, "❌ Errors: %d files\n", errorCount)
+	}
+	if removedCount > 0 {
+		console.Infof("🧹 Junk tags removed: %d\n", removedCount)
+		if err := storage.NewRepository().RecordRemovedTags(manifest, storage.SplitPaths(*metadataFile)); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to record removed tags in %s: %v\n", *metadataFile, err)
+			errorCount++
+		} else {
+			console.Infof("📝 Removed tags recorded in %s\n", *metadataFile)
+		}
+	}
+	// Checksums only cover a complete album
+	complete := errorCount == 0
+	for _, kind := range checksumKinds {
+		if !complete {
+			fmt.Fprintf(os.Stderr, "⚠️  Not writing %s: some files failed\n", kind.FileName())
+			continue
+		}
+		path, n, err := checksum.Write(outDir, kind)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Failed to write %s: %v\n", kind.FileName(), err)
+			errorCount++
+			continue
+		}
+		console.Infof("🔏 %s written for %d files: %s\n", kind.FileName(), n, path)
 	}
-	fmt.Printf("\n📁 Tagged files written to: %s\n", outDir)
+	console.Resultf(outDir, "\n📁 Tagged files written to: %s\n", outDir)
 
 	if errorCount > 0 {
 		os.Exit(1)
 	}
 }
 
-// LoadMetadataJSON loads torrent metadata from a JSON file.
-func LoadMetadataJSON(path string) (*domain.Torrent, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+// lockOutput takes the lock for outDir, unless it is the album directory
+// already held by albumLock (tagging in place). The returned lock is nil then,
+// which Release ignores.
+func lockOutput(albumLock *state.Lock, outDir string) (*state.Lock, error) {
+	if abs, err := filepath.Abs(outDir); err == nil && abs == albumLock.Info.Target {
+		return nil, nil
 	}
+	return state.AcquireDir(outDir)
+}
 
-	repo := storage.NewRepository()
-	torrent, err := repo.LoadFromJSON(data)
+// recordMove records that file under dir was tagged to destPath under outDir,
+// by their relative paths, for rewriting the cue sheets that name it.
+func recordMove(moved map[string]string, dir, file, outDir, destPath string) {
+	from, err := filepath.Rel(dir, file)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+		return
+	}
+	to, err := filepath.Rel(outDir, destPath)
+	if err != nil {
+		return
 	}
+	moved[from] = to
+}
 
+// LoadMetadataJSON loads torrent metadata from a JSON file, or assembles a set's
+// from a comma-separated list of the set's file and disc files.
+func LoadMetadataJSON(paths string) (*domain.Torrent, error) {
+	torrent, err := storage.NewRepository().LoadSet(storage.SplitPaths(paths))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
 	return torrent, nil
 }
 
-// FindFLACFiles recursively finds all FLAC files in a directory.
-func FindFLACFiles(dir string) ([]string, error) {
+// FindAudioFiles recursively finds all FLAC and DSD (DSF, DFF) files in a directory.
+func FindAudioFiles(dir string) ([]string, error) {
 	var files []string
 
 	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
@@ -242,7 +473,7 @@ func FindFLACFiles(dir string) ([]string, error) {
 			return err
 		}
 
-		if !info.IsDir() && strings.HasSuffix(strings.ToLower(path), ".flac") {
+		if !info.IsDir() && (strings.HasSuffix(strings.ToLower(path), ".flac") || tagging.IsDSD(path)) {
 			files = append(files, path)
 		}
 
@@ -252,36 +483,120 @@ func FindFLACFiles(dir string) ([]string, error) {
 	return files, err
 }
 
-// MatchTracksToFiles matches tracks to files based on track number in filename.
-// Returns a map of track -> file path (empty string if no match found).
-func MatchTracksToFiles(torrent *domain.Torrent, files []string) map[*domain.Track]string {
-	matches := make(map[*domain.Track]string)
+// withExtension gives a generated filename the extension of the source file, so
+// DSD files keep theirs.
+func withExtension(filename, source string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + strings.ToLower(filepath.Ext(source))
+}
+
+// MatchConfidence is how sure a track's pairing with a file is.
+type MatchConfidence int
+
+const (
+	MatchByNumber MatchConfidence = iota + 1 // The file name starts with the track number
+	MatchByName                              // The file has the name of the track's path
+	MatchByPath                              // The file is at the track's path
+)
+
+func (c MatchConfidence) String() string {
+	switch c {
+	case MatchByPath:
+		return "path"
+	case MatchByName:
+		return "file name"
+	case MatchByNumber:
+		return "track number"
+	}
+	return "none"
+}
 
-	for _, track := range torrent.Tracks() {
-		matches[track] = ""
+// TrackMatch pairs a track with the file it is tagged from.
+type TrackMatch struct {
+	Track      *domain.Track
+	File       string
+	Confidence MatchConfidence
+}
 
-		// Try to find file by track number prefix
-		trackPrefix := fmt.Sprintf("%02d", track.Track)
+// MatchResult is the outcome of matching tracks to files.
+type MatchResult struct {
+	Pairs           []TrackMatch    // In track order
+	UnmatchedTracks []*domain.Track // Tracks no file was found for
+	UnmatchedFiles  []string        // Audio files no track claimed: extra audio not in the metadata
+}
 
-		for _, file := range files {
-			base := filepath.Base(file)
-			if strings.HasPrefix(base, trackPrefix) {
-				matches[track] = file
-				break
+// MatchTracksToFiles matches tracks to files: first by the track's path, then by
+// its file name, then by the track number the file name starts with. Each file
+// is matched to one track at most.
+func MatchTracksToFiles(torrent *domain.Torrent, files []string) MatchResult {
+	tracks := torrent.Tracks()
+	matched := make(map[*domain.Track]TrackMatch)
+	claimed := make(map[string]bool)
+
+	passes := []struct {
+		confidence MatchConfidence
+		matches    func(track *domain.Track, file string) bool
+	}{
+		{MatchByPath, func(track *domain.Track, file string) bool {
+			path := filepath.ToSlash(track.File.Path)
+			file = filepath.ToSlash(file)
+			return path != "" && (file == path || strings.HasSuffix(file, "/"+path))
+		}},
+		{MatchByName, func(track *domain.Track, file string) bool {
+			return track.File.Path != "" && filepath.Base(file) == filepath.Base(track.File.Path)
+		}},
+		{MatchByNumber, func(track *domain.Track, file string) bool {
+			return strings.HasPrefix(filepath.Base(file), fmt.Sprintf("%02d", track.Track))
+		}},
+	}
+	for _, pass := range passes {
+		for _, track := range tracks {
+			if _, ok := matched[track]; ok {
+				continue
+			}
+			for _, file := range files {
+				if !claimed[file] && pass.matches(track, file) {
+					matched[track] = TrackMatch{Track: track, File: file, Confidence: pass.confidence}
+					claimed[file] = true
+					break
+				}
 			}
 		}
 	}
 
-	return matches
+	var result MatchResult
+	for _, track := range tracks {
+		if m, ok := matched[track]; ok {
+			result.Pairs = append(result.Pairs, m)
+		} else {
+			result.UnmatchedTracks = append(result.UnmatchedTracks, track)
+		}
+	}
+	for _, file := range files {
+		if !claimed[file] {
+			result.UnmatchedFiles = append(result.UnmatchedFiles, file)
+		}
+	}
+	return result
 }
 
 // buildDestinationPath builds the destination path for a track file.
-// Handles multi-disc albums by creating subdirectories.
-func buildDestinationPath(baseDir string, track *domain.Track, filename string, isMultiDisc bool) string {
+// Handles multi-disc albums by creating subdirectories named from discTemplate.
+func buildDestinationPath(baseDir string, torrent *domain.Torrent, track *domain.Track, filename, discTemplate string, isMultiDisc bool) string {
 	if isMultiDisc {
 		// Create disc subdirectory for all discs in multi-disc albums
-		discSubdir := tagging.GenerateDiscSubdirectoryName(track.Disc, "")
+		discSubdir := torrent.DiscDirectoryName(discTemplate, track.Disc)
 		return filepath.Join(baseDir, discSubdir, filename)
 	}
 	return filepath.Join(baseDir, filename)
 }
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: tag -metadata FILE [options]\n\n")
+	fmt.Fprintf(os.Stderr, "Writes the tags in a metadata JSON file to an album's FLAC files, copying them\n")
+	fmt.Fprintf(os.Stderr, "to a directory named from the metadata along with the cover and back cover images.\n")
+	fmt.Fprintf(os.Stderr, "A large set can be tagged from its metadata file plus disc metadata files that\n")
+	fmt.Fprintf(os.Stderr, "each correct one disc: -metadata set.json,disc3.json,disc7.json\n\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	flag.PrintDefaults()
+	exitcode.PrintCodes(os.Stderr)
+}
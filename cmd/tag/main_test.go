@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/state"
 )
 
 func TestLoadMetadataJSON(t *testing.T) {
@@ -51,21 +54,31 @@ func TestLoadMetadataJSON(t *testing.T) {
 	}
 }
 
-func TestFindFLACFiles(t *testing.T) {
+func TestFindAudioFiles(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Create test structure
 	os.Create(filepath.Join(tmpDir, "01 Track.flac"))
 	os.Create(filepath.Join(tmpDir, "02 Track.flac"))
+	os.Create(filepath.Join(tmpDir, "03 Track.DSF"))
 	os.Create(filepath.Join(tmpDir, "cover.jpg")) // should be ignored
 
-	files, err := FindFLACFiles(tmpDir)
+	files, err := FindAudioFiles(tmpDir)
 	if err != nil {
-		t.Fatalf("FindFLACFiles() error = %v", err)
+		t.Fatalf("FindAudioFiles() error = %v", err)
+	}
+
+	if len(files) != 3 {
+		t.Errorf("Found %d files, want 3", len(files))
 	}
+}
 
-	if len(files) != 2 {
-		t.Errorf("Found %d files, want 2", len(files))
+func TestWithExtension(t *testing.T) {
+	if got := withExtension("01 - Aria.flac", "/in/track01.DSF"); got != "01 - Aria.dsf" {
+		t.Errorf("withExtension() = %q, want 01 - Aria.dsf", got)
+	}
+	if got := withExtension("01 - Aria.flac", "/in/track01.flac"); got != "01 - Aria.flac" {
+		t.Errorf("withExtension() = %q, want 01 - Aria.flac", got)
 	}
 }
 
@@ -134,34 +147,48 @@ func TestMatchTracksToFiles(t *testing.T) {
 	// Create test files
 	files := []string{
 		filepath.Join(tmpDir, "01 First Track.flac"),
-		filepath.Join(tmpDir, "02 Second Track.flac"),
+		filepath.Join(tmpDir, "02.flac"),
+		filepath.Join(tmpDir, "04 Bonus.flac"),
 		// Note: Track 3 has no matching file
 	}
 
-	matches := MatchTracksToFiles(torrent, files)
+	result := MatchTracksToFiles(torrent, files)
 
-	// Should have 3 tracks in matches
-	if len(matches) != 3 {
-		t.Errorf("matches count = %d, want 3", len(matches))
+	if len(result.Pairs) != 2 {
+		t.Fatalf("matched tracks = %d, want 2", len(result.Pairs))
 	}
-
-	// Check that tracks 1 and 2 matched, track 3 did not
-	matchCount := 0
-	unmatchCount := 0
-	for _, file := range matches {
-		if file != "" {
-			matchCount++
-		} else {
-			unmatchCount++
-		}
+	if p := result.Pairs[0]; p.Track.Track != 1 || p.File != files[0] || p.Confidence != MatchByNumber {
+		t.Errorf("pair 1 = %+v", p)
 	}
-
-	if matchCount != 2 {
-		t.Errorf("matched tracks = %d, want 2", matchCount)
+	if p := result.Pairs[1]; p.Track.Track != 2 || p.File != files[1] || p.Confidence != MatchByPath {
+		t.Errorf("pair 2 = %+v", p)
+	}
+	if len(result.UnmatchedTracks) != 1 || result.UnmatchedTracks[0].Track != 3 {
+		t.Errorf("unmatched tracks = %+v, want track 3", result.UnmatchedTracks)
 	}
+	if len(result.UnmatchedFiles) != 1 || result.UnmatchedFiles[0] != files[2] {
+		t.Errorf("unmatched files = %q, want the bonus track", result.UnmatchedFiles)
+	}
+}
+
+func TestMatchTracksToFiles_MultiDisc(t *testing.T) {
+	torrent := &domain.Torrent{Files: []domain.FileLike{
+		&domain.Track{File: domain.File{Path: "CD1/01 Allegro.flac"}, Disc: 1, Track: 1, Title: "Allegro"},
+		&domain.Track{File: domain.File{Path: "CD2/01 Adagio.flac"}, Disc: 2, Track: 1, Title: "Adagio"},
+		&domain.Track{File: domain.File{Path: "old/01 Presto.flac"}, Disc: 3, Track: 1, Title: "Presto"},
+	}}
+	files := []string{"/in/CD1/01 Allegro.flac", "/in/CD2/01 Adagio.flac", "/in/CD3/01 Presto.flac"}
 
-	if unmatchCount != 1 {
-		t.Errorf("unmatched tracks = %d, want 1", unmatchCount)
+	result := MatchTracksToFiles(torrent, files)
+
+	want := []MatchConfidence{MatchByPath, MatchByPath, MatchByName}
+	if len(result.Pairs) != 3 || len(result.UnmatchedTracks) != 0 || len(result.UnmatchedFiles) != 0 {
+		t.Fatalf("MatchTracksToFiles() = %+v", result)
+	}
+	for i, p := range result.Pairs {
+		if p.File != files[i] || p.Confidence != want[i] {
+			t.Errorf("disc %d paired with %s by %s, want %s by %s", p.Track.Disc, p.File, p.Confidence, files[i], want[i])
+		}
 	}
 }
 
@@ -230,3 +257,25 @@ func TestDryRunMode(t *testing.T) {
 	// - Writes no files
 	// - Returns success exit code
 }
+
+func TestLockOutput(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	albumDir := t.TempDir()
+	albumLock, err := state.AcquireDir(albumDir)
+	if err != nil {
+		t.Fatalf("AcquireDir() error = %v", err)
+	}
+	defer albumLock.Release()
+
+	// Tagging in place must not trip over the album's own lock
+	outLock, err := lockOutput(albumLock, albumDir+"/.")
+	if err != nil || outLock != nil {
+		t.Errorf("lockOutput(album dir) = %v, %v; want no second lock", outLock, err)
+	}
+
+	outLock, err = lockOutput(albumLock, filepath.Join(t.TempDir(), "out"))
+	if err != nil || outLock == nil {
+		t.Fatalf("lockOutput(other dir) = %v, %v; want a lock", outLock, err)
+	}
+	outLock.Release()
+}
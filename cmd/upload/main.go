@@ -8,26 +8,57 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/cehbz/classical-tagger/internal/cache"
 	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+	"github.com/cehbz/classical-tagger/internal/rulepack"
+	"github.com/cehbz/classical-tagger/internal/state"
+	"github.com/cehbz/classical-tagger/internal/titlecase"
 	"github.com/cehbz/classical-tagger/internal/uploader"
 )
 
 func main() {
+	configWindow, configSpacing := config.LoadUploadSchedule()
 	// Define flags
 	var (
 		torrentDir  = flag.String("dir", "", "Directory containing tagged FLAC files (required)")
+		rootName    = flag.String("root", "", "Library root from config to resolve a relative --dir against")
+		metadata    = flag.String("metadata", "", "Upload from this metadata JSON (from extract/tag) instead of the files' tags; checked against the files. A comma-separated list assembles a set from its metadata file and disc metadata files")
 		torrentID   = flag.Int("torrent", 0, "ID of torrent to trump (required)")
 		apiKey      = flag.String("api-key", "", "Redacted API key (optional, will be loaded from config file if not provided)")
-		trumpReason = flag.String("reason", "", "Custom trump reason (optional, auto-generated if not provided)")
+		trumpReason = flag.String("reason", "", "Custom trump reason (optional, overrides the upload.trump_reason template)")
 		dryRun      = flag.Bool("dry-run", false, "Perform dry run without uploading")
+		newEdition  = flag.Bool("new-edition", false, "Upload to the torrent's group as a new edition instead of trumping it, with the remaster fields from the local edition")
+		media       = flag.String("media", "", "Media of the local files for -new-edition (CD, WEB, Vinyl, ...; default: CD when a rip log or cue sheet is present, else the trumped torrent's); a trump is refused when it differs")
+		confirm     = flag.Bool("confirm-group", false, "Upload even if the album title does not resemble the target group name")
+		confirmMast = flag.Bool("confirm-mastering", false, "Trump even if the files' sizes, bit depth or track lengths suggest a different mastering from the torrent's, noting the differences in the trump reason")
+		noSearch    = flag.Bool("skip-artist-search", false, "Don't search Redacted for existing spellings of artists new to the group")
+		wikiFile    = flag.String("wiki-file", "", "Where to write a suggested group description after uploading (default: group_<id>_wiki.txt)")
+		extras      = flag.Bool("include-extras", config.LoadIncludeExtras(), "Keep extras folders (bonus DVD or other video) in the torrent (default: upload.include_extras in config, or false)")
+		maxArtists  = flag.Int("max-artists", config.LoadMaxArtists(), "Most artists to credit; the soloists on the fewest tracks beyond it are only listed in the description, 0 for no limit (default: upload.max_artists in config, or 25)")
+		crossSeed   = flag.String("cross-seed", "", "Comma-separated site profiles (e.g. ops) to also build a .torrent for, with each site's announce URL and source, for cross-seeding")
+		collages    = flag.String("collage", "", "Comma-separated IDs of collages to add the group to after uploading; collages already holding it are reported")
+		requestID   = flag.Int("fill-request", 0, "ID of a request to fill with this upload (checks its format/media/catalogue requirements)")
 		clearCache  = flag.Bool("clear-cache", false, "Clear metadata cache before running")
 		verbose     = flag.Bool("verbose", false, "Enable verbose output")
 		help        = flag.Bool("help", false, "Show help message")
+		apiRequests = flag.Int("redacted-requests", 0, "Redacted requests allowed per window (default: redacted.rate_limit in config, or 10)")
+		apiWindow   = flag.Duration("redacted-window", 0, "Redacted rate limit window (default: redacted.rate_limit in config, or 10s)")
+		apiTimeout  = flag.Duration("timeout", 0, "Redacted HTTP timeout (default: redacted.timeout_seconds in config, or 30s)")
+		window      = flag.String("window", configWindow, "Daily window (HH:MM-HH:MM, local time) to defer the submission to; the upload is prepared now and queued (default: upload.window in config)")
+		spacing     = flag.Duration("spacing", configSpacing, "Least time between submissions, e.g. 30m; the upload is prepared now and queued (default: upload.spacing_minutes in config)")
+		runQueue    = flag.Bool("run-queue", false, "Submit the queued uploads as the window and spacing allow, waiting for each one's slot; --dir and --torrent are not needed")
 	)
 
+	console.AddFlag(flag.CommandLine)
+
 	// Custom usage message
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Classical Music Torrent Uploader
@@ -51,10 +82,19 @@ Options:
 		  Use --api-key flag to override config file.
 		  
 		  XDG_CACHE_HOME can be set to override cache directory (defaults to ~/.cache)
+		  XDG_STATE_HOME can be set to override lockfile directory (defaults to ~/.local/state)
+		  Uploads and dry runs are recorded in uploads.json there, to warn about duplicates
+		  Uploads prepared under --window or --spacing wait in upload_queue.json there
 		`, config.GetConfigPathForDisplay())
+		exitcode.PrintCodes(os.Stderr)
 	}
 
-	flag.Parse()
+	exitcode.ParseFlags(flag.CommandLine, os.Args[1:])
+	rules, err := rulepack.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	titlecase.Configure(rules.ProtectedWords)
 
 	// Show help if requested
 	if *help {
@@ -63,13 +103,13 @@ Options:
 	}
 
 	// Validate required arguments
-	if *torrentDir == "" {
+	if *torrentDir == "" && !*runQueue {
 		fmt.Fprintf(os.Stderr, "Error: --dir is required\n\n")
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	if *torrentID == 0 {
+	if *torrentID == 0 && !*runQueue {
 		fmt.Fprintf(os.Stderr, "Error: --torrent is required\n\n")
 		flag.Usage()
 		os.Exit(1)
@@ -88,8 +128,26 @@ Options:
 		}
 	}
 
+	schedule := uploader.Schedule{Spacing: *spacing}
+	if *window != "" {
+		if schedule.Window, err = uploader.ParseWindow(*window); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --window: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *runQueue {
+		runUploadQueue(*apiKey, schedule, config.LoadRedactedLimits().Override(*apiRequests, *apiWindow, *apiTimeout), *verbose)
+		return
+	}
+
+	root, err := config.LoadRoot(*rootName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --root: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Resolve torrent directory to absolute path
-	absDir, err := filepath.Abs(*torrentDir)
+	absDir, err := filepath.Abs(root.Resolve(*torrentDir))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error resolving directory path: %v\n", err)
 		os.Exit(1)
@@ -98,26 +156,92 @@ Options:
 	// Check directory exists
 	if info, err := os.Stat(absDir); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: directory %s does not exist\n", absDir)
-		os.Exit(1)
+		os.Exit(exitcode.Load)
 	} else if !info.IsDir() {
 		fmt.Fprintf(os.Stderr, "Error: %s is not a directory\n", absDir)
 		os.Exit(1)
 	}
 
+	// Prevent concurrent runs on the same album or torrent (which share the cached .torrent file)
+	dirLock, err := state.AcquireDir(absDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer dirLock.Release()
+
+	torrentLock, err := state.Acquire(fmt.Sprintf("torrent:%d", *torrentID))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer torrentLock.Release()
+
 	// Create upload command
 	cmd := uploader.NewUploadCommand(*apiKey, absDir, *torrentID)
 
+	// Configure API limits
+	limits := config.LoadRedactedLimits().Override(*apiRequests, *apiWindow, *apiTimeout)
+	cmd.Client.RateLimiter = ratelimit.NewRateLimiter(limits.Requests, limits.Window)
+	cmd.Client.HTTPClient.Timeout = limits.Timeout
+
 	// Configure options
 	if *trumpReason != "" {
 		cmd.TrumpReason = *trumpReason
 	}
 	cmd.DryRun = *dryRun
+	cmd.NewEdition = *newEdition
+	if *media != "" {
+		if cmd.Media, err = uploader.ParseMedia(*media); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --media: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	cmd.ConfirmGroup = *confirm
+	cmd.ConfirmMastering = *confirmMast
+	cmd.RequestID = *requestID
+	cmd.MetadataFile = *metadata
+	cmd.SkipArtistSearch = *noSearch
+	cmd.WikiFile = *wikiFile
+	cmd.IncludeExtras = *extras
+	cmd.MaxArtists = *maxArtists
+	cmd.Workers = config.LoadWorkers()
+	if cmd.Site, err = loadSite("red"); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, name := range strings.Split(*crossSeed, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		site, err := loadSite(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --cross-seed: %v\n", err)
+			os.Exit(1)
+		}
+		cmd.CrossSeed = append(cmd.CrossSeed, site)
+	}
+	if cmd.Collages, err = parseIDs(*collages); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --collage: %v\n", err)
+		os.Exit(1)
+	}
+	cmd.ArtistAliases = domain.NewAliasTable(rules.ArtistAliases)
 	cmd.Verbose = *verbose
+	cmd.Ledger = true
+	cmd.Schedule = schedule
+	if cmd.TrumpReason == "" {
+		tmpl, err := uploader.ParseTrumpReasonTemplate(config.LoadTrumpReasonTemplate())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		cmd.TrumpReasonTemplate = tmpl
+	}
 
 	// Clear cache if requested
 	if *clearCache {
 		if *verbose {
-			fmt.Println("Clearing cache...")
+			fmt.Fprintln(os.Stderr, "Clearing cache...")
 		}
 
 		c := cache.NewCache(0)
@@ -135,19 +259,67 @@ Options:
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-sigChan
-		fmt.Println("\nInterrupted, cancelling upload...")
+		fmt.Fprintln(os.Stderr, "\nInterrupted, cancelling upload...")
 		cancel()
 	}()
 
 	// Execute upload
-	if err := cmd.Execute(ctx); err != nil {
-		fmt.Fprintf(os.Stderr, "Upload failed: %v\n", err)
-		os.Exit(1)
+	err = cmd.Execute(ctx)
+	if *verbose {
+		fmt.Fprintf(os.Stderr, "\n⏱️  Redacted API: %s\n", cmd.Client.RateLimiter.Stats())
+	}
+	if err != nil {
+		exitcode.Fail("Upload failed", err)
 	}
 
-	if *dryRun {
-		fmt.Println("\nDry run completed successfully. No changes were made.")
-	} else {
-		fmt.Println("\nUpload completed successfully!")
+	switch {
+	case *dryRun:
+		console.Infoln("\nDry run completed successfully. No changes were made.")
+	case !schedule.IsZero():
+		console.Infoln("\nUpload prepared and queued.")
+	default:
+		console.Infoln("\nUpload completed successfully!")
+	}
+}
+
+// runUploadQueue submits the queued uploads as schedule allows, until the
+// queue is empty or the run is interrupted.
+func runUploadQueue(apiKey string, schedule uploader.Schedule, limits config.APILimits, verbose bool) {
+	cmd := uploader.NewUploadCommand(apiKey, "", 0)
+	cmd.Client.RateLimiter = ratelimit.NewRateLimiter(limits.Requests, limits.Window)
+	cmd.Client.HTTPClient.Timeout = limits.Timeout
+	cmd.Schedule = schedule
+	cmd.Verbose = verbose
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if err := cmd.RunQueue(ctx, true); err != nil {
+		exitcode.Fail("Upload queue failed", err)
+	}
+	console.Infoln("\nUpload queue is empty.")
+}
+
+// loadSite loads the named site profile from config as a torrent target.
+func loadSite(name string) (uploader.TorrentSite, error) {
+	site, err := config.LoadSite(name)
+	if err != nil {
+		return uploader.TorrentSite{}, err
+	}
+	return uploader.TorrentSite{Name: name, Announce: site.Announce, Source: site.Source}, nil
+}
+
+// parseIDs parses a comma-separated list of positive IDs.
+func parseIDs(list string) ([]int, error) {
+	var ids []int
+	for _, field := range strings.Split(list, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		id, err := strconv.Atoi(field)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid ID %q", field)
+		}
+		ids = append(ids, id)
 	}
+	return ids, nil
 }
@@ -5,8 +5,15 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/console"
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/profiling"
+	"github.com/cehbz/classical-tagger/internal/rulepack"
+	"github.com/cehbz/classical-tagger/internal/schema"
 	"github.com/cehbz/classical-tagger/internal/storage"
+	"github.com/cehbz/classical-tagger/internal/titlecase"
 	"github.com/cehbz/classical-tagger/internal/validation"
 )
 
@@ -78,8 +85,42 @@ func ValidateJSONFiles(metadataFile string, referenceFile string) (*ValidationRe
 	return report, nil
 }
 
-// PrintReport formats and prints a validation report
+// ApplyRenames gives the metadata file's artists the reference's spelling where
+// the two spell the same artist differently, saving the file. Returns a
+// description of each rename; the file is left alone when there are none.
+func ApplyRenames(metadataFile, referenceFile string) ([]string, error) {
+	repo := storage.NewRepository()
+	torrent, err := repo.LoadFromFile(metadataFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JSON metadata file: %w", err)
+	}
+	reference, err := repo.LoadFromFile(referenceFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reference JSON file: %w", err)
+	}
+	renames := torrent.AdoptReferenceSpellings(reference)
+	if len(renames) == 0 {
+		return nil, nil
+	}
+	if err := repo.SaveToFile(torrent, metadataFile); err != nil {
+		return nil, fmt.Errorf("failed to save %s: %w", metadataFile, err)
+	}
+	return renames, nil
+}
+
+// PrintReport formats and prints a validation report. Quiet, it prints one issue
+// per line as -stdin does, and load errors to stderr.
 func PrintReport(report *ValidationReport) {
+	if console.Quiet() {
+		for _, err := range report.LoadErrors {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", report.MetadataFile, err)
+		}
+		for _, issue := range report.Issues {
+			fmt.Printf("%s: %s\n", report.MetadataFile, issue)
+		}
+		return
+	}
+
 	fmt.Printf("=== Validation Report ===\n\n")
 	fmt.Printf("Metadata file: %s\n", report.MetadataFile)
 	if report.ReferenceFile != "" {
@@ -132,36 +173,128 @@ func printIssues(issues []domain.ValidationIssue) {
 	}
 }
 
+// lintStdin validates the metadata JSON on standard input against the optional
+// reference file argument, exiting 2 when anything blocks.
+func lintStdin(profile domain.ValidationProfile) {
+	var reference *domain.Torrent
+	if flag.NArg() == 1 {
+		var err error
+		if reference, err = storage.NewRepository().LoadFromFile(flag.Arg(0)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: reference file: %v\n", err)
+			os.Exit(exitcode.Load)
+		}
+	}
+	lint := func() (bool, error) { return LintJSON(os.Stdin, os.Stdout, *stdinName, reference, profile, allow) }
+	if *schemaOnly {
+		lint = func() (bool, error) { return LintJSONSchema(os.Stdin, os.Stdout, *stdinName) }
+	}
+	blocking, err := lint()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading standard input: %v\n", err)
+		os.Exit(exitcode.Load)
+	}
+	if blocking {
+		os.Exit(exitcode.Validation)
+	}
+}
+
 func usage() {
-	fmt.Fprintf(os.Stderr, "Usage: validate <metadata.json> [reference.json]\n\n")
+	fmt.Fprintf(os.Stderr, "Usage: validate [options] <metadata.json> [reference.json]\n")
+	fmt.Fprintf(os.Stderr, "       validate -stdin [options] [reference.json] < metadata.json\n\n")
 	fmt.Fprintf(os.Stderr, "Validates a JSON metadata file against validation rules.\n")
 	fmt.Fprintf(os.Stderr, "If a reference JSON file is provided, validates against it as well.\n\n")
 	fmt.Fprintf(os.Stderr, "Arguments:\n")
 	fmt.Fprintf(os.Stderr, "  metadata.json   Required: Path to the JSON metadata file to validate\n")
 	fmt.Fprintf(os.Stderr, "  reference.json  Optional: Path to a reference JSON file for comparison\n\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "Examples:\n")
 	fmt.Fprintf(os.Stderr, "  # Validate a JSON metadata file:\n")
 	fmt.Fprintf(os.Stderr, "  validate album.json\n\n")
 	fmt.Fprintf(os.Stderr, "  # Validate against a reference:\n")
-	fmt.Fprintf(os.Stderr, "  validate album.json reference.json\n")
+	fmt.Fprintf(os.Stderr, "  validate album.json reference.json\n\n")
+	fmt.Fprintf(os.Stderr, "  # Adopt the reference's spelling of artist names, then validate:\n")
+	fmt.Fprintf(os.Stderr, "  validate -apply-renames album.json reference.json\n\n")
+	fmt.Fprintf(os.Stderr, "  # Also check the cue sheets in the tagged folder:\n")
+	fmt.Fprintf(os.Stderr, "  validate -dir \"Bach - Cello Suites [FLAC]\" album.json\n\n")
+	fmt.Fprintf(os.Stderr, "  # Fail on warnings too, as configured for the seeding root:\n")
+	fmt.Fprintf(os.Stderr, "  validate -root seeding album.json\n")
+	fmt.Fprintf(os.Stderr, "  # Check only the structure, e.g. after editing by hand:\n")
+	fmt.Fprintf(os.Stderr, "  validate -schema-only album.json\n")
+	fmt.Fprintf(os.Stderr, "  # List the issues alone, one per line, for scripts:\n")
+	fmt.Fprintf(os.Stderr, "  validate -quiet album.json\n")
+	fmt.Fprintf(os.Stderr, "\n  # Lint an editor buffer on save:\n")
+	fmt.Fprintf(os.Stderr, "  validate -stdin -stdin-name album.json < album.json\n")
+	exitcode.PrintCodes(os.Stderr)
 }
 
+var (
+	stdin        = flag.Bool("stdin", false, "Read the metadata JSON from standard input and print one issue per line, for editor plugins; a reference JSON may still be given as the argument")
+	stdinName    = flag.String("stdin-name", "stdin", "With -stdin, the file name to prefix issues with, so editors can match them to the buffer")
+	rootName     = flag.String("root", "", "Library root from config whose validation profile to apply")
+	schemaOnly   = flag.Bool("schema-only", false, "Only check the metadata JSON's structure against its JSON Schema (types, required properties, misspelled keys), not the rules")
+	printSchema  = flag.Bool("print-schema", false, "Print the JSON Schema of the metadata format, for editors, and exit")
+	applyRenames = flag.Bool("apply-renames", false, "With a reference JSON, rewrite the metadata JSON so artists the reference spells differently (accents, punctuation, spacing) take its spelling throughout the album, then validate")
+	albumDir     = flag.String("dir", "", "Album folder the metadata describes; checks that the FILE entries of its CUE sheets name files in it")
+	allow        domain.Overrides
+	profileName  = flag.String("profile", "", "Validation profile: default (errors fail), strict (warnings fail too) or lenient (report only) (defaults to the root's, or default)")
+	cpuProfile   = flag.String("cpuprofile", "", "Write a CPU profile to this file (inspect with go tool pprof)")
+	memProfile   = flag.String("memprofile", "", "Write a heap profile to this file on completion")
+)
+
 func main() {
+	flag.Var(&allow, "allow", "Issues that don't fail validation, leaving every other check in force: warnings (under -profile strict) or missing-year (repeatable or comma-separated)")
+	console.AddFlag(flag.CommandLine)
 	flag.Usage = usage
-	flag.Parse()
+	exitcode.ParseFlags(flag.CommandLine, os.Args[1:])
+	rules, err := rulepack.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	titlecase.Configure(rules.ProtectedWords)
+
+	if *printSchema {
+		data, err := schema.Generate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(data)
+		return
+	}
 
-	if flag.NArg() < 1 {
+	if flag.NArg() < 1 && !*stdin {
 		fmt.Fprintf(os.Stderr, "Error: JSON metadata file is required\n\n")
 		usage()
 		os.Exit(1)
 	}
 
-	if flag.NArg() > 2 {
+	if flag.NArg() > 2 || (*stdin && flag.NArg() > 1) {
 		fmt.Fprintf(os.Stderr, "Error: too many arguments\n\n")
 		usage()
 		os.Exit(1)
 	}
 
+	root, err := config.LoadRoot(*rootName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -root: %v\n", err)
+		os.Exit(1)
+	}
+	if *profileName == "" {
+		*profileName = root.Validation
+	}
+	profile, err := domain.ParseValidationProfile(*profileName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -profile: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *stdin {
+		lintStdin(profile)
+		return
+	}
+
 	metadataFile := flag.Arg(0)
 	referenceFile := ""
 	if flag.NArg() == 2 {
@@ -172,11 +305,11 @@ func main() {
 	info, err := os.Stat(metadataFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: metadata file '%s' not found: %v\n", metadataFile, err)
-		os.Exit(1)
+		os.Exit(exitcode.Load)
 	}
 	if info.IsDir() {
 		fmt.Fprintf(os.Stderr, "Error: '%s' is a directory, expected a JSON file\n", metadataFile)
-		os.Exit(1)
+		os.Exit(exitcode.Load)
 	}
 
 	// Validate reference file exists if provided
@@ -184,26 +317,64 @@ func main() {
 		refInfo, err := os.Stat(referenceFile)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: reference file '%s' not found: %v\n", referenceFile, err)
-			os.Exit(1)
+			os.Exit(exitcode.Load)
 		}
 		if refInfo.IsDir() {
 			fmt.Fprintf(os.Stderr, "Error: '%s' is a directory, expected a JSON file\n", referenceFile)
+			os.Exit(exitcode.Load)
+		}
+	}
+
+	if *applyRenames {
+		if referenceFile == "" || *schemaOnly {
+			fmt.Fprintf(os.Stderr, "Error: -apply-renames needs a reference JSON and can't be used with -schema-only\n")
 			os.Exit(1)
 		}
+		renames, err := ApplyRenames(metadataFile, referenceFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -apply-renames: %v\n", err)
+			os.Exit(exitcode.Load)
+		}
+		for _, rename := range renames {
+			console.Infof("✓ Renamed %s\n", rename)
+		}
 	}
 
 	// Perform validation
-	report, err := ValidateJSONFiles(metadataFile, referenceFile)
+	stop, err := profiling.Start(*cpuProfile, *memProfile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	var report *ValidationReport
+	if *schemaOnly {
+		report, err = ValidateJSONSchema(metadataFile)
+	} else {
+		report, err = ValidateJSONFiles(metadataFile, referenceFile)
+	}
+	if err := stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Validation failed: %v\n", err)
 		os.Exit(1)
 	}
+	if *albumDir != "" {
+		issues, err := validation.CheckCueSheets(nil, *albumDir)
+		if err != nil {
+			report.LoadErrors = append(report.LoadErrors, err)
+		}
+		report.Issues = append(report.Issues, issues...)
+	}
 
 	// Print report
 	PrintReport(report)
 
-	// Exit with error code if there are errors
-	if report.HasErrors() {
-		os.Exit(1)
+	// Exit with error code if there are load errors or issues the profile treats as blocking
+	if len(report.LoadErrors) > 0 {
+		os.Exit(exitcode.Load)
+	}
+	if len(allow.Blocking(profile, report.Issues)) > 0 {
+		os.Exit(exitcode.Validation)
 	}
 }
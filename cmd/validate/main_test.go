@@ -2,8 +2,10 @@ package main
 
 import (
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
@@ -176,6 +178,62 @@ func TestValidateJSONFiles_WithReference(t *testing.T) {
 	}
 }
 
+func TestApplyRenames(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsonFile := filepath.Join(tmpDir, "album.json")
+	refFile := filepath.Join(tmpDir, "reference.json")
+
+	build := func(composer string) *domain.Torrent {
+		return &domain.Torrent{
+			RootPath:     "test-album",
+			Title:        "Test Album",
+			OriginalYear: 2013,
+			AlbumArtist:  []domain.Artist{{Name: composer, Role: domain.RoleComposer}},
+			Files: []domain.FileLike{
+				&domain.Track{
+					File:    domain.File{Path: "01 - Track 1.flac"},
+					Disc:    1,
+					Track:   1,
+					Title:   "Track 1",
+					Artists: []domain.Artist{{Name: composer, Role: domain.RoleComposer}},
+				},
+			},
+		}
+	}
+	repo := storage.NewRepository()
+	if err := repo.SaveToFile(build("Antonin Dvorak"), jsonFile); err != nil {
+		t.Fatalf("Failed to save torrent JSON: %v", err)
+	}
+	if err := repo.SaveToFile(build("Antonín Dvořák"), refFile); err != nil {
+		t.Fatalf("Failed to save reference JSON: %v", err)
+	}
+
+	renames, err := ApplyRenames(jsonFile, refFile)
+	if err != nil {
+		t.Fatalf("ApplyRenames error: %v", err)
+	}
+	if len(renames) != 1 || renames[0] != `"Antonin Dvorak" -> "Antonín Dvořák"` {
+		t.Errorf("ApplyRenames() = %q, want the composer renamed", renames)
+	}
+
+	report, err := ValidateJSONFiles(jsonFile, refFile)
+	if err != nil {
+		t.Fatalf("ValidateJSONFiles error: %v", err)
+	}
+	if got := report.Torrent.AlbumArtist[0].Name; got != "Antonín Dvořák" {
+		t.Errorf("saved album artist = %q, want the reference spelling", got)
+	}
+	for _, issue := range report.Issues {
+		if issue.Rule == "2.3.18.4-artists" {
+			t.Errorf("Unexpected artist issue after renaming: %s", issue)
+		}
+	}
+
+	if renames, err := ApplyRenames(jsonFile, refFile); err != nil || renames != nil {
+		t.Errorf("ApplyRenames() again = %q, %v, want nothing to do", renames, err)
+	}
+}
+
 func TestValidateJSONFiles_InvalidReference(t *testing.T) {
 	tmpDir := t.TempDir()
 	jsonFile := filepath.Join(tmpDir, "album.json")
@@ -505,3 +563,62 @@ func TestValidateJSONFiles_JSONSerialization(t *testing.T) {
 		t.Errorf("Torrent title = %q, want %q", report.Torrent.Title, torrent.Title)
 	}
 }
+
+func TestLintJSON(t *testing.T) {
+	album := `{
+  "title": "Christmas Motets",
+  "original_year": 2013,
+  "files": [
+    {"path": "01 - Frohlocket.flac", "disc": 1, "track": 1, "title": "Frohlocket", "artists": []}
+  ]
+}`
+	var out strings.Builder
+	blocking, err := LintJSON(strings.NewReader(album), &out, "album.json", nil, domain.ValidationDefault, nil)
+	if err != nil {
+		t.Fatalf("LintJSON() error = %v", err)
+	}
+	if !blocking || !strings.Contains(out.String(), "album.json: [ERROR] Track 1 (01 - Frohlocket.flac): ") {
+		t.Errorf("LintJSON() = %v,\n%s\nwant a blocking track error prefixed with the name", blocking, out.String())
+	}
+	if lenient, _ := LintJSON(strings.NewReader(album), io.Discard, "album.json", nil, domain.ValidationLenient, nil); lenient {
+		t.Error("LintJSON() with the lenient profile blocks, want report only")
+	}
+
+	out.Reset()
+	malformed := "{\n  \"title\": \"Christmas Motets\",\n  \"original_year\": 2013\n  \"files\": []\n}"
+	if blocking, _ := LintJSON(strings.NewReader(malformed), &out, "album.json", nil, domain.ValidationDefault, nil); !blocking {
+		t.Error("LintJSON(malformed) does not block")
+	}
+	if !strings.HasPrefix(out.String(), "album.json:4:3: [ERROR] invalid JSON: ") {
+		t.Errorf("LintJSON(malformed) = %q, want the position of the missing comma", out.String())
+	}
+}
+
+func TestLintJSONSchema(t *testing.T) {
+	album := `{
+  "schema_version": 1,
+  "root_path": "Christmas Motets",
+  "title": "Christmas Motets",
+  "original_yaer": 2013,
+  "files": [
+    {"path": "01 - Frohlocket.flac", "disc": 1, "track": "1", "title": "Frohlocket", "artists": []}
+  ]
+}`
+	var out strings.Builder
+	blocking, err := LintJSONSchema(strings.NewReader(album), &out, "album.json")
+	if err != nil {
+		t.Fatalf("LintJSONSchema() error = %v", err)
+	}
+	want := `album.json: [ERROR] Album (/): schema - missing property "original_year"
+album.json: [ERROR] Album (/files/0/track): schema - want integer, got string
+album.json: [ERROR] Album (/): schema - unknown property "original_yaer"
+`
+	if !blocking || out.String() != want {
+		t.Errorf("LintJSONSchema() = %v,\n%s\nwant\n%s", blocking, out.String(), want)
+	}
+
+	out.Reset()
+	if blocking, _ := LintJSONSchema(strings.NewReader(`{"title": }`), &out, "album.json"); !blocking || !strings.HasPrefix(out.String(), "album.json:1:11: [ERROR] invalid JSON: ") {
+		t.Errorf("LintJSONSchema(malformed) = %v, %q", blocking, out.String())
+	}
+}
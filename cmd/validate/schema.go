@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/schema"
+	"github.com/cehbz/classical-tagger/internal/storage"
+)
+
+// schemaRule is the rule ID of structural issues found by -schema-only.
+const schemaRule = "schema"
+
+// schemaIssues checks a metadata JSON document against the metadata schema,
+// one error issue per violation located by its JSON Pointer. Documents written
+// with an older schema are migrated first, as loading them would.
+func schemaIssues(data []byte) ([]domain.ValidationIssue, error) {
+	migrated, _, err := storage.Migrate(data)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := schema.Validate(migrated)
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]domain.ValidationIssue, 0, len(violations))
+	for _, v := range violations {
+		path := v.Path
+		if path == "" {
+			path = "/"
+		}
+		issues = append(issues, domain.ValidationIssue{
+			Level:   domain.LevelError,
+			Path:    path,
+			Rule:    schemaRule,
+			Message: v.Message,
+		})
+	}
+	return issues, nil
+}
+
+// ValidateJSONSchema checks only the structure of a JSON metadata file: types,
+// required properties and unknown (usually misspelled) keys.
+func ValidateJSONSchema(metadataFile string) (*ValidationReport, error) {
+	report := &ValidationReport{MetadataFile: metadataFile}
+	data, err := os.ReadFile(metadataFile)
+	if err == nil {
+		report.Issues, err = schemaIssues(data)
+	}
+	if err != nil {
+		report.LoadErrors = append(report.LoadErrors, fmt.Errorf("failed to load JSON metadata file: %w", err))
+	}
+	return report, nil
+}
+
+// LintJSONSchema checks the structure of the metadata JSON read from r, as
+// LintJSON does its rules. Any structural issue blocks.
+func LintJSONSchema(r io.Reader, w io.Writer, name string) (bool, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return false, err
+	}
+	issues, err := schemaIssues(data)
+	if err != nil {
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			line, col := position(data, syntax.Offset-1)
+			fmt.Fprintf(w, "%s:%d:%d: [%s] invalid JSON: %v\n", name, line, col, domain.LevelError, syntax)
+		} else {
+			fmt.Fprintf(w, "%s: [%s] %v\n", name, domain.LevelError, err)
+		}
+		return true, nil
+	}
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s: %s\n", name, issue)
+	}
+	return len(issues) > 0, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/storage"
+	"github.com/cehbz/classical-tagger/internal/validation"
+)
+
+// LintJSON validates the metadata JSON read from r, as an editor plugin sends the
+// buffer being saved, and writes one line per issue to w, prefixed with name so
+// editors can match them ("album.json: [ERROR] Track 3 (03.flac): ..."). Malformed JSON is
+// reported with its line and column ("album.json:12:5: ..."). It returns whether
+// the profile finds anything blocking that allow does not let through.
+func LintJSON(r io.Reader, w io.Writer, name string, reference *domain.Torrent, profile domain.ValidationProfile, allow domain.Overrides) (bool, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return false, err
+	}
+
+	var issues []domain.ValidationIssue
+	if reference == nil {
+		issues, err = validation.CheckJSON(data)
+	} else {
+		var torrent *domain.Torrent
+		if torrent, err = storage.NewRepository().LoadFromJSON(data); err == nil {
+			issues = validation.Check(torrent, reference)
+		}
+	}
+	if err != nil {
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			line, col := position(data, syntax.Offset-1) // Offset counts the offending byte
+			fmt.Fprintf(w, "%s:%d:%d: [%s] invalid JSON: %v\n", name, line, col, domain.LevelError, syntax)
+		} else {
+			fmt.Fprintf(w, "%s: [%s] %v\n", name, domain.LevelError, err)
+		}
+		return true, nil
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s: %s\n", name, issue)
+	}
+	return len(allow.Blocking(profile, issues)) > 0, nil
+}
+
+// position returns the 1-based line and column of the byte at offset in data.
+func position(data []byte, offset int64) (line, col int) {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
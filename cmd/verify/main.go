@@ -0,0 +1,252 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/cehbz/classical-tagger/internal/checksum"
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/exitcode"
+	"github.com/cehbz/classical-tagger/internal/storage"
+	"github.com/cehbz/classical-tagger/internal/tagging"
+	"github.com/cehbz/classical-tagger/internal/torrentfile"
+)
+
+var (
+	dir          = flag.String("dir", "", "Seeding directory to verify (required)")
+	rootName     = flag.String("root", "", "Library root from config to resolve a relative -dir against")
+	torrentFile  = flag.String("torrent", "", "Path to the .torrent file to verify sizes and piece hashes against")
+	metadataFile = flag.String("metadata", "", "Path to metadata JSON file used as manifest and to verify tags")
+	checksums    = flag.Bool("checksums", false, "Verify the audio files against the SHA256SUMS and ffp.txt files in the directory (written by tag -checksums)")
+	workers      = flag.Int("workers", 0, "Number of files to check in parallel (default: performance.workers in config, or number of CPUs)")
+)
+
+// FileReport lists the problems found for one file.
+type FileReport struct {
+	Path     string
+	Problems []string
+}
+
+// VerifyReport contains the per-file verification results.
+type VerifyReport struct {
+	Dir   string
+	Files []FileReport
+}
+
+// HasProblems returns true if any file failed verification.
+func (r *VerifyReport) HasProblems() bool {
+	for _, f := range r.Files {
+		if len(f.Problems) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *VerifyReport) add(path, problem string) {
+	for i := range r.Files {
+		if r.Files[i].Path == path {
+			if problem != "" {
+				r.Files[i].Problems = append(r.Files[i].Problems, problem)
+			}
+			return
+		}
+	}
+	f := FileReport{Path: path}
+	if problem != "" {
+		f.Problems = append(f.Problems, problem)
+	}
+	r.Files = append(r.Files, f)
+}
+
+func main() {
+	console.AddFlag(flag.CommandLine)
+	flag.Usage = usage
+	exitcode.ParseFlags(flag.CommandLine, os.Args[1:])
+
+	if *dir == "" || (*torrentFile == "" && *metadataFile == "" && !*checksums) {
+		usage()
+		os.Exit(exitcode.Failure)
+	}
+
+	root, err := config.LoadRoot(*rootName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -root: %v\n", err)
+		os.Exit(exitcode.Failure)
+	}
+	*dir = root.Resolve(*dir)
+
+	n := *workers
+	if n <= 0 {
+		n = config.LoadWorkers()
+	}
+
+	report, err := VerifyDirectory(*dir, *torrentFile, *metadataFile, *checksums, n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitcode.Load)
+	}
+
+	PrintReport(report)
+	if report.HasProblems() {
+		os.Exit(exitcode.Validation)
+	}
+}
+
+// VerifyDirectory checks dir against a .torrent file, a metadata JSON manifest and/or
+// (with checksums) the checksum files in dir. The torrent provides file sizes and piece
+// hashes; the metadata provides the file list and the tags each track should carry.
+func VerifyDirectory(dir, torrentFile, metadataFile string, checksums bool, workers int) (*VerifyReport, error) {
+	report := &VerifyReport{Dir: dir}
+
+	if torrentFile != "" {
+		meta, err := torrentfile.Load(torrentFile)
+		if err != nil {
+			return nil, err
+		}
+		// Single-file torrents are verified relative to the containing directory
+		contentDir := dir
+		if meta.IsSingleFile() {
+			contentDir = filepath.Dir(dir)
+		}
+		results, err := torrentfile.Verify(contentDir, meta)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify torrent content: %w", err)
+		}
+		for _, r := range results {
+			report.add(r.Path, r.Problem())
+		}
+	}
+
+	if metadataFile != "" {
+		torrent, err := storage.NewRepository().LoadFromFile(metadataFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load metadata: %w", err)
+		}
+		verifyManifest(report, dir, torrent, workers)
+	}
+
+	if checksums {
+		results, err := checksum.Verify(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify checksums: %w", err)
+		}
+		for _, r := range results {
+			report.add(r.Path, r.Problem)
+		}
+	}
+
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].Path < report.Files[j].Path
+	})
+	return report, nil
+}
+
+// verifyManifest checks that every file in the metadata exists and that tracks carry the expected tags.
+// Files are checked by a pool of workers, which helps on slow network storage.
+func verifyManifest(report *VerifyReport, dir string, torrent *domain.Torrent, workers int) {
+	problems := make([][]string, len(torrent.Files))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				problems[i] = checkManifestFile(dir, torrent.Files[i], torrent)
+			}
+		}()
+	}
+	for i := range torrent.Files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, file := range torrent.Files {
+		rel := filepath.ToSlash(file.GetPath())
+		report.add(rel, "")
+		for _, p := range problems[i] {
+			report.add(rel, p)
+		}
+	}
+}
+
+// checkManifestFile returns the problems found for one manifest entry.
+func checkManifestFile(dir string, file domain.FileLike, torrent *domain.Torrent) []string {
+	path := filepath.Join(dir, filepath.FromSlash(file.GetPath()))
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []string{"missing"}
+		}
+		return []string{err.Error()}
+	}
+
+	track, ok := file.(*domain.Track)
+	if !ok {
+		return nil
+	}
+	mismatches, err := tagging.VerifyTags(path, track, torrent)
+	if err != nil {
+		return []string{fmt.Sprintf("cannot read tags: %v", err)}
+	}
+	return mismatches
+}
+
+// PrintReport formats and prints a verification report. Quiet, it prints one
+// problem per line, prefixed with the file's path.
+func PrintReport(report *VerifyReport) {
+	if console.Quiet() {
+		for _, f := range report.Files {
+			for _, p := range f.Problems {
+				fmt.Printf("%s: %s\n", f.Path, p)
+			}
+		}
+		return
+	}
+
+	fmt.Printf("=== Verify Report ===\n\n")
+	fmt.Printf("Directory: %s\n\n", report.Dir)
+
+	failed := 0
+	for _, f := range report.Files {
+		if len(f.Problems) == 0 {
+			fmt.Printf("✓ %s\n", f.Path)
+			continue
+		}
+		failed++
+		fmt.Printf("❌ %s\n", f.Path)
+		for _, p := range f.Problems {
+			fmt.Printf("    %s\n", p)
+		}
+	}
+
+	fmt.Println("\n=== SUMMARY ===")
+	if failed > 0 {
+		fmt.Printf("❌ FAILED: %d of %d files have problems\n", failed, len(report.Files))
+	} else {
+		fmt.Printf("✅ PASSED: %d files verified\n", len(report.Files))
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: verify -dir DIRECTORY [-torrent FILE] [-metadata FILE] [-checksums]\n\n")
+	fmt.Fprintf(os.Stderr, "Verify a seeding directory has not changed since it was tagged and uploaded.\n")
+	fmt.Fprintf(os.Stderr, "At least one of -torrent, -metadata or -checksums is required.\n\n")
+	fmt.Fprintf(os.Stderr, "Options:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nChecks:\n")
+	fmt.Fprintf(os.Stderr, "  -torrent   file presence, file sizes and piece hashes\n")
+	fmt.Fprintf(os.Stderr, "  -metadata  file presence and track tags (title, album, composer, numbering)\n")
+	fmt.Fprintf(os.Stderr, "  -checksums audio files against SHA256SUMS and FLAC fingerprints (ffp.txt)\n")
+	exitcode.PrintCodes(os.Stderr)
+}
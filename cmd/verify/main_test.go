@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/checksum"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/torrentfile"
+)
+
+func writeTorrent(t *testing.T, path string, files map[string]string, order []string) {
+	t.Helper()
+	var content []byte
+	fileList := []any{}
+	for _, name := range order {
+		content = append(content, files[name]...)
+		fileList = append(fileList, map[string]any{"length": len(files[name]), "path": []any{name}})
+	}
+	var pieces []byte
+	const pieceLength = 8
+	for i := 0; i < len(content); i += pieceLength {
+		h := sha1.Sum(content[i:min(i+pieceLength, len(content))])
+		pieces = append(pieces, h[:]...)
+	}
+	data, err := torrentfile.Encode(map[string]any{
+		"info": map[string]any{
+			"name":         "Album",
+			"piece length": pieceLength,
+			"pieces":       pieces,
+			"files":        fileList,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyDirectory_Torrent(t *testing.T) {
+	tmp := t.TempDir()
+	dir := filepath.Join(tmp, "Album")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{"01.flac": "first track data", "02.flac": "second track data"}
+	torrentPath := filepath.Join(tmp, "album.torrent")
+	writeTorrent(t, torrentPath, files, []string{"01.flac", "02.flac"})
+
+	os.WriteFile(filepath.Join(dir, "01.flac"), []byte(files["01.flac"]), 0644)
+	os.WriteFile(filepath.Join(dir, "02.flac"), []byte("second track DATA"), 0644)
+
+	report, err := VerifyDirectory(dir, torrentPath, "", false, 1)
+	if err != nil {
+		t.Fatalf("VerifyDirectory error: %v", err)
+	}
+	if !report.HasProblems() {
+		t.Fatal("expected problems for modified file")
+	}
+	if len(report.Files) != 2 {
+		t.Fatalf("expected 2 file reports, got %d", len(report.Files))
+	}
+	if len(report.Files[0].Problems) != 0 {
+		t.Errorf("01.flac should verify, got %v", report.Files[0].Problems)
+	}
+	if len(report.Files[1].Problems) != 1 {
+		t.Errorf("02.flac should fail hash check, got %v", report.Files[1].Problems)
+	}
+}
+
+func TestVerifyManifest_MissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "folder.jpg"), []byte("jpg"), 0644)
+
+	torrent := &domain.Torrent{
+		Title: "Album",
+		Files: []domain.FileLike{
+			&domain.File{Path: "folder.jpg"},
+			&domain.Track{File: domain.File{Path: "01 - Title.flac"}, Disc: 1, Track: 1, Title: "Title"},
+		},
+	}
+
+	report := &VerifyReport{Dir: dir}
+	verifyManifest(report, dir, torrent, 2)
+
+	if len(report.Files) != 2 {
+		t.Fatalf("expected 2 file reports, got %d", len(report.Files))
+	}
+	if len(report.Files[0].Problems) != 0 {
+		t.Errorf("folder.jpg should verify, got %v", report.Files[0].Problems)
+	}
+	if len(report.Files[1].Problems) != 1 || report.Files[1].Problems[0] != "missing" {
+		t.Errorf("track should be reported missing, got %v", report.Files[1].Problems)
+	}
+}
+
+func TestVerifyDirectory_Checksums(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "01.flac"), []byte("first track data"), 0644)
+	os.WriteFile(filepath.Join(dir, "02.flac"), []byte("second track data"), 0644)
+	if _, _, err := checksum.Write(dir, checksum.SHA256); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "02.flac"), []byte("second track DATA"), 0644)
+
+	report, err := VerifyDirectory(dir, "", "", true, 1)
+	if err != nil {
+		t.Fatalf("VerifyDirectory error: %v", err)
+	}
+	if len(report.Files) != 2 {
+		t.Fatalf("expected 2 file reports, got %d", len(report.Files))
+	}
+	if len(report.Files[0].Problems) != 0 {
+		t.Errorf("01.flac should verify, got %v", report.Files[0].Problems)
+	}
+	if len(report.Files[1].Problems) != 1 {
+		t.Errorf("02.flac should fail its SHA-256, got %v", report.Files[1].Problems)
+	}
+
+	if _, err := VerifyDirectory(t.TempDir(), "", "", true, 1); err == nil {
+		t.Error("VerifyDirectory() without checksum files, want error")
+	}
+}
@@ -0,0 +1,245 @@
+// Package artwork checks an album's cover image for the problems that most often
+// get uploads flagged: a missing cover, thumbnails and oversized scans, booklet
+// spreads instead of the front, huge files and JPEG encodings that hardware
+// players can't display. Fix re-encodes the cover to address what it can.
+package artwork
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // Register decoders for image.DecodeConfig
+	_ "image/png"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+const (
+	// MinDimension is the smallest width and height not reported as a thumbnail.
+	MinDimension = 500
+	// MaxDimension is the largest width or height not reported as oversized.
+	MaxDimension = 3000
+	// MaxBytes is the largest cover file not reported as too large.
+	MaxBytes = 5 << 20
+	// MaxAspect is the largest ratio of long to short side not reported; fronts
+	// are square or nearly so, spreads and tray inserts are not.
+	MaxAspect = 1.25
+	// RuleID identifies cover issues in validation output.
+	RuleID = "artwork.cover"
+)
+
+// coverNames lists the cover file names players and trackers look for, in order of preference.
+var coverNames = []string{
+	"cover.jpg", "cover.jpeg", "folder.jpg", "folder.jpeg", "front.jpg", "front.jpeg",
+	"cover.png", "folder.png", "front.png",
+}
+
+// backNames lists the file names of the back cover, in order of preference.
+var backNames = []string{"back.jpg", "back.jpeg", "back.png"}
+
+// Cover describes an album's cover image.
+type Cover struct {
+	Path        string // Relative to the album directory
+	Format      string // "jpeg" or "png"
+	Width       int
+	Height      int
+	Bytes       int64
+	Progressive bool // Progressive JPEG, which some hardware players can't display
+	CMYK        bool // CMYK JPEG, shown with inverted or wrong colours by many viewers
+}
+
+// Find returns the cover image in the top level of dir, or nil when it has none.
+// Disc subdirectories are not searched: the cover belongs at the album root.
+func Find(files fsys.FS, dir string) (*Cover, error) {
+	return find(files, dir, coverNames)
+}
+
+// FindBack returns the back cover image (back.jpg) in the top level of dir, or
+// nil when it has none.
+func FindBack(files fsys.FS, dir string) (*Cover, error) {
+	return find(files, dir, backNames)
+}
+
+// find returns the first image in the top level of dir named one of names,
+// ignoring case.
+func find(files fsys.FS, dir string, names []string) (*Cover, error) {
+	files = fsys.Or(files)
+
+	found := make(map[string]string) // Lowercase name to actual name
+	err := files.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if d.IsDir() {
+			return fs.SkipDir
+		}
+		found[strings.ToLower(d.Name())] = d.Name()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if actual, ok := found[name]; ok {
+			return Inspect(files, dir, actual)
+		}
+	}
+	return nil, nil
+}
+
+// Inspect reads the header of the image at name, relative to dir.
+func Inspect(files fsys.FS, dir, name string) (*Cover, error) {
+	data, err := fsys.Or(files).ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	return inspect(name, data)
+}
+
+// inspect reads the header of data, the image at name.
+func inspect(name string, data []byte) (*Cover, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &Cover{
+		Path:        filepath.ToSlash(name),
+		Format:      format,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+		Bytes:       int64(len(data)),
+		Progressive: format == "jpeg" && isProgressive(data),
+		CMYK:        cfg.ColorModel == color.CMYKModel,
+	}, nil
+}
+
+// Problems describes what is wrong with the cover; empty when nothing is.
+func (c *Cover) Problems() []string {
+	var problems []string
+	if c.Width < MinDimension || c.Height < MinDimension {
+		problems = append(problems, fmt.Sprintf("%s is %d×%d, smaller than %d×%d", c.Path, c.Width, c.Height, MinDimension, MinDimension))
+	}
+	if c.Width > MaxDimension || c.Height > MaxDimension {
+		problems = append(problems, fmt.Sprintf("%s is %d×%d, larger than %d pixels on a side", c.Path, c.Width, c.Height, MaxDimension))
+	}
+	if c.aspect() > MaxAspect {
+		problems = append(problems, fmt.Sprintf("%s is %d×%d, not square: a booklet spread or tray insert rather than the front?", c.Path, c.Width, c.Height))
+	}
+	if c.Bytes > MaxBytes {
+		problems = append(problems, fmt.Sprintf("%s is %.1f MB, larger than %d MB", c.Path, float64(c.Bytes)/(1<<20), MaxBytes>>20))
+	}
+	if c.Progressive {
+		problems = append(problems, fmt.Sprintf("%s is a progressive JPEG, which some hardware players can't display", c.Path))
+	}
+	if c.CMYK {
+		problems = append(problems, fmt.Sprintf("%s is a CMYK JPEG, which many viewers show in the wrong colours", c.Path))
+	}
+	return problems
+}
+
+// Fixable reports whether Fix would address any of the cover's problems: it
+// downscales oversized images and re-encodes large, progressive, CMYK and PNG
+// covers as baseline RGB JPEG. Thumbnails and spreads need a better scan.
+func (c *Cover) Fixable() bool {
+	return c.Width > MaxDimension || c.Height > MaxDimension || c.Bytes > MaxBytes ||
+		c.Progressive || c.CMYK || c.Format == "png"
+}
+
+func (c *Cover) aspect() float64 {
+	long, short := max(c.Width, c.Height), min(c.Width, c.Height)
+	if short == 0 {
+		return 0
+	}
+	return float64(long) / float64(short)
+}
+
+// Check returns the cover problems in dir as validation warnings: artwork is
+// judged by moderators rather than rejected outright.
+func Check(files fsys.FS, dir string) []domain.ValidationIssue {
+	warn := func(message string) domain.ValidationIssue {
+		return domain.ValidationIssue{Level: domain.LevelWarning, Track: 0, Rule: RuleID, Message: message}
+	}
+
+	cover, err := Find(files, dir)
+	if err != nil {
+		return []domain.ValidationIssue{warn(fmt.Sprintf("Cover image unreadable: %v", err))}
+	}
+	if cover == nil {
+		return []domain.ValidationIssue{warn("No cover image (cover.jpg or folder.jpg) in the album folder")}
+	}
+
+	var issues []domain.ValidationIssue
+	for _, problem := range cover.Problems() {
+		issues = append(issues, warn("Cover "+problem))
+	}
+	return issues
+}
+
+// isProgressive scans the JPEG markers up to the first frame header and reports
+// whether it is progressive (SOF2, SOF6, SOF10 or SOF14).
+func isProgressive(data []byte) bool {
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return false
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF: // Fill byte
+			i++
+			continue
+		case marker >= 0xC0 && marker <= 0xCF && marker != 0xC4 && marker != 0xC8 && marker != 0xCC:
+			return marker&0x03 == 0x02
+		case marker == 0xDA: // Start of scan without a frame header: malformed
+			return false
+		}
+		i += 2 + (int(data[i+2])<<8 | int(data[i+3]))
+	}
+	return false
+}
+
+// Images returns those of the cover and back cover images dir has in its top level.
+func Images(files fsys.FS, dir string) ([]*Cover, error) {
+	var images []*Cover
+	for _, find := range []func(fsys.FS, string) (*Cover, error){Find, FindBack} {
+		cover, err := find(files, dir)
+		if err != nil {
+			return images, err
+		}
+		if cover != nil {
+			images = append(images, cover)
+		}
+	}
+	return images, nil
+}
+
+// Copy copies the cover and back cover images in the top level of from into
+// to, keeping their names, and returns the names copied.
+func Copy(files fsys.FS, from, to string) ([]string, error) {
+	files = fsys.Or(files)
+	images, err := Images(files, from)
+	if err != nil {
+		return nil, err
+	}
+	var copied []string
+	for _, cover := range images {
+		name := filepath.FromSlash(cover.Path)
+		data, err := files.ReadFile(filepath.Join(from, name))
+		if err != nil {
+			return copied, err
+		}
+		if err := files.WriteFile(filepath.Join(to, name), data, 0644); err != nil {
+			return copied, err
+		}
+		copied = append(copied, cover.Path)
+	}
+	return copied, nil
+}
@@ -0,0 +1,163 @@
+package artwork
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+const album = "/music/Bach - Goldberg Variations"
+
+// writeImage encodes a width×height grey image at name in the album folder.
+func writeImage(t *testing.T, mem *fsys.Mem, name string, width, height int) {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i)
+	}
+	var buf bytes.Buffer
+	var err error
+	if strings.HasSuffix(name, ".png") {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem.MkdirAll(album, 0755)
+	if err := mem.WriteFile(filepath.Join(album, name), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		width  int
+		height int
+		want   []string // Substrings of the expected messages, in order
+	}{
+		{"good cover", "cover.jpg", 1000, 1000, nil},
+		{"folder.jpg accepted", "Folder.JPG", 600, 600, nil},
+		{"no cover", "back.jpg", 1000, 1000, []string{"No cover image"}},
+		{"thumbnail", "cover.jpg", 300, 300, []string{"smaller than 500×500"}},
+		{"oversized", "cover.png", 3200, 3100, []string{"larger than 3000 pixels"}},
+		{"spread", "cover.jpg", 1400, 700, []string{"not square"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := fsys.NewMem()
+			writeImage(t, mem, tt.file, tt.width, tt.height)
+			mem.MkdirAll(filepath.Join(album, "CD1"), 0755)
+			mem.WriteFile(filepath.Join(album, "CD1", "cover.jpg"), []byte("not an image"), 0644)
+
+			issues := Check(mem, album)
+			if len(issues) != len(tt.want) {
+				t.Fatalf("Check() = %v, want %d issues", issues, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(issues[i].Message, want) {
+					t.Errorf("issue %d = %q, want it to mention %q", i, issues[i].Message, want)
+				}
+				if issues[i].Rule != RuleID {
+					t.Errorf("issue %d rule = %q, want %q", i, issues[i].Rule, RuleID)
+				}
+			}
+		})
+	}
+}
+
+func TestIsProgressive(t *testing.T) {
+	app0 := []byte{0xFF, 0xE0, 0x00, 0x04, 0x4A, 0x46}
+	sof := func(marker byte) []byte {
+		data := append([]byte{0xFF, 0xD8}, app0...)
+		return append(data, 0xFF, marker, 0x00, 0x0B, 0x08)
+	}
+
+	if isProgressive(sof(0xC0)) {
+		t.Error("isProgressive(baseline) = true, want false")
+	}
+	if !isProgressive(sof(0xC2)) {
+		t.Error("isProgressive(SOF2) = false, want true")
+	}
+	if isProgressive([]byte{0xFF, 0xD8, 0x00}) {
+		t.Error("isProgressive(truncated) = true, want false")
+	}
+}
+
+func TestFix(t *testing.T) {
+	mem := fsys.NewMem()
+	writeImage(t, mem, "cover.png", 3200, 3000)
+
+	cover, err := Find(mem, album)
+	if err != nil || cover == nil {
+		t.Fatalf("Find() = %v, %v", cover, err)
+	}
+	fixed, err := Fix(mem, album, cover)
+	if err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if fixed.Path != "cover.jpg" || fixed.Format != "jpeg" {
+		t.Errorf("Fix() wrote %s as %s, want cover.jpg as jpeg", fixed.Path, fixed.Format)
+	}
+	if fixed.Width != FixDimension || fixed.Height != 1406 {
+		t.Errorf("Fix() size = %d×%d, want %d×1406", fixed.Width, fixed.Height, FixDimension)
+	}
+	if len(fixed.Problems()) != 0 {
+		t.Errorf("fixed cover problems = %v, want none", fixed.Problems())
+	}
+	if _, err := mem.Stat(filepath.Join(album, "cover.png")); err == nil {
+		t.Error("Fix() left cover.png behind")
+	}
+
+	// Nothing to fix: returned unchanged
+	again, err := Fix(mem, album, fixed)
+	if err != nil || again != fixed {
+		t.Errorf("Fix(fixed) = %v, %v, want it unchanged", again, err)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	mem := fsys.NewMem()
+	writeImage(t, mem, "Folder.jpg", 600, 600)
+	writeImage(t, mem, "back.png", 700, 560)
+	writeImage(t, mem, "booklet.jpg", 600, 600)
+	const out = "/music/tagged"
+	mem.MkdirAll(out, 0755)
+
+	copied, err := Copy(mem, album, out)
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if want := []string{"Folder.jpg", "back.png"}; !slices.Equal(copied, want) {
+		t.Errorf("Copy() = %v, want %v", copied, want)
+	}
+	for _, name := range copied {
+		if _, err := mem.Stat(filepath.Join(out, name)); err != nil {
+			t.Errorf("%s not copied: %v", name, err)
+		}
+	}
+	if _, err := mem.Stat(filepath.Join(out, "booklet.jpg")); err == nil {
+		t.Error("Copy() copied booklet.jpg")
+	}
+}
+
+func TestDownscale_Averages(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 2, 2))
+	img.Pix = []uint8{0, 100, 100, 200}
+	got := downscale(img, 1, 1)
+	if r, _, _, _ := got.At(0, 0).RGBA(); r>>8 != 100 {
+		t.Errorf("downscale() = %v, want the mean grey 100", color.GrayModel.Convert(got.At(0, 0)))
+	}
+}
@@ -0,0 +1,123 @@
+package artwork
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// thumbnailSizes are the longest sides of the Cover Art Archive's thumbnails,
+// smallest first.
+var thumbnailSizes = []int{250, 500, 1200}
+
+// CoverArtArchive finds cover images by the album's MusicBrainz release ID.
+type CoverArtArchive struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewCoverArtArchive creates a Cover Art Archive client.
+func NewCoverArtArchive() *CoverArtArchive {
+	return &CoverArtArchive{
+		BaseURL:    "https://coverartarchive.org",
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// caaRelease is the Cover Art Archive's listing of a release's images.
+type caaRelease struct {
+	Images []struct {
+		Front      bool              `json:"front"`
+		Back       bool              `json:"back"`
+		Image      string            `json:"image"`
+		Thumbnails map[string]string `json:"thumbnails"`
+	} `json:"images"`
+}
+
+func (a *CoverArtArchive) Name() string { return "Cover Art Archive" }
+
+func (a *CoverArtArchive) License() string {
+	return "uploaded by MusicBrainz editors; copyright remains with the rights holders"
+}
+
+// Candidates returns the release's images flagged as side, as the smallest
+// thumbnail of at least size pixels or else the original upload.
+func (a *CoverArtArchive) Candidates(ctx context.Context, album *domain.Torrent, side Side, size int) ([]Candidate, error) {
+	if album.Edition == nil || strings.TrimSpace(album.Edition.MusicBrainzAlbumID) == "" {
+		return nil, nil
+	}
+	mbid := strings.TrimSpace(album.Edition.MusicBrainzAlbumID)
+
+	resp, err := a.get(ctx, a.BaseURL+"/release/"+mbid)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil // No images, or no such release
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cover art archive %w: release %s: %d", domain.ErrAPI, mbid, resp.StatusCode)
+	}
+	var release caaRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to parse cover art response: %w", err)
+	}
+
+	page := "https://musicbrainz.org/release/" + mbid + "/cover-art"
+	var candidates []Candidate
+	for _, img := range release.Images {
+		if (side == Front && !img.Front) || (side == Back && !img.Back) {
+			continue
+		}
+		c := Candidate{URL: img.Image, Page: page}
+		if thumb := img.Thumbnails[thumbnailFor(size)]; thumb != "" {
+			c.URL = thumb
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
+
+// thumbnailFor names the smallest thumbnail of at least size pixels, never
+// one smaller than MinDimension; "" when size is 0 or larger than them all.
+func thumbnailFor(size int) string {
+	if size <= 0 {
+		return ""
+	}
+	i := slices.IndexFunc(thumbnailSizes, func(n int) bool { return n >= max(size, MinDimension) })
+	if i < 0 {
+		return ""
+	}
+	return strconv.Itoa(thumbnailSizes[i])
+}
+
+// Download fetches the image, following the redirect to where it is stored.
+func (a *CoverArtArchive) Download(ctx context.Context, c Candidate) ([]byte, error) {
+	resp, err := a.get(ctx, c.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cover art archive %w: image %s: %d", domain.ErrAPI, c.URL, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func (a *CoverArtArchive) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
+	return a.HTTPClient.Do(req)
+}
@@ -0,0 +1,44 @@
+package artwork
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/discogs"
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// Discogs finds cover images of the album's Discogs release. Discogs doesn't
+// say which secondary image is the back, so it only offers the front.
+type Discogs struct {
+	Client *discogs.Client
+}
+
+func (d Discogs) Name() string { return "Discogs" }
+
+func (d Discogs) License() string {
+	return "used under the Discogs API terms; copyright remains with the rights holders"
+}
+
+// Candidates returns the release's primary image. Discogs serves each image
+// in one size, so size is ignored.
+func (d Discogs) Candidates(ctx context.Context, album *domain.Torrent, side Side, size int) ([]Candidate, error) {
+	if side != Front || album.Edition == nil || album.Edition.DiscogsReleaseID == 0 {
+		return nil, nil
+	}
+	release, err := d.Client.GetRelease(ctx, album.Edition.DiscogsReleaseID)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []Candidate
+	for _, img := range release.Images {
+		if strings.EqualFold(img.Type, "primary") && img.URI != "" {
+			candidates = append(candidates, Candidate{URL: img.URI, Page: release.URL() + "/image", Width: img.Width, Height: img.Height})
+		}
+	}
+	return candidates, nil
+}
+
+func (d Discogs) Download(ctx context.Context, c Candidate) ([]byte, error) {
+	return d.Client.Image(ctx, c.URL)
+}
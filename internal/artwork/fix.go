@@ -0,0 +1,90 @@
+package artwork
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+const (
+	// FixDimension is the longest side Fix downscales oversized covers to.
+	FixDimension = 1500
+	// fixQuality is the JPEG quality Fix encodes at.
+	fixQuality = 90
+)
+
+// Fix re-encodes cover, found in dir, as a baseline RGB JPEG no larger than
+// FixDimension on its longest side. A JPEG is replaced in place; a PNG is written
+// next to it as a .jpg and then removed. It returns the cover as rewritten;
+// covers that are not Fixable are returned unchanged.
+func Fix(files fsys.FS, dir string, cover *Cover) (*Cover, error) {
+	files = fsys.Or(files)
+	if !cover.Fixable() {
+		return cover, nil
+	}
+
+	from := filepath.Join(dir, filepath.FromSlash(cover.Path))
+	data, err := files.ReadFile(from)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", cover.Path, err)
+	}
+
+	if long := max(cover.Width, cover.Height); long > FixDimension {
+		img = downscale(img, cover.Width*FixDimension/long, cover.Height*FixDimension/long)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: fixQuality}); err != nil {
+		return nil, fmt.Errorf("%s: %w", cover.Path, err)
+	}
+
+	name := cover.Path
+	if cover.Format != "jpeg" {
+		name = strings.TrimSuffix(name, path.Ext(name)) + ".jpg"
+	}
+	to := filepath.Join(dir, filepath.FromSlash(name))
+	if err := files.WriteFile(to, buf.Bytes(), 0644); err != nil {
+		return nil, err
+	}
+	if to != from {
+		if err := files.Remove(from); err != nil {
+			return nil, err
+		}
+	}
+	return Inspect(files, dir, name)
+}
+
+// downscale resizes img to width×height by averaging the source pixels each
+// destination pixel covers, which avoids the aliasing of nearest-neighbour sampling.
+func downscale(img image.Image, width, height int) image.Image {
+	src := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		y0 := src.Min.Y + y*src.Dy()/height
+		y1 := max(src.Min.Y+(y+1)*src.Dy()/height, y0+1)
+		for x := range width {
+			x0 := src.Min.X + x*src.Dx()/width
+			x1 := max(src.Min.X+(x+1)*src.Dx()/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa), n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{uint16(r / n), uint16(g / n), uint16(b / n), uint16(a / n)})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,148 @@
+package artwork
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+// Side is the side of the album an image shows.
+type Side string
+
+const (
+	Front Side = "front"
+	Back  Side = "back"
+)
+
+// ParseSides parses a comma-separated list of sides, e.g. "front,back".
+func ParseSides(s string) ([]Side, error) {
+	var sides []Side
+	for _, name := range strings.Split(s, ",") {
+		switch side := Side(strings.ToLower(strings.TrimSpace(name))); side {
+		case Front, Back:
+			sides = append(sides, side)
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown cover side %q (want front or back)", name)
+		}
+	}
+	return sides, nil
+}
+
+// fileName is the name an image of the side in format is saved as: cover.jpg
+// for the front, back.jpg for the back.
+func (s Side) fileName(format string) string {
+	base := "cover"
+	if s == Back {
+		base = "back"
+	}
+	if format == "png" {
+		return base + ".png"
+	}
+	return base + ".jpg"
+}
+
+// Candidate is an image a source offers for one side of an album.
+type Candidate struct {
+	URL    string
+	Page   string // Where the source shows the image with its credits
+	Width  int    // 0 when the source doesn't say
+	Height int
+}
+
+// Source finds and downloads images of an album's covers.
+type Source interface {
+	// Name names the source in messages and credits, e.g. "Discogs".
+	Name() string
+	// License describes the terms the source's images are offered under.
+	License() string
+	// Candidates returns the source's images of side for album, best first,
+	// preferring those size pixels on their longest side or larger (0: the
+	// largest). No images, or no release to look them up by, is not an error.
+	Candidates(ctx context.Context, album *domain.Torrent, side Side, size int) ([]Candidate, error)
+	// Download returns the image data of a candidate.
+	Download(ctx context.Context, c Candidate) ([]byte, error)
+}
+
+// Fetched is an image downloaded for one side of an album.
+type Fetched struct {
+	Data   []byte
+	Cover  *Cover // Path is the file name to save it as
+	Credit domain.ArtworkCredit
+}
+
+// Fetch tries each source in turn and returns the first image of side that is
+// usable: not a thumbnail and, for the front, not a spread. Problems Fix
+// addresses, such as oversized or progressive images, don't disqualify one.
+// Sources that fail are logged and skipped; it returns nil when none has a
+// usable image.
+func Fetch(ctx context.Context, sources []Source, album *domain.Torrent, side Side, size int, log func(string, ...any)) (*Fetched, error) {
+	if log == nil {
+		log = func(string, ...any) {}
+	}
+	for _, source := range sources {
+		candidates, err := source.Candidates(ctx, album, side, size)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log("%s: %v", source.Name(), err)
+			continue
+		}
+		for _, c := range candidates {
+			data, err := source.Download(ctx, c)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				log("%s: %v", source.Name(), err)
+				continue
+			}
+			cover, err := inspect(c.URL, data)
+			if err != nil {
+				log("%s: %v", source.Name(), err)
+				continue
+			}
+			if problem := unusable(cover, side); problem != "" {
+				log("%s %s image %s", source.Name(), side, problem)
+				continue
+			}
+			cover.Path = side.fileName(cover.Format)
+			return &Fetched{
+				Data:  data,
+				Cover: cover,
+				Credit: domain.ArtworkCredit{
+					File:    cover.Path,
+					Side:    string(side),
+					Source:  source.Name(),
+					URL:     c.URL,
+					Page:    c.Page,
+					License: source.License(),
+				},
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+// unusable describes why cover won't do for side, or returns "" when it will.
+// Backs are tray inserts as often as not, so only the front must be square.
+func unusable(cover *Cover, side Side) string {
+	switch {
+	case cover.Width < MinDimension || cover.Height < MinDimension:
+		return fmt.Sprintf("is %d×%d, smaller than %d×%d", cover.Width, cover.Height, MinDimension, MinDimension)
+	case side == Front && cover.aspect() > MaxAspect:
+		return fmt.Sprintf("is %d×%d, not square", cover.Width, cover.Height)
+	}
+	return ""
+}
+
+// Save writes the fetched image into dir as f.Cover.Path, replacing any file
+// of that name.
+func (f *Fetched) Save(files fsys.FS, dir string) error {
+	return fsys.Or(files).WriteFile(filepath.Join(dir, f.Cover.Path), f.Data, 0644)
+}
@@ -0,0 +1,224 @@
+package artwork
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+// jpegData encodes a width×height grey JPEG.
+func jpegData(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// stubSource offers images by URL, failing to list them when err is set.
+type stubSource struct {
+	name   string
+	images map[string][]byte
+	order  []string
+	err    error
+}
+
+func (s stubSource) Name() string    { return s.name }
+func (s stubSource) License() string { return s.name + " terms" }
+
+func (s stubSource) Candidates(context.Context, *domain.Torrent, Side, int) ([]Candidate, error) {
+	var candidates []Candidate
+	for _, url := range s.order {
+		candidates = append(candidates, Candidate{URL: url, Page: "https://example.com/" + s.name})
+	}
+	return candidates, s.err
+}
+
+func (s stubSource) Download(_ context.Context, c Candidate) ([]byte, error) {
+	if data, ok := s.images[c.URL]; ok {
+		return data, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestParseSides(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []Side
+		wantErr bool
+	}{
+		{"front", []Side{Front}, false},
+		{"Front, back", []Side{Front, Back}, false},
+		{"", nil, false},
+		{"spine", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSides(tt.in)
+		if (err != nil) != tt.wantErr || !slices.Equal(got, tt.want) {
+			t.Errorf("ParseSides(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	thumb := jpegData(t, 300, 300)
+	spread := jpegData(t, 1400, 700)
+	good := jpegData(t, 800, 800)
+
+	tests := []struct {
+		name     string
+		sources  []Source
+		side     Side
+		wantFrom string // Credited source; "" for nothing fetched
+		wantFile string
+		wantLog  []string
+	}{
+		{
+			name: "first source usable",
+			sources: []Source{
+				stubSource{name: "Discogs", images: map[string][]byte{"d1": good}, order: []string{"d1"}},
+				stubSource{name: "Cover Art Archive", images: map[string][]byte{"c1": good}, order: []string{"c1"}},
+			},
+			side: Front, wantFrom: "Discogs", wantFile: "cover.jpg",
+		},
+		{
+			name: "thumbnail and spread skipped",
+			sources: []Source{
+				stubSource{name: "Discogs", images: map[string][]byte{"d1": thumb, "d2": spread}, order: []string{"d1", "d2", "missing"}},
+				stubSource{name: "Cover Art Archive", images: map[string][]byte{"c1": good}, order: []string{"c1"}},
+			},
+			side: Front, wantFrom: "Cover Art Archive", wantFile: "cover.jpg",
+			wantLog: []string{"Discogs front image is 300×300, smaller than 500×500", "Discogs front image is 1400×700, not square", "Discogs: not found"},
+		},
+		{
+			name: "back may be oblong",
+			sources: []Source{
+				stubSource{name: "Cover Art Archive", images: map[string][]byte{"c1": spread}, order: []string{"c1"}},
+			},
+			side: Back, wantFrom: "Cover Art Archive", wantFile: "back.jpg",
+		},
+		{
+			name: "failing source skipped",
+			sources: []Source{
+				stubSource{name: "Discogs", err: errors.New("release 1 not found")},
+			},
+			side:    Front,
+			wantLog: []string{"Discogs: release 1 not found"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var log []string
+			logf := func(format string, args ...any) { log = append(log, fmt.Sprintf(format, args...)) }
+			got, err := Fetch(context.Background(), tt.sources, &domain.Torrent{}, tt.side, 0, logf)
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if !slices.Equal(log, tt.wantLog) {
+				t.Errorf("Fetch() logged %q, want %q", log, tt.wantLog)
+			}
+			if tt.wantFrom == "" {
+				if got != nil {
+					t.Errorf("Fetch() = %+v, want nil", got.Credit)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("Fetch() = nil")
+			}
+			want := domain.ArtworkCredit{
+				File: tt.wantFile, Side: string(tt.side), Source: tt.wantFrom, URL: got.Credit.URL,
+				Page: "https://example.com/" + tt.wantFrom, License: tt.wantFrom + " terms",
+			}
+			if got.Credit != want || got.Cover.Path != tt.wantFile {
+				t.Errorf("Fetch() credit = %+v, want %+v", got.Credit, want)
+			}
+
+			mem := fsys.NewMem()
+			mem.MkdirAll(album, 0755)
+			if err := got.Save(mem, album); err != nil {
+				t.Fatal(err)
+			}
+			find := Find
+			if tt.side == Back {
+				find = FindBack
+			}
+			if saved, err := find(mem, album); err != nil || saved == nil || saved.Path != tt.wantFile {
+				t.Errorf("saved image not found: %+v, %v", saved, err)
+			}
+		})
+	}
+}
+
+func TestCoverArtArchive(t *testing.T) {
+	const mbid = "5f1b3c1e-0000-4000-8000-000000000001"
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("request without a User-Agent")
+		}
+		switch r.URL.Path {
+		case "/release/" + mbid:
+			fmt.Fprintf(w, `{"images": [
+				{"front": false, "back": true, "image": "%[1]s/back.jpg", "thumbnails": {"250": "%[1]s/back-250.jpg", "500": "%[1]s/back-500.jpg", "1200": "%[1]s/back-1200.jpg"}},
+				{"front": true, "back": false, "image": "%[1]s/front.jpg", "thumbnails": {"250": "%[1]s/front-250.jpg", "500": "%[1]s/front-500.jpg", "1200": "%[1]s/front-1200.jpg"}}
+			]}`, server.URL)
+		case "/front-1200.jpg":
+			w.Write(jpegData(t, 1200, 1200))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	caa := &CoverArtArchive{BaseURL: server.URL, HTTPClient: server.Client()}
+	ctx := context.Background()
+	album := &domain.Torrent{Edition: &domain.Edition{MusicBrainzAlbumID: mbid}}
+
+	tests := []struct {
+		side Side
+		size int
+		want string
+	}{
+		{Front, 0, "/front.jpg"},
+		{Front, 100, "/front-500.jpg"}, // Never a thumbnail
+		{Front, 1000, "/front-1200.jpg"},
+		{Front, 2000, "/front.jpg"},
+		{Back, 500, "/back-500.jpg"},
+	}
+	for _, tt := range tests {
+		got, err := caa.Candidates(ctx, album, tt.side, tt.size)
+		if err != nil {
+			t.Fatalf("Candidates(%s, %d) error = %v", tt.side, tt.size, err)
+		}
+		if len(got) != 1 || got[0].URL != server.URL+tt.want || !strings.HasSuffix(got[0].Page, mbid+"/cover-art") {
+			t.Errorf("Candidates(%s, %d) = %+v, want %s", tt.side, tt.size, got, tt.want)
+		}
+	}
+
+	got, err := Fetch(ctx, []Source{caa}, album, Front, 1000, nil)
+	if err != nil || got == nil {
+		t.Fatalf("Fetch() = %v, %v", got, err)
+	}
+	if got.Cover.Width != 1200 || got.Credit.Source != "Cover Art Archive" {
+		t.Errorf("Fetch() = %+v, %+v", got.Cover, got.Credit)
+	}
+
+	// Releases without images, or without an MBID, have no candidates
+	for _, a := range []*domain.Torrent{{Edition: &domain.Edition{MusicBrainzAlbumID: "no-art"}}, {}} {
+		if got, err := caa.Candidates(ctx, a, Front, 0); err != nil || got != nil {
+			t.Errorf("Candidates(%+v) = %v, %v; want none", a.Edition, got, err)
+		}
+	}
+}
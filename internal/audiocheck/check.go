@@ -0,0 +1,231 @@
+// Package audiocheck decodes FLAC files in full to find damage that tag-level
+// checks cannot see: corrupt or truncated frames, audio that does not match the
+// STREAMINFO MD5 signature, clipping and DC offset.
+package audiocheck
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"github.com/go-flac/go-flac"
+)
+
+const (
+	// ClipRun is how many consecutive full-scale samples count as one clip.
+	ClipRun = 3
+	// MaxDCOffset is the largest mean sample value, as a fraction of full scale,
+	// not reported as a DC offset (about -46 dBFS).
+	MaxDCOffset = 0.005
+	// maxListedFrames caps how many corrupt frames Errors lists individually.
+	maxListedFrames = 5
+)
+
+// FrameError is a frame that could not be decoded.
+type FrameError struct {
+	Frame  int   // Index of the frame in the stream, counting good and corrupt frames
+	Offset int64 // Byte offset from the first frame
+	Err    error
+}
+
+func (e FrameError) Error() string {
+	return fmt.Sprintf("frame %d (byte %d): %v", e.Frame, e.Offset, e.Err)
+}
+
+func (e FrameError) Unwrap() error { return e.Err }
+
+// Result is the outcome of decoding one file.
+type Result struct {
+	Path            string
+	SampleRate      int
+	Channels        int
+	BitsPerSample   int
+	Frames          int   // Frames decoded successfully
+	Samples         int64 // Samples per channel decoded
+	ExpectedSamples int64 // From STREAMINFO; 0 when unknown
+	Corrupt         []FrameError
+	MD5Checked      bool // False when STREAMINFO has no MD5 signature
+	MD5OK           bool
+	ClippedRuns     []int     // Per channel
+	DCOffset        []float64 // Per channel, mean sample as a fraction of full scale
+}
+
+// Errors describes damage to the audio: corrupt frames, a sample count or MD5
+// signature that does not match STREAMINFO. Empty when the audio is intact.
+func (r *Result) Errors() []string {
+	var errs []string
+	for i, fe := range r.Corrupt {
+		if i == maxListedFrames {
+			errs = append(errs, fmt.Sprintf("%d more corrupt frames", len(r.Corrupt)-i))
+			break
+		}
+		errs = append(errs, "corrupt "+fe.Error())
+	}
+	if r.ExpectedSamples > 0 && r.Samples != r.ExpectedSamples {
+		errs = append(errs, fmt.Sprintf("decoded %d samples, STREAMINFO says %d", r.Samples, r.ExpectedSamples))
+	}
+	if r.MD5Checked && !r.MD5OK {
+		errs = append(errs, "audio MD5 does not match STREAMINFO")
+	}
+	return errs
+}
+
+// Warnings describes signs of a poor transfer or master: clipping and DC offset.
+func (r *Result) Warnings() []string {
+	var warnings []string
+	for ch, runs := range r.ClippedRuns {
+		if runs > 0 {
+			warnings = append(warnings, fmt.Sprintf("channel %d clips %d times", ch+1, runs))
+		}
+	}
+	for ch, dc := range r.DCOffset {
+		if math.Abs(dc) > MaxDCOffset {
+			warnings = append(warnings, fmt.Sprintf("channel %d has a DC offset of %+.2f%% of full scale", ch+1, dc*100))
+		}
+	}
+	return warnings
+}
+
+// Check decodes the FLAC file at path. Decoding problems are reported in the
+// Result; the error is for files that cannot be read or are not FLAC at all.
+func Check(path string) (*Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	meta, err := flac.ParseMetadata(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FLAC metadata: %w", err)
+	}
+	info, err := meta.GetStreamInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse STREAMINFO: %w", err)
+	}
+
+	res := &Result{
+		Path:            path,
+		SampleRate:      info.SampleRate,
+		Channels:        info.ChannelCount,
+		BitsPerSample:   info.BitDepth,
+		ExpectedSamples: info.SampleCount,
+		MD5Checked:      !bytes.Equal(info.AudioMD5, make([]byte, md5.Size)),
+		ClippedRuns:     make([]int, info.ChannelCount),
+		DCOffset:        make([]float64, info.ChannelCount),
+	}
+	stats := newStats(info.ChannelCount, info.BitDepth)
+	sum := md5.New()
+	var pcm []byte
+
+	br := &bitReader{r: bufio.NewReaderSize(f, 1<<20)}
+	var fr frame
+	resyncing := false // After a corrupt frame, skipped bytes and false syncs are part of the damage
+	for {
+		start := br.offset
+		skipped, err := br.syncFrame()
+		if skipped > 0 && !resyncing {
+			res.Corrupt = append(res.Corrupt, FrameError{Frame: res.Frames + len(res.Corrupt), Offset: start, Err: fmt.Errorf("%w: %d bytes skipped", ErrLostSync, skipped)})
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start = br.offset - 2
+		if err := br.readFrame(info.BitDepth, &fr); err != nil {
+			if !resyncing {
+				if errors.Is(err, io.ErrUnexpectedEOF) {
+					err = fmt.Errorf("truncated: %w", err)
+				}
+				res.Corrupt = append(res.Corrupt, FrameError{Frame: res.Frames + len(res.Corrupt), Offset: start, Err: err})
+			}
+			resyncing = true
+			continue
+		}
+		resyncing = false
+
+		res.Frames++
+		res.Samples += int64(fr.blockSize)
+		pcm = appendPCM(pcm[:0], &fr)
+		sum.Write(pcm)
+		stats.add(fr.samples)
+	}
+
+	res.MD5OK = bytes.Equal(sum.Sum(nil), info.AudioMD5)
+	stats.result(res)
+	return res, nil
+}
+
+// appendPCM appends the frame's samples interleaved, little-endian, in whole
+// bytes: the form the STREAMINFO MD5 signature is computed over.
+func appendPCM(pcm []byte, f *frame) []byte {
+	width := (f.bitsPerSample + 7) / 8
+	for i := 0; i < f.blockSize; i++ {
+		for _, ch := range f.samples {
+			v := ch[i]
+			for b := 0; b < width; b++ {
+				pcm = append(pcm, byte(v>>(8*b)))
+			}
+		}
+	}
+	return pcm
+}
+
+// stats accumulates per-channel clipping and DC offset.
+type stats struct {
+	full    int64 // Full scale: 1 << (bits per sample - 1)
+	sums    []int64
+	runs    []int // Current run of full-scale samples
+	clipped []int
+	samples int64
+}
+
+func newStats(channels, bitsPerSample int) *stats {
+	return &stats{
+		full:    int64(1) << (bitsPerSample - 1),
+		sums:    make([]int64, channels),
+		runs:    make([]int, channels),
+		clipped: make([]int, channels),
+	}
+}
+
+func (s *stats) add(samples [][]int32) {
+	if len(samples) == 0 {
+		return
+	}
+	for ch, block := range samples {
+		if ch >= len(s.sums) {
+			break
+		}
+		for _, v := range block {
+			v := int64(v)
+			s.sums[ch] += v
+			if v >= s.full-1 || v <= -s.full {
+				if s.runs[ch]++; s.runs[ch] == ClipRun {
+					s.clipped[ch]++
+				}
+			} else {
+				s.runs[ch] = 0
+			}
+		}
+	}
+	s.samples += int64(len(samples[0]))
+}
+
+func (s *stats) result(r *Result) {
+	copy(r.ClippedRuns, s.clipped)
+	if s.samples == 0 {
+		return
+	}
+	for ch, sum := range s.sums {
+		r.DCOffset[ch] = float64(sum) / float64(s.samples) / float64(s.full)
+	}
+}
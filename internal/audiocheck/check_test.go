@@ -0,0 +1,309 @@
+package audiocheck
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// bitWriter writes big-endian bit fields for the test encoder.
+type bitWriter struct {
+	buf  []byte
+	acc  uint64
+	nacc uint
+}
+
+func (w *bitWriter) write(v uint64, n uint) {
+	for i := int(n) - 1; i >= 0; i-- {
+		w.acc = w.acc<<1 | v>>uint(i)&1
+		if w.nacc++; w.nacc == 8 {
+			w.buf = append(w.buf, byte(w.acc))
+			w.acc, w.nacc = 0, 0
+		}
+	}
+}
+
+func (w *bitWriter) writeSigned(v int64, n uint) { w.write(uint64(v)&(1<<n-1), n) }
+
+func (w *bitWriter) align() {
+	for w.nacc != 0 {
+		w.write(0, 1)
+	}
+}
+
+func (w *bitWriter) writeRice(residual []int32, k uint) {
+	w.write(0, 2) // 4-bit Rice parameters
+	w.write(0, 4) // Partition order 0
+	w.write(uint64(k), 4)
+	for _, r := range residual {
+		u := uint64(r<<1 ^ r>>31)
+		for q := u >> k; q > 0; q-- {
+			w.write(0, 1)
+		}
+		w.write(1, 1)
+		w.write(u&(1<<k-1), k)
+	}
+}
+
+// subframe kinds the test encoder writes
+const (
+	verbatim = iota
+	fixed2
+	lpc1
+	constant
+)
+
+func writeSubframe(w *bitWriter, kind int, s []int32, bps uint) {
+	switch kind {
+	case constant:
+		w.write(0, 8)
+		w.writeSigned(int64(s[0]), bps)
+	case verbatim:
+		w.write(1<<1, 8)
+		for _, v := range s {
+			w.writeSigned(int64(v), bps)
+		}
+	case fixed2:
+		w.write((8+2)<<1, 8)
+		w.writeSigned(int64(s[0]), bps)
+		w.writeSigned(int64(s[1]), bps)
+		residual := make([]int32, 0, len(s))
+		for i := 2; i < len(s); i++ {
+			residual = append(residual, s[i]-(2*s[i-1]-s[i-2]))
+		}
+		w.writeRice(residual, 8)
+	case lpc1:
+		const precision, shift, coef = 15, 13, 1 << 13 // s[i] predicted as s[i-1]
+		w.write((32+0)<<1, 8)
+		w.writeSigned(int64(s[0]), bps)
+		w.write(precision-1, 4)
+		w.writeSigned(shift, 5)
+		w.writeSigned(coef, precision)
+		residual := make([]int32, 0, len(s))
+		for i := 1; i < len(s); i++ {
+			residual = append(residual, s[i]-int32(int64(coef)*int64(s[i-1])>>shift))
+		}
+		w.writeRice(residual, 8)
+	}
+}
+
+func crc8(data []byte) uint8 {
+	var c uint8
+	for _, b := range data {
+		c = crc8Table[c^b]
+	}
+	return c
+}
+
+func crc16(data []byte) uint16 {
+	var c uint16
+	for _, b := range data {
+		c = c<<8 ^ crc16Table[byte(c>>8)^b]
+	}
+	return c
+}
+
+// encodeFLAC encodes 16-bit stereo samples in 4096-sample frames (the last one
+// shorter), cycling through channel assignments and subframe kinds.
+func encodeFLAC(left, right []int32) []byte {
+	const block = 4096
+	var frames []byte
+	for n, start := 0, 0; start < len(left); n, start = n+1, start+block {
+		end := min(start+block, len(left))
+		l, r := left[start:end], right[start:end]
+
+		w := &bitWriter{}
+		w.write(0xFFF8, 16)
+		if end-start == block {
+			w.write(12, 4) // 4096
+		} else {
+			w.write(7, 4) // 16-bit size at end of header
+		}
+		w.write(9, 4) // 44.1 kHz
+		assignment := []uint64{1, leftSide, midSide}[n%3]
+		w.write(assignment, 4)
+		w.write(4<<1, 4) // 16 bits, reserved bit
+		w.write(uint64(n), 8)
+		if end-start != block {
+			w.write(uint64(end-start-1), 16)
+		}
+		w.write(uint64(crc8(w.buf)), 8)
+
+		switch assignment {
+		case 1:
+			writeSubframe(w, fixed2, l, 16)
+			writeSubframe(w, verbatim, r, 16)
+		case leftSide:
+			side := make([]int32, len(l))
+			for i := range l {
+				side[i] = l[i] - r[i]
+			}
+			writeSubframe(w, lpc1, l, 16)
+			writeSubframe(w, fixed2, side, 17)
+		case midSide:
+			mid, side := make([]int32, len(l)), make([]int32, len(l))
+			for i := range l {
+				mid[i], side[i] = (l[i]+r[i])>>1, l[i]-r[i]
+			}
+			if allEqual(mid) {
+				writeSubframe(w, constant, mid, 16)
+			} else {
+				writeSubframe(w, lpc1, mid, 16)
+			}
+			writeSubframe(w, verbatim, side, 17)
+		}
+		w.align()
+		w.write(uint64(crc16(w.buf)), 16)
+		frames = append(frames, w.buf...)
+	}
+
+	sum := md5.New()
+	for i := range left {
+		sum.Write([]byte{byte(left[i]), byte(left[i] >> 8), byte(right[i]), byte(right[i] >> 8)})
+	}
+
+	info := &bitWriter{}
+	info.write(4096, 16)
+	info.write(4096, 16)
+	info.write(0, 24)
+	info.write(0, 24)
+	info.write(44100, 20)
+	info.write(2-1, 3)
+	info.write(16-1, 5)
+	info.write(uint64(len(left)), 36)
+	info.buf = append(info.buf, sum.Sum(nil)...)
+
+	out := []byte("fLaC")
+	out = append(out, 0x80, 0, 0, byte(len(info.buf)))
+	out = append(out, info.buf...)
+	return append(out, frames...)
+}
+
+func allEqual(s []int32) bool {
+	for _, v := range s {
+		if v != s[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// signal returns a stereo 440 Hz sine of the given amplitude plus dc, clipped
+// to 16 bits.
+func signal(samples int, amplitude, dc float64) (left, right []int32) {
+	clip := func(v float64) int32 { return int32(math.Max(-32768, math.Min(32767, math.Round(v)))) }
+	for i := range samples {
+		v := amplitude * math.Sin(2*math.Pi*440*float64(i)/44100)
+		left = append(left, clip(v+dc))
+		right = append(right, clip(v/2+dc))
+	}
+	return left, right
+}
+
+func checkBytes(t *testing.T, data []byte) *Result {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.flac")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Check(path)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	return res
+}
+
+func TestCheck_Intact(t *testing.T) {
+	left, right := signal(4096*5+1000, 10000, 0)
+	res := checkBytes(t, encodeFLAC(left, right))
+
+	if errs := res.Errors(); len(errs) != 0 {
+		t.Errorf("Errors() = %v", errs)
+	}
+	if warnings := res.Warnings(); len(warnings) != 0 {
+		t.Errorf("Warnings() = %v", warnings)
+	}
+	if res.Frames != 6 || res.Samples != int64(len(left)) || !res.MD5Checked || !res.MD5OK {
+		t.Errorf("Check() = %+v", res)
+	}
+}
+
+func TestCheck_Silence(t *testing.T) {
+	left, right := signal(4096*3, 0, 0)
+	if errs := checkBytes(t, encodeFLAC(left, right)).Errors(); len(errs) != 0 {
+		t.Errorf("Errors() = %v", errs)
+	}
+}
+
+func TestCheck_CorruptFrame(t *testing.T) {
+	left, right := signal(4096*4, 10000, 0)
+	data := encodeFLAC(left, right)
+	second := bytes.Index(data, []byte{0xFF, 0xF8, 0xC9, 0x88}) // Header of frame 1, the left/side one
+	data[second+400] ^= 0x10
+
+	res := checkBytes(t, data)
+	if len(res.Corrupt) != 1 || res.Corrupt[0].Frame != 1 {
+		t.Fatalf("Corrupt = %v, want frame 1", res.Corrupt)
+	}
+	if res.Frames != 3 || res.MD5OK {
+		t.Errorf("Frames = %d, MD5OK = %v; want 3 frames decoded and an MD5 mismatch", res.Frames, res.MD5OK)
+	}
+	if errs := res.Errors(); len(errs) != 3 {
+		t.Errorf("Errors() = %v, want corrupt frame, sample count and MD5", errs)
+	}
+}
+
+func TestCheck_Truncated(t *testing.T) {
+	left, right := signal(4096*2, 10000, 0)
+	data := encodeFLAC(left, right)
+
+	res := checkBytes(t, data[:len(data)-100])
+	if len(res.Corrupt) != 1 || !strings.Contains(res.Corrupt[0].Error(), "truncated") {
+		t.Errorf("Corrupt = %v, want a truncated frame", res.Corrupt)
+	}
+}
+
+func TestCheck_MD5Mismatch(t *testing.T) {
+	left, right := signal(4096, 10000, 0)
+	data := encodeFLAC(left, right)
+	data[8+18] ^= 0xFF // First byte of the STREAMINFO MD5
+
+	res := checkBytes(t, data)
+	if errs := res.Errors(); len(errs) != 1 || !strings.Contains(errs[0], "MD5") {
+		t.Errorf("Errors() = %v, want an MD5 mismatch", errs)
+	}
+}
+
+func TestCheck_ClippingAndDCOffset(t *testing.T) {
+	left, right := signal(4096*2, 40000, 500)
+	res := checkBytes(t, encodeFLAC(left, right))
+
+	if errs := res.Errors(); len(errs) != 0 {
+		t.Fatalf("Errors() = %v", errs)
+	}
+	if res.ClippedRuns[0] == 0 || res.ClippedRuns[1] != 0 {
+		t.Errorf("ClippedRuns = %v, want clipping on channel 1 only", res.ClippedRuns)
+	}
+	warnings := strings.Join(res.Warnings(), "\n")
+	for _, want := range []string{"channel 1 clips", "channel 2 has a DC offset of +1."} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("Warnings() = %q, missing %q", warnings, want)
+		}
+	}
+}
+
+func TestCheck_NotFLAC(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.flac")
+	os.WriteFile(path, []byte("ID3 not a flac file"), 0644)
+	if _, err := Check(path); err == nil {
+		t.Error("Check() on a non-FLAC file should fail")
+	}
+	if _, err := Check(filepath.Join(t.TempDir(), "missing.flac")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Check() error = %v, want not exist", err)
+	}
+}
@@ -0,0 +1,483 @@
+package audiocheck
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// Frame decoding errors. Any of these marks the frame as corrupt.
+var (
+	ErrHeaderCRC  = errors.New("frame header CRC mismatch")
+	ErrFrameCRC   = errors.New("frame CRC mismatch")
+	ErrBadHeader  = errors.New("invalid frame header")
+	ErrBadSubtype = errors.New("invalid subframe")
+	ErrLostSync   = errors.New("data between frames")
+)
+
+// bitReader reads big-endian bit fields, keeping running CRC-8 and CRC-16 of the
+// bytes consumed since the last reset.
+type bitReader struct {
+	r      *bufio.Reader
+	acc    uint64 // Low n bits are unread
+	n      uint
+	crc8   uint8
+	crc16  uint16
+	offset int64 // Bytes consumed
+}
+
+func (br *bitReader) fill() error {
+	b, err := br.r.ReadByte()
+	if err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	br.acc = br.acc<<8 | uint64(b)
+	br.n += 8
+	br.update(b)
+	br.offset++
+	return nil
+}
+
+// update adds b to the running CRCs.
+func (br *bitReader) update(b byte) {
+	br.crc8 = crc8Table[br.crc8^b]
+	br.crc16 = br.crc16<<8 ^ crc16Table[byte(br.crc16>>8)^b]
+}
+
+// read returns the next k (at most 56) bits.
+func (br *bitReader) read(k uint) (uint64, error) {
+	for br.n < k {
+		if err := br.fill(); err != nil {
+			return 0, err
+		}
+	}
+	br.n -= k
+	v := br.acc >> br.n
+	br.acc &= 1<<br.n - 1
+	return v, nil
+}
+
+// readSigned returns the next k bits as a two's complement integer.
+func (br *bitReader) readSigned(k uint) (int64, error) {
+	if k == 0 {
+		return 0, nil
+	}
+	v, err := br.read(k)
+	if err != nil {
+		return 0, err
+	}
+	return int64(v<<(64-k)) >> (64 - k), nil
+}
+
+// readUnary returns the number of 0 bits before the next 1 bit.
+func (br *bitReader) readUnary() (uint64, error) {
+	var count uint64
+	for {
+		if br.n == 0 {
+			if err := br.fill(); err != nil {
+				return 0, err
+			}
+		}
+		if br.acc == 0 {
+			count += uint64(br.n)
+			br.n = 0
+			continue
+		}
+		l := uint(bits.Len64(br.acc))
+		count += uint64(br.n - l)
+		br.n = l - 1
+		br.acc &= 1<<br.n - 1
+		return count, nil
+	}
+}
+
+// align discards bits up to the next byte boundary.
+func (br *bitReader) align() {
+	br.n -= br.n % 8
+	br.acc &= 1<<br.n - 1
+}
+
+// frame is one decoded FLAC frame.
+type frame struct {
+	blockSize     int
+	bitsPerSample int
+	samples       [][]int32 // Per channel
+}
+
+// channel assignments beyond the independent ones
+const (
+	leftSide  = 8
+	sideRight = 9
+	midSide   = 10
+)
+
+// syncFrame consumes bytes up to and including the next frame sync code and
+// returns how many bytes it skipped to find it (0 when the reader was at one).
+// Returns io.EOF when the stream ends first.
+func (br *bitReader) syncFrame() (skipped int64, err error) {
+	br.align()
+	var prev uint64
+	for {
+		if br.n == 0 {
+			if _, err := br.r.Peek(1); err == io.EOF {
+				return skipped, io.EOF
+			}
+		}
+		b, err := br.read(8)
+		if err != nil {
+			return skipped, err
+		}
+		if prev == 0xFF && b&0xFE == 0xF8 {
+			br.crc8, br.crc16 = 0, 0
+			br.update(0xFF)
+			br.update(byte(b))
+			return skipped - 1, nil
+		}
+		prev = b
+		skipped++
+	}
+}
+
+// readFrame decodes the frame whose sync code was just consumed.
+func (br *bitReader) readFrame(streamBPS int, f *frame) error {
+	hdr, err := br.read(16)
+	if err != nil {
+		return err
+	}
+	blockCode := hdr >> 12
+	rateCode := hdr >> 8 & 0xF
+	assignment := int(hdr >> 4 & 0xF)
+	sizeCode := hdr >> 1 & 0x7
+	if blockCode == 0 || rateCode == 15 || assignment > midSide || sizeCode == 3 || hdr&1 != 0 {
+		return ErrBadHeader
+	}
+
+	// Frame or sample number, UTF-8 coded
+	lead, err := br.read(8)
+	if err != nil {
+		return err
+	}
+	ones := bits.LeadingZeros8(^uint8(lead))
+	if ones == 1 || ones > 7 {
+		return ErrBadHeader
+	}
+	for i := 1; i < ones; i++ {
+		if _, err := br.read(8); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case blockCode == 1:
+		f.blockSize = 192
+	case blockCode <= 5:
+		f.blockSize = 576 << (blockCode - 2)
+	case blockCode == 6:
+		v, err := br.read(8)
+		if err != nil {
+			return err
+		}
+		f.blockSize = int(v) + 1
+	case blockCode == 7:
+		v, err := br.read(16)
+		if err != nil {
+			return err
+		}
+		f.blockSize = int(v) + 1
+	default:
+		f.blockSize = 256 << (blockCode - 8)
+	}
+	switch rateCode {
+	case 12:
+		_, err = br.read(8)
+	case 13, 14:
+		_, err = br.read(16)
+	}
+	if err != nil {
+		return err
+	}
+
+	f.bitsPerSample = [8]int{streamBPS, 8, 12, 0, 16, 20, 24, 32}[sizeCode]
+
+	want := br.crc8
+	got, err := br.read(8)
+	if err != nil {
+		return err
+	}
+	if uint8(got) != want {
+		return ErrHeaderCRC
+	}
+
+	channels := assignment + 1
+	if assignment >= leftSide {
+		channels = 2
+	}
+	for len(f.samples) < channels {
+		f.samples = append(f.samples, nil)
+	}
+	f.samples = f.samples[:channels]
+	for ch := range f.samples {
+		if cap(f.samples[ch]) < f.blockSize {
+			f.samples[ch] = make([]int32, f.blockSize)
+		}
+		f.samples[ch] = f.samples[ch][:f.blockSize]
+
+		bps := f.bitsPerSample
+		if (assignment == leftSide || assignment == midSide) && ch == 1 || assignment == sideRight && ch == 0 {
+			bps++ // Side channel
+		}
+		if err := br.readSubframe(uint(bps), f.samples[ch]); err != nil {
+			return err
+		}
+	}
+
+	br.align()
+	wantCRC := br.crc16
+	gotCRC, err := br.read(16)
+	if err != nil {
+		return err
+	}
+	if uint16(gotCRC) != wantCRC {
+		return ErrFrameCRC
+	}
+
+	decorrelate(assignment, f.samples)
+	return nil
+}
+
+func decorrelate(assignment int, s [][]int32) {
+	switch assignment {
+	case leftSide:
+		for i := range s[0] {
+			s[1][i] = s[0][i] - s[1][i]
+		}
+	case sideRight:
+		for i := range s[0] {
+			s[0][i] += s[1][i]
+		}
+	case midSide:
+		for i := range s[0] {
+			side := s[1][i]
+			mid := s[0][i]<<1 | side&1
+			s[0][i], s[1][i] = (mid+side)>>1, (mid-side)>>1
+		}
+	}
+}
+
+func (br *bitReader) readSubframe(bps uint, out []int32) error {
+	hdr, err := br.read(8)
+	if err != nil {
+		return err
+	}
+	if hdr&0x80 != 0 {
+		return ErrBadSubtype
+	}
+	var wasted uint
+	if hdr&1 != 0 {
+		k, err := br.readUnary()
+		if err != nil {
+			return err
+		}
+		wasted = uint(k) + 1
+		if wasted >= bps {
+			return ErrBadSubtype
+		}
+		bps -= wasted
+	}
+
+	switch kind := hdr >> 1 & 0x3F; {
+	case kind == 0:
+		v, err := br.readSigned(bps)
+		if err != nil {
+			return err
+		}
+		for i := range out {
+			out[i] = int32(v)
+		}
+	case kind == 1:
+		for i := range out {
+			v, err := br.readSigned(bps)
+			if err != nil {
+				return err
+			}
+			out[i] = int32(v)
+		}
+	case kind >= 8 && kind <= 12:
+		if err := br.readFixed(bps, int(kind-8), out); err != nil {
+			return err
+		}
+	case kind >= 32:
+		if err := br.readLPC(bps, int(kind-31), out); err != nil {
+			return err
+		}
+	default:
+		return ErrBadSubtype
+	}
+
+	if wasted > 0 {
+		for i := range out {
+			out[i] <<= wasted
+		}
+	}
+	return nil
+}
+
+func (br *bitReader) readWarmup(bps uint, order int, out []int32) error {
+	if order > len(out) {
+		return ErrBadSubtype
+	}
+	for i := 0; i < order; i++ {
+		v, err := br.readSigned(bps)
+		if err != nil {
+			return err
+		}
+		out[i] = int32(v)
+	}
+	return nil
+}
+
+func (br *bitReader) readFixed(bps uint, order int, out []int32) error {
+	if err := br.readWarmup(bps, order, out); err != nil {
+		return err
+	}
+	if err := br.readResidual(order, out); err != nil {
+		return err
+	}
+	for i := order; i < len(out); i++ {
+		switch order {
+		case 1:
+			out[i] += out[i-1]
+		case 2:
+			out[i] += 2*out[i-1] - out[i-2]
+		case 3:
+			out[i] += 3*out[i-1] - 3*out[i-2] + out[i-3]
+		case 4:
+			out[i] += 4*out[i-1] - 6*out[i-2] + 4*out[i-3] - out[i-4]
+		}
+	}
+	return nil
+}
+
+func (br *bitReader) readLPC(bps uint, order int, out []int32) error {
+	if err := br.readWarmup(bps, order, out); err != nil {
+		return err
+	}
+	precision, err := br.read(4)
+	if err != nil {
+		return err
+	}
+	if precision == 15 {
+		return ErrBadSubtype
+	}
+	shift, err := br.readSigned(5)
+	if err != nil {
+		return err
+	}
+	if shift < 0 {
+		return ErrBadSubtype
+	}
+	coefs := make([]int64, order)
+	for i := range coefs {
+		if coefs[i], err = br.readSigned(uint(precision) + 1); err != nil {
+			return err
+		}
+	}
+	if err := br.readResidual(order, out); err != nil {
+		return err
+	}
+	for i := order; i < len(out); i++ {
+		var sum int64
+		for j, c := range coefs {
+			sum += c * int64(out[i-j-1])
+		}
+		out[i] += int32(sum >> shift)
+	}
+	return nil
+}
+
+// readResidual reads the Rice-coded residual for out[order:].
+func (br *bitReader) readResidual(order int, out []int32) error {
+	method, err := br.read(2)
+	if err != nil {
+		return err
+	}
+	if method > 1 {
+		return ErrBadSubtype
+	}
+	paramBits, escape := uint(4), uint64(15)
+	if method == 1 {
+		paramBits, escape = 5, 31
+	}
+	partOrder, err := br.read(4)
+	if err != nil {
+		return err
+	}
+	parts := 1 << partOrder
+	if len(out)%parts != 0 || len(out)/parts < order {
+		return ErrBadSubtype
+	}
+
+	i := order
+	for p := 0; p < parts; p++ {
+		end := (p + 1) * len(out) / parts
+		param, err := br.read(paramBits)
+		if err != nil {
+			return err
+		}
+		if param == escape {
+			raw, err := br.read(5)
+			if err != nil {
+				return err
+			}
+			for ; i < end; i++ {
+				v, err := br.readSigned(uint(raw))
+				if err != nil {
+					return err
+				}
+				out[i] = int32(v)
+			}
+			continue
+		}
+		for ; i < end; i++ {
+			q, err := br.readUnary()
+			if err != nil {
+				return err
+			}
+			r, err := br.read(uint(param))
+			if err != nil {
+				return err
+			}
+			u := q<<param | r
+			out[i] = int32(u>>1) ^ -int32(u&1)
+		}
+	}
+	return nil
+}
+
+var (
+	crc8Table  [256]uint8
+	crc16Table [256]uint16
+)
+
+func init() {
+	for i := range 256 {
+		c8, c16 := uint8(i), uint16(i)<<8
+		for range 8 {
+			if c8&0x80 != 0 {
+				c8 = c8<<1 ^ 0x07
+			} else {
+				c8 <<= 1
+			}
+			if c16&0x8000 != 0 {
+				c16 = c16<<1 ^ 0x8005
+			} else {
+				c16 <<= 1
+			}
+		}
+		crc8Table[i], crc16Table[i] = c8, c16
+	}
+}
@@ -6,13 +6,17 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/cehbz/classical-tagger/internal/clock"
 	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/fsys"
 	"github.com/gregjones/httpcache"
 	"github.com/gregjones/httpcache/diskcache"
 )
@@ -23,6 +27,16 @@ type Cache struct {
 	BaseDir       string
 	HTTPCache     httpcache.Cache
 	HTTPTransport *httpcache.Transport
+	FS            fsys.FS     // JSON data files (nil: the operating system)
+	Clock         clock.Clock // Timestamps and expiry (nil: clock.System)
+	Warnings      io.Writer   // Where removed corrupt entries are reported (nil: standard error)
+}
+
+// entry is the envelope a cached value is saved in.
+type entry struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Data      json.RawMessage `json:"data"`
+	Key       string          `json:"original_key"` // Store original key for reference
 }
 
 // NewCache creates a new cache with the specified TTL
@@ -107,7 +121,7 @@ func (c *Cache) GetCacheDir(appName string) string {
 		return ""
 	}
 	dir := filepath.Join(c.BaseDir, appName)
-	os.MkdirAll(dir, 0755)
+	c.fs().MkdirAll(dir, 0755)
 	return dir
 }
 
@@ -132,26 +146,34 @@ func (c *Cache) SaveTo(key string, data any, appName string) error {
 	safeKey := c.sanitizeKey(key)
 	path := filepath.Join(dir, safeKey+".json")
 
-	file, err := os.Create(path)
+	raw, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	// Create wrapper with timestamp
-	wrapper := struct {
-		Timestamp time.Time `json:"timestamp"`
-		Data      any       `json:"data"`
-		Key       string    `json:"original_key"` // Store original key for reference
-	}{
-		Timestamp: time.Now(),
-		Data:      data,
-		Key:       key,
+	encoded, err := json.MarshalIndent(entry{Timestamp: c.clock().Now(), Data: raw, Key: key}, "", "  ")
+	if err != nil {
+		return err
 	}
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(wrapper)
+	// Write beside the entry and rename into place, so an interrupted write
+	// never leaves a truncated entry behind
+	file, err := c.fs().CreateTemp(dir, "."+safeKey+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer c.fs().Remove(file.Name()) // No-op once renamed
+	if _, err := file.Write(append(encoded, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return c.fs().Rename(file.Name(), path)
 }
 
 // Load loads data from cache
@@ -173,50 +195,61 @@ func (c *Cache) LoadFrom(key string, target any, appName string) bool {
 	path := filepath.Join(dir, safeKey+".json")
 
 	// Check if file exists
-	info, err := os.Stat(path)
-	if os.IsNotExist(err) {
-		return false
-	}
+	info, err := c.fs().Stat(path)
 	if err != nil {
 		return false
 	}
 
 	// Check if cache is expired
-	if time.Since(info.ModTime()) > c.TTL {
+	if clock.Since(c.clock(), info.ModTime()) > c.TTL {
 		return false
 	}
 
-	// Load file
-	file, err := os.Open(path)
+	data, err := c.fs().ReadFile(path)
 	if err != nil {
 		return false
 	}
-	defer file.Close()
 
-	// Decode wrapper
-	var wrapper struct {
-		Timestamp time.Time       `json:"timestamp"`
-		Data      json.RawMessage `json:"data"`
-		Key       string          `json:"original_key"`
+	// An entry that doesn't decode was cut short by an interrupted write, or
+	// saved in a shape the caller no longer reads: remove it so it is fetched
+	// afresh, rather than failing every later load
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		c.discard(path, err)
+		return false
 	}
-
-	if err := json.NewDecoder(file).Decode(&wrapper); err != nil {
+	if e.Timestamp.IsZero() || len(e.Data) == 0 {
+		c.discard(path, fmt.Errorf("no timestamp or data"))
 		return false
 	}
 
 	// Check timestamp-based expiry
-	if time.Since(wrapper.Timestamp) > c.TTL {
+	if clock.Since(c.clock(), e.Timestamp) > c.TTL {
 		return false
 	}
 
 	// Decode actual data
-	if err := json.Unmarshal(wrapper.Data, target); err != nil {
+	if err := json.Unmarshal(e.Data, target); err != nil {
+		c.discard(path, err)
 		return false
 	}
 
 	return true
 }
 
+// discard removes a cache entry that failed to load, with a warning.
+func (c *Cache) discard(path string, reason error) {
+	w := c.Warnings
+	if w == nil {
+		w = os.Stderr
+	}
+	if err := c.fs().Remove(path); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(w, "Warning: corrupt cache entry %s (%v) could not be removed: %v\n", path, reason, err)
+		return
+	}
+	fmt.Fprintf(w, "Warning: removed corrupt cache entry %s: %v\n", path, reason)
+}
+
 // Clear removes all cached files for an app
 func (c *Cache) Clear(appName string) error {
 	if c == nil {
@@ -224,12 +257,12 @@ func (c *Cache) Clear(appName string) error {
 	}
 	dir := c.GetCacheDir(appName)
 
-	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	return c.fs().WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
-		if filepath.Ext(path) == ".json" || filepath.Ext(path) == ".torrent" {
-			return os.Remove(path)
+		if ext := filepath.Ext(path); ext == ".json" || ext == ".torrent" || ext == ".tmp" {
+			return c.fs().Remove(path)
 		}
 		return nil
 	})
@@ -247,13 +280,13 @@ func (c *Cache) ClearKey(key string, appName string) error {
 
 	safeKey := c.sanitizeKey(key)
 	path := filepath.Join(dir, safeKey+".json")
-	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+	if err := c.fs().Remove(path); err != nil && !os.IsNotExist(err) {
 		return err
 	}
 
 	// Also try to remove .torrent files with same key
 	torrentPath := filepath.Join(dir, safeKey+".torrent")
-	if err := os.Remove(torrentPath); err != nil && !os.IsNotExist(err) {
+	if err := c.fs().Remove(torrentPath); err != nil && !os.IsNotExist(err) {
 		return err
 	}
 
@@ -278,12 +311,12 @@ func (c *Cache) IsExpired(key string, appName string) bool {
 	safeKey := c.sanitizeKey(key)
 	path := filepath.Join(dir, safeKey+".json")
 
-	info, err := os.Stat(path)
+	info, err := c.fs().Stat(path)
 	if err != nil {
 		return true
 	}
 
-	return time.Since(info.ModTime()) > c.TTL
+	return clock.Since(c.clock(), info.ModTime()) > c.TTL
 }
 
 // GetAge returns how old a cached item is
@@ -299,12 +332,22 @@ func (c *Cache) GetAge(key string, appName string) (time.Duration, error) {
 	safeKey := c.sanitizeKey(key)
 	path := filepath.Join(dir, safeKey+".json")
 
-	info, err := os.Stat(path)
+	info, err := c.fs().Stat(path)
 	if err != nil {
 		return 0, fmt.Errorf("cache item not found: %w", err)
 	}
 
-	return time.Since(info.ModTime()), nil
+	return clock.Since(c.clock(), info.ModTime()), nil
+}
+
+// fs returns the file system holding the JSON data files.
+func (c *Cache) fs() fsys.FS {
+	return fsys.Or(c.FS)
+}
+
+// clock returns the clock timestamping and expiring entries.
+func (c *Cache) clock() clock.Clock {
+	return clock.Or(c.Clock)
 }
 
 // sanitizeKey creates a safe filename from a cache key
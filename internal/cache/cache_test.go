@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+func TestCache_FakeClockAndFS(t *testing.T) {
+	now := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	mem := &fsys.Mem{Clock: now}
+	mem.MkdirAll("/cache", 0755)
+	c := &Cache{TTL: time.Hour, BaseDir: "/cache", FS: mem, Clock: now}
+
+	if err := c.SaveTo("release_123", map[string]int{"id": 123}, "discogs"); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+	if _, err := mem.Stat("/cache/discogs/release_123.json"); err != nil {
+		t.Errorf("entry not written to the injected FS: %v", err)
+	}
+
+	now.Advance(59 * time.Minute)
+	var got map[string]int
+	if !c.LoadFrom("release_123", &got, "discogs") || got["id"] != 123 {
+		t.Errorf("LoadFrom() before expiry = %v", got)
+	}
+	if age, err := c.GetAge("release_123", "discogs"); err != nil || age != 59*time.Minute {
+		t.Errorf("GetAge() = %v, %v; want 59m", age, err)
+	}
+
+	now.Advance(2 * time.Minute)
+	if c.LoadFrom("release_123", &got, "discogs") || !c.IsExpired("release_123", "discogs") {
+		t.Error("entry should expire after the TTL")
+	}
+}
+
+func TestCache_CorruptEntriesRemoved(t *testing.T) {
+	now := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	mem := &fsys.Mem{Clock: now}
+	mem.MkdirAll("/cache/discogs", 0755)
+	var warnings strings.Builder
+	c := &Cache{TTL: time.Hour, BaseDir: "/cache", FS: mem, Clock: now, Warnings: &warnings}
+
+	if err := c.SaveTo("release_1", map[string]int{"id": 1}, "discogs"); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+	data, _ := mem.ReadFile("/cache/discogs/release_1.json")
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"truncated by an interrupted write", string(data[:len(data)/2])},
+		{"empty", ""},
+		{"no data", `{"timestamp": "2024-01-01T00:00:00Z"}`},
+		{"data of another shape", `{"timestamp": "2024-01-01T00:00:00Z", "data": [1, 2]}`},
+	}
+	for _, tt := range tests {
+		warnings.Reset()
+		mem.WriteFile("/cache/discogs/release_1.json", []byte(tt.data), 0644)
+
+		var got map[string]int
+		if c.LoadFrom("release_1", &got, "discogs") {
+			t.Errorf("%s: LoadFrom() = %v, want a miss", tt.name, got)
+		}
+		if _, err := mem.Stat("/cache/discogs/release_1.json"); err == nil {
+			t.Errorf("%s: entry not removed", tt.name)
+		}
+		if !strings.Contains(warnings.String(), "removed corrupt cache entry /cache/discogs/release_1.json") {
+			t.Errorf("%s: warnings = %q", tt.name, warnings.String())
+		}
+	}
+
+	// The entry is saved afresh and loads again, without temporary files left over
+	if err := c.SaveTo("release_1", map[string]int{"id": 1}, "discogs"); err != nil {
+		t.Fatalf("SaveTo() error = %v", err)
+	}
+	var got map[string]int
+	if !c.LoadFrom("release_1", &got, "discogs") || got["id"] != 1 {
+		t.Errorf("LoadFrom() after resaving = %v", got)
+	}
+	mem.WalkDir("/cache", func(path string, d fs.DirEntry, err error) error {
+		if strings.HasSuffix(path, ".tmp") {
+			t.Errorf("temporary file left behind: %s", path)
+		}
+		return nil
+	})
+}
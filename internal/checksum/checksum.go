@@ -0,0 +1,252 @@
+// Package checksum writes and checks the checksum files curators ship with an
+// album: SHA256SUMS over its audio files and ffp.txt, the FLAC fingerprints
+// (the audio MD5 signature each FLAC file's STREAMINFO carries).
+package checksum
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-flac/go-flac"
+)
+
+// Kind is a type of checksum file.
+type Kind string
+
+const (
+	SHA256 Kind = "sha256" // SHA256SUMS, in sha256sum format, over FLAC and DSD files
+	FFP    Kind = "ffp"    // ffp.txt, "path:md5" FLAC fingerprints
+)
+
+// Kinds lists every checksum kind, in the order files are written and checked.
+var Kinds = []Kind{SHA256, FFP}
+
+// ErrUnknownKind is returned for a checksum kind other than sha256 or ffp.
+var ErrUnknownKind = errors.New("unknown checksum kind")
+
+// ErrNoChecksums is returned by Verify for a directory without checksum files.
+var ErrNoChecksums = errors.New("no SHA256SUMS or ffp.txt")
+
+// ParseKinds parses checksum kind names, ignoring blanks and repeats.
+func ParseKinds(names []string) ([]Kind, error) {
+	var kinds []Kind
+	for _, name := range names {
+		kind := Kind(strings.ToLower(strings.TrimSpace(name)))
+		switch {
+		case kind == "":
+			continue
+		case !slices.Contains(Kinds, kind):
+			return nil, fmt.Errorf("%w %q (want sha256 or ffp)", ErrUnknownKind, name)
+		case !slices.Contains(kinds, kind):
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds, nil
+}
+
+// FileName returns the name of the kind's checksum file.
+func (k Kind) FileName() string {
+	if k == FFP {
+		return "ffp.txt"
+	}
+	return "SHA256SUMS"
+}
+
+// label names the kind's checksum in problems.
+func (k Kind) label() string {
+	if k == FFP {
+		return "FLAC fingerprint"
+	}
+	return "SHA-256"
+}
+
+// covers reports whether the kind's checksum file lists the file at path.
+func (k Kind) covers(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".flac":
+		return true
+	case ".dsf", ".dff":
+		return k == SHA256
+	}
+	return false
+}
+
+// sum returns the kind's checksum of the file at path, as lowercase hex.
+func (k Kind) sum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if k == FFP {
+		meta, err := flac.ParseMetadata(f)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse FLAC metadata: %w", err)
+		}
+		info, err := meta.GetStreamInfo()
+		if err != nil {
+			return "", fmt.Errorf("failed to parse STREAMINFO: %w", err)
+		}
+		return hex.EncodeToString(info.AudioMD5), nil
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// files returns the files under dir the kind covers, relative to dir with
+// forward slashes, sorted.
+func (k Kind) files(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !k.covers(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	slices.Sort(paths)
+	return paths, err
+}
+
+// Write writes the kind's checksum file into dir, covering every file under it
+// the kind applies to, and returns the file's path and how many files it lists.
+func Write(dir string, kind Kind) (string, int, error) {
+	paths, err := kind.files(dir)
+	if err != nil {
+		return "", 0, err
+	}
+	var b bytes.Buffer
+	for _, rel := range paths {
+		sum, err := kind.sum(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", 0, fmt.Errorf("%s: %w", rel, err)
+		}
+		if kind == FFP {
+			fmt.Fprintf(&b, "%s:%s\n", rel, sum)
+		} else {
+			fmt.Fprintf(&b, "%s  %s\n", sum, rel)
+		}
+	}
+	path := filepath.Join(dir, kind.FileName())
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		return "", 0, fmt.Errorf("failed to write %s: %w", kind.FileName(), err)
+	}
+	return path, len(paths), nil
+}
+
+// Result is the outcome of checking one file against a checksum file.
+type Result struct {
+	Path    string // Relative to the album root, with forward slashes
+	Kind    Kind
+	Problem string // "" when the file matches
+}
+
+// Verify checks the files under dir against whichever checksum files dir
+// holds. Files a checksum file lists but that are gone, that no longer match,
+// or that it should cover but does not list are problems. A directory with no
+// checksum file returns ErrNoChecksums.
+func Verify(dir string) ([]Result, error) {
+	var results []Result
+	found := false
+	for _, kind := range Kinds {
+		data, err := os.ReadFile(filepath.Join(dir, kind.FileName()))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		found = true
+
+		listed, err := parse(kind, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", kind.FileName(), err)
+		}
+		for _, entry := range listed {
+			result := Result{Path: entry.path, Kind: kind}
+			sum, err := kind.sum(filepath.Join(dir, filepath.FromSlash(entry.path)))
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				result.Problem = "missing"
+			case err != nil:
+				result.Problem = err.Error()
+			case !strings.EqualFold(sum, entry.sum):
+				result.Problem = fmt.Sprintf("%s does not match %s", kind.label(), kind.FileName())
+			}
+			results = append(results, result)
+		}
+
+		paths, err := kind.files(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range paths {
+			if !slices.ContainsFunc(listed, func(e entry) bool { return e.path == rel }) {
+				results = append(results, Result{Path: rel, Kind: kind, Problem: "not in " + kind.FileName()})
+			}
+		}
+	}
+	if !found {
+		return nil, ErrNoChecksums
+	}
+	return results, nil
+}
+
+// entry is one line of a checksum file.
+type entry struct {
+	path string
+	sum  string
+}
+
+// parse reads a checksum file's entries. sha256sum's binary marker ("*path")
+// and ffp comment lines (starting with ";") are accepted.
+func parse(kind Kind, data []byte) ([]entry, error) {
+	var entries []entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, ";") || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var e entry
+		var ok bool
+		if kind == FFP {
+			i := strings.LastIndex(text, ":")
+			ok = i > 0
+			if ok {
+				e = entry{path: text[:i], sum: strings.TrimSpace(text[i+1:])}
+			}
+		} else {
+			var rest string
+			e.sum, rest, ok = strings.Cut(text, " ")
+			e.path = strings.TrimPrefix(strings.TrimPrefix(rest, " "), "*")
+		}
+		if !ok || e.path == "" || e.sum == "" {
+			return nil, fmt.Errorf("line %d: malformed entry %q", line, text)
+		}
+		e.path = strings.ReplaceAll(e.path, `\`, "/")
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
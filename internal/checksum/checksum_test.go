@@ -0,0 +1,127 @@
+package checksum
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-flac/go-flac"
+)
+
+// writeFLAC writes a FLAC file with only a STREAMINFO block, whose audio MD5
+// signature is sixteen copies of md5Byte, followed by frames.
+func writeFLAC(t *testing.T, path string, md5Byte byte, frames string) {
+	t.Helper()
+	info := make([]byte, 34)
+	copy(info[18:], bytes.Repeat([]byte{md5Byte}, 16))
+
+	var b bytes.Buffer
+	b.WriteString("fLaC")
+	b.Write([]byte{0x80 | byte(flac.StreamInfo), 0, 0, 34}) // Last block, 34-byte STREAMINFO
+	b.Write(info)
+	b.WriteString(frames)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseKinds(t *testing.T) {
+	kinds, err := ParseKinds([]string{" SHA256", "", "ffp", "sha256"})
+	if err != nil || !slices.Equal(kinds, []Kind{SHA256, FFP}) {
+		t.Errorf("ParseKinds() = %v, %v; want [sha256 ffp]", kinds, err)
+	}
+	if _, err := ParseKinds([]string{"md5"}); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("ParseKinds(md5) error = %v, want ErrUnknownKind", err)
+	}
+}
+
+func TestWriteAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	writeFLAC(t, filepath.Join(dir, "CD1", "01 - Kyrie.flac"), 0xAB, "first")
+	writeFLAC(t, filepath.Join(dir, "CD2", "01 - Credo.flac"), 0xCD, "second")
+	os.WriteFile(filepath.Join(dir, "CD2", "02 - Sanctus.dsf"), []byte("DSD audio"), 0644)
+	os.WriteFile(filepath.Join(dir, "folder.jpg"), []byte("cover"), 0644)
+
+	path, n, err := Write(dir, FFP)
+	if err != nil || n != 2 {
+		t.Fatalf("Write(ffp) = %d files, %v; want 2", n, err)
+	}
+	data, _ := os.ReadFile(path)
+	if want := "CD1/01 - Kyrie.flac:" + strings.Repeat("ab", 16) + "\n"; !strings.HasPrefix(string(data), want) {
+		t.Errorf("ffp.txt = %q, want it to start with %q", data, want)
+	}
+	if _, n, err = Write(dir, SHA256); err != nil || n != 3 {
+		t.Fatalf("Write(sha256) = %d files, %v; want 3 with the DSD file", n, err)
+	}
+
+	results, err := Verify(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("Verify() = %d results, want 5", len(results))
+	}
+	for _, r := range results {
+		if r.Problem != "" {
+			t.Errorf("%s (%s): %s, want a match", r.Path, r.Kind, r.Problem)
+		}
+	}
+
+	// Changing a file's bytes but not its audio breaks only the SHA-256
+	writeFLAC(t, filepath.Join(dir, "CD1", "01 - Kyrie.flac"), 0xAB, "retagged")
+	os.Remove(filepath.Join(dir, "CD2", "02 - Sanctus.dsf"))
+	writeFLAC(t, filepath.Join(dir, "CD2", "03 - Agnus Dei.flac"), 0xEF, "third")
+
+	results, err = Verify(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var problems []string
+	for _, r := range results {
+		if r.Problem != "" {
+			problems = append(problems, string(r.Kind)+" "+r.Path+": "+r.Problem)
+		}
+	}
+	want := []string{
+		"sha256 CD1/01 - Kyrie.flac: SHA-256 does not match SHA256SUMS",
+		"sha256 CD2/02 - Sanctus.dsf: missing",
+		"sha256 CD2/03 - Agnus Dei.flac: not in SHA256SUMS",
+		"ffp CD2/03 - Agnus Dei.flac: not in ffp.txt",
+	}
+	if !slices.Equal(problems, want) {
+		t.Errorf("problems = %q, want %q", problems, want)
+	}
+}
+
+func TestVerify_ForeignFormats(t *testing.T) {
+	dir := t.TempDir()
+	writeFLAC(t, filepath.Join(dir, "CD1", "01.flac"), 0x12, "audio")
+	sum, err := SHA256.sum(filepath.Join(dir, "CD1", "01.flac"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sha256sum's binary marker, and an ffp from Windows with a comment and CRLF
+	os.WriteFile(filepath.Join(dir, "SHA256SUMS"), []byte(strings.ToUpper(sum)+" *CD1/01.flac\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "ffp.txt"), []byte("; generated by foobar2000\r\nCD1\\01.flac:"+strings.Repeat("12", 16)+"\r\n"), 0644)
+
+	results, err := Verify(dir)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("Verify() = %v, %v; want 2 results", results, err)
+	}
+	for _, r := range results {
+		if r.Problem != "" {
+			t.Errorf("%s (%s): %s, want a match", r.Path, r.Kind, r.Problem)
+		}
+	}
+
+	if _, err := Verify(t.TempDir()); !errors.Is(err, ErrNoChecksums) {
+		t.Errorf("Verify() without checksum files error = %v, want ErrNoChecksums", err)
+	}
+}
@@ -0,0 +1,38 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+	short, long := c.After(time.Second), c.After(time.Minute)
+	select {
+	case <-c.After(0):
+	default:
+		t.Error("After(0) should fire at once")
+	}
+	if c.Waiters() != 2 {
+		t.Errorf("Waiters() = %d, want 2", c.Waiters())
+	}
+
+	c.Advance(2 * time.Second)
+	select {
+	case now := <-short:
+		if !now.Equal(start.Add(2 * time.Second)) {
+			t.Errorf("After(1s) fired at %v", now)
+		}
+	default:
+		t.Error("After(1s) should fire after advancing 2s")
+	}
+	select {
+	case <-long:
+		t.Error("After(1m) fired early")
+	default:
+	}
+	if got := Since(c, start); got != 2*time.Second {
+		t.Errorf("Since() = %v, want 2s", got)
+	}
+}
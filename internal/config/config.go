@@ -1,9 +1,11 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -12,14 +14,235 @@ import (
 // Config represents the application configuration.
 type Config struct {
 	Discogs struct {
-		Token string `yaml:"token"`
+		Token          string    `yaml:"token"`
+		RateLimit      RateLimit `yaml:"rate_limit"`
+		TimeoutSeconds int       `yaml:"timeout_seconds"` // Default: 30
 	} `yaml:"discogs"`
 	Redacted struct {
-		APIKey string `yaml:"api_key"`
+		APIKey         string    `yaml:"api_key"`
+		RateLimit      RateLimit `yaml:"rate_limit"`
+		TimeoutSeconds int       `yaml:"timeout_seconds"` // Default: 30
 	} `yaml:"redacted"`
 	Cache struct {
 		TTLHours int `yaml:"ttl_hours"` // Default: 24 if not specified
 	} `yaml:"cache"`
+	Performance struct {
+		Workers int `yaml:"workers"` // Default: number of CPUs
+	} `yaml:"performance"`
+	Naming struct {
+		DirectoryTemplate string `yaml:"directory_template"` // Empty: built-in directory naming
+		DiscTemplate      string `yaml:"disc_template"`      // Empty: "Disc {disc}"
+		FilenamePolicy    string `yaml:"filename_policy"`    // Empty: "redacted"
+	} `yaml:"naming"`
+	Artists struct {
+		Aliases map[string]string `yaml:"aliases"` // Variant spelling -> canonical spelling
+	} `yaml:"artists"`
+	Upload struct {
+		TrumpReason    string `yaml:"trump_reason"`    // Built-in template name or text/template; empty: "default"
+		IncludeExtras  bool   `yaml:"include_extras"`  // Keep extras folders (bonus DVD video) in built torrents
+		MaxArtists     int    `yaml:"max_artists"`     // Artists credited on the upload form; 0: DefaultMaxArtists, negative: no limit
+		Window         string `yaml:"window"`          // Daily "HH:MM-HH:MM" span submissions are deferred to; empty: any time
+		SpacingMinutes int    `yaml:"spacing_minutes"` // Least minutes between submissions; 0: none
+	} `yaml:"upload"`
+	Roots          map[string]Root `yaml:"roots"` // Named library roots, e.g. incoming, staging, seeding
+	Sites          map[string]Site `yaml:"sites"` // Trackers to build torrents for, over DefaultSites
+	Capitalization struct {
+		ProtectedWords []string `yaml:"protected_words"` // Added to the built-in protected words (BWV, RIAS, II, ...)
+	} `yaml:"capitalization"`
+	Titles struct {
+		Keys      string `yaml:"keys"`      // Key signature style: english ("C-sharp minor"), symbols ("C# minor") or german ("cis-Moll"); empty: as found
+		Numbering string `yaml:"numbering"` // Work number abbreviation: "No.", "Nr." or "no."; empty: as found
+	} `yaml:"titles"`
+	Enrich struct {
+		Chain      []string            `yaml:"chain"`      // Sources in order, later ones taking precedence; default: local, discogs, web, library, file
+		Precedence map[string][]string `yaml:"precedence"` // Per field, sources from highest to lowest precedence
+	} `yaml:"enrich"`
+	Tagging struct {
+		PreserveTags []string `yaml:"preserve_tags"` // Junk tags (ITUNNORM, MQAENCODER, ...) the tag command keeps
+		Retention    []string `yaml:"retention"`     // "PATTERN=keep|drop|overwrite" rules for existing tags, first match wins
+		Checksums    []string `yaml:"checksums"`     // Checksum files written beside tagged audio: sha256, ffp
+	} `yaml:"tagging"`
+	ReadOnly ReadOnly `yaml:"read_only"`
+	Watch    Watch    `yaml:"watch"`
+}
+
+// ReadOnly keeps extract from writing in album directories, e.g. on a NAS
+// share the music is seeded from.
+type ReadOnly struct {
+	Enabled bool   `yaml:"enabled"`  // Default for extract -read-only
+	WorkDir string `yaml:"work_dir"` // Where read-only extract writes its JSON; empty: "work" in the state directory
+}
+
+// RateLimit configures an API rate limiter: Requests per WindowSeconds.
+type RateLimit struct {
+	Requests      int `yaml:"requests"`
+	WindowSeconds int `yaml:"window_seconds"`
+}
+
+// APILimits holds the effective rate limit and HTTP timeout for an API.
+type APILimits struct {
+	Requests int
+	Window   time.Duration
+	Timeout  time.Duration
+}
+
+// Default API limits, matching each service's published rate limits.
+var (
+	DefaultDiscogsLimits  = APILimits{Requests: 60, Window: time.Minute, Timeout: 30 * time.Second}
+	DefaultRedactedLimits = APILimits{Requests: 10, Window: 10 * time.Second, Timeout: 30 * time.Second}
+)
+
+// Override returns the limits with any positive argument (typically from command-line flags) applied.
+func (l APILimits) Override(requests int, window, timeout time.Duration) APILimits {
+	if requests > 0 {
+		l.Requests = requests
+	}
+	if window > 0 {
+		l.Window = window
+	}
+	if timeout > 0 {
+		l.Timeout = timeout
+	}
+	return l
+}
+
+// ErrUnknownRoot is returned for a library root name not defined in config.
+var ErrUnknownRoot = errors.New("unknown library root")
+
+// Root is a named library directory with its own defaults. Empty fields fall back
+// to the global settings.
+type Root struct {
+	Path              string `yaml:"path"`
+	Validation        string `yaml:"validation"`         // Validation profile: default, strict or lenient
+	OutputRoot        string `yaml:"output_root"`        // Where tag writes albums; empty: next to the source
+	DirectoryTemplate string `yaml:"directory_template"` // Overrides naming.directory_template
+	DiscTemplate      string `yaml:"disc_template"`      // Overrides naming.disc_template
+}
+
+// Resolve joins a relative dir onto the root's path; absolute paths are returned unchanged.
+func (r Root) Resolve(dir string) string {
+	if r.Path == "" || filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(r.Path, dir)
+}
+
+// LoadRoot loads the named library root from config file. An empty name returns the
+// zero Root, whose Resolve leaves paths as given.
+func LoadRoot(name string) (Root, error) {
+	if name == "" {
+		return Root{}, nil
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return Root{}, fmt.Errorf("%w %q: %v", ErrUnknownRoot, name, err)
+	}
+	root, ok := cfg.Roots[name]
+	if !ok || root.Path == "" {
+		return Root{}, fmt.Errorf("%w %q: define roots.%s.path in %s", ErrUnknownRoot, name, name, getConfigPath())
+	}
+	return root, nil
+}
+
+// ErrUnknownSite is returned for a site profile neither built in nor defined in config.
+var ErrUnknownSite = errors.New("unknown site")
+
+// Site is a tracker a torrent can be built for. The info "source" field
+// differs per site, giving each site's torrent of the same files its own info
+// hash, as cross-seeding rules require.
+type Site struct {
+	Announce string `yaml:"announce"` // Announce URL, normally with the passkey
+	Source   string `yaml:"source"`   // Info "source" field, e.g. "RED" or "OPS"
+}
+
+// DefaultSites are the built-in site profiles. Their announce URLs lack a
+// passkey, which the sites add to downloaded copies of uploaded torrents.
+var DefaultSites = map[string]Site{
+	"red": {Announce: "https://flacsfor.me/announce", Source: "RED"},
+	"ops": {Announce: "https://home.opsfet.ch/announce", Source: "OPS"},
+}
+
+// LoadSite loads the named site profile: the fields set in config over the
+// built-in profile of the same name.
+func LoadSite(name string) (Site, error) {
+	site := DefaultSites[name]
+	if cfg, err := loadConfig(); err == nil {
+		if configured, ok := cfg.Sites[name]; ok {
+			if configured.Announce != "" {
+				site.Announce = configured.Announce
+			}
+			if configured.Source != "" {
+				site.Source = configured.Source
+			}
+		}
+	}
+	if site.Announce == "" {
+		return Site{}, fmt.Errorf("%w %q: define sites.%s.announce in %s", ErrUnknownSite, name, name, getConfigPath())
+	}
+	return site, nil
+}
+
+// loadConfig reads and parses the config file.
+func loadConfig() (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(getConfigPath())
+	if err != nil {
+		return cfg, err
+	}
+	err = yaml.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+// apiLimits applies configured values over defaults, ignoring non-positive values.
+func apiLimits(defaults APILimits, rate RateLimit, timeoutSeconds int) APILimits {
+	limits := defaults
+	if rate.Requests > 0 {
+		limits.Requests = rate.Requests
+	}
+	if rate.WindowSeconds > 0 {
+		limits.Window = time.Duration(rate.WindowSeconds) * time.Second
+	}
+	if timeoutSeconds > 0 {
+		limits.Timeout = time.Duration(timeoutSeconds) * time.Second
+	}
+	return limits
+}
+
+// LoadDiscogsLimits loads the Discogs rate limit and timeout from config file, returns defaults if not specified.
+func LoadDiscogsLimits() APILimits {
+	cfg, err := loadConfig()
+	if err != nil {
+		return DefaultDiscogsLimits
+	}
+	return apiLimits(DefaultDiscogsLimits, cfg.Discogs.RateLimit, cfg.Discogs.TimeoutSeconds)
+}
+
+// LoadRedactedLimits loads the Redacted rate limit and timeout from config file, returns defaults if not specified.
+func LoadRedactedLimits() APILimits {
+	cfg, err := loadConfig()
+	if err != nil {
+		return DefaultRedactedLimits
+	}
+	return apiLimits(DefaultRedactedLimits, cfg.Redacted.RateLimit, cfg.Redacted.TimeoutSeconds)
+}
+
+// LoadWorkers loads the worker pool size from config file, returns the number of CPUs if not specified.
+func LoadWorkers() int {
+	cfg, err := loadConfig()
+	if err != nil || cfg.Performance.Workers <= 0 {
+		return runtime.NumCPU()
+	}
+	return cfg.Performance.Workers
+}
+
+// LoadArtistAliases loads artist aliases (variant -> canonical spelling) from config file,
+// returns nil if not specified.
+func LoadArtistAliases() map[string]string {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	return cfg.Artists.Aliases
 }
 
 // LoadDiscogsToken loads the Discogs personal access token from the config file.
@@ -97,6 +320,192 @@ func LoadCacheTTL() time.Duration {
 	return time.Duration(cfg.Cache.TTLHours) * time.Hour
 }
 
+// LoadDirectoryTemplate loads the directory naming template from config file, returns "" if not specified.
+func LoadDirectoryTemplate() string {
+	data, err := os.ReadFile(getConfigPath())
+	if err != nil {
+		return ""
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return ""
+	}
+
+	return cfg.Naming.DirectoryTemplate
+}
+
+// LoadDiscTemplate loads the disc subdirectory naming template from config file, returns "" if not specified.
+func LoadDiscTemplate() string {
+	data, err := os.ReadFile(getConfigPath())
+	if err != nil {
+		return ""
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return ""
+	}
+
+	return cfg.Naming.DiscTemplate
+}
+
+// LoadFilenamePolicy loads the track filename policy from config file, returns "" if not specified.
+func LoadFilenamePolicy() string {
+	cfg, err := loadConfig()
+	if err != nil {
+		return ""
+	}
+	return cfg.Naming.FilenamePolicy
+}
+
+// LoadTrumpReasonTemplate loads the trump reason template (a built-in name or template text)
+// from config file, returns "" if not specified.
+func LoadTrumpReasonTemplate() string {
+	cfg, err := loadConfig()
+	if err != nil {
+		return ""
+	}
+	return cfg.Upload.TrumpReason
+}
+
+// LoadIncludeExtras loads whether built torrents keep extras folders (a bonus DVD
+// or other video) from config file, returns false if not specified.
+func LoadIncludeExtras() bool {
+	cfg, err := loadConfig()
+	if err != nil {
+		return false
+	}
+	return cfg.Upload.IncludeExtras
+}
+
+// DefaultMaxArtists is how many artists an upload credits before moving minor
+// soloists to the description.
+const DefaultMaxArtists = 25
+
+// LoadMaxArtists loads how many artists an upload credits from config file,
+// returns DefaultMaxArtists if not specified and 0 (no limit) if negative.
+func LoadMaxArtists() int {
+	cfg, err := loadConfig()
+	if err != nil || cfg.Upload.MaxArtists == 0 {
+		return DefaultMaxArtists
+	}
+	return max(cfg.Upload.MaxArtists, 0)
+}
+
+// LoadUploadSchedule loads the upload window ("HH:MM-HH:MM") and the spacing
+// between upload submissions from config file, returns "" and 0 (submit at
+// once) if not specified.
+func LoadUploadSchedule() (window string, spacing time.Duration) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", 0
+	}
+	return cfg.Upload.Window, time.Duration(max(cfg.Upload.SpacingMinutes, 0)) * time.Minute
+}
+
+// LoadProtectedWords loads extra words whose spelling title-casing must keep
+// (e.g. "NHK", "deutsche harmonia mundi") from config file, returns nil if not specified.
+func LoadProtectedWords() []string {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	return cfg.Capitalization.ProtectedWords
+}
+
+// LoadTitleStyle loads the house style for key signatures and work numbers in
+// titles from config file, returns "" for each not specified.
+func LoadTitleStyle() (keys, numbering string) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return "", ""
+	}
+	return cfg.Titles.Keys, cfg.Titles.Numbering
+}
+
+// DefaultEnrichChain is the enrichment chain used when none is configured.
+var DefaultEnrichChain = []string{"local", "discogs", "web", "library", "file"}
+
+// LoadEnrichChain loads the enrichment sources to run, in order, from config file,
+// returns DefaultEnrichChain if not specified.
+func LoadEnrichChain() []string {
+	cfg, err := loadConfig()
+	if err != nil || len(cfg.Enrich.Chain) == 0 {
+		return DefaultEnrichChain
+	}
+	return cfg.Enrich.Chain
+}
+
+// LoadEnrichPrecedence loads the per-field source precedence from config file,
+// returns nil if not specified.
+func LoadEnrichPrecedence() map[string][]string {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	return cfg.Enrich.Precedence
+}
+
+// LoadPreservedTags loads the junk tags the tag command must keep instead of
+// stripping from config file, returns nil if not specified.
+func LoadPreservedTags() []string {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	return cfg.Tagging.PreserveTags
+}
+
+// LoadTagRetention loads the tag command's "PATTERN=policy" retention rules
+// from config file, returns nil if not specified.
+func LoadTagRetention() []string {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	return cfg.Tagging.Retention
+}
+
+// LoadChecksums loads the checksum files (sha256, ffp) the tag command writes
+// into its output from config file, returns nil if not specified.
+func LoadChecksums() []string {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil
+	}
+	return cfg.Tagging.Checksums
+}
+
+// Watch tunes how extract -wait-complete tells an album copied into a watched
+// folder is complete.
+type Watch struct {
+	SettleSeconds int    `yaml:"settle_seconds"` // How long files must stay unchanged; default: 30
+	PollSeconds   int    `yaml:"poll_seconds"`   // Interval between checks; default: 2
+	Marker        string `yaml:"marker"`         // File that must exist, e.g. ".complete"; empty: none
+	IgnoreWriters bool   `yaml:"ignore_writers"` // Don't wait for files open for writing to close
+}
+
+// LoadWatch loads the watch settings from config file, returns the zero value
+// (built-in defaults) if not specified.
+func LoadWatch() Watch {
+	cfg, err := loadConfig()
+	if err != nil {
+		return Watch{}
+	}
+	return cfg.Watch
+}
+
+// LoadReadOnly loads the read-only settings from config file, returns the zero
+// value (off, no work directory) if not specified.
+func LoadReadOnly() ReadOnly {
+	cfg, err := loadConfig()
+	if err != nil {
+		return ReadOnly{}
+	}
+	return cfg.ReadOnly
+}
+
 // getConfigPath returns the path to the config file.
 // Respects XDG Base Directory specification.
 func getConfigPath() string {
@@ -121,47 +530,23 @@ func GetConfigPathForDisplay() string {
 	return getConfigPath()
 }
 
+// CheckFile reads and parses the config file, returning why it cannot be used.
+func CheckFile() error {
+	_, err := loadConfig()
+	return err
+}
+
 // CreateSampleConfig creates a sample config file at the appropriate location.
 func CreateSampleConfig() error {
-	configPath := getConfigPath()
-
-	// Create directory if it doesn't exist
-	configDir := filepath.Dir(configPath)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
-	}
-
-	// Check if file already exists
-	if _, err := os.Stat(configPath); err == nil {
-		return fmt.Errorf("config file already exists at %s", configPath)
+	sample, err := Setup{}.Render()
+	if err != nil {
+		return err
 	}
-
-	// Sample configuration
-	sample := `# Classical Tagger Configuration
-
-# Discogs API Settings
-discogs:
-  # Your personal access token from https://www.discogs.com/settings/developers
-  token: "your-discogs-token-here"
-
-# Redacted API Settings
-redacted:
-  # Your API key from Redacted user settings
-  # Generate at: https://redacted.sh/user.php?action=edit (Access Settings)
-  api_key: "your-redacted-api-key-here"
-
-# Cache Settings (optional)
-cache:
-  # Cache TTL in hours (default: 24)
-  ttl_hours: 24
-`
-
-	// Write sample config
-	if err := os.WriteFile(configPath, []byte(sample), 0644); err != nil {
-		return fmt.Errorf("failed to write config file: %w", err)
+	if err := WriteConfig(sample, false); err != nil {
+		return err
 	}
 
-	fmt.Printf("Sample config created at: %s\n", configPath)
+	fmt.Printf("Sample config created at: %s\n", getConfigPath())
 	fmt.Println("Please edit it and add your Discogs personal access token.")
 	return nil
 }
@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 	"time"
 )
@@ -147,6 +149,367 @@ func TestLoadCacheTTL_Default(t *testing.T) {
 	}
 }
 
+func TestLoadDirectoryTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configFile := filepath.Join(configDir, "config.yaml")
+
+	configContent := `naming:
+  directory_template: "{composer_sort} - {title}"`
+
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	if got := LoadDirectoryTemplate(); got != "{composer_sort} - {title}" {
+		t.Errorf("Expected template '{composer_sort} - {title}', got %q", got)
+	}
+}
+
+func TestLoadDiscTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configFile := filepath.Join(configDir, "config.yaml")
+
+	configContent := `naming:
+  disc_template: "CD{disc}"`
+
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	if got := LoadDiscTemplate(); got != "CD{disc}" {
+		t.Errorf("Expected template 'CD{disc}', got %q", got)
+	}
+}
+
+func TestLoadFilenamePolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configContent := `naming:
+  filename_policy: plain`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	if got := LoadFilenamePolicy(); got != "plain" {
+		t.Errorf("LoadFilenamePolicy() = %q, want plain", got)
+	}
+}
+
+func TestLoadTrumpReasonTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configFile := filepath.Join(configDir, "config.yaml")
+
+	configContent := `upload:
+  trump_reason: detailed`
+
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	if got := LoadTrumpReasonTemplate(); got != "detailed" {
+		t.Errorf("Expected template 'detailed', got %q", got)
+	}
+}
+
+func TestLoadAPILimits(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configFile := filepath.Join(configDir, "config.yaml")
+
+	configContent := `discogs:
+  rate_limit:
+    requests: 240
+  timeout_seconds: 90
+redacted:
+  rate_limit:
+    requests: 5
+    window_seconds: 20
+performance:
+  workers: 3`
+
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	discogs := LoadDiscogsLimits()
+	want := APILimits{Requests: 240, Window: time.Minute, Timeout: 90 * time.Second}
+	if discogs != want {
+		t.Errorf("LoadDiscogsLimits() = %+v, want %+v", discogs, want)
+	}
+
+	redacted := LoadRedactedLimits()
+	want = APILimits{Requests: 5, Window: 20 * time.Second, Timeout: 30 * time.Second}
+	if redacted != want {
+		t.Errorf("LoadRedactedLimits() = %+v, want %+v", redacted, want)
+	}
+
+	if workers := LoadWorkers(); workers != 3 {
+		t.Errorf("LoadWorkers() = %d, want 3", workers)
+	}
+}
+
+func TestLoadAPILimits_Default(t *testing.T) {
+	os.Setenv("XDG_CONFIG_HOME", "/nonexistent/path")
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	if got := LoadDiscogsLimits(); got != DefaultDiscogsLimits {
+		t.Errorf("LoadDiscogsLimits() = %+v, want %+v", got, DefaultDiscogsLimits)
+	}
+	if got := LoadRedactedLimits(); got != DefaultRedactedLimits {
+		t.Errorf("LoadRedactedLimits() = %+v, want %+v", got, DefaultRedactedLimits)
+	}
+	if got := LoadWorkers(); got < 1 {
+		t.Errorf("LoadWorkers() = %d, want at least 1", got)
+	}
+}
+
+func TestAPILimits_Override(t *testing.T) {
+	limits := DefaultDiscogsLimits.Override(120, 0, 5*time.Second)
+	want := APILimits{Requests: 120, Window: time.Minute, Timeout: 5 * time.Second}
+	if limits != want {
+		t.Errorf("Override() = %+v, want %+v", limits, want)
+	}
+}
+
+func TestLoadArtistAliases(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configContent := `artists:
+  aliases:
+    English Concert: The English Concert
+    Orchestre Revolutionnaire et Romantique: Orchestre Révolutionnaire et Romantique`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	aliases := LoadArtistAliases()
+	if len(aliases) != 2 || aliases["English Concert"] != "The English Concert" {
+		t.Errorf("LoadArtistAliases() = %v", aliases)
+	}
+}
+
+func TestLoadProtectedWords(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configContent := `capitalization:
+  protected_words: [NHK, SWR2]`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	words := LoadProtectedWords()
+	if len(words) != 2 || words[0] != "NHK" || words[1] != "SWR2" {
+		t.Errorf("LoadProtectedWords() = %v", words)
+	}
+}
+
+func TestLoadTitleStyle(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configContent := `titles:
+  keys: german
+  numbering: "Nr."`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	if keys, numbering := LoadTitleStyle(); keys != "german" || numbering != "Nr." {
+		t.Errorf("LoadTitleStyle() = %q, %q", keys, numbering)
+	}
+}
+
+func TestLoadPreservedTags(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	if tags := LoadPreservedTags(); tags != nil {
+		t.Errorf("LoadPreservedTags() without config = %v, want nil", tags)
+	}
+
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configContent := `tagging:
+  preserve_tags: [MQAENCODER, ORIGINALSAMPLERATE]
+  retention: ["ENCODER=keep", "MUSICBRAINZ_*=drop"]
+  checksums: [sha256, ffp]`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	if tags := LoadPreservedTags(); !slices.Equal(tags, []string{"MQAENCODER", "ORIGINALSAMPLERATE"}) {
+		t.Errorf("LoadPreservedTags() = %v", tags)
+	}
+	if rules := LoadTagRetention(); !slices.Equal(rules, []string{"ENCODER=keep", "MUSICBRAINZ_*=drop"}) {
+		t.Errorf("LoadTagRetention() = %v", rules)
+	}
+	if kinds := LoadChecksums(); !slices.Equal(kinds, []string{"sha256", "ffp"}) {
+		t.Errorf("LoadChecksums() = %v", kinds)
+	}
+}
+
+func TestLoadEnrich(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	if chain := LoadEnrichChain(); !slices.Equal(chain, DefaultEnrichChain) {
+		t.Errorf("LoadEnrichChain() without config = %v, want %v", chain, DefaultEnrichChain)
+	}
+
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configContent := `enrich:
+  chain: [local, file]
+  precedence:
+    title: [local]`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	if chain := LoadEnrichChain(); !slices.Equal(chain, []string{"local", "file"}) {
+		t.Errorf("LoadEnrichChain() = %v", chain)
+	}
+	if p := LoadEnrichPrecedence(); !slices.Equal(p["title"], []string{"local"}) {
+		t.Errorf("LoadEnrichPrecedence() = %v", p)
+	}
+}
+
+func TestLoadRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configContent := `roots:
+  incoming:
+    path: /music/incoming
+    validation: lenient
+    output_root: /music/staging
+  broken:
+    validation: strict`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	root, err := LoadRoot("incoming")
+	if err != nil {
+		t.Fatalf("LoadRoot(incoming) error = %v", err)
+	}
+	if root.Validation != "lenient" || root.OutputRoot != "/music/staging" {
+		t.Errorf("LoadRoot(incoming) = %+v", root)
+	}
+	if got := root.Resolve("Bach - Motets"); got != "/music/incoming/Bach - Motets" {
+		t.Errorf("Resolve(relative) = %q", got)
+	}
+	if got := root.Resolve("/elsewhere/album"); got != "/elsewhere/album" {
+		t.Errorf("Resolve(absolute) = %q", got)
+	}
+
+	for _, name := range []string{"seeding", "broken"} {
+		if _, err := LoadRoot(name); !errors.Is(err, ErrUnknownRoot) {
+			t.Errorf("LoadRoot(%s) error = %v, want ErrUnknownRoot", name, err)
+		}
+	}
+	if root, err := LoadRoot(""); err != nil || root.Resolve("./album") != "./album" {
+		t.Errorf("LoadRoot(\"\") = %+v, %v; want paths left as given", root, err)
+	}
+}
+
+func TestLoadSite(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configContent := `sites:
+  red:
+    announce: https://flacsfor.me/passkey/announce
+  private:
+    announce: https://tracker.example/announce
+    source: PRV
+  nameless:
+    source: NONE`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	tests := map[string]Site{
+		"red":     {Announce: "https://flacsfor.me/passkey/announce", Source: "RED"}, // Source kept from the built-in profile
+		"ops":     DefaultSites["ops"],
+		"private": {Announce: "https://tracker.example/announce", Source: "PRV"},
+	}
+	for name, want := range tests {
+		if got, err := LoadSite(name); err != nil || got != want {
+			t.Errorf("LoadSite(%s) = %+v, %v; want %+v", name, got, err, want)
+		}
+	}
+	for _, name := range []string{"nameless", "missing"} {
+		if _, err := LoadSite(name); !errors.Is(err, ErrUnknownSite) {
+			t.Errorf("LoadSite(%s) error = %v, want ErrUnknownSite", name, err)
+		}
+	}
+}
+
 func TestGetConfigPath(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -188,3 +551,207 @@ func TestGetConfigPath(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadIncludeExtras(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configFile := filepath.Join(configDir, "config.yaml")
+
+	configContent := `upload:
+  include_extras: true`
+
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	if !LoadIncludeExtras() {
+		t.Error("Expected include_extras to be true")
+	}
+}
+
+func TestLoadUploadSchedule(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configContent := "upload:\n  window: \"01:00-06:00\"\n  spacing_minutes: 30"
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	window, spacing := LoadUploadSchedule()
+	if window != "01:00-06:00" || spacing != 30*time.Minute {
+		t.Errorf("LoadUploadSchedule() = %q, %v; want 01:00-06:00, 30m", window, spacing)
+	}
+}
+
+func TestLoadMaxArtists(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configFile := filepath.Join(configDir, "config.yaml")
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	tests := []struct {
+		content string
+		want    int
+	}{
+		{"upload:\n  trump_reason: brief", DefaultMaxArtists},
+		{"upload:\n  max_artists: 12", 12},
+		{"upload:\n  max_artists: -1", 0},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(configFile, []byte(tt.content), 0644); err != nil {
+			t.Fatalf("Failed to create test config: %v", err)
+		}
+		if got := LoadMaxArtists(); got != tt.want {
+			t.Errorf("LoadMaxArtists() with %q = %d, want %d", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestLoadWatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configFile := filepath.Join(configDir, "config.yaml")
+
+	configContent := `watch:
+  settle_seconds: 90
+  marker: .complete
+  ignore_writers: true`
+
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	want := Watch{SettleSeconds: 90, Marker: ".complete", IgnoreWriters: true}
+	if got := LoadWatch(); got != want {
+		t.Errorf("LoadWatch() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadReadOnly(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "classical-tagger")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create test config directory: %v", err)
+	}
+	configFile := filepath.Join(configDir, "config.yaml")
+
+	configContent := `read_only:
+  enabled: true
+  work_dir: /srv/work`
+
+	if err := os.WriteFile(configFile, []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to create test config: %v", err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", tmpDir)
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	got := LoadReadOnly()
+	if !got.Enabled || got.WorkDir != "/srv/work" {
+		t.Errorf("LoadReadOnly() = %+v, want enabled with work dir /srv/work", got)
+	}
+}
+
+func TestSetupRender(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	setup := Setup{
+		DiscogsToken:      `token-with-"quotes"`,
+		RedactedAPIKey:    "abc123",
+		DirectoryTemplate: "{composer_last} - {title} [{year}] [{format}]",
+		FilenamePolicy:    "plain",
+		Roots: []NamedRoot{
+			{Name: "incoming", Root: Root{Path: "/music/incoming", OutputRoot: "/music/staging"}},
+			{Name: "seeding", Root: Root{Path: "/music/seeding"}},
+		},
+	}
+	content, err := setup.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteConfig(content, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if token, err := LoadDiscogsToken(); err != nil || token != setup.DiscogsToken {
+		t.Errorf("LoadDiscogsToken() = %q, %v; want %q", token, err, setup.DiscogsToken)
+	}
+	if key, err := LoadRedactedAPIKey(); err != nil || key != setup.RedactedAPIKey {
+		t.Errorf("LoadRedactedAPIKey() = %q, %v; want %q", key, err, setup.RedactedAPIKey)
+	}
+	if got := LoadDirectoryTemplate(); got != setup.DirectoryTemplate {
+		t.Errorf("LoadDirectoryTemplate() = %q, want %q", got, setup.DirectoryTemplate)
+	}
+	if got := LoadFilenamePolicy(); got != "plain" {
+		t.Errorf("LoadFilenamePolicy() = %q, want plain", got)
+	}
+	if root, err := LoadRoot("incoming"); err != nil || root.Path != "/music/incoming" || root.OutputRoot != "/music/staging" {
+		t.Errorf("LoadRoot(incoming) = %+v, %v", root, err)
+	}
+	if root, err := LoadRoot("seeding"); err != nil || root.Path != "/music/seeding" {
+		t.Errorf("LoadRoot(seeding) = %+v, %v", root, err)
+	}
+	// Settings not chosen stay documented but commented out
+	if got := LoadCacheTTL(); got != 24*time.Hour {
+		t.Errorf("LoadCacheTTL() = %v, want the 24h default", got)
+	}
+
+	// Credentials not given are left as placeholders
+	content, err = Setup{}.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteConfig(content, true); err != nil {
+		t.Fatal(err)
+	}
+	if token, _ := LoadDiscogsToken(); token != DiscogsTokenPlaceholder {
+		t.Errorf("LoadDiscogsToken() = %q, want the placeholder", token)
+	}
+	if _, err := LoadRoot("incoming"); !errors.Is(err, ErrUnknownRoot) {
+		t.Errorf("LoadRoot(incoming) error = %v, want ErrUnknownRoot", err)
+	}
+}
+
+func TestWriteConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := GetConfigPathForDisplay()
+
+	if err := WriteConfig("first: 1\n", false); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("config file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+	if err := WriteConfig("second: 2\n", false); err == nil {
+		t.Error("WriteConfig() over an existing file succeeded without replace")
+	}
+	if err := WriteConfig("second: 2\n", true); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]string{path: "second: 2\n", path + ".bak": "first: 1\n"} {
+		if data, err := os.ReadFile(file); err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", file, data, err, want)
+		}
+	}
+}
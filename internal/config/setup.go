@@ -0,0 +1,200 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// Placeholders written for credentials not given, which config test reports
+// as still needing to be set.
+const (
+	DiscogsTokenPlaceholder   = "your-discogs-token-here"
+	RedactedAPIKeyPlaceholder = "your-redacted-api-key-here"
+)
+
+// Setup holds the settings chosen when creating a config file; the rest of
+// the file is documented defaults, commented out.
+type Setup struct {
+	DiscogsToken      string // Empty: DiscogsTokenPlaceholder
+	RedactedAPIKey    string // Empty: RedactedAPIKeyPlaceholder
+	DirectoryTemplate string // Empty: built-in directory naming
+	FilenamePolicy    string // Empty: the default, redacted
+	Roots             []NamedRoot
+}
+
+// NamedRoot is a library root written to the config file under its name.
+type NamedRoot struct {
+	Name string
+	Root
+}
+
+// configTemplate is the documented config file a Setup fills in.
+var configTemplate = template.Must(template.New("config").Funcs(template.FuncMap{
+	// JSON strings are valid double-quoted YAML scalars
+	"quote": func(s string) (string, error) {
+		data, err := json.Marshal(s)
+		return string(data), err
+	},
+}).Parse(`# Classical Tagger Configuration
+
+# Discogs API Settings
+discogs:
+  # Your personal access token from https://www.discogs.com/settings/developers
+  token: {{quote .DiscogsToken}}
+  # Optional: override rate limit (default: 60 requests per 60 seconds) and HTTP timeout
+  # rate_limit:
+  #   requests: 60
+  #   window_seconds: 60
+  # timeout_seconds: 30
+
+# Redacted API Settings
+redacted:
+  # Your API key from Redacted user settings
+  # Generate at: https://redacted.sh/user.php?action=edit (Access Settings)
+  api_key: {{quote .RedactedAPIKey}}
+  # Optional: override rate limit (default: 10 requests per 10 seconds) and HTTP timeout
+  # rate_limit:
+  #   requests: 10
+  #   window_seconds: 10
+  # timeout_seconds: 30
+
+# Cache Settings (optional)
+cache:
+  # Cache TTL in hours (default: 24)
+  ttl_hours: 24
+
+# Performance Settings (optional)
+performance:
+  # Worker pool size for file processing (default: number of CPUs)
+  # workers: 4
+
+# Naming Settings (optional)
+naming:
+  # Directory name template; placeholders: {composer}, {composer_last},
+  # {composer_sort}, {title}, {performers}, {year}, {format}
+  {{if .DirectoryTemplate}}directory_template: {{quote .DirectoryTemplate}}{{else}}# directory_template: "{composer_sort} - {title} ({performers}) - {year} [{format}]"{{end}}
+  # Track filename conventions: redacted ("01 - Title.flac", composer named on
+  # multi-composer albums) or plain ("1 - Title.flac", no composer)
+  {{if .FilenamePolicy}}filename_policy: {{.FilenamePolicy}}{{else}}# filename_policy: redacted{{end}}
+
+# Library Roots (optional)
+# Commands given --root NAME resolve a relative --dir against the root's path
+{{- with .Roots}}
+roots:
+{{- range .}}
+  {{.Name}}:
+    path: {{quote .Path}}
+{{- if .OutputRoot}}
+    output_root: {{quote .OutputRoot}}  # where tag writes albums
+{{- end}}
+{{- end}}
+{{- end}}
+# roots:
+#   incoming:
+#     path: /music/incoming
+#     validation: lenient          # default, strict (warnings block too) or lenient (report only)
+#     output_root: /music/staging  # where tag writes albums
+#   seeding:
+#     path: /music/seeding
+#     validation: strict
+#     directory_template: "{composer_sort} - {title} ({performers}) - {year} [{format}]"
+
+# Tracker Site Profiles (optional)
+# The announce URL and info "source" field of the torrents built for each site;
+# red and ops are built in, without a passkey. upload builds for red, and for
+# the sites given to --cross-seed
+# sites:
+#   red:
+#     announce: "https://flacsfor.me/YOUR-PASSKEY/announce"
+#   ops:
+#     announce: "https://home.opsfet.ch/YOUR-PASSKEY/announce"
+#     source: OPS
+
+# Capitalization Settings (optional)
+capitalization:
+  # Words kept exactly as spelled by title-casing and capitalization checks,
+  # in addition to built-ins like BWV, KV, RIAS, USSR and roman numerals
+  # protected_words: ["NHK", "SWR2"]
+
+# Title house style (optional), applied to titles by extract; validate warns when
+# an album mixes notations whatever the style
+# titles:
+#   keys: english     # english ("C-sharp minor"), symbols ("C# minor") or german ("cis-Moll")
+#   numbering: "No."  # "No.", "Nr." or "no."
+
+# Metadata enrichment run by extract
+# enrich:
+#   chain: [local, discogs, web, library, file]  # later sources take precedence by default
+#   # Per field (title, year, recording_years, edition, album_artist, tracks, files),
+#   # sources from highest to lowest precedence
+#   precedence:
+#     tracks: [file, local, discogs]
+
+# Tag writing
+# tagging:
+#   # Junk tags (iTunes normalization, MQA markers, AccurateRip data, ripper
+#   # comments) are stripped when tagging unless listed here
+#   preserve_tags: [MQAENCODER, ORIGINALSAMPLERATE]
+#   # What re-tagging does with tags already in the files, by tag name pattern
+#   # (first match wins): keep the file's value, drop the tag, or overwrite it
+#   # with the metadata's value (removing it when the metadata has none)
+#   retention: ["ENCODER=keep", "ACCURATERIPRESULT=keep", "MUSICBRAINZ_*=drop"]
+#   # Checksum files written into the tagged album: SHA256SUMS (sha256) and
+#   # ffp.txt FLAC fingerprints (ffp); verify -checksums checks them
+#   checksums: [sha256, ffp]
+
+# Read-only extraction, for albums on a NAS or seeding share
+# read_only:
+#   # extract never renames, re-encodes or moves anything in album directories
+#   # and writes its JSON to work_dir instead of the current directory
+#   enabled: true
+#   work_dir: /srv/classical-tagger/work  # default: work in the state directory
+`))
+
+// Render returns the config file for the setup, with every other setting
+// documented and commented out.
+func (s Setup) Render() (string, error) {
+	if s.DiscogsToken == "" {
+		s.DiscogsToken = DiscogsTokenPlaceholder
+	}
+	if s.RedactedAPIKey == "" {
+		s.RedactedAPIKey = RedactedAPIKeyPlaceholder
+	}
+	var b strings.Builder
+	if err := configTemplate.Execute(&b, s); err != nil {
+		return "", fmt.Errorf("failed to render config file: %w", err)
+	}
+	return b.String(), nil
+}
+
+// WriteConfig writes the config file, readable only by its owner as it holds
+// credentials. An existing file is an error unless replace is set, when it is
+// kept as config.yaml.bak.
+func WriteConfig(content string, replace bool) error {
+	configPath := getConfigPath()
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	switch _, err := os.Stat(configPath); {
+	case err == nil && !replace:
+		return fmt.Errorf("config file already exists at %s", configPath)
+	case err == nil:
+		if err := os.Rename(configPath, configPath+".bak"); err != nil {
+			return fmt.Errorf("failed to back up config file: %w", err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("failed to check config file: %w", err)
+	}
+
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Package console writes the commands' output to the right stream: results
+// (file paths, issues, reports) on stdout, where scripts can pipe them, and
+// progress, decoration and diagnostics on stderr. With -quiet, progress and
+// decoration are dropped and results are printed bare, one per line; warnings
+// and errors still reach stderr.
+package console
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	quiet bool
+
+	// Where output goes; tests redirect them
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// AddFlag registers -quiet on fs.
+func AddFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&quiet, "quiet", false, "Print only results, bare and one per line, for scripts: no progress, emoji or summaries (warnings and errors still go to stderr)")
+}
+
+// SetQuiet sets quiet mode, as -quiet does.
+func SetQuiet(q bool) {
+	quiet = q
+}
+
+// Quiet reports whether quiet mode is on.
+func Quiet() bool {
+	return quiet
+}
+
+// Infof writes progress or decoration to stderr, unless quiet.
+func Infof(format string, args ...any) {
+	if !quiet {
+		fmt.Fprintf(stderr, format, args...)
+	}
+}
+
+// Infoln writes a line of progress or decoration to stderr, unless quiet.
+func Infoln(args ...any) {
+	if !quiet {
+		fmt.Fprintln(stderr, args...)
+	}
+}
+
+// Resultf writes a result to stdout: as format describes it, or when quiet
+// value alone on its line.
+func Resultf(value, format string, args ...any) {
+	if quiet {
+		fmt.Fprintln(stdout, value)
+		return
+	}
+	fmt.Fprintf(stdout, format, args...)
+}
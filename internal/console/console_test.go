@@ -0,0 +1,54 @@
+package console
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestOutput(t *testing.T) {
+	var out, errs bytes.Buffer
+	stdout, stderr = &out, &errs
+	t.Cleanup(func() { SetQuiet(false) })
+
+	tests := []struct {
+		Name     string
+		Args     []string
+		WantOut  string
+		WantErrs string
+	}{
+		{
+			Name:     "default",
+			WantOut:  "✓ Saved to: album.json\n",
+			WantErrs: "Loading album...\n✓ Loaded\n",
+		},
+		{
+			Name:    "quiet",
+			Args:    []string{"-quiet"},
+			WantOut: "album.json\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			out.Reset()
+			errs.Reset()
+			SetQuiet(false)
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			AddFlag(fs)
+			if err := fs.Parse(tt.Args); err != nil {
+				t.Fatal(err)
+			}
+
+			Infof("Loading %s...\n", "album")
+			Infoln("✓ Loaded")
+			Resultf("album.json", "✓ Saved to: %s\n", "album.json")
+
+			if out.String() != tt.WantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.WantOut)
+			}
+			if errs.String() != tt.WantErrs {
+				t.Errorf("stderr = %q, want %q", errs.String(), tt.WantErrs)
+			}
+		})
+	}
+}
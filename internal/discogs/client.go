@@ -3,12 +3,14 @@ package discogs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -16,9 +18,13 @@ import (
 
 	"github.com/cehbz/classical-tagger/internal/cache"
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 	"github.com/cehbz/classical-tagger/internal/ratelimit"
 )
 
+// ErrUnauthorized is returned when Discogs rejects the token.
+var ErrUnauthorized = errors.New("token rejected")
+
 // Client is a Discogs API client.
 type Client struct {
 	BaseURL     string
@@ -41,6 +47,17 @@ type Release struct {
 	ExtraArtists  []Artist `json:"extraartists,omitempty"`
 	Tracklist     []Track  `json:"tracklist,omitempty"`
 	Labels        []Label  `json:"labels,omitempty"`
+	Notes         string   `json:"notes,omitempty"`
+	Images        []Image  `json:"images,omitempty"`
+}
+
+// Image is a picture of a release: the "primary" image is the front cover, the
+// "secondary" ones the back, booklet, disc and so on, in no fixed order.
+type Image struct {
+	Type   string `json:"type"`
+	URI    string `json:"uri"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
 
 type Role string
@@ -94,100 +111,68 @@ func NewClient(token string) *Client {
 }
 
 // Search searches for releases by artist and album.
-func (c *Client) Search(artist, album string) ([]*Release, error) {
-	// Create a cache key from the query
-	cacheKey := fmt.Sprintf("search_%s_%s", url.QueryEscape(artist), url.QueryEscape(album))
-
-	// Try cache first
-	var cached []*Release
-	if c.Cache.LoadFrom(cacheKey, &cached, "discogs") {
-		return cached, nil
-	}
-
-	// Rate limit
-	ctx := context.Background()
-	if err := c.RateLimiter.Wait(ctx); err != nil {
-		return nil, err
-	}
-
-	// Build search URL
-	u, err := url.Parse(c.BaseURL + "/database/search")
-	if err != nil {
-		return nil, err
-	}
-
-	q := u.Query()
+func (c *Client) Search(ctx context.Context, artist, album string) ([]*Release, error) {
+	q := url.Values{}
 	q.Set("artist", artist)
 	q.Set("release_title", album)
-	q.Set("type", "release")
 	q.Set("format", "CD") // Prefer CD releases for classical music
-	u.RawQuery = q.Encode()
-
-	// Create request
-	req, err := http.NewRequest("GET", u.String(), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Add auth header
-	req.Header.Set("Authorization", "Discogs token="+c.Token)
-	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
-
-	// Execute request
-	resp, err := c.HTTPClient.Do(req)
-	c.RateLimiter.OnResponse()
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("discogs API error: %d - %s", resp.StatusCode, string(body))
-	}
-
-	// Parse response
-	var searchResp searchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
-		return nil, fmt.Errorf("failed to parse search response: %w", err)
-	}
+	return c.searchReleases(ctx, fmt.Sprintf("search_%s_%s", url.QueryEscape(artist), url.QueryEscape(album)), q)
+}
 
-	// Convert search results to releases
-	releases := make([]*Release, len(searchResp.Results))
-	for i, result := range searchResp.Results {
-		releases[i] = &Release{
-			ID:            result.ID,
-			Title:         result.Title,
-			Country:       result.Country,
-			CatalogNumber: result.Catno,
-			Format:        result.Format,
+// SearchTitles searches for releases under each title variant in turn, so that
+// multi-language releases are found whichever variant Discogs lists first.
+// Results are merged in order and deduplicated by release ID.
+func (c *Client) SearchTitles(ctx context.Context, artist string, titles []string) ([]*Release, error) {
+	var releases []*Release
+	seen := make(map[int]bool)
+	for _, title := range titles {
+		results, err := c.Search(ctx, artist, title)
+		if err != nil {
+			return nil, err
 		}
-
-		// Parse year
-		if result.Year != "" {
-			if year, err := strconv.Atoi(result.Year); err == nil {
-				releases[i].Year = year
+		for _, r := range results {
+			if !seen[r.ID] {
+				seen[r.ID] = true
+				releases = append(releases, r)
 			}
 		}
-
-		// Get first label if available
-		if len(result.Label) > 0 {
-			releases[i].Label = result.Label[0]
-		}
 	}
-
-	c.Cache.SaveTo(cacheKey, releases, "discogs")
-
 	return releases, nil
 }
 
 // SearchSimple searches for releases using a simple query parameter.
 // This is more forgiving than the advanced search with separate artist and release_title parameters.
 // No format restriction is applied.
-func (c *Client) SearchSimple(query string) ([]*Release, error) {
-	// Create a cache key from the query
-	cacheKey := fmt.Sprintf("search_simple_%s", url.QueryEscape(query))
+func (c *Client) SearchSimple(ctx context.Context, query string) ([]*Release, error) {
+	q := url.Values{}
+	q.Set("query", query)
+	return c.searchReleases(ctx, fmt.Sprintf("search_simple_%s", url.QueryEscape(query)), q)
+}
+
+// SearchCatalogNumber searches for releases by catalog number, narrowed to label when
+// given. Box sets share artists and titles with their single-disc reissues, so this is
+// far more precise than Search.
+func (c *Client) SearchCatalogNumber(ctx context.Context, catno, label string) ([]*Release, error) {
+	q := url.Values{}
+	q.Set("catno", catno)
+	if label != "" {
+		q.Set("label", label)
+	}
+	return c.searchReleases(ctx, fmt.Sprintf("search_catno_%s_%s", url.QueryEscape(catno), url.QueryEscape(label)), q)
+}
+
+// SearchBarcode searches for releases by UPC/EAN barcode. Spaces and dashes are ignored.
+func (c *Client) SearchBarcode(ctx context.Context, barcode string) ([]*Release, error) {
+	barcode = strings.NewReplacer(" ", "", "-", "").Replace(barcode)
+	q := url.Values{}
+	q.Set("barcode", barcode)
+	return c.searchReleases(ctx, fmt.Sprintf("search_barcode_%s", url.QueryEscape(barcode)), q)
+}
 
+// searchReleases runs a release search with the given query parameters, caching
+// the results under cacheKey. Cancelling ctx aborts both the rate limiter wait and
+// the request.
+func (c *Client) searchReleases(ctx context.Context, cacheKey string, q url.Values) ([]*Release, error) {
 	// Try cache first
 	var cached []*Release
 	if c.Cache.LoadFrom(cacheKey, &cached, "discogs") {
@@ -195,7 +180,6 @@ func (c *Client) SearchSimple(query string) ([]*Release, error) {
 	}
 
 	// Rate limit
-	ctx := context.Background()
 	if err := c.RateLimiter.Wait(ctx); err != nil {
 		return nil, err
 	}
@@ -205,15 +189,11 @@ func (c *Client) SearchSimple(query string) ([]*Release, error) {
 	if err != nil {
 		return nil, err
 	}
-
-	q := u.Query()
-	q.Set("query", query)
 	q.Set("type", "release")
-	// Note: No format restriction for fallback search
 	u.RawQuery = q.Encode()
 
 	// Create request
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -230,9 +210,14 @@ func (c *Client) SearchSimple(query string) ([]*Release, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return nil, &ratelimit.ErrRateLimited{Service: "Discogs", RetryAfter: retryAfter}
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("discogs API error: %d - %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("discogs %w: %d - %s", domain.ErrAPI, resp.StatusCode, string(body))
 	}
 
 	// Parse response
@@ -271,7 +256,7 @@ func (c *Client) SearchSimple(query string) ([]*Release, error) {
 }
 
 // GetRelease fetches detailed information for a specific release.
-func (c *Client) GetRelease(releaseID int) (*Release, error) {
+func (c *Client) GetRelease(ctx context.Context, releaseID int) (*Release, error) {
 	// Check cache first
 	cacheKey := fmt.Sprintf("release_%d", releaseID)
 	var cached Release
@@ -280,7 +265,6 @@ func (c *Client) GetRelease(releaseID int) (*Release, error) {
 	}
 
 	// Apply rate limiting
-	ctx := context.Background()
 	if err := c.RateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limiter error: %w", err)
 	}
@@ -289,7 +273,7 @@ func (c *Client) GetRelease(releaseID int) (*Release, error) {
 	u := fmt.Sprintf("%s/releases/%d", c.BaseURL, releaseID)
 
 	// Create request
-	req, err := http.NewRequest("GET", u, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -310,9 +294,14 @@ func (c *Client) GetRelease(releaseID int) (*Release, error) {
 		return nil, fmt.Errorf("release %d not found", releaseID)
 	}
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return nil, &ratelimit.ErrRateLimited{Service: "Discogs", RetryAfter: retryAfter}
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("discogs API error: %d - %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("discogs %w: %d - %s", domain.ErrAPI, resp.StatusCode, string(body))
 	}
 
 	// Parse response
@@ -332,6 +321,81 @@ func (c *Client) GetRelease(releaseID int) (*Release, error) {
 	return &release, nil
 }
 
+// Image downloads a release image from its URI.
+func (c *Client) Image(ctx context.Context, uri string) ([]byte, error) {
+	if err := c.RateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Discogs token="+c.Token)
+	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
+
+	resp, err := c.HTTPClient.Do(req)
+	c.RateLimiter.OnResponse()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return nil, &ratelimit.ErrRateLimited{Service: "Discogs", RetryAfter: retryAfter}
+	default:
+		return nil, fmt.Errorf("discogs %w: image %s: %d", domain.ErrAPI, uri, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Identity returns the name of the user the token belongs to, checking the
+// token with a read-only request. A rejected token returns ErrUnauthorized.
+func (c *Client) Identity(ctx context.Context) (string, error) {
+	if err := c.RateLimiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/oauth/identity", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Discogs token="+c.Token)
+	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
+
+	resp, err := c.HTTPClient.Do(req)
+	c.RateLimiter.OnResponse()
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", domain.Classify(ErrUnauthorized, domain.ErrAPI)
+	case http.StatusTooManyRequests:
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return "", &ratelimit.ErrRateLimited{Service: "Discogs", RetryAfter: retryAfter}
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("discogs %w: %d - %s", domain.ErrAPI, resp.StatusCode, string(body))
+	}
+
+	var identity struct {
+		Username string `json:"username"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&identity); err != nil {
+		return "", fmt.Errorf("failed to parse identity response: %w", err)
+	}
+	return identity.Username, nil
+}
+
 type ArtistMap map[string]map[domain.Role]struct{}
 
 func (a ArtistMap) Artists() []domain.Artist {
@@ -362,16 +426,12 @@ func (a *ArtistMap) Add(name string, role domain.Role) {
 	(*a)[name][role] = struct{}{}
 }
 
-// normalizeArtistName normalizes an artist name for comparison (case-insensitive)
-func normalizeArtistName(name string) string {
-	return strings.ToLower(strings.TrimSpace(name))
-}
-
 // DomainRole determines the role for an artist with preference order:
 // 1. Discogs main artist role (if present)
 // 2. Discogs extraartists role (if artist name matches)
 // 3. Local file metadata role (if artist name matches)
-// 4. RoleUnknown (will cause error)
+// 4. A role assigned by hand on an earlier album, or inferred from the name
+// 5. RoleUnknown (conversion fails with domain.ErrRoleUnknown)
 func (artist Artist) DomainRole(release *Release, localTorrent *domain.Torrent) domain.Role {
 	// 1. Check if main artist has explicit role
 	if role := artist.Role.DomainRole(); role != domain.RoleUnknown {
@@ -379,10 +439,10 @@ func (artist Artist) DomainRole(release *Release, localTorrent *domain.Torrent)
 	}
 
 	// 2. Check extraartists for matching name
-	normalizedName := normalizeArtistName(artist.Name)
+	normalizedName := normalize.Name(artist.Name)
 	if release != nil {
 		for _, extraArtist := range release.ExtraArtists {
-			if normalizeArtistName(extraArtist.Name) == normalizedName {
+			if normalize.Name(extraArtist.Name) == normalizedName {
 				if role := extraArtist.Role.DomainRole(); role != domain.RoleUnknown {
 					return role
 				}
@@ -394,7 +454,7 @@ func (artist Artist) DomainRole(release *Release, localTorrent *domain.Torrent)
 	if localTorrent != nil {
 		// Check album artists
 		for _, localArtist := range localTorrent.AlbumArtist {
-			if normalizeArtistName(localArtist.Name) == normalizedName {
+			if normalize.Name(localArtist.Name) == normalizedName {
 				if localArtist.Role != domain.RoleUnknown {
 					return localArtist.Role
 				}
@@ -403,7 +463,7 @@ func (artist Artist) DomainRole(release *Release, localTorrent *domain.Torrent)
 		// Check track artists
 		for _, track := range localTorrent.Tracks() {
 			for _, localArtist := range track.Artists {
-				if normalizeArtistName(localArtist.Name) == normalizedName {
+				if normalize.Name(localArtist.Name) == normalizedName {
 					if localArtist.Role != domain.RoleUnknown {
 						return localArtist.Role
 					}
@@ -412,7 +472,7 @@ func (artist Artist) DomainRole(release *Release, localTorrent *domain.Torrent)
 		}
 	}
 
-	// 4. Infer from name
+	// 4. Infer from name (a role remembered from an earlier album first)
 	if role := inferRoleFromName(artist.Name); role != domain.RoleUnknown {
 		return role
 	}
@@ -424,18 +484,54 @@ func (artist Artist) DomainRole(release *Release, localTorrent *domain.Torrent)
 // DomainTorrent converts a Discogs Release to a domain Torrent
 // localTorrent is optional and used to fill in missing role information from file metadata
 func (release *Release) DomainTorrent(rootPath string, localTorrent *domain.Torrent) (*domain.Torrent, error) {
+	torrent, _, err := release.DomainTorrentWithNotes(rootPath, localTorrent)
+	return torrent, err
+}
+
+// trackEntry is a tracklist entry awaiting final disc/track numbering.
+type trackEntry struct {
+	position discogsPosition
+	raw      string
+	title    string
+	artists  []domain.Artist
+	duration time.Duration // From the tracklist; 0 when not given
+}
+
+// URL returns the release's page on the Discogs website.
+func (release *Release) URL() string {
+	return fmt.Sprintf("https://www.discogs.com/release/%d", release.ID)
+}
+
+// RecordingYears returns the recording years given in the release notes
+// ("Recorded at Jesus-Christus-Kirche, Berlin, 14-16 March 2012"), which Discogs
+// keeps separate from the release year.
+func (release *Release) RecordingYears() []int {
+	var years []int
+	for _, line := range strings.Split(release.Notes, "\n") {
+		if strings.Contains(strings.ToLower(line), "recorded") {
+			years = append(years, domain.ParseYears(line)...)
+		}
+	}
+	slices.Sort(years)
+	return slices.Compact(years)
+}
+
+// DomainTorrentWithNotes converts a Discogs Release to a domain Torrent and also
+// returns notes describing tracklist positions that were renumbered or skipped.
+func (release *Release) DomainTorrentWithNotes(rootPath string, localTorrent *domain.Torrent) (*domain.Torrent, []string, error) {
 
 	if release == nil {
-		return nil, fmt.Errorf("release is nil")
+		return nil, nil, fmt.Errorf("release is nil")
 	}
 
 	// Convert edition
 	var edition *domain.Edition
-	if release.Label != "" || release.CatalogNumber != "" || release.Year > 0 {
+	if release.Label != "" || release.CatalogNumber != "" || release.Year > 0 || release.ID > 0 {
 		edition = &domain.Edition{
-			Label:         release.Label,
-			CatalogNumber: release.CatalogNumber,
-			Year:          release.Year,
+			Label:            release.Label,
+			CatalogNumber:    release.CatalogNumber,
+			Year:             release.Year,
+			DiscogsReleaseID: release.ID,
 		}
 	}
 
@@ -461,12 +557,14 @@ func (release *Release) DomainTorrent(rootPath string, localTorrent *domain.Torr
 	// Validate no unknown roles in album artists
 	for _, artist := range albumArtists {
 		if artist.Role == domain.RoleUnknown {
-			return nil, fmt.Errorf("cannot determine role for album artist '%s'. Discogs has no role, extraartists has no matching entry, and file metadata has no matching entry", artist.Name)
+			return nil, nil, &domain.ErrRoleUnknown{Artist: artist.Name}
 		}
 	}
 
-	// Convert tracks
-	tracks := make([]domain.FileLike, 0, len(release.Tracklist))
+	var notes []string
+
+	// Collect tracks in tracklist order; numbering is finalized afterwards
+	entries := make([]trackEntry, 0, len(release.Tracklist))
 	for _, discogsTrack := range release.Tracklist {
 
 		trackArtistsMap := albumArtistMap.Copy()
@@ -477,17 +575,29 @@ func (release *Release) DomainTorrent(rootPath string, localTorrent *domain.Torr
 			trackArtistsMap.Add(artist.Name, role)
 		}
 
+		pos := parseDiscogsPosition(discogsTrack.Position)
+
 		// Process any subtracks - these have explicit positions and titles
 		for _, subtrack := range discogsTrack.SubTracks {
-			subTrackDisc, subTrackNum := parseDiscogsPosition(subtrack.Position)
-			if subTrackNum == 0 {
-				// Invalid position, skip
-				continue
-			}
+			subPos := parseDiscogsPosition(subtrack.Position)
 
 			// Build track title: prepend parent work title to subtrack title
 			subTrackTitle := discogsTrack.Title + ": " + subtrack.Title
 
+			if subPos.Video {
+				notes = append(notes, fmt.Sprintf("skipped video track %q: %s", subtrack.Position, subTrackTitle))
+				continue
+			}
+			if !subPos.Valid() {
+				// Invalid position, skip
+				continue
+			}
+			if pos.Valid() && subPos.Sub != "" {
+				// Parent has its own audio position: sub-positions are index points within it
+				notes = append(notes, fmt.Sprintf("sub-track %q (%s) is part of track %q", subtrack.Position, subTrackTitle, discogsTrack.Position))
+				continue
+			}
+
 			// Build track artists: add parent composer
 			subTrackArtistsMap := trackArtistsMap.Copy()
 
@@ -500,67 +610,125 @@ func (release *Release) DomainTorrent(rootPath string, localTorrent *domain.Torr
 			// Validate no unknown roles in subtrack artists
 			for _, artist := range subTrackArtists {
 				if artist.Role == domain.RoleUnknown {
-					return nil, fmt.Errorf("cannot determine role for track artist '%s' in subtrack '%s'. Discogs has no role, extraartists has no matching entry, and file metadata has no matching entry", artist.Name, subTrackTitle)
+					return nil, nil, &domain.ErrRoleUnknown{Artist: artist.Name, Track: subTrackTitle}
 				}
 			}
-			// Generate a path from track number and title
-			path := generateTrackPath(subTrackNum, subTrackTitle)
-
-			domainSubTrack := &domain.Track{
-				File: domain.File{
-					Path: path,
-				},
-				Disc:    subTrackDisc,
-				Track:   subTrackNum,
-				Title:   subTrackTitle,
-				Artists: subTrackArtists,
-			}
-			tracks = append(tracks, domainSubTrack)
+
+			entries = append(entries, trackEntry{
+				position: subPos,
+				raw:      subtrack.Position,
+				title:    subTrackTitle,
+				artists:  subTrackArtists,
+				duration: domain.ParseDuration(subtrack.Duration),
+			})
 		}
 
-		disc, trackNum := parseDiscogsPosition(discogsTrack.Position)
-		if trackNum == 0 {
+		if pos.Video {
+			notes = append(notes, fmt.Sprintf("skipped video track %q: %s", discogsTrack.Position, discogsTrack.Title))
+			continue
+		}
+		if !pos.Valid() {
 			// Invalid position, skip
 			continue
 		}
 
-		// Generate a path from track number and title (since we don't have actual files)
-		path := generateTrackPath(trackNum, discogsTrack.Title)
-
 		trackArtists := trackArtistsMap.Artists()
 
 		// Validate no unknown roles in track artists
 		for _, artist := range trackArtists {
 			if artist.Role == domain.RoleUnknown {
-				return nil, fmt.Errorf("cannot determine role for track artist '%s' in track '%s'. Discogs has no role, extraartists has no matching entry, and file metadata has no matching entry", artist.Name, discogsTrack.Title)
+				return nil, nil, &domain.ErrRoleUnknown{Artist: artist.Name, Track: discogsTrack.Title}
 			}
 		}
 
-		track := &domain.Track{
+		entries = append(entries, trackEntry{
+			position: pos,
+			raw:      discogsTrack.Position,
+			title:    discogsTrack.Title,
+			artists:  trackArtists,
+			duration: domain.ParseDuration(discogsTrack.Duration),
+		})
+	}
+
+	notes = append(notes, numberTracks(entries)...)
+
+	tracks := make([]domain.FileLike, 0, len(entries))
+	for _, entry := range entries {
+		tracks = append(tracks, &domain.Track{
 			File: domain.File{
-				Path: path,
+				// Generate a path from track number and title (since we don't have actual files)
+				Path: generateTrackPath(entry.position.Track, entry.title),
 			},
-			Disc:    disc,
-			Track:   trackNum,
-			Title:   discogsTrack.Title,
-			Artists: trackArtists,
-		}
-		tracks = append(tracks, track)
+			Disc:     entry.position.Disc,
+			Track:    entry.position.Track,
+			Title:    entry.title,
+			Artists:  entry.artists,
+			Duration: entry.duration,
+		})
 	}
 
+	title, alternateTitles := domain.SplitTitleVariants(release.Title)
 	torrent := &domain.Torrent{
-		Title:        release.Title,
-		OriginalYear: release.Year,
-		Edition:      edition,
-		AlbumArtist:  albumArtists,
-		Files:        tracks,
-		SiteMetadata: nil,
+		Title:           title,
+		AlternateTitles: alternateTitles,
+		OriginalYear:    release.Year,
+		RecordingYears:  release.RecordingYears(),
+		Edition:         edition,
+		AlbumArtist:     albumArtists,
+		Files:           tracks,
+		Sources:         []string{release.URL()},
+		SiteMetadata:    nil,
+	}
+	if localTorrent != nil {
+		torrent.AllowMissingComposer = localTorrent.AllowMissingComposer
+	}
+
+	// Record soloists' instruments from their Discogs credits ("Piano", "Soprano Vocals"),
+	// and the characters singers sing ("Soprano Vocals [Violetta]")
+	instruments := release.Instruments()
+	characters := release.Characters()
+	for i := range torrent.AlbumArtist {
+		setInstrument(&torrent.AlbumArtist[i], instruments)
+		setCharacter(&torrent.AlbumArtist[i], characters)
+	}
+	for _, track := range torrent.Tracks() {
+		for i := range track.Artists {
+			setInstrument(&track.Artists[i], instruments)
+			setCharacter(&track.Artists[i], characters)
+		}
 	}
 
 	// Generate root_path using the same logic as directory naming
 	torrent.RootPath = path.Join(rootPath, torrent.DirectoryName())
 
-	return torrent, nil
+	return torrent, notes, nil
+}
+
+// numberTracks assigns final track numbers in place and returns notes for renumbered positions.
+// Discs containing side-letter or sub-track positions have no direct CD track equivalent,
+// so every track on such a disc is numbered sequentially in tracklist order.
+func numberTracks(entries []trackEntry) []string {
+	sequentialDiscs := make(map[int]bool)
+	for _, entry := range entries {
+		if entry.position.Sequential() {
+			sequentialDiscs[entry.position.Disc] = true
+		}
+	}
+
+	var notes []string
+	counters := make(map[int]int)
+	for i := range entries {
+		pos := &entries[i].position
+		if !sequentialDiscs[pos.Disc] {
+			continue
+		}
+		counters[pos.Disc]++
+		if pos.Track != counters[pos.Disc] || pos.Sequential() {
+			notes = append(notes, fmt.Sprintf("position %q mapped to disc %d track %d", entries[i].raw, pos.Disc, counters[pos.Disc]))
+		}
+		pos.Track = counters[pos.Disc]
+	}
+	return notes
 }
 
 func (role Role) DomainRole() domain.Role {
@@ -578,13 +746,115 @@ func (role Role) DomainRole() domain.Role {
 	case "guest":
 		return domain.RoleGuest
 	default:
+		if role.Instrument() != "" || role.Character() != "" {
+			return domain.RoleSoloist
+		}
 		return domain.RoleUnknown
 	}
 }
 
-// inferRoleFromName tries to determine the role of an artist from their name
-// Returns domain.RoleUnknown if no role can be determined
+// instrumentRoles are the Discogs credit roles naming a soloist's instrument or voice.
+var instrumentRoles = map[string]bool{
+	"piano": true, "fortepiano": true, "harpsichord": true, "organ": true, "celesta": true,
+	"violin": true, "viola": true, "cello": true, "violoncello": true, "double bass": true,
+	"viola da gamba": true, "guitar": true, "lute": true, "theorbo": true, "harp": true,
+	"flute": true, "recorder": true, "oboe": true, "clarinet": true, "bassoon": true,
+	"horn": true, "french horn": true, "trumpet": true, "trombone": true, "tuba": true,
+	"saxophone": true, "percussion": true, "timpani": true, "accordion": true,
+	"soprano": true, "mezzo-soprano": true, "alto": true, "contralto": true, "countertenor": true,
+	"tenor": true, "baritone": true, "bass-baritone": true, "bass": true, "treble": true,
+}
+
+// Instrument returns the lowercase instrument or voice named by a credit role such as
+// "Piano", "Violin [Solo]" or "Soprano Vocals", or "" if the role is not an instrument.
+// Only the first of several comma-separated roles is considered.
+func (role Role) Instrument() string {
+	r := string(role)
+	if i := strings.Index(r, "["); i >= 0 {
+		r = r[:i]
+	}
+	r, _, _ = strings.Cut(r, ",")
+	r = strings.ToLower(strings.TrimSpace(r))
+	r = strings.TrimSpace(strings.TrimSuffix(r, " vocals"))
+	if instrumentRoles[r] {
+		return r
+	}
+	return ""
+}
+
+// Character returns the operatic role named in a vocal credit role's brackets,
+// "Violetta" for "Soprano Vocals [Violetta]", or "" if the role is not a voice
+// or names no character. Only the first of several comma-separated roles is
+// considered.
+func (role Role) Character() string {
+	r, _, _ := strings.Cut(string(role), ",")
+	voice, rest, ok := strings.Cut(r, "[")
+	if !ok {
+		return ""
+	}
+	voice = strings.ToLower(strings.TrimSpace(voice))
+	if voice != "vocals" && !domain.IsVoice(role.Instrument()) {
+		return ""
+	}
+	character, _, _ := strings.Cut(rest, "]")
+	return strings.TrimSpace(character)
+}
+
+// Instruments maps the normalized names of credited instrumentalists and singers
+// to their instrument.
+func (release *Release) Instruments() map[string]string {
+	found := make(map[string]string)
+	credits := slices.Concat(release.Artists, release.ExtraArtists)
+	for _, t := range release.Tracklist {
+		credits = append(credits, t.Artists...)
+	}
+	for _, a := range credits {
+		key := normalize.Name(a.Name)
+		if instrument := a.Role.Instrument(); instrument != "" && found[key] == "" {
+			found[key] = instrument
+		}
+	}
+	return found
+}
+
+// Characters maps the normalized names of credited singers to the character
+// they sing, the first credited when they sing several.
+func (release *Release) Characters() map[string]string {
+	found := make(map[string]string)
+	credits := slices.Concat(release.Artists, release.ExtraArtists)
+	for _, t := range release.Tracklist {
+		credits = append(credits, t.Artists...)
+	}
+	for _, a := range credits {
+		key := normalize.Name(a.Name)
+		if character := a.Role.Character(); character != "" && found[key] == "" {
+			found[key] = character
+		}
+	}
+	return found
+}
+
+// setCharacter fills a soloist's character from the release credits.
+func setCharacter(artist *domain.Artist, characters map[string]string) {
+	if artist.Role == domain.RoleSoloist && artist.Character == "" {
+		artist.Character = characters[normalize.Name(artist.Name)]
+	}
+}
+
+// setInstrument fills a soloist's instrument from the release credits.
+func setInstrument(artist *domain.Artist, instruments map[string]string) {
+	if artist.Role == domain.RoleSoloist && artist.Instrument == "" {
+		artist.Instrument = instruments[normalize.Name(artist.Name)]
+	}
+}
+
+// inferRoleFromName tries to determine the role of an artist from their name:
+// a role assigned to them by hand (ConfigureKnownRoles), else an ensemble
+// keyword. Returns domain.RoleUnknown if no role can be determined
 func inferRoleFromName(name string) domain.Role {
+	if role, ok := knownRole(name); ok {
+		return role
+	}
 	ensembleKeywords := map[string]Role{
 		"orchestra":    "ensemble",
 		"orchestre":    "ensemble",
@@ -611,39 +881,88 @@ func inferRoleFromName(name string) domain.Role {
 	return domain.RoleUnknown
 }
 
-// parseDiscogsPosition parses a Discogs position string (e.g., "1", "1-1", "A1", "CD1-1")
-// Returns (disc, track)
-// TODO: Handle track as string
-func parseDiscogsPosition(position string) (int, int) {
+// discogsPosition is a parsed Discogs tracklist position.
+type discogsPosition struct {
+	Disc  int    // Disc (or record) number, 1-based
+	Track int    // Track number on the disc; provisional for sequential positions
+	Side  string // Vinyl/cassette side letter ("A", "B", ...), if any
+	Sub   string // Sub-track suffix for positions like "3.1", "A3.a" or "A3b"
+	Video bool   // Enhanced-CD video material ("Video 1"), not audio
+}
+
+// Valid reports whether the position identifies an audio track.
+func (p discogsPosition) Valid() bool {
+	return !p.Video && p.Track > 0
+}
+
+// Sequential reports whether the track number must be assigned from tracklist order
+// because the position has no direct CD track equivalent.
+func (p discogsPosition) Sequential() bool {
+	return p.Side != "" || p.Sub != ""
+}
+
+var (
+	discPrefixPattern = regexp.MustCompile(`^([A-Za-z]*)(\d+)$`)
+	trackPartPattern  = regexp.MustCompile(`^([A-Z]?)(\d+)(?:\.?([a-z])|\.(\d+))?$`)
+	videoPattern      = regexp.MustCompile(`(?i)^video\s*\d*$`)
+)
+
+// parseDiscogsPosition parses a Discogs position string.
+// Supported forms follow the Discogs guidelines:
+//   - "1", "20": track on disc 1
+//   - "1-1", "CD1-1", "DVD9-88": disc-track
+//   - "A1", "B4", "C3": vinyl sides; A/B are record 1, C/D record 2, ...
+//   - "3.1", "A3.a", "A3b": sub-tracks
+//   - "Video 1": enhanced-CD video material
+//
+// Unparseable positions return a zero Track.
+func parseDiscogsPosition(position string) discogsPosition {
 	position = strings.TrimSpace(position)
 	if position == "" {
-		return 1, 0
+		return discogsPosition{Disc: 1}
 	}
 
-	// Handle formats like "1", "1-1", "A1", "CD1-1", etc.
-	parts := strings.Split(position, "-")
-	if len(parts) == 1 {
-		track, _ := strconv.Atoi(position)
-		return 1, track
+	if videoPattern.MatchString(position) {
+		return discogsPosition{Disc: 1, Video: true}
 	}
 
+	disc := 0
+	trackPart := position
+
 	// Format: "disc-track" or "CD1-1"
-	discStr := strings.TrimSpace(parts[0])
-	trackStr := strings.TrimSpace(parts[1])
+	if parts := strings.SplitN(position, "-", 2); len(parts) == 2 {
+		matches := discPrefixPattern.FindStringSubmatch(strings.TrimSpace(parts[0]))
+		if matches == nil {
+			return discogsPosition{Disc: 1}
+		}
+		disc, _ = strconv.Atoi(matches[2])
+		if disc == 0 {
+			return discogsPosition{Disc: 1}
+		}
+		trackPart = strings.TrimSpace(parts[1])
+	}
 
-	// Remove non-numeric prefix from disc (e.g., "CD1" -> "1")
-	discStr = regexp.MustCompile(`[^0-9]`).ReplaceAllString(discStr, "")
-	disc, _ := strconv.Atoi(discStr)
-	if disc == 0 {
-		return 1, 0
+	matches := trackPartPattern.FindStringSubmatch(trackPart)
+	if matches == nil {
+		return discogsPosition{Disc: 1}
 	}
-	trackStr = regexp.MustCompile(`[^0-9]`).ReplaceAllString(trackStr, "")
-	track, _ := strconv.Atoi(trackStr)
-	if track == 0 {
-		return 1, 0
+
+	pos := discogsPosition{Disc: disc, Side: matches[1]}
+	pos.Track, _ = strconv.Atoi(matches[2])
+	if pos.Track == 0 {
+		return discogsPosition{Disc: 1}
+	}
+	pos.Sub = matches[3] + matches[4]
+
+	if pos.Disc == 0 {
+		pos.Disc = 1
+		if pos.Side != "" {
+			// Two sides per record
+			pos.Disc = int(pos.Side[0]-'A')/2 + 1
+		}
 	}
 
-	return disc, track
+	return pos
 }
 
 // generateTrackPath generates a file path from track number and title
@@ -1,12 +1,16 @@
 package discogs
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
 )
 
 func TestClient_Search(t *testing.T) {
@@ -66,7 +70,7 @@ func TestClient_Search(t *testing.T) {
 	client := NewClient("test-token")
 	client.BaseURL = server.URL
 
-	releases, err := client.Search("Bach", "Goldberg Variations")
+	releases, err := client.Search(context.Background(), "Bach", "Goldberg Variations")
 	if err != nil {
 		t.Fatalf("Search() error = %v", err)
 	}
@@ -99,7 +103,7 @@ func TestClient_Search_NoResults(t *testing.T) {
 	client := NewClient("test-token")
 	client.BaseURL = server.URL
 
-	releases, err := client.Search("Unknown Artist", "Unknown Album")
+	releases, err := client.Search(context.Background(), "Unknown Artist", "Unknown Album")
 	if err != nil {
 		t.Fatalf("Search() error = %v", err)
 	}
@@ -109,6 +113,65 @@ func TestClient_Search_NoResults(t *testing.T) {
 	}
 }
 
+func TestClient_SearchTitles(t *testing.T) {
+	responses := map[string]string{
+		"Noël!":        `{"results": [{"id": 1001, "title": "Noël!"}]}`,
+		"Weihnachten!": `{"results": [{"id": 1001, "title": "Noël!"}, {"id": 1002, "title": "Weihnachten!"}]}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		response, ok := responses[r.URL.Query().Get("release_title")]
+		if !ok {
+			response = `{"results": []}`
+		}
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	releases, err := client.SearchTitles(context.Background(), "SearchTitles Choir", []string{"Noël!", "Christmas!", "Weihnachten!"})
+	if err != nil {
+		t.Fatalf("SearchTitles() error = %v", err)
+	}
+
+	if len(releases) != 2 {
+		t.Fatalf("Expected 2 deduplicated releases, got %d", len(releases))
+	}
+	if releases[0].ID != 1001 || releases[1].ID != 1002 {
+		t.Errorf("Expected IDs [1001 1002], got [%d %d]", releases[0].ID, releases[1].ID)
+	}
+}
+
+func TestClient_RateLimited(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	_, err := client.Search(context.Background(), "Rate Limited Artist", "Album")
+	var rateLimited *ratelimit.ErrRateLimited
+	if !errors.As(err, &rateLimited) || rateLimited.RetryAfter != 30*time.Second {
+		t.Errorf("Search() error = %v, want ErrRateLimited retrying after 30s", err)
+	}
+	if stats := client.RateLimiter.Stats(); stats.RateLimited != 1 || stats.Slowdown != 2 {
+		t.Errorf("RateLimiter.Stats() = %+v, want the 429 recorded and the rate halved", stats)
+	}
+
+	// The limiter now holds requests for the 30s Retry-After
+	client.RateLimiter = ratelimit.NewRateLimiter(60, time.Minute)
+	if _, err := client.GetRelease(context.Background(), 424242); !errors.As(err, &rateLimited) {
+		t.Errorf("GetRelease() error = %v, want ErrRateLimited", err)
+	}
+}
+
 func TestClient_SearchSimple(t *testing.T) {
 	mockResponse := `{
 		"results": [
@@ -160,7 +223,7 @@ func TestClient_SearchSimple(t *testing.T) {
 	client := NewClient("test-token")
 	client.BaseURL = server.URL
 
-	releases, err := client.SearchSimple("RIAS Kammerchor Noël Christmas Weihnachten")
+	releases, err := client.SearchSimple(context.Background(), "RIAS Kammerchor Noël Christmas Weihnachten")
 	if err != nil {
 		t.Fatalf("SearchSimple() error = %v", err)
 	}
@@ -221,7 +284,7 @@ func TestClient_GetRelease(t *testing.T) {
 	client := NewClient("test-token")
 	client.BaseURL = server.URL
 
-	release, err := client.GetRelease(195873)
+	release, err := client.GetRelease(context.Background(), 195873)
 	if err != nil {
 		t.Fatalf("GetRelease() error = %v", err)
 	}
@@ -250,7 +313,7 @@ func TestClient_GetRelease_NotFound(t *testing.T) {
 	client := NewClient("test-token")
 	client.BaseURL = server.URL
 
-	release, err := client.GetRelease(999999)
+	release, err := client.GetRelease(context.Background(), 999999)
 	if err == nil {
 		t.Error("Expected error for not found release")
 	}
@@ -259,6 +322,29 @@ func TestClient_GetRelease_NotFound(t *testing.T) {
 	}
 }
 
+func TestClient_Image(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Discogs token=test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if r.URL.Path != "/images/R-195873-1.jpg" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("jpeg data"))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	data, err := client.Image(context.Background(), server.URL+"/images/R-195873-1.jpg")
+	if err != nil || string(data) != "jpeg data" {
+		t.Errorf("Image() = %q, %v", data, err)
+	}
+	if _, err := client.Image(context.Background(), server.URL+"/images/missing.jpg"); !errors.Is(err, domain.ErrAPI) {
+		t.Errorf("Image() of a missing image error = %v, want ErrAPI", err)
+	}
+}
+
 func TestRelease_MarshalJSON(t *testing.T) {
 	release := &Release{
 		ID:            123,
@@ -424,3 +510,75 @@ func TestRelease_DomainTorrent(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_SearchIdentifiers(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("type") != "release" || q.Get("format") != "" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case q.Get("catno") == "HMC 902170" && q.Get("label") == "Harmonia Mundi":
+			w.Write([]byte(`{"results": [{"id": 5001, "title": "Christmas Album", "catno": "HMC 902170"}]}`))
+		case q.Get("barcode") == "3149020217021":
+			w.Write([]byte(`{"results": [{"id": 5001, "title": "Christmas Album"}]}`))
+		default:
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+			w.Write([]byte(`{"results": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	releases, err := client.SearchCatalogNumber(context.Background(), "HMC 902170", "Harmonia Mundi")
+	if err != nil || len(releases) != 1 || releases[0].CatalogNumber != "HMC 902170" {
+		t.Errorf("SearchCatalogNumber() = %v, %v", releases, err)
+	}
+
+	releases, err = client.SearchBarcode(context.Background(), "3 149020 217021")
+	if err != nil || len(releases) != 1 || releases[0].ID != 5001 {
+		t.Errorf("SearchBarcode() = %v, %v", releases, err)
+	}
+}
+
+func TestClient_ContextCancellation(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient("test-token")
+	client.BaseURL = server.URL
+
+	// A request in flight is abandoned when the context is cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetRelease(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetRelease() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetRelease() took %v after cancellation", elapsed)
+	}
+
+	// So is a rate limiter wait
+	client.RateLimiter = ratelimit.NewRateLimiter(1, time.Hour)
+	client.RateLimiter.Wait(context.Background())
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Search(ctx, "Bach", "Goldberg Variations"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Search() error = %v, want context.Canceled", err)
+	}
+}
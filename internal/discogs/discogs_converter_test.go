@@ -1,6 +1,7 @@
 package discogs
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -156,6 +157,10 @@ func TestConvertDiscogsRelease_ErrorOnUnknownRole(t *testing.T) {
 	if err != nil && !strings.Contains(err.Error(), "cannot determine role") {
 		t.Errorf("Expected error message about role determination, got: %v", err)
 	}
+	var roleUnknown *domain.ErrRoleUnknown
+	if !errors.As(err, &roleUnknown) || roleUnknown.Artist != "Unknown Artist" {
+		t.Errorf("Expected *domain.ErrRoleUnknown for Unknown Artist, got: %v", err)
+	}
 }
 
 func TestConvertDiscogsRelease_SkipParentWorkEntries(t *testing.T) {
@@ -298,39 +303,166 @@ func TestParseDiscogsPosition(t *testing.T) {
 	*/
 
 	tests := []struct {
-		name      string
-		position  string
-		wantDisc  int
-		wantTrack int
+		name     string
+		position string
+		want     discogsPosition
 	}{
-		{"single number", "1", 1, 1},
-		{"disc-track format", "2-10", 2, 10},
-		{"CD prefix", "CD3-2", 3, 2},
-		{"empty", "", 1, 0},
-		{"invalid", "abc", 1, 0},
-		{"track 20", "20", 1, 20},
-		{"with sides", "B4", 1, 0},       // TODO: should be 2, 1
-		{"multiple 12\" LP", "C3", 1, 0}, // TODO: should be 3, 1
-		{"with programs", "D8", 1, 0},    // TODO: should be 4, 8
-		{"multiple CDs", "3-99", 3, 99},
-		{"multi-disc or multi-format releases", "DVD9-88", 9, 88},
-		{"sub tracks", "3.4", 1, 0},       // TODO: should be 1, "3.4"
-		{"enhanced CDs", "Video 1", 1, 0}, // TODO: should be 1, "Video 1"
-		{"enhanced CDs", "Video 2", 1, 0}, // TODO: should be 1, "Video 2"
+		{"single number", "1", discogsPosition{Disc: 1, Track: 1}},
+		{"disc-track format", "2-10", discogsPosition{Disc: 2, Track: 10}},
+		{"CD prefix", "CD3-2", discogsPosition{Disc: 3, Track: 2}},
+		{"empty", "", discogsPosition{Disc: 1}},
+		{"invalid", "abc", discogsPosition{Disc: 1}},
+		{"track 20", "20", discogsPosition{Disc: 1, Track: 20}},
+		{"with sides", "B4", discogsPosition{Disc: 1, Track: 4, Side: "B"}},
+		{"multiple 12\" LP", "C3", discogsPosition{Disc: 2, Track: 3, Side: "C"}},
+		{"with programs", "D8", discogsPosition{Disc: 2, Track: 8, Side: "D"}},
+		{"multiple CDs", "3-99", discogsPosition{Disc: 3, Track: 99}},
+		{"multi-disc or multi-format releases", "DVD9-88", discogsPosition{Disc: 9, Track: 88}},
+		{"sub tracks", "3.4", discogsPosition{Disc: 1, Track: 3, Sub: "4"}},
+		{"sub tracks with side and point", "A3.a", discogsPosition{Disc: 1, Track: 3, Side: "A", Sub: "a"}},
+		{"sub tracks with side no point", "A3b", discogsPosition{Disc: 1, Track: 3, Side: "A", Sub: "b"}},
+		{"sub tracks on disc", "2-3.1", discogsPosition{Disc: 2, Track: 3, Sub: "1"}},
+		{"enhanced CDs", "Video 1", discogsPosition{Disc: 1, Video: true}},
+		{"enhanced CDs", "Video 2", discogsPosition{Disc: 1, Video: true}},
+		{"track zero", "0", discogsPosition{Disc: 1}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			disc, track := parseDiscogsPosition(tt.position)
-			if disc != tt.wantDisc {
-				t.Errorf("parseDiscogsPosition(%q) disc = %d, want %d", tt.position, disc, tt.wantDisc)
-			}
-			if track != tt.wantTrack {
-				t.Errorf("parseDiscogsPosition(%q) track = %d, want %d", tt.position, track, tt.wantTrack)
+			got := parseDiscogsPosition(tt.position)
+			if got != tt.want {
+				t.Errorf("parseDiscogsPosition(%q) = %+v, want %+v", tt.position, got, tt.want)
 			}
 		})
 	}
 }
+
+func TestConvertDiscogsRelease_DottedSubPositions(t *testing.T) {
+	release := &Release{
+		Title: "Test Album",
+		Year:  2013,
+		Tracklist: []Track{
+			{Position: "1", Title: "Overture", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+			{Position: "2.1", Title: "Allegro", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+			{Position: "2.2", Title: "Adagio", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+			{Position: "3", Title: "Finale", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+		},
+	}
+
+	torrent, notes, err := release.DomainTorrentWithNotes("", nil)
+	if err != nil {
+		t.Fatalf("DomainTorrentWithNotes failed: %v", err)
+	}
+
+	tracks := torrent.Tracks()
+	if len(tracks) != 4 {
+		t.Fatalf("Expected 4 tracks, got %d", len(tracks))
+	}
+	for i, track := range tracks {
+		if track.Track != i+1 || track.Disc != 1 {
+			t.Errorf("Track %d (%s): got disc %d track %d", i, track.Title, track.Disc, track.Track)
+		}
+	}
+	// 2.1, 2.2 and the shifted 3 are renumbered
+	if len(notes) != 3 {
+		t.Errorf("Expected 3 notes, got %d: %v", len(notes), notes)
+	}
+}
+
+func TestConvertDiscogsRelease_SidePositions(t *testing.T) {
+	release := &Release{
+		Title: "Test Album",
+		Year:  1975,
+		Tracklist: []Track{
+			{Position: "A1", Title: "Side A One", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+			{Position: "A2", Title: "Side A Two", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+			{Position: "B1", Title: "Side B One", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+			{Position: "C1", Title: "Side C One", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+			{Position: "D1", Title: "Side D One", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+		},
+	}
+
+	torrent, notes, err := release.DomainTorrentWithNotes("", nil)
+	if err != nil {
+		t.Fatalf("DomainTorrentWithNotes failed: %v", err)
+	}
+
+	tracks := torrent.Tracks()
+	want := [][2]int{{1, 1}, {1, 2}, {1, 3}, {2, 1}, {2, 2}}
+	if len(tracks) != len(want) {
+		t.Fatalf("Expected %d tracks, got %d", len(want), len(tracks))
+	}
+	for i, track := range tracks {
+		if track.Disc != want[i][0] || track.Track != want[i][1] {
+			t.Errorf("%s: got disc %d track %d, want disc %d track %d",
+				track.Title, track.Disc, track.Track, want[i][0], want[i][1])
+		}
+	}
+	if len(notes) != len(want) {
+		t.Errorf("Expected %d notes, got %d: %v", len(want), len(notes), notes)
+	}
+}
+
+func TestConvertDiscogsRelease_VideoTracksFlagged(t *testing.T) {
+	release := &Release{
+		Title: "Test Album",
+		Year:  2005,
+		Tracklist: []Track{
+			{Position: "1", Title: "Audio", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+			{Position: "Video 1", Title: "Making Of", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+		},
+	}
+
+	torrent, notes, err := release.DomainTorrentWithNotes("", nil)
+	if err != nil {
+		t.Fatalf("DomainTorrentWithNotes failed: %v", err)
+	}
+	if len(torrent.Tracks()) != 1 {
+		t.Errorf("Expected 1 audio track, got %d", len(torrent.Tracks()))
+	}
+	if len(notes) != 1 || !strings.Contains(notes[0], "Video 1") {
+		t.Errorf("Expected a note for the video track, got %v", notes)
+	}
+}
+
+func TestConvertDiscogsRelease_IndexSubtracksFoldIntoParent(t *testing.T) {
+	release := &Release{
+		Title: "Test Album",
+		Year:  2013,
+		Tracklist: []Track{
+			{
+				Position: "1",
+				Title:    "Symphony",
+				Artists:  []Artist{{Name: "Composer1", Role: "Composed By"}},
+				SubTracks: []Track{
+					{Position: "1.1", Title: "Allegro"},
+					{Position: "1.2", Title: "Adagio"},
+				},
+			},
+			{Position: "2", Title: "Encore", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+		},
+	}
+
+	torrent, notes, err := release.DomainTorrentWithNotes("", nil)
+	if err != nil {
+		t.Fatalf("DomainTorrentWithNotes failed: %v", err)
+	}
+
+	tracks := torrent.Tracks()
+	if len(tracks) != 2 {
+		t.Fatalf("Expected 2 tracks, got %d", len(tracks))
+	}
+	if tracks[0].Title != "Symphony" || tracks[0].Track != 1 {
+		t.Errorf("Track 1 should be the parent work, got %q (%d)", tracks[0].Title, tracks[0].Track)
+	}
+	if tracks[1].Track != 2 {
+		t.Errorf("Encore should stay track 2, got %d", tracks[1].Track)
+	}
+	if len(notes) != 2 {
+		t.Errorf("Expected 2 notes for folded sub-tracks, got %d: %v", len(notes), notes)
+	}
+}
+
 func TestConvertDiscogsRelease_ProcessSubtracks(t *testing.T) {
 	release := &Release{
 		Title: "Test Album",
@@ -565,6 +697,8 @@ func TestMapDiscogsRoleToDomain(t *testing.T) {
 		{"empty role with ensemble name", Artist{Name: "Berlin Philharmonic", Role: ""}, domain.RoleEnsemble},
 		{"empty role with soloist name", Artist{Name: "Vladimir Horowitz", Role: ""}, domain.RoleUnknown},
 		{"unknown role", Artist{Name: "Artist", Role: "Unknown"}, domain.RoleUnknown},
+		{"instrument", Artist{Name: "Martha Argerich", Role: "Piano"}, domain.RoleSoloist},
+		{"voice", Artist{Name: "Dorothea Röschmann", Role: "Soprano Vocals"}, domain.RoleSoloist},
 	}
 
 	for _, tt := range tests {
@@ -576,3 +710,135 @@ func TestMapDiscogsRoleToDomain(t *testing.T) {
 		})
 	}
 }
+
+func TestRole_Instrument(t *testing.T) {
+	tests := map[Role]string{
+		"Piano":                 "piano",
+		"Violin [Solo]":         "violin",
+		"Soprano Vocals":        "soprano",
+		"Harpsichord, Organ":    "harpsichord",
+		"Conductor":             "",
+		"Liner Notes":           "",
+		"Recorded By, Producer": "",
+	}
+	for role, want := range tests {
+		if got := role.Instrument(); got != want {
+			t.Errorf("Role(%q).Instrument() = %q, want %q", role, got, want)
+		}
+	}
+}
+
+func TestRole_Character(t *testing.T) {
+	tests := map[Role]string{
+		"Soprano Vocals [Violetta Valéry]":       "Violetta Valéry",
+		"Vocals [Alfredo Germont]":               "Alfredo Germont",
+		"Baritone Vocals [Giorgio], Liner Notes": "Giorgio",
+		"Soprano Vocals":                         "",
+		"Violin [Solo]":                          "",
+		"Chorus Master":                          "",
+	}
+	for role, want := range tests {
+		if got := role.Character(); got != want {
+			t.Errorf("Role(%q).Character() = %q, want %q", role, got, want)
+		}
+	}
+}
+
+func TestConvertDiscogsRelease_Instruments(t *testing.T) {
+	release := &Release{
+		Title: "Piano Concerto No. 3",
+		Year:  1967,
+		Artists: []Artist{
+			{Name: "Martha Argerich"},
+			{Name: "Berliner Philharmoniker", Role: "Orchestra"},
+		},
+		ExtraArtists: []Artist{
+			{Name: "Martha Argerich", Role: "Piano"},
+			{Name: "Sergei Prokofiev", Role: "Composed By"},
+		},
+		Tracklist: []Track{
+			{Position: "1", Title: "I. Andante - Allegro"},
+		},
+	}
+
+	torrent, err := release.DomainTorrent("test-path", nil)
+	if err != nil {
+		t.Fatalf("DomainTorrent() error = %v", err)
+	}
+	for _, artists := range [][]domain.Artist{torrent.AlbumArtist, torrent.Tracks()[0].Artists} {
+		for _, a := range artists {
+			want := ""
+			if a.Name == "Martha Argerich" {
+				want = "piano"
+				if a.Role != domain.RoleSoloist {
+					t.Errorf("Martha Argerich role = %v, want soloist", a.Role)
+				}
+			}
+			if a.Instrument != want {
+				t.Errorf("%s instrument = %q, want %q", a.Name, a.Instrument, want)
+			}
+		}
+	}
+}
+
+func TestConvertDiscogsRelease_Sources(t *testing.T) {
+	release := &Release{
+		ID:        4321,
+		Title:     "Motets",
+		Year:      2013,
+		Tracklist: []Track{{Position: "1", Title: "Ave Maria", Artists: []Artist{{Name: "Anton Bruckner", Role: "Composed By"}}}},
+	}
+	torrent, err := release.DomainTorrent("test-path", nil)
+	if err != nil {
+		t.Fatalf("DomainTorrent() error = %v", err)
+	}
+	if len(torrent.Sources) != 1 || torrent.Sources[0] != "https://www.discogs.com/release/4321" {
+		t.Errorf("Sources = %v, want the release URL", torrent.Sources)
+	}
+	if torrent.Edition == nil || torrent.Edition.DiscogsReleaseID != 4321 {
+		t.Errorf("Edition = %+v, want DiscogsReleaseID 4321", torrent.Edition)
+	}
+}
+
+func TestConvertDiscogsRelease_TitleVariants(t *testing.T) {
+	release := &Release{
+		Title: "Noël! = Christmas! = Weihnachten!",
+		Year:  2004,
+		Tracklist: []Track{
+			{Position: "1", Title: "Carol", Artists: []Artist{{Name: "Composer1", Role: "Composed By"}}},
+		},
+	}
+
+	torrent, err := release.DomainTorrent("", nil)
+	if err != nil {
+		t.Fatalf("DomainTorrent failed: %v", err)
+	}
+	if torrent.Title != "Noël!" {
+		t.Errorf("Title = %q, want %q", torrent.Title, "Noël!")
+	}
+	if len(torrent.AlternateTitles) != 2 || torrent.AlternateTitles[0] != "Christmas!" || torrent.AlternateTitles[1] != "Weihnachten!" {
+		t.Errorf("AlternateTitles = %v", torrent.AlternateTitles)
+	}
+}
+
+func TestConvertDiscogsRelease_RecordingYears(t *testing.T) {
+	release := &Release{
+		Title: "Goldberg Variations",
+		Year:  1982,
+		Notes: "Recorded April-May 1981, 30th Street Studio, New York\nPublished 1741\n℗ 1982 CBS Inc.",
+		Tracklist: []Track{
+			{Position: "1", Title: "Aria", Artists: []Artist{{Name: "Johann Sebastian Bach", Role: "Composed By"}}},
+		},
+	}
+
+	torrent, err := release.DomainTorrent("", nil)
+	if err != nil {
+		t.Fatalf("DomainTorrent failed: %v", err)
+	}
+	if torrent.OriginalYear != 1982 {
+		t.Errorf("OriginalYear = %d, want 1982", torrent.OriginalYear)
+	}
+	if len(torrent.RecordingYears) != 1 || torrent.RecordingYears[0] != 1981 {
+		t.Errorf("RecordingYears = %v, want [1981]", torrent.RecordingYears)
+	}
+}
@@ -0,0 +1,42 @@
+package discogs
+
+import (
+	"sync"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+var (
+	knownMu    sync.RWMutex
+	knownRoles = map[string]domain.Role{} // Normalized name -> role
+)
+
+// ConfigureKnownRoles sets the roles assigned to artists by hand (artist name
+// -> role), which inferRoleFromName consults before guessing from the name.
+func ConfigureKnownRoles(roles map[string]domain.Role) {
+	known := make(map[string]domain.Role, len(roles))
+	for name, role := range roles {
+		if role != domain.RoleUnknown {
+			known[normalize.Name(name)] = role
+		}
+	}
+	knownMu.Lock()
+	knownRoles = known
+	knownMu.Unlock()
+}
+
+// RememberRole adds one hand-assigned role to the known roles.
+func RememberRole(name string, role domain.Role) {
+	knownMu.Lock()
+	knownRoles[normalize.Name(name)] = role
+	knownMu.Unlock()
+}
+
+// knownRole returns the hand-assigned role for the artist, if any.
+func knownRole(name string) (domain.Role, bool) {
+	knownMu.RLock()
+	defer knownMu.RUnlock()
+	role, ok := knownRoles[normalize.Name(name)]
+	return role, ok
+}
@@ -0,0 +1,30 @@
+package discogs
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestInferRoleFromName_KnownRoles(t *testing.T) {
+	defer ConfigureKnownRoles(nil)
+
+	if got := inferRoleFromName("Hans-Christoph Rademann"); got != domain.RoleUnknown {
+		t.Fatalf("inferRoleFromName() = %v before any role is known, want unknown", got)
+	}
+
+	ConfigureKnownRoles(map[string]domain.Role{"Hans-Christoph Rademann": domain.RoleConductor})
+	// Matched ignoring case
+	if got := inferRoleFromName("HANS-CHRISTOPH RADEMANN"); got != domain.RoleConductor {
+		t.Errorf("inferRoleFromName() = %v, want the known conductor role", got)
+	}
+
+	// A known role wins over the ensemble keyword guess
+	RememberRole("Trio Mediaeval", domain.RolePerformer)
+	if got := inferRoleFromName("Trio Mediaeval"); got != domain.RolePerformer {
+		t.Errorf("inferRoleFromName() = %v, want the remembered performer role", got)
+	}
+	if got := inferRoleFromName("Berliner Philharmoniker Orchestra"); got != domain.RoleEnsemble {
+		t.Errorf("inferRoleFromName() = %v, want the ensemble guess", got)
+	}
+}
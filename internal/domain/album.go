@@ -2,12 +2,15 @@ package domain
 
 // Album represents a classical music release.
 type Album struct {
-	FolderName   string   `json:"folder_name"`
-	Title        string   `json:"title"`
-	OriginalYear int      `json:"original_year"`
-	Edition      *Edition `json:"edition,omitempty"`
-	AlbumArtist  []Artist `json:"album_artist,omitempty"`
-	Tracks       []*Track `json:"tracks"`
+	FolderName     string   `json:"folder_name"`
+	Title          string   `json:"title"`
+	OriginalYear   int      `json:"original_year"`
+	RecordingYears []int    `json:"recording_years,omitempty"`
+	Edition        *Edition `json:"edition,omitempty"`
+	AlbumArtist    []Artist `json:"album_artist,omitempty"`
+	Tracks         []*Track `json:"tracks"`
+
+	AllowMissingComposer bool `json:"allow_missing_composer,omitempty"` // Crossover/recital disc; see Torrent
 }
 
 // IsMultiDisc returns true if the album contains tracks from multiple discs.
@@ -89,12 +92,15 @@ func (a *Album) ToTorrent(rootPath string) *Torrent {
 	}
 
 	return &Torrent{
-		RootPath:     rootPath,
-		Title:        a.Title,
-		OriginalYear: a.OriginalYear,
-		Edition:      a.Edition,
-		AlbumArtist:  a.AlbumArtist,
-		Files:        fs,
-		SiteMetadata: nil, // Not available from Album
+		RootPath:       rootPath,
+		Title:          a.Title,
+		OriginalYear:   a.OriginalYear,
+		RecordingYears: a.RecordingYears,
+		Edition:        a.Edition,
+		AlbumArtist:    a.AlbumArtist,
+		Files:          fs,
+		SiteMetadata:   nil, // Not available from Album
+
+		AllowMissingComposer: a.AllowMissingComposer,
 	}
-}
\ No newline at end of file
+}
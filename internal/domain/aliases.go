@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+// ArticleKey returns the comparison key for an artist name, ignoring case, Unicode
+// normalization form and a leading definite article, so "The English Concert" and
+// "English Concert" share a key.
+func ArticleKey(name string) string {
+	return strings.TrimPrefix(normalize.Key(name, 0), "the ")
+}
+
+// AliasTable maps artist name variants to their canonical spelling, keyed by ArticleKey.
+type AliasTable map[string]string
+
+// NewAliasTable builds an alias table from variant -> canonical pairs (typically from config).
+// Each canonical name is also registered as its own alias.
+func NewAliasTable(aliases map[string]string) AliasTable {
+	table := make(AliasTable, 2*len(aliases))
+	for variant, canonical := range aliases {
+		table[ArticleKey(variant)] = canonical
+		table[ArticleKey(canonical)] = canonical
+	}
+	return table
+}
+
+// Prefer makes name the canonical spelling for its key, overriding any configured alias.
+// Used to adopt spellings that already exist elsewhere, such as site artist pages.
+func (a AliasTable) Prefer(name string) {
+	a[ArticleKey(name)] = name
+}
+
+// Canonical returns the canonical spelling for name, if the table has one.
+func (a AliasTable) Canonical(name string) (string, bool) {
+	canonical, ok := a[ArticleKey(name)]
+	return canonical, ok
+}
+
+// NormalizeArtistNames makes each artist's spelling consistent across album and track
+// artists. Special composers take their canonical spelling ("Anon." -> "Anonymous");
+// other names sharing an ArticleKey take the alias table's spelling, or else the most
+// common spelling in the torrent (the one with "The" on a tie). Returns a description of
+// each rename.
+func (t *Torrent) NormalizeArtistNames(aliases AliasTable) []string {
+	counts := make(map[string]map[string]int) // key -> spelling -> uses
+	t.eachArtist(func(a *Artist) {
+		key := ArticleKey(a.Name)
+		if counts[key] == nil {
+			counts[key] = make(map[string]int)
+		}
+		counts[key][a.Name]++
+	})
+
+	canonical := make(map[string]string)
+	for key, spellings := range counts {
+		if name, ok := aliases.Canonical(key); ok {
+			canonical[key] = name
+			continue
+		}
+		if len(spellings) > 1 {
+			canonical[key] = mostCommonSpelling(spellings)
+		}
+	}
+
+	renamed := make(map[string]string)
+	t.eachArtist(func(a *Artist) {
+		if a.IsSpecialComposer() {
+			if name, _ := SpecialComposer(a.Name); name != a.Name {
+				renamed[a.Name] = name
+				a.Name = name
+			}
+			return
+		}
+		if name, ok := canonical[ArticleKey(a.Name)]; ok && name != a.Name {
+			renamed[a.Name] = name
+			a.Name = name
+		}
+	})
+
+	notes := make([]string, 0, len(renamed))
+	for from, to := range renamed {
+		notes = append(notes, fmt.Sprintf("%q -> %q", from, to))
+	}
+	sort.Strings(notes)
+	return notes
+}
+
+// mostCommonSpelling picks the most used spelling, preferring "The ..." and then
+// alphabetical order on ties so the result is deterministic.
+func mostCommonSpelling(spellings map[string]int) string {
+	names := make([]string, 0, len(spellings))
+	for name := range spellings {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := names[i], names[j]
+		if spellings[a] != spellings[b] {
+			return spellings[a] > spellings[b]
+		}
+		if hasArticle(a) != hasArticle(b) {
+			return hasArticle(a)
+		}
+		return a < b
+	})
+	return names[0]
+}
+
+func hasArticle(name string) bool {
+	return strings.HasPrefix(strings.ToLower(name), "the ")
+}
+
+// eachArtist calls fn for every album and track artist.
+func (t *Torrent) eachArtist(fn func(*Artist)) {
+	for i := range t.AlbumArtist {
+		fn(&t.AlbumArtist[i])
+	}
+	for _, track := range t.Tracks() {
+		for i := range track.Artists {
+			fn(&track.Artists[i])
+		}
+	}
+}
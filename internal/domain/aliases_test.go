@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArticleKey(t *testing.T) {
+	if ArticleKey("The English Concert") != ArticleKey("english concert") {
+		t.Errorf("ArticleKey should ignore case and a leading \"The\"")
+	}
+	if ArticleKey("Theatre of Voices") == ArticleKey("atre of Voices") {
+		t.Errorf("ArticleKey should only strip \"The\" as a separate word")
+	}
+}
+
+func TestTorrent_NormalizeArtistNames(t *testing.T) {
+	build := func() *Torrent {
+		return &Torrent{
+			AlbumArtist: []Artist{{Name: "English Concert", Role: RoleEnsemble}},
+			Files: []FileLike{
+				&Track{Track: 1, Artists: []Artist{{Name: "The English Concert", Role: RoleEnsemble}, {Name: "Trevor Pinnock", Role: RoleConductor}}},
+				&Track{Track: 2, Artists: []Artist{{Name: "The English Concert", Role: RoleEnsemble}}},
+			},
+		}
+	}
+
+	t.Run("most common spelling", func(t *testing.T) {
+		torrent := build()
+		notes := torrent.NormalizeArtistNames(nil)
+		if torrent.AlbumArtist[0].Name != "The English Concert" {
+			t.Errorf("album artist = %q, want The English Concert", torrent.AlbumArtist[0].Name)
+		}
+		if want := []string{`"English Concert" -> "The English Concert"`}; !reflect.DeepEqual(notes, want) {
+			t.Errorf("notes = %v, want %v", notes, want)
+		}
+	})
+
+	t.Run("alias table wins", func(t *testing.T) {
+		torrent := build()
+		torrent.NormalizeArtistNames(NewAliasTable(map[string]string{"The English Concert": "English Concert"}))
+		for _, track := range torrent.Tracks() {
+			if track.Artists[0].Name != "English Concert" {
+				t.Errorf("track %d artist = %q, want English Concert", track.Track, track.Artists[0].Name)
+			}
+		}
+	})
+
+	t.Run("preferred spelling overrides alias", func(t *testing.T) {
+		torrent := build()
+		aliases := NewAliasTable(map[string]string{"The English Concert": "English Concert"})
+		aliases.Prefer("The English Concert")
+		torrent.NormalizeArtistNames(aliases)
+		if torrent.AlbumArtist[0].Name != "The English Concert" {
+			t.Errorf("album artist = %q, want The English Concert", torrent.AlbumArtist[0].Name)
+		}
+	})
+}
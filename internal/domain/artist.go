@@ -1,12 +1,18 @@
 package domain
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 // Artist represents a person involved in a recording.
 // All fields are exported and mutable.
 type Artist struct {
-	Name string `json:"name"`
-	Role Role   `json:"role"`
+	Name       string `json:"name"`
+	Role       Role   `json:"role"`
+	SortName   string `json:"sort_name,omitempty"`  // e.g. "Beethoven, Ludwig van"; derived when empty
+	Instrument string `json:"instrument,omitempty"` // Soloist's instrument or voice, e.g. "piano", "soprano"
+	Character  string `json:"character,omitempty"`  // Operatic role sung, e.g. "Violetta"
 }
 
 // String returns a string representation of the artist (Name - Role).
@@ -14,6 +20,103 @@ func (a Artist) String() string {
 	return a.Name + " (" + a.Role.String() + ")"
 }
 
+// Credit returns the name as credited in performer lists: "Martha Argerich (piano)"
+// when the instrument is known, otherwise just the name.
+func (a Artist) Credit() string {
+	if a.Instrument == "" {
+		return a.Name
+	}
+	return a.Name + " (" + a.Instrument + ")"
+}
+
+// TagCredit returns the credit written to the PERFORMER tag: the Credit, with the
+// character a singer sings after the voice, "Anna Netrebko (soprano, Violetta)".
+// The character is left out when the voice is not known.
+func (a Artist) TagCredit() string {
+	if a.Character == "" || a.Instrument == "" {
+		return a.Credit()
+	}
+	return a.Name + " (" + a.Instrument + ", " + a.Character + ")"
+}
+
+// voices are the instruments that are singing voices.
+var voices = []string{
+	"voice", "vocals", "soprano", "mezzo-soprano", "alto", "contralto", "countertenor",
+	"tenor", "baritone", "bass-baritone", "bass", "treble", "boy soprano",
+}
+
+// IsVoice reports whether instrument is a singing voice ("soprano", "vocals").
+func IsVoice(instrument string) bool {
+	return slices.Contains(voices, strings.ToLower(strings.TrimSpace(instrument)))
+}
+
+// SplitCharacter splits the instrument of a PERFORMER credit written by
+// TagCredit, "soprano, Violetta", into the voice and the character sung. An
+// instrument that does not start with a voice is returned whole.
+func SplitCharacter(instrument string) (voice, character string) {
+	voice, character, ok := strings.Cut(instrument, ",")
+	if !ok || !IsVoice(voice) {
+		return instrument, ""
+	}
+	return strings.TrimSpace(voice), strings.TrimSpace(character)
+}
+
+// ParseCredit splits a performer credit in the Vorbis PERFORMER convention,
+// "Martha Argerich (piano)", into name and instrument. A credit without a trailing
+// parenthesis is all name.
+func ParseCredit(credit string) (name, instrument string) {
+	credit = strings.TrimSpace(credit)
+	if !strings.HasSuffix(credit, ")") {
+		return credit, ""
+	}
+	open := strings.LastIndex(credit, " (")
+	if open <= 0 {
+		return credit, ""
+	}
+	return strings.TrimSpace(credit[:open]), strings.TrimSpace(credit[open+2 : len(credit)-1])
+}
+
+// SortKey returns the artist's sort name, deriving one from Name when SortName is not set.
+func (a Artist) SortKey() string {
+	if a.SortName != "" {
+		return a.SortName
+	}
+	return DeriveSortName(a.Name, a.Role)
+}
+
+// LastName returns the family name used for brevity in directory names.
+// An explicit SortName is authoritative; the last-word heuristic is only a fallback.
+// Special composers ("Gregorian Chant") are used whole.
+func (a Artist) LastName() string {
+	if a.IsSpecialComposer() {
+		return a.Name
+	}
+	if before, _, ok := strings.Cut(a.SortName, ","); ok {
+		return strings.TrimSpace(before)
+	}
+	parts := strings.Fields(a.Name)
+	if len(parts) == 0 {
+		return a.Name
+	}
+	return parts[len(parts)-1]
+}
+
+// DeriveSortName derives a "Last, First" sort name for a person.
+// Names already in sort order, ensembles and special composers are returned unchanged.
+// "Ludwig van Beethoven" -> "Beethoven, Ludwig van"
+func DeriveSortName(name string, role Role) string {
+	name = strings.TrimSpace(name)
+	if role == RoleEnsemble || strings.Contains(name, ",") || (Artist{Name: name, Role: role}).IsSpecialComposer() {
+		return name
+	}
+	parts := strings.Fields(name)
+	if len(parts) < 2 {
+		return name
+	}
+	last := parts[len(parts)-1]
+	return last + ", " + strings.Join(parts[:len(parts)-1], " ")
+}
+
 // ParseArtist creates an Artist from name and role string.
 func ParseArtist(name, roleStr string) (Artist, error) {
 	name = strings.TrimSpace(name)
@@ -34,30 +137,37 @@ func ParseArtist(name, roleStr string) (Artist, error) {
 
 // ParseArtistField parses a comma or semicolon-separated artist tag field into individual artists.
 // Handles formats like "Soloist; Orchestra; Conductor" or "Soloist, Orchestra, Conductor".
+// Reversed names ("Bach, Johann Sebastian") are kept whole and put in natural order with
+// their sort name; life dates ("(1685-1750)") and leading honorifics ("Sir") are dropped.
 // Returns a slice of artists with RoleUnknown (roles should be inferred from context).
 // This is used for parsing FLAC tags where multiple artists may be stored in a single tag.
 func ParseArtistField(artistField string) []Artist {
 	artists := make([]Artist, 0)
 
-	// Try semicolon separator first (more reliable)
-	names := strings.Split(artistField, ";")
-	if len(names) == 1 {
-		names = strings.Split(artistField, ",")
-	}
-
-	for _, name := range names {
-		name = strings.TrimSpace(name)
-		if name == "" {
-			continue
-		}
-
+	for _, name := range splitArtistField(artistField) {
 		// Do not infer roles from names; preserve original order and mark as Unknown
 		// Roles should be inferred from context (e.g., ALBUMARTIST vs ARTIST vs COMPOSER tags)
-		artists = append(artists, Artist{
-			Name: name,
-			Role: RoleUnknown,
-		})
+		if artist, ok := parseArtistName(name); ok {
+			artists = append(artists, artist)
+		}
 	}
 
 	return artists
 }
+
+// ParseArtistFields parses the values of a Vorbis comment artist tag that may repeat
+// (ARTIST=, COMPOSER=, ...). Repeated fields hold one artist each, so a name with a
+// comma stays whole; a single field is split as ParseArtistField does, since taggers
+// writing one field put every artist in it.
+func ParseArtistFields(fields []string) []Artist {
+	if len(fields) == 1 {
+		return ParseArtistField(fields[0])
+	}
+	artists := make([]Artist, 0, len(fields))
+	for _, field := range fields {
+		if artist, ok := parseArtistName(field); ok {
+			artists = append(artists, artist)
+		}
+	}
+	return artists
+}
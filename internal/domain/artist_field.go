@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// honorifics are titles dropped from the front of names: Redacted credits
+// "Simon Rattle", not "Sir Simon Rattle".
+var honorifics = []string{"Sir", "Dame", "Lord", "Dr.", "Dr", "Prof.", "Professor", "Maestro"}
+
+// artistDatesPattern matches life dates appended to a name: "(1685-1750)",
+// "[1685–1750]", "(b. 1950)", "(d. 1901)", "(c.1450-1521)".
+var artistDatesPattern = regexp.MustCompile(`\s*[(\[]\s*(?:(?:b|d|c|ca)\.\s*|born\s+|died\s+)?\d{3,4}\??\s*(?:[-–—]\s*(?:(?:c|ca)\.\s*)?(?:\d{2,4})?\??\s*)?[)\]]`)
+
+// datesOnlyPattern matches a field part holding nothing but life dates, as left
+// by splitting "Bach, Johann Sebastian, 1685-1750" on commas.
+var datesOnlyPattern = regexp.MustCompile(`^[(\[]?\s*\d{3,4}\s*[-–—]\s*\d{2,4}\s*[)\]]?$`)
+
+// nameParticles are the lowercase particles of surnames ("van Beethoven", "de Falla").
+var nameParticles = []string{"van", "von", "de", "der", "den", "di", "da", "del", "della", "du", "des", "le", "la", "y", "zu"}
+
+// ensembleWords mark a name as an ensemble, which is never half of a reversed
+// personal name ("Pollini, Berlin Philharmonic").
+var ensembleWords = []string{
+	"orchestra", "orchester", "orchestre", "orquesta", "philharmonic", "philharmoniker", "philharmonia",
+	"symphony", "symphoniker", "ensemble", "choir", "chor", "chorus", "choeur", "consort", "quartet", "quartett",
+	"quatuor", "trio", "quintet", "sextet", "octet", "players", "soloists", "singers", "band", "academy", "kammerchor",
+	"sinfonietta", "camerata", "baroque", "opera", "staatskapelle", "concerto", "collegium", "capella", "cappella",
+}
+
+// initialPattern matches initials such as "J.", "J.S." and "C.P.E.".
+var initialPattern = regexp.MustCompile(`^(?:\p{Lu}\.)+$`)
+
+// splitArtistField splits a multi-value artist tag into names. Semicolons
+// separate artists when present; otherwise commas do, except the comma inside
+// a reversed name ("Bach, Johann Sebastian"), which stays with its name.
+func splitArtistField(field string) []string {
+	if strings.Contains(field, ";") {
+		return strings.Split(field, ";")
+	}
+
+	parts := strings.Split(field, ",")
+	var names []string
+	for i := 0; i < len(parts); i++ {
+		if i+1 < len(parts) && isReversedName(parts[i], parts[i+1]) {
+			names = append(names, parts[i]+","+parts[i+1])
+			i++
+			continue
+		}
+		names = append(names, parts[i])
+	}
+	return names
+}
+
+// isReversedName reports whether surname and given, separated by a comma in a
+// tag, are one person's name in sort order rather than two artists.
+func isReversedName(surname, given string) bool {
+	titled := cleanGivenNames(given) != stripDates(given) // "Rattle, Sir Simon"
+	surname, given = cleanArtistName(surname), cleanGivenNames(given)
+	surnameWords, givenWords := strings.Fields(surname), strings.Fields(given)
+	if len(surnameWords) == 0 || len(givenWords) == 0 || len(givenWords) > 3 {
+		return false
+	}
+
+	// The surname is one capitalized word, possibly after particles ("van Beethoven")
+	for i, w := range surnameWords {
+		if i < len(surnameWords)-1 && !isNameParticle(w) || i == len(surnameWords)-1 && !isCapitalizedWord(w) {
+			return false
+		}
+	}
+
+	// Given names are capitalized words or initials, possibly followed by particles ("Ludwig van")
+	telling := titled || len(givenWords) > 1
+	for i, w := range givenWords {
+		switch {
+		case initialPattern.MatchString(w):
+			telling = true
+		case i > 0 && isNameParticle(w):
+			telling = true
+		case !isCapitalizedWord(w):
+			return false
+		}
+	}
+	for _, w := range append(surnameWords, givenWords...) {
+		if isEnsembleWord(w) {
+			return false
+		}
+	}
+
+	// A single given name could as well be another artist's surname ("Brahms, Schumann"),
+	// unless the result is a known composer
+	if !telling {
+		_, known := ComposerLifetime(given + " " + surname)
+		return known
+	}
+	return true
+}
+
+// cleanArtistName strips life dates and a leading honorific from a name.
+func cleanArtistName(name string) string {
+	return stripHonorific(stripDates(name), 2)
+}
+
+// cleanGivenNames strips life dates and a leading honorific from the given
+// names of a reversed name ("Sir Simon" in "Rattle, Sir Simon").
+func cleanGivenNames(given string) string {
+	return stripHonorific(stripDates(given), 1)
+}
+
+func stripDates(name string) string {
+	return strings.TrimSpace(artistDatesPattern.ReplaceAllString(name, ""))
+}
+
+// stripHonorific drops a leading honorific when at least minWords words remain.
+func stripHonorific(name string, minWords int) string {
+	for _, title := range honorifics {
+		if rest, ok := strings.CutPrefix(name, title+" "); ok && len(strings.Fields(rest)) >= minWords {
+			return strings.TrimSpace(rest)
+		}
+	}
+	return name
+}
+
+// parseArtistName turns one name from an artist tag into an Artist, putting a
+// reversed name ("Bach, Johann Sebastian") in natural order with its sort name kept.
+func parseArtistName(name string) (Artist, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" || datesOnlyPattern.MatchString(name) {
+		return Artist{}, false
+	}
+
+	if surname, given, ok := strings.Cut(name, ","); ok && !strings.Contains(given, ",") && isReversedName(surname, given) {
+		surname, given = cleanArtistName(surname), cleanGivenNames(given)
+		return Artist{Name: given + " " + surname, Role: RoleUnknown, SortName: surname + ", " + given}, true
+	}
+
+	name = cleanArtistName(name)
+	if name == "" {
+		return Artist{}, false
+	}
+	return Artist{Name: name, Role: RoleUnknown}, true
+}
+
+func isNameParticle(w string) bool {
+	return slices.Contains(nameParticles, w)
+}
+
+func isCapitalizedWord(w string) bool {
+	r, _ := utf8.DecodeRuneInString(w)
+	return unicode.IsUpper(r)
+}
+
+func isEnsembleWord(w string) bool {
+	return slices.Contains(ensembleWords, strings.ToLower(strings.Trim(w, ".,")))
+}
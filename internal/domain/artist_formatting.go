@@ -1,14 +1,19 @@
 package domain
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 // FormatArtists formats a list of artists according to classical music conventions.
 // Format: "Soloist(s), Orchestra/Ensemble, Conductor"
-// Composers are excluded from the ARTIST tag.
+// Composers are excluded from the ARTIST tag. A soloist directing from the
+// keyboard is listed once, as a soloist; the CONDUCTOR tag credits the direction.
 func FormatArtists(artists []Artist) string {
 	if len(artists) == 0 {
 		return ""
 	}
+	directors := PlayDirectors(artists)
 
 	var soloists []string
 	var ensembles []string
@@ -22,6 +27,9 @@ func FormatArtists(artists []Artist) string {
 		case RoleEnsemble:
 			ensembles = append(ensembles, artist.Name)
 		case RoleConductor:
+			if directors[artist.Name] {
+				continue
+			}
 			conductors = append(conductors, artist.Name)
 		case RoleComposer:
 			// Composers excluded from ARTIST tag
@@ -41,3 +49,135 @@ func FormatArtists(artists []Artist) string {
 
 	return strings.Join(parts, ", ")
 }
+
+// PlayDirectors returns the names credited among artists both as conductor and
+// as a soloist or performer: the pianist or violinist directing from the
+// instrument. They keep both credits; listings name them once.
+func PlayDirectors(artists []Artist) map[string]bool {
+	conducts := make(map[string]bool)
+	plays := make(map[string]bool)
+	for _, a := range artists {
+		switch a.Role {
+		case RoleConductor:
+			conducts[a.Name] = true
+		case RoleSoloist, RolePerformer:
+			plays[a.Name] = true
+		}
+	}
+	directors := make(map[string]bool)
+	for name := range conducts {
+		if plays[name] {
+			directors[name] = true
+		}
+	}
+	return directors
+}
+
+// conventionalOrder ranks roles in the order classical credits list them:
+// soloists, other performers and ensembles, then the conductor, with
+// non-performing roles after.
+var conventionalOrder = map[Role]int{
+	RoleSoloist:   0,
+	RolePerformer: 1,
+	RoleEnsemble:  2,
+	RoleConductor: 3,
+	RoleGuest:     4,
+	RoleComposer:  5,
+	RoleArranger:  6,
+	RoleProducer:  7,
+	RoleDJ:        8,
+	RoleRemixer:   9,
+	RoleUnknown:   10,
+}
+
+// OrderArtists returns artists in conventional credit order, keeping the original
+// order within each role. An artist listed more than once with the same role is
+// kept once, with the first known instrument and character.
+func OrderArtists(artists []Artist) []Artist {
+	type key struct {
+		name string
+		role Role
+	}
+	index := make(map[key]int)
+	ordered := make([]Artist, 0, len(artists))
+	for _, a := range artists {
+		k := key{a.Name, a.Role}
+		if i, ok := index[k]; ok {
+			if ordered[i].Instrument == "" {
+				ordered[i].Instrument = a.Instrument
+			}
+			if ordered[i].Character == "" {
+				ordered[i].Character = a.Character
+			}
+			continue
+		}
+		index[k] = len(ordered)
+		ordered = append(ordered, a)
+	}
+	slices.SortStableFunc(ordered, func(a, b Artist) int {
+		return conventionalOrder[a.Role] - conventionalOrder[b.Role]
+	})
+	return ordered
+}
+
+// PerformerCredits returns display lines for the performers among artists, in
+// conventional order. Soloists playing the same instrument share a line
+// ("Martha Argerich, Nelson Freire (piano)") and conductors are marked as such.
+// A soloist directing from the instrument has one line, "Murray Perahia (piano &
+// direction)". Composers and non-performing roles are omitted.
+func PerformerCredits(artists []Artist) []string {
+	var lines []string
+	instrumentLine := make(map[string]int)
+	var soloists [][]string // names per line, parallel to lines
+	directors := PlayDirectors(artists)
+	directed := make(map[string]bool)
+	for _, a := range OrderArtists(artists) {
+		switch {
+		case directors[a.Name]:
+			// Soloists and performers come before the conductor, so the first
+			// credit is the instrument's
+			if directed[a.Name] {
+				continue
+			}
+			directed[a.Name] = true
+			soloists = append(soloists, nil)
+			if a.Instrument != "" {
+				lines = append(lines, a.Name+" ("+a.Instrument+" & direction)")
+			} else {
+				lines = append(lines, a.Name+" (direction)")
+			}
+		case a.Role == RoleSoloist && a.Instrument != "":
+			if i, ok := instrumentLine[a.Instrument]; ok {
+				soloists[i] = append(soloists[i], a.Name)
+				lines[i] = strings.Join(soloists[i], ", ") + " (" + a.Instrument + ")"
+				continue
+			}
+			instrumentLine[a.Instrument] = len(lines)
+			soloists = append(soloists, []string{a.Name})
+			lines = append(lines, a.Credit())
+		case a.Role == RoleConductor:
+			soloists = append(soloists, nil)
+			lines = append(lines, a.Name+" (conductor)")
+		case a.Role.IsPerformer():
+			soloists = append(soloists, nil)
+			lines = append(lines, a.Name)
+		}
+	}
+	return lines
+}
+
+// CastList returns the opera cast among artists, one "Violetta – Anna Netrebko"
+// line per character and singer, in order of first appearance. Artists singing
+// no character are left out.
+func CastList(artists []Artist) []string {
+	var lines []string
+	for _, a := range artists {
+		if a.Character == "" {
+			continue
+		}
+		if line := a.Character + " – " + a.Name; !slices.Contains(lines, line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
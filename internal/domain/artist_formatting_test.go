@@ -1,6 +1,9 @@
 package domain
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 // TestFormatArtists tests formatting multiple artists according to classical music rules.
 func TestFormatArtists(t *testing.T) {
@@ -67,6 +70,15 @@ func TestFormatArtists(t *testing.T) {
 			},
 			Want: "Glenn Gould",
 		},
+		{
+			Name: "soloist directing from the keyboard",
+			Artists: []Artist{
+				{Name: "Murray Perahia", Role: RoleSoloist},
+				{Name: "Academy of St Martin in the Fields", Role: RoleEnsemble},
+				{Name: "Murray Perahia", Role: RoleConductor},
+			},
+			Want: "Murray Perahia, Academy of St Martin in the Fields",
+		},
 	}
 
 	for _, tt := range tests {
@@ -78,3 +90,92 @@ func TestFormatArtists(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderArtists(t *testing.T) {
+	got := OrderArtists([]Artist{
+		{Name: "Sergei Prokofiev", Role: RoleComposer},
+		{Name: "Claudio Abbado", Role: RoleConductor},
+		{Name: "Berliner Philharmoniker", Role: RoleEnsemble},
+		{Name: "Martha Argerich", Role: RoleSoloist},
+		{Name: "Nelson Freire", Role: RoleSoloist},
+		{Name: "Martha Argerich", Role: RoleSoloist, Instrument: "piano"},
+	})
+	want := []Artist{
+		{Name: "Martha Argerich", Role: RoleSoloist, Instrument: "piano"},
+		{Name: "Nelson Freire", Role: RoleSoloist},
+		{Name: "Berliner Philharmoniker", Role: RoleEnsemble},
+		{Name: "Claudio Abbado", Role: RoleConductor},
+		{Name: "Sergei Prokofiev", Role: RoleComposer},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("OrderArtists() = %v, want %v", got, want)
+	}
+}
+
+func TestPerformerCredits(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Artists []Artist
+		Want    []string
+	}{
+		{
+			Name: "concerto",
+			Artists: []Artist{
+				{Name: "Claudio Abbado", Role: RoleConductor},
+				{Name: "Sergei Prokofiev", Role: RoleComposer},
+				{Name: "Berliner Philharmoniker", Role: RoleEnsemble},
+				{Name: "Martha Argerich", Role: RoleSoloist, Instrument: "piano"},
+			},
+			Want: []string{"Martha Argerich (piano)", "Berliner Philharmoniker", "Claudio Abbado (conductor)"},
+		},
+		{
+			Name: "soloists grouped by instrument",
+			Artists: []Artist{
+				{Name: "Martha Argerich", Role: RoleSoloist, Instrument: "piano"},
+				{Name: "Gidon Kremer", Role: RoleSoloist, Instrument: "violin"},
+				{Name: "Nelson Freire", Role: RoleSoloist, Instrument: "piano"},
+				{Name: "Mischa Maisky", Role: RoleSoloist},
+			},
+			Want: []string{"Martha Argerich, Nelson Freire (piano)", "Gidon Kremer (violin)", "Mischa Maisky"},
+		},
+		{
+			Name: "soloist directing from the keyboard",
+			Artists: []Artist{
+				{Name: "Murray Perahia", Role: RoleConductor},
+				{Name: "Academy of St Martin in the Fields", Role: RoleEnsemble},
+				{Name: "Murray Perahia", Role: RoleSoloist, Instrument: "piano"},
+			},
+			Want: []string{"Murray Perahia (piano & direction)", "Academy of St Martin in the Fields"},
+		},
+		{
+			Name:    "no performers",
+			Artists: []Artist{{Name: "Johann Sebastian Bach", Role: RoleComposer}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := PerformerCredits(tt.Artists); !slices.Equal(got, tt.Want) {
+				t.Errorf("PerformerCredits() = %q, want %q", got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestCastList(t *testing.T) {
+	artists := []Artist{
+		{Name: "Giuseppe Verdi", Role: RoleComposer},
+		{Name: "Anna Netrebko", Role: RoleSoloist, Instrument: "soprano", Character: "Violetta"},
+		{Name: "Rolando Villazón", Role: RoleSoloist, Instrument: "tenor", Character: "Alfredo"},
+		{Name: "Wiener Philharmoniker", Role: RoleEnsemble},
+		{Name: "Anna Netrebko", Role: RoleSoloist, Instrument: "soprano", Character: "Violetta"},
+		{Name: "Carlo Rizzi", Role: RoleConductor},
+	}
+	want := []string{"Violetta – Anna Netrebko", "Alfredo – Rolando Villazón"}
+	if got := CastList(artists); !slices.Equal(got, want) {
+		t.Errorf("CastList() = %q, want %q", got, want)
+	}
+	if got := CastList(artists[:1]); got != nil {
+		t.Errorf("CastList() without singers = %q, want none", got)
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "fmt"
+
+// ArtistPropagationPolicy decides whether album-level performers are copied onto
+// tracks that don't credit them.
+type ArtistPropagationPolicy string
+
+const (
+	// ArtistPropagate adds every album artist to every track (the default)
+	ArtistPropagate ArtistPropagationPolicy = "propagate"
+	// ArtistKeepSparse leaves track credits exactly as tagged, e.g. for box sets
+	// where each disc has its own ensemble
+	ArtistKeepSparse ArtistPropagationPolicy = "keep-sparse"
+	// ArtistPrompt asks, per album artist, whether to add them to the tracks missing them
+	ArtistPrompt ArtistPropagationPolicy = "prompt"
+)
+
+// ParseArtistPropagationPolicy parses "propagate", "keep-sparse" or "prompt"; "" means propagate.
+func ParseArtistPropagationPolicy(s string) (ArtistPropagationPolicy, error) {
+	switch p := ArtistPropagationPolicy(s); p {
+	case "":
+		return ArtistPropagate, nil
+	case ArtistPropagate, ArtistKeepSparse, ArtistPrompt:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %q (want propagate, keep-sparse or prompt)", ErrUnknownArtistPropagationPolicy, s)
+	}
+}
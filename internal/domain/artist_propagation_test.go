@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseArtistPropagationPolicy(t *testing.T) {
+	for input, want := range map[string]ArtistPropagationPolicy{"": ArtistPropagate, "propagate": ArtistPropagate, "keep-sparse": ArtistKeepSparse, "prompt": ArtistPrompt} {
+		got, err := ParseArtistPropagationPolicy(input)
+		if err != nil || got != want {
+			t.Errorf("ParseArtistPropagationPolicy(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseArtistPropagationPolicy("sparse"); !errors.Is(err, ErrUnknownArtistPropagationPolicy) {
+		t.Errorf("ParseArtistPropagationPolicy(sparse) error = %v, want ErrUnknownArtistPropagationPolicy", err)
+	}
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestParseArtistField(t *testing.T) {
 	tests := []struct {
@@ -59,3 +62,200 @@ func TestParseArtistField(t *testing.T) {
 		})
 	}
 }
+
+func TestDeriveSortName(t *testing.T) {
+	tests := []struct {
+		name string
+		role Role
+		want string
+	}{
+		{"Ludwig van Beethoven", RoleComposer, "Beethoven, Ludwig van"},
+		{"Johann Sebastian Bach", RoleComposer, "Bach, Johann Sebastian"},
+		{"Beethoven, Ludwig van", RoleComposer, "Beethoven, Ludwig van"},
+		{"Hildegard", RoleComposer, "Hildegard"},
+		{"Berliner Philharmoniker", RoleEnsemble, "Berliner Philharmoniker"},
+		{"  Glenn Gould ", RoleSoloist, "Gould, Glenn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DeriveSortName(tt.name, tt.role); got != tt.want {
+				t.Errorf("DeriveSortName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArtist_SortKeyAndLastName(t *testing.T) {
+	derived := Artist{Name: "Ludwig van Beethoven", Role: RoleComposer}
+	if got := derived.SortKey(); got != "Beethoven, Ludwig van" {
+		t.Errorf("SortKey() = %q", got)
+	}
+	if got := derived.LastName(); got != "Beethoven" {
+		t.Errorf("LastName() = %q", got)
+	}
+
+	// Explicit sort names win over the last-word heuristic
+	explicit := Artist{Name: "Tomás Luis de Victoria", Role: RoleComposer, SortName: "Victoria, Tomás Luis de"}
+	if got := explicit.SortKey(); got != "Victoria, Tomás Luis de" {
+		t.Errorf("SortKey() = %q", got)
+	}
+	if got := explicit.LastName(); got != "Victoria" {
+		t.Errorf("LastName() = %q", got)
+	}
+
+	compound := Artist{Name: "Ralph Vaughan Williams", Role: RoleComposer, SortName: "Vaughan Williams, Ralph"}
+	if got := compound.LastName(); got != "Vaughan Williams" {
+		t.Errorf("LastName() = %q", got)
+	}
+}
+
+func TestParseCredit(t *testing.T) {
+	tests := []struct {
+		Credit, WantName, WantInstrument string
+	}{
+		{"Martha Argerich (piano)", "Martha Argerich", "piano"},
+		{" Dietrich Fischer-Dieskau (baritone) ", "Dietrich Fischer-Dieskau", "baritone"},
+		{"Glenn Gould", "Glenn Gould", ""},
+		{"Orchestre de la Suisse Romande (OSR) Soloists", "Orchestre de la Suisse Romande (OSR) Soloists", ""},
+		{"(piano)", "(piano)", ""},
+	}
+	for _, tt := range tests {
+		name, instrument := ParseCredit(tt.Credit)
+		if name != tt.WantName || instrument != tt.WantInstrument {
+			t.Errorf("ParseCredit(%q) = %q, %q; want %q, %q", tt.Credit, name, instrument, tt.WantName, tt.WantInstrument)
+		}
+		if tt.WantInstrument != "" {
+			if got := (Artist{Name: name, Instrument: instrument}).Credit(); got != strings.TrimSpace(tt.Credit) {
+				t.Errorf("Credit() = %q, want %q", got, strings.TrimSpace(tt.Credit))
+			}
+		}
+	}
+}
+
+// TestParseArtistField_Corpus checks ParseArtistField against artist and composer
+// tag values as found in real rips.
+func TestParseArtistField_Corpus(t *testing.T) {
+	tests := []struct {
+		Field string
+		Want  []string // Names, with "|Sort" appended when a sort name is derived from the tag
+	}{
+		// Reversed names
+		{"Bach, Johann Sebastian", []string{"Johann Sebastian Bach|Bach, Johann Sebastian"}},
+		{"Beethoven, Ludwig van", []string{"Ludwig van Beethoven|Beethoven, Ludwig van"}},
+		{"Bach, J.S.", []string{"J.S. Bach|Bach, J.S."}},
+		{"Bach, C. P. E.", []string{"C. P. E. Bach|Bach, C. P. E."}},
+		{"Brahms, Johannes", []string{"Johannes Brahms|Brahms, Johannes"}},
+		{"Karajan, Herbert von", []string{"Herbert von Karajan|Karajan, Herbert von"}},
+		{"Falla, Manuel de", []string{"Manuel de Falla|Falla, Manuel de"}},
+		{"Dvořák, Antonín", []string{"Antonín Dvořák|Dvořák, Antonín"}},
+		{"Mozart, Wolfgang Amadeus; Haydn, Joseph", []string{"Wolfgang Amadeus Mozart|Mozart, Wolfgang Amadeus", "Joseph Haydn|Haydn, Joseph"}},
+		{"Bach, Johann Sebastian, Vivaldi, Antonio", []string{"Johann Sebastian Bach|Bach, Johann Sebastian", "Antonio Vivaldi|Vivaldi, Antonio"}},
+
+		// Lists of surnames or full names stay split
+		{"Brahms, Schumann", []string{"Brahms", "Schumann"}},
+		{"Argerich, Kremer, Maisky", []string{"Argerich", "Kremer", "Maisky"}},
+		{"Pollini, Berlin Philharmonic, Abbado", []string{"Pollini", "Berlin Philharmonic", "Abbado"}},
+		{"Anne-Sophie Mutter, Berliner Philharmoniker, Herbert von Karajan", []string{"Anne-Sophie Mutter", "Berliner Philharmoniker", "Herbert von Karajan"}},
+		{"Mahler, Chamber Orchestra of Europe", []string{"Mahler", "Chamber Orchestra of Europe"}},
+		{"Emerson String Quartet; Mstislav Rostropovich", []string{"Emerson String Quartet", "Mstislav Rostropovich"}},
+
+		// Life dates
+		{"J. S. Bach (1685-1750)", []string{"J. S. Bach"}},
+		{"Johann Sebastian Bach (1685–1750)", []string{"Johann Sebastian Bach"}},
+		{"Arvo Pärt (b. 1935)", []string{"Arvo Pärt"}},
+		{"Josquin des Prez (c.1450-1521)", []string{"Josquin des Prez"}},
+		{"Bach, Johann Sebastian (1685-1750)", []string{"Johann Sebastian Bach|Bach, Johann Sebastian"}},
+		{"Bach, Johann Sebastian, 1685-1750", []string{"Johann Sebastian Bach|Bach, Johann Sebastian"}},
+		{"Henry Purcell [1659-1695]; John Blow [1649-1708]", []string{"Henry Purcell", "John Blow"}},
+
+		// Honorifics
+		{"Sir Simon Rattle", []string{"Simon Rattle"}},
+		{"Dame Emma Kirkby; Sir John Eliot Gardiner", []string{"Emma Kirkby", "John Eliot Gardiner"}},
+		{"Sir Georg Solti, Chicago Symphony Orchestra", []string{"Georg Solti", "Chicago Symphony Orchestra"}},
+		{"Rattle, Sir Simon", []string{"Simon Rattle|Rattle, Simon"}},
+
+		// Left alone
+		{"Martha Argerich (piano)", []string{"Martha Argerich (piano)"}},
+		{"Les Arts Florissants", []string{"Les Arts Florissants"}},
+		{"  ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Field, func(t *testing.T) {
+			var got []string
+			for _, a := range ParseArtistField(tt.Field) {
+				if a.Role != RoleUnknown {
+					t.Errorf("%q has role %v, want unknown", a.Name, a.Role)
+				}
+				name := a.Name
+				if a.SortName != "" {
+					name += "|" + a.SortName
+				}
+				got = append(got, name)
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.Want, "; ") {
+				t.Errorf("ParseArtistField(%q) = %q, want %q", tt.Field, got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestParseArtistFields(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Fields []string
+		Want   []string // Names, with "|Sort" appended when a sort name is derived from the tag
+	}{
+		{"repeated fields keep commas", []string{"Academy of St Martin in the Fields, London", "Marriner, Sir Neville"},
+			[]string{"Academy of St Martin in the Fields, London", "Neville Marriner|Marriner, Neville"}},
+		{"single field is split", []string{"Brahms, Schumann"}, []string{"Brahms", "Schumann"}},
+		{"empty fields skipped", []string{"Martha Argerich", " ", "Gidon Kremer"}, []string{"Martha Argerich", "Gidon Kremer"}},
+		{"no fields", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			var got []string
+			for _, a := range ParseArtistFields(tt.Fields) {
+				name := a.Name
+				if a.SortName != "" {
+					name += "|" + a.SortName
+				}
+				got = append(got, name)
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.Want, "; ") {
+				t.Errorf("ParseArtistFields(%q) = %q, want %q", tt.Fields, got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestSplitCharacter(t *testing.T) {
+	tests := []struct {
+		Instrument, WantVoice, WantCharacter string
+	}{
+		{"soprano, Violetta", "soprano", "Violetta"},
+		{"Bass-Baritone, Don Giovanni", "Bass-Baritone", "Don Giovanni"},
+		{"piano, four hands", "piano, four hands", ""},
+		{"tenor", "tenor", ""},
+	}
+	for _, tt := range tests {
+		voice, character := SplitCharacter(tt.Instrument)
+		if voice != tt.WantVoice || character != tt.WantCharacter {
+			t.Errorf("SplitCharacter(%q) = %q, %q; want %q, %q", tt.Instrument, voice, character, tt.WantVoice, tt.WantCharacter)
+		}
+	}
+
+	singer := Artist{Name: "Anna Netrebko", Role: RoleSoloist, Instrument: "soprano", Character: "Violetta"}
+	if got := singer.TagCredit(); got != "Anna Netrebko (soprano, Violetta)" {
+		t.Errorf("TagCredit() = %q", got)
+	}
+	if got := singer.Credit(); got != "Anna Netrebko (soprano)" {
+		t.Errorf("Credit() = %q", got)
+	}
+	singer.Instrument = ""
+	if got := singer.TagCredit(); got != "Anna Netrebko" {
+		t.Errorf("TagCredit() without a voice = %q", got)
+	}
+}
@@ -0,0 +1,13 @@
+package domain
+
+// ArtworkCredit records where a cover image placed in the album directory was
+// fetched from, so reports can credit it.
+// All fields are exported and mutable.
+type ArtworkCredit struct {
+	File    string `json:"file"`    // Relative to the album directory, e.g. "cover.jpg"
+	Side    string `json:"side"`    // "front" or "back"
+	Source  string `json:"source"`  // "Discogs" or "Cover Art Archive"
+	URL     string `json:"url"`     // The image downloaded
+	Page    string `json:"page"`    // Where the source shows the image with its credits
+	License string `json:"license"` // Terms the image is offered under
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ChannelLayout names a channel count the way edition titles do: "Mono", "Stereo",
+// "Quadraphonic", "5.1 Surround", ... Returns "" for an unknown (zero) count.
+func ChannelLayout(channels int) string {
+	switch channels {
+	case 0:
+		return ""
+	case 1:
+		return "Mono"
+	case 2:
+		return "Stereo"
+	case 4:
+		return "Quadraphonic"
+	case 5:
+		return "5.0 Surround"
+	case 6:
+		return "5.1 Surround"
+	case 7:
+		return "6.1 Surround"
+	case 8:
+		return "7.1 Surround"
+	default:
+		return fmt.Sprintf("%d-Channel", channels)
+	}
+}
+
+// ChannelCounts returns the distinct channel counts of the torrent's tracks, in
+// ascending order. Tracks with an unknown count are ignored.
+func (t *Torrent) ChannelCounts() []int {
+	var counts []int
+	for _, track := range t.Tracks() {
+		if track.Channels > 0 && !slices.Contains(counts, track.Channels) {
+			counts = append(counts, track.Channels)
+		}
+	}
+	slices.Sort(counts)
+	return counts
+}
+
+// IsMultichannel reports whether any track has more than two channels.
+func (t *Torrent) IsMultichannel() bool {
+	counts := t.ChannelCounts()
+	return len(counts) > 0 && counts[len(counts)-1] > 2
+}
+
+// ChannelLayout returns the layout of the torrent's largest channel count ("5.1 Surround"),
+// or "" when no track has a known count.
+func (t *Torrent) ChannelLayout() string {
+	counts := t.ChannelCounts()
+	if len(counts) == 0 {
+		return ""
+	}
+	return ChannelLayout(counts[len(counts)-1])
+}
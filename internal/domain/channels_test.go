@@ -0,0 +1,31 @@
+package domain
+
+import "testing"
+
+func TestChannelLayout(t *testing.T) {
+	for channels, want := range map[int]string{0: "", 1: "Mono", 2: "Stereo", 4: "Quadraphonic", 6: "5.1 Surround", 8: "7.1 Surround", 3: "3-Channel"} {
+		if got := ChannelLayout(channels); got != want {
+			t.Errorf("ChannelLayout(%d) = %q, want %q", channels, got, want)
+		}
+	}
+}
+
+func TestTorrent_Channels(t *testing.T) {
+	torrent := &Torrent{Files: []FileLike{
+		&Track{Track: 1, Channels: 6},
+		&Track{Track: 2, Channels: 2},
+		&Track{Track: 3},
+		&File{Path: "cover.jpg"},
+	}}
+	if got := torrent.ChannelCounts(); len(got) != 2 || got[0] != 2 || got[1] != 6 {
+		t.Errorf("ChannelCounts() = %v, want [2 6]", got)
+	}
+	if !torrent.IsMultichannel() || torrent.ChannelLayout() != "5.1 Surround" {
+		t.Errorf("IsMultichannel() = %v, ChannelLayout() = %q", torrent.IsMultichannel(), torrent.ChannelLayout())
+	}
+
+	stereo := &Torrent{Files: []FileLike{&Track{Track: 1, Channels: 2}}}
+	if stereo.IsMultichannel() || stereo.ChannelLayout() != "Stereo" {
+		t.Errorf("stereo: IsMultichannel() = %v, ChannelLayout() = %q", stereo.IsMultichannel(), stereo.ChannelLayout())
+	}
+}
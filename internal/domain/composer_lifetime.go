@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+// Lifetime is a composer's birth and death years. Died is 0 for living composers.
+type Lifetime struct {
+	Born int
+	Died int
+}
+
+// String renders the lifetime as "1685–1750", or "b. 1935" for a living composer.
+func (l Lifetime) String() string {
+	if l.Died == 0 {
+		return fmt.Sprintf("b. %d", l.Born)
+	}
+	return fmt.Sprintf("%d–%d", l.Born, l.Died)
+}
+
+// Contains reports whether year falls within the lifetime.
+func (l Lifetime) Contains(year int) bool {
+	return year >= l.Born && (l.Died == 0 || year <= l.Died)
+}
+
+// composerLifetimes lists frequently recorded composers by full name; spelling
+// variants share an entry. Names are matched by compact key, so diacritics and
+// punctuation don't matter.
+var composerLifetimes = map[Lifetime][]string{
+	{1098, 1179}: {"Hildegard von Bingen"},
+	{1300, 1377}: {"Guillaume de Machaut"},
+	{1397, 1474}: {"Guillaume Du Fay", "Guillaume Dufay"},
+	{1410, 1497}: {"Johannes Ockeghem"},
+	{1450, 1521}: {"Josquin des Prez", "Josquin Desprez"},
+	{1505, 1585}: {"Thomas Tallis"},
+	{1525, 1594}: {"Giovanni Pierluigi da Palestrina"},
+	{1532, 1594}: {"Orlande de Lassus", "Orlando di Lasso"},
+	{1543, 1623}: {"William Byrd"},
+	{1548, 1611}: {"Tomás Luis de Victoria"},
+	{1563, 1626}: {"John Dowland"},
+	{1566, 1613}: {"Carlo Gesualdo"},
+	{1567, 1643}: {"Claudio Monteverdi"},
+	{1585, 1672}: {"Heinrich Schütz"},
+	{1632, 1687}: {"Jean-Baptiste Lully"},
+	{1637, 1707}: {"Dieterich Buxtehude"},
+	{1653, 1713}: {"Arcangelo Corelli"},
+	{1659, 1695}: {"Henry Purcell"},
+	{1668, 1733}: {"François Couperin"},
+	{1671, 1751}: {"Tomaso Albinoni"},
+	{1678, 1741}: {"Antonio Vivaldi"},
+	{1681, 1767}: {"Georg Philipp Telemann"},
+	{1683, 1764}: {"Jean-Philippe Rameau"},
+	{1685, 1750}: {"Johann Sebastian Bach"},
+	{1685, 1759}: {"George Frideric Handel", "Georg Friedrich Händel"},
+	{1685, 1757}: {"Domenico Scarlatti"},
+	{1710, 1736}: {"Giovanni Battista Pergolesi"},
+	{1714, 1788}: {"Carl Philipp Emanuel Bach"},
+	{1714, 1787}: {"Christoph Willibald Gluck"},
+	{1732, 1809}: {"Joseph Haydn", "Franz Joseph Haydn"},
+	{1743, 1805}: {"Luigi Boccherini"},
+	{1750, 1825}: {"Antonio Salieri"},
+	{1756, 1791}: {"Wolfgang Amadeus Mozart"},
+	{1770, 1827}: {"Ludwig van Beethoven"},
+	{1782, 1840}: {"Niccolò Paganini"},
+	{1786, 1826}: {"Carl Maria von Weber"},
+	{1792, 1868}: {"Gioachino Rossini"},
+	{1797, 1828}: {"Franz Schubert"},
+	{1797, 1848}: {"Gaetano Donizetti"},
+	{1801, 1835}: {"Vincenzo Bellini"},
+	{1803, 1869}: {"Hector Berlioz"},
+	{1804, 1857}: {"Mikhail Glinka"},
+	{1809, 1847}: {"Felix Mendelssohn", "Felix Mendelssohn Bartholdy"},
+	{1810, 1849}: {"Frédéric Chopin"},
+	{1810, 1856}: {"Robert Schumann"},
+	{1811, 1886}: {"Franz Liszt"},
+	{1813, 1883}: {"Richard Wagner"},
+	{1813, 1901}: {"Giuseppe Verdi"},
+	{1818, 1893}: {"Charles Gounod"},
+	{1819, 1880}: {"Jacques Offenbach"},
+	{1819, 1896}: {"Clara Schumann"},
+	{1822, 1890}: {"César Franck"},
+	{1824, 1896}: {"Anton Bruckner"},
+	{1825, 1899}: {"Johann Strauss II"},
+	{1833, 1887}: {"Alexander Borodin"},
+	{1833, 1897}: {"Johannes Brahms"},
+	{1835, 1921}: {"Camille Saint-Saëns"},
+	{1838, 1875}: {"Georges Bizet"},
+	{1839, 1881}: {"Modest Mussorgsky"},
+	{1840, 1893}: {"Pyotr Ilyich Tchaikovsky", "Peter Ilyich Tchaikovsky", "Piotr Ilyich Tchaikovsky"},
+	{1841, 1904}: {"Antonín Dvořák"},
+	{1843, 1907}: {"Edvard Grieg"},
+	{1844, 1908}: {"Nikolai Rimsky-Korsakov"},
+	{1845, 1924}: {"Gabriel Fauré"},
+	{1854, 1928}: {"Leoš Janáček"},
+	{1857, 1934}: {"Edward Elgar"},
+	{1858, 1924}: {"Giacomo Puccini"},
+	{1860, 1911}: {"Gustav Mahler"},
+	{1860, 1903}: {"Hugo Wolf"},
+	{1862, 1918}: {"Claude Debussy"},
+	{1864, 1949}: {"Richard Strauss"},
+	{1865, 1931}: {"Carl Nielsen"},
+	{1865, 1957}: {"Jean Sibelius"},
+	{1866, 1925}: {"Erik Satie"},
+	{1872, 1915}: {"Alexander Scriabin"},
+	{1872, 1958}: {"Ralph Vaughan Williams"},
+	{1873, 1943}: {"Sergei Rachmaninoff", "Sergei Rachmaninov"},
+	{1874, 1951}: {"Arnold Schoenberg", "Arnold Schönberg"},
+	{1874, 1954}: {"Charles Ives"},
+	{1874, 1934}: {"Gustav Holst"},
+	{1875, 1937}: {"Maurice Ravel"},
+	{1876, 1946}: {"Manuel de Falla"},
+	{1879, 1936}: {"Ottorino Respighi"},
+	{1881, 1945}: {"Béla Bartók"},
+	{1882, 1971}: {"Igor Stravinsky"},
+	{1882, 1967}: {"Zoltán Kodály"},
+	{1883, 1945}: {"Anton Webern"},
+	{1885, 1935}: {"Alban Berg"},
+	{1891, 1953}: {"Sergei Prokofiev"},
+	{1895, 1963}: {"Paul Hindemith"},
+	{1898, 1937}: {"George Gershwin"},
+	{1899, 1963}: {"Francis Poulenc"},
+	{1900, 1990}: {"Aaron Copland"},
+	{1906, 1975}: {"Dmitri Shostakovich"},
+	{1908, 1992}: {"Olivier Messiaen"},
+	{1910, 1981}: {"Samuel Barber"},
+	{1913, 1976}: {"Benjamin Britten"},
+	{1918, 1990}: {"Leonard Bernstein"},
+	{1923, 2006}: {"György Ligeti"},
+	{1935, 0}:    {"Arvo Pärt"},
+	{1936, 0}:    {"Steve Reich"},
+	{1937, 0}:    {"Philip Glass"},
+}
+
+// composerLifetimeIndex maps the compact key of each composer spelling to its lifetime.
+var composerLifetimeIndex = func() map[string]Lifetime {
+	index := make(map[string]Lifetime)
+	for lifetime, names := range composerLifetimes {
+		for _, name := range names {
+			index[composerKey(name)] = lifetime
+		}
+	}
+	return index
+}()
+
+func composerKey(name string) string {
+	return normalize.Key(name, normalize.FoldDiacritics|normalize.LettersAndDigits)
+}
+
+// ComposerLifetime returns the birth and death years of a composer given by
+// full name, or false when the composer isn't known. Surnames alone are not
+// matched: "Bach" or "Strauss" could be any of several composers.
+func ComposerLifetime(name string) (Lifetime, bool) {
+	lifetime, ok := composerLifetimeIndex[composerKey(name)]
+	return lifetime, ok
+}
+
+// composerSurnameIndex maps the compact key of each spelling's last word to
+// the first spelling of every composer with that surname.
+var composerSurnameIndex = func() map[string][]string {
+	index := make(map[string][]string)
+	for _, names := range composerLifetimes {
+		for _, name := range names {
+			fields := strings.Fields(name)
+			key := composerKey(fields[len(fields)-1])
+			if !slices.Contains(index[key], names[0]) {
+				index[key] = append(index[key], names[0])
+			}
+		}
+	}
+	return index
+}()
+
+// ComposerFullName returns the full name of a composer given by surname alone,
+// as folder names often do ("Beethoven"), or false when no known composer has
+// the surname or several do ("Bach", "Strauss").
+func ComposerFullName(surname string) (string, bool) {
+	names := composerSurnameIndex[composerKey(surname)]
+	if len(names) != 1 {
+		return "", false
+	}
+	return names[0], true
+}
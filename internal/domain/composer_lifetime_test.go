@@ -0,0 +1,56 @@
+package domain
+
+import "testing"
+
+func TestComposerLifetime(t *testing.T) {
+	tests := []struct {
+		name string
+		want Lifetime
+		ok   bool
+	}{
+		{"Johann Sebastian Bach", Lifetime{1685, 1750}, true},
+		{"Georg Friedrich Händel", Lifetime{1685, 1759}, true},
+		{"Antonin Dvorak", Lifetime{1841, 1904}, true},
+		{"pyotr ilyich tchaikovsky", Lifetime{1840, 1893}, true},
+		{"Arvo Pärt", Lifetime{1935, 0}, true},
+		{"Bach", Lifetime{}, false},
+		{"Jane Doe", Lifetime{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ComposerLifetime(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ComposerLifetime(%q) = %v, %v; want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if !(Lifetime{1935, 0}).Contains(2024) || (Lifetime{1756, 1791}).Contains(1792) {
+		t.Error("Contains() misjudges an open or closed lifetime")
+	}
+}
+
+func TestLifetime_String(t *testing.T) {
+	tests := map[Lifetime]string{
+		{1685, 1750}: "1685–1750",
+		{1935, 0}:    "b. 1935",
+	}
+	for lifetime, want := range tests {
+		if got := lifetime.String(); got != want {
+			t.Errorf("%#v.String() = %q, want %q", lifetime, got, want)
+		}
+	}
+}
+
+func TestComposerFullName(t *testing.T) {
+	tests := map[string]string{
+		"Beethoven": "Ludwig van Beethoven",
+		"Händel":    "George Frideric Handel",
+		"dvorak":    "Antonín Dvořák",
+		"Bach":      "",
+		"Doe":       "",
+	}
+	for surname, want := range tests {
+		if got, ok := ComposerFullName(surname); got != want || ok != (want != "") {
+			t.Errorf("ComposerFullName(%q) = %q, %v; want %q", surname, got, ok, want)
+		}
+	}
+}
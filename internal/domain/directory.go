@@ -1,38 +1,112 @@
 package domain
 
 import (
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
-// formatComposersForDirectory formats composer names for directory name.
-// Uses last names if multiple composers to save space.
-func formatComposersForDirectory(composers []string) string {
-	if len(composers) == 0 {
-		return ""
-	}
+// directoryTemplatePlaceholders lists the placeholders supported by DirectoryNameFromTemplate.
+var directoryTemplatePlaceholders = []string{
+	"{composer}", "{composer_last}", "{composer_sort}", "{title}", "{performers}", "{year}", "{format}",
+}
 
-	if len(composers) == 1 {
-		// Single composer - use full name or last name
-		name := composers[0]
-		parts := strings.Fields(name)
-		if len(parts) > 1 {
-			// Use last name for brevity
-			return parts[len(parts)-1]
+var (
+	templatePlaceholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+	emptyBracketsPattern       = regexp.MustCompile(`\(\s*\)|\[\s*\]`)
+)
+
+// ValidateDirectoryTemplate checks that a template only uses supported placeholders.
+func ValidateDirectoryTemplate(template string) error {
+	for _, placeholder := range templatePlaceholderPattern.FindAllString(template, -1) {
+		known := false
+		for _, p := range directoryTemplatePlaceholders {
+			if placeholder == p {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: %s", ErrUnknownTemplate, placeholder)
 		}
-		return name
+	}
+	return nil
+}
+
+// DirectoryNameFromTemplate renders a directory name from a template such as
+// "{composer_sort} - {title} ({performers}) - {year} [{format}]".
+// Empty placeholders are dropped along with their brackets and separators.
+// Falls back to DirectoryName for an empty or invalid template, or if the result exceeds 180 characters.
+func (torrent Torrent) DirectoryNameFromTemplate(template string) string {
+	if template == "" || ValidateDirectoryTemplate(template) != nil {
+		return torrent.DirectoryName()
 	}
 
-	// Multiple composers - use last names separated by commas
-	var lastNames []string
+	composers := torrent.directoryComposers()
+	fullNames := make([]string, 0, len(composers))
+	sortNames := make([]string, 0, len(composers))
 	for _, composer := range composers {
-		parts := strings.Fields(composer)
-		if len(parts) > 0 {
-			lastNames = append(lastNames, parts[len(parts)-1])
-		} else {
-			lastNames = append(lastNames, composer)
+		fullNames = append(fullNames, composer.Name)
+		sortNames = append(sortNames, composer.SortKey())
+	}
+
+	performers := torrent.Performers()
+	if len(performers) == 0 {
+		performers = torrent.PrimaryPerformers()
+	}
+
+	year := ""
+	if torrent.OriginalYear > 0 {
+		year = strconv.Itoa(torrent.OriginalYear)
+	}
+
+	title := torrent.Title
+	if strings.TrimSpace(title) == "" {
+		title = "Untitled Album"
+	}
+
+	replacer := strings.NewReplacer(
+		"{composer}", strings.Join(fullNames, ", "),
+		"{composer_last}", formatComposersForDirectory(composers),
+		// Sort names contain commas, so separate multiple composers with semicolons
+		"{composer_sort}", strings.Join(sortNames, "; "),
+		"{title}", title,
+		"{performers}", formatPerformersForDirectory(performers),
+		"{year}", year,
+		"{format}", "FLAC",
+	)
+	name := replacer.Replace(template)
+
+	// Tidy up around placeholders that rendered empty: drop empty brackets and
+	// " - " segments, and attach a bracket-only segment such as "[FLAC]" to the previous one
+	name = emptyBracketsPattern.ReplaceAllString(name, "")
+	var segments []string
+	for _, segment := range strings.Split(name, " - ") {
+		segment = strings.TrimSpace(segment)
+		switch {
+		case segment == "":
+		case len(segments) > 0 && (segment[0] == '[' || segment[0] == '('):
+			segments[len(segments)-1] += " " + segment
+		default:
+			segments = append(segments, segment)
 		}
 	}
+	name = SanitizeDirectoryName(strings.Join(segments, " - "))
+
+	if name == "" || len(name) > 180 {
+		return torrent.DirectoryName()
+	}
+	return name
+}
+
+// formatComposersForDirectory formats composer names for directory name.
+// Uses last names to save space.
+func formatComposersForDirectory(composers []Artist) string {
+	lastNames := make([]string, 0, len(composers))
+	for _, composer := range composers {
+		lastNames = append(lastNames, composer.LastName())
+	}
 	return strings.Join(lastNames, ", ")
 }
 
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -201,3 +203,59 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestDirectoryNameFromTemplate(t *testing.T) {
+	torrent := Torrent{
+		Title:        "Symphonies 5 & 7",
+		OriginalYear: 1975,
+		AlbumArtist: []Artist{
+			{Name: "Ludwig van Beethoven", Role: RoleComposer},
+			{Name: "Wiener Philharmoniker", Role: RoleEnsemble},
+			{Name: "Carlos Kleiber", Role: RoleConductor},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		torrent  Torrent
+		template string
+		want     string
+	}{
+		{"composer sort", torrent, "{composer_sort} - {title} [{format}]", "Beethoven, Ludwig van - Symphonies 5 & 7 [FLAC]"},
+		{"composer full name", torrent, "{composer} - {title} - {year}", "Ludwig van Beethoven - Symphonies 5 & 7 - 1975"},
+		{"composer last with performers", torrent, "{composer_last} - {title} ({performers})", "Beethoven - Symphonies 5 & 7 (Philharmoniker, Kleiber)"},
+		{"empty placeholders dropped", Torrent{Title: "Anthology"}, "{composer_sort} - {title} ({performers}) - {year} [{format}]", "Anthology [FLAC]"},
+		{"empty template uses default", torrent, "", torrent.DirectoryName()},
+		{"unknown placeholder uses default", torrent, "{label} - {title}", torrent.DirectoryName()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.torrent.DirectoryNameFromTemplate(tt.template); got != tt.want {
+				t.Errorf("DirectoryNameFromTemplate(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirectoryName_UsesSortNameForLastName(t *testing.T) {
+	torrent := Torrent{
+		Title: "Fantasia on a Theme by Thomas Tallis",
+		AlbumArtist: []Artist{
+			{Name: "Ralph Vaughan Williams", Role: RoleComposer, SortName: "Vaughan Williams, Ralph"},
+		},
+	}
+
+	if got := torrent.DirectoryName(); !strings.HasPrefix(got, "Vaughan Williams - ") {
+		t.Errorf("DirectoryName() = %q, want prefix %q", got, "Vaughan Williams - ")
+	}
+}
+
+func TestValidateDirectoryTemplate(t *testing.T) {
+	if err := ValidateDirectoryTemplate("{composer_sort} - {title} ({performers}) - {year} [{format}]"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateDirectoryTemplate("{composer} - {album}"); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("expected ErrUnknownTemplate, got %v", err)
+	}
+}
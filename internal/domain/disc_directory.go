@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultDiscTemplate names disc subdirectories "Disc 1", "Disc 2", ...
+const DefaultDiscTemplate = "Disc {disc}"
+
+// discTemplatePlaceholders lists the placeholders supported by disc subdirectory templates.
+var discTemplatePlaceholders = []string{"{disc}", "{subtitle}"}
+
+// discFolderPattern recognizes disc subdirectory names such as "CD1", "CD 01",
+// "Disc 2" and "Disc 2 - Act II".
+var discFolderPattern = regexp.MustCompile(`(?i)^(cd|disc|disk)( ?)(\d+)(?:\s*-\s*(.+))?$`)
+
+// ValidateDiscTemplate checks that a disc subdirectory template uses only
+// {disc} and {subtitle} and contains {disc}.
+func ValidateDiscTemplate(template string) error {
+	for _, placeholder := range templatePlaceholderPattern.FindAllString(template, -1) {
+		known := false
+		for _, p := range discTemplatePlaceholders {
+			if placeholder == p {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: %s", ErrUnknownTemplate, placeholder)
+		}
+	}
+	if !strings.Contains(template, "{disc}") {
+		return fmt.Errorf("%w: disc template must contain {disc}", ErrUnknownTemplate)
+	}
+	return nil
+}
+
+// DiscDirectoryName renders a disc subdirectory name from a template such as
+// "CD{disc}", "CD {disc}" or "Disc {disc} - {subtitle}". The disc number is
+// zero-padded when discCount is 10 or more so folders sort correctly, and an
+// empty subtitle is dropped along with its separator. An empty or invalid
+// template falls back to DefaultDiscTemplate.
+func DiscDirectoryName(template string, disc, discCount int, subtitle string) string {
+	if template == "" || ValidateDiscTemplate(template) != nil {
+		template = DefaultDiscTemplate
+	}
+
+	number := strconv.Itoa(disc)
+	if discCount >= 10 {
+		number = fmt.Sprintf("%0*d", len(strconv.Itoa(discCount)), disc)
+	}
+
+	name := strings.NewReplacer("{disc}", number, "{subtitle}", strings.TrimSpace(subtitle)).Replace(template)
+	name = emptyBracketsPattern.ReplaceAllString(name, "")
+	var segments []string
+	for _, segment := range strings.Split(name, " - ") {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return SanitizeDirectoryName(strings.Join(segments, " - "))
+}
+
+// DiscDirectoryName renders the subdirectory name for one of the torrent's discs,
+// taking the disc count and the disc's DISCSUBTITLE from its tracks.
+func (t *Torrent) DiscDirectoryName(template string, disc int) string {
+	discCount := 0
+	subtitle := ""
+	for _, track := range t.Tracks() {
+		discCount = max(discCount, track.Disc)
+		if track.Disc == disc && subtitle == "" {
+			subtitle = track.DiscSubtitle
+		}
+	}
+	return DiscDirectoryName(template, disc, discCount, subtitle)
+}
+
+// DiscFolderScheme describes how an existing disc subdirectory is named.
+type DiscFolderScheme struct {
+	Prefix string // "CD", "Disc" or "Disk" as written
+	Space  bool   // whether a space separates prefix and number
+	Width  int    // digits in the disc number, e.g. 2 for "CD01"
+}
+
+// ParseDiscFolder recognizes a disc subdirectory name, returning its naming
+// scheme and disc number. ok is false for names that aren't "CD1"/"Disc 1" style.
+func ParseDiscFolder(name string) (scheme DiscFolderScheme, disc int, ok bool) {
+	m := discFolderPattern.FindStringSubmatch(name)
+	if m == nil {
+		return DiscFolderScheme{}, 0, false
+	}
+	disc, _ = strconv.Atoi(m[3])
+	return DiscFolderScheme{Prefix: m[1], Space: m[2] != "", Width: len(m[3])}, disc, true
+}
+
+// String renders the scheme as an example name, e.g. "CD01" or "Disc 1".
+func (s DiscFolderScheme) String() string {
+	sep := ""
+	if s.Space {
+		sep = " "
+	}
+	return fmt.Sprintf("%s%s%0*d", s.Prefix, sep, s.Width, 1)
+}
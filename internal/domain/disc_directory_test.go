@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDiscDirectoryName(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Template  string
+		Disc      int
+		DiscCount int
+		Subtitle  string
+		Want      string
+	}{
+		{"default", "", 2, 3, "", "Disc 2"},
+		{"CD without space", "CD{disc}", 2, 3, "", "CD2"},
+		{"CD with space", "CD {disc}", 2, 3, "", "CD 2"},
+		{"padded for ten or more discs", "CD{disc}", 2, 12, "", "CD02"},
+		{"padded for a hundred discs", "CD{disc}", 7, 100, "", "CD007"},
+		{"subtitle", "Disc {disc} - {subtitle}", 2, 3, "Act II: Finale", "Disc 2 - Act II Finale"},
+		{"missing subtitle drops separator", "Disc {disc} - {subtitle}", 2, 3, "", "Disc 2"},
+		{"bracketed missing subtitle", "CD{disc} ({subtitle})", 2, 3, " ", "CD2"},
+		{"invalid template falls back", "CD{number}", 2, 3, "", "Disc 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := DiscDirectoryName(tt.Template, tt.Disc, tt.DiscCount, tt.Subtitle); got != tt.Want {
+				t.Errorf("DiscDirectoryName(%q, %d, %d, %q) = %q, want %q", tt.Template, tt.Disc, tt.DiscCount, tt.Subtitle, got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestValidateDiscTemplate(t *testing.T) {
+	if err := ValidateDiscTemplate("Disc {disc} - {subtitle}"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, template := range []string{"CD{number}", "{subtitle}"} {
+		if err := ValidateDiscTemplate(template); !errors.Is(err, ErrUnknownTemplate) {
+			t.Errorf("ValidateDiscTemplate(%q) = %v, want ErrUnknownTemplate", template, err)
+		}
+	}
+}
+
+func TestTorrent_DiscDirectoryName(t *testing.T) {
+	torrent := &Torrent{Files: []FileLike{
+		&Track{Disc: 1, Track: 1},
+		&Track{Disc: 2, Track: 1, DiscSubtitle: "Act II"},
+	}}
+	if got := torrent.DiscDirectoryName("Disc {disc} - {subtitle}", 2); got != "Disc 2 - Act II" {
+		t.Errorf("DiscDirectoryName() = %q", got)
+	}
+	if got := torrent.DiscDirectoryName("Disc {disc} - {subtitle}", 1); got != "Disc 1" {
+		t.Errorf("DiscDirectoryName() = %q", got)
+	}
+}
+
+func TestParseDiscFolder(t *testing.T) {
+	tests := []struct {
+		In     string
+		Want   DiscFolderScheme
+		Disc   int
+		WantOK bool
+	}{
+		{"CD1", DiscFolderScheme{Prefix: "CD", Width: 1}, 1, true},
+		{"CD 01", DiscFolderScheme{Prefix: "CD", Space: true, Width: 2}, 1, true},
+		{"Disc 2 - Act II", DiscFolderScheme{Prefix: "Disc", Space: true, Width: 1}, 2, true},
+		{"Act II", DiscFolderScheme{}, 0, false},
+	}
+	for _, tt := range tests {
+		scheme, disc, ok := ParseDiscFolder(tt.In)
+		if scheme != tt.Want || disc != tt.Disc || ok != tt.WantOK {
+			t.Errorf("ParseDiscFolder(%q) = %+v, %d, %v", tt.In, scheme, disc, ok)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiscMap maps a source's disc numbers to the local discs they describe, for
+// box sets a source orders differently than the rip (the bonus disc first,
+// say). Discs it doesn't name keep their number.
+type DiscMap map[int]int
+
+// ParseDiscMap parses "SOURCE=LOCAL" pairs separated by commas, e.g. "1=2,2=1".
+func ParseDiscMap(s string) (DiscMap, error) {
+	m := make(DiscMap)
+	targets := make(map[int]bool)
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(pair, "=")
+		source, err1 := strconv.Atoi(strings.TrimSpace(from))
+		local, err2 := strconv.Atoi(strings.TrimSpace(to))
+		switch {
+		case !ok || err1 != nil || err2 != nil || source < 1 || local < 1:
+			return nil, fmt.Errorf("%w: %q (want SOURCE=LOCAL disc numbers, e.g. 1=2,2=1)", ErrInvalidDiscMap, pair)
+		case m[source] != 0:
+			return nil, fmt.Errorf("%w: source disc %d is mapped twice", ErrInvalidDiscMap, source)
+		case targets[local]:
+			return nil, fmt.Errorf("%w: local disc %d is mapped to twice", ErrInvalidDiscMap, local)
+		}
+		m[source] = local
+		targets[local] = true
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// String returns the map as ParseDiscMap reads it, in source disc order.
+func (m DiscMap) String() string {
+	sources := make([]int, 0, len(m))
+	for source := range m {
+		sources = append(sources, source)
+	}
+	slices.Sort(sources)
+	pairs := make([]string, len(sources))
+	for i, source := range sources {
+		pairs[i] = fmt.Sprintf("%d=%d", source, m[source])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// IsIdentity reports whether the map leaves every disc where it is.
+func (m DiscMap) IsIdentity() bool {
+	for source, local := range m {
+		if source != local {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply renumbers the discs of t's tracks. Fails, leaving t unchanged, when two
+// discs would end up with the same number.
+func (m DiscMap) Apply(t *Torrent) error {
+	renumbered := make(map[int]int) // Local disc -> the source disc moved there
+	for _, track := range t.Tracks() {
+		local, ok := m[track.Disc]
+		if !ok {
+			local = track.Disc
+		}
+		if source, taken := renumbered[local]; taken && source != track.Disc {
+			return fmt.Errorf("%w: discs %d and %d would both become disc %d", ErrInvalidDiscMap, source, track.Disc, local)
+		}
+		renumbered[local] = track.Disc
+	}
+	for _, track := range t.Tracks() {
+		if local, ok := m[track.Disc]; ok {
+			track.Disc = local
+		}
+	}
+	return nil
+}
+
+// MatchDiscs works out which of source's discs each of t's discs is, by their
+// track counts and durations. Returns nil when the discs already line up, or
+// when some local disc matches none of source's.
+func (t *Torrent) MatchDiscs(source *Torrent) DiscMap {
+	local, other := discProfiles(t), discProfiles(source)
+	if len(local) < 2 || len(local) != len(other) {
+		return nil
+	}
+
+	type pair struct {
+		source, local int
+		cost          time.Duration
+	}
+	var pairs []pair
+	aligned := true
+	for l, lp := range local {
+		for s, sp := range other {
+			if cost, ok := profileCost(lp, sp); ok {
+				pairs = append(pairs, pair{s, l, cost})
+			}
+		}
+		if _, ok := profileCost(lp, other[l]); !ok {
+			aligned = false
+		}
+	}
+	if aligned {
+		return nil
+	}
+
+	// Closest pairs first, a disc staying where it is winning ties
+	slices.SortFunc(pairs, func(a, b pair) int {
+		if a.cost != b.cost {
+			return cmp.Compare(a.cost, b.cost)
+		}
+		if (a.source == a.local) != (b.source == b.local) {
+			if a.source == a.local {
+				return -1
+			}
+			return 1
+		}
+		return a.local - b.local
+	})
+	m := make(DiscMap)
+	matched := make(map[int]bool)
+	for _, p := range pairs {
+		if _, ok := m[p.source]; !ok && !matched[p.local] {
+			m[p.source] = p.local
+			matched[p.local] = true
+		}
+	}
+	if len(m) != len(local) || m.IsIdentity() {
+		return nil
+	}
+	return m
+}
+
+// discProfiles returns the durations of each disc's tracks in track order.
+func discProfiles(t *Torrent) map[int][]time.Duration {
+	tracks := slices.Clone(t.Tracks())
+	slices.SortStableFunc(tracks, func(a, b *Track) int { return a.Track - b.Track })
+	profiles := make(map[int][]time.Duration)
+	for _, track := range tracks {
+		profiles[track.Disc] = append(profiles[track.Disc], track.Duration)
+	}
+	return profiles
+}
+
+// profileCost compares a local disc's durations with a source disc's. They
+// match when they have as many tracks, durations to compare, and fewer than one
+// in four durations beyond DurationTolerance; the cost is the total difference.
+func profileCost(local, source []time.Duration) (time.Duration, bool) {
+	if len(local) != len(source) {
+		return 0, false
+	}
+	var cost time.Duration
+	compared, mismatches := 0, 0
+	for i, d := range local {
+		if d <= 0 || source[i] <= 0 {
+			continue
+		}
+		compared++
+		diff := (d - source[i]).Abs()
+		if diff > DurationTolerance(d) {
+			mismatches++
+		}
+		cost += diff
+	}
+	return cost, compared > 0 && mismatches*4 < compared
+}
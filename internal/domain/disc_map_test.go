@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDiscMap(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"1=2,2=1", "1=2,2=1", false},
+		{" 3=1 , 1=3 ", "1=3,3=1", false},
+		{"", "", false},
+		{"1=2,1=3", "", true},
+		{"1=2,3=2", "", true},
+		{"1:2", "", true},
+		{"0=1", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDiscMap(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidDiscMap) {
+				t.Errorf("ParseDiscMap(%q) error = %v, want ErrInvalidDiscMap", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("ParseDiscMap(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+// discsOf builds a torrent whose discs have tracks of the given lengths in seconds.
+func discsOf(discs ...[]int) *Torrent {
+	t := &Torrent{}
+	for d, seconds := range discs {
+		for i, s := range seconds {
+			t.Files = append(t.Files, &Track{Disc: d + 1, Track: i + 1, Duration: time.Duration(s) * time.Second})
+		}
+	}
+	return t
+}
+
+func TestDiscMap_Apply(t *testing.T) {
+	torrent := discsOf([]int{60}, []int{70}, []int{80})
+	if err := (DiscMap{1: 3, 3: 1}).Apply(torrent); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	var got []int
+	for _, track := range torrent.Tracks() {
+		got = append(got, track.Disc)
+	}
+	if got[0] != 3 || got[1] != 2 || got[2] != 1 {
+		t.Errorf("discs = %v, want [3 2 1]", got)
+	}
+
+	// Moving disc 1 onto disc 2, which stays, is refused
+	torrent = discsOf([]int{60}, []int{70})
+	if err := (DiscMap{1: 2}).Apply(torrent); !errors.Is(err, ErrInvalidDiscMap) {
+		t.Errorf("Apply() error = %v, want ErrInvalidDiscMap", err)
+	}
+	if torrent.Tracks()[0].Disc != 1 {
+		t.Error("a failed Apply() should leave the torrent unchanged")
+	}
+}
+
+func TestTorrent_MatchDiscs(t *testing.T) {
+	main1, main2, bonus := []int{600, 420, 515}, []int{1200, 300}, []int{200, 210, 190, 230}
+	local := discsOf(main1, main2, bonus)
+
+	tests := []struct {
+		name   string
+		source *Torrent
+		want   string
+	}{
+		{"already aligned", discsOf(main1, main2, bonus), ""},
+		{"bonus disc first", discsOf(bonus, main1, main2), "1=3,2=1,3=2"},
+		{"durations rounded", discsOf([]int{201, 209, 190, 231}, []int{601, 420, 514}, []int{1199, 300}), "1=3,2=1,3=2"},
+		{"a disc matching nothing", discsOf(bonus, main1, []int{100, 100}), ""},
+		{"fewer discs", discsOf(main1, main2), ""},
+	}
+	for _, tt := range tests {
+		if got := local.MatchDiscs(tt.source).String(); got != tt.want {
+			t.Errorf("%s: MatchDiscs() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
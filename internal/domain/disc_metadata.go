@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+// AssembleDiscs builds the metadata of a whole set from the set's metadata and
+// disc metadata files (Disc set) that each cover one disc, so large sets can be
+// corrected a disc at a time. A disc file's tracks replace the set's tracks on
+// that disc; the disc's Title becomes their disc subtitle, and its Edition, when
+// it differs from the set's, their own. Track-level values are kept. Neither
+// set nor discs is modified.
+func AssembleDiscs(set *Torrent, discs []*Torrent) (*Torrent, error) {
+	if set.Disc != 0 {
+		return nil, fmt.Errorf("%w: no metadata for the whole set, only for disc %d", ErrDiscMetadata, set.Disc)
+	}
+	replaced := make(map[int]bool)
+	for _, disc := range discs {
+		switch {
+		case disc.Disc < 1:
+			return nil, fmt.Errorf("%w: %q covers the whole set, not a disc", ErrDiscMetadata, disc.Title)
+		case replaced[disc.Disc]:
+			return nil, fmt.Errorf("%w: disc %d is covered twice", ErrDiscMetadata, disc.Disc)
+		case len(disc.Tracks()) == 0:
+			return nil, fmt.Errorf("disc %d: %w", disc.Disc, ErrNoTracks)
+		}
+		for _, track := range disc.Tracks() {
+			if track.Disc != 0 && track.Disc != disc.Disc {
+				return nil, fmt.Errorf("%w: disc %d metadata has %s on disc %d", ErrDiscMetadata, disc.Disc, track.File.Path, track.Disc)
+			}
+		}
+		replaced[disc.Disc] = true
+	}
+
+	assembled := *set
+	assembled.Files = nil
+	assembled.Sources = slices.Clone(set.Sources)
+	assembled.Artwork = slices.Clone(set.Artwork)
+	var tracks []*Track
+	var others []FileLike
+	paths := make(map[string]bool)
+	for _, f := range set.Files {
+		if track, ok := f.(*Track); ok {
+			if !replaced[track.Disc] {
+				tracks = append(tracks, track)
+			}
+			continue
+		}
+		others = append(others, f)
+		paths[f.GetPath()] = true
+	}
+
+	for _, disc := range discs {
+		for _, f := range disc.Files {
+			track, ok := f.(*Track)
+			if !ok {
+				// Files such as a disc's own scans join the set's once
+				if !paths[f.GetPath()] {
+					others = append(others, f)
+					paths[f.GetPath()] = true
+				}
+				continue
+			}
+			t := *track
+			t.Disc = disc.Disc
+			if t.DiscSubtitle == "" {
+				t.DiscSubtitle = disc.Title
+			}
+			if t.Edition == nil && disc.Edition != nil && (set.Edition == nil || *disc.Edition != *set.Edition) {
+				t.Edition = disc.Edition
+			}
+			tracks = append(tracks, &t)
+		}
+		for _, source := range disc.Sources {
+			if !slices.Contains(assembled.Sources, source) {
+				assembled.Sources = append(assembled.Sources, source)
+			}
+		}
+		for _, credit := range disc.Artwork {
+			if !slices.Contains(assembled.Artwork, credit) {
+				assembled.Artwork = append(assembled.Artwork, credit)
+			}
+		}
+	}
+
+	slices.SortStableFunc(tracks, func(a, b *Track) int {
+		if a.Disc != b.Disc {
+			return a.Disc - b.Disc
+		}
+		return a.Track - b.Track
+	})
+	for _, track := range tracks {
+		assembled.Files = append(assembled.Files, track)
+	}
+	assembled.Files = append(assembled.Files, others...)
+	return &assembled, nil
+}
+
+// DiscMetadata returns a disc metadata file for one disc of the set, to be
+// corrected on its own and assembled back with AssembleDiscs: the disc's
+// tracks, titled with their disc subtitle, and the disc's edition.
+func (t *Torrent) DiscMetadata(disc int) (*Torrent, error) {
+	part := &Torrent{RootPath: t.RootPath, Disc: disc, Edition: t.Edition}
+	for _, track := range t.Tracks() {
+		if track.Disc != disc {
+			continue
+		}
+		copied := *track
+		if len(part.Files) == 0 {
+			part.Title = copied.DiscSubtitle
+			if copied.Edition != nil {
+				part.Edition = copied.Edition
+			}
+		}
+		if copied.DiscSubtitle == part.Title {
+			copied.DiscSubtitle = ""
+		}
+		if copied.Edition != nil && part.Edition != nil && *copied.Edition == *part.Edition {
+			copied.Edition = nil
+		}
+		part.Files = append(part.Files, &copied)
+	}
+	if len(part.Files) == 0 {
+		return nil, fmt.Errorf("disc %d: %w", disc, ErrNoTracks)
+	}
+	return part, nil
+}
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func discSet() *Torrent {
+	return &Torrent{
+		Title:   "The Complete Symphonies",
+		Edition: &Edition{Label: "DG", CatalogNumber: "BOX1", Year: 2020},
+		Sources: []string{"https://www.discogs.com/release/1"},
+		Files: []FileLike{
+			&Track{File: File{Path: "CD1/01.flac"}, Disc: 1, Track: 1, Title: "Symphony No. 1: I", DiscSubtitle: "Symphonies 1 & 2"},
+			&Track{File: File{Path: "CD2/01.flac"}, Disc: 2, Track: 1, Title: "Symphony No. 3: I", DiscSubtitle: "Eroica"},
+			&Track{File: File{Path: "CD2/02.flac"}, Disc: 2, Track: 2, Title: "Symphony No. 3: II", DiscSubtitle: "Eroica"},
+			&File{Path: "cover.jpg"},
+		},
+	}
+}
+
+func TestAssembleDiscs(t *testing.T) {
+	set := discSet()
+	disc := &Torrent{
+		Disc:    2,
+		Title:   "Symphony No. 3 \"Eroica\"",
+		Edition: &Edition{Label: "DG", CatalogNumber: "4474442", Year: 1996},
+		Sources: []string{"https://www.discogs.com/release/2"},
+		Files: []FileLike{
+			&Track{File: File{Path: "CD2/02.flac"}, Track: 2, Title: "Symphony No. 3: II. Marcia funebre"},
+			&Track{File: File{Path: "CD2/01.flac"}, Track: 1, Title: "Symphony No. 3: I. Allegro con brio"},
+			&File{Path: "CD2/booklet.pdf"},
+		},
+	}
+
+	got, err := AssembleDiscs(set, []*Torrent{disc})
+	if err != nil {
+		t.Fatalf("AssembleDiscs() error = %v", err)
+	}
+	tracks := got.Tracks()
+	if len(tracks) != 3 || len(got.Files) != 5 {
+		t.Fatalf("assembled %d tracks, %d files; want 3, 5", len(tracks), len(got.Files))
+	}
+	if tracks[0] != set.Files[0] {
+		t.Error("disc 1 should keep the set's track")
+	}
+	for i, want := range []string{"Symphony No. 3: I. Allegro con brio", "Symphony No. 3: II. Marcia funebre"} {
+		track := tracks[i+1]
+		if track.Title != want || track.Disc != 2 || track.DiscSubtitle != disc.Title || track.Edition != disc.Edition {
+			t.Errorf("track %d = %+v", i+2, track)
+		}
+	}
+	if got.Title != set.Title || got.Edition != set.Edition || len(got.Sources) != 2 {
+		t.Errorf("album = %q %+v %v", got.Title, got.Edition, got.Sources)
+	}
+	if len(set.Tracks()) != 3 || set.Tracks()[1].Title != "Symphony No. 3: I" || disc.Tracks()[0].Disc != 0 {
+		t.Error("AssembleDiscs() modified its arguments")
+	}
+
+	// A disc of the set's own edition adds no override
+	same := &Torrent{Disc: 1, Title: "Symphonies 1 & 2", Edition: &Edition{Label: "DG", CatalogNumber: "BOX1", Year: 2020},
+		Files: []FileLike{&Track{File: File{Path: "CD1/01.flac"}, Disc: 1, Track: 1, Title: "Symphony No. 1: I. Adagio molto"}}}
+	got, err = AssembleDiscs(set, []*Torrent{same})
+	if err != nil {
+		t.Fatalf("AssembleDiscs() error = %v", err)
+	}
+	if track := got.Tracks()[0]; track.Edition != nil || track.Title != "Symphony No. 1: I. Adagio molto" {
+		t.Errorf("track 1 = %+v", track)
+	}
+}
+
+func TestAssembleDiscs_Errors(t *testing.T) {
+	track := func(disc int) []FileLike {
+		return []FileLike{&Track{File: File{Path: "x.flac"}, Disc: disc, Track: 1, Title: "x"}}
+	}
+	tests := []struct {
+		name  string
+		set   *Torrent
+		discs []*Torrent
+		want  error
+	}{
+		{"set is a disc", &Torrent{Disc: 1}, nil, ErrDiscMetadata},
+		{"disc without a number", discSet(), []*Torrent{{Files: track(0)}}, ErrDiscMetadata},
+		{"disc covered twice", discSet(), []*Torrent{{Disc: 2, Files: track(2)}, {Disc: 2, Files: track(2)}}, ErrDiscMetadata},
+		{"track on another disc", discSet(), []*Torrent{{Disc: 2, Files: track(3)}}, ErrDiscMetadata},
+		{"disc without tracks", discSet(), []*Torrent{{Disc: 2}}, ErrNoTracks},
+	}
+	for _, tt := range tests {
+		if _, err := AssembleDiscs(tt.set, tt.discs); !errors.Is(err, tt.want) {
+			t.Errorf("%s: AssembleDiscs() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestTorrent_DiscMetadata(t *testing.T) {
+	set := discSet()
+	part, err := set.DiscMetadata(2)
+	if err != nil {
+		t.Fatalf("DiscMetadata() error = %v", err)
+	}
+	if part.Disc != 2 || part.Title != "Eroica" || part.Edition != set.Edition || len(part.Files) != 2 {
+		t.Fatalf("DiscMetadata(2) = %+v", part)
+	}
+	if part.Tracks()[0].DiscSubtitle != "" {
+		t.Error("the disc's title should stand in for its tracks' subtitle")
+	}
+
+	// Assembling an unchanged disc file gives back the set's tracks
+	got, err := AssembleDiscs(set, []*Torrent{part})
+	if err != nil {
+		t.Fatalf("AssembleDiscs() error = %v", err)
+	}
+	for i, track := range got.Tracks() {
+		want := set.Tracks()[i]
+		if track.Title != want.Title || track.Disc != want.Disc || track.DiscSubtitle != want.DiscSubtitle || track.Edition != nil {
+			t.Errorf("track %d = %+v, want %+v", i+1, track, want)
+		}
+	}
+
+	if _, err := set.DiscMetadata(5); !errors.Is(err, ErrNoTracks) {
+		t.Errorf("DiscMetadata(5) error = %v, want ErrNoTracks", err)
+	}
+}
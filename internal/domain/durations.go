@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses a tracklist playing time, "m:ss" or "h:mm:ss", as
+// FormatDuration writes it. Returns 0 for "" or anything else.
+func ParseDuration(s string) time.Duration {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0
+	}
+	var seconds int
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// DurationTolerance returns how far a source's playing time for a track of
+// duration d may be from the file's before they are different recordings:
+// 3 seconds, for rounding and pregaps, or 2% of long tracks.
+func DurationTolerance(d time.Duration) time.Duration {
+	return max(3*time.Second, d/50)
+}
+
+// DurationMismatch is a track whose file plays for longer or shorter than a
+// source says, beyond DurationTolerance.
+type DurationMismatch struct {
+	Disc, Track   int
+	Local, Source time.Duration
+}
+
+// String describes the mismatch, e.g. "track 3: 7:12, source 9:45".
+func (m DurationMismatch) String() string {
+	track := fmt.Sprintf("track %d", m.Track)
+	if m.Disc > 0 {
+		track = fmt.Sprintf("disc %d track %d", m.Disc, m.Track)
+	}
+	return fmt.Sprintf("%s: %s, source %s", track, FormatDuration(m.Local), FormatDuration(m.Source))
+}
+
+// DurationCheck is the result of comparing a torrent's track durations with a source's.
+type DurationCheck struct {
+	Compared   int // Tracks whose duration both know
+	Mismatches []DurationMismatch
+}
+
+// Matches reports whether durations were compared and all of them agree.
+func (c DurationCheck) Matches() bool {
+	return c.Compared > 0 && len(c.Mismatches) == 0
+}
+
+// DifferentRecording reports whether enough durations disagree (at least one
+// in four compared) that the source is likely another recording or edition,
+// rather than one mistyped duration.
+func (c DurationCheck) DifferentRecording() bool {
+	return c.Compared > 0 && len(c.Mismatches)*4 >= c.Compared
+}
+
+// String summarizes the check, e.g. "12 of 12 durations match" or "4 of 12
+// durations differ"; "" when nothing was compared.
+func (c DurationCheck) String() string {
+	switch {
+	case c.Compared == 0:
+		return ""
+	case len(c.Mismatches) == 0:
+		return fmt.Sprintf("%d of %d durations match", c.Compared, c.Compared)
+	default:
+		return fmt.Sprintf("%d of %d durations differ", len(c.Mismatches), c.Compared)
+	}
+}
+
+// CompareDurations compares the durations of t's tracks with those source gives
+// for the same disc and track numbers. Tracks either side has no duration for
+// are skipped.
+func (t *Torrent) CompareDurations(source *Torrent) DurationCheck {
+	var check DurationCheck
+	if t == nil || source == nil {
+		return check
+	}
+	sourceTracks := make(map[[2]int]*Track)
+	for _, track := range source.Tracks() {
+		sourceTracks[[2]int{track.Disc, track.Track}] = track
+	}
+	for _, track := range t.Tracks() {
+		other := sourceTracks[[2]int{track.Disc, track.Track}]
+		if track.Duration <= 0 || other == nil || other.Duration <= 0 {
+			continue
+		}
+		check.Compared++
+		diff := track.Duration - other.Duration
+		if diff.Abs() > DurationTolerance(track.Duration) {
+			check.Mismatches = append(check.Mismatches, DurationMismatch{
+				Disc: track.Disc, Track: track.Track, Local: track.Duration, Source: other.Duration,
+			})
+		}
+	}
+	return check
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"3:45":    3*time.Minute + 45*time.Second,
+		"1:02:03": time.Hour + 2*time.Minute + 3*time.Second,
+		" 0:59 ":  59 * time.Second,
+		"":        0,
+		"3'45":    0,
+		"-1:00":   0,
+	}
+	for s, want := range tests {
+		if got := ParseDuration(s); got != want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestTorrent_CompareDurations(t *testing.T) {
+	torrent := func(durations ...time.Duration) *Torrent {
+		tr := &Torrent{}
+		for i, d := range durations {
+			tr.Files = append(tr.Files, &Track{Disc: 1, Track: i + 1, Duration: d})
+		}
+		return tr
+	}
+	local := torrent(3*time.Minute, 10*time.Minute, 2*time.Minute, 4*time.Minute)
+
+	tests := []struct {
+		Name       string
+		Source     *Torrent
+		Compared   int
+		Mismatches int
+		Different  bool
+	}{
+		{"within tolerance", torrent(3*time.Minute+2*time.Second, 10*time.Minute+11*time.Second, 2*time.Minute, 4*time.Minute), 4, 0, false},
+		{"one in four differs", torrent(3*time.Minute, 10*time.Minute, 2*time.Minute, 5*time.Minute), 4, 1, true},
+		{"unknown durations skipped", torrent(3*time.Minute, 0, 0, 0), 1, 0, false},
+		{"another recording", torrent(4*time.Minute, 12*time.Minute, 3*time.Minute, 4*time.Minute), 4, 3, true},
+		{"no source", nil, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			check := local.CompareDurations(tt.Source)
+			if check.Compared != tt.Compared || len(check.Mismatches) != tt.Mismatches || check.DifferentRecording() != tt.Different {
+				t.Errorf("CompareDurations() = %+v (different %v), want %d compared, %d mismatches, different %v",
+					check, check.DifferentRecording(), tt.Compared, tt.Mismatches, tt.Different)
+			}
+		})
+	}
+
+	got := local.CompareDurations(torrent(3*time.Minute, 10*time.Minute, 2*time.Minute, 5*time.Minute))
+	if got.String() != "1 of 4 durations differ" || got.Mismatches[0].String() != "disc 1 track 4: 4:00, source 5:00" {
+		t.Errorf("CompareDurations() = %q, %q", got, got.Mismatches[0])
+	}
+}
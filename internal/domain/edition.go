@@ -5,5 +5,19 @@ package domain
 type Edition struct {
 	Label         string `json:"label"`
 	CatalogNumber string `json:"catalog_number,omitempty"`
+	Barcode       string `json:"barcode,omitempty"` // UPC/EAN
 	Year          int    `json:"year"`
+
+	// Identifiers of the exact release, written as tags so it can be found again without searching
+	DiscogsReleaseID   int    `json:"discogs_release_id,omitempty"`
+	MusicBrainzAlbumID string `json:"musicbrainz_album_id,omitempty"` // MusicBrainz release MBID
+}
+
+// TrackEdition returns the edition of the disc the track is on, falling back to
+// the album's.
+func (t *Torrent) TrackEdition(track *Track) *Edition {
+	if track.Edition != nil {
+		return track.Edition
+	}
+	return t.Edition
 }
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+// EnsembleScale is the size of a credited ensemble as its name tells it, which
+// decides whether its recordings need a conductor.
+type EnsembleScale int
+
+const (
+	// EnsembleUnknown is any name that tells neither, e.g. "Ensemble Modern" or a choir
+	EnsembleUnknown EnsembleScale = iota
+	// EnsembleChamber is a string quartet, piano trio, consort or the like, which plays without a conductor
+	EnsembleChamber
+	// EnsembleOrchestral is an orchestra, which is conducted or directed from an instrument
+	EnsembleOrchestral
+)
+
+// chamberWords are the words naming a chamber ensemble ("Emerson String Quartet").
+var chamberWords = []string{
+	"duo", "trio", "quartet", "quartett", "quartetto", "quatuor", "quintet", "quintett", "quintetto",
+	"sextet", "sextett", "septet", "octet", "oktett", "nonet", "consort",
+}
+
+// orchestralStems begin or appear in the names of orchestras, including
+// compounds such as "Gewandhausorchester".
+var orchestralStems = []string{
+	"orchest", "orquest", "orkest", "philharmoni", "symphoniker", "sinfonia", "sinfonietta", "staatskapelle",
+}
+
+// choirWords name choirs, whose "Symphony Chorus" is not an orchestra.
+var choirWords = []string{"choir", "chor", "chorus", "choeur", "coro", "kammerchor", "singers", "voices"}
+
+// EnsembleScale returns the scale of the ensemble a is, by its name.
+func (a Artist) EnsembleScale() EnsembleScale {
+	name := normalize.Name(a.Name)
+	words := strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) })
+	switch {
+	case slices.ContainsFunc(words, func(w string) bool { return slices.Contains(chamberWords, w) }):
+		return EnsembleChamber
+	case slices.ContainsFunc(words, func(w string) bool { return slices.Contains(choirWords, w) }):
+		return EnsembleUnknown
+	case slices.ContainsFunc(orchestralStems, func(stem string) bool { return strings.Contains(name, stem) }),
+		slices.Contains(words, "symphony"):
+		return EnsembleOrchestral
+	}
+	return EnsembleUnknown
+}
+
+// orchestralWorkPattern matches titles of works written for orchestra:
+// symphonies, concertos, overtures and symphonic poems.
+var orchestralWorkPattern = regexp.MustCompile(`(?i)\b(?:symphon(?:y|ie|ies)|sinfonie|concerto|konzert|overture|ouverture|tone poem|symphonic poem)\b`)
+
+// IsOrchestralWork reports whether a track title names a work written for
+// orchestra ("Symphony No. 5", "Piano Concerto in A minor", "Overture").
+func IsOrchestralWork(title string) bool {
+	return orchestralWorkPattern.MatchString(title)
+}
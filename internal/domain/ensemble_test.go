@@ -0,0 +1,50 @@
+package domain
+
+import "testing"
+
+func TestArtist_EnsembleScale(t *testing.T) {
+	tests := []struct {
+		Name string
+		Want EnsembleScale
+	}{
+		{"Emerson String Quartet", EnsembleChamber},
+		{"Beaux Arts Trio", EnsembleChamber},
+		{"Quatuor Ébène", EnsembleChamber},
+		{"Trío Arbós", EnsembleChamber},
+		{"Tri\u0301o Arbo\u0301s", EnsembleChamber}, // Decomposed (NFD)
+		{"Fretwork Consort", EnsembleChamber},
+		{"Berliner Philharmoniker", EnsembleOrchestral},
+		{"London Symphony Orchestra", EnsembleOrchestral},
+		{"Gewandhausorchester Leipzig", EnsembleOrchestral},
+		{"Chamber Orchestra of Europe", EnsembleOrchestral},
+		{"Staatskapelle Dresden", EnsembleOrchestral},
+		{"Boston Symphony", EnsembleOrchestral},
+		{"London Symphony Chorus", EnsembleUnknown},
+		{"Ensemble Intercontemporain", EnsembleUnknown},
+		{"Triopolis", EnsembleUnknown},
+	}
+	for _, tt := range tests {
+		if got := (Artist{Name: tt.Name, Role: RoleEnsemble}).EnsembleScale(); got != tt.Want {
+			t.Errorf("EnsembleScale(%q) = %v, want %v", tt.Name, got, tt.Want)
+		}
+	}
+}
+
+func TestIsOrchestralWork(t *testing.T) {
+	tests := []struct {
+		Title string
+		Want  bool
+	}{
+		{"Symphony No. 5 in C minor, Op. 67: I. Allegro con brio", true},
+		{"Piano Concerto No. 2: II. Adagio", true},
+		{"Die Zauberflöte, K. 620: Overture", true},
+		{"Sinfonie Nr. 9", true},
+		{"String Quartet No. 14: I. Adagio", false},
+		{"Goldberg Variations: Aria", false},
+	}
+	for _, tt := range tests {
+		if got := IsOrchestralWork(tt.Title); got != tt.Want {
+			t.Errorf("IsOrchestralWork(%q) = %v, want %v", tt.Title, got, tt.Want)
+		}
+	}
+}
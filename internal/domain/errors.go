@@ -1,9 +1,96 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Standard domain errors
 var (
-	ErrEmptyArtistName = errors.New("artist name cannot be empty")
-	ErrInvalidRole     = errors.New("invalid artist role")
+	ErrEmptyArtistName                = errors.New("artist name cannot be empty")
+	ErrInvalidRole                    = errors.New("invalid artist role")
+	ErrUnknownTemplate                = errors.New("unknown directory template placeholder")
+	ErrUnknownTitleVariant            = errors.New("unknown title variant")
+	ErrUnknownHiddenTrackPolicy       = errors.New("unknown hidden track policy")
+	ErrUnknownArtistPropagationPolicy = errors.New("unknown artist propagation policy")
+	ErrUnknownWorkGroupingPolicy      = errors.New("unknown work grouping policy")
+	ErrUnknownValidationProfile       = errors.New("unknown validation profile")
+	ErrUnknownFilenamePolicy          = errors.New("unknown filename policy")
+	ErrUnknownTagRetention            = errors.New("unknown tag retention policy")
+	ErrUnknownOverride                = errors.New("unknown override")
+	ErrUnknownTitleStyle              = errors.New("unknown title style")
+	ErrNoTracks                       = errors.New("no tracks found")
+	ErrNoComposer                     = errors.New("no composer found in tags")
+	ErrMetadataMismatch               = errors.New("metadata does not match the files")
+	ErrMergedAlbums                   = errors.New("directory holds more than one album")
+	ErrDiscMetadata                   = errors.New("disc metadata does not fit the set")
+	ErrInvalidDiscMap                 = errors.New("invalid disc map")
+	ErrValidation                     = errors.New("validation failed")
+	ErrAPI                            = errors.New("API error")
+	ErrAborted                        = errors.New("aborted")
 )
+
+// Classify marks err as being of kind (ErrValidation, ErrAPI, ...), so that
+// errors.Is(err, kind) holds, without changing its message. Commands choose
+// their exit code by kind.
+func Classify(err, kind error) error {
+	if err == nil {
+		return nil
+	}
+	return classified{err, kind}
+}
+
+type classified struct {
+	error
+	kind error
+}
+
+func (e classified) Unwrap() []error { return []error{e.error, e.kind} }
+
+// ErrRoleUnknown reports an artist whose role no metadata source could determine.
+type ErrRoleUnknown struct {
+	Artist string
+	Track  string // Title of the track crediting the artist; empty for album artists
+}
+
+func (e *ErrRoleUnknown) Error() string {
+	if e.Track == "" {
+		return fmt.Sprintf("cannot determine role for album artist %q", e.Artist)
+	}
+	return fmt.Sprintf("cannot determine role for artist %q on track %q", e.Artist, e.Track)
+}
+
+// ErrMediaMismatch reports local files from a different media than the torrent
+// they would trump, e.g. a CD rip against a WEB torrent: a new edition, not a trump.
+type ErrMediaMismatch struct {
+	Local     string // Media of the local files
+	Evidence  string // What says so ("rip log present")
+	Trumped   string // Media of the torrent being trumped
+	TorrentID int
+}
+
+func (e *ErrMediaMismatch) Error() string {
+	return fmt.Sprintf("files are %s (%s) but torrent %d is %s; a different media is a new edition, not a trump", e.Local, e.Evidence, e.TorrentID, e.Trumped)
+}
+
+// ErrMissingScope reports an API key refused an endpoint because it was
+// created without the scope the endpoint needs. It is an ErrAPI.
+type ErrMissingScope struct {
+	Service string // "Redacted"
+	Scope   string // The scope needed, e.g. "Torrent"
+	Action  string // What was refused, e.g. "uploading"
+	Message string // The service's own explanation, if any
+}
+
+func (e *ErrMissingScope) Error() string {
+	msg := fmt.Sprintf("%s API key lacks the %s scope needed for %s", e.Service, e.Scope, e.Action)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is makes a missing scope an API error for errors.Is.
+func (e *ErrMissingScope) Is(target error) bool {
+	return target == ErrAPI
+}
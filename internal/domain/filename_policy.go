@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FilenamePolicy names a tracker's track filename conventions. The tag command
+// generates filenames with a policy and validation checks filenames against the
+// default one, so generated names never break the rules they are checked by.
+type FilenamePolicy string
+
+const (
+	// FilenamePolicyRedacted writes "01 - Title.flac": at least two digits, " - "
+	// after the number and, on albums with several composers, the composer's
+	// surname before the title ("01 - Bach - Title.flac")
+	FilenamePolicyRedacted FilenamePolicy = "redacted"
+	// FilenamePolicyPlain writes "1 - Title.flac", padding track numbers only on
+	// albums of ten or more tracks, and never names composers
+	FilenamePolicyPlain FilenamePolicy = "plain"
+)
+
+// DefaultFilenamePolicy is the policy validation checks filenames against.
+const DefaultFilenamePolicy = FilenamePolicyRedacted
+
+// FilenameSeparator separates the track number, composer and title in filenames.
+const FilenameSeparator = " - "
+
+// leadingNumberPattern splits a filename into its leading track number and the rest.
+var leadingNumberPattern = regexp.MustCompile(`^(\d+)(.*)$`)
+
+// ParseFilenamePolicy parses "redacted" or "plain"; "" means DefaultFilenamePolicy.
+func ParseFilenamePolicy(s string) (FilenamePolicy, error) {
+	switch p := FilenamePolicy(s); p {
+	case "":
+		return DefaultFilenamePolicy, nil
+	case FilenamePolicyRedacted, FilenamePolicyPlain:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %q (want redacted or plain)", ErrUnknownFilenamePolicy, s)
+	}
+}
+
+// TrackNumber formats a track number for an album of totalTracks tracks, wide
+// enough for every track to sort in order and at least two digits under
+// FilenamePolicyRedacted.
+func (p FilenamePolicy) TrackNumber(track, totalTracks int) string {
+	width := 1
+	if totalTracks > 9 {
+		width = len(strconv.Itoa(totalTracks))
+	}
+	if p == FilenamePolicyRedacted && width < 2 {
+		width = 2
+	}
+	return fmt.Sprintf("%0*d", width, track)
+}
+
+// Composer returns the composer surname the policy puts in the track's
+// filename, or "" when the filename names no composer.
+func (p FilenamePolicy) Composer(track *Track, torrent *Torrent) string {
+	if p != FilenamePolicyRedacted || !torrent.hasSeveralComposers() {
+		return ""
+	}
+	for _, artist := range track.Artists {
+		if artist.Role == RoleComposer {
+			return artist.LastName()
+		}
+	}
+	return ""
+}
+
+// Filename joins a track number, optional composer and title, all already safe
+// for the filesystem, into a filename with extension ext.
+func (p FilenamePolicy) Filename(number, composer, title, ext string) string {
+	parts := []string{number}
+	if composer != "" {
+		parts = append(parts, composer)
+	}
+	return strings.Join(append(parts, title), FilenameSeparator) + ext
+}
+
+// Check returns the ways a track's filename breaks the policy, as issues with
+// Level and Message set: a track number running straight into the title is an
+// error, other deviations are warnings and a missing composer is a suggestion.
+// Filenames without a leading track number are left to the track number rule.
+func (p FilenamePolicy) Check(filename string, track *Track, torrent *Torrent) []ValidationIssue {
+	m := leadingNumberPattern.FindStringSubmatch(filename)
+	if m == nil {
+		return nil
+	}
+	number, rest := m[1], m[2]
+
+	var issues []ValidationIssue
+	switch {
+	case strings.HasPrefix(rest, FilenameSeparator):
+	case strings.TrimLeft(rest, " -._") == rest:
+		issues = append(issues, ValidationIssue{Level: LevelError,
+			Message: fmt.Sprintf("track number %s runs into the title; separate them with %q", number, FilenameSeparator)})
+	default:
+		issues = append(issues, ValidationIssue{Level: LevelWarning,
+			Message: fmt.Sprintf("track number %s should be followed by %q", number, FilenameSeparator)})
+	}
+	if want := p.TrackNumber(track.Track, len(torrent.Tracks())); len(number) < len(want) {
+		issues = append(issues, ValidationIssue{Level: LevelWarning,
+			Message: fmt.Sprintf("track number %s should be written %s", number, want)})
+	}
+	if composer := p.Composer(track, torrent); composer != "" {
+		named := strings.TrimLeft(rest, " -._")
+		if !strings.HasPrefix(strings.ToLower(named), strings.ToLower(composer)) {
+			issues = append(issues, ValidationIssue{Level: LevelInfo,
+				Message: fmt.Sprintf("name the composer after the track number on an album with several composers (%s)", composer)})
+		}
+	}
+	return issues
+}
+
+// hasSeveralComposers reports whether the torrent's tracks credit more than one composer.
+func (t *Torrent) hasSeveralComposers() bool {
+	composers := make(map[string]bool)
+	for _, track := range t.Tracks() {
+		for _, artist := range track.Artists {
+			if artist.Role == RoleComposer {
+				composers[artist.Name] = true
+			}
+		}
+	}
+	return len(composers) > 1
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseFilenamePolicy(t *testing.T) {
+	for in, want := range map[string]FilenamePolicy{"": FilenamePolicyRedacted, "redacted": FilenamePolicyRedacted, "plain": FilenamePolicyPlain} {
+		if got, err := ParseFilenamePolicy(in); err != nil || got != want {
+			t.Errorf("ParseFilenamePolicy(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFilenamePolicy("ops"); !errors.Is(err, ErrUnknownFilenamePolicy) {
+		t.Errorf("ParseFilenamePolicy(ops) error = %v, want ErrUnknownFilenamePolicy", err)
+	}
+}
+
+func TestFilenamePolicy_TrackNumber(t *testing.T) {
+	tests := []struct {
+		Policy      FilenamePolicy
+		Track       int
+		TotalTracks int
+		Want        string
+	}{
+		{FilenamePolicyRedacted, 1, 5, "01"},
+		{FilenamePolicyRedacted, 7, 120, "007"},
+		{FilenamePolicyPlain, 1, 5, "1"},
+		{FilenamePolicyPlain, 1, 12, "01"},
+	}
+	for _, tt := range tests {
+		if got := tt.Policy.TrackNumber(tt.Track, tt.TotalTracks); got != tt.Want {
+			t.Errorf("%s.TrackNumber(%d, %d) = %q, want %q", tt.Policy, tt.Track, tt.TotalTracks, got, tt.Want)
+		}
+	}
+}
+
+func TestFilenamePolicy_Check(t *testing.T) {
+	bach := &Track{Track: 1, Artists: []Artist{{Name: "Johann Sebastian Bach", Role: RoleComposer}}}
+	handel := &Track{Track: 2, Artists: []Artist{{Name: "George Frideric Handel", Role: RoleComposer}}}
+	single := &Torrent{Files: []FileLike{bach}}
+	multi := &Torrent{Files: []FileLike{bach, handel}}
+
+	tests := []struct {
+		Name     string
+		Policy   FilenamePolicy
+		Filename string
+		Torrent  *Torrent
+		Want     []Level
+	}{
+		{"conforming", FilenamePolicyRedacted, "01 - Toccata.flac", single, nil},
+		{"no separator", FilenamePolicyRedacted, "01Toccata.flac", single, []Level{LevelError}},
+		{"dot separator", FilenamePolicyRedacted, "01. Toccata.flac", single, []Level{LevelWarning}},
+		{"one digit", FilenamePolicyRedacted, "1 - Toccata.flac", single, []Level{LevelWarning}},
+		{"one digit under plain", FilenamePolicyPlain, "1 - Toccata.flac", single, nil},
+		{"no track number", FilenamePolicyRedacted, "Toccata.flac", single, nil},
+		{"composer named", FilenamePolicyRedacted, "01 - Bach - Toccata.flac", multi, nil},
+		{"composer missing", FilenamePolicyRedacted, "01 - Toccata.flac", multi, []Level{LevelInfo}},
+		{"composer not required by plain", FilenamePolicyPlain, "1 - Toccata.flac", multi, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			var got []Level
+			for _, issue := range tt.Policy.Check(tt.Filename, bach, tt.Torrent) {
+				got = append(got, issue.Level)
+			}
+			if !slices.Equal(got, tt.Want) {
+				t.Errorf("Check(%q) levels = %v, want %v", tt.Filename, got, tt.Want)
+			}
+		})
+	}
+}
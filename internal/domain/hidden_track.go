@@ -0,0 +1,49 @@
+package domain
+
+import "fmt"
+
+// HiddenTrackPolicy decides what happens to hidden pregap tracks (Track.Hidden).
+type HiddenTrackPolicy string
+
+const (
+	// HiddenTrackInclude keeps hidden tracks, titling untitled ones HiddenTrackTitle
+	HiddenTrackInclude HiddenTrackPolicy = "include"
+	// HiddenTrackDrop removes hidden tracks from the metadata
+	HiddenTrackDrop HiddenTrackPolicy = "drop"
+)
+
+// ParseHiddenTrackPolicy parses "include" or "drop"; "" means include.
+func ParseHiddenTrackPolicy(s string) (HiddenTrackPolicy, error) {
+	switch p := HiddenTrackPolicy(s); p {
+	case "":
+		return HiddenTrackInclude, nil
+	case HiddenTrackInclude, HiddenTrackDrop:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %q (want include or drop)", ErrUnknownHiddenTrackPolicy, s)
+	}
+}
+
+// ApplyHiddenTrackPolicy applies policy to the torrent's hidden tracks and returns
+// the paths of the tracks it dropped.
+func (t *Torrent) ApplyHiddenTrackPolicy(policy HiddenTrackPolicy) []string {
+	var dropped []string
+	files := t.Files[:0]
+	for _, f := range t.Files {
+		track, ok := f.(*Track)
+		if !ok || !track.IsHidden() {
+			files = append(files, f)
+			continue
+		}
+		if policy == HiddenTrackDrop {
+			dropped = append(dropped, track.Path)
+			continue
+		}
+		if track.Title == "" {
+			track.Title = HiddenTrackTitle
+		}
+		files = append(files, f)
+	}
+	t.Files = files
+	return dropped
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseHiddenTrackPolicy(t *testing.T) {
+	for input, want := range map[string]HiddenTrackPolicy{"": HiddenTrackInclude, "include": HiddenTrackInclude, "drop": HiddenTrackDrop} {
+		got, err := ParseHiddenTrackPolicy(input)
+		if err != nil || got != want {
+			t.Errorf("ParseHiddenTrackPolicy(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseHiddenTrackPolicy("keep"); !errors.Is(err, ErrUnknownHiddenTrackPolicy) {
+		t.Errorf("ParseHiddenTrackPolicy(keep) error = %v, want ErrUnknownHiddenTrackPolicy", err)
+	}
+}
+
+func TestTorrent_ApplyHiddenTrackPolicy(t *testing.T) {
+	build := func() *Torrent {
+		return &Torrent{Files: []FileLike{
+			&Track{File: File{Path: "00 - Pregap.flac"}, Disc: 1, Track: 0, Hidden: true},
+			&Track{File: File{Path: "01 - Aria.flac"}, Disc: 1, Track: 1, Title: "Aria"},
+			&Track{File: File{Path: "Sarabande.flac"}, Disc: 1, Track: 0, Title: "Sarabande"}, // Unnumbered, not hidden
+			&File{Path: "folder.jpg"},
+		}}
+	}
+
+	included := build()
+	if dropped := included.ApplyHiddenTrackPolicy(HiddenTrackInclude); len(dropped) != 0 {
+		t.Errorf("include dropped %v", dropped)
+	}
+	if tracks := included.Tracks(); len(tracks) != 3 || tracks[0].Title != HiddenTrackTitle || tracks[2].Title != "Sarabande" {
+		t.Errorf("include: tracks = %v, want hidden track titled %q and the unnumbered one left alone", tracks, HiddenTrackTitle)
+	}
+
+	dropped := build()
+	paths := dropped.ApplyHiddenTrackPolicy(HiddenTrackDrop)
+	if len(paths) != 1 || paths[0] != "00 - Pregap.flac" {
+		t.Errorf("drop returned %v", paths)
+	}
+	if len(dropped.Files) != 3 || len(dropped.Tracks()) != 2 {
+		t.Errorf("drop: %d files, %d tracks; want 3 files, 2 tracks", len(dropped.Files), len(dropped.Tracks()))
+	}
+}
@@ -0,0 +1,232 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+// GroupingConfidence is how sure GroupMovements is of a work title.
+type GroupingConfidence int
+
+const (
+	// GroupingNone: no work title was found for the run
+	GroupingNone GroupingConfidence = iota
+	// GroupingLow: the album title, for an album that is one work and no source has the structure
+	GroupingLow
+	// GroupingMedium: a source names one work for the run, but not every movement matches
+	GroupingMedium
+	// GroupingHigh: a source names one work for the run and every movement matches
+	GroupingHigh
+)
+
+// String returns "none", "low", "medium" or "high".
+func (c GroupingConfidence) String() string {
+	switch c {
+	case GroupingLow:
+		return "low"
+	case GroupingMedium:
+		return "medium"
+	case GroupingHigh:
+		return "high"
+	default:
+		return "none"
+	}
+}
+
+// WorkGroupingPolicy decides which work titles GroupMovements writes into the
+// track titles.
+type WorkGroupingPolicy string
+
+const (
+	// WorkGroupingConfident applies medium and high confidence work titles (the default)
+	WorkGroupingConfident WorkGroupingPolicy = "confident"
+	// WorkGroupingAll applies every work title found, including the album title guess
+	WorkGroupingAll WorkGroupingPolicy = "all"
+	// WorkGroupingOff only reports the work titles, leaving the track titles as tagged
+	WorkGroupingOff WorkGroupingPolicy = "off"
+)
+
+// ParseWorkGroupingPolicy parses "confident", "all" or "off"; "" means confident.
+func ParseWorkGroupingPolicy(s string) (WorkGroupingPolicy, error) {
+	switch p := WorkGroupingPolicy(s); p {
+	case "":
+		return WorkGroupingConfident, nil
+	case WorkGroupingConfident, WorkGroupingAll, WorkGroupingOff:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %q (want confident, all or off)", ErrUnknownWorkGroupingPolicy, s)
+	}
+}
+
+// applies reports whether the policy writes a work title found with confidence c.
+func (p WorkGroupingPolicy) applies(c GroupingConfidence) bool {
+	switch p {
+	case WorkGroupingAll:
+		return c >= GroupingLow
+	case WorkGroupingOff:
+		return false
+	default:
+		return c >= GroupingMedium
+	}
+}
+
+// MovementGrouping is a run of consecutive tracks by one composer whose titles
+// are bare movement names ("Allegro", "II. Andante"), and the work they belong to.
+type MovementGrouping struct {
+	Disc        int
+	First, Last int    // Track numbers
+	Work        string // "" when no work title was found
+	Source      string // Where Work came from: the source's first Sources entry (a URL), or "album title"
+	Confidence  GroupingConfidence
+	Applied     bool // Whether the track titles were prefixed with Work
+}
+
+// String describes the grouping, e.g. `disc 1 tracks 1-4 -> "Symphony No. 5":
+// high confidence (https://www.discogs.com/release/123)`.
+func (g MovementGrouping) String() string {
+	tracks := fmt.Sprintf("disc %d tracks %d-%d", g.Disc, g.First, g.Last)
+	if g.Work == "" {
+		return tracks + ": bare movement titles, no work found"
+	}
+	s := fmt.Sprintf("%s -> %q: %s confidence (%s)", tracks, g.Work, g.Confidence, g.Source)
+	if !g.Applied {
+		s += ", not applied"
+	}
+	return s
+}
+
+// movementWords are tempo markings and dance forms that open bare movement titles.
+var movementWords = map[string]bool{
+	"adagietto": true, "adagio": true, "allegretto": true, "allegro": true, "andante": true, "andantino": true,
+	"grave": true, "larghetto": true, "largo": true, "lento": true, "maestoso": true, "moderato": true,
+	"prestissimo": true, "presto": true, "vivace": true, "vivo": true, "tempo": true, "molto": true,
+	"poco": true, "un": true, "sostenuto": true, "allemande": true, "allemanda": true, "courante": true,
+	"corrente": true, "sarabande": true, "sarabanda": true, "gigue": true, "giga": true, "gavotte": true,
+	"bourree": true, "menuet": true, "menuetto": true, "minuet": true, "minuetto": true, "passepied": true,
+	"siciliano": true, "siciliana": true, "scherzo": true, "rondo": true, "rondeau": true, "finale": true,
+	"intermezzo": true, "romanze": true, "romance": true, "cavatina": true, "marcia": true, "tema": true,
+}
+
+// movementNumberPattern matches a movement number opening a title ("IV. ", "2 - ").
+var movementNumberPattern = regexp.MustCompile(`^(?:[IVXLC]+|\d+)\s*[.):-]\s*`)
+
+// bareMovement returns the movement name of a title that is only a movement,
+// without its number, or "" for any other title.
+func bareMovement(title string) string {
+	if _, movement := SplitWorkTitle(title); movement != "" {
+		return ""
+	}
+	name := strings.TrimSpace(movementNumberPattern.ReplaceAllString(strings.TrimSpace(title), ""))
+	first, _, _ := strings.Cut(normalize.Name(name), " ")
+	if !movementWords[strings.Trim(first, ".,;:!")] {
+		return ""
+	}
+	return name
+}
+
+// sameMovement reports whether two movement names are the same movement: one
+// is the other, or starts it ("Allegro" and "Allegro con brio").
+func sameMovement(a, b string) bool {
+	a, b = normalize.Compact(a), normalize.Compact(b)
+	return a != "" && b != "" && (strings.HasPrefix(a, b) || strings.HasPrefix(b, a))
+}
+
+// GroupMovements finds runs of two or more consecutive tracks on one disc by
+// one composer whose titles are bare movement names and looks up the work they
+// belong to: the work the first source with titles for every track of the run
+// names for all of them ("Symphony No. 5: I. Allegro con brio"), else, when
+// the run is the whole album, the album title. The titles of the runs the
+// policy accepts become "Work: Movement". Returns every run found.
+func (t *Torrent) GroupMovements(policy WorkGroupingPolicy, sources ...*Torrent) []MovementGrouping {
+	var groupings []MovementGrouping
+	tracks := t.Tracks()
+	for start := 0; start < len(tracks); {
+		end := start + 1
+		if bareMovement(tracks[start].Title) != "" {
+			for end < len(tracks) && tracks[end].Disc == tracks[start].Disc &&
+				tracks[end].Track == tracks[end-1].Track+1 &&
+				tracks[end].Composer() == tracks[start].Composer() &&
+				bareMovement(tracks[end].Title) != "" {
+				end++
+			}
+		}
+		if run := tracks[start:end]; len(run) >= 2 {
+			g := MovementGrouping{Disc: run[0].Disc, First: run[0].Track, Last: run[len(run)-1].Track}
+			g.Work, g.Source, g.Confidence = t.findWork(run, len(run) == len(tracks), sources)
+			if g.Work != "" && policy.applies(g.Confidence) {
+				t.ApplyMovementGrouping(g)
+				g.Applied = true
+			}
+			groupings = append(groupings, g)
+		}
+		start = end
+	}
+	return groupings
+}
+
+// ApplyMovementGrouping prefixes the titles of the grouping's tracks with its
+// work ("Work: Movement"), skipping titles that already name a work. Used to
+// apply a grouping the policy left for the user to confirm.
+func (t *Torrent) ApplyMovementGrouping(g MovementGrouping) {
+	if g.Work == "" {
+		return
+	}
+	for _, track := range t.Tracks() {
+		if track.Disc == g.Disc && track.Track >= g.First && track.Track <= g.Last {
+			if _, movement := SplitWorkTitle(track.Title); movement == "" {
+				track.Title = g.Work + ": " + strings.TrimSpace(track.Title)
+			}
+		}
+	}
+}
+
+// findWork returns the work title for a run of bare movement tracks, where it
+// came from and how sure it is.
+func (t *Torrent) findWork(run []*Track, wholeAlbum bool, sources []*Torrent) (string, string, GroupingConfidence) {
+	for _, source := range sources {
+		if work, confidence := source.workFor(run); work != "" {
+			name := "another source"
+			if len(source.Sources) > 0 {
+				name = source.Sources[0]
+			}
+			return work, name, confidence
+		}
+	}
+	if wholeAlbum && t.Title != "" {
+		return t.Title, "album title", GroupingLow
+	}
+	return "", "", GroupingNone
+}
+
+// workFor returns the one work the torrent's titles for the run's tracks name,
+// or "" when a track is missing, has no work or names a different one.
+func (t *Torrent) workFor(run []*Track) (string, GroupingConfidence) {
+	if t == nil {
+		return "", GroupingNone
+	}
+	var work string
+	confidence := GroupingHigh
+	for _, local := range run {
+		var title string
+		for _, track := range t.Tracks() {
+			if track.Disc == local.Disc && track.Track == local.Track {
+				title = track.Title
+				break
+			}
+		}
+		w, movement := SplitWorkTitle(title)
+		if movement == "" || (work != "" && normalize.Name(w) != normalize.Name(work)) {
+			return "", GroupingNone
+		}
+		if work == "" {
+			work = w
+		}
+		if !sameMovement(bareMovement(local.Title), movementNumberPattern.ReplaceAllString(movement, "")) {
+			confidence = GroupingMedium
+		}
+	}
+	return work, confidence
+}
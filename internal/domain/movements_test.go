@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestBareMovement(t *testing.T) {
+	tests := map[string]string{
+		"Allegro con brio":         "Allegro con brio",
+		"II. Andante":              "Andante",
+		"3 - Menuetto. Allegretto": "Menuetto. Allegretto",
+		"Tempo di minuetto":        "Tempo di minuetto",
+		"Symphony No. 5: Allegro":  "",
+		"Ave Maria":                "",
+		"Adagio for Strings":       "Adagio for Strings",
+	}
+	for title, want := range tests {
+		if got := bareMovement(title); got != want {
+			t.Errorf("bareMovement(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func movementTorrent(titles ...string) *Torrent {
+	torrent := &Torrent{Title: "Symphony No. 5"}
+	beethoven := Artist{Name: "Ludwig van Beethoven", Role: RoleComposer}
+	for i, title := range titles {
+		torrent.Files = append(torrent.Files, &Track{Disc: 1, Track: i + 1, Title: title, Artists: []Artist{beethoven}})
+	}
+	return torrent
+}
+
+func titles(t *Torrent) []string {
+	var out []string
+	for _, track := range t.Tracks() {
+		out = append(out, track.Title)
+	}
+	return out
+}
+
+func TestTorrent_GroupMovements(t *testing.T) {
+	online := movementTorrent(
+		"Egmont, Op. 84: Overture",
+		"Symphony No. 5 in C minor, Op. 67: I. Allegro con brio",
+		"Symphony No. 5 in C minor, Op. 67: II. Andante con moto",
+		"Symphony No. 5 in C minor, Op. 67: III. Scherzo. Allegro",
+		"Symphony No. 5 in C minor, Op. 67: IV. Allegro",
+		"Coriolan Overture, Op. 62",
+	)
+	online.Sources = []string{"https://www.discogs.com/release/123"}
+
+	local := movementTorrent("Egmont Overture", "Allegro con brio", "Andante con moto", "Scherzo", "Finale. Allegro", "Coriolan Overture")
+	groupings := local.GroupMovements(WorkGroupingConfident, online)
+	if len(groupings) != 1 {
+		t.Fatalf("GroupMovements() = %v, want one run", groupings)
+	}
+	g := groupings[0]
+	if g.First != 2 || g.Last != 5 || g.Work != "Symphony No. 5 in C minor, Op. 67" || g.Source != online.Sources[0] || !g.Applied {
+		t.Errorf("grouping = %+v", g)
+	}
+	// "Finale. Allegro" is not the online "IV. Allegro"
+	if g.Confidence != GroupingMedium {
+		t.Errorf("Confidence = %v, want medium", g.Confidence)
+	}
+	want := []string{
+		"Egmont Overture",
+		"Symphony No. 5 in C minor, Op. 67: Allegro con brio",
+		"Symphony No. 5 in C minor, Op. 67: Andante con moto",
+		"Symphony No. 5 in C minor, Op. 67: Scherzo",
+		"Symphony No. 5 in C minor, Op. 67: Finale. Allegro",
+		"Coriolan Overture",
+	}
+	if got := titles(local); !slices.Equal(got, want) {
+		t.Errorf("titles = %q, want %q", got, want)
+	}
+}
+
+func TestTorrent_GroupMovements_Confidence(t *testing.T) {
+	online := movementTorrent("Symphony No. 5: I. Allegro con brio", "Symphony No. 5: II. Andante con moto")
+
+	local := movementTorrent("I. Allegro con brio", "II. Andante con moto")
+	if g := local.GroupMovements(WorkGroupingOff, online); len(g) != 1 || g[0].Confidence != GroupingHigh || g[0].Applied {
+		t.Errorf("GroupMovements(off) = %+v, want a high confidence run left unapplied", g)
+	}
+	if got := titles(local); got[0] != "I. Allegro con brio" {
+		t.Errorf("off policy changed titles: %q", got)
+	}
+
+	// No source has the structure: the album title, for a single-work album
+	g := local.GroupMovements(WorkGroupingConfident)
+	if len(g) != 1 || g[0].Work != "Symphony No. 5" || g[0].Confidence != GroupingLow || g[0].Applied {
+		t.Fatalf("GroupMovements(confident) = %+v, want a low confidence album title left unapplied", g)
+	}
+	local.ApplyMovementGrouping(g[0])
+	if got := titles(local); got[1] != "Symphony No. 5: II. Andante con moto" {
+		t.Errorf("ApplyMovementGrouping() titles = %q", got)
+	}
+
+	// Online works that differ across the run give no structure
+	mixed := movementTorrent("Sonata No. 1: Allegro", "Sonata No. 2: Adagio", "Coda")
+	local = movementTorrent("Allegro", "Adagio", "Coda")
+	if g := local.GroupMovements(WorkGroupingAll, mixed); len(g) != 1 || g[0].Work != "" || g[0].Applied {
+		t.Errorf("GroupMovements() = %+v, want a run with no work", g)
+	}
+}
+
+func TestParseWorkGroupingPolicy(t *testing.T) {
+	if p, err := ParseWorkGroupingPolicy(""); err != nil || p != WorkGroupingConfident {
+		t.Errorf(`ParseWorkGroupingPolicy("") = %q, %v`, p, err)
+	}
+	if _, err := ParseWorkGroupingPolicy("always"); !errors.Is(err, ErrUnknownWorkGroupingPolicy) {
+		t.Errorf("ParseWorkGroupingPolicy(always) error = %v", err)
+	}
+}
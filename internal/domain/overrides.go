@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Override lets a command proceed past one known kind of problem while every
+// other check still blocks.
+type Override string
+
+const (
+	// AllowUnmatchedTracks tags the files it can match when some tracks have no file
+	AllowUnmatchedTracks Override = "unmatched-tracks"
+	// AllowWarnings keeps warnings from blocking under the strict profile
+	AllowWarnings Override = "warnings"
+	// AllowMissingYear keeps a missing album year from blocking
+	AllowMissingYear Override = "missing-year"
+)
+
+// Overrides are the overrides given for a run.
+type Overrides []Override
+
+// ParseOverrides parses override names, each of which may hold several
+// separated by commas ("warnings,missing-year").
+func ParseOverrides(specs []string) (Overrides, error) {
+	var overrides Overrides
+	for _, spec := range specs {
+		for _, name := range strings.Split(spec, ",") {
+			switch o := Override(strings.TrimSpace(name)); o {
+			case "":
+			case AllowUnmatchedTracks, AllowWarnings, AllowMissingYear:
+				if !slices.Contains(overrides, o) {
+					overrides = append(overrides, o)
+				}
+			default:
+				return nil, fmt.Errorf("%w: %q (want unmatched-tracks, warnings or missing-year)", ErrUnknownOverride, name)
+			}
+		}
+	}
+	return overrides, nil
+}
+
+// Allows reports whether o was given.
+func (o Overrides) Allows(override Override) bool {
+	return slices.Contains(o, override)
+}
+
+// Blocking returns the issues profile treats as blocking, less those the
+// overrides allow. Errors always block.
+func (o Overrides) Blocking(profile ValidationProfile, issues []ValidationIssue) []ValidationIssue {
+	var blocking []ValidationIssue
+	for _, issue := range profile.Blocking(issues) {
+		switch {
+		case issue.Level == LevelError:
+			blocking = append(blocking, issue)
+		case o.Allows(AllowMissingYear) && issue.IsMissingYear():
+		case o.Allows(AllowWarnings):
+		default:
+			blocking = append(blocking, issue)
+		}
+	}
+	return blocking
+}
+
+// String returns the overrides as given on the command line ("warnings,missing-year").
+func (o Overrides) String() string {
+	names := make([]string, len(o))
+	for i, override := range o {
+		names[i] = string(override)
+	}
+	return strings.Join(names, ",")
+}
+
+// Set adds the overrides in s, so Overrides can be a repeatable command-line flag.
+func (o *Overrides) Set(s string) error {
+	parsed, err := ParseOverrides(append([]string{o.String()}, s))
+	if err != nil {
+		return err
+	}
+	*o = parsed
+	return nil
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseOverrides(t *testing.T) {
+	got, err := ParseOverrides([]string{"warnings, missing-year", "warnings", "unmatched-tracks"})
+	if err != nil {
+		t.Fatalf("ParseOverrides() error = %v", err)
+	}
+	if got.String() != "warnings,missing-year,unmatched-tracks" {
+		t.Errorf("ParseOverrides() = %q", got)
+	}
+	if _, err := ParseOverrides([]string{"errors"}); !errors.Is(err, ErrUnknownOverride) {
+		t.Errorf("ParseOverrides(errors) error = %v, want ErrUnknownOverride", err)
+	}
+
+	var flagged Overrides
+	for _, s := range []string{"warnings", "missing-year"} {
+		if err := flagged.Set(s); err != nil {
+			t.Fatalf("Set(%q) error = %v", s, err)
+		}
+	}
+	if !flagged.Allows(AllowWarnings) || !flagged.Allows(AllowMissingYear) || flagged.Allows(AllowUnmatchedTracks) {
+		t.Errorf("Set() = %q, want warnings and missing-year", flagged)
+	}
+}
+
+func TestOverrides_Blocking(t *testing.T) {
+	issues := []ValidationIssue{
+		{Level: LevelError, Rule: "2.3.1"},
+		{Level: LevelWarning, Rule: "2.3.8", Message: MessageYearMissing},
+		{Level: LevelWarning, Rule: "2.3.16.4-album", Message: MessageYearTagMissing},
+		{Level: LevelWarning, Rule: "2.3.2"},
+	}
+	tests := []struct {
+		Name    string
+		Allow   Overrides
+		Profile ValidationProfile
+		Want    int
+	}{
+		{"none, strict", nil, ValidationStrict, 4},
+		{"missing year, strict", Overrides{AllowMissingYear}, ValidationStrict, 2},
+		{"warnings, strict", Overrides{AllowWarnings}, ValidationStrict, 1},
+		{"warnings never lift errors", Overrides{AllowWarnings, AllowMissingYear}, ValidationDefault, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := tt.Allow.Blocking(tt.Profile, issues); len(got) != tt.Want {
+				t.Errorf("Blocking() = %v, want %d issues", got, tt.Want)
+			}
+		})
+	}
+}
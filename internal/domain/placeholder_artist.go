@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// placeholderArtistPattern matches the artist names rippers and taggers write
+// when they have none: "Unknown", "Unknown Artist", "Various", "VA", "N/A",
+// "?", ... "Anonymous" and "Traditional" are real composer credits and do not
+// match.
+var placeholderArtistPattern = regexp.MustCompile(`(?i)^(?:[\[(<]?unknown(?:\s+(?:artist|composer|performer|orchestra|conductor))?[\])>]?|various(?:\s+artists?)?|v\.?\s*a\.?|n\.?\s*/?\s*a\.?|none|no\s+artist|artist|composer|\?+|-+)$`)
+
+// signaturePattern matches ripper or tracker signatures left in artist fields:
+// "Ripped by user", "[TR24]", "www.example.org", "user@tracker".
+var signaturePattern = regexp.MustCompile(`(?i)\b(?:ripped|uploaded|encoded|tagged)\s+by\b|^\[[a-z0-9]+\]$|www\.|https?://|\S@\S|\.(?:com|org|net|ru|to)\b`)
+
+// IsPlaceholderArtist reports whether name is empty, a placeholder a ripper
+// wrote for want of a credit ("Unknown", "Various", "VA", "N/A") or a ripper
+// or tracker signature rather than an artist.
+func IsPlaceholderArtist(name string) bool {
+	name = strings.TrimSpace(name)
+	return name == "" || placeholderArtistPattern.MatchString(name) || signaturePattern.MatchString(name)
+}
+
+// DropPlaceholderArtists removes placeholder artists from the tracks where a
+// real credit in the same role exists: on the track itself, or on the track
+// with the same disc and track number in the first source that has one, whose
+// credits then take the placeholder's place. Placeholders without a real
+// credit are kept for validation to report. Returns a description of each change.
+func (t *Torrent) DropPlaceholderArtists(sources ...*Torrent) []string {
+	var dropped []string
+	for _, track := range t.Tracks() {
+		var kept []Artist
+		for _, a := range track.Artists {
+			if !IsPlaceholderArtist(a.Name) {
+				kept = append(kept, a)
+				continue
+			}
+			if slices.ContainsFunc(track.Artists, func(b Artist) bool { return b.Role == a.Role && !IsPlaceholderArtist(b.Name) }) {
+				dropped = append(dropped, fmt.Sprintf("disc %d track %d: dropped %s %q", track.Disc, track.Track, a.Role, a.Name))
+				continue
+			}
+			replaced := false
+			for _, source := range sources {
+				real := source.realArtists(track.Disc, track.Track, a.Role)
+				if len(real) == 0 {
+					continue
+				}
+				names := make([]string, len(real))
+				for i, r := range real {
+					names[i] = r.Name
+				}
+				dropped = append(dropped, fmt.Sprintf("disc %d track %d: %s %q -> %q", track.Disc, track.Track, a.Role, a.Name, strings.Join(names, ", ")))
+				kept = append(kept, real...)
+				replaced = true
+				break
+			}
+			if !replaced {
+				kept = append(kept, a)
+			}
+		}
+		track.Artists = kept
+	}
+	return dropped
+}
+
+// realArtists returns the given track's artists in role that are not placeholders.
+func (t *Torrent) realArtists(disc, number int, role Role) []Artist {
+	if t == nil {
+		return nil
+	}
+	for _, track := range t.Tracks() {
+		if track.Disc != disc || track.Track != number {
+			continue
+		}
+		var real []Artist
+		for _, a := range track.Artists {
+			if a.Role == role && !IsPlaceholderArtist(a.Name) {
+				real = append(real, a)
+			}
+		}
+		return real
+	}
+	return nil
+}
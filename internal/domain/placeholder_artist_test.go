@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsPlaceholderArtist(t *testing.T) {
+	for _, name := range []string{"", "Unknown", "unknown artist", "[Unknown]", "Unknown Composer", "Various", "Various Artists",
+		"VA", "V.A.", "N/A", "n.a.", "?", "--", "Ripped by Somebody", "[TR24]", "www.example.org", "rips@tracker"} {
+		if !IsPlaceholderArtist(name) {
+			t.Errorf("IsPlaceholderArtist(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"Anonymous", "Traditional", "Vanessa Benelli Mosell", "Nathalie Stutzmann", "Various Voices Choir",
+		"Unknown Mortal Orchestra", "Les Arts Florissants", "Sol Gabetta", "Anne Sofie von Otter"} {
+		if IsPlaceholderArtist(name) {
+			t.Errorf("IsPlaceholderArtist(%q) = true, want false", name)
+		}
+	}
+}
+
+func TestTorrent_DropPlaceholderArtists(t *testing.T) {
+	bach := Artist{Name: "Johann Sebastian Bach", Role: RoleComposer}
+	unknown := Artist{Name: "Unknown", Role: RoleComposer}
+	various := Artist{Name: "Various", Role: RoleEnsemble}
+	gould := Artist{Name: "Glenn Gould", Role: RoleSoloist, Instrument: "piano"}
+	local := &Torrent{Files: []FileLike{
+		&Track{Disc: 1, Track: 1, Title: "Aria", Artists: []Artist{unknown, bach, gould}},
+		&Track{Disc: 1, Track: 2, Title: "Variatio 1", Artists: []Artist{unknown, gould}},
+		&Track{Disc: 1, Track: 3, Title: "Variatio 2", Artists: []Artist{unknown, various}},
+	}}
+	placeholder := &Torrent{Files: []FileLike{
+		&Track{Disc: 1, Track: 2, Artists: []Artist{{Name: "VA", Role: RoleComposer}}},
+	}}
+	remote := &Torrent{Files: []FileLike{
+		&Track{Disc: 1, Track: 2, Artists: []Artist{bach}},
+		&Track{Disc: 1, Track: 3, Artists: []Artist{gould}},
+	}}
+
+	dropped := local.DropPlaceholderArtists(placeholder, remote)
+	if len(dropped) != 2 {
+		t.Errorf("dropped %v, want 2 changes", dropped)
+	}
+	tracks := local.Tracks()
+	for i, want := range [][]Artist{
+		{bach, gould},      // Real composer on the track itself
+		{bach, gould},      // Real composer from a source
+		{unknown, various}, // No real credit in either role: kept for validation
+	} {
+		if !slices.Equal(tracks[i].Artists, want) {
+			t.Errorf("track %d artists = %v, want %v", i+1, tracks[i].Artists, want)
+		}
+	}
+}
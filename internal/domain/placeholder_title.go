@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// placeholderTitlePattern matches the titles rippers write when they have none:
+// "Track 01", "Audio Track 3", "Piste 5", "Unknown", "Untitled", ...
+var placeholderTitlePattern = regexp.MustCompile(`(?i)^(?:(?:(?:audio\s+)?track|piste|pista|titel|traccia|spur|title)\s*[#.]?\s*\d+|unknown(?:\s+(?:title|track))?|untitled(?:\s+track)?|no\s+title|\?+|-+)$`)
+
+// IsPlaceholderTitle reports whether title is empty or a placeholder written by a
+// ripper that found no track titles ("Track 01", "Unknown", "Untitled").
+func IsPlaceholderTitle(title string) bool {
+	title = strings.TrimSpace(title)
+	return title == "" || placeholderTitlePattern.MatchString(title)
+}
+
+// PlaceholderTitleTracks returns the tracks whose titles are empty or placeholders.
+func (t *Torrent) PlaceholderTitleTracks() []*Track {
+	var tracks []*Track
+	for _, track := range t.Tracks() {
+		if IsPlaceholderTitle(track.Title) {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks
+}
+
+// FillPlaceholderTitles replaces empty and placeholder track titles with the real
+// title of the track with the same disc and track number in the first source that
+// has one. Returns a description of each replacement.
+func (t *Torrent) FillPlaceholderTitles(sources ...*Torrent) []string {
+	var filled []string
+	for _, track := range t.PlaceholderTitleTracks() {
+		for _, source := range sources {
+			if title, ok := source.realTitle(track.Disc, track.Track); ok {
+				filled = append(filled, fmt.Sprintf("disc %d track %d: %q -> %q", track.Disc, track.Track, track.Title, title))
+				track.Title = title
+				break
+			}
+		}
+	}
+	return filled
+}
+
+// realTitle returns the title of the given track when it is not a placeholder.
+func (t *Torrent) realTitle(disc, number int) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	for _, track := range t.Tracks() {
+		if track.Disc == disc && track.Track == number && !IsPlaceholderTitle(track.Title) {
+			return track.Title, true
+		}
+	}
+	return "", false
+}
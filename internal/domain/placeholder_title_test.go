@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsPlaceholderTitle(t *testing.T) {
+	for _, title := range []string{"", "  ", "Track 01", "track 1", "Track01", "Track #3", "Audio Track 12", "Piste 5", "Unknown", "Unknown Title", "Untitled", "???", "-"} {
+		if !IsPlaceholderTitle(title) {
+			t.Errorf("IsPlaceholderTitle(%q) = false, want true", title)
+		}
+	}
+	for _, title := range []string{"Aria", "Track and Field", "Unknown Pleasures", "Symphony No. 1", HiddenTrackTitle, "Title Music"} {
+		if IsPlaceholderTitle(title) {
+			t.Errorf("IsPlaceholderTitle(%q) = true, want false", title)
+		}
+	}
+}
+
+func TestTorrent_FillPlaceholderTitles(t *testing.T) {
+	local := &Torrent{Files: []FileLike{
+		&Track{Disc: 1, Track: 1, Title: "Track 01"},
+		&Track{Disc: 1, Track: 2, Title: ""},
+		&Track{Disc: 1, Track: 3, Title: "Variatio 2"},
+		&Track{Disc: 1, Track: 4, Title: "Unknown"},
+	}}
+	placeholder := &Torrent{Files: []FileLike{
+		&Track{Disc: 1, Track: 1, Title: "Track 1"},
+	}}
+	remote := &Torrent{Files: []FileLike{
+		&Track{Disc: 1, Track: 1, Title: "Aria"},
+		&Track{Disc: 1, Track: 2, Title: "Variatio 1"},
+		&Track{Disc: 1, Track: 3, Title: "Variation 2"},
+	}}
+
+	filled := local.FillPlaceholderTitles(placeholder, remote)
+	if len(filled) != 2 {
+		t.Errorf("filled %v, want 2 replacements", filled)
+	}
+	var titles []string
+	for _, track := range local.Tracks() {
+		titles = append(titles, track.Title)
+	}
+	if want := []string{"Aria", "Variatio 1", "Variatio 2", "Unknown"}; !slices.Equal(titles, want) {
+		t.Errorf("titles = %q, want %q", titles, want)
+	}
+	if remaining := local.PlaceholderTitleTracks(); len(remaining) != 1 || remaining[0].Track != 4 {
+		t.Errorf("PlaceholderTitleTracks() = %v, want track 4", remaining)
+	}
+}
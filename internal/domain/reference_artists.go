@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+// SpellingKey returns the key two spellings of one artist's name share: case,
+// diacritics, punctuation, spacing and a leading "The" are ignored, so
+// "Dvořák" and "Dvorak", or "J.S. Bach" and "J. S. Bach", match. Romanizations
+// ("Tchaikovsky", "Chaikovsky") do not: they may be different people.
+func SpellingKey(name string) string {
+	return normalize.Key(ArticleKey(name), normalize.FoldDiacritics|normalize.LettersAndDigits)
+}
+
+// ArtistChangeKind is how a track's artist differs from the reference's.
+type ArtistChangeKind int
+
+const (
+	ArtistMissing     ArtistChangeKind = iota + 1 // Credited by the reference only
+	ArtistExtra                                   // Credited by the track only
+	ArtistRenamed                                 // The same artist, spelled differently
+	ArtistRoleChanged                             // The same artist in another role
+)
+
+// ArtistChange is one difference between a track's artists and the reference
+// track's.
+type ArtistChange struct {
+	Kind      ArtistChangeKind
+	Artist    Artist // The track's artist; zero when missing
+	Reference Artist // The reference's artist; zero when extra
+}
+
+// String describes the change, e.g. `"Antonin Dvorak" is spelled "Antonín
+// Dvořák" in the reference`.
+func (c ArtistChange) String() string {
+	switch c.Kind {
+	case ArtistMissing:
+		return fmt.Sprintf("%s is missing; the reference credits it", c.Reference)
+	case ArtistExtra:
+		return fmt.Sprintf("%s is not in the reference", c.Artist)
+	case ArtistRenamed:
+		s := fmt.Sprintf("%q is spelled %q in the reference", c.Artist.Name, c.Reference.Name)
+		if c.Artist.Role != c.Reference.Role {
+			s += fmt.Sprintf(", as %s rather than %s", c.Reference.Role, c.Artist.Role)
+		}
+		return s
+	case ArtistRoleChanged:
+		return fmt.Sprintf("%q is %s but %s in the reference", c.Artist.Name, c.Artist.Role, c.Reference.Role)
+	}
+	return ""
+}
+
+// TrackArtistDiff is how a track's artists differ from those of the reference
+// track with the same disc and track number.
+type TrackArtistDiff struct {
+	Track   *Track
+	Changes []ArtistChange
+}
+
+// DiffArtists compares the artists of t's tracks with those of the reference
+// tracks with the same disc and track numbers. Artists are paired by name and
+// role, then by SpellingKey and role (a rename), then by name alone (a role
+// change) and by SpellingKey alone; those left over are missing or extra.
+// Tracks the reference lacks, and tracks without differences, are left out.
+func (t *Torrent) DiffArtists(reference *Torrent) []TrackArtistDiff {
+	if t == nil || reference == nil {
+		return nil
+	}
+	refTracks := make(map[[2]int]*Track)
+	for _, track := range reference.Tracks() {
+		refTracks[[2]int{track.Disc, track.Track}] = track
+	}
+
+	var diffs []TrackArtistDiff
+	for _, track := range t.Tracks() {
+		ref := refTracks[[2]int{track.Disc, track.Track}]
+		if ref == nil {
+			continue
+		}
+		if changes := diffTrackArtists(track.Artists, ref.Artists); len(changes) > 0 {
+			diffs = append(diffs, TrackArtistDiff{Track: track, Changes: changes})
+		}
+	}
+	return diffs
+}
+
+// diffTrackArtists pairs the artists of a track with the reference track's,
+// returning the differences in the track's artist order, then the missing ones
+// in the reference's order.
+func diffTrackArtists(artists, reference []Artist) []ArtistChange {
+	paired := make([]int, len(artists)) // Index into reference + 1; 0 when unpaired
+	taken := make([]bool, len(reference))
+	passes := []struct {
+		kind    ArtistChangeKind // 0: no change
+		matches func(a, r Artist) bool
+	}{
+		{0, func(a, r Artist) bool { return a.Name == r.Name && a.Role == r.Role }},
+		{ArtistRenamed, func(a, r Artist) bool { return a.Role == r.Role && SpellingKey(a.Name) == SpellingKey(r.Name) }},
+		{ArtistRoleChanged, func(a, r Artist) bool { return a.Name == r.Name }},
+		{ArtistRenamed, func(a, r Artist) bool { return SpellingKey(a.Name) == SpellingKey(r.Name) }},
+	}
+	kinds := make([]ArtistChangeKind, len(artists))
+	for _, pass := range passes {
+		for i, a := range artists {
+			if paired[i] != 0 {
+				continue
+			}
+			for j, r := range reference {
+				if !taken[j] && pass.matches(a, r) {
+					paired[i], taken[j], kinds[i] = j+1, true, pass.kind
+					break
+				}
+			}
+		}
+	}
+
+	var changes []ArtistChange
+	for i, a := range artists {
+		switch {
+		case paired[i] == 0:
+			changes = append(changes, ArtistChange{Kind: ArtistExtra, Artist: a})
+		case kinds[i] != 0:
+			changes = append(changes, ArtistChange{Kind: kinds[i], Artist: a, Reference: reference[paired[i]-1]})
+		}
+	}
+	for j, r := range reference {
+		if !taken[j] {
+			changes = append(changes, ArtistChange{Kind: ArtistMissing, Reference: r})
+		}
+	}
+	return changes
+}
+
+// ArtistRenames returns the reference's spelling of each artist name of t it
+// spells differently, as found by DiffArtists. A name spelled two ways in the
+// reference takes the spelling of its first track.
+func (t *Torrent) ArtistRenames(reference *Torrent) map[string]string {
+	renames := make(map[string]string)
+	for _, diff := range t.DiffArtists(reference) {
+		for _, c := range diff.Changes {
+			if _, ok := renames[c.Artist.Name]; !ok && c.Kind == ArtistRenamed {
+				renames[c.Artist.Name] = c.Reference.Name
+			}
+		}
+	}
+	return renames
+}
+
+// AdoptReferenceSpellings gives the artists the reference spells differently
+// its spelling throughout the album, album artists included, leaving their roles
+// alone. A sort name derived from the old spelling is derived again. Returns a
+// description of each rename.
+func (t *Torrent) AdoptReferenceSpellings(reference *Torrent) []string {
+	renames := t.ArtistRenames(reference)
+	if len(renames) == 0 {
+		return nil
+	}
+	t.eachArtist(func(a *Artist) {
+		name, ok := renames[a.Name]
+		if !ok {
+			return
+		}
+		if a.SortName != "" && a.SortName == DeriveSortName(a.Name, a.Role) {
+			a.SortName = DeriveSortName(name, a.Role)
+		}
+		a.Name = name
+	})
+
+	notes := make([]string, 0, len(renames))
+	for from, to := range renames {
+		notes = append(notes, fmt.Sprintf("%q -> %q", from, to))
+	}
+	sort.Strings(notes)
+	return notes
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpellingKey(t *testing.T) {
+	tests := []struct {
+		A, B string
+		Same bool
+	}{
+		{"Antonín Dvořák", "Antonin Dvorak", true},
+		{"J.S. Bach", "J. S. Bach", true},
+		{"The English Concert", "English Concert", true},
+		{"Pyotr Ilyich Tchaikovsky", "Pyotr Il'yich Chaikovsky", false},
+		{"Berlin Philharmonic", "Berliner Philharmoniker", false},
+	}
+	for _, tt := range tests {
+		if same := SpellingKey(tt.A) == SpellingKey(tt.B); same != tt.Same {
+			t.Errorf("SpellingKey(%q) == SpellingKey(%q) is %v, want %v", tt.A, tt.B, same, tt.Same)
+		}
+	}
+}
+
+func TestTorrent_DiffArtists(t *testing.T) {
+	torrent := func(artists ...[]Artist) *Torrent {
+		tr := &Torrent{}
+		for i, a := range artists {
+			tr.Files = append(tr.Files, &Track{Disc: 1, Track: i + 1, Title: "Track", Artists: a})
+		}
+		return tr
+	}
+	dvorak := Artist{Name: "Antonin Dvorak", Role: RoleComposer}
+	dvorakRef := Artist{Name: "Antonín Dvořák", Role: RoleComposer}
+	kubelik := Artist{Name: "Rafael Kubelik", Role: RoleConductor}
+	bpo := Artist{Name: "Berliner Philharmoniker", Role: RoleEnsemble}
+	soloist := Artist{Name: "Mstislav Rostropovich", Role: RoleSoloist}
+
+	local := torrent(
+		[]Artist{dvorak, kubelik, bpo},
+		[]Artist{dvorak, {Name: "Rafael Kubelik", Role: RoleSoloist}, bpo, soloist},
+		[]Artist{dvorak, kubelik},
+		[]Artist{dvorak},
+	)
+	reference := torrent(
+		[]Artist{dvorakRef, kubelik, bpo},
+		[]Artist{dvorakRef, kubelik, bpo},
+		[]Artist{dvorakRef, kubelik, bpo},
+	)
+
+	diffs := local.DiffArtists(reference)
+	want := [][]ArtistChange{
+		{{Kind: ArtistRenamed, Artist: dvorak, Reference: dvorakRef}},
+		{
+			{Kind: ArtistRenamed, Artist: dvorak, Reference: dvorakRef},
+			{Kind: ArtistRoleChanged, Artist: Artist{Name: "Rafael Kubelik", Role: RoleSoloist}, Reference: kubelik},
+			{Kind: ArtistExtra, Artist: soloist},
+		},
+		{
+			{Kind: ArtistRenamed, Artist: dvorak, Reference: dvorakRef},
+			{Kind: ArtistMissing, Reference: bpo},
+		},
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("DiffArtists() = %d diffs, want %d: %+v", len(diffs), len(want), diffs)
+	}
+	for i, diff := range diffs {
+		if diff.Track.Track != i+1 || !reflect.DeepEqual(diff.Changes, want[i]) {
+			t.Errorf("DiffArtists()[%d] = track %d %+v, want track %d %+v", i, diff.Track.Track, diff.Changes, i+1, want[i])
+		}
+	}
+
+	if got, want := diffs[0].Changes[0].String(), `"Antonin Dvorak" is spelled "Antonín Dvořák" in the reference`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := diffs[1].Changes[1].String(), `"Rafael Kubelik" is soloist but conductor in the reference`; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if diffs := local.DiffArtists(nil); diffs != nil {
+		t.Errorf("DiffArtists(nil) = %+v, want nil", diffs)
+	}
+}
+
+func TestTorrent_AdoptReferenceSpellings(t *testing.T) {
+	local := &Torrent{
+		AlbumArtist: []Artist{{Name: "Antonin Dvorak", Role: RoleComposer}},
+		Files: []FileLike{
+			&Track{Disc: 1, Track: 1, Title: "I.", Artists: []Artist{
+				{Name: "Antonin Dvorak", Role: RoleComposer, SortName: "Dvorak, Antonin"},
+				{Name: "Rafael Kubelik", Role: RoleConductor},
+			}},
+			&Track{Disc: 1, Track: 2, Title: "II.", Artists: []Artist{
+				{Name: "Antonin Dvorak", Role: RoleComposer},
+				{Name: "Rafael Kubelik", Role: RoleSoloist},
+			}},
+		},
+	}
+	reference := &Torrent{
+		Files: []FileLike{
+			&Track{Disc: 1, Track: 1, Title: "I.", Artists: []Artist{
+				{Name: "Antonín Dvořák", Role: RoleComposer},
+				{Name: "Rafael Kubelík", Role: RoleConductor},
+			}},
+		},
+	}
+
+	notes := local.AdoptReferenceSpellings(reference)
+	wantNotes := []string{`"Antonin Dvorak" -> "Antonín Dvořák"`, `"Rafael Kubelik" -> "Rafael Kubelík"`}
+	if !reflect.DeepEqual(notes, wantNotes) {
+		t.Errorf("AdoptReferenceSpellings() = %q, want %q", notes, wantNotes)
+	}
+	if got := local.AlbumArtist[0].Name; got != "Antonín Dvořák" {
+		t.Errorf("album artist = %q, want the reference spelling", got)
+	}
+	track1, track2 := local.Tracks()[0], local.Tracks()[1]
+	if got := track1.Artists[0].SortName; got != "Dvořák, Antonín" {
+		t.Errorf("sort name = %q, want it derived from the reference spelling", got)
+	}
+	// Renamed throughout the album, in any role, even on tracks the reference lacks
+	if got := track2.Artists[1]; got.Name != "Rafael Kubelík" || got.Role != RoleSoloist {
+		t.Errorf("track 2 artist = %+v, want Rafael Kubelík, soloist", got)
+	}
+
+	if notes := local.AdoptReferenceSpellings(reference); notes != nil {
+		t.Errorf("AdoptReferenceSpellings() again = %q, want nothing to do", notes)
+	}
+}
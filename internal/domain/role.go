@@ -67,6 +67,8 @@ func ParseRole(s string) (Role, error) {
 		return RoleEnsemble, nil
 	case "conductor":
 		return RoleConductor, nil
+	case "performer":
+		return RolePerformer, nil
 	case "arranger":
 		return RoleArranger, nil
 	case "guest":
@@ -0,0 +1,41 @@
+package domain
+
+import "github.com/cehbz/classical-tagger/internal/normalize"
+
+// Special composers credit music with no known composer. They are not people:
+// they have no surname or sort name of their own, and validation accepts them
+// where it asks for an identifiable composer.
+const (
+	ComposerAnonymous      = "Anonymous"
+	ComposerTraditional    = "Traditional"
+	ComposerGregorianChant = "Gregorian Chant"
+)
+
+// specialComposerSpellings maps the compact key (lowercase letters and digits,
+// diacritics folded) of each accepted spelling to its special composer.
+var specialComposerSpellings = map[string]string{
+	"anonymous": ComposerAnonymous, "anon": ComposerAnonymous, "anonym": ComposerAnonymous,
+	"anonyme": ComposerAnonymous, "anonimo": ComposerAnonymous, "anonymus": ComposerAnonymous,
+	"traditional": ComposerTraditional, "trad": ComposerTraditional, "traditionnel": ComposerTraditional,
+	"traditionell": ComposerTraditional, "tradicional": ComposerTraditional, "tradizionale": ComposerTraditional,
+	"gregorianchant": ComposerGregorianChant, "gregorian": ComposerGregorianChant, "plainchant": ComposerGregorianChant,
+	"plainsong": ComposerGregorianChant, "chantgregorien": ComposerGregorianChant, "gregorianischerchoral": ComposerGregorianChant,
+}
+
+// SpecialComposer returns the canonical spelling of a special composer credit,
+// e.g. "anon." -> "Anonymous", "Trad." -> "Traditional", "Plainchant" ->
+// "Gregorian Chant", or false when name is not one.
+func SpecialComposer(name string) (string, bool) {
+	canonical, ok := specialComposerSpellings[normalize.Key(name, normalize.FoldDiacritics|normalize.LettersAndDigits)]
+	return canonical, ok
+}
+
+// IsSpecialComposer reports whether the artist is a composer credited as
+// Anonymous, Traditional or Gregorian Chant, in any accepted spelling.
+func (a Artist) IsSpecialComposer() bool {
+	if a.Role != RoleComposer {
+		return false
+	}
+	_, ok := SpecialComposer(a.Name)
+	return ok
+}
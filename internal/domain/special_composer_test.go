@@ -0,0 +1,57 @@
+package domain
+
+import "testing"
+
+func TestSpecialComposer(t *testing.T) {
+	tests := map[string]string{
+		"Anonymous":       ComposerAnonymous,
+		"anon.":           ComposerAnonymous,
+		"Anónimo":         ComposerAnonymous,
+		"Trad.":           ComposerTraditional,
+		"Traditionnel":    ComposerTraditional,
+		"Gregorian chant": ComposerGregorianChant,
+		"Plainchant":      ComposerGregorianChant,
+	}
+	for name, want := range tests {
+		if got, ok := SpecialComposer(name); !ok || got != want {
+			t.Errorf("SpecialComposer(%q) = %q, %v; want %q", name, got, ok, want)
+		}
+	}
+	if got, ok := SpecialComposer("Anonymous 4"); ok {
+		t.Errorf("SpecialComposer(Anonymous 4) = %q, want no match", got)
+	}
+}
+
+func TestArtist_IsSpecialComposer(t *testing.T) {
+	chant := Artist{Name: "Gregorian Chant", Role: RoleComposer}
+	if !chant.IsSpecialComposer() {
+		t.Error("Gregorian Chant composer should be special")
+	}
+	if got := chant.LastName(); got != "Gregorian Chant" {
+		t.Errorf("LastName() = %q, want the whole name", got)
+	}
+	if got := chant.SortKey(); got != "Gregorian Chant" {
+		t.Errorf("SortKey() = %q, want the name unchanged", got)
+	}
+	if (Artist{Name: "Traditional", Role: RoleEnsemble}).IsSpecialComposer() {
+		t.Error("only composer credits are special")
+	}
+}
+
+func TestTorrent_NormalizeArtistNames_SpecialComposers(t *testing.T) {
+	torrent := &Torrent{Files: []FileLike{
+		&Track{Track: 1, Artists: []Artist{{Name: "Anon.", Role: RoleComposer}}},
+		&Track{Track: 2, Artists: []Artist{{Name: "trad", Role: RoleComposer}, {Name: "Anon.", Role: RoleEnsemble}}},
+	}}
+	notes := torrent.NormalizeArtistNames(nil)
+	tracks := torrent.Tracks()
+	if tracks[0].Artists[0].Name != ComposerAnonymous || tracks[1].Artists[0].Name != ComposerTraditional {
+		t.Errorf("composers = %q, %q", tracks[0].Artists[0].Name, tracks[1].Artists[0].Name)
+	}
+	if tracks[1].Artists[1].Name != "Anon." {
+		t.Errorf("non-composer %q should keep its spelling", tracks[1].Artists[1].Name)
+	}
+	if len(notes) != 2 {
+		t.Errorf("notes = %v", notes)
+	}
+}
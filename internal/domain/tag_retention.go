@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// TagRetention decides what re-tagging does with a tag already in a file.
+// Without a rule, tags the writer manages are overwritten, junk is dropped and
+// everything else is kept.
+type TagRetention string
+
+const (
+	// TagKeep keeps the existing value, even over the one in the metadata
+	TagKeep TagRetention = "keep"
+	// TagDrop removes the tag; a value in the metadata is not written either
+	TagDrop TagRetention = "drop"
+	// TagOverwrite writes the metadata's value, removing the tag when the
+	// metadata has none
+	TagOverwrite TagRetention = "overwrite"
+)
+
+// ParseTagRetention parses "keep", "drop" or "overwrite".
+func ParseTagRetention(s string) (TagRetention, error) {
+	switch r := TagRetention(strings.ToLower(strings.TrimSpace(s))); r {
+	case TagKeep, TagDrop, TagOverwrite:
+		return r, nil
+	}
+	return "", fmt.Errorf("%w: %q (want keep, drop or overwrite)", ErrUnknownTagRetention, s)
+}
+
+// TagRetentionRule applies a retention policy to the tags whose names match
+// Pattern, a shell pattern such as "ENCODER" or "REPLAYGAIN_*".
+type TagRetentionRule struct {
+	Pattern   string
+	Retention TagRetention
+}
+
+// TagRetentionRules are tried in order; the first matching rule applies.
+type TagRetentionRules []TagRetentionRule
+
+// ParseTagRetentionRules parses "PATTERN=policy" rules, such as
+// "ENCODER=keep" or "MUSICBRAINZ_*=drop". Patterns are case-insensitive.
+func ParseTagRetentionRules(specs []string) (TagRetentionRules, error) {
+	var rules TagRetentionRules
+	for _, spec := range specs {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		pattern, policy, ok := strings.Cut(spec, "=")
+		pattern = strings.ToUpper(strings.TrimSpace(pattern))
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("tag retention rule %q: want PATTERN=keep|drop|overwrite", spec)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("tag retention rule %q: %w", spec, err)
+		}
+		retention, err := ParseTagRetention(policy)
+		if err != nil {
+			return nil, fmt.Errorf("tag retention rule %q: %w", spec, err)
+		}
+		rules = append(rules, TagRetentionRule{Pattern: pattern, Retention: retention})
+	}
+	return rules, nil
+}
+
+// Lookup returns the policy of the first rule matching the tag name, or false
+// when no rule does.
+func (rules TagRetentionRules) Lookup(key string) (TagRetention, bool) {
+	key = strings.ToUpper(key)
+	for _, rule := range rules {
+		if ok, _ := path.Match(rule.Pattern, key); ok {
+			return rule.Retention, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTagRetentionRules(t *testing.T) {
+	rules, err := ParseTagRetentionRules([]string{"encoder=keep", " REPLAYGAIN_* = drop ", "", "*=overwrite"})
+	if err != nil {
+		t.Fatalf("ParseTagRetentionRules() error = %v", err)
+	}
+
+	tests := []struct {
+		key  string
+		want TagRetention
+	}{
+		{"ENCODER", TagKeep},
+		{"replaygain_track_gain", TagDrop},
+		{"COMMENT", TagOverwrite},
+	}
+	for _, tt := range tests {
+		if got, ok := rules.Lookup(tt.key); !ok || got != tt.want {
+			t.Errorf("Lookup(%q) = %q, %v; want %q", tt.key, got, ok, tt.want)
+		}
+	}
+	if _, ok := rules[:1].Lookup("COMMENT"); ok {
+		t.Error("Lookup() matched a tag no rule covers")
+	}
+
+	if _, err := ParseTagRetentionRules([]string{"ENCODER=preserve"}); !errors.Is(err, ErrUnknownTagRetention) {
+		t.Errorf("unknown policy error = %v, want ErrUnknownTagRetention", err)
+	}
+	for _, bad := range []string{"ENCODER", "=keep", "[=keep"} {
+		if _, err := ParseTagRetentionRules([]string{bad}); err == nil {
+			t.Errorf("ParseTagRetentionRules(%q) succeeded, want an error", bad)
+		}
+	}
+}
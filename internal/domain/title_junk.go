@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+)
+
+// titleJunkToken is one format or bitrate indicator as folder names write them:
+// "FLAC", "WEB", "24-96", "24bit", "96kHz", "320kbps", "DSD64", "Hi-Res".
+const titleJunkToken = `(?:WEB|FLAC|MP3|AAC|ALAC|WAV|APE|WV|DSD(?:64|128|256)?|DSF|DFF|Hi-?Res|\d{2}[- ]?bits?|\d{2}-\d{2,3}(?:[.,]\d)?|\d{2,3}(?:[.,]\d)?\s*kHz|\d{3}\s*kbps)`
+
+var (
+	// DirectoryYearPattern matches a year in brackets, as folder names give it:
+	// "[1963]", "(1963)", "{1963}". The submatch is the year.
+	DirectoryYearPattern = regexp.MustCompile(`[\[({]\s*(\d{4})\s*[\])}]`)
+
+	// titleJunkPattern matches bracketed format and bitrate indicators ("[FLAC]",
+	// "{24-96}", "(FLAC 24bit/96kHz)") and years in square or curly brackets. A
+	// year in parentheses is left alone: classical titles use it for a version
+	// ("Symphony No. 4 (1841)").
+	titleJunkPattern = regexp.MustCompile(`(?i)[\[({]\s*` + titleJunkToken + `(?:[\s/,+-]+` + titleJunkToken + `)*\s*[\])}]|[\[{]\s*\d{4}\s*[\]}]`)
+
+	// danglingSeparatorPattern matches separators left at the end once junk is removed.
+	danglingSeparatorPattern = regexp.MustCompile(`[\s\-–—_,]+$`)
+)
+
+// StripTitleJunk removes the format, bitrate and year indicators that folder
+// names carry ("Beethoven Symphonies [FLAC] [2013] {24-96}") from title.
+// Returns the cleaned title and the indicators removed, as written.
+func StripTitleJunk(title string) (string, []string) {
+	junk := titleJunkPattern.FindAllString(title, -1)
+	if len(junk) == 0 {
+		return title, nil
+	}
+	cleaned := strings.Join(strings.Fields(titleJunkPattern.ReplaceAllString(title, " ")), " ")
+	cleaned = danglingSeparatorPattern.ReplaceAllString(cleaned, "")
+	cleaned = strings.ReplaceAll(cleaned, " ,", ",")
+	return cleaned, junk
+}
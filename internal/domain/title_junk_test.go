@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStripTitleJunk(t *testing.T) {
+	tests := []struct {
+		title, want string
+		junk        []string
+	}{
+		{"Beethoven Symphonies [FLAC] [2013] {24-96}", "Beethoven Symphonies", []string{"[FLAC]", "[2013]", "{24-96}"}},
+		{"Mahler 2 [24-192 FLAC] - [2019]", "Mahler 2", []string{"[24-192 FLAC]", "[2019]"}},
+		{"Sonatas (16-44.1), Vol. 2", "Sonatas, Vol. 2", []string{"(16-44.1)"}},
+		{"Sacred Music [DSD64]", "Sacred Music", []string{"[DSD64]"}},
+		{"Symphony No. 4 (1841)", "Symphony No. 4 (1841)", nil},
+		{"Flac Is Not A Composer", "Flac Is Not A Composer", nil},
+		{"Songs [Live]", "Songs [Live]", nil},
+	}
+	for _, tt := range tests {
+		got, junk := StripTitleJunk(tt.title)
+		if got != tt.want || !slices.Equal(junk, tt.junk) {
+			t.Errorf("StripTitleJunk(%q) = %q, %q; want %q, %q", tt.title, got, junk, tt.want, tt.junk)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// KeyStyle is a house style for writing key signatures in titles.
+type KeyStyle string
+
+const (
+	KeyStyleEnglish KeyStyle = "english" // "C-sharp minor", "B-flat major"
+	KeyStyleSymbols KeyStyle = "symbols" // "C# minor", "Bb major"
+	KeyStyleGerman  KeyStyle = "german"  // "cis-Moll", "B-Dur" (German B is B-flat, H is B)
+)
+
+// NumberingStyle is the abbreviation a house style numbers works with: "No.",
+// "Nr." or "no.".
+type NumberingStyle string
+
+// TitleStyle is a house style for the key signatures and work numbers in
+// titles. Empty fields leave that notation as found.
+type TitleStyle struct {
+	Keys      KeyStyle
+	Numbering NumberingStyle
+}
+
+// ParseTitleStyle parses a key style (english, symbols or german) and a
+// numbering abbreviation ("No.", "Nr." or "no."); either may be "".
+func ParseTitleStyle(keys, numbering string) (TitleStyle, error) {
+	var style TitleStyle
+	switch k := KeyStyle(strings.ToLower(strings.TrimSpace(keys))); k {
+	case "", KeyStyleEnglish, KeyStyleSymbols, KeyStyleGerman:
+		style.Keys = k
+	default:
+		return style, fmt.Errorf("%w: keys %q (want english, symbols or german)", ErrUnknownTitleStyle, keys)
+	}
+	switch n := NumberingStyle(strings.TrimSpace(numbering)); n {
+	case "", "No.", "Nr.", "no.":
+		style.Numbering = n
+	default:
+		return style, fmt.Errorf("%w: numbering %q (want No., Nr. or no.)", ErrUnknownTitleStyle, numbering)
+	}
+	return style, nil
+}
+
+// IsZero reports whether the style leaves every title as found.
+func (s TitleStyle) IsZero() bool {
+	return s.Keys == "" && s.Numbering == ""
+}
+
+// Key is a key signature.
+type Key struct {
+	Note       byte // 'A' to 'G'
+	Accidental int  // -1 flat, 0 natural, 1 sharp
+	Minor      bool
+}
+
+// Format writes the key in style: "C-sharp minor", "C# minor" or "cis-Moll".
+func (k Key) Format(style KeyStyle) string {
+	mode := "major"
+	if k.Minor {
+		mode = "minor"
+	}
+	switch style {
+	case KeyStyleSymbols:
+		return string(k.Note) + [...]string{"b", "", "#"}[k.Accidental+1] + " " + mode
+	case KeyStyleGerman:
+		name := germanNote(k)
+		if k.Minor {
+			return strings.ToLower(name) + "-Moll"
+		}
+		return name + "-Dur"
+	}
+	return string(k.Note) + [...]string{"-flat", "", "-sharp"}[k.Accidental+1] + " " + mode
+}
+
+// germanNote names the key's note in German: H for B, B for B-flat, and the
+// suffixes -is and -es (-s after A and E) for sharps and flats.
+func germanNote(k Key) string {
+	note := string(k.Note)
+	if note == "B" {
+		note = "H"
+	}
+	switch {
+	case k.Accidental > 0:
+		return note + "is"
+	case k.Accidental == 0:
+		return note
+	case note == "H":
+		return "B"
+	case note == "A" || note == "E":
+		return note + "s"
+	}
+	return note + "es"
+}
+
+// parseGermanNote parses a German note name ("cis", "Es", "B", "h").
+func parseGermanNote(s string) (Key, bool) {
+	letter, suffix := unicode.ToUpper(rune(s[0])), strings.ToLower(s[1:])
+	var k Key
+	switch {
+	case letter == 'H':
+		k.Note = 'B'
+		if suffix == "is" {
+			k.Accidental = 1
+		} else if suffix != "" {
+			return k, false
+		}
+	case letter == 'B':
+		k.Note, k.Accidental = 'B', -1
+		if suffix != "" {
+			return k, false
+		}
+	default:
+		k.Note = byte(letter)
+		vowel := letter == 'A' || letter == 'E'
+		switch {
+		case suffix == "is":
+			k.Accidental = 1
+		case suffix == "s" && vowel, suffix == "es" && !vowel:
+			k.Accidental = -1
+		case suffix != "":
+			return k, false
+		}
+	}
+	return k, true
+}
+
+// keyPattern matches key signatures written in English ("C-sharp minor",
+// "C sharp minor"), with symbols ("C# minor", "B♭ major") and in German
+// ("cis-Moll", "Es-Dur"). Submatches: 1 English note, 2 sharp/flat, 3 symbol,
+// 4 mode; 5 German note, 6 Dur/Moll.
+var keyPattern = regexp.MustCompile(`\b(?:([A-Ga-g])(?:[- ]((?i:sharp|flat))|(#|♯|♭|b))?\s+((?i:major|minor))|([A-Ha-h](?:is|es|s)?)[- ]((?i:dur|moll)))\b`)
+
+// KeyNotation is a key signature as written in a title.
+type KeyNotation struct {
+	Key   Key
+	Text  string   // As written, e.g. "cis-Moll"
+	Style KeyStyle // "" for keys without accidentals written in English, which the symbols style writes alike
+	start int
+	end   int
+}
+
+// FindKeys returns the key signatures written in title, in order. A lowercase
+// English note only counts after "in" ("Sonata in c minor"), so that "a minor
+// role" is not read as a key.
+func FindKeys(title string) []KeyNotation {
+	var keys []KeyNotation
+	for _, m := range keyPattern.FindAllStringSubmatchIndex(title, -1) {
+		group := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return title[m[2*i]:m[2*i+1]]
+		}
+		n := KeyNotation{Text: title[m[0]:m[1]], start: m[0], end: m[1]}
+		if note := group(5); note != "" {
+			key, ok := parseGermanNote(note)
+			if !ok {
+				continue
+			}
+			key.Minor = strings.EqualFold(group(6), "moll")
+			n.Key, n.Style = key, KeyStyleGerman
+			keys = append(keys, n)
+			continue
+		}
+
+		note := group(1)
+		if unicode.IsLower(rune(note[0])) && !strings.HasSuffix(strings.ToLower(title[:m[0]]), "in ") {
+			continue
+		}
+		n.Key = Key{Note: strings.ToUpper(note)[0], Minor: strings.EqualFold(group(4), "minor")}
+		switch accidental := strings.ToLower(group(2) + group(3)); accidental {
+		case "sharp":
+			n.Key.Accidental, n.Style = 1, KeyStyleEnglish
+		case "flat":
+			n.Key.Accidental, n.Style = -1, KeyStyleEnglish
+		case "#", "♯":
+			n.Key.Accidental, n.Style = 1, KeyStyleSymbols
+		case "b", "♭":
+			n.Key.Accidental, n.Style = -1, KeyStyleSymbols
+		}
+		keys = append(keys, n)
+	}
+	return keys
+}
+
+// numberPattern matches work numbers: "No. 3", "no.3", "Nr. 3", "No 3", "Nº 3".
+// Submatches: 1 the abbreviation as written, 2 the number.
+var numberPattern = regexp.MustCompile(`\b((?:No|NO|no|Nr|NR|nr|N[º°])\.?)\s*(\d+)`)
+
+// NumberNotation is a work number as written in a title.
+type NumberNotation struct {
+	Abbreviation string // As written, e.g. "Nr." or "no"
+	Number       string
+	Text         string
+	start        int
+	end          int
+}
+
+// FindNumbers returns the work numbers written in title, in order.
+func FindNumbers(title string) []NumberNotation {
+	var numbers []NumberNotation
+	for _, m := range numberPattern.FindAllStringSubmatchIndex(title, -1) {
+		numbers = append(numbers, NumberNotation{
+			Abbreviation: title[m[2]:m[3]],
+			Number:       title[m[4]:m[5]],
+			Text:         title[m[0]:m[1]],
+			start:        m[0],
+			end:          m[1],
+		})
+	}
+	return numbers
+}
+
+// Apply rewrites the key signatures and work numbers in title in the style.
+func (s TitleStyle) Apply(title string) string {
+	type replacement struct {
+		start, end int
+		text       string
+	}
+	var replacements []replacement
+	if s.Keys != "" {
+		for _, k := range FindKeys(title) {
+			replacements = append(replacements, replacement{k.start, k.end, k.Key.Format(s.Keys)})
+		}
+	}
+	if s.Numbering != "" {
+		for _, n := range FindNumbers(title) {
+			replacements = append(replacements, replacement{n.start, n.end, string(s.Numbering) + " " + n.Number})
+		}
+	}
+	if len(replacements) == 0 {
+		return title
+	}
+	sort.Slice(replacements, func(i, j int) bool { return replacements[i].start < replacements[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, r := range replacements {
+		if r.start < last {
+			continue // Overlapping match; keep the first
+		}
+		b.WriteString(title[last:r.start])
+		b.WriteString(r.text)
+		last = r.end
+	}
+	b.WriteString(title[last:])
+	return b.String()
+}
+
+// NormalizeTitles rewrites the key signatures and work numbers in the album
+// and track titles in style. Returns a description of each change.
+func (t *Torrent) NormalizeTitles(style TitleStyle) []string {
+	if style.IsZero() {
+		return nil
+	}
+	changed := make(map[string]string)
+	normalize := func(title *string) {
+		if to := style.Apply(*title); to != *title {
+			changed[*title] = to
+			*title = to
+		}
+	}
+	normalize(&t.Title)
+	for _, track := range t.Tracks() {
+		normalize(&track.Title)
+	}
+
+	notes := make([]string, 0, len(changed))
+	for from, to := range changed {
+		notes = append(notes, fmt.Sprintf("%q -> %q", from, to))
+	}
+	sort.Strings(notes)
+	return notes
+}
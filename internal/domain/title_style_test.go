@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseTitleStyle(t *testing.T) {
+	tests := []struct {
+		keys, numbering string
+		want            TitleStyle
+		wantErr         bool
+	}{
+		{"", "", TitleStyle{}, false},
+		{"English", "No.", TitleStyle{Keys: KeyStyleEnglish, Numbering: "No."}, false},
+		{"german", "Nr.", TitleStyle{Keys: KeyStyleGerman, Numbering: "Nr."}, false},
+		{"solfege", "", TitleStyle{}, true},
+		{"", "#", TitleStyle{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTitleStyle(tt.keys, tt.numbering)
+		if tt.wantErr {
+			if !errors.Is(err, ErrUnknownTitleStyle) {
+				t.Errorf("ParseTitleStyle(%q, %q) error = %v, want ErrUnknownTitleStyle", tt.keys, tt.numbering, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseTitleStyle(%q, %q) = %+v, %v; want %+v", tt.keys, tt.numbering, got, err, tt.want)
+		}
+	}
+}
+
+func TestFindKeys(t *testing.T) {
+	tests := []struct {
+		title string
+		want  []Key
+		style []KeyStyle
+	}{
+		{"Sonata in C-sharp minor", []Key{{'C', 1, true}}, []KeyStyle{KeyStyleEnglish}},
+		{"Sonata in c sharp minor", []Key{{'C', 1, true}}, []KeyStyle{KeyStyleEnglish}},
+		{"Concerto in Bb Major", []Key{{'B', -1, false}}, []KeyStyle{KeyStyleSymbols}},
+		{"Prelude in F♯ minor", []Key{{'F', 1, true}}, []KeyStyle{KeyStyleSymbols}},
+		{"Symphony in D major", []Key{{'D', 0, false}}, []KeyStyle{""}},
+		{"Messe h-Moll", []Key{{'B', 0, true}}, []KeyStyle{KeyStyleGerman}},
+		{"Sinfonie B-Dur", []Key{{'B', -1, false}}, []KeyStyle{KeyStyleGerman}},
+		{"Es-Dur und as-Moll", []Key{{'E', -1, false}, {'A', -1, true}}, []KeyStyle{KeyStyleGerman, KeyStyleGerman}},
+		{"Fantasie Cis-Dur", []Key{{'C', 1, false}}, []KeyStyle{KeyStyleGerman}},
+		{"Aria for a minor role", nil, nil},
+		{"Ees-Dur", nil, nil},
+	}
+	for _, tt := range tests {
+		got := FindKeys(tt.title)
+		var keys []Key
+		var styles []KeyStyle
+		for _, k := range got {
+			keys = append(keys, k.Key)
+			styles = append(styles, k.Style)
+		}
+		if !slices.Equal(keys, tt.want) || !slices.Equal(styles, tt.style) {
+			t.Errorf("FindKeys(%q) = %v %v, want %v %v", tt.title, keys, styles, tt.want, tt.style)
+		}
+	}
+}
+
+func TestKey_Format(t *testing.T) {
+	tests := []struct {
+		key                      Key
+		english, symbols, german string
+	}{
+		{Key{'C', 1, true}, "C-sharp minor", "C# minor", "cis-Moll"},
+		{Key{'B', -1, false}, "B-flat major", "Bb major", "B-Dur"},
+		{Key{'B', 0, true}, "B minor", "B minor", "h-Moll"},
+		{Key{'E', -1, false}, "E-flat major", "Eb major", "Es-Dur"},
+		{Key{'A', -1, true}, "A-flat minor", "Ab minor", "as-Moll"},
+		{Key{'G', -1, false}, "G-flat major", "Gb major", "Ges-Dur"},
+		{Key{'D', 0, false}, "D major", "D major", "D-Dur"},
+	}
+	for _, tt := range tests {
+		for style, want := range map[KeyStyle]string{KeyStyleEnglish: tt.english, KeyStyleSymbols: tt.symbols, KeyStyleGerman: tt.german} {
+			if got := tt.key.Format(style); got != want {
+				t.Errorf("%+v.Format(%s) = %q, want %q", tt.key, style, got, want)
+			}
+		}
+	}
+}
+
+func TestFindNumbers(t *testing.T) {
+	var got []string
+	for _, n := range FindNumbers("Op. 27 No. 1, no.2, Nr 3, Nº 4, Nos. 5-6, Nocturne 7") {
+		got = append(got, n.Abbreviation+"|"+n.Number)
+	}
+	want := []string{"No.|1", "no.|2", "Nr|3", "Nº|4"}
+	if !slices.Equal(got, want) {
+		t.Errorf("FindNumbers() = %q, want %q", got, want)
+	}
+}
+
+func TestTitleStyle_Apply(t *testing.T) {
+	tests := []struct {
+		style TitleStyle
+		title string
+		want  string
+	}{
+		{TitleStyle{Keys: KeyStyleEnglish, Numbering: "No."}, "Sonata Nr.14 in cis-Moll, Op. 27 no. 2", "Sonata No. 14 in C-sharp minor, Op. 27 No. 2"},
+		{TitleStyle{Keys: KeyStyleSymbols}, "Concerto in B-flat Major", "Concerto in Bb major"},
+		{TitleStyle{Keys: KeyStyleGerman, Numbering: "Nr."}, "Symphony No. 9 in D minor", "Symphony Nr. 9 in d-Moll"},
+		{TitleStyle{Numbering: "No."}, "Sonata in c-sharp minor", "Sonata in c-sharp minor"},
+		{TitleStyle{}, "Sonata Nr. 1 in cis-Moll", "Sonata Nr. 1 in cis-Moll"},
+	}
+	for _, tt := range tests {
+		if got := tt.style.Apply(tt.title); got != tt.want {
+			t.Errorf("%+v.Apply(%q) = %q, want %q", tt.style, tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestTorrent_NormalizeTitles(t *testing.T) {
+	torrent := &Torrent{
+		Title: "Klaviersonaten Nr. 8 & 14",
+		Files: []FileLike{
+			&Track{File: File{Path: "01.flac"}, Disc: 1, Track: 1, Title: "Sonata No. 8 in C minor"},
+			&Track{File: File{Path: "02.flac"}, Disc: 1, Track: 2, Title: "Sonata Nr. 14 in cis-Moll"},
+		},
+	}
+	notes := torrent.NormalizeTitles(TitleStyle{Keys: KeyStyleEnglish, Numbering: "No."})
+
+	want := []string{
+		`"Klaviersonaten Nr. 8 & 14" -> "Klaviersonaten No. 8 & 14"`,
+		`"Sonata Nr. 14 in cis-Moll" -> "Sonata No. 14 in C-sharp minor"`,
+	}
+	if !slices.Equal(notes, want) {
+		t.Errorf("NormalizeTitles() = %q, want %q", notes, want)
+	}
+	if got := torrent.Tracks()[1].Title; got != "Sonata No. 14 in C-sharp minor" {
+		t.Errorf("track 2 title = %q", got)
+	}
+	if notes := torrent.NormalizeTitles(TitleStyle{}); notes != nil {
+		t.Errorf("NormalizeTitles(zero style) = %q, want nothing", notes)
+	}
+}
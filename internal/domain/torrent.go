@@ -4,8 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 )
 
+// SchemaVersion is the version of the metadata JSON written by MarshalJSON. Bump it
+// whenever a model change needs older files upgraded, and add the matching
+// migration to internal/storage.
+const SchemaVersion = 1
+
 // Torrent represents a torrent directory with associated metadata and files.
 // It is the aggregate root of the domain model.
 type Torrent struct {
@@ -13,14 +20,30 @@ type Torrent struct {
 	RootPath string `json:"root_path"` // Relative path to torrent directory
 
 	// Album-level metadata
-	Title        string   `json:"title"`
-	OriginalYear int      `json:"original_year"`
-	Edition      *Edition `json:"edition,omitempty"`
-	AlbumArtist  []Artist `json:"album_artist,omitempty"`
+	Title           string   `json:"title"`
+	AlternateTitles []string `json:"alternate_titles,omitempty"` // Other-language variants of Title
+	OriginalYear    int      `json:"original_year"`              // Year of the original release
+	RecordingYears  []int    `json:"recording_years,omitempty"`  // Years the recording sessions took place
+	Edition         *Edition `json:"edition,omitempty"`
+	AlbumArtist     []Artist `json:"album_artist,omitempty"`
+
+	// Set in a disc metadata file, which covers only this disc of a set: Title is
+	// then the disc's subtitle and Edition the disc's own release (see AssembleDiscs)
+	Disc int `json:"disc,omitempty"`
+
+	// Crossover or recital disc whose tracks may lack a composer: validation reports
+	// missing composers as warnings instead of errors
+	AllowMissingComposer bool `json:"allow_missing_composer,omitempty"`
 
 	// All files in the torrent (mix of File and Track)
 	Files []FileLike `json:"files"`
 
+	// Where the metadata came from (e.g. Discogs release URLs), cited in reports
+	Sources []string `json:"sources,omitempty"`
+
+	// Cover images fetched into the album directory, credited in reports
+	Artwork []ArtworkCredit `json:"artwork,omitempty"`
+
 	// Site-specific metadata (optional, for upload)
 	SiteMetadata *SiteMetadata `json:"site_metadata,omitempty"`
 }
@@ -30,13 +53,20 @@ type Torrent struct {
 // marshaled as their concrete types (File or Track).
 func (t *Torrent) MarshalJSON() ([]byte, error) {
 	type torrentJSON struct {
-		RootPath     string        `json:"root_path"`
-		Title        string        `json:"title"`
-		OriginalYear int           `json:"original_year"`
-		Edition      *Edition      `json:"edition,omitempty"`
-		AlbumArtist  []Artist      `json:"album_artist,omitempty"`
-		Files        any           `json:"files"`
-		SiteMetadata *SiteMetadata `json:"site_metadata,omitempty"`
+		SchemaVersion   int             `json:"schema_version"`
+		RootPath        string          `json:"root_path"`
+		Title           string          `json:"title"`
+		AlternateTitles []string        `json:"alternate_titles,omitempty"`
+		OriginalYear    int             `json:"original_year"`
+		RecordingYears  []int           `json:"recording_years,omitempty"`
+		Edition         *Edition        `json:"edition,omitempty"`
+		AlbumArtist     []Artist        `json:"album_artist,omitempty"`
+		Disc            int             `json:"disc,omitempty"`
+		AllowMissing    bool            `json:"allow_missing_composer,omitempty"`
+		Files           any             `json:"files"`
+		Sources         []string        `json:"sources,omitempty"`
+		Artwork         []ArtworkCredit `json:"artwork,omitempty"`
+		SiteMetadata    *SiteMetadata   `json:"site_metadata,omitempty"`
 	}
 
 	// Marshal Files array by converting each FileLike to its concrete type
@@ -56,13 +86,20 @@ func (t *Torrent) MarshalJSON() ([]byte, error) {
 	}
 
 	tj := torrentJSON{
-		RootPath:     t.RootPath,
-		Title:        t.Title,
-		OriginalYear: t.OriginalYear,
-		Edition:      t.Edition,
-		AlbumArtist:  t.AlbumArtist,
-		Files:        filesData,
-		SiteMetadata: t.SiteMetadata,
+		SchemaVersion:   SchemaVersion,
+		RootPath:        t.RootPath,
+		Title:           t.Title,
+		AlternateTitles: t.AlternateTitles,
+		OriginalYear:    t.OriginalYear,
+		RecordingYears:  t.RecordingYears,
+		Edition:         t.Edition,
+		AlbumArtist:     t.AlbumArtist,
+		Disc:            t.Disc,
+		AllowMissing:    t.AllowMissingComposer,
+		Files:           filesData,
+		Sources:         t.Sources,
+		Artwork:         t.Artwork,
+		SiteMetadata:    t.SiteMetadata,
 	}
 
 	return json.Marshal(tj)
@@ -74,13 +111,19 @@ func (t *Torrent) MarshalJSON() ([]byte, error) {
 func (t *Torrent) UnmarshalJSON(data []byte) error {
 	// Use an intermediate struct with Files as raw JSON
 	type torrentJSON struct {
-		RootPath     string          `json:"root_path"`
-		Title        string          `json:"title"`
-		OriginalYear int             `json:"original_year"`
-		Edition      *Edition        `json:"edition,omitempty"`
-		AlbumArtist  []Artist        `json:"album_artist,omitempty"`
-		Files        json.RawMessage `json:"files"`
-		SiteMetadata *SiteMetadata   `json:"site_metadata,omitempty"`
+		RootPath        string          `json:"root_path"`
+		Title           string          `json:"title"`
+		AlternateTitles []string        `json:"alternate_titles,omitempty"`
+		OriginalYear    int             `json:"original_year"`
+		RecordingYears  []int           `json:"recording_years,omitempty"`
+		Edition         *Edition        `json:"edition,omitempty"`
+		AlbumArtist     []Artist        `json:"album_artist,omitempty"`
+		Disc            int             `json:"disc,omitempty"`
+		AllowMissing    bool            `json:"allow_missing_composer,omitempty"`
+		Files           json.RawMessage `json:"files"`
+		Sources         []string        `json:"sources,omitempty"`
+		Artwork         []ArtworkCredit `json:"artwork,omitempty"`
+		SiteMetadata    *SiteMetadata   `json:"site_metadata,omitempty"`
 	}
 
 	var tmp torrentJSON
@@ -91,9 +134,15 @@ func (t *Torrent) UnmarshalJSON(data []byte) error {
 	// Copy simple fields
 	t.RootPath = tmp.RootPath
 	t.Title = tmp.Title
+	t.AlternateTitles = tmp.AlternateTitles
 	t.OriginalYear = tmp.OriginalYear
+	t.RecordingYears = tmp.RecordingYears
 	t.Edition = tmp.Edition
 	t.AlbumArtist = tmp.AlbumArtist
+	t.Disc = tmp.Disc
+	t.AllowMissingComposer = tmp.AllowMissing
+	t.Sources = tmp.Sources
+	t.Artwork = tmp.Artwork
 	t.SiteMetadata = tmp.SiteMetadata
 
 	// Unmarshal Files array (Files field may be missing or null)
@@ -144,6 +193,53 @@ func (t *Torrent) Save(filename string) error {
 	return encoder.Encode(t)
 }
 
+// TitleVariants returns Title followed by its alternate-language variants.
+func (t Torrent) TitleVariants() []string {
+	variants := make([]string, 0, 1+len(t.AlternateTitles))
+	if t.Title != "" {
+		variants = append(variants, t.Title)
+	}
+	for _, alt := range t.AlternateTitles {
+		if alt != "" && !slices.Contains(variants, alt) {
+			variants = append(variants, alt)
+		}
+	}
+	return variants
+}
+
+// WithTitle returns a copy of the torrent whose Title is the given variant, so that
+// directory names and tags can each be driven by a different language variant.
+// The variant must match Title or one of AlternateTitles (case-insensitive);
+// an empty variant returns an unchanged copy.
+func (t Torrent) WithTitle(variant string) (*Torrent, error) {
+	if variant == "" {
+		return &t, nil
+	}
+	for _, v := range t.TitleVariants() {
+		if strings.EqualFold(v, variant) {
+			t.Title = v
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q (available: %s)", ErrUnknownTitleVariant, variant, strings.Join(t.TitleVariants(), " | "))
+}
+
+// SplitTitleVariants splits a multi-language title in Discogs notation
+// ("Noël! = Christmas! = Weihnachten!") into the primary title and its alternates.
+func SplitTitleVariants(title string) (string, []string) {
+	parts := strings.Split(title, " = ")
+	var variants []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			variants = append(variants, p)
+		}
+	}
+	if len(variants) < 2 {
+		return strings.TrimSpace(title), nil
+	}
+	return variants[0], variants[1:]
+}
+
 // IsMultiDisc returns true if the torrent contains tracks from multiple discs.
 // A torrent is considered multi-disc if any track has Disc > 1 or if there are multiple distinct disc numbers.
 func (t *Torrent) IsMultiDisc() bool {
@@ -181,29 +277,35 @@ func (t *Torrent) Tracks() []*Track {
 // Returns the list of universal artists.
 // Per classical music guide: "When the performer(s) do not remain the same throughout
 // all tracks, this tag is used to credit the one who does appear in all tracks."
+// Artists are matched by name and role, so a soloist who also conducts keeps both credits.
 func (torrent Torrent) AlbumArtists() []Artist {
-	artistCounts := make(map[string]int)
+	type credit struct {
+		name string
+		role Role
+	}
+	artistCounts := make(map[credit]int)
 	artistOrder := make([]Artist, 0)
 	for _, track := range torrent.Tracks() {
-		trackArtists := make(map[string]struct{})
+		trackArtists := make(map[credit]struct{})
 		for _, artist := range track.Artists {
 			if !artist.Role.IsPerformer() {
 				continue
 			}
-			if _, ok := trackArtists[artist.Name]; ok {
+			key := credit{artist.Name, artist.Role}
+			if _, ok := trackArtists[key]; ok {
 				continue
 			}
-			trackArtists[artist.Name] = struct{}{}
-			if artistCounts[artist.Name] == 0 {
+			trackArtists[key] = struct{}{}
+			if artistCounts[key] == 0 {
 				artistOrder = append(artistOrder, artist)
 			}
-			artistCounts[artist.Name]++
+			artistCounts[key]++
 		}
 	}
 
 	var albumArtists []Artist
 	for _, artist := range artistOrder {
-		if artistCounts[artist.Name] == len(torrent.Tracks()) {
+		if artistCounts[credit{artist.Name, artist.Role}] == len(torrent.Tracks()) {
 			albumArtists = append(albumArtists, artist)
 		}
 	}
@@ -240,11 +342,7 @@ func (torrent Torrent) DirectoryName() string {
 
 	// Get primary composer(s) - prefer AlbumArtist, fall back to tracks only if AlbumArtist is empty
 	// If AlbumArtist is set but has no composers, skip composer prefix (for Discogs releases with only performers)
-	composers := torrent.Composers()
-	if len(composers) == 0 && len(torrent.AlbumArtist) == 0 {
-		// Only fall back to tracks if AlbumArtist is completely empty (local extraction)
-		composers = torrent.PrimaryComposers()
-	}
+	composers := torrent.directoryComposers()
 	composerStr := ""
 	if len(composers) > 0 {
 		composerStr = formatComposersForDirectory(composers) + " - "
@@ -270,6 +368,38 @@ func (torrent Torrent) DirectoryName() string {
 	return dirName + performerStr + yearStr + formatIndicator
 }
 
+// directoryComposers returns the composers named in the directory.
+// Prefers AlbumArtist; falls back to tracks only if AlbumArtist is completely empty (local extraction).
+// If AlbumArtist is set but has no composers, returns none (for Discogs releases with only performers).
+func (t Torrent) directoryComposers() []Artist {
+	var composers []Artist
+	for _, artist := range t.AlbumArtist {
+		if artist.Role == RoleComposer && artist.Name != "" {
+			composers = append(composers, artist)
+		}
+	}
+	if len(composers) > 0 || len(t.AlbumArtist) > 0 {
+		return composers
+	}
+	for _, name := range t.PrimaryComposers() {
+		composers = append(composers, t.trackArtist(name, RoleComposer))
+	}
+	return composers
+}
+
+// trackArtist returns the first track artist with the given name and role,
+// preserving its SortName, or a bare Artist if none is found.
+func (t Torrent) trackArtist(name string, role Role) Artist {
+	for _, track := range t.Tracks() {
+		for _, artist := range track.Artists {
+			if artist.Name == name && artist.Role == role {
+				return artist
+			}
+		}
+	}
+	return Artist{Name: name, Role: role}
+}
+
 // Composers extracts composer names from AlbumArtist.
 func (t Torrent) Composers() []string {
 	var composers []string
@@ -281,11 +411,12 @@ func (t Torrent) Composers() []string {
 	return composers
 }
 
-// Performers extracts performer names (non-composers) from AlbumArtist.
+// Performers extracts performer names (non-composers) from AlbumArtist, each
+// once: a soloist who also conducts is named once.
 func (t Torrent) Performers() []string {
 	var performers []string
 	for _, artist := range t.AlbumArtist {
-		if artist.Role != RoleComposer && artist.Name != "" {
+		if artist.Role != RoleComposer && artist.Name != "" && !slices.Contains(performers, artist.Name) {
 			performers = append(performers, artist.Name)
 		}
 	}
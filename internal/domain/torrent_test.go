@@ -2,8 +2,10 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -170,6 +172,31 @@ func TestTorrent_AlbumArtists(t *testing.T) {
 			},
 			want: []Artist{{Name: "Johann Sebastian Bach", Role: RoleSoloist}},
 		},
+		{
+			name: "soloist who also conducts keeps both roles",
+			torrent: &Torrent{
+				Files: []FileLike{
+					&Track{
+						Track: 1,
+						Artists: []Artist{
+							{Name: "Murray Perahia", Role: RoleSoloist},
+							{Name: "Murray Perahia", Role: RoleConductor},
+						},
+					},
+					&Track{
+						Track: 2,
+						Artists: []Artist{
+							{Name: "Murray Perahia", Role: RoleSoloist},
+							{Name: "Murray Perahia", Role: RoleConductor},
+						},
+					},
+				},
+			},
+			want: []Artist{
+				{Name: "Murray Perahia", Role: RoleSoloist},
+				{Name: "Murray Perahia", Role: RoleConductor},
+			},
+		},
 		{
 			name: "not all tracks have same performer",
 			torrent: &Torrent{
@@ -411,6 +438,17 @@ func TestTorrent_Performers(t *testing.T) {
 			},
 			want: []string{},
 		},
+		{
+			name: "soloist who also conducts is named once",
+			torrent: &Torrent{
+				AlbumArtist: []Artist{
+					{Name: "Murray Perahia", Role: RoleSoloist},
+					{Name: "Academy of St Martin in the Fields", Role: RoleEnsemble},
+					{Name: "Murray Perahia", Role: RoleConductor},
+				},
+			},
+			want: []string{"Murray Perahia", "Academy of St Martin in the Fields"},
+		},
 		{
 			name: "arranger is included as performer",
 			torrent: &Torrent{
@@ -986,3 +1024,103 @@ func TestTorrent_Save(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitTitleVariants(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		wantTitle string
+		wantAlts  []string
+	}{
+		{"single title", "Goldberg Variations", "Goldberg Variations", nil},
+		{"three languages", "Noël! = Christmas! = Weihnachten!", "Noël!", []string{"Christmas!", "Weihnachten!"}},
+		{"equals without spaces kept", "E=mc2", "E=mc2", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, alts := SplitTitleVariants(tt.title)
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			if !reflect.DeepEqual(alts, tt.wantAlts) {
+				t.Errorf("alternates = %v, want %v", alts, tt.wantAlts)
+			}
+		})
+	}
+}
+
+func TestTorrent_WithTitle(t *testing.T) {
+	torrent := Torrent{Title: "Noël!", AlternateTitles: []string{"Christmas!", "Weihnachten!"}}
+
+	variant, err := torrent.WithTitle("weihnachten!")
+	if err != nil {
+		t.Fatalf("WithTitle() error = %v", err)
+	}
+	if variant.Title != "Weihnachten!" {
+		t.Errorf("Title = %q, want %q", variant.Title, "Weihnachten!")
+	}
+	if torrent.Title != "Noël!" {
+		t.Errorf("original torrent modified: %q", torrent.Title)
+	}
+
+	unchanged, err := torrent.WithTitle("")
+	if err != nil || unchanged.Title != "Noël!" {
+		t.Errorf("WithTitle(\"\") = %v, %v", unchanged, err)
+	}
+
+	if _, err := torrent.WithTitle("Navidad!"); !errors.Is(err, ErrUnknownTitleVariant) {
+		t.Errorf("expected ErrUnknownTitleVariant, got %v", err)
+	}
+}
+
+func TestTorrent_AlternateTitlesJSON(t *testing.T) {
+	torrent := &Torrent{Title: "Noël!", AlternateTitles: []string{"Christmas!"}}
+	data, err := json.Marshal(torrent)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	var decoded Torrent
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.AlternateTitles, torrent.AlternateTitles) {
+		t.Errorf("AlternateTitles = %v, want %v", decoded.AlternateTitles, torrent.AlternateTitles)
+	}
+}
+
+func TestTorrent_SourcesAndComposerModeJSON(t *testing.T) {
+	torrent := &Torrent{Title: "Play Bach", AllowMissingComposer: true, Sources: []string{"https://www.discogs.com/release/1"}}
+	data, err := json.Marshal(torrent)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	var decoded Torrent
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !decoded.AllowMissingComposer || !reflect.DeepEqual(decoded.Sources, torrent.Sources) {
+		t.Errorf("decoded = %+v, want AllowMissingComposer and Sources kept", decoded)
+	}
+}
+
+func TestTorrent_ArtworkJSON(t *testing.T) {
+	torrent := &Torrent{Title: "Play Bach", Artwork: []ArtworkCredit{{
+		File: "cover.jpg", Side: "front", Source: "Cover Art Archive",
+		URL: "https://coverartarchive.org/release/abc/1-1200.jpg", Page: "https://musicbrainz.org/release/abc/cover-art",
+	}}}
+	data, err := json.Marshal(torrent)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+
+	var decoded Torrent
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.Artwork, torrent.Artwork) {
+		t.Errorf("decoded Artwork = %+v, want %+v", decoded.Artwork, torrent.Artwork)
+	}
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // Track represents a single track/movement.
 // Track embeds File, so it IS a File and can be stored in Files []*File.
 // All fields are exported and mutable.
@@ -11,6 +13,23 @@ type Track struct {
 	Track   int      `json:"track"`
 	Title   string   `json:"title"`
 	Artists []Artist `json:"artists"`
+	Hidden  bool     `json:"hidden,omitempty"` // Hidden audio in the pregap before track 1 (HTOA), numbered 0
+
+	DiscSubtitle string        `json:"disc_subtitle,omitempty"` // DISCSUBTITLE, e.g. "Act II"
+	Channels     int           `json:"channels,omitempty"`      // From STREAMINFO; 0 if unknown
+	Duration     time.Duration `json:"duration,omitempty"`      // From STREAMINFO, in nanoseconds; 0 if unknown
+
+	// Optional dates distinct from the album's release year
+	CompositionYear int   `json:"composition_year,omitempty"`
+	RecordingYears  []int `json:"recording_years,omitempty"` // Overrides the album's recording years
+
+	// Release of the disc the track is on, when a set gathers discs from different releases
+	Edition *Edition `json:"edition,omitempty"` // Overrides the album's edition
+
+	MusicBrainzTrackID string `json:"musicbrainz_track_id,omitempty"` // MUSICBRAINZ_TRACKID, when known
+
+	// Junk tags the tag command stripped from the file, as "KEY=value (rule)"
+	RemovedTags []string `json:"removed_tags,omitempty"`
 }
 
 // Composers returns all the composer artists.
@@ -32,10 +51,9 @@ func (t *Track) Performers() []string {
 			performers = append(performers, artist.Name)
 		}
 	}
-	return performers	
+	return performers
 }
 
-
 // Composer extracts composer name from artist list
 func (t *Track) Composer() string {
 	for _, artist := range t.Artists {
@@ -45,3 +63,13 @@ func (t *Track) Composer() string {
 	}
 	return ""
 }
+
+// HiddenTrackTitle is the title given to untitled hidden tracks.
+const HiddenTrackTitle = "[Hidden Track]"
+
+// IsHidden reports whether the track is hidden audio in the pregap before track 1
+// (HTOA). Hidden tracks are numbered 0, but a track 0 is not hidden unless found
+// to be: it may just be missing its number.
+func (t *Track) IsHidden() bool {
+	return t.Hidden
+}
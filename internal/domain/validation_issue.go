@@ -8,14 +8,37 @@ type ValidationIssue struct {
 	Level    Level  `json:"level"`             // Severity level (ERROR, WARNING, INFO)
 	Required bool   `json:"required"`          // Whether the issue is required (e.g., for extraction)
 	Track    int    `json:"track"`             // 0 for album-level, -1 for directory-level, >0 for track number
+	Disc     int    `json:"disc,omitempty"`    // Disc of the track on multi-disc albums, otherwise 0
+	Path     string `json:"path,omitempty"`    // File or folder the issue is about, relative to the album root
 	Rule     string `json:"rule"`              // Section number from rules (e.g., "2.3.16.4")
 	Message  string `json:"message,omitempty"` // Context-specific message
 }
 
+// Messages of the album-level issues that report a missing year.
+const (
+	MessageYearTagMissing = "Year tag is missing (strongly recommended)"
+	MessageYearMissing    = "Year is missing (should include recording/original year)"
+)
+
+// IsMissingYear reports whether the issue is about the album having no year.
+func (v ValidationIssue) IsMissingYear() bool {
+	return v.Track == 0 && (v.Message == MessageYearTagMissing || v.Message == MessageYearMissing)
+}
+
+// ForTrack returns the issue located at track: its disc, number and file path.
+func (v ValidationIssue) ForTrack(track *Track) ValidationIssue {
+	v.Track = track.Track
+	v.Disc = track.Disc
+	v.Path = track.File.Path
+	return v
+}
+
 // String returns a formatted string representation of the issue.
 func (v ValidationIssue) String() string {
 	var location string
 	switch {
+	case v.Track > 0 && v.Disc > 0:
+		location = fmt.Sprintf("Disc %d Track %d", v.Disc, v.Track)
 	case v.Track > 0:
 		location = fmt.Sprintf("Track %d", v.Track)
 	case v.Track == 0:
@@ -23,6 +46,9 @@ func (v ValidationIssue) String() string {
 	case v.Track == -1:
 		location = "Directory"
 	}
+	if v.Path != "" {
+		location += " (" + v.Path + ")"
+	}
 
 	return fmt.Sprintf("[%s] %s: %s - %s", v.Level, location, v.Rule, v.Message)
 }
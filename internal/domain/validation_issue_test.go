@@ -49,6 +49,20 @@ func TestValidationIssue_String(t *testing.T) {
 				"Missing required tag 'Title'",
 			},
 		},
+		{
+			Name: "located track error",
+			Issue: ValidationIssue{
+				Level:   LevelError,
+				Track:   12,
+				Disc:    3,
+				Path:    "CD3/12 - Ave Maria.flac",
+				Rule:    "2.3.16.4",
+				Message: "Missing required tag 'Artist'",
+			},
+			WantStr: []string{
+				"Disc 3 Track 12 (CD3/12 - Ave Maria.flac): 2.3.16.4",
+			},
+		},
 		{
 			Name: "album-level warning",
 			Issue: ValidationIssue{
@@ -127,3 +141,11 @@ func TestValidationIssue_JSONSerialization(t *testing.T) {
 		t.Errorf("Message after round-trip = %v, want %v", decoded.Message, issue.Message)
 	}
 }
+
+func TestValidationIssue_ForTrack(t *testing.T) {
+	track := &Track{File: File{Path: "CD2/03.flac"}, Disc: 2, Track: 3}
+	issue := ValidationIssue{Level: LevelError, Rule: "2.3.16.4"}.ForTrack(track)
+	if issue.Track != 3 || issue.Disc != 2 || issue.Path != "CD2/03.flac" {
+		t.Errorf("ForTrack() = %+v, want disc 2 track 3 at CD2/03.flac", issue)
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "fmt"
+
+// ValidationProfile decides which validation issues block a command.
+type ValidationProfile string
+
+const (
+	// ValidationDefault blocks on errors
+	ValidationDefault ValidationProfile = "default"
+	// ValidationStrict blocks on errors and warnings
+	ValidationStrict ValidationProfile = "strict"
+	// ValidationLenient reports issues without blocking
+	ValidationLenient ValidationProfile = "lenient"
+)
+
+// ParseValidationProfile parses "default", "strict" or "lenient"; "" means default.
+func ParseValidationProfile(s string) (ValidationProfile, error) {
+	switch p := ValidationProfile(s); p {
+	case "":
+		return ValidationDefault, nil
+	case ValidationDefault, ValidationStrict, ValidationLenient:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %q (want default, strict or lenient)", ErrUnknownValidationProfile, s)
+	}
+}
+
+// Blocks reports whether the profile treats an issue of the given level as blocking.
+func (p ValidationProfile) Blocks(level Level) bool {
+	switch p {
+	case ValidationLenient:
+		return false
+	case ValidationStrict:
+		return level == LevelError || level == LevelWarning
+	default:
+		return level == LevelError
+	}
+}
+
+// Blocking returns the issues the profile treats as blocking.
+func (p ValidationProfile) Blocking(issues []ValidationIssue) []ValidationIssue {
+	var blocking []ValidationIssue
+	for _, issue := range issues {
+		if p.Blocks(issue.Level) {
+			blocking = append(blocking, issue)
+		}
+	}
+	return blocking
+}
@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseValidationProfile(t *testing.T) {
+	for in, want := range map[string]ValidationProfile{"": ValidationDefault, "strict": ValidationStrict, "lenient": ValidationLenient} {
+		if got, err := ParseValidationProfile(in); err != nil || got != want {
+			t.Errorf("ParseValidationProfile(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseValidationProfile("paranoid"); !errors.Is(err, ErrUnknownValidationProfile) {
+		t.Errorf("ParseValidationProfile(paranoid) error = %v, want ErrUnknownValidationProfile", err)
+	}
+}
+
+func TestValidationProfile_Blocking(t *testing.T) {
+	issues := []ValidationIssue{
+		{Level: LevelError, Rule: "2.3.1"},
+		{Level: LevelWarning, Rule: "2.3.2"},
+		{Level: LevelInfo, Rule: "2.3.3"},
+	}
+	tests := []struct {
+		Profile ValidationProfile
+		Want    int
+	}{
+		{ValidationDefault, 1},
+		{ValidationStrict, 2},
+		{ValidationLenient, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.Profile.Blocking(issues); len(got) != tt.Want {
+			t.Errorf("%s.Blocking() = %v, want %d issues", tt.Profile, got, tt.Want)
+		}
+	}
+}
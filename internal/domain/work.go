@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Work is a composition and the consecutive tracks, its movements, that perform it.
+type Work struct {
+	Title    string // "Symphony No. 5 in C minor, Op. 67"; the track title for a single-track work
+	Composer string
+	Tracks   []*Track
+}
+
+// SplitWorkTitle splits a track title into the work and the movement at the
+// first ": " ("Symphony No. 5 in C minor, Op. 67: I. Allegro con brio"). A
+// title without one is the whole work, with no movement.
+func SplitWorkTitle(title string) (work, movement string) {
+	if work, movement, ok := strings.Cut(title, ": "); ok && strings.TrimSpace(movement) != "" {
+		return strings.TrimSpace(work), strings.TrimSpace(movement)
+	}
+	return strings.TrimSpace(title), ""
+}
+
+// Works groups the torrent's tracks into works: runs of consecutive tracks on
+// the same disc with the same composer and work title. Works are returned in
+// track order.
+func (t *Torrent) Works() []*Work {
+	var works []*Work
+	var last *Track
+	for _, track := range t.Tracks() {
+		title, _ := SplitWorkTitle(track.Title)
+		if n := len(works); n > 0 && last.Disc == track.Disc &&
+			works[n-1].Title == title && works[n-1].Composer == track.Composer() {
+			works[n-1].Tracks = append(works[n-1].Tracks, track)
+		} else {
+			works = append(works, &Work{Title: title, Composer: track.Composer(), Tracks: []*Track{track}})
+		}
+		last = track
+	}
+	return works
+}
+
+// Movement returns the title of track within the work: the part after the work
+// title, or the whole title for a single-track work.
+func (w *Work) Movement(track *Track) string {
+	if _, movement := SplitWorkTitle(track.Title); movement != "" {
+		return movement
+	}
+	return track.Title
+}
+
+// Duration returns the total playing time of the work's tracks, or 0 when any
+// track's duration is unknown: a partial total would understate the work.
+func (w *Work) Duration() time.Duration {
+	var total time.Duration
+	for _, track := range w.Tracks {
+		if track.Duration <= 0 {
+			return 0
+		}
+		total += track.Duration
+	}
+	return total
+}
+
+// FormatDuration formats d to the nearest second as "m:ss", or "h:mm:ss" from
+// an hour up, the way tracklists show playing times.
+func FormatDuration(d time.Duration) string {
+	seconds := int((d + time.Second/2) / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
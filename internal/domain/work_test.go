@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitWorkTitle(t *testing.T) {
+	tests := []struct {
+		title, work, movement string
+	}{
+		{"Symphony No. 5 in C minor, Op. 67: I. Allegro con brio", "Symphony No. 5 in C minor, Op. 67", "I. Allegro con brio"},
+		{"Mass in B minor, BWV 232: Gloria: Domine Deus", "Mass in B minor, BWV 232", "Gloria: Domine Deus"},
+		{"Chaconne", "Chaconne", ""},
+		{"Ballade: ", "Ballade:", ""},
+	}
+	for _, tt := range tests {
+		work, movement := SplitWorkTitle(tt.title)
+		if work != tt.work || movement != tt.movement {
+			t.Errorf("SplitWorkTitle(%q) = %q, %q; want %q, %q", tt.title, work, movement, tt.work, tt.movement)
+		}
+	}
+}
+
+func TestTorrent_Works(t *testing.T) {
+	beethoven := []Artist{{Name: "Ludwig van Beethoven", Role: RoleComposer}}
+	schubert := []Artist{{Name: "Franz Schubert", Role: RoleComposer}}
+	track := func(disc, num int, title string, artists []Artist, d time.Duration) *Track {
+		return &Track{Disc: disc, Track: num, Title: title, Artists: artists, Duration: d}
+	}
+	torrent := &Torrent{Files: []FileLike{
+		track(1, 1, "Symphony No. 5 in C minor, Op. 67: I. Allegro con brio", beethoven, 7*time.Minute+20*time.Second),
+		track(1, 2, "Symphony No. 5 in C minor, Op. 67: II. Andante con moto", beethoven, 9*time.Minute+40*time.Second),
+		track(1, 3, "Symphony No. 8 in B minor, D. 759: I. Allegro moderato", schubert, 0),
+		track(1, 4, "Symphony No. 8 in B minor, D. 759: II. Andante con moto", schubert, 11*time.Minute),
+		track(1, 5, "Egmont, Op. 84: Overture", beethoven, 8*time.Minute),
+		// The same work continued on the next disc is reported per disc
+		track(2, 1, "Egmont, Op. 84: Clärchens Tod", beethoven, 3*time.Minute),
+	}}
+
+	works := torrent.Works()
+	if len(works) != 4 {
+		t.Fatalf("Works() = %d works, want 4", len(works))
+	}
+	if works[0].Title != "Symphony No. 5 in C minor, Op. 67" || len(works[0].Tracks) != 2 {
+		t.Errorf("works[0] = %q with %d tracks", works[0].Title, len(works[0].Tracks))
+	}
+	if got := works[0].Duration(); got != 17*time.Minute {
+		t.Errorf("works[0].Duration() = %v, want 17m", got)
+	}
+	if got := works[0].Movement(works[0].Tracks[1]); got != "II. Andante con moto" {
+		t.Errorf("Movement() = %q", got)
+	}
+	if got := works[1].Duration(); got != 0 {
+		t.Errorf("Duration() with an unknown movement = %v, want 0", got)
+	}
+	if works[1].Composer != "Franz Schubert" || works[3].Tracks[0].Disc != 2 {
+		t.Errorf("works = %+v, %+v", works[1], works[3])
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		4*time.Minute + 35*time.Second:            "4:35",
+		59*time.Second + 600*time.Millisecond:     "1:00",
+		time.Hour + 2*time.Minute + 3*time.Second: "1:02:03",
+		0: "0:00",
+	}
+	for d, want := range tests {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
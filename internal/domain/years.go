@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// yearPattern matches a year or a year range ("2012", "2012-2013", "1998–2001")
+var yearPattern = regexp.MustCompile(`\b(1[5-9]\d{2}|20\d{2})(?:\s*[-–]\s*(1[5-9]\d{2}|20\d{2}))?\b`)
+
+// maxYearRange caps range expansion so "1685-1750" (a composer's life) isn't read as 65 sessions
+const maxYearRange = 10
+
+// ParseYears extracts the years mentioned in a date tag or free text, sorted and
+// deduplicated: "2012-03-04" gives [2012], "2012-2014" gives [2012 2013 2014].
+func ParseYears(s string) []int {
+	var years []int
+	for _, m := range yearPattern.FindAllStringSubmatch(s, -1) {
+		from, _ := strconv.Atoi(m[1])
+		to := from
+		if m[2] != "" {
+			to, _ = strconv.Atoi(m[2])
+		}
+		if to < from || to-from > maxYearRange {
+			to = from
+		}
+		for y := from; y <= to; y++ {
+			years = append(years, y)
+		}
+	}
+	slices.Sort(years)
+	return slices.Compact(years)
+}
+
+// FormatYears formats years for a tag, collapsing consecutive runs:
+// [2012] gives "2012", [2012 2013] gives "2012-2013", [2009 2012] gives "2009, 2012".
+func FormatYears(years []int) string {
+	sorted := slices.Compact(slices.Sorted(slices.Values(years)))
+	var parts []string
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		part := strconv.Itoa(sorted[i])
+		if j > i {
+			part += "-" + strconv.Itoa(sorted[j])
+		}
+		parts = append(parts, part)
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// TrackRecordingYears returns the track's recording years, falling back to the album's.
+func (t *Torrent) TrackRecordingYears(track *Track) []int {
+	if len(track.RecordingYears) > 0 {
+		return track.RecordingYears
+	}
+	return t.RecordingYears
+}
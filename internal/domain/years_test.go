@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYears(t *testing.T) {
+	tests := []struct {
+		Input string
+		Want  []int
+	}{
+		{"2012", []int{2012}},
+		{"2012-03-04", []int{2012}},
+		{"2012-2014", []int{2012, 2013, 2014}},
+		{"Recorded 14-16 March 2009 and 2012, Berlin", []int{2009, 2012}},
+		{"1685-1750", []int{1685}},
+		{"no year", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseYears(tt.Input); !reflect.DeepEqual(got, tt.Want) {
+			t.Errorf("ParseYears(%q) = %v, want %v", tt.Input, got, tt.Want)
+		}
+	}
+}
+
+func TestFormatYears(t *testing.T) {
+	tests := []struct {
+		Input []int
+		Want  string
+	}{
+		{[]int{2012}, "2012"},
+		{[]int{2013, 2012}, "2012-2013"},
+		{[]int{2009, 2012, 2013, 2013}, "2009, 2012-2013"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := FormatYears(tt.Input); got != tt.Want {
+			t.Errorf("FormatYears(%v) = %q, want %q", tt.Input, got, tt.Want)
+		}
+	}
+}
+
+func TestTorrent_TrackRecordingYears(t *testing.T) {
+	torrent := &Torrent{RecordingYears: []int{2012}}
+	if got := torrent.TrackRecordingYears(&Track{}); !reflect.DeepEqual(got, []int{2012}) {
+		t.Errorf("album fallback = %v", got)
+	}
+	if got := torrent.TrackRecordingYears(&Track{RecordingYears: []int{1999}}); !reflect.DeepEqual(got, []int{1999}) {
+		t.Errorf("track override = %v", got)
+	}
+}
@@ -0,0 +1,250 @@
+// Package enrich looks up album metadata from a chain of sources (local tags,
+// Discogs, a label's album page, a hand-edited file) and merges the results by
+// field precedence.
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+var (
+	// ErrNotFound is returned by an enricher that has nothing for the album; the chain skips it.
+	ErrNotFound = errors.New("no match found")
+	// ErrUnknownEnricher is returned for a chain entry that names no known enricher.
+	ErrUnknownEnricher = errors.New("unknown enricher")
+	// ErrUnknownField is returned for a precedence entry that names no mergeable field.
+	ErrUnknownField = errors.New("unknown metadata field")
+)
+
+// Enricher looks up metadata for an album from one source.
+type Enricher interface {
+	// Name identifies the source in config, precedence lists and output file names.
+	Name() string
+	// Lookup returns the source's metadata for album (the local extraction), or an
+	// error wrapping ErrNotFound when the source has no match.
+	Lookup(ctx context.Context, album *domain.Torrent) (*domain.Torrent, error)
+}
+
+// AmbiguousError is returned when a source has several candidate matches and the
+// user must pick one.
+type AmbiguousError struct {
+	Source     string
+	Candidates []string // One line per candidate
+	IDs        []int    // Candidate release IDs, parallel to Candidates
+	Hint       string   // How to select a candidate
+}
+
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("%s: %d candidate matches", e.Source, len(e.Candidates))
+}
+
+// Mergeable fields, as named in precedence lists.
+const (
+	FieldTitle          = "title" // Title and alternate titles
+	FieldYear           = "year"
+	FieldRecordingYears = "recording_years"
+	FieldEdition        = "edition"
+	FieldAlbumArtist    = "album_artist"
+	FieldTracks         = "tracks" // Track titles and artists, matched by disc and track number
+	FieldFiles          = "files"  // The file list and root path, i.e. the files on disk
+)
+
+// Fields lists every mergeable field.
+var Fields = []string{FieldTitle, FieldYear, FieldRecordingYears, FieldEdition, FieldAlbumArtist, FieldTracks, FieldFiles}
+
+// Result is one enricher's metadata.
+type Result struct {
+	Source  string
+	Torrent *domain.Torrent
+}
+
+// Chain runs enrichers in order. Unless Precedence says otherwise, a field comes
+// from the last source that has it, except FieldFiles, which comes from the first
+// (the local files being tagged).
+type Chain struct {
+	Enrichers []Enricher
+	// Precedence lists, per field, source names from highest to lowest precedence.
+	// Sources missing from a list rank below those in it, in default order.
+	Precedence map[string][]string
+	// Log reports skipped sources; nil discards.
+	Log func(format string, args ...any)
+}
+
+// ValidatePrecedence checks that every precedence entry names a known field.
+func ValidatePrecedence(precedence map[string][]string) error {
+	for field := range precedence {
+		if !slices.Contains(Fields, field) {
+			return fmt.Errorf("%w %q (want one of %s)", ErrUnknownField, field, strings.Join(Fields, ", "))
+		}
+	}
+	return nil
+}
+
+// Run looks album up with every enricher and returns the results in chain order.
+// Sources with no match are skipped; any other error stops the chain.
+func (c *Chain) Run(ctx context.Context, album *domain.Torrent) ([]Result, error) {
+	var results []Result
+	for _, e := range c.Enrichers {
+		t, err := e.Lookup(ctx, album)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			c.log("%s: %v", e.Name(), err)
+			continue
+		case err != nil:
+			return results, err
+		case t == nil:
+			continue
+		}
+		results = append(results, Result{Source: e.Name(), Torrent: t})
+	}
+	return results, nil
+}
+
+func (c *Chain) log(format string, args ...any) {
+	if c.Log != nil {
+		c.Log(format, args...)
+	}
+}
+
+// Merge combines results field by field according to the chain's precedence.
+// Sources and the missing-composer mode are combined from all results.
+func (c *Chain) Merge(results []Result) *domain.Torrent {
+	if len(results) == 0 {
+		return nil
+	}
+	if len(results) == 1 {
+		return results[0].Torrent
+	}
+
+	merged := &domain.Torrent{}
+	files := c.ordered(FieldFiles, results)[0].Torrent
+	merged.RootPath = files.RootPath
+	for _, f := range files.Files {
+		merged.Files = append(merged.Files, cloneFile(f))
+	}
+
+	for _, r := range c.ordered(FieldTitle, results) {
+		if r.Torrent.Title != "" {
+			merged.Title, merged.AlternateTitles = r.Torrent.Title, r.Torrent.AlternateTitles
+			break
+		}
+	}
+	for _, r := range c.ordered(FieldYear, results) {
+		if r.Torrent.OriginalYear > 0 {
+			merged.OriginalYear = r.Torrent.OriginalYear
+			break
+		}
+	}
+	for _, r := range c.ordered(FieldRecordingYears, results) {
+		if len(r.Torrent.RecordingYears) > 0 {
+			merged.RecordingYears = r.Torrent.RecordingYears
+			break
+		}
+	}
+	editions := c.ordered(FieldEdition, results)
+	for _, r := range editions {
+		if r.Torrent.Edition != nil {
+			edition := *r.Torrent.Edition
+			merged.Edition = &edition
+			break
+		}
+	}
+	// Release identifiers the chosen edition lacks come from any source that knows them
+	for _, r := range editions {
+		if e := r.Torrent.Edition; e != nil && merged.Edition != nil {
+			if merged.Edition.DiscogsReleaseID == 0 {
+				merged.Edition.DiscogsReleaseID = e.DiscogsReleaseID
+			}
+			if merged.Edition.MusicBrainzAlbumID == "" {
+				merged.Edition.MusicBrainzAlbumID = e.MusicBrainzAlbumID
+			}
+		}
+	}
+	for _, r := range c.ordered(FieldAlbumArtist, results) {
+		if len(r.Torrent.AlbumArtist) > 0 {
+			merged.AlbumArtist = r.Torrent.AlbumArtist
+			break
+		}
+	}
+
+	tracksOrder := c.ordered(FieldTracks, results)
+	for _, track := range merged.Tracks() {
+		if t := findTrack(tracksOrder, track, func(t *domain.Track) bool { return t.Title != "" }); t != nil {
+			track.Title = t.Title
+		}
+		if t := findTrack(tracksOrder, track, func(t *domain.Track) bool { return len(t.Artists) > 0 }); t != nil {
+			track.Artists = slices.Clone(t.Artists)
+		}
+	}
+
+	for _, r := range results {
+		for _, s := range r.Torrent.Sources {
+			if !slices.Contains(merged.Sources, s) {
+				merged.Sources = append(merged.Sources, s)
+			}
+		}
+		merged.AllowMissingComposer = merged.AllowMissingComposer || r.Torrent.AllowMissingComposer
+		if merged.SiteMetadata == nil {
+			merged.SiteMetadata = r.Torrent.SiteMetadata
+		}
+	}
+	return merged
+}
+
+// ordered returns results from highest to lowest precedence for field.
+func (c *Chain) ordered(field string, results []Result) []Result {
+	// Default: later sources win, except for the files themselves
+	def := slices.Clone(results)
+	if field != FieldFiles {
+		slices.Reverse(def)
+	}
+
+	var out []Result
+	for _, name := range c.Precedence[field] {
+		for _, r := range def {
+			if r.Source == name {
+				out = append(out, r)
+			}
+		}
+	}
+	for _, r := range def {
+		if !slices.Contains(c.Precedence[field], r.Source) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// findTrack returns the first track matching want's disc and track number, in
+// precedence order, for which ok holds.
+func findTrack(results []Result, want *domain.Track, ok func(*domain.Track) bool) *domain.Track {
+	for _, r := range results {
+		for _, t := range r.Torrent.Tracks() {
+			if t.Disc == want.Disc && t.Track == want.Track && ok(t) {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// cloneFile copies a file entry so merging never modifies a source's torrent.
+func cloneFile(f domain.FileLike) domain.FileLike {
+	switch v := f.(type) {
+	case *domain.Track:
+		c := *v
+		c.Artists = slices.Clone(v.Artists)
+		return &c
+	case *domain.File:
+		c := *v
+		return &c
+	default:
+		return f
+	}
+}
@@ -0,0 +1,327 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/discogs"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/scraping"
+)
+
+// fake returns a fixed torrent or error.
+type fake struct {
+	name    string
+	torrent *domain.Torrent
+	err     error
+}
+
+func (f fake) Name() string { return f.name }
+
+func (f fake) Lookup(context.Context, *domain.Torrent) (*domain.Torrent, error) {
+	return f.torrent, f.err
+}
+
+func album(title string, year int, trackTitles ...string) *domain.Torrent {
+	t := &domain.Torrent{RootPath: title, Title: title, OriginalYear: year}
+	for i, tt := range trackTitles {
+		t.Files = append(t.Files, &domain.Track{
+			File:    domain.File{Path: fmt.Sprintf("%02d.flac", i+1)},
+			Disc:    1,
+			Track:   i + 1,
+			Title:   tt,
+			Artists: []domain.Artist{{Name: "Bach", Role: domain.RoleComposer}},
+		})
+	}
+	return t
+}
+
+func TestChain_Run(t *testing.T) {
+	local := album("Local", 0, "aria")
+	var logged []string
+	chain := &Chain{
+		Enrichers: []Enricher{
+			Local{},
+			fake{name: "discogs", err: fmt.Errorf("%w on Discogs", ErrNotFound)},
+			fake{name: "file", torrent: album("File", 1982, "Aria")},
+		},
+		Log: func(format string, args ...any) { logged = append(logged, fmt.Sprintf(format, args...)) },
+	}
+
+	results, err := chain.Run(context.Background(), local)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	var sources []string
+	for _, r := range results {
+		sources = append(sources, r.Source)
+	}
+	if !slices.Equal(sources, []string{"local", "file"}) {
+		t.Errorf("Run() sources = %v, want [local file]", sources)
+	}
+	if len(logged) != 1 {
+		t.Errorf("Run() should log the skipped source, logged %v", logged)
+	}
+
+	failing := errors.New("connection refused")
+	chain.Enrichers[1] = fake{name: "discogs", err: failing}
+	if _, err := chain.Run(context.Background(), local); !errors.Is(err, failing) {
+		t.Errorf("Run() error = %v, want %v", err, failing)
+	}
+}
+
+func TestChain_Merge(t *testing.T) {
+	local := album("Local", 0, "aria", "variatio 1")
+	local.Sources = []string{"local"}
+	discogsAlbum := album("Goldberg Variations", 1982, "Aria", "")
+	discogsAlbum.RootPath = "Bach - Goldberg Variations"
+	discogsAlbum.Sources = []string{"https://www.discogs.com/release/1"}
+	results := []Result{{"local", local}, {"discogs", discogsAlbum}}
+
+	merged := (&Chain{}).Merge(results)
+	if merged.RootPath != "Local" {
+		t.Errorf("RootPath = %q, want the local files' root", merged.RootPath)
+	}
+	if merged.Title != "Goldberg Variations" || merged.OriginalYear != 1982 {
+		t.Errorf("Title, year = %q, %d; want the later source's", merged.Title, merged.OriginalYear)
+	}
+	tracks := merged.Tracks()
+	if tracks[0].Title != "Aria" || tracks[1].Title != "variatio 1" {
+		t.Errorf("track titles = %q, %q; want Aria and the local fallback", tracks[0].Title, tracks[1].Title)
+	}
+	if !slices.Equal(merged.Sources, []string{"local", "https://www.discogs.com/release/1"}) {
+		t.Errorf("Sources = %v", merged.Sources)
+	}
+	if local.Tracks()[0].Title != "aria" {
+		t.Error("Merge() modified a source torrent")
+	}
+
+	merged = (&Chain{Precedence: map[string][]string{FieldTitle: {"local"}, FieldTracks: {"local"}}}).Merge(results)
+	if merged.Title != "Local" || merged.OriginalYear != 1982 {
+		t.Errorf("Title, year = %q, %d; want local title and Discogs year", merged.Title, merged.OriginalYear)
+	}
+	if merged.Tracks()[0].Title != "aria" {
+		t.Errorf("track title = %q, want local precedence", merged.Tracks()[0].Title)
+	}
+}
+
+func TestChain_MergeEditionIdentifiers(t *testing.T) {
+	local := album("Local", 0, "aria")
+	local.Edition = &domain.Edition{Label: "Sony", MusicBrainzAlbumID: "5d2a6a0e-8c7f-4f3b-9b1e-0c7d2e4a6b8f"}
+	discogsAlbum := album("Goldberg Variations", 1982, "Aria")
+	discogsAlbum.Edition = &domain.Edition{Label: "Sony Classical", CatalogNumber: "SMK 52594", DiscogsReleaseID: 1234567}
+
+	merged := (&Chain{}).Merge([]Result{{"local", local}, {"discogs", discogsAlbum}})
+	want := domain.Edition{Label: "Sony Classical", CatalogNumber: "SMK 52594", DiscogsReleaseID: 1234567, MusicBrainzAlbumID: "5d2a6a0e-8c7f-4f3b-9b1e-0c7d2e4a6b8f"}
+	if merged.Edition == nil || *merged.Edition != want {
+		t.Errorf("Edition = %+v, want the Discogs edition with the local MusicBrainz ID", merged.Edition)
+	}
+	if discogsAlbum.Edition.MusicBrainzAlbumID != "" {
+		t.Error("Merge() modified a source edition")
+	}
+}
+
+func TestValidatePrecedence(t *testing.T) {
+	if err := ValidatePrecedence(map[string][]string{FieldTracks: {"file"}}); err != nil {
+		t.Errorf("ValidatePrecedence() error = %v", err)
+	}
+	if err := ValidatePrecedence(map[string][]string{"genre": {"file"}}); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("ValidatePrecedence() error = %v, want ErrUnknownField", err)
+	}
+}
+
+func TestFile_Lookup(t *testing.T) {
+	if _, err := (File{}).Lookup(context.Background(), nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() without a path error = %v, want ErrNotFound", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "manual.json")
+	if err := album("Manual", 1955, "Aria").Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := File{Path: path}.Lookup(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if got.Title != "Manual" || got.OriginalYear != 1955 {
+		t.Errorf("Lookup() = %q (%d)", got.Title, got.OriginalYear)
+	}
+}
+
+func TestLibrary_Lookup(t *testing.T) {
+	if _, err := (Library{}).Lookup(context.Background(), nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() without a path error = %v, want ErrNotFound", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "roon.json")
+	export := `{"title": "Goldberg Variations", "year": 1982, "tracks": [{"track": 1, "title": "Aria", "composers": ["Johann Sebastian Bach"]}]}`
+	if err := os.WriteFile(path, []byte(export), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Library{Path: path}.Lookup(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if got.Title != "Goldberg Variations" || len(got.Tracks()) != 1 || got.Tracks()[0].Composer() != "Johann Sebastian Bach" {
+		t.Errorf("Lookup() = %q with %d tracks", got.Title, len(got.Tracks()))
+	}
+}
+
+func TestDiscogs_Lookup_Ambiguous(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results": [{"id": 1, "title": "Goldberg Variations", "label": ["Sony"], "year": "1982"}, {"id": 2, "title": "Goldberg Variations", "label": ["CBS"]}]}`))
+	}))
+	defer server.Close()
+
+	client := discogs.NewClient("test-token")
+	client.BaseURL = server.URL
+	_, err := (&Discogs{Client: client, Barcode: "5099705259425"}).Lookup(context.Background(), album("Goldberg Variations", 0))
+
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Lookup() error = %v, want *AmbiguousError", err)
+	}
+	want := []string{"[1] Goldberg Variations - Sony (1982)", "[2] Goldberg Variations - CBS"}
+	if !slices.Equal(ambiguous.Candidates, want) {
+		t.Errorf("Candidates = %q, want %q", ambiguous.Candidates, want)
+	}
+	if !slices.Equal(ambiguous.IDs, []int{1, 2}) {
+		t.Errorf("IDs = %v, want [1 2]", ambiguous.IDs)
+	}
+}
+
+func TestDiscogs_Lookup_AmbiguousRankedByDurations(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/releases/1":
+			w.Write([]byte(`{"id": 1, "title": "Goldberg Variations", "tracklist": [{"position": "1", "title": "Aria", "duration": "3:05"}, {"position": "2", "title": "Variatio 1", "duration": "1:50"}]}`))
+		case "/releases/2":
+			w.Write([]byte(`{"id": 2, "title": "Goldberg Variations", "tracklist": [{"position": "1", "title": "Aria", "duration": "1:53"}, {"position": "2", "title": "Variatio 1", "duration": "0:45"}]}`))
+		default:
+			w.Write([]byte(`{"results": [{"id": 1, "title": "Goldberg Variations", "year": "1982"}, {"id": 2, "title": "Goldberg Variations", "year": "1955"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := discogs.NewClient("test-token")
+	client.BaseURL = server.URL
+	local := album("Goldberg Variations", 0, "Aria", "Variatio 1")
+	local.Tracks()[0].Duration = 113 * time.Second
+	local.Tracks()[1].Duration = 46 * time.Second
+	_, err := (&Discogs{Client: client, Barcode: "5099705259425"}).Lookup(context.Background(), local)
+
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Lookup() error = %v, want *AmbiguousError", err)
+	}
+	want := []string{
+		"[2] Goldberg Variations (1955) - 2 of 2 durations match",
+		"[1] Goldberg Variations (1982) - 2 of 2 durations differ (likely a different recording)",
+	}
+	if !slices.Equal(ambiguous.Candidates, want) {
+		t.Errorf("Candidates = %q, want %q", ambiguous.Candidates, want)
+	}
+	if !slices.Equal(ambiguous.IDs, []int{2, 1}) {
+		t.Errorf("IDs = %v, want [2 1]", ambiguous.IDs)
+	}
+}
+
+func TestDiscogs_Lookup_TaggedReleaseID(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1234567, "title": "Goldberg Variations", "year": 1982, "tracklist": [{"position": "1", "title": "Aria", "extraartists": [{"name": "Johann Sebastian Bach", "role": "Composed By"}]}]}`))
+	}))
+	defer server.Close()
+
+	client := discogs.NewClient("test-token")
+	client.BaseURL = server.URL
+	local := album("Goldberg Variations", 0, "Aria")
+	local.Edition = &domain.Edition{DiscogsReleaseID: 1234567}
+	got, err := (&Discogs{Client: client}).Lookup(context.Background(), local)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	// Fetched directly, without searching
+	for _, path := range paths {
+		if path != "/releases/1234567" {
+			t.Errorf("requested %s, want only the tagged release", path)
+		}
+	}
+	if got.Edition == nil || got.Edition.DiscogsReleaseID != 1234567 {
+		t.Errorf("Edition = %+v, want release 1234567", got.Edition)
+	}
+}
+
+func TestDiscogs_Lookup_DiscOrder(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// The release lists the bonus disc first
+		w.Write([]byte(`{"id": 7, "title": "Symphonies", "tracklist": [
+			{"position": "1-1", "title": "Rehearsal", "duration": "3:20"},
+			{"position": "1-2", "title": "Interview", "duration": "3:30"},
+			{"position": "2-1", "title": "Symphony No. 1: I", "duration": "10:00"}]}`))
+	}))
+	defer server.Close()
+	client := discogs.NewClient("test-token")
+	client.BaseURL = server.URL
+
+	local := &domain.Torrent{Title: "Symphonies", Files: []domain.FileLike{
+		&domain.Track{File: domain.File{Path: "CD1/01.flac"}, Disc: 1, Track: 1, Duration: 600 * time.Second},
+		&domain.Track{File: domain.File{Path: "CD2/01.flac"}, Disc: 2, Track: 1, Duration: 200 * time.Second},
+		&domain.Track{File: domain.File{Path: "CD2/02.flac"}, Disc: 2, Track: 2, Duration: 210 * time.Second},
+	}}
+	discs := func(torrent *domain.Torrent) map[string]int {
+		got := make(map[string]int)
+		for _, track := range torrent.Tracks() {
+			got[track.Title] = track.Disc
+		}
+		return got
+	}
+
+	// Matched by duration
+	got, err := (&Discogs{Client: client, ReleaseID: 7}).Lookup(context.Background(), local)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if d := discs(got); d["Symphony No. 1: I"] != 1 || d["Rehearsal"] != 2 || d["Interview"] != 2 {
+		t.Errorf("discs by duration = %v", d)
+	}
+
+	// Given by hand, without durations to go by
+	for _, track := range local.Tracks() {
+		track.Duration = 0
+	}
+	got, err = (&Discogs{Client: client, ReleaseID: 7, DiscMap: domain.DiscMap{1: 2, 2: 1}}).Lookup(context.Background(), local)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if d := discs(got); d["Symphony No. 1: I"] != 1 || d["Rehearsal"] != 2 {
+		t.Errorf("discs by map = %v", d)
+	}
+}
+
+func TestWeb_Lookup(t *testing.T) {
+	if _, err := (Web{}).Lookup(context.Background(), nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() without a URL error = %v, want ErrNotFound", err)
+	}
+	web := Web{URL: "album.html", Registry: scraping.DefaultRegistry()}
+	if _, err := web.Lookup(context.Background(), nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() of an unsupported URL error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,91 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"slices"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/uploader"
+)
+
+// Redacted is a Redacted torrent's group musicInfo laid over the local file
+// structure: a best-effort source for albums Discogs does not list. The files,
+// durations, stream info and track titles stay the local ones; the album title,
+// year, edition and artists come from the group and torrent.
+type Redacted struct {
+	Client    *uploader.RedactedClient
+	TorrentID int
+}
+
+// Name implements Enricher.
+func (Redacted) Name() string { return "redacted" }
+
+// Lookup implements Enricher.
+func (r Redacted) Lookup(ctx context.Context, album *domain.Torrent) (*domain.Torrent, error) {
+	if r.TorrentID == 0 {
+		return nil, fmt.Errorf("%w: no Redacted torrent ID given", ErrNotFound)
+	}
+	torrent, err := r.Client.GetTorrent(ctx, r.TorrentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Redacted torrent %d: %w", r.TorrentID, err)
+	}
+	group, err := r.Client.GetTorrentGroup(ctx, torrent.GroupID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Redacted group %d: %w", torrent.GroupID, err)
+	}
+	return hybridTorrent(album, torrent, group), nil
+}
+
+// hybridTorrent combines album's files with the Redacted torrent and group.
+// Tracks without a composer get the group's composer when it has exactly one;
+// tracks without performers get the group's performers.
+func hybridTorrent(album *domain.Torrent, torrent *uploader.Torrent, group *uploader.TorrentGroup) *domain.Torrent {
+	t := &domain.Torrent{
+		RootPath:     album.RootPath,
+		Title:        html.UnescapeString(group.Name),
+		OriginalYear: group.Year,
+		Sources:      []string{fmt.Sprintf("https://redacted.sh/torrents.php?torrentid=%d", torrent.TorrentID)},
+		SiteMetadata: &domain.SiteMetadata{
+			TorrentID: torrent.TorrentID,
+			GroupID:   torrent.GroupID,
+			Tags:      group.Tags,
+			Media:     torrent.Media,
+			Format:    torrent.Format,
+			Encoding:  torrent.Encoding,
+		},
+	}
+	if torrent.RemasterRecordLabel != "" || torrent.RemasterCatalogueNumber != "" {
+		t.Edition = &domain.Edition{
+			Label:         html.UnescapeString(torrent.RemasterRecordLabel),
+			CatalogNumber: html.UnescapeString(torrent.RemasterCatalogueNumber),
+			Year:          torrent.RemasterYear,
+		}
+	}
+
+	var composers, performers []domain.Artist
+	for _, a := range group.DomainArtists() {
+		switch {
+		case a.Role == domain.RoleComposer:
+			composers = append(composers, a)
+		case a.Role.IsPerformer():
+			performers = append(performers, a)
+		}
+	}
+	t.AlbumArtist = performers
+
+	for _, f := range album.Files {
+		f = cloneFile(f)
+		if track, ok := f.(*domain.Track); ok {
+			if len(track.Composers()) == 0 && len(composers) == 1 {
+				track.Artists = append(track.Artists, composers[0])
+			}
+			if !slices.ContainsFunc(track.Artists, func(a domain.Artist) bool { return a.Role.IsPerformer() }) {
+				track.Artists = append(track.Artists, performers...)
+			}
+		}
+		t.Files = append(t.Files, f)
+	}
+	return t
+}
@@ -0,0 +1,88 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+	"github.com/cehbz/classical-tagger/internal/uploader"
+)
+
+func TestRedacted_Lookup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch q.Get("action") + ":" + q.Get("id") {
+		case "torrent:99":
+			w.Write([]byte(`{"status": "success", "response": {
+				"group": {"id": 7, "name": "Goldberg Variations", "year": 1955},
+				"torrent": {"id": 99, "media": "CD", "format": "FLAC", "encoding": "Lossless",
+					"remastered": true, "remasterYear": 2002, "remasterRecordLabel": "Sony Classical",
+					"remasterCatalogueNumber": "SMK 87703"}}}`))
+		case "torrentgroup:7":
+			w.Write([]byte(`{"status": "success", "response": {"group": {
+				"id": 7, "name": "Goldberg Variations", "year": 1955, "tags": ["classical", "baroque"],
+				"musicInfo": {"composers": [{"id": 1, "name": "Johann Sebastian Bach"}],
+					"artists": [{"id": 2, "name": "Glenn Gould"}]}}}}`))
+		default:
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+			w.Write([]byte(`{"status": "failure", "error": "bad id parameter"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &uploader.RedactedClient{
+		BaseURL:     server.URL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		RateLimiter: ratelimit.NewRateLimiter(10, 10*time.Second),
+	}
+
+	// Local tags: file structure and titles, one track missing its artists
+	local := album("Gould 1955", 0, "Aria", "Variatio 1")
+	local.Tracks()[1].Artists = nil
+	local.Tracks()[0].Artists = append(local.Tracks()[0].Artists, domain.Artist{Name: "Gould", Role: domain.RoleSoloist})
+
+	got, err := Redacted{Client: client, TorrentID: 99}.Lookup(context.Background(), local)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if got.Title != "Goldberg Variations" || got.OriginalYear != 1955 || got.RootPath != "Gould 1955" {
+		t.Errorf("album = %q %d %q, want the group's title and year over the local root", got.Title, got.OriginalYear, got.RootPath)
+	}
+	if want := (domain.Edition{Label: "Sony Classical", CatalogNumber: "SMK 87703", Year: 2002}); got.Edition == nil || *got.Edition != want {
+		t.Errorf("Edition = %+v, want %+v", got.Edition, want)
+	}
+	if want := []domain.Artist{{Name: "Glenn Gould", Role: domain.RolePerformer}}; !slices.Equal(got.AlbumArtist, want) {
+		t.Errorf("AlbumArtist = %v, want %v", got.AlbumArtist, want)
+	}
+	if got.SiteMetadata == nil || got.SiteMetadata.TorrentID != 99 || got.SiteMetadata.GroupID != 7 {
+		t.Errorf("SiteMetadata = %+v, want torrent 99 in group 7", got.SiteMetadata)
+	}
+
+	tracks := got.Tracks()
+	if len(tracks) != 2 || tracks[0].Title != "Aria" || tracks[0].Path != "01.flac" {
+		t.Fatalf("tracks = %v, want the local files and titles", tracks)
+	}
+	// Tracks keep their own artists and gain only what they lack
+	if want := []domain.Artist{{Name: "Bach", Role: domain.RoleComposer}, {Name: "Gould", Role: domain.RoleSoloist}}; !slices.Equal(tracks[0].Artists, want) {
+		t.Errorf("track 1 artists = %v, want %v", tracks[0].Artists, want)
+	}
+	if want := []domain.Artist{{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}, {Name: "Glenn Gould", Role: domain.RolePerformer}}; !slices.Equal(tracks[1].Artists, want) {
+		t.Errorf("track 2 artists = %v, want %v", tracks[1].Artists, want)
+	}
+	if len(local.Tracks()[1].Artists) != 0 {
+		t.Error("Lookup() modified the local album")
+	}
+}
+
+func TestRedacted_LookupWithoutTorrentID(t *testing.T) {
+	_, err := Redacted{}.Lookup(context.Background(), album("Local", 0))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Lookup() error = %v, want ErrNotFound", err)
+	}
+}
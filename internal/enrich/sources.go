@@ -0,0 +1,419 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/cehbz/classical-tagger/internal/discogs"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+	"github.com/cehbz/classical-tagger/internal/scraping"
+	"github.com/cehbz/classical-tagger/internal/storage"
+)
+
+// Local is the album's own tags: it returns the album it is given.
+type Local struct{}
+
+// Name implements Enricher.
+func (Local) Name() string { return "local" }
+
+// Lookup implements Enricher.
+func (Local) Lookup(_ context.Context, album *domain.Torrent) (*domain.Torrent, error) {
+	return album, nil
+}
+
+// File is metadata written or corrected by hand, loaded from a JSON file.
+type File struct {
+	Path string
+}
+
+// Name implements Enricher.
+func (File) Name() string { return "file" }
+
+// Lookup implements Enricher.
+func (f File) Lookup(_ context.Context, _ *domain.Torrent) (*domain.Torrent, error) {
+	if f.Path == "" {
+		return nil, fmt.Errorf("%w: no metadata file given", ErrNotFound)
+	}
+	return storage.NewRepository().LoadFromFile(f.Path)
+}
+
+// Library is metadata curated in a library manager and exported to a file: a
+// JRiver MPL playlist or a Roon album export.
+type Library struct {
+	Path string
+	// Log receives parsing warnings (nil discards)
+	Log func(format string, args ...any)
+}
+
+// Name implements Enricher.
+func (Library) Name() string { return "library" }
+
+// Lookup implements Enricher.
+func (l Library) Lookup(_ context.Context, _ *domain.Torrent) (*domain.Torrent, error) {
+	if l.Path == "" {
+		return nil, fmt.Errorf("%w: no library export given", ErrNotFound)
+	}
+	f, err := os.Open(l.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	result, err := scraping.ParseLibraryExport(f)
+	if err != nil {
+		return nil, err
+	}
+	if l.Log != nil {
+		for _, warning := range result.Warnings {
+			l.Log("⚠️  %s: %s", l.Path, warning)
+		}
+	}
+	return result.Torrent, nil
+}
+
+// Web is an album page scraped with the extractor registry: a site-specific
+// extractor, else the generic schema.org JSON-LD one.
+type Web struct {
+	URL      string
+	Registry *scraping.Registry
+	// Log receives extraction warnings (nil discards)
+	Log func(format string, args ...any)
+}
+
+// Name implements Enricher.
+func (Web) Name() string { return "web" }
+
+// Lookup implements Enricher.
+func (w Web) Lookup(_ context.Context, _ *domain.Torrent) (*domain.Torrent, error) {
+	if w.URL == "" {
+		return nil, fmt.Errorf("%w: no album page URL given", ErrNotFound)
+	}
+	result, err := w.Registry.Extract(w.URL)
+	if errors.Is(err, scraping.ErrUnsupportedURL) {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if w.Log != nil {
+		for _, warning := range result.Warnings {
+			w.Log("⚠️  %s: %s", w.URL, warning)
+		}
+		for _, e := range result.Errors {
+			w.Log("⚠️  %s: %s: %s", w.URL, e.Field, e.Message)
+		}
+	}
+	return result.Torrent, nil
+}
+
+// discogsCandidate renders one line of a multiple-match listing.
+var discogsCandidate = template.Must(template.New("release").Parse(
+	`[{{.ID}}] {{.Title}}{{if .Label}} - {{.Label}}{{end}}{{if .CatalogNumber}} {{.CatalogNumber}}{{end}}{{if gt .Year 0}} ({{.Year}}){{end}}{{if .Country}}, {{.Country}}{{end}}`))
+
+// Discogs looks the album up on Discogs: by ReleaseID when set, else by the
+// DISCOGS_RELEASE_ID tag of an earlier run, else by barcode and catalog number,
+// else by artist and title.
+type Discogs struct {
+	Client *discogs.Client
+	// ReleaseID selects a release directly
+	ReleaseID int
+	// Barcode and CatalogNumber override those from the local tags and cue sheets
+	Barcode       string
+	CatalogNumber string
+	// RootPath is the directory the generated root_path is placed under
+	RootPath string
+	// DiscMap maps the release's discs to the local ones; nil matches them by
+	// their durations when they don't line up
+	DiscMap domain.DiscMap
+	// Verbose logs each search; Log receives progress and warnings (nil discards)
+	Verbose bool
+	Log     func(format string, args ...any)
+}
+
+// Name implements Enricher.
+func (d *Discogs) Name() string { return "discogs" }
+
+// Lookup implements Enricher. Several matches return an *AmbiguousError listing them.
+func (d *Discogs) Lookup(ctx context.Context, album *domain.Torrent) (*domain.Torrent, error) {
+	releaseID := d.ReleaseID
+	if releaseID == 0 && album != nil && album.Edition != nil && album.Edition.DiscogsReleaseID != 0 {
+		releaseID = album.Edition.DiscogsReleaseID
+		if d.Verbose {
+			d.log("Using Discogs release %d from the DISCOGS_RELEASE_ID tag", releaseID)
+		}
+	}
+
+	var releases []*discogs.Release
+	if releaseID != 0 {
+		release, err := d.Client.GetRelease(ctx, releaseID)
+		if err != nil || release == nil {
+			return nil, fmt.Errorf("failed to fetch Discogs release %d: %w", releaseID, err)
+		}
+		releases = append(releases, release)
+	} else if releases = d.searchByIdentifier(ctx, album); len(releases) == 0 {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var err error
+		if releases, err = d.searchByTitle(ctx, album); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(releases) > 1 {
+		amb := &AmbiguousError{Source: d.Name(), Hint: "re-run with --release-id to select a specific release"}
+		for _, c := range d.rankByDurations(ctx, album, releases) {
+			var b strings.Builder
+			if err := discogsCandidate.Execute(&b, c.release); err != nil {
+				return nil, err
+			}
+			if summary := c.durations.String(); summary != "" {
+				b.WriteString(" - " + summary)
+			}
+			if c.durations.DifferentRecording() {
+				b.WriteString(" (likely a different recording)")
+			}
+			amb.Candidates = append(amb.Candidates, b.String())
+			amb.IDs = append(amb.IDs, c.release.ID)
+		}
+		return nil, amb
+	}
+
+	if d.Verbose {
+		d.log("Found single match: %s - %s [%d]", releases[0].Label, releases[0].CatalogNumber, releases[0].ID)
+	}
+	release, err := d.Client.GetRelease(ctx, releases[0].ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Discogs release details: %w", err)
+	}
+
+	torrent, notes, err := release.DomainTorrentWithNotes(d.RootPath, album)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert Discogs release: %w", err)
+	}
+	for _, note := range notes {
+		d.log("⚠️  Discogs tracklist: %s", note)
+	}
+	switch m, err := d.alignDiscs(album, torrent); {
+	case err != nil:
+		return nil, fmt.Errorf("mapping the discs of Discogs release %d: %w", release.ID, err)
+	case m != nil && d.DiscMap != nil:
+		d.log("Discogs release %d discs mapped to the local ones: %s", release.ID, m)
+	case m != nil:
+		d.log("⚠️  Discogs release %d orders its discs differently; matched to the local ones by duration: %s (override with -disc-map)", release.ID, m)
+	}
+	if check := album.CompareDurations(torrent); check.DifferentRecording() {
+		d.log("⚠️  Discogs release %d: %s from the files; it may be a different recording or edition", release.ID, check)
+	}
+	return torrent, nil
+}
+
+// maxDurationRanked is how many candidate releases rankByDurations fetches.
+const maxDurationRanked = 10
+
+// rankedRelease is a candidate release and how its durations compare with the files'.
+type rankedRelease struct {
+	release   *discogs.Release
+	durations domain.DurationCheck
+}
+
+// rankByDurations fetches the tracklists of the first maxDurationRanked
+// candidates and orders them by how well their durations match the album's
+// files: all matching first, then those not compared, then those with some
+// differences, and last those that are likely a different recording. Candidates
+// keep their search order within each rank. Without local durations the order
+// is unchanged.
+func (d *Discogs) rankByDurations(ctx context.Context, album *domain.Torrent, releases []*discogs.Release) []rankedRelease {
+	ranked := make([]rankedRelease, len(releases))
+	for i, release := range releases {
+		ranked[i].release = release
+	}
+	if album == nil || !slices.ContainsFunc(album.Tracks(), func(t *domain.Track) bool { return t.Duration > 0 }) {
+		return ranked
+	}
+	for i := range ranked[:min(len(ranked), maxDurationRanked)] {
+		release, err := d.Client.GetRelease(ctx, ranked[i].release.ID)
+		if ctx.Err() != nil {
+			return ranked
+		}
+		if err != nil {
+			d.log("Warning: cannot fetch Discogs release %d to compare durations: %v", ranked[i].release.ID, err)
+			continue
+		}
+		if torrent, _, err := release.DomainTorrentWithNotes(d.RootPath, album); err == nil {
+			if _, err := d.alignDiscs(album, torrent); err == nil {
+				ranked[i].durations = album.CompareDurations(torrent)
+			}
+		}
+	}
+	rank := func(c domain.DurationCheck) int {
+		switch {
+		case c.Matches():
+			return 0
+		case c.Compared == 0:
+			return 1
+		case !c.DifferentRecording():
+			return 2
+		default:
+			return 3
+		}
+	}
+	slices.SortStableFunc(ranked, func(a, b rankedRelease) int { return rank(a.durations) - rank(b.durations) })
+	return ranked
+}
+
+// alignDiscs renumbers the discs of a release's torrent to the local ones: by
+// DiscMap when set, else by matching the discs' durations. Returns the map
+// applied, nil when the discs were left as they are.
+func (d *Discogs) alignDiscs(album, torrent *domain.Torrent) (domain.DiscMap, error) {
+	m := d.DiscMap
+	if m == nil && album != nil {
+		m = album.MatchDiscs(torrent)
+	}
+	if m == nil {
+		return nil, nil
+	}
+	if err := m.Apply(torrent); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// searchByIdentifier searches by barcode, then by catalog number (with and then
+// without the label). Returns nil when there is nothing to search by or nothing was found.
+func (d *Discogs) searchByIdentifier(ctx context.Context, t *domain.Torrent) []*discogs.Release {
+	code, catalog, label := d.Barcode, d.CatalogNumber, ""
+	if t.Edition != nil {
+		label = t.Edition.Label
+		if code == "" {
+			code = t.Edition.Barcode
+		}
+		if catalog == "" {
+			catalog = t.Edition.CatalogNumber
+		}
+	}
+
+	type search struct {
+		desc string
+		run  func() ([]*discogs.Release, error)
+	}
+	var searches []search
+	if code != "" {
+		searches = append(searches, search{"barcode " + code, func() ([]*discogs.Release, error) { return d.Client.SearchBarcode(ctx, code) }})
+	}
+	if catalog != "" {
+		if label != "" {
+			searches = append(searches, search{fmt.Sprintf("catalog number %s (%s)", catalog, label), func() ([]*discogs.Release, error) { return d.Client.SearchCatalogNumber(ctx, catalog, label) }})
+		}
+		searches = append(searches, search{"catalog number " + catalog, func() ([]*discogs.Release, error) { return d.Client.SearchCatalogNumber(ctx, catalog, "") }})
+	}
+
+	for _, s := range searches {
+		if d.Verbose {
+			d.log("Searching Discogs by %s", s.desc)
+		}
+		releases, err := s.run()
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			d.log("Warning: Discogs search by %s failed: %v", s.desc, err)
+			continue
+		}
+		if len(releases) > 0 {
+			return releases
+		}
+	}
+	if (d.Barcode != "" || d.CatalogNumber != "") && len(searches) > 0 {
+		d.log("Warning: no Discogs releases found by barcode/catalog number, searching by artist and title")
+	}
+	return nil
+}
+
+// searchByTitle searches by artist and every title variant, falling back to a
+// simple combined query. Failed or empty searches return an ErrNotFound error.
+func (d *Discogs) searchByTitle(ctx context.Context, t *domain.Torrent) ([]*discogs.Release, error) {
+	artist := searchArtist(t)
+	album := t.Title
+	if artist == "" || album == "" {
+		return nil, fmt.Errorf("%w: cannot search Discogs without artist and album information", ErrNotFound)
+	}
+
+	// Search every language variant of the title to improve recall
+	primary, alternates := domain.SplitTitleVariants(album)
+	titles := append([]string{primary}, alternates...)
+	for _, alt := range t.AlternateTitles {
+		if !slices.Contains(titles, alt) {
+			titles = append(titles, alt)
+		}
+	}
+
+	if d.Verbose {
+		d.log("Searching Discogs for: artist=%q album=%q", artist, strings.Join(titles, " | "))
+	}
+	releases, err := d.Client.SearchTitles(ctx, artist, titles)
+	if err != nil {
+		return nil, searchError("Discogs search failed", err)
+	}
+	if len(releases) > 0 {
+		return releases, nil
+	}
+
+	// Try fallback simple search with combined query
+	if d.Verbose {
+		d.log("Advanced search found no results, trying simple search...")
+	}
+	releases, err = d.Client.SearchSimple(ctx, artist+" "+album)
+	if err != nil {
+		return nil, searchError("Discogs fallback search failed", err)
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("%w on Discogs for: %s - %s", ErrNotFound, artist, album)
+	}
+	return releases, nil
+}
+
+// searchError reports a failed search as not found, so the chain moves on,
+// unless Discogs rate limited it: that stops the chain so the user can back off.
+func searchError(msg string, err error) error {
+	var rateLimited *ratelimit.ErrRateLimited
+	if errors.As(err, &rateLimited) {
+		return err
+	}
+	return fmt.Errorf("%w: %s: %v", ErrNotFound, msg, err)
+}
+
+func (d *Discogs) log(format string, args ...any) {
+	if d.Log != nil {
+		d.Log(format, args...)
+	}
+}
+
+// searchArtist returns the artist to search by: the composer if credited, else
+// the first artist, from the album artists and then the first track.
+func searchArtist(t *domain.Torrent) string {
+	if t == nil {
+		return ""
+	}
+	candidates := [][]domain.Artist{t.AlbumArtist}
+	if tracks := t.Tracks(); len(tracks) > 0 {
+		candidates = append(candidates, tracks[0].Artists)
+	}
+	for _, artists := range candidates {
+		if len(artists) == 0 {
+			continue
+		}
+		for _, artist := range artists {
+			if artist.Role == domain.RoleComposer {
+				return artist.Name
+			}
+		}
+		return artists[0].Name
+	}
+	return ""
+}
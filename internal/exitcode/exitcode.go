@@ -0,0 +1,124 @@
+// Package exitcode maps the typed errors of the other packages to process exit
+// codes and remediation hints, so the commands (and any future front end) can
+// report failures consistently instead of parsing error strings.
+package exitcode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+)
+
+// Exit codes.
+const (
+	OK         = 0
+	Failure    = 1 // Any error not classified below
+	Validation = 2 // The metadata needs attention before the command can proceed
+	Load       = 3 // The album could not be read
+	Network    = 4 // A remote service refused or failed the request
+	Abort      = 5 // The user interrupted the command
+)
+
+// PrintCodes writes the exit codes for a command's usage message.
+func PrintCodes(w io.Writer) {
+	fmt.Fprintf(w, "\nExit status:\n")
+	fmt.Fprintf(w, "  %d  success\n", OK)
+	fmt.Fprintf(w, "  %d  any other failure, including invalid arguments\n", Failure)
+	fmt.Fprintf(w, "  %d  validation errors: the metadata or files need attention first\n", Validation)
+	fmt.Fprintf(w, "  %d  load errors: the album, metadata or reference file could not be read\n", Load)
+	fmt.Fprintf(w, "  %d  network or API errors: a remote service failed or refused the request\n", Network)
+	fmt.Fprintf(w, "  %d  aborted by the user, e.g. with Ctrl-C\n", Abort)
+}
+
+// ParseFlags parses args with fs, exiting Failure on invalid flags rather
+// than the flag package's 2, which is Validation here, and OK after -help.
+func ParseFlags(fs *flag.FlagSet, args []string) {
+	fs.Init(fs.Name(), flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(OK)
+		}
+		os.Exit(Failure)
+	}
+}
+
+// Code returns the exit code for err.
+func Code(err error) int {
+	var roleUnknown *domain.ErrRoleUnknown
+	var mediaMismatch *domain.ErrMediaMismatch
+	var rateLimited *ratelimit.ErrRateLimited
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case err == nil:
+		return OK
+	case errors.Is(err, domain.ErrAborted), errors.Is(err, context.Canceled):
+		return Abort
+	case errors.As(err, &rateLimited), errors.Is(err, domain.ErrAPI), errors.As(err, &netErr):
+		return Network
+	case errors.Is(err, domain.ErrNoTracks), errors.Is(err, fs.ErrNotExist), errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return Load
+	case errors.As(err, &roleUnknown), errors.As(err, &mediaMismatch), errors.Is(err, domain.ErrNoComposer), errors.Is(err, domain.ErrMetadataMismatch), errors.Is(err, domain.ErrMergedAlbums),
+		errors.Is(err, domain.ErrValidation):
+		return Validation
+	}
+	return Failure
+}
+
+// Hint returns advice on fixing err, or "" when there is none.
+func Hint(err error) string {
+	var roleUnknown *domain.ErrRoleUnknown
+	var mediaMismatch *domain.ErrMediaMismatch
+	var rateLimited *ratelimit.ErrRateLimited
+	var missingScope *domain.ErrMissingScope
+	switch {
+	case errors.As(err, &missingScope):
+		return fmt.Sprintf("create a %s API key with the %s scope (and any others you use) and set it in the config file", missingScope.Service, missingScope.Scope)
+	case errors.As(err, &rateLimited):
+		if rateLimited.RetryAfter > 0 {
+			return fmt.Sprintf("wait %s before running the command again, or lower the request rate in the config file", rateLimited.RetryAfter)
+		}
+		return "wait a minute before running the command again, or lower the request rate in the config file"
+	case errors.Is(err, domain.ErrNoTracks):
+		return "check that the directory is the album folder and that its FLAC files are readable"
+	case errors.As(err, &roleUnknown):
+		return fmt.Sprintf("neither the release nor the local tags give a role for %s; tag the local files (for example PERFORMER=%s (piano)) or edit the role in the saved JSON", roleUnknown.Artist, roleUnknown.Artist)
+	case errors.As(err, &mediaMismatch):
+		return fmt.Sprintf("upload the files to the group as a new edition with -new-edition -media %s; its remaster fields (label, catalog number, year) come from the local edition. If the group is the wrong release altogether, upload a new group on the site", mediaMismatch.Local)
+	case errors.Is(err, domain.ErrNoComposer):
+		return "add COMPOSER tags, or pass -allow-missing-composer for crossover and recital albums"
+	case errors.Is(err, domain.ErrMetadataMismatch):
+		return "run tag with the metadata file to rewrite the files' tags, or fix the file paths in the JSON"
+	case errors.Is(err, domain.ErrMergedAlbums):
+		return "re-run extract with -split to move each album's files into its own directory and extract them separately"
+	}
+	return ""
+}
+
+// Fail prints err, with prefix and any hint, to stderr and exits with its code.
+func Fail(prefix string, err error) {
+	Print(os.Stderr, prefix, err)
+	os.Exit(Code(err))
+}
+
+// Print writes err, with prefix and any hint, to w.
+func Print(w io.Writer, prefix string, err error) {
+	if prefix != "" {
+		fmt.Fprintf(w, "%s: %v\n", prefix, err)
+	} else {
+		fmt.Fprintf(w, "Error: %v\n", err)
+	}
+	if hint := Hint(err); hint != "" {
+		fmt.Fprintf(w, "Hint: %s\n", hint)
+	}
+}
@@ -0,0 +1,65 @@
+package exitcode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, OK},
+		{"untyped", errors.New("disk full"), Failure},
+		{"no tracks", fmt.Errorf("%w: no FLAC files in /music", domain.ErrNoTracks), Load},
+		{"unknown role", fmt.Errorf("failed to convert: %w", &domain.ErrRoleUnknown{Artist: "Karajan"}), Validation},
+		{"metadata mismatch", fmt.Errorf("album.json: %w in /music (2 errors)", domain.ErrMetadataMismatch), Validation},
+		{"merged albums", fmt.Errorf("%w: /music/incoming", domain.ErrMergedAlbums), Validation},
+		{"media mismatch", fmt.Errorf("upload failed: %w", &domain.ErrMediaMismatch{Local: "CD", Trumped: "WEB"}), Validation},
+		{"rate limited", fmt.Errorf("upload failed: %w", &ratelimit.ErrRateLimited{Service: "Redacted"}), Network},
+		{"validation", domain.Classify(errors.New("request not satisfied"), domain.ErrValidation), Validation},
+		{"validation count", fmt.Errorf("%w with %d errors", domain.ErrValidation, 3), Validation},
+		{"API error", fmt.Errorf("discogs %w: 500 - oops", domain.ErrAPI), Network},
+		{"missing scope", fmt.Errorf("API key check failed: %w", &domain.ErrMissingScope{Service: "Redacted", Scope: "Torrent", Action: "uploading"}), Network},
+		{"unreachable", fmt.Errorf("lookup failed: %w", &net.DNSError{Err: "no such host", Name: "api.discogs.com"}), Network},
+		{"missing file", fmt.Errorf("load album.json: %w", fs.ErrNotExist), Load},
+		{"bad JSON", fmt.Errorf("parse album.json: %w", json.Unmarshal([]byte("{"), new(any))), Load},
+		{"interrupted", fmt.Errorf("extract: %w", context.Canceled), Abort},
+		{"aborted", domain.ErrAborted, Abort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Code(tt.err); got != tt.want {
+				t.Errorf("Code(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrint(t *testing.T) {
+	var buf bytes.Buffer
+	Print(&buf, "Upload failed", &ratelimit.ErrRateLimited{Service: "Redacted", RetryAfter: 5 * time.Second})
+	want := "Upload failed: rate limited by Redacted, retry after 5s\nHint: wait 5s before running the command again"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("Print() = %q, want prefix %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	Print(&buf, "", errors.New("disk full"))
+	if buf.String() != "Error: disk full\n" {
+		t.Errorf("Print() = %q, want no hint", buf.String())
+	}
+}
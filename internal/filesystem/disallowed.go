@@ -0,0 +1,74 @@
+package filesystem
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+// DisallowedFile is a file that must not be packaged into a torrent.
+type DisallowedFile struct {
+	Path   string // Relative to the album root
+	Reason string // "lossy audio", "archive", "executable" or "torrent file"
+}
+
+// lossyExtensions lists lossy audio formats, not allowed alongside FLAC.
+var lossyExtensions = []string{".mp3", ".m4a", ".aac", ".ogg", ".oga", ".opus", ".wma", ".mpc", ".mp2"}
+
+// archiveExtensions lists archive formats, which hide their contents from the tracker.
+var archiveExtensions = []string{
+	".7z", ".ace", ".arj", ".bz2", ".cab", ".gz", ".lzh", ".rar", ".sit", ".sitx", ".tar", ".tbz2", ".tgz", ".txz", ".xz", ".zip",
+}
+
+// executableExtensions lists programs and scripts.
+var executableExtensions = []string{
+	".exe", ".com", ".bat", ".cmd", ".msi", ".dll", ".scr", ".vbs", ".ps1", ".sh", ".jar", ".apk", ".dmg", ".pkg", ".app", ".lnk",
+}
+
+// FindDisallowedFiles returns the files under root that must not be uploaded:
+// lossy audio when the album also holds FLAC, archives, executables and nested
+// torrent files. Top-level folders named in skip (extras left out of the
+// torrent) are not scanned. The result is sorted by path.
+func FindDisallowedFiles(files fsys.FS, root string, skip []string) ([]DisallowedFile, error) {
+	var found, lossy []DisallowedFile
+	hasFLAC := false
+	err := fsys.Or(files).WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if rel != "." && slices.Contains(skip, rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch ext := strings.ToLower(filepath.Ext(path)); {
+		case ext == ".flac":
+			hasFLAC = true
+		case slices.Contains(lossyExtensions, ext):
+			lossy = append(lossy, DisallowedFile{Path: rel, Reason: "lossy audio"})
+		case slices.Contains(archiveExtensions, ext):
+			found = append(found, DisallowedFile{Path: rel, Reason: "archive"})
+		case slices.Contains(executableExtensions, ext):
+			found = append(found, DisallowedFile{Path: rel, Reason: "executable"})
+		case ext == ".torrent":
+			found = append(found, DisallowedFile{Path: rel, Reason: "torrent file"})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if hasFLAC {
+		found = append(found, lossy...)
+	}
+	slices.SortFunc(found, func(a, b DisallowedFile) int { return strings.Compare(a.Path, b.Path) })
+	return found, nil
+}
@@ -0,0 +1,53 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+func TestFindDisallowedFiles(t *testing.T) {
+	write := func(mem *fsys.Mem, paths ...string) {
+		for _, path := range paths {
+			mem.MkdirAll(filepath.Dir(path), 0755)
+			mem.WriteFile(path, []byte("x"), 0644)
+		}
+	}
+
+	mem := fsys.NewMem()
+	write(mem,
+		"/album/01 Allegro.flac",
+		"/album/01 Allegro.mp3",
+		"/album/folder.jpg",
+		"/album/Scans.zip",
+		"/album/Extras/player.EXE",
+		"/album/Extras/album.torrent",
+		"/album/DVD/VIDEO_TS/setup.exe",
+	)
+	got, err := FindDisallowedFiles(mem, "/album", []string{"DVD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []DisallowedFile{
+		{Path: "01 Allegro.mp3", Reason: "lossy audio"},
+		{Path: "Extras/album.torrent", Reason: "torrent file"},
+		{Path: "Extras/player.EXE", Reason: "executable"},
+		{Path: "Scans.zip", Reason: "archive"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindDisallowedFiles() = %v, want %v", got, want)
+	}
+
+	// A lossy-only album is not a mix
+	mem = fsys.NewMem()
+	write(mem, "/mp3/01.mp3", "/mp3/02.mp3")
+	got, err = FindDisallowedFiles(mem, "/mp3", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("FindDisallowedFiles() = %v, want none for an MP3-only album", got)
+	}
+}
@@ -0,0 +1,137 @@
+package filesystem
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+// FolderKind classifies a folder at the root of an album by what it holds.
+type FolderKind int
+
+const (
+	// FolderOther holds neither audio nor video (scans, booklets, logs).
+	FolderOther FolderKind = iota
+	// FolderAudio holds audio files: a disc folder of the album itself.
+	FolderAudio
+	// FolderExtras holds video or a disc image and no audio, such as a bonus DVD.
+	FolderExtras
+)
+
+// String returns the kind's name as shown to users.
+func (k FolderKind) String() string {
+	switch k {
+	case FolderAudio:
+		return "audio"
+	case FolderExtras:
+		return "extras"
+	default:
+		return "other"
+	}
+}
+
+// audioExtensions lists the audio file extensions that make a folder part of the album.
+var audioExtensions = []string{".flac", ".dsf", ".dff", ".wav", ".mp3", ".m4a", ".ape"}
+
+// videoExtensions lists video and disc image file extensions.
+var videoExtensions = []string{
+	".vob", ".ifo", ".bup", ".m2ts", ".mts", ".mkv", ".mp4", ".m4v", ".avi", ".mpg", ".mpeg", ".mov", ".iso",
+}
+
+// videoStructureFolders are the folders of an authored DVD or Blu-ray.
+var videoStructureFolders = []string{"video_ts", "audio_ts", "bdmv", "certificate"}
+
+// IsVideoFile reports whether path names a video or disc image file.
+func IsVideoFile(path string) bool {
+	return hasExtension(path, videoExtensions)
+}
+
+// isAudioFile reports whether path names an audio file.
+func isAudioFile(path string) bool {
+	return hasExtension(path, audioExtensions)
+}
+
+func hasExtension(path string, extensions []string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// ClassifyFolders classifies each top-level folder among paths, which are
+// relative to the album root. A folder holding any audio is FolderAudio even
+// if it also holds video; otherwise video files or a VIDEO_TS/BDMV structure
+// make it FolderExtras. Files at the root are not classified.
+func ClassifyFolders(paths []string) map[string]FolderKind {
+	kinds := make(map[string]FolderKind)
+	for _, path := range paths {
+		components := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+		if len(components) < 2 {
+			continue
+		}
+		folder := components[0]
+		kind := kinds[folder]
+		switch {
+		case isAudioFile(path):
+			kind = FolderAudio
+		case kind == FolderAudio:
+		case IsVideoFile(path) || isVideoStructure(components[1:len(components)-1]):
+			kind = FolderExtras
+		}
+		kinds[folder] = kind
+	}
+	return kinds
+}
+
+// isVideoStructure reports whether any of dirs is a DVD or Blu-ray structure folder.
+func isVideoStructure(dirs []string) bool {
+	for _, dir := range dirs {
+		for _, name := range videoStructureFolders {
+			if strings.EqualFold(dir, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExtrasFolders returns the sorted names of the top-level folders among paths
+// classified as FolderExtras.
+func ExtrasFolders(paths []string) []string {
+	var extras []string
+	for folder, kind := range ClassifyFolders(paths) {
+		if kind == FolderExtras {
+			extras = append(extras, folder)
+		}
+	}
+	sort.Strings(extras)
+	return extras
+}
+
+// ScanExtrasFolders walks root and returns the names of its top-level extras folders.
+func ScanExtrasFolders(files fsys.FS, root string) ([]string, error) {
+	var paths []string
+	err := fsys.Or(files).WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ExtrasFolders(paths), nil
+}
@@ -0,0 +1,57 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+func TestClassifyFolders(t *testing.T) {
+	paths := []string{
+		"folder.jpg",
+		"CD1/01 Allegro.flac",
+		"CD2/01 Adagio.flac",
+		"CD2/booklet.pdf",
+		"Bonus DVD/VIDEO_TS/VTS_01_1.VOB",
+		"Bonus DVD/VIDEO_TS/VIDEO_TS.IFO",
+		"Blu-ray/BDMV/index.bdmv",
+		"Documentary/interview.mkv",
+		"Scans/front.jpg",
+		"Mixed/01 Allegro.flac",
+		"Mixed/clip.mp4",
+	}
+	want := map[string]FolderKind{
+		"CD1":         FolderAudio,
+		"CD2":         FolderAudio,
+		"Bonus DVD":   FolderExtras,
+		"Blu-ray":     FolderExtras,
+		"Documentary": FolderExtras,
+		"Scans":       FolderOther,
+		"Mixed":       FolderAudio,
+	}
+	if got := ClassifyFolders(paths); !reflect.DeepEqual(got, want) {
+		t.Errorf("ClassifyFolders() = %v, want %v", got, want)
+	}
+}
+
+func TestScanExtrasFolders(t *testing.T) {
+	mem := fsys.NewMem()
+	for _, path := range []string{
+		"/album/CD1/01 Allegro.flac",
+		"/album/DVD/VIDEO_TS/VTS_01_1.VOB",
+		"/album/Video/concert.m2ts",
+	} {
+		mem.MkdirAll(filepath.Dir(path), 0755)
+		mem.WriteFile(path, []byte("x"), 0644)
+	}
+
+	got, err := ScanExtrasFolders(mem, "/album")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"DVD", "Video"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanExtrasFolders() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,26 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MoveFiles moves files, given relative to srcDir, to the same relative paths
+// in destDir, creating it and any subdirectories. It stops at the first file
+// that cannot be moved; the files already moved stay moved.
+func MoveFiles(srcDir, destDir string, files []string) error {
+	for _, file := range files {
+		dest := filepath.Join(destDir, file)
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+		}
+		if _, err := os.Lstat(dest); err == nil {
+			return fmt.Errorf("cannot move %s: %s already exists", file, dest)
+		}
+		if err := os.Rename(filepath.Join(srcDir, file), dest); err != nil {
+			return fmt.Errorf("failed to move %s: %w", file, err)
+		}
+	}
+	return nil
+}
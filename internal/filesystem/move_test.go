@@ -0,0 +1,40 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMoveFiles(t *testing.T) {
+	base := t.TempDir()
+	src := filepath.Join(base, "Merged")
+	for _, file := range []string{"01 Aria.flac", filepath.Join("CD2", "01 Prelude.flac"), "folder.jpg"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(src, file)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(src, file), []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dest := filepath.Join(base, "Bach - Cello Suites")
+	if err := MoveFiles(src, dest, []string{"01 Aria.flac", filepath.Join("CD2", "01 Prelude.flac")}); err != nil {
+		t.Fatalf("MoveFiles() error = %v", err)
+	}
+	for _, file := range []string{"01 Aria.flac", filepath.Join("CD2", "01 Prelude.flac")} {
+		if _, err := os.Stat(filepath.Join(dest, file)); err != nil {
+			t.Errorf("%s not moved: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(src, "folder.jpg")); err != nil {
+		t.Errorf("unlisted file moved: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(src, "01 Aria.flac"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := MoveFiles(src, dest, []string{"01 Aria.flac"}); err == nil {
+		t.Error("MoveFiles() over an existing file, want error")
+	}
+}
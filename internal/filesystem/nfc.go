@@ -0,0 +1,59 @@
+package filesystem
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+// Rename is a file or directory renamed from one path to another.
+type Rename struct {
+	From string
+	To   string
+}
+
+// RenameToNFC renames root and every file and directory under it whose name
+// is not in canonical composed form (NFC), as macOS rips often are. Trackers and
+// tags use NFC, so decomposed (NFD) names fail to match their titles and
+// display inconsistently. Entries are renamed deepest first, so each rename's
+// From is valid when it happens. With dryRun the renames are only reported.
+func RenameToNFC(files fsys.FS, root string, dryRun bool) ([]Rename, error) {
+	files = fsys.Or(files)
+
+	var paths []string
+	err := files.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !normalize.IsNFC(d.Name()) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var renames []Rename
+	for i := len(paths) - 1; i >= 0; i-- {
+		from := paths[i]
+		to := filepath.Join(filepath.Dir(from), normalize.NFC(filepath.Base(from)))
+		if !dryRun {
+			// Normalization-insensitive file systems (APFS) find the same file under both names
+			if existing, err := files.Stat(to); err == nil {
+				if current, err := files.Stat(from); err != nil || !os.SameFile(existing, current) {
+					return renames, fmt.Errorf("cannot rename %s to NFC: %s already exists", from, to)
+				}
+			}
+			if err := files.Rename(from, to); err != nil {
+				return renames, err
+			}
+		}
+		renames = append(renames, Rename{From: from, To: to})
+	}
+	return renames, nil
+}
@@ -0,0 +1,48 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+func TestRenameToNFC(t *testing.T) {
+	nfd := "Dvor\u030ca\u0301k" // "Dvořák", decomposed
+	mem := fsys.NewMem()
+	root := "/music/" + nfd + " - Symphony No. 9"
+	mem.MkdirAll(filepath.Join(root, "CD1"), 0755)
+	mem.WriteFile(filepath.Join(root, "CD1", "01 - Adagio – Allegro molto.flac"), nil, 0644)
+	mem.WriteFile(filepath.Join(root, "CD1", "02 - Le\u0301gende.flac"), nil, 0644)
+
+	renames, err := RenameToNFC(mem, root, true)
+	if err != nil {
+		t.Fatalf("RenameToNFC(dry run) error = %v", err)
+	}
+	if len(renames) != 2 {
+		t.Fatalf("renames = %v, want the folder and the second track", renames)
+	}
+	if _, err := mem.Stat(root); err != nil {
+		t.Errorf("dry run renamed %s", root)
+	}
+
+	renames, err = RenameToNFC(mem, root, false)
+	if err != nil {
+		t.Fatalf("RenameToNFC() error = %v", err)
+	}
+	wantRoot := normalize.NFC(root)
+	if last := renames[len(renames)-1]; last.From != root || last.To != wantRoot {
+		t.Errorf("last rename = %v, want the album folder, renamed after its contents", last)
+	}
+	if _, err := mem.Stat(filepath.Join(wantRoot, "CD1", "02 - Légende.flac")); err != nil {
+		t.Errorf("renamed track not found: %v", err)
+	}
+
+	// A composed twin of a decomposed name (possible on Linux) is not overwritten
+	mem.WriteFile(filepath.Join(wantRoot, "CD1", "03 - E\u0301tude.flac"), nil, 0644)
+	mem.WriteFile(filepath.Join(wantRoot, "CD1", "03 - Étude.flac"), nil, 0644)
+	if _, err := RenameToNFC(mem, wantRoot, false); err == nil {
+		t.Error("RenameToNFC() over an existing file succeeded, want an error")
+	}
+}
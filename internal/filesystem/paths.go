@@ -0,0 +1,24 @@
+package filesystem
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// IsWithin reports whether path is dir or lies below it, comparing absolute,
+// cleaned paths (symlinks are not resolved).
+func IsWithin(path, dir string) bool {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
@@ -0,0 +1,22 @@
+package filesystem
+
+import "testing"
+
+func TestIsWithin(t *testing.T) {
+	tests := []struct {
+		Path, Dir string
+		Want      bool
+	}{
+		{"/music/Album", "/music/Album", true},
+		{"/music/Album/work", "/music/Album", true},
+		{"/music/Album/../Other", "/music/Album", false},
+		{"/music/Album (FLAC)", "/music/Album", false},
+		{"/music", "/music/Album", false},
+		{"/music/Album/..work", "/music/Album", true},
+	}
+	for _, tt := range tests {
+		if got := IsWithin(tt.Path, tt.Dir); got != tt.Want {
+			t.Errorf("IsWithin(%q, %q) = %v, want %v", tt.Path, tt.Dir, got, tt.Want)
+		}
+	}
+}
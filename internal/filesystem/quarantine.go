@@ -0,0 +1,64 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// QuarantineReportSuffix is appended to an album's name for the validation
+// report written beside it in quarantine.
+const QuarantineReportSuffix = ".validation.txt"
+
+// Quarantine moves albumDir into quarantineDir, or with link leaves it in place
+// and symlinks it there, and writes report beside it as the album's name plus
+// QuarantineReportSuffix. The report stays outside the album so it is never
+// packaged with it. It returns the album's path in quarantine; an album of the
+// same name already quarantined is an error. An album on another filesystem
+// than quarantineDir is copied there, then removed.
+func Quarantine(albumDir, quarantineDir, report string, link bool) (string, error) {
+	albumDir, err := filepath.Abs(albumDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(quarantineDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create quarantine directory: %w", err)
+	}
+	dest := filepath.Join(quarantineDir, filepath.Base(albumDir))
+	if _, err := os.Lstat(dest); err == nil {
+		return "", fmt.Errorf("cannot quarantine %s: %s already exists", albumDir, dest)
+	}
+
+	if link {
+		err = os.Symlink(albumDir, dest)
+	} else {
+		err = move(albumDir, dest)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to quarantine %s: %w", albumDir, err)
+	}
+	if err := os.WriteFile(dest+QuarantineReportSuffix, []byte(report), 0644); err != nil {
+		return dest, fmt.Errorf("failed to write quarantine report: %w", err)
+	}
+	return dest, nil
+}
+
+// rename is os.Rename, replaced in tests to simulate another filesystem.
+var rename = os.Rename
+
+// move renames dir to dest, or when they are on different filesystems (which
+// rename cannot cross) copies dir to dest and removes it. A failed copy is
+// removed again, leaving dir as it was.
+func move(dir, dest string) error {
+	err := rename(dir, dest)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := os.CopyFS(dest, os.DirFS(dir)); err != nil {
+		os.RemoveAll(dest)
+		return fmt.Errorf("copying across filesystems failed (-quarantine-link leaves albums in place): %w", err)
+	}
+	return os.RemoveAll(dir)
+}
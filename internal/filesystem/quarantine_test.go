@@ -0,0 +1,94 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestQuarantine(t *testing.T) {
+	base := t.TempDir()
+	quarantine := filepath.Join(base, "quarantine")
+	album := filepath.Join(base, "incoming", "Bach - Cantatas")
+	if err := os.MkdirAll(album, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(album, "01.flac"), []byte("flac"), 0644)
+
+	dest, err := Quarantine(album, quarantine, "Validation issues (1):\n", false)
+	if err != nil {
+		t.Fatalf("Quarantine() error = %v", err)
+	}
+	if dest != filepath.Join(quarantine, "Bach - Cantatas") {
+		t.Errorf("Quarantine() = %q, want the album in the quarantine directory", dest)
+	}
+	if _, err := os.Stat(album); !os.IsNotExist(err) {
+		t.Errorf("album still at %s after moving", album)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "01.flac")); err != nil {
+		t.Errorf("moved album lost its files: %v", err)
+	}
+	if report, err := os.ReadFile(dest + QuarantineReportSuffix); err != nil || string(report) != "Validation issues (1):\n" {
+		t.Errorf("report = %q (err %v), want it beside the album", report, err)
+	}
+
+	// A second album of the same name is refused and left in place
+	if err := os.MkdirAll(album, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Quarantine(album, quarantine, "", false); err == nil {
+		t.Error("Quarantine() over an existing album, want error")
+	}
+	if _, err := os.Stat(album); err != nil {
+		t.Errorf("refused album moved: %v", err)
+	}
+}
+
+func TestQuarantine_OtherFilesystem(t *testing.T) {
+	rename = func(from, to string) error {
+		return &os.LinkError{Op: "rename", Old: from, New: to, Err: syscall.EXDEV}
+	}
+	defer func() { rename = os.Rename }()
+
+	base := t.TempDir()
+	album := filepath.Join(base, "incoming", "Bach - Cantatas")
+	if err := os.MkdirAll(filepath.Join(album, "Scans"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(album, "01.flac"), []byte("flac"), 0644)
+	os.WriteFile(filepath.Join(album, "Scans", "front.jpg"), []byte("jpeg"), 0644)
+
+	// The album is copied across and removed
+	dest, err := Quarantine(album, filepath.Join(base, "quarantine"), "report", false)
+	if err != nil {
+		t.Fatalf("Quarantine() error = %v", err)
+	}
+	if _, err := os.Stat(album); !os.IsNotExist(err) {
+		t.Errorf("album still at %s after moving", album)
+	}
+	for _, name := range []string{"01.flac", filepath.Join("Scans", "front.jpg")} {
+		if _, err := os.Stat(filepath.Join(dest, name)); err != nil {
+			t.Errorf("moved album lost %s: %v", name, err)
+		}
+	}
+}
+
+func TestQuarantine_Link(t *testing.T) {
+	base := t.TempDir()
+	album := filepath.Join(base, "Mahler - Symphony No. 2")
+	if err := os.MkdirAll(album, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	dest, err := Quarantine(album, filepath.Join(base, "quarantine"), "report", true)
+	if err != nil {
+		t.Fatalf("Quarantine(link) error = %v", err)
+	}
+	if target, err := os.Readlink(dest); err != nil || target != album {
+		t.Errorf("link target = %q (err %v), want %q", target, err, album)
+	}
+	if _, err := os.Stat(album); err != nil {
+		t.Errorf("linked album moved: %v", err)
+	}
+}
@@ -0,0 +1,443 @@
+package fsys
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
+)
+
+var (
+	errNotDir   = errors.New("not a directory")
+	errIsDir    = errors.New("is a directory")
+	errNotEmpty = errors.New("directory not empty")
+)
+
+// Mem is an in-memory FS for tests. Like the operating system it requires
+// parent directories to exist; the root and current directories always do.
+// Modification times come from Clock, so cache expiry can be tested with a
+// clock.Fake.
+type Mem struct {
+	Clock clock.Clock // Modification times (nil: clock.System)
+
+	mu    sync.Mutex
+	nodes map[string]*memNode
+	temps int
+}
+
+type memNode struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+	dir     bool
+}
+
+// NewMem returns an empty in-memory file system.
+func NewMem() *Mem {
+	return &Mem{}
+}
+
+// node returns the node at the cleaned path, creating the implicit root and
+// current directories. Callers hold m.mu.
+func (m *Mem) node(name string) *memNode {
+	if m.nodes == nil {
+		m.nodes = make(map[string]*memNode)
+	}
+	if n, ok := m.nodes[name]; ok {
+		return n
+	}
+	if name == "." || name == string(filepath.Separator) {
+		n := &memNode{mode: fs.ModeDir | 0755, dir: true}
+		m.nodes[name] = n
+		return n
+	}
+	return nil
+}
+
+// checkParent returns an error unless the directory holding name exists.
+func (m *Mem) checkParent(op, name string) error {
+	if p := m.node(filepath.Dir(name)); p == nil {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	} else if !p.dir {
+		return &fs.PathError{Op: op, Path: name, Err: errNotDir}
+	}
+	return nil
+}
+
+func (m *Mem) now() time.Time {
+	return clock.Or(m.Clock).Now()
+}
+
+// within reports whether name is dir or inside it.
+func within(name, dir string) bool {
+	if dir == "." {
+		return !filepath.IsAbs(name)
+	}
+	return name == dir || strings.HasPrefix(name, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+func (m *Mem) Open(name string) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clean := filepath.Clean(name)
+	n := m.node(clean)
+	if n == nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &memFile{fs: m, name: name, node: n}, nil
+}
+
+func (m *Mem) Create(name string) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.create("open", name, 0666)
+}
+
+// create makes an empty file at name, replacing any file there. Callers hold m.mu.
+func (m *Mem) create(op, name string, perm fs.FileMode) (File, error) {
+	clean := filepath.Clean(name)
+	if err := m.checkParent(op, clean); err != nil {
+		return nil, err
+	}
+	if n := m.node(clean); n != nil && n.dir {
+		return nil, &fs.PathError{Op: op, Path: name, Err: errIsDir}
+	}
+	n := &memNode{mode: perm, modTime: m.now()}
+	m.nodes[clean] = n
+	return &memFile{fs: m, name: name, node: n, writable: true}, nil
+}
+
+func (m *Mem) CreateTemp(dir, pattern string) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if dir == "" {
+		dir = "."
+	}
+	for {
+		m.temps++
+		random := strconv.Itoa(m.temps)
+		name := pattern + random
+		if i := strings.LastIndex(pattern, "*"); i >= 0 {
+			name = pattern[:i] + random + pattern[i+1:]
+		}
+		name = filepath.Join(dir, name)
+		if m.node(filepath.Clean(name)) == nil {
+			return m.create("createtemp", name, 0600)
+		}
+	}
+}
+
+func (m *Mem) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := m.node(filepath.Clean(name))
+	switch {
+	case n == nil:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	case n.dir:
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errIsDir}
+	}
+	return slices.Clone(n.data), nil
+}
+
+func (m *Mem) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, err := m.create("open", name, perm)
+	if err != nil {
+		return err
+	}
+	f.(*memFile).node.data = slices.Clone(data)
+	return nil
+}
+
+func (m *Mem) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := m.node(filepath.Clean(name))
+	if n == nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return n.info(filepath.Base(name)), nil
+}
+
+func (m *Mem) MkdirAll(path string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clean := filepath.Clean(path)
+	var missing []string
+	for dir := clean; ; dir = filepath.Dir(dir) {
+		if n := m.node(dir); n != nil {
+			if !n.dir {
+				return &fs.PathError{Op: "mkdir", Path: path, Err: errNotDir}
+			}
+			break
+		}
+		missing = append(missing, dir)
+	}
+	for _, dir := range slices.Backward(missing) {
+		m.nodes[dir] = &memNode{mode: fs.ModeDir | perm, modTime: m.now(), dir: true}
+	}
+	return nil
+}
+
+func (m *Mem) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clean := filepath.Clean(name)
+	n := m.node(clean)
+	if n == nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if n.dir {
+		for other := range m.nodes {
+			if other != clean && within(other, clean) {
+				return &fs.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+			}
+		}
+	}
+	delete(m.nodes, clean)
+	return nil
+}
+
+func (m *Mem) RemoveAll(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clean := filepath.Clean(path)
+	for name := range m.nodes {
+		if within(name, clean) {
+			delete(m.nodes, name)
+		}
+	}
+	return nil
+}
+
+func (m *Mem) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	from, to := filepath.Clean(oldpath), filepath.Clean(newpath)
+	n := m.node(from)
+	if n == nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: fs.ErrNotExist}
+	}
+	if err := m.checkParent("rename", to); err != nil {
+		return err
+	}
+	if existing := m.node(to); existing != nil && existing.dir != n.dir {
+		return &fs.PathError{Op: "rename", Path: newpath, Err: errIsDir}
+	}
+	moved := make(map[string]*memNode)
+	for name, node := range m.nodes {
+		if within(name, from) {
+			moved[filepath.Join(to, relative(name, from))] = node
+			delete(m.nodes, name)
+		}
+	}
+	for name, node := range moved {
+		m.nodes[name] = node
+	}
+	return nil
+}
+
+// WalkDir walks the tree at root like filepath.WalkDir: depth first, each
+// directory's entries in lexical order.
+func (m *Mem) WalkDir(root string, fn fs.WalkDirFunc) error {
+	m.mu.Lock()
+	clean := filepath.Clean(root)
+	if m.node(clean) == nil {
+		m.mu.Unlock()
+		err := fn(root, nil, &fs.PathError{Op: "lstat", Path: root, Err: fs.ErrNotExist})
+		if errors.Is(err, fs.SkipDir) || errors.Is(err, fs.SkipAll) {
+			return nil
+		}
+		return err
+	}
+	type entry struct {
+		path string
+		info fs.FileInfo
+	}
+	var entries []entry
+	for name, n := range m.nodes {
+		if within(name, clean) {
+			path := root
+			if name != clean {
+				path = filepath.Join(root, relative(name, clean))
+			}
+			entries = append(entries, entry{path, n.info(filepath.Base(name))})
+		}
+	}
+	m.mu.Unlock()
+
+	// Comparing path elements orders each directory's entries before the next sibling
+	sep := string(filepath.Separator)
+	slices.SortFunc(entries, func(a, b entry) int {
+		return strings.Compare(strings.ReplaceAll(a.path, sep, "\x00"), strings.ReplaceAll(b.path, sep, "\x00"))
+	})
+	var skipped []string
+	for _, e := range entries {
+		if slices.ContainsFunc(skipped, func(dir string) bool { return within(filepath.Clean(e.path), dir) }) {
+			continue
+		}
+		err := fn(e.path, fs.FileInfoToDirEntry(e.info), nil)
+		switch {
+		case errors.Is(err, fs.SkipAll):
+			return nil
+		case errors.Is(err, fs.SkipDir) && e.info.IsDir():
+			skipped = append(skipped, filepath.Clean(e.path))
+		case errors.Is(err, fs.SkipDir):
+			skipped = append(skipped, filepath.Dir(filepath.Clean(e.path)))
+		case err != nil:
+			return err
+		}
+	}
+	return nil
+}
+
+// relative returns name, which is inside dir, relative to dir.
+func relative(name, dir string) string {
+	if dir == "." {
+		return name
+	}
+	return strings.TrimPrefix(name[len(dir):], string(filepath.Separator))
+}
+
+func (n *memNode) info(name string) fs.FileInfo {
+	return memInfo{name: name, size: int64(len(n.data)), mode: n.mode, modTime: n.modTime}
+}
+
+type memInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) Mode() fs.FileMode  { return i.mode }
+func (i memInfo) ModTime() time.Time { return i.modTime }
+func (i memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memInfo) Sys() any           { return nil }
+
+// memFile is an open Mem file. Files opened with Open are read-only.
+type memFile struct {
+	fs       *Mem
+	name     string
+	node     *memNode
+	offset   int64
+	writable bool
+	closed   bool
+}
+
+func (f *memFile) check(op string) error {
+	if f.closed {
+		return &fs.PathError{Op: op, Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.node.dir && op != "close" && op != "stat" {
+		return &fs.PathError{Op: op, Path: f.name, Err: errIsDir}
+	}
+	return nil
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	n, err := f.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("read"); err != nil {
+		return 0, err
+	}
+	if off >= int64(len(f.node.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.node.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("write"); err != nil {
+		return 0, err
+	}
+	if !f.writable {
+		return 0, &fs.PathError{Op: "write", Path: f.name, Err: fs.ErrPermission}
+	}
+	if end := f.offset + int64(len(p)); end > int64(len(f.node.data)) {
+		f.node.data = append(f.node.data, make([]byte, end-int64(len(f.node.data)))...)
+	}
+	copy(f.node.data[f.offset:], p)
+	f.offset += int64(len(p))
+	f.node.modTime = f.fs.now()
+	return len(p), nil
+}
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("seek"); err != nil {
+		return 0, err
+	}
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.node.data))
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *memFile) Close() error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("close"); err != nil {
+		return err
+	}
+	f.closed = true
+	return nil
+}
+
+func (f *memFile) Name() string {
+	return f.name
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("stat"); err != nil {
+		return nil, err
+	}
+	return f.node.info(filepath.Base(f.name)), nil
+}
+
+func (f *memFile) Chmod(mode fs.FileMode) error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("chmod"); err != nil {
+		return err
+	}
+	f.node.mode = f.node.mode&fs.ModeType | mode.Perm()
+	return nil
+}
@@ -0,0 +1,97 @@
+package fsys
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
+)
+
+func TestMem_Files(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := &Mem{Clock: clock.NewFake(start)}
+
+	if err := m.WriteFile("/album/01.flac", []byte("x"), 0644); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("WriteFile() without parent error = %v, want ErrNotExist", err)
+	}
+	if err := m.MkdirAll("/album/CD1", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.WriteFile("/album/CD1/01.flac", []byte("fLaC"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := m.Open("/album/CD1/01.flac")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(f)
+	f.Close()
+	if string(data) != "fLaC" {
+		t.Errorf("read %q, want fLaC", data)
+	}
+	if _, err := f.Write([]byte("x")); err == nil {
+		t.Error("Write() to a file opened read-only should fail")
+	}
+
+	info, err := m.Stat("/album/CD1/01.flac")
+	if err != nil || info.Size() != 4 || !info.ModTime().Equal(start) || info.IsDir() {
+		t.Errorf("Stat() = %v, %v", info, err)
+	}
+
+	tmp, err := m.CreateTemp("/album", ".01.flac.*.tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp.Write([]byte("tagged"))
+	tmp.Close()
+	if err := m.Rename(tmp.Name(), "/album/CD1/01.flac"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := m.ReadFile("/album/CD1/01.flac"); string(data) != "tagged" {
+		t.Errorf("renamed file holds %q", data)
+	}
+
+	if err := m.Remove("/album"); err == nil {
+		t.Error("Remove() of a non-empty directory should fail")
+	}
+	if err := m.RemoveAll("/album"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Stat("/album/CD1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat() after RemoveAll error = %v", err)
+	}
+}
+
+func TestMem_WalkDir(t *testing.T) {
+	m := NewMem()
+	m.MkdirAll("album/CD1", 0755)
+	m.MkdirAll("album/CD2", 0755)
+	for _, name := range []string{"album/CD1/01.flac", "album/CD1/02.flac", "album/CD2/01.flac", "album/cover.jpg", "album/CD10.txt"} {
+		m.WriteFile(name, nil, 0644)
+	}
+
+	var walked []string
+	err := m.WalkDir("album", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, filepath.ToSlash(path))
+		if d.IsDir() && d.Name() == "CD2" {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"album", "album/CD1", "album/CD1/01.flac", "album/CD1/02.flac", "album/CD10.txt", "album/CD2", "album/cover.jpg"}
+	if !slices.Equal(walked, want) {
+		t.Errorf("WalkDir() visited %v, want %v", walked, want)
+	}
+}
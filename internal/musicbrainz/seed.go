@@ -0,0 +1,271 @@
+// Package musicbrainz prepares corrected album metadata for submission back to
+// MusicBrainz: release editor seed fields for a new release, and an edit note
+// suggesting the work and performer relationships, which the release editor
+// cannot seed.
+package musicbrainz
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// BaseURL is the MusicBrainz server the seed page submits to.
+const BaseURL = "https://musicbrainz.org"
+
+// Field is one release editor seed field. The editor reads fields by name, so
+// their order only matters to readers.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// mediumFormats maps site media to MusicBrainz medium formats.
+var mediumFormats = map[string]string{
+	"CD": "CD", "SACD": "SACD", "DVD": "DVD-Audio", "Blu-Ray": "Blu-ray",
+	"Vinyl": "Vinyl", "Cassette": "Cassette", "WEB": "Digital Media",
+}
+
+// Seed returns the release editor fields that add t as a new release, with
+// editNote as the edit note. Track artists follow the classical style
+// guideline: the composers, then the performers.
+func Seed(t *domain.Torrent, sources []string, editNote string) []Field {
+	var fields []Field
+	add := func(name, value string) {
+		if value != "" {
+			fields = append(fields, Field{name, value})
+		}
+	}
+
+	add("name", t.Title)
+	add("type", "album")
+	add("status", "official")
+	addCredit(add, "artist_credit", t.AlbumArtist)
+	if e := t.Edition; e != nil {
+		if e.Year > 0 {
+			add("date.year", strconv.Itoa(e.Year))
+		}
+		add("labels.0.name", e.Label)
+		add("labels.0.catalog_number", e.CatalogNumber)
+		add("barcode", e.Barcode)
+	}
+
+	format := ""
+	if t.SiteMetadata != nil {
+		format = mediumFormats[t.SiteMetadata.Media]
+	}
+	for i, disc := range discs(t) {
+		prefix := fmt.Sprintf("mediums.%d", i)
+		add(prefix+".format", format)
+		add(prefix+".name", disc[0].DiscSubtitle)
+		for j, track := range disc {
+			trackPrefix := fmt.Sprintf("%s.track.%d", prefix, j)
+			add(trackPrefix+".number", strconv.Itoa(track.Track))
+			add(trackPrefix+".name", track.Title)
+			if track.Duration > 0 {
+				add(trackPrefix+".length", strconv.FormatInt(track.Duration.Milliseconds(), 10))
+			}
+			addCredit(add, trackPrefix+".artist_credit", trackCredit(track))
+		}
+	}
+
+	for i, source := range sources {
+		add(fmt.Sprintf("urls.%d.url", i), source)
+	}
+	add("edit_note", editNote)
+	return fields
+}
+
+// addCredit adds the artist credit fields under prefix, joining the names
+// with ", " and the last with " & ", or "; " between composers and performers.
+func addCredit(add func(name, value string), prefix string, artists []domain.Artist) {
+	for i, a := range artists {
+		name := fmt.Sprintf("%s.names.%d", prefix, i)
+		add(name+".name", a.Name)
+		add(name+".artist.name", a.Name)
+		switch {
+		case i == len(artists)-1:
+		case a.Role == domain.RoleComposer && artists[i+1].Role != domain.RoleComposer:
+			add(name+".join_phrase", "; ")
+		case i == len(artists)-2:
+			add(name+".join_phrase", " & ")
+		default:
+			add(name+".join_phrase", ", ")
+		}
+	}
+}
+
+// trackCredit returns the track's composers, then its performers.
+func trackCredit(track *domain.Track) []domain.Artist {
+	var composers, performers []domain.Artist
+	for _, a := range track.Artists {
+		switch {
+		case a.Role == domain.RoleComposer:
+			composers = append(composers, a)
+		case a.Role.IsPerformer():
+			performers = append(performers, a)
+		}
+	}
+	return append(composers, performers...)
+}
+
+// discs groups the tracks by disc, in order.
+func discs(t *domain.Torrent) [][]*domain.Track {
+	var discs [][]*domain.Track
+	for _, track := range t.Tracks() {
+		if n := len(discs); n > 0 && discs[n-1][0].Disc == track.Disc {
+			discs[n-1] = append(discs[n-1], track)
+			continue
+		}
+		discs = append(discs, []*domain.Track{track})
+	}
+	return discs
+}
+
+// Relationships suggests the relationships to add once the release exists,
+// one per line: each work's composer and the recordings performing it, and
+// each performer's role on the recordings, e.g. "Martha Argerich: instrument
+// (piano) on tracks 5-7".
+func Relationships(t *domain.Torrent) []string {
+	multiDisc := t.IsMultiDisc()
+	var lines []string
+	for _, w := range t.Works() {
+		line := fmt.Sprintf("Work %q:", w.Title)
+		if w.Composer != "" {
+			line += " composer " + w.Composer + ";"
+		}
+		lines = append(lines, fmt.Sprintf("%s recordings of %s are performances of it", line, trackList(w.Tracks, multiDisc)))
+	}
+
+	// Each artist's relationship, with the tracks it holds on, in order of appearance
+	type credit struct{ name, relationship string }
+	var order []credit
+	tracks := make(map[credit][]*domain.Track)
+	for _, track := range t.Tracks() {
+		for _, a := range track.Artists {
+			relationship := relationshipType(a)
+			if relationship == "" {
+				continue
+			}
+			c := credit{a.Name, relationship}
+			if _, ok := tracks[c]; !ok {
+				order = append(order, c)
+			}
+			tracks[c] = append(tracks[c], track)
+		}
+	}
+	for _, c := range order {
+		lines = append(lines, fmt.Sprintf("%s: %s on %s", c.name, c.relationship, trackList(tracks[c], multiDisc)))
+	}
+	return lines
+}
+
+// relationshipType names the recording relationship for a performer or
+// arranger; composers are credited on the work and others are left out.
+func relationshipType(a domain.Artist) string {
+	switch a.Role {
+	case domain.RoleConductor:
+		return "conductor"
+	case domain.RoleEnsemble:
+		if a.EnsembleScale() == domain.EnsembleOrchestral {
+			return "orchestra"
+		}
+		return "performer"
+	case domain.RoleArranger:
+		return "arranger"
+	case domain.RoleSoloist, domain.RolePerformer, domain.RoleGuest:
+		switch {
+		case a.Instrument == "":
+			return "performer"
+		case domain.IsVoice(a.Instrument):
+			return "vocal (" + a.Instrument + ")"
+		default:
+			return "instrument (" + a.Instrument + ")"
+		}
+	}
+	return ""
+}
+
+// trackList names tracks compactly, collapsing runs: "track 3", "tracks 1-4,
+// 7", or "tracks 1.1-1.4, 2.3" on multi-disc albums.
+func trackList(tracks []*domain.Track, multiDisc bool) string {
+	number := func(t *domain.Track) string {
+		if multiDisc {
+			return fmt.Sprintf("%d.%d", t.Disc, t.Track)
+		}
+		return strconv.Itoa(t.Track)
+	}
+	var runs []string
+	for i := 0; i < len(tracks); {
+		j := i
+		for j+1 < len(tracks) && tracks[j+1].Disc == tracks[i].Disc && tracks[j+1].Track == tracks[j].Track+1 {
+			j++
+		}
+		if j > i {
+			runs = append(runs, number(tracks[i])+"-"+number(tracks[j]))
+		} else {
+			runs = append(runs, number(tracks[i]))
+		}
+		i = j + 1
+	}
+	if len(tracks) == 1 {
+		return "track " + runs[0]
+	}
+	return "tracks " + strings.Join(runs, ", ")
+}
+
+// EditNote returns the edit note for submitting t: where the data comes
+// from, and the relationships to add once the release exists.
+func EditNote(t *domain.Torrent, sources []string) string {
+	var b strings.Builder
+	b.WriteString("Corrected metadata of a tagged rip.\n")
+	if len(sources) > 0 {
+		b.WriteString("\nChecked against:\n")
+		for _, s := range sources {
+			fmt.Fprintf(&b, "  %s\n", s)
+		}
+	}
+	if lines := Relationships(t); len(lines) > 0 {
+		b.WriteString("\nRelationships to add:\n")
+		for _, line := range lines {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// SeedPage returns an HTML page for submitting t to MusicBrainz. Without a
+// release MBID it holds a form that opens the release editor seeded with t;
+// with one, the release exists, so it links to its editors instead. Either
+// way the edit note follows, to paste into the relationship editor.
+func SeedPage(t *domain.Torrent, sources []string) string {
+	note := EditNote(t, sources)
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s - MusicBrainz submission</title>\n</head>\n<body>\n", html.EscapeString(t.Title))
+	fmt.Fprintf(&b, "<h1>%s</h1>\n", html.EscapeString(t.Title))
+	if mbid := releaseMBID(t); mbid != "" {
+		release := BaseURL + "/release/" + mbid
+		fmt.Fprintf(&b, "<p>The release is already in MusicBrainz: <a href=\"%s\">%s</a>.</p>\n", release, release)
+		fmt.Fprintf(&b, "<p><a href=\"%s/edit\">Edit the release</a> or <a href=\"%s/edit-relationships\">its relationships</a> with the edit note below.</p>\n", release, release)
+	} else {
+		fmt.Fprintf(&b, "<form action=\"%s/release/add\" method=\"post\" accept-charset=\"utf-8\">\n", BaseURL)
+		for _, f := range Seed(t, sources, note) {
+			fmt.Fprintf(&b, "<input type=\"hidden\" name=\"%s\" value=\"%s\">\n", html.EscapeString(f.Name), html.EscapeString(f.Value))
+		}
+		b.WriteString("<p>Review every tab of the release editor before entering the edit.</p>\n")
+		b.WriteString("<button type=\"submit\">Open the MusicBrainz release editor</button>\n</form>\n")
+	}
+	fmt.Fprintf(&b, "<h2>Edit note</h2>\n<pre>%s</pre>\n</body>\n</html>\n", html.EscapeString(note))
+	return b.String()
+}
+
+// releaseMBID returns the MusicBrainz release ID of t's edition, or "".
+func releaseMBID(t *domain.Torrent) string {
+	if t.Edition == nil {
+		return ""
+	}
+	return strings.TrimSpace(t.Edition.MusicBrainzAlbumID)
+}
@@ -0,0 +1,134 @@
+package musicbrainz
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func seedTorrent() *domain.Torrent {
+	beethoven := domain.Artist{Name: "Ludwig van Beethoven", Role: domain.RoleComposer}
+	karajan := domain.Artist{Name: "Herbert von Karajan", Role: domain.RoleConductor}
+	bpo := domain.Artist{Name: "Berliner Philharmoniker", Role: domain.RoleEnsemble}
+	t := &domain.Torrent{
+		Title:        "Symphony No. 5 & Piano Concerto No. 1",
+		AlbumArtist:  []domain.Artist{karajan, bpo},
+		Edition:      &domain.Edition{Label: "Deutsche Grammophon", CatalogNumber: "447 400-2", Barcode: "028944740027", Year: 1995},
+		SiteMetadata: &domain.SiteMetadata{Media: "CD"},
+	}
+	titles := []string{
+		"Symphony No. 5 in C minor, Op. 67: I. Allegro con brio",
+		"Symphony No. 5 in C minor, Op. 67: II. Andante con moto",
+		"Piano Concerto No. 1 in C major, Op. 15: I. Allegro con brio",
+		"Piano Concerto No. 1 in C major, Op. 15: II. Largo",
+	}
+	for i, title := range titles {
+		track := &domain.Track{Disc: 1, Track: i + 1, Title: title, Duration: 90 * time.Second,
+			Artists: []domain.Artist{beethoven, karajan, bpo}}
+		if i >= 2 {
+			track.Artists = append(track.Artists, domain.Artist{Name: "Martha Argerich", Role: domain.RoleSoloist, Instrument: "piano"})
+		}
+		t.Files = append(t.Files, track)
+	}
+	return t
+}
+
+func TestSeed(t *testing.T) {
+	fields := Seed(seedTorrent(), []string{"https://www.discogs.com/release/123"}, "note")
+	values := make(map[string]string)
+	for _, f := range fields {
+		values[f.Name] = f.Value
+	}
+
+	want := map[string]string{
+		"name":                              "Symphony No. 5 & Piano Concerto No. 1",
+		"artist_credit.names.0.name":        "Herbert von Karajan",
+		"artist_credit.names.0.join_phrase": " & ",
+		"artist_credit.names.1.name":        "Berliner Philharmoniker",
+		"date.year":                         "1995",
+		"labels.0.name":                     "Deutsche Grammophon",
+		"labels.0.catalog_number":           "447 400-2",
+		"barcode":                           "028944740027",
+		"mediums.0.format":                  "CD",
+		"mediums.0.track.2.number":          "3",
+		"mediums.0.track.2.name":            "Piano Concerto No. 1 in C major, Op. 15: I. Allegro con brio",
+		"mediums.0.track.2.length":          "90000",
+		// The composer, then the performers
+		"mediums.0.track.2.artist_credit.names.0.name":        "Ludwig van Beethoven",
+		"mediums.0.track.2.artist_credit.names.0.join_phrase": "; ",
+		"mediums.0.track.2.artist_credit.names.1.join_phrase": ", ",
+		"mediums.0.track.2.artist_credit.names.2.join_phrase": " & ",
+		"mediums.0.track.2.artist_credit.names.3.name":        "Martha Argerich",
+		"urls.0.url": "https://www.discogs.com/release/123",
+		"edit_note":  "note",
+	}
+	for name, value := range want {
+		if values[name] != value {
+			t.Errorf("%s = %q, want %q", name, values[name], value)
+		}
+	}
+	if _, ok := values["mediums.0.track.2.artist_credit.names.3.join_phrase"]; ok {
+		t.Error("the last artist has a join phrase")
+	}
+}
+
+func TestRelationships(t *testing.T) {
+	want := []string{
+		`Work "Symphony No. 5 in C minor, Op. 67": composer Ludwig van Beethoven; recordings of tracks 1-2 are performances of it`,
+		`Work "Piano Concerto No. 1 in C major, Op. 15": composer Ludwig van Beethoven; recordings of tracks 3-4 are performances of it`,
+		"Herbert von Karajan: conductor on tracks 1-4",
+		"Berliner Philharmoniker: orchestra on tracks 1-4",
+		"Martha Argerich: instrument (piano) on tracks 3-4",
+	}
+	if got := Relationships(seedTorrent()); !slices.Equal(got, want) {
+		t.Errorf("Relationships() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestTrackList(t *testing.T) {
+	tracks := func(numbers ...[2]int) []*domain.Track {
+		var list []*domain.Track
+		for _, n := range numbers {
+			list = append(list, &domain.Track{Disc: n[0], Track: n[1]})
+		}
+		return list
+	}
+	tests := []struct {
+		tracks    []*domain.Track
+		multiDisc bool
+		want      string
+	}{
+		{tracks([2]int{1, 3}), false, "track 3"},
+		{tracks([2]int{1, 1}, [2]int{1, 2}, [2]int{1, 4}), false, "tracks 1-2, 4"},
+		{tracks([2]int{1, 9}, [2]int{1, 10}, [2]int{2, 1}), true, "tracks 1.9-1.10, 2.1"},
+	}
+	for _, tt := range tests {
+		if got := trackList(tt.tracks, tt.multiDisc); got != tt.want {
+			t.Errorf("trackList() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestSeedPage(t *testing.T) {
+	torrent := seedTorrent()
+	page := SeedPage(torrent, nil)
+	for _, want := range []string{
+		`<form action="https://musicbrainz.org/release/add" method="post"`,
+		`name="name" value="Symphony No. 5 &amp; Piano Concerto No. 1"`,
+		"Martha Argerich: instrument (piano) on tracks 3-4",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+
+	// A release already in MusicBrainz is edited, not added again
+	torrent.Edition.MusicBrainzAlbumID = "f1b9e5b0-6f4d-4b8e-9c5e-2f2b5d0c7a11"
+	page = SeedPage(torrent, nil)
+	if strings.Contains(page, "<form") || !strings.Contains(page, "/release/f1b9e5b0-6f4d-4b8e-9c5e-2f2b5d0c7a11/edit-relationships") {
+		t.Errorf("page for a known release should link to its editors:\n%s", page)
+	}
+}
@@ -0,0 +1,124 @@
+// Package normalize provides the Unicode-aware string keys used wherever names
+// and titles are compared, so "Dvořák" typed on a Mac (decomposed) and on Linux
+// (precomposed) compare equal everywhere.
+package normalize
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Options controls how aggressively Key normalizes a string.
+type Options uint8
+
+const (
+	// FoldDiacritics strips combining marks after compatibility decomposition,
+	// so "Dvořák" and "Dvorak" share a key.
+	FoldDiacritics Options = 1 << iota
+	// LettersAndDigits drops spaces and punctuation entirely, so
+	// "Jean-Guihen Queyras" and "Jean Guihen Queyras" share a key.
+	LettersAndDigits
+)
+
+// NFC returns s in canonical composed form.
+func NFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// IsNFC reports whether s is in canonical composed form. ASCII strings are.
+func IsNFC(s string) bool {
+	return norm.NFC.IsNormalString(s)
+}
+
+// Fold removes diacritics: s is decomposed with NFKD, combining marks are
+// dropped and the result is recomposed with NFC. Letters without a
+// decomposition (ø, ł, ß) are left as they are.
+func Fold(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+// Key returns a comparison key for s: NFC-normalized, lowercased, trimmed, with
+// runs of whitespace collapsed to a single space, further reduced by opts.
+// Keys are for comparison only and should never be written back to metadata.
+func Key(s string, opts Options) string {
+	// Lowercase on both sides of normalization: compatibility decomposition can
+	// produce capitals (🄵 -> F) and lowercasing can produce decomposable runes.
+	if opts&FoldDiacritics != 0 {
+		s = Fold(strings.ToLower(Fold(s)))
+	} else {
+		s = NFC(strings.ToLower(NFC(s)))
+	}
+
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r):
+			space = opts&LettersAndDigits == 0
+		case opts&LettersAndDigits == 0:
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Name is the key used to match artist names across sources: case- and
+// diacritic-insensitive, but spacing and punctuation still count.
+func Name(s string) string {
+	return Key(s, FoldDiacritics)
+}
+
+// Compact is the loosest key: lowercase letters and digits only, with
+// diacritics kept (use Key with FoldDiacritics|LettersAndDigits to drop them).
+func Compact(s string) string {
+	return Key(s, LettersAndDigits)
+}
+
+// Equal reports whether a and b have the same key under opts.
+func Equal(a, b string, opts Options) bool {
+	return Key(a, opts) == Key(b, opts)
+}
+
+// letterFolds spells out letters Fold leaves alone because they have no
+// decomposition.
+var letterFolds = strings.NewReplacer("ø", "o", "æ", "ae", "œ", "oe", "ł", "l", "ß", "ss", "đ", "d", "ı", "i")
+
+// transliterations collapse the spellings romanizations of one name differ by
+// ("Tchaikovsky", "Tschaikowsky"; "Rachmaninoff", "Rachmaninov"; "Yevgeny", "Evgeny").
+// Applied in order to a Compact key with diacritics folded.
+var transliterations = []struct{ from, to string }{
+	{"tsch", "ch"}, {"tch", "ch"}, {"sch", "sh"}, {"kh", "h"}, {"ck", "k"}, {"ph", "f"},
+	{"w", "v"}, {"ff", "v"}, {"ye", "e"}, {"ij", "i"}, {"iy", "i"}, {"yi", "i"}, {"y", "i"}, {"ii", "i"},
+	{"ou", "u"}, {"cz", "ch"},
+}
+
+// Transliteration is a key for names that may be romanized differently:
+// diacritics are folded and common transliteration differences collapsed, so
+// "Gennady Rozhdestvensky" and "Gennadi Rozhdestvenskiy" share a key. It is
+// loose enough to match unrelated names occasionally, so use it to flag
+// possible duplicates for a person to check, never to merge them.
+func Transliteration(s string) string {
+	key := letterFolds.Replace(Key(s, FoldDiacritics|LettersAndDigits))
+	for _, t := range transliterations {
+		key = strings.ReplaceAll(key, t.from, t.to)
+	}
+	return key
+}
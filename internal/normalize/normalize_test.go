@@ -0,0 +1,126 @@
+package normalize
+
+import (
+	"strings"
+	"testing"
+	"testing/quick"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		Name string
+		In   string
+		Opts Options
+		Want string
+	}{
+		{"case and spacing", "  Berlin   Philharmonic ", 0, "berlin philharmonic"},
+		{"decomposed input", "Dvořák", 0, "dvořák"},
+		{"keeps diacritics", "Dvořák", 0, "dvořák"},
+		{"folds diacritics", "Antonín Dvořák", FoldDiacritics, "antonin dvorak"},
+		{"fold leaves undecomposable letters", "Søren Łukasz", FoldDiacritics, "søren łukasz"},
+		{"keeps punctuation", "Jean-Guihen Queyras", 0, "jean-guihen queyras"},
+		{"letters and digits", "Jean-Guihen Queyras", LettersAndDigits, "jeanguihenqueyras"},
+		{"compatibility forms", "Symphony No. ²", FoldDiacritics | LettersAndDigits, "symphonyno2"},
+		{"empty", "", FoldDiacritics, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := Key(tt.In, tt.Opts); got != tt.Want {
+				t.Errorf("Key(%q, %d) = %q, want %q", tt.In, tt.Opts, got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestNameAndCompact(t *testing.T) {
+	if !Equal("ANTONÍN DVOŘÁK", "Antonin Dvorak", FoldDiacritics) {
+		t.Error("Name should ignore case and diacritics")
+	}
+	if Name("Jean-Guihen Queyras") == Name("Jean Guihen Queyras") {
+		t.Error("Name should keep punctuation")
+	}
+	if Compact("Jean-Guihen Queyras") != Compact("jean guihen queyras") {
+		t.Error("Compact should ignore punctuation and spacing")
+	}
+	if Compact("Dvořák") == Compact("Dvorak") {
+		t.Error("Compact should keep diacritics")
+	}
+}
+
+var allOptions = []Options{0, FoldDiacritics, LettersAndDigits, FoldDiacritics | LettersAndDigits}
+
+// Applying Key to its own output changes nothing.
+func TestKey_Idempotent(t *testing.T) {
+	for _, opts := range allOptions {
+		f := func(s string) bool {
+			k := Key(s, opts)
+			return Key(k, opts) == k
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Errorf("opts %d: %v", opts, err)
+		}
+	}
+}
+
+// Composed and decomposed spellings of the same text share a key.
+func TestKey_NormalizationFormInvariant(t *testing.T) {
+	for _, opts := range allOptions {
+		f := func(s string) bool {
+			return Key(norm.NFC.String(s), opts) == Key(norm.NFD.String(s), opts)
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Errorf("opts %d: %v", opts, err)
+		}
+	}
+}
+
+// Keys are lowercase, trimmed and never contain runs of whitespace.
+func TestKey_Shape(t *testing.T) {
+	f := func(s string) bool {
+		k := Key(s, 0)
+		return k == strings.TrimSpace(k) && !strings.Contains(k, "  ") && k == strings.ToLower(k)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// Folded strings contain no combining marks and fold to themselves.
+func TestFold_NoMarks(t *testing.T) {
+	f := func(s string) bool {
+		folded := Fold(s)
+		for _, r := range folded {
+			if unicode.Is(unicode.Mn, r) {
+				return false
+			}
+		}
+		return Fold(folded) == folded
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTransliteration(t *testing.T) {
+	same := [][2]string{
+		{"Gennady Rozhdestvensky", "Gennadi Rozhdestvenskiy"},
+		{"Tschaikowsky", "Tchaikovsky"},
+		{"Sergei Rachmaninoff", "Sergey Rachmaninov"},
+		{"Mstislav Rostropovich", "Mstislaw Rostropowitsch"},
+		{"Evgeny Kissin", "Yevgeny Kissin"},
+		{"Leif Ove Andsnes", "Leif Ove Andsnes"},
+		{"Søren Kierkegaard", "Soren Kierkegaard"},
+	}
+	for _, pair := range same {
+		if Transliteration(pair[0]) != Transliteration(pair[1]) {
+			t.Errorf("Transliteration(%q) = %q, Transliteration(%q) = %q; want equal", pair[0], Transliteration(pair[0]), pair[1], Transliteration(pair[1]))
+		}
+	}
+	if Transliteration("Bach") == Transliteration("Bax") {
+		t.Error("Bach and Bax should differ")
+	}
+}
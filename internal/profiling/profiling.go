@@ -0,0 +1,60 @@
+// Package profiling writes the CPU and heap profiles requested with the
+// commands' -cpuprofile and -memprofile flags, for inspection with go tool pprof.
+package profiling
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+)
+
+// Start starts a CPU profile written to cpuPath, when set, and returns a
+// function that stops it and writes a heap profile to memPath, when set.
+// Commands exit with os.Exit from many places, so the returned function may be
+// called more than once (deferred and before exiting); only the first call acts.
+func Start(cpuPath, memPath string) (func() error, error) {
+	var cpu *os.File
+	if cpuPath != "" {
+		f, err := os.Create(cpuPath)
+		if err != nil {
+			return nil, fmt.Errorf("creating CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("starting CPU profile: %w", err)
+		}
+		cpu = f
+	}
+
+	var once sync.Once
+	var stopErr error
+	return func() error {
+		once.Do(func() {
+			if cpu != nil {
+				pprof.StopCPUProfile()
+				stopErr = cpu.Close()
+			}
+			if memPath != "" {
+				stopErr = errors.Join(stopErr, writeHeapProfile(memPath))
+			}
+		})
+		return stopErr
+	}, nil
+}
+
+// writeHeapProfile writes a heap profile of the live objects to path.
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating memory profile: %w", err)
+	}
+	runtime.GC() // Up-to-date statistics
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return fmt.Errorf("writing memory profile: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,44 @@
+package profiling
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	dir := t.TempDir()
+	cpuPath, memPath := filepath.Join(dir, "cpu.prof"), filepath.Join(dir, "mem.prof")
+
+	stop, err := Start(cpuPath, memPath)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("stop() error = %v", err)
+	}
+	// Later calls are no-ops, so commands can both defer and call it before exiting
+	if err := stop(); err != nil {
+		t.Errorf("second stop() error = %v", err)
+	}
+	for _, path := range []string{cpuPath, memPath} {
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s not written: %v", filepath.Base(path), err)
+		}
+	}
+
+	// Nothing requested: nothing written
+	stop, err = Start("", "")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Errorf("stop() error = %v", err)
+	}
+}
+
+func TestStart_BadPath(t *testing.T) {
+	if _, err := Start(filepath.Join(t.TempDir(), "missing", "cpu.prof"), ""); err == nil {
+		t.Error("Start() error = nil, want an error for an uncreatable file")
+	}
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRateLimited reports that a service refused a request with HTTP 429.
+type ErrRateLimited struct {
+	Service    string
+	RetryAfter time.Duration // 0 when the service did not say
+}
+
+func (e *ErrRateLimited) Error() string {
+	if e.RetryAfter <= 0 {
+		return fmt.Sprintf("rate limited by %s", e.Service)
+	}
+	return fmt.Sprintf("rate limited by %s, retry after %s", e.Service, e.RetryAfter)
+}
+
+// ParseRetryAfter parses a Retry-After header, given either in seconds or as an
+// HTTP date. Returns 0 for an empty, malformed or past value.
+func ParseRetryAfter(header string) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at).Round(time.Second), 0)
+	}
+	return 0
+}
@@ -0,0 +1,40 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{" 120 ", 2 * time.Minute},
+		{"-3", 0},
+		{"soon", 0},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		if got := ParseRetryAfter(tt.header); got != tt.want {
+			t.Errorf("ParseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := ParseRetryAfter(future); got < 58*time.Second || got > time.Minute {
+		t.Errorf("ParseRetryAfter(%q) = %v, want about a minute", future, got)
+	}
+}
+
+func TestErrRateLimited_Error(t *testing.T) {
+	if got := (&ErrRateLimited{Service: "Discogs"}).Error(); got != "rate limited by Discogs" {
+		t.Errorf("Error() = %q", got)
+	}
+	if got := (&ErrRateLimited{Service: "Redacted", RetryAfter: 5 * time.Second}).Error(); got != "rate limited by Redacted, retry after 5s" {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -3,19 +3,49 @@ package ratelimit
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
 )
 
+// maxSlowdown caps the adaptive slowdown at 8 times slower than configured.
+const maxSlowdown = 8
+
 // RateLimiter implements a leaky bucket rate limiter
 type RateLimiter struct {
 	capacity   int           // max tokens in bucket
 	refillRate time.Duration // time between token refills
 	tokens     int           // current tokens
 	lastRefill time.Time     // last refill timestamp
+	baseRefill time.Duration // configured refillRate, before any slowdown
+	slowdown   int           // refillRate multiplier, doubled by each 429
+	resumeAt   time.Time     // no tokens before this time (Retry-After)
+	stats      Stats
+	clock      clock.Clock
 	mu         sync.Mutex
 }
 
+// Stats summarizes a rate limiter's activity over a run.
+type Stats struct {
+	Requests    int           // Requests let through by Wait
+	Waits       int           // Requests that had to wait for a token
+	Waited      time.Duration // Total time requests spent waiting
+	RateLimited int           // 429 responses reported by OnRateLimited
+	Slowdown    int           // How many times slower than configured the limiter now runs
+}
+
+// String summarizes the stats, e.g. "spent 42s waiting on rate limits (12 of
+// 80 requests waited; 1 rate limited, slowed 2x)".
+func (s Stats) String() string {
+	summary := fmt.Sprintf("spent %s waiting on rate limits (%d of %d requests waited", s.Waited.Round(time.Second), s.Waits, s.Requests)
+	if s.RateLimited > 0 {
+		summary += fmt.Sprintf("; %d rate limited, slowed %dx", s.RateLimited, s.Slowdown)
+	}
+	return summary + ")"
+}
+
 // NewRateLimiter creates a new rate limiter
 // capacity is number of requests per interval
 func NewRateLimiter(capacity int, interval time.Duration) *RateLimiter {
@@ -24,16 +54,31 @@ func NewRateLimiter(capacity int, interval time.Duration) *RateLimiter {
 		refillRate: interval / time.Duration(capacity), // Per-token refill time
 		tokens:     capacity,
 		lastRefill: time.Now(),
+		baseRefill: interval / time.Duration(capacity),
+		slowdown:   1,
+		clock:      clock.System,
 	}
 }
 
+// SetClock makes the limiter pace requests by c, so tests can advance a
+// clock.Fake instead of sleeping. The bucket starts full at c's time.
+func (rl *RateLimiter) SetClock(c clock.Clock) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.clock = clock.Or(c)
+	rl.lastRefill = rl.clock.Now()
+	rl.resumeAt = time.Time{}
+}
+
 // Wait blocks until a token is available
 func (rl *RateLimiter) Wait(ctx context.Context) error {
+	start := rl.now()
+	waited := false
 	for {
 		rl.mu.Lock()
 		
 		// Refill tokens based on elapsed time
-		now := time.Now()
+		now := rl.clock.Now()
 		elapsed := now.Sub(rl.lastRefill)
 		tokensToAdd := int(elapsed / rl.refillRate)
 		if rl.tokens + tokensToAdd < rl.capacity {
@@ -45,21 +90,34 @@ func (rl *RateLimiter) Wait(ctx context.Context) error {
 		}
 		
 		// Check if we have a token available
-		if rl.tokens > 0 {
+		if rl.tokens > 0 && !now.Before(rl.resumeAt) {
 			rl.tokens--
+			rl.stats.Requests++
+			if waited {
+				rl.stats.Waits++
+				rl.stats.Waited += now.Sub(start)
+			}
 			rl.mu.Unlock()
 			return nil
 		}
 		
 		// Calculate wait time until next token
 		waitTime := rl.refillRate - now.Sub(rl.lastRefill)
+		if rl.tokens > 0 || rl.resumeAt.Sub(now) > waitTime {
+			waitTime = rl.resumeAt.Sub(now)
+		}
+		waited = true
 		rl.mu.Unlock()
 		
 		// Wait with context cancellation support
 		select {
-		case <-time.After(waitTime):
+		case <-rl.after(waitTime):
 			continue
 		case <-ctx.Done():
+			rl.mu.Lock()
+			rl.stats.Waits++
+			rl.stats.Waited += rl.clock.Now().Sub(start)
+			rl.mu.Unlock()
 			return ctx.Err()
 		}
 	}
@@ -71,5 +129,47 @@ func (rl *RateLimiter) OnResponse() {
 	defer rl.mu.Unlock()
 	// Update lastRefill based on when we receive the response
 	// This ensures rate limiting is based on actual response times
-	rl.lastRefill = time.Now()
-}
\ No newline at end of file
+	rl.lastRefill = rl.clock.Now()
+}
+
+// OnRateLimited records a 429 response and halves the request rate for the rest
+// of the run, down to maxSlowdown times slower than configured. No request is let
+// through before retryAfter has passed, when the server gave one.
+func (rl *RateLimiter) OnRateLimited(retryAfter time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.stats.RateLimited++
+	if rl.slowdown < maxSlowdown {
+		rl.slowdown *= 2
+		rl.refillRate = rl.baseRefill * time.Duration(rl.slowdown)
+	}
+	// The server saw a burst it did not accept: start refilling from empty
+	rl.tokens = 0
+	rl.lastRefill = rl.clock.Now()
+	if retryAfter > 0 {
+		rl.resumeAt = rl.lastRefill.Add(retryAfter)
+	}
+}
+
+// Stats returns the limiter's activity so far.
+func (rl *RateLimiter) Stats() Stats {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	stats := rl.stats
+	stats.Slowdown = rl.slowdown
+	return stats
+}
+
+// now returns the limiter's clock time.
+func (rl *RateLimiter) now() time.Time {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.clock.Now()
+}
+
+// after waits on the limiter's clock.
+func (rl *RateLimiter) after(d time.Duration) <-chan time.Time {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.clock.After(d)
+}
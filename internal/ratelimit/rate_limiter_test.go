@@ -3,8 +3,11 @@ package ratelimit
 
 import (
 	"context"
+	"runtime"
 	"testing"
 	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
 )
 
 func TestRateLimiter_BasicOperation(t *testing.T) {
@@ -163,4 +166,69 @@ func TestRateLimiter_MultipleOnResponseCalls(t *testing.T) {
 	if elapsed < 900*time.Millisecond || elapsed > 1100*time.Millisecond {
 		t.Errorf("expected wait of ~1 second from last OnResponse, got %v", elapsed)
 	}
-}
\ No newline at end of file
+}
+func TestRateLimiter_OnRateLimited(t *testing.T) {
+	limiter := NewRateLimiter(100, time.Second) // A token every 10ms
+	ctx := context.Background()
+
+	limiter.OnRateLimited(50 * time.Millisecond)
+	start := time.Now()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("request after a 429 waited %v, want at least the 50ms Retry-After", elapsed)
+	}
+
+	stats := limiter.Stats()
+	if stats.Requests != 1 || stats.Waits != 1 || stats.RateLimited != 1 || stats.Slowdown != 2 || stats.Waited < 45*time.Millisecond {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	for range 4 {
+		limiter.OnRateLimited(0)
+	}
+	if stats := limiter.Stats(); stats.Slowdown != maxSlowdown || stats.RateLimited != 5 {
+		t.Errorf("Stats() after 5 429s = %+v, want slowdown capped at %d", stats, maxSlowdown)
+	}
+	if limiter.refillRate != 80*time.Millisecond {
+		t.Errorf("refillRate = %v, want 80ms", limiter.refillRate)
+	}
+}
+
+func TestStats_String(t *testing.T) {
+	stats := Stats{Requests: 80, Waits: 12, Waited: 42*time.Second + 300*time.Millisecond, Slowdown: 1}
+	if got := stats.String(); got != "spent 42s waiting on rate limits (12 of 80 requests waited)" {
+		t.Errorf("String() = %q", got)
+	}
+	stats.RateLimited, stats.Slowdown = 1, 2
+	if got := stats.String(); got != "spent 42s waiting on rate limits (12 of 80 requests waited; 1 rate limited, slowed 2x)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestRateLimiter_FakeClock(t *testing.T) {
+	now := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := NewRateLimiter(2, 2*time.Second)
+	limiter.SetClock(now)
+	ctx := context.Background()
+
+	for range 2 {
+		if err := limiter.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	done := make(chan error)
+	go func() { done <- limiter.Wait(ctx) }()
+	for now.Waiters() == 0 {
+		runtime.Gosched() // Until the third request blocks on the clock
+	}
+	now.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if stats := limiter.Stats(); stats.Requests != 3 || stats.Waits != 1 || stats.Waited != time.Second {
+		t.Errorf("Stats() = %+v, want the third request to wait exactly 1s", stats)
+	}
+}
@@ -0,0 +1,173 @@
+// Package review collects low-confidence decisions made during a batch run so
+// they can be answered together at the end, instead of stopping at each album.
+package review
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
+)
+
+// Decision is a question about one album with a fixed set of answers.
+type Decision struct {
+	Album    string // Album directory, shown with the question
+	Kind     string // What is being decided, e.g. "release" or "role"
+	Question string
+	Choices  []string
+	// Apply records the chosen answer (an index into Choices), typically by
+	// rewriting the album's metadata JSON.
+	Apply func(choice int) error
+}
+
+// Queue holds decisions until Review is called.
+type Queue struct {
+	Clock     clock.Clock // Time source for the review time limit (nil: the system clock)
+	decisions []Decision
+}
+
+// Summary reports how a review went.
+type Summary struct {
+	Accepted   int
+	Skipped    int
+	Unreviewed []Decision // Not asked: time ran out, the reader was exhausted, or the user quit
+	Failed     []error    // Accepted answers whose Apply failed
+}
+
+// Add queues a decision.
+func (q *Queue) Add(d Decision) {
+	q.decisions = append(q.decisions, d)
+}
+
+// Len returns the number of queued decisions.
+func (q *Queue) Len() int {
+	return len(q.decisions)
+}
+
+// Review asks each queued decision on out, reading answers from in: a choice
+// number accepts it, an empty line or "s" skips it and "q" stops reviewing.
+// Once limit has passed (if positive) the question waiting for an answer and
+// the rest are left unreviewed, so an unattended batch does not wait forever.
+// The queue is emptied.
+func (q *Queue) Review(in io.Reader, out io.Writer, limit time.Duration) Summary {
+	c := clock.Or(q.Clock)
+	var timeout <-chan time.Time // nil, never ready, without a limit
+	if limit > 0 {
+		timeout = c.After(limit)
+	}
+	lines := newLineReader(in)
+
+	var summary Summary
+	decisions := q.decisions
+	q.decisions = nil
+	for i, d := range decisions {
+		choice, status := ask(lines, timeout, out, i+1, len(decisions), d)
+		if status == timedOut {
+			fmt.Fprintf(out, "\nReview time (%s) is up.\n", limit)
+		}
+		if status != answered {
+			summary.Unreviewed = decisions[i:]
+			break
+		}
+		if choice < 0 {
+			summary.Skipped++
+			continue
+		}
+		if err := d.Apply(choice); err != nil {
+			summary.Failed = append(summary.Failed, fmt.Errorf("%s: %w", d.Album, err))
+			continue
+		}
+		summary.Accepted++
+	}
+	return summary
+}
+
+// line is one line read from the review's input.
+type line struct {
+	text string
+	err  error
+}
+
+// lineReader reads lines from the review's input on a goroutine, so waiting
+// for an answer can be abandoned when time runs out. It reads only when asked,
+// never ahead, so a caller sharing in with the review loses no input to it.
+type lineReader struct {
+	reader  *bufio.Reader
+	lines   chan line
+	pending bool // A read was started and its line not yet taken
+}
+
+// newLineReader reads from in, sharing its buffer if in is a *bufio.Reader.
+func newLineReader(in io.Reader) *lineReader {
+	return &lineReader{reader: bufio.NewReader(in), lines: make(chan line, 1)}
+}
+
+// next starts reading a line, unless one is still pending, and returns the
+// channel it is delivered on. A read left pending when the review ends
+// finishes, and its line is dropped, once in delivers one.
+func (r *lineReader) next() <-chan line {
+	if !r.pending {
+		r.pending = true
+		go func() {
+			text, err := r.reader.ReadString('\n')
+			r.lines <- line{text, err}
+		}()
+	}
+	return r.lines
+}
+
+// askStatus is how asking a question ended.
+type askStatus int
+
+const (
+	answered askStatus = iota // A choice was made or the question skipped
+	stopped                   // The user quit or input ran out
+	timedOut                  // The review time limit passed
+)
+
+// ask prints d and reads an answer until it is valid. It returns the chosen
+// index, or -1 to skip, when answered.
+func ask(lines *lineReader, timeout <-chan time.Time, out io.Writer, n, total int, d Decision) (int, askStatus) {
+	// Time running out while the last answer was applied ends the review
+	select {
+	case <-timeout:
+		return 0, timedOut
+	default:
+	}
+
+	fmt.Fprintf(out, "\n[%d/%d] %s (%s)\n%s\n", n, total, d.Album, d.Kind, d.Question)
+	for i, choice := range d.Choices {
+		fmt.Fprintf(out, "  %d) %s\n", i+1, choice)
+	}
+	for {
+		fmt.Fprintf(out, "Choice [1-%d, Enter to skip, q to stop]: ", len(d.Choices))
+		var l line
+		select {
+		case l = <-lines.next():
+			lines.pending = false
+		case <-timeout:
+			return 0, timedOut
+		}
+		answer := strings.ToLower(strings.TrimSpace(l.text))
+		switch {
+		case answer == "q":
+			return 0, stopped
+		case answer == "" || answer == "s":
+			if l.err != nil {
+				return 0, stopped
+			}
+			return -1, answered
+		}
+		if choice, convErr := strconv.Atoi(answer); convErr == nil && choice >= 1 && choice <= len(d.Choices) {
+			return choice - 1, answered
+		}
+		if l.err != nil {
+			return 0, stopped
+		}
+		fmt.Fprintf(out, "Please enter a number from 1 to %d.\n", len(d.Choices))
+	}
+}
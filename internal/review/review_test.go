@@ -0,0 +1,131 @@
+package review
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
+)
+
+func TestQueue_Review(t *testing.T) {
+	var applied []string
+	decision := func(album string) Decision {
+		return Decision{
+			Album:    album,
+			Kind:     "release",
+			Question: "Which release?",
+			Choices:  []string{"first", "second"},
+			Apply: func(choice int) error {
+				if album == "broken" {
+					return errors.New("cannot write JSON")
+				}
+				applied = append(applied, album+"="+[]string{"first", "second"}[choice])
+				return nil
+			},
+		}
+	}
+
+	var q Queue
+	for _, album := range []string{"a", "b", "broken", "c", "d"} {
+		q.Add(decision(album))
+	}
+
+	// "x" and "3" are rejected and re-asked; "q" stops before d
+	summary := q.Review(strings.NewReader("2\n\nx\n3\n1\n1\nq\n"), io.Discard, 0)
+
+	if got, want := strings.Join(applied, ","), "a=second,c=first"; got != want {
+		t.Errorf("applied = %s, want %s", got, want)
+	}
+	if summary.Accepted != 2 || summary.Skipped != 1 || len(summary.Failed) != 1 || len(summary.Unreviewed) != 1 {
+		t.Errorf("summary = %d accepted, %d skipped, %d failed, %d unreviewed; want 2, 1, 1, 1",
+			summary.Accepted, summary.Skipped, len(summary.Failed), len(summary.Unreviewed))
+	}
+	if len(summary.Unreviewed) == 1 && summary.Unreviewed[0].Album != "d" {
+		t.Errorf("unreviewed = %s, want d", summary.Unreviewed[0].Album)
+	}
+	if q.Len() != 0 {
+		t.Errorf("queue still holds %d decisions", q.Len())
+	}
+}
+
+func TestQueue_ReviewTimeLimit(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	q := Queue{Clock: fake}
+	for _, album := range []string{"a", "b", "c"} {
+		q.Add(Decision{
+			Album:   album,
+			Choices: []string{"yes"},
+			Apply: func(int) error {
+				// Answering takes the user six minutes
+				fake.Advance(6 * time.Minute)
+				return nil
+			},
+		})
+	}
+
+	summary := q.Review(strings.NewReader("1\n1\n1\n"), io.Discard, 10*time.Minute)
+
+	if summary.Accepted != 2 {
+		t.Errorf("accepted = %d, want 2", summary.Accepted)
+	}
+	if len(summary.Unreviewed) != 1 || summary.Unreviewed[0].Album != "c" {
+		t.Errorf("unreviewed = %v, want [c]", summary.Unreviewed)
+	}
+}
+
+func TestQueue_ReviewTimeLimitWhileWaiting(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	q := Queue{Clock: fake}
+	applied := make(chan struct{})
+	q.Add(Decision{Album: "a", Choices: []string{"yes"}, Apply: func(int) error { close(applied); return nil }})
+	q.Add(Decision{Album: "b", Choices: []string{"yes"}, Apply: func(int) error { return nil }})
+
+	// The user answers the first question, then walks away
+	in, user := io.Pipe()
+	defer user.Close()
+	go user.Write([]byte("1\n"))
+
+	result := make(chan Summary)
+	go func() { result <- q.Review(in, io.Discard, 10*time.Minute) }()
+	<-applied
+	fake.Advance(10 * time.Minute)
+
+	select {
+	case summary := <-result:
+		if summary.Accepted != 1 || len(summary.Unreviewed) != 1 || summary.Unreviewed[0].Album != "b" {
+			t.Errorf("summary = %d accepted, unreviewed %v; want 1, [b]", summary.Accepted, summary.Unreviewed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Review() still waiting for an answer after the time limit")
+	}
+}
+
+func TestQueue_ReviewReadsNoFurther(t *testing.T) {
+	var q Queue
+	q.Add(Decision{Album: "a", Choices: []string{"yes"}, Apply: func(int) error { return nil }})
+
+	// Answers after the review's are left for the next reader of the input
+	in := bufio.NewReader(strings.NewReader("1\ny\n"))
+	if summary := q.Review(in, io.Discard, 0); summary.Accepted != 1 {
+		t.Errorf("accepted = %d, want 1", summary.Accepted)
+	}
+	if rest, _ := in.ReadString('\n'); rest != "y\n" {
+		t.Errorf("input left = %q, want %q", rest, "y\n")
+	}
+}
+
+func TestQueue_ReviewEndOfInput(t *testing.T) {
+	var q Queue
+	q.Add(Decision{Album: "a", Choices: []string{"yes"}, Apply: func(int) error { return nil }})
+	q.Add(Decision{Album: "b", Choices: []string{"yes"}, Apply: func(int) error { return nil }})
+
+	// An unattended run (stdin closed) leaves everything for later
+	summary := q.Review(strings.NewReader(""), io.Discard, 0)
+	if summary.Accepted != 0 || len(summary.Unreviewed) != 2 {
+		t.Errorf("summary = %d accepted, %d unreviewed; want 0, 2", summary.Accepted, len(summary.Unreviewed))
+	}
+}
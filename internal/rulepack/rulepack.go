@@ -0,0 +1,301 @@
+// Package rulepack shares the decisions a user has accumulated (protected
+// words, artist aliases and artist roles) as YAML packs other users can import.
+//
+// The user's own rules (config file and remembered roles) always win over
+// imported packs. Packs are applied in name order, and where two disagree the
+// first by name wins; every rule in effect records the pack it came from.
+package rulepack
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// Errors returned for packs that cannot be imported.
+var (
+	ErrInvalidPack = errors.New("invalid rule pack")
+	ErrPackExists  = errors.New("rule pack already imported")
+	ErrNoPack      = errors.New("no such rule pack")
+)
+
+// Pack is a shareable set of rules.
+type Pack struct {
+	Name           string            `yaml:"name"`
+	Description    string            `yaml:"description,omitempty"`
+	ProtectedWords []string          `yaml:"protected_words,omitempty"` // Words title-casing keeps as spelled
+	ArtistAliases  map[string]string `yaml:"artist_aliases,omitempty"`  // Variant spelling -> canonical spelling
+	ArtistRoles    map[string]string `yaml:"artist_roles,omitempty"`    // Artist name -> role ("soloist", "ensemble", ...)
+}
+
+// namePattern is what pack names may hold, as they become file names.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Parse parses and checks a pack: its name, and that every role is known.
+func Parse(data []byte) (*Pack, error) {
+	var pack Pack
+	if err := yaml.Unmarshal(data, &pack); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPack, err)
+	}
+	if err := pack.check(); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// ReadFile reads and parses the pack in path.
+func ReadFile(path string) (*Pack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pack, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return pack, nil
+}
+
+// check reports a pack that cannot be imported.
+func (p *Pack) check() error {
+	if !namePattern.MatchString(p.Name) {
+		return fmt.Errorf("%w: name %q must be lowercase letters, digits, '.', '-' or '_'", ErrInvalidPack, p.Name)
+	}
+	for name, role := range p.ArtistRoles {
+		if _, err := domain.ParseRole(role); err != nil {
+			return fmt.Errorf("%w: artist %q: %v", ErrInvalidPack, name, err)
+		}
+	}
+	return nil
+}
+
+// Size returns how many rules the pack holds.
+func (p *Pack) Size() int {
+	return len(p.ProtectedWords) + len(p.ArtistAliases) + len(p.ArtistRoles)
+}
+
+// Marshal returns the pack as YAML.
+func (p *Pack) Marshal() ([]byte, error) {
+	return yaml.Marshal(p)
+}
+
+// Dir returns the directory imported packs are kept in.
+func Dir() string {
+	return filepath.Join(state.Dir(), "rulepacks")
+}
+
+// Export builds a pack named name from the user's own rules: the config
+// file's protected words and artist aliases, and the artist roles remembered
+// from earlier runs. Rules imported from other packs are left out.
+func Export(name, description string) (*Pack, error) {
+	roles, err := state.LoadArtistRoles()
+	if err != nil {
+		return nil, err
+	}
+	pack := &Pack{
+		Name:           name,
+		Description:    description,
+		ProtectedWords: config.LoadProtectedWords(),
+		ArtistAliases:  config.LoadArtistAliases(),
+	}
+	if len(roles) > 0 {
+		pack.ArtistRoles = make(map[string]string, len(roles))
+		for artist, role := range roles {
+			pack.ArtistRoles[artist] = role.String()
+		}
+	}
+	if err := pack.check(); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// Install imports pack, keeping it in Dir under its name. A pack of the same
+// name is an error unless replace is set.
+func Install(pack *Pack, replace bool) error {
+	if err := pack.check(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return fmt.Errorf("failed to create rule pack directory: %w", err)
+	}
+	path := filepath.Join(Dir(), pack.Name+".yaml")
+	if _, err := os.Stat(path); err == nil && !replace {
+		return fmt.Errorf("%w: %s", ErrPackExists, pack.Name)
+	}
+	data, err := pack.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write rule pack: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the imported pack called name.
+func Remove(name string) error {
+	err := os.Remove(filepath.Join(Dir(), name+".yaml"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNoPack, name)
+	}
+	return err
+}
+
+// Installed returns the imported packs in name order, and an error for any
+// that cannot be read alongside the rest. No pack directory yet is not an error.
+func Installed() ([]*Pack, error) {
+	paths, err := filepath.Glob(filepath.Join(Dir(), "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var packs []*Pack
+	var errs []error
+	for _, path := range paths {
+		pack, err := ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		packs = append(packs, pack)
+	}
+	return packs, errors.Join(errs...)
+}
+
+// Kind is the kind of a rule.
+type Kind string
+
+const (
+	KindProtectedWord Kind = "protected word"
+	KindArtistAlias   Kind = "artist alias"
+	KindArtistRole    Kind = "artist role"
+)
+
+// Key identifies a rule: a protected word, or the artist an alias or role is for.
+type Key struct {
+	Kind Kind
+	Name string
+}
+
+// Own is the origin of the user's own rules.
+const Own = "own"
+
+// Conflict is a pack rule overridden by a rule from the user or an earlier pack.
+type Conflict struct {
+	Key      Key
+	Pack     string // Pack whose rule was dropped
+	Value    string // Its value
+	Kept     string // Value in effect
+	KeptFrom string // Origin of the value in effect
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %q: %s says %q, keeping %q from %s", c.Key.Kind, c.Key.Name, c.Pack, c.Value, c.Kept, c.KeptFrom)
+}
+
+// Rules are the rules in effect: the user's own merged with the imported packs.
+type Rules struct {
+	ProtectedWords []string
+	ArtistAliases  map[string]string
+	ArtistRoles    map[string]domain.Role
+	Origins        map[Key]string // Own, or the name of the pack each rule came from
+	Conflicts      []Conflict
+}
+
+// Load returns the rules in effect. A pack or role store that cannot be read
+// is returned as an error alongside the rules from everything else, so a
+// command can warn and carry on.
+func Load() (*Rules, error) {
+	var errs []error
+	own := &Pack{
+		ProtectedWords: config.LoadProtectedWords(),
+		ArtistAliases:  config.LoadArtistAliases(),
+	}
+	roles, err := state.LoadArtistRoles()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	packs, err := Installed()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	rules := Merge(own, roles, packs)
+	return rules, errors.Join(errs...)
+}
+
+// Merge merges the user's own protected words and aliases (own) and roles
+// with packs, applied in order.
+func Merge(own *Pack, roles map[string]domain.Role, packs []*Pack) *Rules {
+	rules := &Rules{
+		ArtistAliases: make(map[string]string),
+		ArtistRoles:   make(map[string]domain.Role),
+		Origins:       make(map[Key]string),
+	}
+	addWords := func(words []string, origin string) {
+		for _, word := range words {
+			key := Key{KindProtectedWord, strings.ToLower(word)}
+			if _, ok := rules.Origins[key]; ok {
+				continue
+			}
+			rules.Origins[key] = origin
+			rules.ProtectedWords = append(rules.ProtectedWords, word)
+		}
+	}
+	addAliases := func(aliases map[string]string, origin string) {
+		for _, variant := range slices.Sorted(maps.Keys(aliases)) {
+			key := Key{KindArtistAlias, variant}
+			if kept, ok := rules.ArtistAliases[variant]; ok {
+				if kept != aliases[variant] {
+					rules.Conflicts = append(rules.Conflicts, Conflict{key, origin, aliases[variant], kept, rules.Origins[key]})
+				}
+				continue
+			}
+			rules.ArtistAliases[variant] = aliases[variant]
+			rules.Origins[key] = origin
+		}
+	}
+	addRole := func(artist string, role domain.Role, origin string) {
+		key := Key{KindArtistRole, artist}
+		if kept, ok := rules.ArtistRoles[artist]; ok {
+			if kept != role {
+				rules.Conflicts = append(rules.Conflicts, Conflict{key, origin, role.String(), kept.String(), rules.Origins[key]})
+			}
+			return
+		}
+		rules.ArtistRoles[artist] = role
+		rules.Origins[key] = origin
+	}
+
+	if own != nil {
+		addWords(own.ProtectedWords, Own)
+		addAliases(own.ArtistAliases, Own)
+	}
+	for artist, role := range roles {
+		addRole(artist, role, Own)
+	}
+	for _, pack := range packs {
+		addWords(pack.ProtectedWords, pack.Name)
+		addAliases(pack.ArtistAliases, pack.Name)
+		for _, artist := range slices.Sorted(maps.Keys(pack.ArtistRoles)) {
+			role, err := domain.ParseRole(pack.ArtistRoles[artist])
+			if err != nil {
+				continue
+			}
+			addRole(artist, role, pack.Name)
+		}
+	}
+	return rules
+}
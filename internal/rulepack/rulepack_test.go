@@ -0,0 +1,152 @@
+package rulepack
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/state"
+)
+
+func TestParse(t *testing.T) {
+	pack, err := Parse([]byte(`name: baroque
+description: Period-instrument ensembles
+protected_words: [HIP, BWV]
+artist_aliases:
+  "Bach Collegium Japan": "Bach Collegium Japan"
+artist_roles:
+  "Il Giardino Armonico": ensemble
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if pack.Name != "baroque" || pack.Size() != 4 {
+		t.Errorf("Parse() = %+v, want baroque with 4 rules", pack)
+	}
+
+	for _, data := range []string{
+		"name: Baroque Pack",
+		"name: baroque\nartist_roles:\n  Someone: tenor-ish",
+		"name: [baroque",
+	} {
+		if _, err := Parse([]byte(data)); !errors.Is(err, ErrInvalidPack) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidPack", data, err)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	own := &Pack{
+		ProtectedWords: []string{"NHK"},
+		ArtistAliases:  map[string]string{"Karajan": "Herbert von Karajan"},
+	}
+	roles := map[string]domain.Role{"Martha Argerich": domain.RoleSoloist}
+	packs := []*Pack{
+		{
+			Name:           "a-baroque",
+			ProtectedWords: []string{"nhk", "HIP"},
+			ArtistAliases:  map[string]string{"Karajan": "H. von Karajan", "Gardiner": "John Eliot Gardiner"},
+			ArtistRoles:    map[string]string{"Martha Argerich": "performer", "Concerto Köln": "ensemble"},
+		},
+		{
+			Name:          "b-opera",
+			ArtistAliases: map[string]string{"Gardiner": "Sir John Eliot Gardiner"},
+			ArtistRoles:   map[string]string{"Concerto Köln": "ensemble"},
+		},
+	}
+
+	rules := Merge(own, roles, packs)
+	if !slices.Equal(rules.ProtectedWords, []string{"NHK", "HIP"}) {
+		t.Errorf("ProtectedWords = %v, want [NHK HIP]", rules.ProtectedWords)
+	}
+	if rules.ArtistAliases["Karajan"] != "Herbert von Karajan" || rules.ArtistAliases["Gardiner"] != "John Eliot Gardiner" {
+		t.Errorf("ArtistAliases = %v, want own and first pack's", rules.ArtistAliases)
+	}
+	if rules.ArtistRoles["Martha Argerich"] != domain.RoleSoloist || rules.ArtistRoles["Concerto Köln"] != domain.RoleEnsemble {
+		t.Errorf("ArtistRoles = %v", rules.ArtistRoles)
+	}
+	origins := map[Key]string{
+		{KindProtectedWord, "hip"}:          "a-baroque",
+		{KindArtistAlias, "Karajan"}:        Own,
+		{KindArtistAlias, "Gardiner"}:       "a-baroque",
+		{KindArtistRole, "Concerto Köln"}:   "a-baroque",
+		{KindArtistRole, "Martha Argerich"}: Own,
+	}
+	for key, want := range origins {
+		if got := rules.Origins[key]; got != want {
+			t.Errorf("Origins[%v] = %q, want %q", key, got, want)
+		}
+	}
+	// Agreeing rules are not conflicts
+	if len(rules.Conflicts) != 3 {
+		t.Errorf("Conflicts = %v, want Karajan, Argerich and Gardiner", rules.Conflicts)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	pack := &Pack{Name: "baroque", ProtectedWords: []string{"HIP"}}
+	if err := Install(pack, false); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if err := Install(pack, false); !errors.Is(err, ErrPackExists) {
+		t.Errorf("Install() again error = %v, want ErrPackExists", err)
+	}
+	if err := Install(&Pack{Name: "baroque", ProtectedWords: []string{"HIP", "BWV"}}, true); err != nil {
+		t.Errorf("Install(replace) error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(Dir(), "broken.yaml"), []byte("name: [x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	packs, err := Installed()
+	if err == nil {
+		t.Error("Installed() with a broken pack, want error")
+	}
+	if len(packs) != 1 || len(packs[0].ProtectedWords) != 2 {
+		t.Errorf("Installed() = %+v, want the replaced baroque pack", packs)
+	}
+
+	if err := Remove("baroque"); err != nil {
+		t.Errorf("Remove() error = %v", err)
+	}
+	if err := Remove("baroque"); !errors.Is(err, ErrNoPack) {
+		t.Errorf("Remove() again error = %v, want ErrNoPack", err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	if err := os.MkdirAll(filepath.Join(configHome, "classical-tagger"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "capitalization:\n  protected_words: [NHK]\nartists:\n  aliases:\n    Karajan: Herbert von Karajan\n"
+	if err := os.WriteFile(filepath.Join(configHome, "classical-tagger", "config.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.RememberArtistRole("Concerto Köln", domain.RoleEnsemble); err != nil {
+		t.Fatal(err)
+	}
+
+	pack, err := Export("mine", "")
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if pack.ArtistAliases["Karajan"] != "Herbert von Karajan" || pack.ArtistRoles["Concerto Köln"] != "ensemble" || !slices.Equal(pack.ProtectedWords, []string{"NHK"}) {
+		t.Errorf("Export() = %+v", pack)
+	}
+
+	data, err := pack.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back, err := Parse(data); err != nil || back.Size() != pack.Size() {
+		t.Errorf("Parse(Marshal()) = %+v, %v; want the exported pack", back, err)
+	}
+}
@@ -0,0 +1,143 @@
+// Package schema generates the JSON Schema of the metadata JSON from the domain
+// model, and checks documents against it, so editors can complete and check
+// metadata files as they are written.
+package schema
+
+//go:generate sh -c "go run ../../cmd/validate -print-schema > ../../docs/schema/metadata.schema.json"
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// Schema is a JSON Schema (draft 2020-12), limited to the keywords the
+// metadata format needs.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+var (
+	roleType     = reflect.TypeFor[domain.Role]()
+	durationType = reflect.TypeFor[time.Duration]()
+	fileLikeType = reflect.TypeFor[domain.FileLike]()
+)
+
+// Metadata returns the schema of the metadata JSON written by domain.Torrent.
+// Properties the encoder always writes are required, and unknown properties
+// are refused, catching misspelled keys.
+func Metadata() *Schema {
+	g := &generator{defs: make(map[string]*Schema)}
+	root := g.object(reflect.TypeFor[domain.Torrent]())
+	root.Properties["schema_version"] = &Schema{Type: "integer"}
+	root.Schema = "https://json-schema.org/draft/2020-12/schema"
+	root.Title = "classical-tagger album metadata"
+	root.Description = "Album metadata as written by extract and read by tag, validate and upload (schema_version " +
+		strconv.Itoa(domain.SchemaVersion) + ")"
+	root.Defs = g.defs
+	return root
+}
+
+// Generate returns the metadata schema as indented JSON.
+func Generate() ([]byte, error) {
+	data, err := json.MarshalIndent(Metadata(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// generator builds schemas for Go types, defining each struct once.
+type generator struct {
+	defs map[string]*Schema
+}
+
+// schema returns the schema of values of t as encoding/json writes them.
+func (g *generator) schema(t reflect.Type) *Schema {
+	switch {
+	case t == roleType:
+		roles := make([]string, 0, domain.RoleMax+1)
+		for r := domain.RoleUnknown; r <= domain.RoleMax; r++ {
+			roles = append(roles, r.String())
+		}
+		return &Schema{Type: "string", Enum: roles}
+	case t == durationType:
+		return &Schema{Type: "integer"}
+	case t == fileLikeType:
+		return &Schema{AnyOf: []*Schema{g.ref(reflect.TypeFor[domain.Track]()), g.ref(reflect.TypeFor[domain.File]())}}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return &Schema{AnyOf: []*Schema{g.schema(t.Elem()), {Type: "null"}}}
+	case reflect.Slice:
+		return &Schema{AnyOf: []*Schema{{Type: "array", Items: g.schema(t.Elem())}, {Type: "null"}}}
+	case reflect.Struct:
+		return g.ref(t)
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+	return &Schema{}
+}
+
+// ref defines struct type t under its name and refers to the definition.
+func (g *generator) ref(t reflect.Type) *Schema {
+	if _, ok := g.defs[t.Name()]; !ok {
+		g.defs[t.Name()] = nil // Reserved while its fields are generated
+		g.defs[t.Name()] = g.object(t)
+	}
+	return &Schema{Ref: "#/$defs/" + t.Name()}
+}
+
+// object returns the schema of struct type t, with the fields of embedded
+// structs inlined as encoding/json does.
+func (g *generator) object(t reflect.Type) *Schema {
+	closed := false
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: &closed}
+	var add func(t reflect.Type)
+	add = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if field.Anonymous && tag == "" {
+				add(field.Type)
+				continue
+			}
+			if !field.IsExported() || tag == "-" {
+				continue
+			}
+			name, options, _ := strings.Cut(tag, ",")
+			if name == "" {
+				name = field.Name
+			}
+			s.Properties[name] = g.schema(field.Type)
+			if !strings.Contains(options, "omitempty") {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+	add(t)
+	return s
+}
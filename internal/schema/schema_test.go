@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	shipped, err := os.ReadFile("../../docs/schema/metadata.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(shipped, generated) {
+		t.Error("docs/schema/metadata.schema.json is out of date with the domain model; run go generate ./internal/schema")
+	}
+}
+
+func TestValidate_WrittenMetadata(t *testing.T) {
+	torrent := &domain.Torrent{
+		RootPath:     "Bach - Cello Suites",
+		Title:        "Cello Suites",
+		OriginalYear: 1983,
+		Edition:      &domain.Edition{Label: "CBS", Year: 1983},
+		Files: []domain.FileLike{
+			&domain.Track{
+				File: domain.File{Path: "01 - Prelude.flac"}, Disc: 1, Track: 1, Title: "Suite No. 1: Prelude",
+				Artists:  []domain.Artist{{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}},
+				Duration: 150 * time.Second,
+			},
+			&domain.File{Path: "cover.jpg"},
+		},
+	}
+	data, err := json.Marshal(torrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs, err := Validate(data); err != nil || len(errs) != 0 {
+		t.Errorf("Validate(written metadata) = %v, %v; want no errors", errs, err)
+	}
+}
+
+func TestValidate_Errors(t *testing.T) {
+	data := []byte(`{
+		"schema_version": 1,
+		"root_path": "Album",
+		"title": "Album",
+		"original_year": "1983",
+		"compser": "Bach",
+		"edition": null,
+		"files": [
+			{"path": "01.flac", "disc": 1, "track": 1, "title": "Prelude", "artists": [{"name": "Bach", "role": "composr"}]},
+			{"disc": 1, "track": 2, "title": "Allemande", "artists": null}
+		]
+	}`)
+	errs, err := Validate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		`unknown property "compser"`,
+		`/files/0/artists/0/role: "composr" is not one of unknown, composer, conductor, ensemble, soloist, performer, guest, dj, producer, arranger, remixer`,
+		`/files/1: missing property "path"`,
+		`/original_year: want integer, got string`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Validate() =\n%q\nwant\n%q", got, want)
+	}
+
+	if _, err := Validate([]byte(`{"title": `)); err == nil {
+		t.Error("Validate(malformed) error = nil")
+	}
+}
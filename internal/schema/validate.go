@@ -0,0 +1,159 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Error is a place a document breaks the schema.
+type Error struct {
+	Path    string // JSON Pointer to the offending value, "" for the document
+	Message string
+}
+
+func (e Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks a metadata JSON document against the metadata schema. The
+// error is for data that is not JSON; it wraps a *json.SyntaxError.
+func Validate(data []byte) ([]Error, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Tell integers from other numbers
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	root := Metadata()
+	v := &validator{defs: root.Defs}
+	v.check(root, doc, "")
+	return v.errors, nil
+}
+
+// validator collects the errors of a document against a schema.
+type validator struct {
+	defs   map[string]*Schema
+	errors []Error
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	v.errors = append(v.errors, Error{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// check checks value, at path in the document, against s.
+func (v *validator) check(s *Schema, value any, path string) {
+	if s.Ref != "" {
+		v.check(v.defs[strings.TrimPrefix(s.Ref, "#/$defs/")], value, path)
+		return
+	}
+	if len(s.AnyOf) > 0 {
+		v.checkAnyOf(s.AnyOf, value, path)
+		return
+	}
+	if s.Type != "" && kind(value) != s.Type && !(s.Type == "number" && kind(value) == "integer") {
+		v.fail(path, "want %s, got %s", s.Type, kind(value))
+		return
+	}
+	if len(s.Enum) > 0 {
+		if text, _ := value.(string); !slices.Contains(s.Enum, text) {
+			v.fail(path, "%q is not one of %s", text, strings.Join(s.Enum, ", "))
+		}
+	}
+
+	switch value := value.(type) {
+	case []any:
+		if s.Items != nil {
+			for i, item := range value {
+				v.check(s.Items, item, path+"/"+strconv.Itoa(i))
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				v.fail(path, "missing property %q", name)
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			switch {
+			case ok:
+				v.check(property, value[name], path+"/"+escape(name))
+			case s.AdditionalProperties != nil && !*s.AdditionalProperties:
+				v.fail(path, "unknown property %q", name)
+			}
+		}
+	}
+}
+
+// checkAnyOf passes value that matches any of schemas, else reports the
+// errors of the closest match: the first with the fewest errors among those
+// of value's type.
+func (v *validator) checkAnyOf(schemas []*Schema, value any, path string) {
+	var closest []Error
+	closestTyped := false
+	for i, s := range schemas {
+		branch := &validator{defs: v.defs}
+		branch.check(s, value, path)
+		if len(branch.errors) == 0 {
+			return
+		}
+		typed := v.hasType(s, value)
+		if i == 0 || typed && !closestTyped || typed == closestTyped && len(branch.errors) < len(closest) {
+			closest, closestTyped = branch.errors, typed
+		}
+	}
+	v.errors = append(v.errors, closest...)
+}
+
+// hasType reports whether value is of a type s allows.
+func (v *validator) hasType(s *Schema, value any) bool {
+	if s.Ref != "" {
+		return v.hasType(v.defs[strings.TrimPrefix(s.Ref, "#/$defs/")], value)
+	}
+	for _, branch := range s.AnyOf {
+		if v.hasType(branch, value) {
+			return true
+		}
+	}
+	return len(s.AnyOf) == 0 && (s.Type == "" || s.Type == kind(value) || s.Type == "number" && kind(value) == "integer")
+}
+
+// kind returns the JSON Schema type of a value decoded with UseNumber.
+func kind(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// escape escapes a property name for a JSON Pointer.
+func escape(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,191 @@
+package scraping
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/tagging"
+)
+
+// CueTrack is a TRACK entry in a CUE sheet.
+type CueTrack struct {
+	Number    int
+	Title     string
+	Performer string
+	// Index00File/Index01File are the FILEs in effect at INDEX 00 (pregap start)
+	// and INDEX 01 (track start); Index00 is "" when the track has no pregap.
+	Index00, Index01         string
+	Index00File, Index01File string
+}
+
+// CueSheet is the subset of a CUE sheet needed to map files to tracks.
+type CueSheet struct {
+	Title     string
+	Performer string
+	Catalog   string            // CATALOG: the disc's UPC/EAN barcode
+	Remarks   map[string]string // REM lines by upper-case key, e.g. "LABEL", "CATALOGNUMBER"
+	Tracks    []CueTrack
+}
+
+// ParseCueSheet parses a CUE sheet. Unknown commands are ignored.
+func ParseCueSheet(r io.Reader) (*CueSheet, error) {
+	sheet := &CueSheet{Remarks: make(map[string]string)}
+	var current *CueTrack
+	file := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\uFEFF"))
+		command, args, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "FILE":
+			file = cueFileName(args)
+		case "CATALOG":
+			sheet.Catalog = strings.TrimSpace(args)
+		case "REM":
+			key, value, _ := strings.Cut(args, " ")
+			if current == nil && key != "" {
+				sheet.Remarks[strings.ToUpper(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+			}
+		case "TRACK":
+			fields := strings.Fields(args)
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("cue: TRACK without number")
+			}
+			n, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("cue: invalid track number %q", fields[0])
+			}
+			sheet.Tracks = append(sheet.Tracks, CueTrack{Number: n})
+			current = &sheet.Tracks[len(sheet.Tracks)-1]
+		case "TITLE", "PERFORMER":
+			value := strings.Trim(args, `"`)
+			switch {
+			case current == nil && strings.EqualFold(command, "TITLE"):
+				sheet.Title = value
+			case current == nil:
+				sheet.Performer = value
+			case strings.EqualFold(command, "TITLE"):
+				current.Title = value
+			default:
+				current.Performer = value
+			}
+		case "INDEX":
+			fields := strings.Fields(args)
+			if current == nil || len(fields) < 2 {
+				continue
+			}
+			switch fields[0] {
+			case "00", "0":
+				current.Index00, current.Index00File = fields[1], file
+			case "01", "1":
+				current.Index01, current.Index01File = fields[1], file
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cue: %w", err)
+	}
+	return sheet, nil
+}
+
+// cueFileName extracts the file name from FILE arguments (`"name.wav" WAVE`).
+func cueFileName(args string) string {
+	if strings.HasPrefix(args, `"`) {
+		if end := strings.Index(args[1:], `"`); end >= 0 {
+			return args[1 : end+1]
+		}
+	}
+	if i := strings.LastIndex(args, " "); i > 0 {
+		return args[:i]
+	}
+	return args
+}
+
+// HiddenTrack reports whether the disc has audio hidden in the pregap before track 1 (HTOA).
+// file is the pregap's own file when the rip split it out (as EAC does), or "" when the
+// pregap is part of track 1's file.
+func (c *CueSheet) HiddenTrack() (file string, ok bool) {
+	for _, t := range c.Tracks {
+		if t.Number != 1 {
+			continue
+		}
+		if t.Index00 != "" && t.Index00File != t.Index01File {
+			return t.Index00File, true
+		}
+		if t.Index01 != "" && t.Index01 != "00:00:00" {
+			return "", true
+		}
+		if t.Index00 != "" && t.Index00 != t.Index01 {
+			return "", true
+		}
+		return "", false
+	}
+	return "", false
+}
+
+// readCueSheets parses the CUE sheets in dirPath, warning about (and skipping) broken ones.
+func readCueSheets(dirPath string) []*CueSheet {
+	var sheets []*CueSheet
+	cues, _ := filepath.Glob(filepath.Join(dirPath, "*.cue"))
+	for _, cuePath := range cues {
+		data, err := os.ReadFile(cuePath)
+		if err != nil {
+			continue
+		}
+		sheet, err := ParseCueSheet(strings.NewReader(tagging.DecodeCueText(data)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", filepath.Base(cuePath), err)
+			continue
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets
+}
+
+// findHiddenTrackFiles returns the base names (without extension) of pregap files
+// identified by the CUE sheets.
+func findHiddenTrackFiles(sheets []*CueSheet) map[string]bool {
+	hidden := make(map[string]bool)
+	for _, sheet := range sheets {
+		if file, ok := sheet.HiddenTrack(); ok && file != "" {
+			hidden[trimExt(filepath.Base(file))] = true
+		}
+	}
+	return hidden
+}
+
+// fillEditionFromCueSheets fills a missing barcode, catalog number or label from CUE
+// sheets (CATALOG, REM CATALOGNUMBER, REM LABEL). Tags take precedence. Returns edition,
+// or a new one if it was nil and the sheets had something to add.
+func fillEditionFromCueSheets(edition *domain.Edition, sheets []*CueSheet) *domain.Edition {
+	filled := domain.Edition{}
+	if edition != nil {
+		filled = *edition
+	}
+	for _, sheet := range sheets {
+		if filled.Barcode == "" {
+			filled.Barcode = sheet.Catalog
+		}
+		if filled.CatalogNumber == "" {
+			filled.CatalogNumber = sheet.Remarks["CATALOGNUMBER"]
+		}
+		if filled.Label == "" {
+			filled.Label = sheet.Remarks["LABEL"]
+		}
+	}
+	if edition == nil && filled == (domain.Edition{}) {
+		return nil
+	}
+	return &filled
+}
+
+func trimExt(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
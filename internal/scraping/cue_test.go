@@ -0,0 +1,104 @@
+package scraping
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestCueSheet_HiddenTrack(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Cue      string
+		WantFile string
+		WantOK   bool
+	}{
+		{
+			Name: "pregap split into its own file",
+			Cue: `PERFORMER "Glenn Gould"
+TITLE "Goldberg Variations"
+FILE "00 - Pregap.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE "Aria"
+    INDEX 00 00:00:00
+FILE "01 - Aria.wav" WAVE
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    INDEX 01 03:02:10`,
+			WantFile: "00 - Pregap.wav",
+			WantOK:   true,
+		},
+		{
+			Name: "pregap inside single image",
+			Cue: `FILE "image.flac" WAVE
+  TRACK 01 AUDIO
+    INDEX 00 00:00:00
+    INDEX 01 00:32:10`,
+			WantOK: true,
+		},
+		{
+			Name: "no pregap",
+			Cue: `FILE "01 - Aria.flac" WAVE
+  TRACK 01 AUDIO
+    INDEX 01 00:00:00
+FILE "02 - Variatio 1.flac" WAVE
+  TRACK 02 AUDIO
+    INDEX 00 00:00:00
+    INDEX 01 00:01:20`,
+			WantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			sheet, err := ParseCueSheet(strings.NewReader(tt.Cue))
+			if err != nil {
+				t.Fatalf("ParseCueSheet() error = %v", err)
+			}
+			file, ok := sheet.HiddenTrack()
+			if file != tt.WantFile || ok != tt.WantOK {
+				t.Errorf("HiddenTrack() = %q, %v; want %q, %v", file, ok, tt.WantFile, tt.WantOK)
+			}
+		})
+	}
+}
+
+func TestParseCueSheet_Fields(t *testing.T) {
+	sheet, err := ParseCueSheet(strings.NewReader("\uFEFFPERFORMER \"Glenn Gould\"\nTITLE \"Goldberg Variations\"\nFILE \"01.wav\" WAVE\n  TRACK 01 AUDIO\n    TITLE \"Aria\"\n    PERFORMER \"Gould\"\n    INDEX 01 00:00:00\n"))
+	if err != nil {
+		t.Fatalf("ParseCueSheet() error = %v", err)
+	}
+	if sheet.Title != "Goldberg Variations" || sheet.Performer != "Glenn Gould" {
+		t.Errorf("sheet = %q / %q", sheet.Title, sheet.Performer)
+	}
+	if len(sheet.Tracks) != 1 || sheet.Tracks[0].Title != "Aria" || sheet.Tracks[0].Performer != "Gould" || sheet.Tracks[0].Index01File != "01.wav" {
+		t.Errorf("tracks = %+v", sheet.Tracks)
+	}
+}
+
+func TestFillEditionFromCueSheets(t *testing.T) {
+	sheet, err := ParseCueSheet(strings.NewReader("REM GENRE Classical\nREM LABEL \"Harmonia Mundi\"\nREM CATALOGNUMBER HMC 902170\nCATALOG 3149020217021\nFILE \"01.wav\" WAVE\n  TRACK 01 AUDIO\n    REM COMPOSER Bach\n    INDEX 01 00:00:00\n"))
+	if err != nil {
+		t.Fatalf("ParseCueSheet() error = %v", err)
+	}
+	if sheet.Catalog != "3149020217021" || sheet.Remarks["LABEL"] != "Harmonia Mundi" || sheet.Remarks["COMPOSER"] != "" {
+		t.Errorf("sheet = %+v", sheet)
+	}
+
+	edition := fillEditionFromCueSheets(nil, []*CueSheet{sheet})
+	if edition == nil || edition.Barcode != "3149020217021" || edition.CatalogNumber != "HMC 902170" || edition.Label != "Harmonia Mundi" {
+		t.Errorf("fillEditionFromCueSheets(nil) = %+v", edition)
+	}
+
+	// Tags win over the cue sheet
+	tagged := &domain.Edition{CatalogNumber: "HMC902170", Year: 2013}
+	edition = fillEditionFromCueSheets(tagged, []*CueSheet{sheet})
+	if edition.CatalogNumber != "HMC902170" || edition.Barcode != "3149020217021" || edition.Year != 2013 {
+		t.Errorf("fillEditionFromCueSheets(tagged) = %+v", edition)
+	}
+
+	if edition := fillEditionFromCueSheets(nil, nil); edition != nil {
+		t.Errorf("fillEditionFromCueSheets(nil, nil) = %+v, want nil", edition)
+	}
+}
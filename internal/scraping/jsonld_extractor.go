@@ -0,0 +1,339 @@
+package scraping
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// jsonLDScript matches the contents of <script type="application/ld+json"> elements.
+var jsonLDScript = regexp.MustCompile(`(?is)<script[^>]+type\s*=\s*["']?application/ld\+json["']?[^>]*>(.*?)</script>`)
+
+// maxPageSize caps how much of a page is read.
+const maxPageSize = 10 << 20
+
+// JSONLDExtractor reads the schema.org MusicAlbum or MusicRelease JSON-LD that many
+// label sites (Harmonia Mundi, Alpha, BIS, ...) embed in their album pages. It
+// handles any http(s) URL, so it is registered last, as the fallback for sites
+// without a bespoke extractor.
+type JSONLDExtractor struct {
+	Client *http.Client
+}
+
+// NewJSONLDExtractor creates a JSONLDExtractor with a 30 second timeout.
+func NewJSONLDExtractor() *JSONLDExtractor {
+	return &JSONLDExtractor{Client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name implements Extractor.
+func (e *JSONLDExtractor) Name() string { return "JSON-LD (schema.org)" }
+
+// CanHandle implements Extractor: any http or https URL.
+func (e *JSONLDExtractor) CanHandle(url string) bool {
+	return strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://")
+}
+
+// Extract implements Extractor.
+func (e *JSONLDExtractor) Extract(url string) (*ExtractionResult, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+	}
+	req.Header.Set("User-Agent", "classical-tagger/1.0")
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s returned %s", ErrExtractionFailed, url, resp.Status)
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExtractionFailed, err)
+	}
+	return ParseJSONLD(page, url)
+}
+
+// ParseJSONLD extracts the album described by the schema.org JSON-LD in an HTML page.
+// source is recorded as the result's and the torrent's source.
+func ParseJSONLD(page []byte, source string) (*ExtractionResult, error) {
+	var nodes []map[string]any
+	for _, m := range jsonLDScript.FindAllSubmatch(page, -1) {
+		var v any
+		if err := json.Unmarshal(m[1], &v); err != nil {
+			continue // Malformed blocks are common; other blocks may still describe the album
+		}
+		nodes = append(nodes, flattenNodes(v)...)
+	}
+
+	var album, release map[string]any
+	for _, n := range nodes {
+		switch {
+		case album == nil && hasType(n, "MusicAlbum"):
+			album = n
+		case release == nil && hasType(n, "MusicRelease"):
+			release = n
+		}
+	}
+	if album == nil && release != nil {
+		album = firstNode(release["releaseOf"])
+	}
+	if release == nil && album != nil {
+		release = firstNode(album["albumRelease"])
+	}
+	if album == nil && release == nil {
+		return nil, fmt.Errorf("%w: no schema.org MusicAlbum or MusicRelease JSON-LD in %s", ErrExtractionFailed, source)
+	}
+
+	// Fields are read from the release first, then the album
+	sources := []map[string]any{release, album}
+	lookup := func(key string) any {
+		for _, n := range sources {
+			if v, ok := n[key]; ok && v != nil {
+				return v
+			}
+		}
+		return nil
+	}
+
+	result := &ExtractionResult{Source: source, Confidence: 0.6}
+	data := &domain.Album{
+		Title:        cleanText(nameOf(lookup("name"))),
+		OriginalYear: yearOf(lookup("datePublished")),
+		AlbumArtist:  jsonLDArtists(lookup("byArtist")),
+	}
+	if data.Title == "" {
+		data.Title = MissingTitle
+		result.Errors = append(result.Errors, ExtractionError{Field: "title", Message: "album has no name", Required: true})
+	}
+
+	label := cleanText(nameOf(lookup("recordLabel")))
+	catalog := cleanText(nameOf(lookup("catalogNumber")))
+	barcode := ""
+	for _, key := range []string{"gtin13", "gtin12", "gtin14", "gtin"} {
+		if barcode = nameOf(lookup(key)); barcode != "" {
+			break
+		}
+	}
+	if label != "" || catalog != "" || barcode != "" {
+		data.Edition = &domain.Edition{Label: label, CatalogNumber: catalog, Barcode: barcode, Year: data.OriginalYear}
+	} else {
+		result.Warnings = append(result.Warnings, "no record label or catalog number in JSON-LD")
+	}
+
+	albumComposers := jsonLDArtists(lookup("composer"))
+	for i, rec := range trackNodes(lookup("track")) {
+		track := &domain.Track{Disc: 1, Track: i + 1, Title: cleanText(nameOf(rec["name"]))}
+		if disc, num, ok := parsePosition(rec["position"]); ok {
+			track.Disc, track.Track = disc, num
+		}
+
+		composers := jsonLDArtists(rec["composer"])
+		if work := firstNode(rec["recordingOf"]); len(composers) == 0 && work != nil {
+			composers = jsonLDArtists(work["composer"])
+		}
+		if len(composers) == 0 {
+			composers = albumComposers
+		}
+		for _, c := range composers {
+			c.Role = domain.RoleComposer
+			track.Artists = append(track.Artists, c)
+		}
+		track.Artists = append(track.Artists, jsonLDArtists(rec["byArtist"])...)
+		if len(track.Artists) == 0 || track.Artists[0].Role != domain.RoleComposer {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("track %d: no composer in JSON-LD", track.Track))
+		}
+		data.Tracks = append(data.Tracks, track)
+	}
+	if len(data.Tracks) == 0 {
+		result.Errors = append(result.Errors, ExtractionError{Field: "tracks", Message: "album has no tracks", Required: true})
+	}
+
+	propagateAlbumArtists(data.Tracks, data.AlbumArtist, ExtractOptions{})
+	result.Torrent = data.ToTorrent("")
+	result.Torrent.Sources = []string{source}
+	return result, nil
+}
+
+// flattenNodes returns the JSON-LD objects in v, expanding arrays and @graph.
+func flattenNodes(v any) []map[string]any {
+	switch v := v.(type) {
+	case []any:
+		var nodes []map[string]any
+		for _, e := range v {
+			nodes = append(nodes, flattenNodes(e)...)
+		}
+		return nodes
+	case map[string]any:
+		nodes := []map[string]any{v}
+		if graph, ok := v["@graph"]; ok {
+			nodes = append(nodes, flattenNodes(graph)...)
+		}
+		return nodes
+	}
+	return nil
+}
+
+// hasType reports whether node's @type is, or includes, t.
+func hasType(node map[string]any, t string) bool {
+	for _, v := range asList(node["@type"]) {
+		if s, ok := v.(string); ok && (s == t || strings.HasSuffix(s, "/"+t) || strings.HasSuffix(s, ":"+t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// asList returns v as a list: v itself if it is an array, else a list of v (empty for nil).
+func asList(v any) []any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	}
+	return []any{v}
+}
+
+// firstNode returns the first object in v.
+func firstNode(v any) map[string]any {
+	for _, e := range asList(v) {
+		if n, ok := e.(map[string]any); ok {
+			return n
+		}
+	}
+	return nil
+}
+
+// nameOf returns v as text: a string or number as is, an object's name, or the
+// first element of an array.
+func nameOf(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any:
+		return nameOf(v["name"])
+	case []any:
+		if len(v) > 0 {
+			return nameOf(v[0])
+		}
+	}
+	return ""
+}
+
+// yearOf returns the year of an ISO 8601 date such as "2019-03-01", or 0.
+func yearOf(v any) int {
+	s := nameOf(v)
+	if len(s) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(s[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
+
+func cleanText(s string) string {
+	return cleanWhitespace(decodeHTMLEntities(s))
+}
+
+// jsonLDArtists converts Person, MusicGroup and PerformanceRole values to artists.
+// Groups are ensembles; people get a role from roleName when given, else one
+// inferred from their name and position.
+func jsonLDArtists(v any) []domain.Artist {
+	var artists []domain.Artist
+	afterEnsemble := false
+	for _, e := range asList(v) {
+		node, _ := e.(map[string]any)
+		roleName := ""
+		if node != nil && (node["roleName"] != nil || hasType(node, "Role") || hasType(node, "PerformanceRole")) {
+			roleName = strings.ToLower(nameOf(node["roleName"]))
+			for _, key := range []string{"byArtist", "performer", "composer", "member"} {
+				if inner := firstNode(node[key]); inner != nil {
+					node = inner
+					break
+				}
+			}
+		}
+
+		name := cleanText(nameOf(e))
+		if node != nil {
+			name = cleanText(nameOf(node["name"]))
+		}
+		if name == "" {
+			continue
+		}
+
+		var artist domain.Artist
+		switch {
+		case strings.Contains(roleName, "conductor"):
+			artist = domain.Artist{Name: name, Role: domain.RoleConductor}
+		case strings.Contains(roleName, "composer"):
+			artist = domain.Artist{Name: name, Role: domain.RoleComposer}
+		case node != nil && (hasType(node, "MusicGroup") || hasType(node, "PerformingGroup")):
+			artist = domain.Artist{Name: name, Role: domain.RoleEnsemble}
+		default:
+			artist = InferArtistRoleWithContext(name, afterEnsemble).Artist
+			if roleName != "" && artist.Role == domain.RoleSoloist && !strings.Contains(roleName, "perform") {
+				artist.Instrument = roleName
+			}
+		}
+		afterEnsemble = artist.Role == domain.RoleEnsemble
+		if !slices.ContainsFunc(artists, func(a domain.Artist) bool { return a.Name == artist.Name && a.Role == artist.Role }) {
+			artists = append(artists, artist)
+		}
+	}
+	return artists
+}
+
+// trackNodes returns the MusicRecordings in a track property: an array of
+// recordings, an ItemList of ListItems, or a mix.
+func trackNodes(v any) []map[string]any {
+	var tracks []map[string]any
+	for _, e := range asList(v) {
+		node, ok := e.(map[string]any)
+		if !ok {
+			continue
+		}
+		if elements, ok := node["itemListElement"]; ok {
+			tracks = append(tracks, trackNodes(elements)...)
+			continue
+		}
+		if item := firstNode(node["item"]); item != nil {
+			if _, ok := item["position"]; !ok && node["position"] != nil {
+				item["position"] = node["position"]
+			}
+			node = item
+		}
+		tracks = append(tracks, node)
+	}
+	return tracks
+}
+
+// parsePosition parses a track position: a number, "5", or "2.5" / "2-5" for disc 2, track 5.
+func parsePosition(v any) (disc, track int, ok bool) {
+	s := strings.TrimSpace(nameOf(v))
+	if s == "" {
+		return 0, 0, false
+	}
+	if d, t, found := strings.Cut(strings.ReplaceAll(s, "-", "."), "."); found {
+		disc, err1 := strconv.Atoi(d)
+		track, err2 := strconv.Atoi(t)
+		return disc, track, err1 == nil && err2 == nil && disc > 0 && track > 0
+	}
+	track, err := strconv.Atoi(s)
+	return 1, track, err == nil && track > 0
+}
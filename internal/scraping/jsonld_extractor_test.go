@@ -0,0 +1,136 @@
+package scraping
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// jsonLDPage is shaped like a label shop's album page: an unrelated Organization
+// block, then a MusicAlbum with an ItemList of recordings.
+const jsonLDPage = `<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Organization", "name": "Label Shop"}</script>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@type": "MusicAlbum",
+  "name": "Bach: Cantatas BWV 82 &amp; 158",
+  "datePublished": "2019-03-01",
+  "byArtist": [
+    {"@type": "Person", "name": "Christian Immler"},
+    {"@type": "MusicGroup", "name": "Akademie für Alte Musik Berlin"},
+    {"@type": "PerformanceRole", "roleName": "Conductor", "byArtist": {"@type": "Person", "name": "Georg Kallweit"}}
+  ],
+  "albumRelease": {"@type": "MusicRelease", "catalogNumber": "HMM902601", "recordLabel": {"@type": "Organization", "name": "Harmonia Mundi"}, "gtin13": "3149020260129"},
+  "track": {
+    "@type": "ItemList",
+    "numberOfItems": 2,
+    "itemListElement": [
+      {"@type": "ListItem", "position": 1, "item": {"@type": "MusicRecording", "name": "Ich habe genug, BWV 82: I. Aria", "recordingOf": {"@type": "MusicComposition", "composer": {"@type": "Person", "name": "Johann Sebastian Bach"}}}},
+      {"@type": "ListItem", "position": "2.1", "item": {"@type": "MusicRecording", "name": "Der Friede sei mit dir, BWV 158: I. Recitativo", "composer": "Johann Sebastian Bach"}}
+    ]
+  }
+}
+</script></head><body></body></html>`
+
+func TestParseJSONLD(t *testing.T) {
+	result, err := ParseJSONLD([]byte(jsonLDPage), "https://www.harmoniamundi.com/album/123")
+	if err != nil {
+		t.Fatalf("ParseJSONLD() error = %v", err)
+	}
+	torrent := result.Torrent
+
+	if torrent.Title != "Bach: Cantatas BWV 82 & 158" || torrent.OriginalYear != 2019 {
+		t.Errorf("Title, year = %q, %d", torrent.Title, torrent.OriginalYear)
+	}
+	wantEdition := domain.Edition{Label: "Harmonia Mundi", CatalogNumber: "HMM902601", Barcode: "3149020260129", Year: 2019}
+	if torrent.Edition == nil || *torrent.Edition != wantEdition {
+		t.Errorf("Edition = %+v, want %+v", torrent.Edition, wantEdition)
+	}
+	if !slices.Equal(torrent.Sources, []string{"https://www.harmoniamundi.com/album/123"}) {
+		t.Errorf("Sources = %v", torrent.Sources)
+	}
+
+	tracks := torrent.Tracks()
+	if len(tracks) != 2 {
+		t.Fatalf("got %d tracks, want 2", len(tracks))
+	}
+	if tracks[1].Disc != 2 || tracks[1].Track != 1 {
+		t.Errorf("track 2 position = %d.%d, want 2.1", tracks[1].Disc, tracks[1].Track)
+	}
+	wantArtists := []domain.Artist{
+		{Name: "Johann Sebastian Bach", Role: domain.RoleComposer},
+		{Name: "Christian Immler", Role: domain.RoleSoloist},
+		{Name: "Akademie für Alte Musik Berlin", Role: domain.RoleEnsemble},
+		{Name: "Georg Kallweit", Role: domain.RoleConductor},
+	}
+	for _, track := range tracks {
+		if !slices.Equal(track.Artists, wantArtists) {
+			t.Errorf("track %d artists = %v, want %v", track.Track, track.Artists, wantArtists)
+		}
+	}
+	if len(result.Warnings) != 0 || len(result.Errors) != 0 {
+		t.Errorf("Warnings = %v, Errors = %v", result.Warnings, result.Errors)
+	}
+}
+
+func TestParseJSONLD_GraphAndRelease(t *testing.T) {
+	page := `<script type='application/ld+json'>{"@graph": [
+	  {"@type": "WebPage", "name": "BIS"},
+	  {"@type": ["Product", "MusicRelease"], "name": "Sibelius: Symphonies", "catalogNumber": "BIS-2506", "recordLabel": "BIS",
+	   "releaseOf": {"@type": "MusicAlbum", "track": [{"@type": "MusicRecording", "name": "Symphony No. 1: I. Andante", "byArtist": {"@type": "MusicGroup", "name": "Lahti Symphony Orchestra"}}]}}
+	]}</script>`
+
+	result, err := ParseJSONLD([]byte(page), "https://bis.se/x")
+	if err != nil {
+		t.Fatalf("ParseJSONLD() error = %v", err)
+	}
+	if result.Torrent.Title != "Sibelius: Symphonies" || result.Torrent.Edition.CatalogNumber != "BIS-2506" {
+		t.Errorf("Torrent = %+v", result.Torrent)
+	}
+	tracks := result.Torrent.Tracks()
+	if len(tracks) != 1 || tracks[0].Artists[0].Role != domain.RoleEnsemble {
+		t.Errorf("tracks = %+v", tracks)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want the missing composer", result.Warnings)
+	}
+}
+
+func TestParseJSONLD_NoAlbum(t *testing.T) {
+	page := `<script type="application/ld+json">{"@type": "Product", "name": "T-shirt"}</script><script type="application/ld+json">{broken</script>`
+	if _, err := ParseJSONLD([]byte(page), "https://example.com"); !errors.Is(err, ErrExtractionFailed) {
+		t.Errorf("ParseJSONLD() error = %v, want ErrExtractionFailed", err)
+	}
+}
+
+func TestJSONLDExtractor_Extract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/album" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(jsonLDPage))
+	}))
+	defer server.Close()
+
+	registry := DefaultRegistry()
+	if registry.Get(server.URL) == nil || registry.Get("album.json") != nil {
+		t.Error("the JSON-LD fallback should handle http(s) URLs only")
+	}
+
+	result, err := registry.Extract(server.URL + "/album")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if result.Source != server.URL+"/album" || len(result.Torrent.Tracks()) != 2 {
+		t.Errorf("Extract() = %+v", result)
+	}
+	if _, err := registry.Extract(server.URL + "/missing"); !errors.Is(err, ErrExtractionFailed) {
+		t.Errorf("Extract() of a 404 error = %v, want ErrExtractionFailed", err)
+	}
+}
@@ -0,0 +1,312 @@
+package scraping
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// jriverEpoch is day 0 of JRiver's serial dates (the spreadsheet convention).
+var jriverEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// yearPattern finds a four-digit year in a free-form date.
+var yearPattern = regexp.MustCompile(`\b(1[0-9]{3}|20[0-9]{2})\b`)
+
+// mplPlaylist is a JRiver Media Center MPL export: one Item per file, each a
+// list of named fields.
+type mplPlaylist struct {
+	Items []struct {
+		Fields []struct {
+			Name  string `xml:"Name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Field"`
+	} `xml:"Item"`
+}
+
+// roonAlbum is a Roon album export: album fields plus Roon's track credits,
+// "Name - Role" (e.g. "Herbert von Karajan - Conductor", "Martha Argerich - Piano"),
+// and composition grouping (work and part).
+type roonAlbum struct {
+	Title         string `json:"title"`
+	AlbumArtist   string `json:"album_artist"`
+	Year          int    `json:"year"`
+	Label         string `json:"label"`
+	CatalogNumber string `json:"catalog_number"`
+	Tracks        []struct {
+		Disc      int      `json:"disc"`
+		Track     int      `json:"track"`
+		Title     string   `json:"title"`
+		Work      string   `json:"work"`
+		Part      string   `json:"part"`
+		Composers []string `json:"composers"`
+		Credits   []string `json:"credits"`
+		Path      string   `json:"path"`
+	} `json:"tracks"`
+}
+
+// creditRoles maps library credit roles other than instruments and voices to
+// artist roles. Unlisted roles are soloists playing or singing the named part.
+var creditRoles = map[string]domain.Role{
+	"composer": domain.RoleComposer, "conductor": domain.RoleConductor,
+	"orchestra": domain.RoleEnsemble, "ensemble": domain.RoleEnsemble, "choir": domain.RoleEnsemble,
+	"chorus": domain.RoleEnsemble, "quartet": domain.RoleEnsemble, "band": domain.RoleEnsemble,
+	"arranger": domain.RoleArranger, "producer": domain.RoleProducer,
+	"main performer": domain.RolePerformer, "performer": domain.RolePerformer,
+}
+
+// ParseLibraryExport parses album metadata exported from a library manager, so
+// curation done there can seed tagging: a JRiver Media Center MPL playlist (XML)
+// or a Roon album export (JSON). The format is detected from the content. File
+// paths are made relative to the directory the exported files share.
+func ParseLibraryExport(r io.Reader) (*ExtractionResult, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read library export: %w", err)
+	}
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))) // Windows exports start with a BOM
+	switch {
+	case bytes.HasPrefix(data, []byte("<")):
+		return parseJRiverExport(bytes.NewReader(data))
+	case bytes.HasPrefix(data, []byte("{")):
+		return parseRoonExport(bytes.NewReader(data))
+	}
+	return nil, fmt.Errorf("%w: library export is neither JRiver XML nor Roon JSON", ErrExtractionFailed)
+}
+
+// parseJRiverExport parses a JRiver MPL playlist. Multiple values in a field
+// are separated by semicolons, as JRiver writes them.
+func parseJRiverExport(r io.Reader) (*ExtractionResult, error) {
+	var playlist mplPlaylist
+	if err := xml.NewDecoder(r).Decode(&playlist); err != nil {
+		return nil, fmt.Errorf("%w: invalid JRiver export: %v", ErrExtractionFailed, err)
+	}
+
+	result := &ExtractionResult{Torrent: &domain.Torrent{}, Source: "jriver", Confidence: 0.8}
+	var paths []string
+	for i, item := range playlist.Items {
+		fields := make(map[string]string)
+		for _, f := range item.Fields {
+			fields[f.Name] = strings.TrimSpace(f.Value)
+		}
+
+		track := &domain.Track{Title: fields["Name"]}
+		track.Track, _ = strconv.Atoi(fields["Track #"])
+		track.Disc, _ = strconv.Atoi(fields["Disc #"])
+		if track.Disc == 0 {
+			track.Disc = 1
+		}
+		if track.Track == 0 || track.Title == "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("item %d skipped: no track number or name", i+1))
+			continue
+		}
+		for _, name := range splitValues(fields["Composer"]) {
+			track.Artists = append(track.Artists, domain.Artist{Name: name, Role: domain.RoleComposer})
+		}
+		for _, name := range splitValues(fields["Orchestra"]) {
+			track.Artists = append(track.Artists, domain.Artist{Name: name, Role: domain.RoleEnsemble})
+		}
+		for _, name := range splitValues(fields["Conductor"]) {
+			track.Artists = append(track.Artists, domain.Artist{Name: name, Role: domain.RoleConductor})
+		}
+		for _, credit := range splitValues(fields["Artist"]) {
+			track.Artists = appendPerformer(track.Artists, credit)
+		}
+		track.File.Path = fields["Filename"]
+		paths = append(paths, track.File.Path)
+		result.Torrent.Files = append(result.Torrent.Files, track)
+
+		if result.Torrent.Title == "" {
+			result.Torrent.Title = fields["Album"]
+		}
+		if result.Torrent.OriginalYear == 0 {
+			result.Torrent.OriginalYear = jriverYear(fields)
+		}
+		if result.Torrent.Edition == nil && (fields["Publisher"] != "" || fields["Catalog Number"] != "") {
+			result.Torrent.Edition = &domain.Edition{Label: fields["Publisher"], CatalogNumber: fields["Catalog Number"]}
+		}
+		if result.Torrent.AlbumArtist == nil {
+			result.Torrent.AlbumArtist = albumArtists(fields["Album Artist"])
+		}
+	}
+	return finishLibraryExport(result, paths)
+}
+
+// jriverYear reads the year from an item's "Date (readable)", "Year" or serial
+// "Date" field.
+func jriverYear(fields map[string]string) int {
+	for _, key := range []string{"Date (readable)", "Year"} {
+		if m := yearPattern.FindString(fields[key]); m != "" {
+			year, _ := strconv.Atoi(m)
+			return year
+		}
+	}
+	if days, err := strconv.ParseFloat(fields["Date"], 64); err == nil && days > 0 {
+		if year := jriverEpoch.AddDate(0, 0, int(days)).Year(); year > 1000 {
+			return year
+		}
+	}
+	return 0
+}
+
+// parseRoonExport parses a Roon album export.
+func parseRoonExport(r io.Reader) (*ExtractionResult, error) {
+	var album roonAlbum
+	if err := json.NewDecoder(r).Decode(&album); err != nil {
+		return nil, fmt.Errorf("%w: invalid Roon export: %v", ErrExtractionFailed, err)
+	}
+
+	result := &ExtractionResult{
+		Torrent: &domain.Torrent{
+			Title:        album.Title,
+			OriginalYear: album.Year,
+			AlbumArtist:  albumArtists(album.AlbumArtist),
+		},
+		Source:     "roon",
+		Confidence: 0.8,
+	}
+	if album.Label != "" || album.CatalogNumber != "" {
+		result.Torrent.Edition = &domain.Edition{Label: album.Label, CatalogNumber: album.CatalogNumber}
+	}
+
+	var paths []string
+	for i, t := range album.Tracks {
+		track := &domain.Track{Disc: t.Disc, Track: t.Track, Title: t.Title}
+		if t.Work != "" && t.Part != "" {
+			track.Title = t.Work + ": " + t.Part
+		}
+		if track.Disc == 0 {
+			track.Disc = 1
+		}
+		if track.Track == 0 || track.Title == "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("track %d skipped: no track number or title", i+1))
+			continue
+		}
+		for _, name := range t.Composers {
+			track.Artists = append(track.Artists, domain.Artist{Name: strings.TrimSpace(name), Role: domain.RoleComposer})
+		}
+		for _, credit := range t.Credits {
+			name, role, ok := cutCredit(credit)
+			if !ok {
+				track.Artists = appendPerformer(track.Artists, credit)
+				continue
+			}
+			artist := domain.Artist{Name: name, Role: domain.RoleSoloist, Instrument: strings.ToLower(role)}
+			if r, known := creditRoles[strings.ToLower(role)]; known {
+				artist.Role, artist.Instrument = r, ""
+			}
+			if !hasArtist(track.Artists, artist) {
+				track.Artists = append(track.Artists, artist)
+			}
+		}
+		track.File.Path = t.Path
+		paths = append(paths, t.Path)
+		result.Torrent.Files = append(result.Torrent.Files, track)
+	}
+	return finishLibraryExport(result, paths)
+}
+
+// cutCredit splits a Roon credit "Name - Role".
+func cutCredit(credit string) (name, role string, ok bool) {
+	i := strings.LastIndex(credit, " - ")
+	if i <= 0 {
+		return "", "", false
+	}
+	name, role = strings.TrimSpace(credit[:i]), strings.TrimSpace(credit[i+3:])
+	return name, role, name != "" && role != ""
+}
+
+// appendPerformer adds a performer credited as "Name" or "Name (instrument)",
+// inferring the role of a bare name. Artists already credited, such as the
+// conductor repeated in JRiver's Artist field, are skipped.
+func appendPerformer(artists []domain.Artist, credit string) []domain.Artist {
+	name, instrument := domain.ParseCredit(credit)
+	if name == "" || slices.ContainsFunc(artists, func(a domain.Artist) bool { return a.Name == name }) {
+		return artists
+	}
+	if instrument != "" {
+		return append(artists, domain.Artist{Name: name, Role: domain.RoleSoloist, Instrument: instrument})
+	}
+	return append(artists, InferArtistRole(name).Artist)
+}
+
+// hasArtist reports whether artists already credits a's name in a's role.
+func hasArtist(artists []domain.Artist, a domain.Artist) bool {
+	for _, existing := range artists {
+		if existing.Name == a.Name && existing.Role == a.Role {
+			return true
+		}
+	}
+	return false
+}
+
+// albumArtists infers roles for a semicolon-separated album artist field,
+// ignoring "Various Artists".
+func albumArtists(field string) []domain.Artist {
+	var artists []domain.Artist
+	for _, name := range splitValues(field) {
+		if strings.EqualFold(name, "Various Artists") || strings.EqualFold(name, "Various") {
+			continue
+		}
+		artists = append(artists, InferArtistRole(name).Artist)
+	}
+	return artists
+}
+
+// splitValues splits a semicolon-separated multi-value field.
+func splitValues(field string) []string {
+	var values []string
+	for _, v := range strings.Split(field, ";") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// finishLibraryExport makes the tracks' paths relative to the directory they
+// share and fails exports without tracks.
+func finishLibraryExport(result *ExtractionResult, paths []string) (*ExtractionResult, error) {
+	if len(result.Torrent.Files) == 0 {
+		return nil, fmt.Errorf("%w: no tracks found in %s export", ErrExtractionFailed, result.Source)
+	}
+	prefix := commonDir(paths)
+	for _, track := range result.Torrent.Tracks() {
+		if track.File.Path != "" {
+			track.File.Path = strings.TrimPrefix(strings.ReplaceAll(track.File.Path, `\`, "/"), prefix)
+		}
+	}
+	return result, nil
+}
+
+// commonDir returns the directory, with trailing slash, shared by all paths
+// (Windows separators normalized), or "" when they share none.
+func commonDir(paths []string) string {
+	prefix := ""
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		dir := path.Dir(strings.ReplaceAll(p, `\`, "/")) + "/"
+		if prefix == "" {
+			prefix = dir
+			continue
+		}
+		for !strings.HasPrefix(dir, prefix) {
+			prefix = path.Dir(strings.TrimSuffix(prefix, "/")) + "/"
+			if prefix == "./" || prefix == "//" {
+				return ""
+			}
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,137 @@
+package scraping
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+const jriverExport = "\xef\xbb\xbf" + `<?xml version="1.0" encoding="UTF-8" standalone="yes" ?>
+<MPL Version="2.0" Title="Beethoven: Symphonies 5 &amp; 7">
+<Item>
+<Field Name="Filename">C:\Music\Beethoven - Symphonies 5 &amp; 7\01 - Allegro con brio.flac</Field>
+<Field Name="Name">Symphony No. 5 in C minor, Op. 67: I. Allegro con brio</Field>
+<Field Name="Artist">Wiener Philharmoniker; Carlos Kleiber</Field>
+<Field Name="Album">Symphonies 5 &amp; 7</Field>
+<Field Name="Album Artist">Carlos Kleiber</Field>
+<Field Name="Composer">Ludwig van Beethoven</Field>
+<Field Name="Conductor">Carlos Kleiber</Field>
+<Field Name="Orchestra">Wiener Philharmoniker</Field>
+<Field Name="Publisher">Deutsche Grammophon</Field>
+<Field Name="Track #">1</Field>
+<Field Name="Date">27395</Field>
+</Item>
+<Item>
+<Field Name="Filename">C:\Music\Beethoven - Symphonies 5 &amp; 7\02 - Andante con moto.flac</Field>
+<Field Name="Name">Symphony No. 5 in C minor, Op. 67: II. Andante con moto</Field>
+<Field Name="Composer">Ludwig van Beethoven</Field>
+<Field Name="Artist">Martha Argerich (piano)</Field>
+<Field Name="Track #">2</Field>
+</Item>
+<Item>
+<Field Name="Filename">C:\Music\Beethoven - Symphonies 5 &amp; 7\cover.jpg</Field>
+</Item>
+</MPL>`
+
+const roonExport = `{
+  "title": "Goldberg Variations",
+  "album_artist": "Glenn Gould",
+  "year": 1982,
+  "label": "CBS Masterworks",
+  "catalog_number": "MK 37779",
+  "tracks": [
+    {"disc": 1, "track": 1, "work": "Goldberg Variations, BWV 988", "part": "Aria",
+     "composers": ["Johann Sebastian Bach"], "credits": ["Glenn Gould - Piano", "Johann Sebastian Bach - Composer"],
+     "path": "/music/Gould/01 Aria.flac"},
+    {"disc": 1, "track": 2, "title": "Variatio 1", "composers": ["Johann Sebastian Bach"],
+     "credits": ["Glenn Gould - Piano", "Samuel H. Carter - Producer"], "path": "/music/Gould/02 Variatio 1.flac"}
+  ]
+}`
+
+func TestParseLibraryExport_JRiver(t *testing.T) {
+	result, err := ParseLibraryExport(strings.NewReader(jriverExport))
+	if err != nil {
+		t.Fatalf("ParseLibraryExport() error = %v", err)
+	}
+	torrent := result.Torrent
+	if result.Source != "jriver" || torrent.Title != "Symphonies 5 & 7" || torrent.OriginalYear != 1975 {
+		t.Errorf("album = %q %q (%d)", result.Source, torrent.Title, torrent.OriginalYear)
+	}
+	if torrent.Edition == nil || torrent.Edition.Label != "Deutsche Grammophon" {
+		t.Errorf("Edition = %+v", torrent.Edition)
+	}
+	if len(result.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want the cover skipped", result.Warnings)
+	}
+
+	tracks := torrent.Tracks()
+	if len(tracks) != 2 {
+		t.Fatalf("got %d tracks, want 2", len(tracks))
+	}
+	if tracks[0].File.Path != "01 - Allegro con brio.flac" || tracks[0].Disc != 1 {
+		t.Errorf("track 1 = disc %d, path %q", tracks[0].Disc, tracks[0].File.Path)
+	}
+	want := []domain.Artist{
+		{Name: "Ludwig van Beethoven", Role: domain.RoleComposer},
+		{Name: "Wiener Philharmoniker", Role: domain.RoleEnsemble},
+		{Name: "Carlos Kleiber", Role: domain.RoleConductor},
+	}
+	if len(tracks[0].Artists) != len(want) {
+		t.Fatalf("track 1 artists = %v, want %v", tracks[0].Artists, want)
+	}
+	for i, a := range want {
+		if tracks[0].Artists[i] != a {
+			t.Errorf("track 1 artist %d = %v, want %v", i, tracks[0].Artists[i], a)
+		}
+	}
+	if soloist := tracks[1].Artists[1]; soloist.Name != "Martha Argerich" || soloist.Role != domain.RoleSoloist || soloist.Instrument != "piano" {
+		t.Errorf("track 2 soloist = %+v", soloist)
+	}
+}
+
+func TestParseLibraryExport_Roon(t *testing.T) {
+	result, err := ParseLibraryExport(strings.NewReader(roonExport))
+	if err != nil {
+		t.Fatalf("ParseLibraryExport() error = %v", err)
+	}
+	torrent := result.Torrent
+	if result.Source != "roon" || torrent.Title != "Goldberg Variations" || torrent.OriginalYear != 1982 {
+		t.Errorf("album = %q %q (%d)", result.Source, torrent.Title, torrent.OriginalYear)
+	}
+	if torrent.Edition == nil || torrent.Edition.CatalogNumber != "MK 37779" {
+		t.Errorf("Edition = %+v", torrent.Edition)
+	}
+	if len(torrent.AlbumArtist) != 1 || torrent.AlbumArtist[0].Name != "Glenn Gould" {
+		t.Errorf("AlbumArtist = %v", torrent.AlbumArtist)
+	}
+
+	tracks := torrent.Tracks()
+	if len(tracks) != 2 {
+		t.Fatalf("got %d tracks, want 2", len(tracks))
+	}
+	if tracks[0].Title != "Goldberg Variations, BWV 988: Aria" || tracks[0].File.Path != "01 Aria.flac" {
+		t.Errorf("track 1 = %q at %q", tracks[0].Title, tracks[0].File.Path)
+	}
+	// The composer credit duplicates the composers list
+	if len(tracks[0].Artists) != 2 || tracks[0].Artists[1].Instrument != "piano" {
+		t.Errorf("track 1 artists = %+v", tracks[0].Artists)
+	}
+	if producer := tracks[1].Artists[2]; producer.Role != domain.RoleProducer {
+		t.Errorf("track 2 producer = %+v", producer)
+	}
+}
+
+func TestParseLibraryExport_Invalid(t *testing.T) {
+	for name, export := range map[string]string{
+		"empty":     "  \n",
+		"csv":       "Title,Artist\n",
+		"no tracks": `{"title": "Album", "tracks": []}`,
+		"bad xml":   "<MPL><Item>",
+	} {
+		if _, err := ParseLibraryExport(strings.NewReader(export)); !errors.Is(err, ErrExtractionFailed) {
+			t.Errorf("%s: ParseLibraryExport() error = %v, want ErrExtractionFailed", name, err)
+		}
+	}
+}
@@ -5,19 +5,38 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
-	"github.com/dhowden/tag"
+	"github.com/cehbz/classical-tagger/internal/tagging"
 	"github.com/go-flac/flacvorbis"
 	"github.com/go-flac/go-flac"
 )
 
+// ExtractOptions tunes local extraction. The zero value propagates album artists
+// onto every track.
+type ExtractOptions struct {
+	// ArtistPropagation controls copying album-level performers onto tracks
+	ArtistPropagation domain.ArtistPropagationPolicy
+	// ConfirmPropagation is asked, under ArtistPrompt, whether to add artist to the
+	// missing tracks. A nil func declines.
+	ConfirmPropagation func(artist domain.Artist, missing []*domain.Track) bool
+	// AllowMissingComposer keeps tracks without a COMPOSER tag (crossover or recital
+	// discs) instead of skipping them. It is also enabled by a non-classical GENRE.
+	AllowMissingComposer bool
+}
+
 // ExtractFromDirectory reads all FLAC files in a directory and extracts metadata.
 // It attempts to build a complete domain.Album structure from the tags and filenames.
 func ExtractFromDirectory(dirPath string) (*domain.Album, error) {
+	return ExtractFromDirectoryWithOptions(dirPath, ExtractOptions{})
+}
+
+// ExtractFromDirectoryWithOptions is ExtractFromDirectory with extraction options.
+func ExtractFromDirectoryWithOptions(dirPath string, opts ExtractOptions) (*domain.Album, error) {
 	// Verify directory exists
 	info, err := os.Stat(dirPath)
 	if err != nil {
@@ -34,14 +53,14 @@ func ExtractFromDirectory(dirPath string) (*domain.Album, error) {
 	}
 
 	if len(flacFiles) == 0 {
-		return nil, fmt.Errorf("no FLAC files found in directory")
+		return nil, fmt.Errorf("%w: no FLAC or DSD files in %s", domain.ErrNoTracks, dirPath)
 	}
 
 	// Extract metadata from files
-	return extractFromFiles(flacFiles, dirPath)
+	return extractFromFiles(flacFiles, dirPath, opts)
 }
 
-// findFLACFiles recursively finds all FLAC files in a directory.
+// findFLACFiles recursively finds all FLAC and DSD (DSF, DFF) files in a directory.
 func findFLACFiles(dirPath string) ([]string, error) {
 	files := make([]string, 0)
 
@@ -50,7 +69,7 @@ func findFLACFiles(dirPath string) ([]string, error) {
 			return err
 		}
 
-		if !info.IsDir() && strings.HasSuffix(strings.ToLower(info.Name()), ".flac") {
+		if !info.IsDir() && (strings.HasSuffix(strings.ToLower(info.Name()), ".flac") || tagging.IsDSD(info.Name())) {
 			files = append(files, path)
 		}
 
@@ -68,7 +87,7 @@ func findFLACFiles(dirPath string) ([]string, error) {
 }
 
 // extractFromFiles extracts metadata from a list of FLAC files.
-func extractFromFiles(files []string, dirPath string) (*domain.Album, error) {
+func extractFromFiles(files []string, dirPath string, opts ExtractOptions) (*domain.Album, error) {
 	// Create initial album data with sentinel values
 	album := &domain.Album{
 		FolderName:   filepath.Base(dirPath),
@@ -77,39 +96,68 @@ func extractFromFiles(files []string, dirPath string) (*domain.Album, error) {
 		Tracks:       make([]*domain.Track, 0, len(files)),
 	}
 
+	cueSheets := readCueSheets(dirPath)
+
 	// Extract album-level metadata from first file
 	if len(files) > 0 {
 		albumData, warning := extractAlbumMetadata(files[0])
 		album.Title = albumData.Title
 		album.OriginalYear = albumData.OriginalYear
-		album.Edition = albumData.Edition
+		album.RecordingYears = albumData.RecordingYears
+		album.Edition = fillEditionFromCueSheets(albumData.Edition, cueSheets)
 		album.AlbumArtist = albumData.AlbumArtist
 
 		if warning != "" {
 			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
 		}
+
+		if !opts.AllowMissingComposer && albumData.Genre != "" && !isClassicalGenre(albumData.Genre) {
+			fmt.Fprintf(os.Stderr, "Warning: genre %q is not classical; tracks without a composer are kept\n", albumData.Genre)
+			opts.AllowMissingComposer = true
+		}
 	}
+	album.AllowMissingComposer = opts.AllowMissingComposer
+
+	// Pregap (HTOA) files named by CUE sheets become track 0
+	hiddenFiles := findHiddenTrackFiles(cueSheets)
+
+	// A folder name naming the composer stands in for missing COMPOSER tags
+	credits := parseDirectoryCredits(dirPath)
+	seededComposer := 0
 
 	// Extract track metadata from each file and collect ALBUMARTIST values
 	trackAlbumArtists := make(map[string]bool) // Track unique ALBUMARTIST values
 	for _, filePath := range files {
-		track, albumArtistValue, err := extractTrackMetadataWithAlbumArtist(filePath, dirPath)
+		hidden := hiddenFiles[trimExt(filepath.Base(filePath))] || isHiddenTrackFilename(filePath)
+		track, albumArtistValue, err := extractTrackMetadataWithAlbumArtist(filePath, dirPath, hidden, opts.AllowMissingComposer || credits.Composer != "")
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: file %s: %v\n", filepath.Base(filePath), err)
 			continue
 		}
+		if len(track.Composers()) == 0 && credits.Composer != "" {
+			track.Artists = append([]domain.Artist{{Name: credits.Composer, Role: domain.RoleComposer}}, track.Artists...)
+			seededComposer++
+		}
+		if len(track.Composers()) == 0 {
+			fmt.Fprintf(os.Stderr, "Warning: file %s: no composer found in tags; kept for composer research\n", filepath.Base(filePath))
+		}
 
 		// Track ALBUMARTIST value for verification
 		if albumArtistValue != "" {
 			trackAlbumArtists[albumArtistValue] = true
 		}
 
+		// Only keep per-track recording years that differ from the album's
+		if slices.Equal(track.RecordingYears, album.RecordingYears) {
+			track.RecordingYears = nil
+		}
+
 		album.Tracks = append(album.Tracks, track)
 	}
 
 	// Validate we got tracks
 	if len(album.Tracks) == 0 {
-		return nil, fmt.Errorf("no tracks extracted")
+		return nil, fmt.Errorf("%w: no audio file in %s could be read", domain.ErrNoTracks, dirPath)
 	}
 
 	// Verify ALBUMARTIST consistency across tracks
@@ -170,7 +218,7 @@ func extractFromFiles(files []string, dirPath string) (*domain.Album, error) {
 
 			// Ensure AlbumArtist performers are present on each track (unless Various Artists)
 			if !strings.EqualFold(strings.TrimSpace(domain.FormatArtists(album.AlbumArtist)), "Various Artists") {
-				ensureArtistsOnTracks(album.Tracks, album.AlbumArtist)
+				propagateAlbumArtists(album.Tracks, album.AlbumArtist, opts)
 			}
 		}
 	}
@@ -182,15 +230,28 @@ func extractFromFiles(files []string, dirPath string) (*domain.Album, error) {
 			album.AlbumArtist = universalArtists
 			// Ensure AlbumArtist performers are present on each track (unless Various Artists)
 			if !strings.EqualFold(strings.TrimSpace(domain.FormatArtists(album.AlbumArtist)), "Various Artists") {
-				ensureArtistsOnTracks(album.Tracks, album.AlbumArtist)
+				propagateAlbumArtists(album.Tracks, album.AlbumArtist, opts)
 			}
 		}
 	}
 
+	// Folder-name credits are guesses: say so, so they get checked before tagging
+	if seededComposer > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: composer %q taken from directory name for %d tracks without COMPOSER tags (low confidence)\n", credits.Composer, seededComposer)
+	}
+	if len(album.AlbumArtist) == 0 && len(credits.Performers) > 0 {
+		album.AlbumArtist = credits.Performers
+		propagateAlbumArtists(album.Tracks, album.AlbumArtist, opts)
+		fmt.Fprintf(os.Stderr, "Warning: album artists %s taken from directory name (low confidence; check names and roles)\n", domain.FormatArtists(credits.Performers))
+	}
+
 	// Try to extract folder name metadata if album title missing
 	if album.Title == MissingTitle {
 		if _, title, year := parseDirectoryName(dirPath); title != "" {
 			album.Title = title
+			if credits.Work != "" {
+				album.Title = credits.Work
+			}
 			if year > 0 && album.OriginalYear == MissingYear {
 				album.OriginalYear = year
 			}
@@ -211,10 +272,12 @@ func extractFromFiles(files []string, dirPath string) (*domain.Album, error) {
 
 // albumMetadata is a temporary structure for album-level data
 type albumMetadata struct {
-	Title        string
-	OriginalYear int
-	Edition      *domain.Edition
-	AlbumArtist  []domain.Artist
+	Title          string
+	OriginalYear   int
+	RecordingYears []int
+	Edition        *domain.Edition
+	AlbumArtist    []domain.Artist
+	Genre          string
 }
 
 // extractAlbumMetadata extracts album-level metadata from a FLAC file's tags.
@@ -226,15 +289,9 @@ func extractAlbumMetadata(filePath string) (albumMetadata, string) {
 		AlbumArtist:  nil,
 	}
 
-	f, err := os.Open(filePath)
+	metadata, err := tagging.ReadTags(filePath)
 	if err != nil {
-		return meta, fmt.Sprintf("failed to open file for album Metadata: %v", err)
-	}
-	defer f.Close()
-
-	metadata, err := tag.ReadFrom(f)
-	if err != nil {
-		return meta, fmt.Sprintf("failed to read tags for album Metadata: %v", err)
+		return meta, fmt.Sprintf("failed to read album Metadata: %v", err)
 	}
 
 	// Extract album title
@@ -259,6 +316,11 @@ func extractAlbumMetadata(filePath string) (albumMetadata, string) {
 		meta.OriginalYear = year
 	}
 
+	// Recording sessions are tracked separately from the release year
+	meta.RecordingYears = domain.ParseYears(vorbisTags["RECORDINGDATE"])
+
+	meta.Genre = strings.TrimSpace(metadata.Genre())
+
 	// Extract album artist
 	if albumArtistStr := metadata.AlbumArtist(); albumArtistStr != "" {
 		// Parse the string into artists (roles will be inferred)
@@ -280,10 +342,33 @@ func extractAlbumMetadata(filePath string) (albumMetadata, string) {
 	return meta, ""
 }
 
-// readVorbisCommentTags reads all Vorbis comment tags from a FLAC file.
+// readVorbisCommentTags reads all Vorbis comment tags from a FLAC file, or the
+// ID3 frames of a DSD file under their Vorbis names.
 // Returns a map of tag names (uppercase) to values.
 func readVorbisCommentTags(filePath string) map[string]string {
 	tags := make(map[string]string)
+	for name, values := range readVorbisCommentValues(filePath) {
+		tags[name] = values[len(values)-1]
+	}
+	return tags
+}
+
+// readVorbisCommentValues returns every value of each Vorbis comment field, in
+// file order, keyed by upper-case field name. Fields may repeat, as in one
+// COMPOSER= or PERFORMER= field per artist. A DSD file's ID3 tags hold one value
+// per field.
+func readVorbisCommentValues(filePath string) map[string][]string {
+	tags := make(map[string][]string)
+	if tagging.IsDSD(filePath) {
+		dsdTags, err := tagging.ReadDSDTags(filePath)
+		if err != nil {
+			return tags
+		}
+		for name, value := range dsdTags {
+			tags[name] = []string{value}
+		}
+		return tags
+	}
 
 	flacFile, err := flac.ParseFile(filePath)
 	if err != nil {
@@ -303,8 +388,7 @@ func readVorbisCommentTags(filePath string) map[string]string {
 				parts := strings.SplitN(comment, "=", 2)
 				if len(parts) == 2 {
 					tagName := strings.ToUpper(parts[0])
-					tagValue := parts[1]
-					tags[tagName] = tagValue
+					tags[tagName] = append(tags[tagName], parts[1])
 				}
 			}
 			break
@@ -332,6 +416,25 @@ func extractEditionFromTags(tags map[string]string) *domain.Edition {
 		found = true
 	}
 
+	// Read BARCODE tag (some taggers write UPC or EAN instead)
+	for _, key := range []string{"BARCODE", "UPC", "EAN"} {
+		if barcode := strings.TrimSpace(tags[key]); barcode != "" {
+			edition.Barcode = barcode
+			found = true
+			break
+		}
+	}
+
+	// Read release identifiers written by this tool, Picard or the Discogs plugins
+	if id, err := strconv.Atoi(strings.TrimSpace(tags["DISCOGS_RELEASE_ID"])); err == nil && id > 0 {
+		edition.DiscogsReleaseID = id
+		found = true
+	}
+	if mbid := strings.TrimSpace(tags["MUSICBRAINZ_ALBUMID"]); mbid != "" {
+		edition.MusicBrainzAlbumID = mbid
+		found = true
+	}
+
 	// Read DATE tag (edition year)
 	if dateStr := tags["DATE"]; dateStr != "" {
 		if year, err := strconv.Atoi(strings.TrimSpace(dateStr)); err == nil && year > 0 {
@@ -375,16 +478,11 @@ func extractEditionFromComment(comment string) *domain.Edition {
 }
 
 // extractTrackMetadataWithAlbumArtist extracts track-level metadata and also returns ALBUMARTIST value.
-func extractTrackMetadataWithAlbumArtist(filePath string, baseDir string) (*domain.Track, string, error) {
-	f, err := os.Open(filePath)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to open file: %w", err)
-	}
-	defer f.Close()
-
-	metadata, err := tag.ReadFrom(f)
+// Hidden (pregap) tracks are marked Hidden, numbered 0 and titled domain.HiddenTrackTitle unless tagged with a title.
+func extractTrackMetadataWithAlbumArtist(filePath string, baseDir string, hidden, allowMissingComposer bool) (*domain.Track, string, error) {
+	metadata, err := tagging.ReadTags(filePath)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read tags: %w", err)
+		return nil, "", err
 	}
 
 	track := &domain.Track{
@@ -399,7 +497,10 @@ func extractTrackMetadataWithAlbumArtist(filePath string, baseDir string) (*doma
 
 	// Extract track number
 	trackNum, _ := metadata.Track()
-	if trackNum > 0 {
+	if hidden {
+		track.Track = 0
+		track.Hidden = true
+	} else if trackNum > 0 {
 		track.Track = trackNum
 	} else {
 		// Try to extract from filename
@@ -422,36 +523,87 @@ func extractTrackMetadataWithAlbumArtist(filePath string, baseDir string) (*doma
 	// Extract title
 	if title := metadata.Title(); title != "" {
 		track.Title = title
+	} else if hidden {
+		track.Title = domain.HiddenTrackTitle
 	} else {
 		// Use filename without extension as fallback
 		track.Title = extractTitleFromFilename(filePath)
 	}
 
-	// Extract composer (required field)
-	if composer := metadata.Composer(); composer != "" {
-		track.Artists = append(track.Artists, domain.Artist{Name: composer, Role: domain.RoleComposer})
-	} else {
-		return track, "", fmt.Errorf("no composer found in tags")
+	// Composition and recording dates and disc subtitle. Artist tags may repeat,
+	// one field per artist, which the tag facade collapses to the last
+	vorbisValues := readVorbisCommentValues(filePath)
+	vorbisTags := make(map[string]string, len(vorbisValues))
+	for name, values := range vorbisValues {
+		vorbisTags[name] = values[len(values)-1]
+	}
+	if years := domain.ParseYears(vorbisTags["COMPOSITIONDATE"]); len(years) > 0 {
+		track.CompositionYear = years[0]
+	}
+	track.RecordingYears = domain.ParseYears(vorbisTags["RECORDINGDATE"])
+	track.DiscSubtitle = strings.TrimSpace(vorbisTags["DISCSUBTITLE"])
+	track.MusicBrainzTrackID = strings.TrimSpace(vorbisTags["MUSICBRAINZ_TRACKID"])
+
+	// Channel count from STREAMINFO, to tell stereo and surround files apart, and
+	// playing time, to total works in descriptions
+	if channels, err := tagging.ReadChannelCount(filePath); err == nil {
+		track.Channels = channels
+	}
+	if duration, err := tagging.ReadDuration(filePath); err == nil {
+		track.Duration = duration
+	}
+
+	// Extract composers, one per COMPOSER field (required unless the album allows missing composers)
+	composers := vorbisValues["COMPOSER"]
+	if len(composers) < 2 {
+		composers = []string{metadata.Composer()}
+	}
+	for _, composer := range composers {
+		if composer = strings.TrimSpace(composer); composer != "" {
+			track.Artists = append(track.Artists, domain.Artist{Name: composer, Role: domain.RoleComposer})
+		}
+	}
+	if len(track.Artists) == 0 && !allowMissingComposer {
+		return track, "", domain.ErrNoComposer
 	}
 
-	// Extract artists
-	if artist := metadata.Artist(); artist != "" {
+	// Extract artists, one per field when ARTIST repeats
+	if artists := vorbisValues["ARTIST"]; len(artists) > 1 {
+		track.Artists = append(track.Artists, domain.ParseArtistFields(artists)...)
+	} else if artist := metadata.Artist(); artist != "" {
 		track.Artists = append(track.Artists, domain.ParseArtistField(artist)...)
-	} else if albumArtist := metadata.AlbumArtist(); albumArtist != "" {
+	} else if albumArtists := vorbisValues["ALBUMARTIST"]; len(albumArtists) > 1 {
 		// Fallback to album artist if artist tag missing
+		track.Artists = append(track.Artists, domain.ParseArtistFields(albumArtists)...)
+	} else if albumArtist := metadata.AlbumArtist(); albumArtist != "" {
 		track.Artists = append(track.Artists, domain.ParseArtistField(albumArtist)...)
 	}
 
-	// Extract ALBUMARTIST value for verification (but don't store in track)
+	// Extract ALBUMARTIST value for verification (but don't store in track), repeated
+	// fields listed as FormatArtists writes them
 	albumArtistValue := metadata.AlbumArtist()
+	if albumArtists := vorbisValues["ALBUMARTIST"]; len(albumArtists) > 1 {
+		albumArtistValue = strings.Join(albumArtists, ", ")
+	}
 
 	// Check for DJ tags - error exit if found
-	vorbisTags := readVorbisCommentTags(filePath)
 	if djTag := vorbisTags["DJ"]; djTag != "" {
 		fmt.Fprintf(os.Stderr, "Error: DJ tag detected in file: %s. DJ tags are not yet supported.\n", filePath)
 		os.Exit(1)
 	}
 
+	// PERFORMER credits carry soloists' instruments: "Martha Argerich (piano)", in one
+	// field or one field each
+	applyPerformerCredits(track, strings.Join(vorbisValues["PERFORMER"], ";"))
+	// CONDUCTOR names the conductor, who may also be a soloist directing from the keyboard
+	for _, conductor := range vorbisValues["CONDUCTOR"] {
+		applyConductorCredit(track, conductor)
+	}
+	// ENSEMBLE names the orchestras and choirs
+	for _, ensemble := range vorbisValues["ENSEMBLE"] {
+		applyEnsembleCredit(track, ensemble)
+	}
+
 	// Set relative filename (add before the final return)
 	relPath, err := filepath.Rel(baseDir, filePath)
 	if err == nil {
@@ -483,6 +635,13 @@ func extractTrackNumberFromFilename(filePath string) int {
 	return 0
 }
 
+// isHiddenTrackFilename reports whether the filename is numbered as track 0 ("00 - Pregap.flac").
+func isHiddenTrackFilename(filePath string) bool {
+	return hiddenTrackFilenamePattern.MatchString(filepath.Base(filePath))
+}
+
+var hiddenTrackFilenamePattern = regexp.MustCompile(`^0{1,3}[\s\-._]`)
+
 // extractDiscFromPath attempts to extract disc number from file path.
 // Looks for "CD1", "CD2", "Disc 1", "Disc 2", etc.
 func extractDiscFromPath(filePath string) int {
@@ -529,20 +688,86 @@ func parseDirectoryName(dirPath string) (folderName string, title string, year i
 	dirName := filepath.Base(dirPath)
 
 	// Extract year from brackets or parentheses
-	yearPattern := regexp.MustCompile(`[\[\(](\d{4})[\]\)]`)
-	if matches := yearPattern.FindStringSubmatch(dirName); len(matches) > 1 {
+	if matches := domain.DirectoryYearPattern.FindStringSubmatch(dirName); len(matches) > 1 {
 		year, _ = strconv.Atoi(matches[1])
 	}
 
-	// Remove year and format indicators for title
-	title = yearPattern.ReplaceAllString(dirName, "")
-
-	// Remove format indicators like [FLAC], [MP3], etc.
-	formatPattern := regexp.MustCompile(`\s*\[(FLAC|MP3|AAC|ALAC|WAV|APE|WV|24-\d+|16-\d+)\]`)
-	title = formatPattern.ReplaceAllString(title, "")
-
-	// Clean up whitespace
+	// Remove the year, then format indicators like [FLAC] or {24-96}, for the title
+	title, _ = domain.StripTitleJunk(domain.DirectoryYearPattern.ReplaceAllString(dirName, ""))
 	title = strings.TrimSpace(title)
 
 	return dirName, title, year
 }
+
+// directoryCredits are the credits a folder name gives when the tags have none.
+// They are guesses: the caller reports them as such.
+type directoryCredits struct {
+	Composer   string          // Expanded to the full name when the surname is unambiguous
+	Work       string          // The work or album title between the credits
+	Performers []domain.Artist // Roles inferred from the names
+}
+
+// parseDirectoryCredits recognizes the credits in common folder naming patterns:
+// "Composer - Work - Performer (Year)", "Performer - Composer: Work" and
+// "Composer - Work" when the work is recognizably one ("Beethoven - Symphony
+// No. 5"). Other names yield no credits.
+func parseDirectoryCredits(dirPath string) directoryCredits {
+	_, title, _ := parseDirectoryName(dirPath)
+	parts := strings.Split(strings.ReplaceAll(title, " – ", " - "), " - ")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	var credits directoryCredits
+	var performers string
+	switch {
+	case len(parts) >= 3:
+		credits.Composer, credits.Work, performers = parts[0], strings.Join(parts[1:len(parts)-1], " - "), parts[len(parts)-1]
+	case len(parts) == 2:
+		if composer, work, ok := strings.Cut(parts[1], ": "); ok && !looksLikeWork(composer) {
+			performers, credits.Composer, credits.Work = parts[0], composer, work
+		} else if looksLikeWork(parts[1]) {
+			credits.Composer, credits.Work = parts[0], parts[1]
+		}
+	}
+	if credits.Composer == "" || credits.Work == "" {
+		return directoryCredits{}
+	}
+
+	if full, ok := domain.ComposerFullName(credits.Composer); ok {
+		credits.Composer = full
+	}
+	for _, inference := range ParseArtistList(strings.ReplaceAll(performers, ";", ",")) {
+		credits.Performers = append(credits.Performers, inference.Artist)
+	}
+	return credits
+}
+
+// looksLikeWork reports whether s names a work type ("Symphony No. 5", "Goldberg Variations").
+func looksLikeWork(s string) bool {
+	lower := strings.ToLower(s)
+	for _, word := range workWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// classicalGenres are GENRE words that mark an album as classical.
+var classicalGenres = []string{
+	"classical", "baroque", "renaissance", "medieval", "early music", "opera", "choral",
+	"chamber", "orchestral", "symphon", "concert", "lied", "oratorio", "klassik", "classique",
+}
+
+// isClassicalGenre reports whether a GENRE tag names a classical genre. Crossover
+// discs tagged "Jazz", "Pop" or "Soundtrack" are not, and may lack composers.
+func isClassicalGenre(genre string) bool {
+	genre = strings.ToLower(genre)
+	for _, g := range classicalGenres {
+		if strings.Contains(genre, g) {
+			return true
+		}
+	}
+	return false
+}
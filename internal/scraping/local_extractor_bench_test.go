@@ -0,0 +1,100 @@
+package scraping
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-flac/flacvorbis"
+	"github.com/go-flac/go-flac"
+)
+
+// writeTaggedFLAC writes a FLAC file holding a one-minute 44.1 kHz stereo
+// STREAMINFO, the given Vorbis comments and a few bytes of undecodable frame
+// data, which extraction never reads.
+func writeTaggedFLAC(tb testing.TB, path string, comments ...string) {
+	tb.Helper()
+
+	info := make([]byte, 34)
+	// Sample rate (20 bits), channels-1 (3), bits per sample-1 (5), total samples (36)
+	packed := uint64(44100)<<44 | uint64(1)<<41 | uint64(15)<<36 | uint64(44100*60)
+	binary.BigEndian.PutUint64(info[10:18], packed)
+
+	cmt := flacvorbis.New()
+	for _, comment := range comments {
+		key, value, _ := strings.Cut(comment, "=")
+		if err := cmt.Add(key, value); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	block := cmt.Marshal()
+	f := &flac.File{
+		Meta:   []*flac.MetaDataBlock{{Type: flac.StreamInfo, Data: info}, &block},
+		Frames: []byte{0xFF, 0xF8, 0, 0},
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		tb.Fatal(err)
+	}
+	if err := f.Save(path); err != nil {
+		tb.Fatalf("Failed to write tagged FLAC: %v", err)
+	}
+}
+
+// BenchmarkExtractFromDirectory measures local extraction of a synthetic
+// 500-track album on five discs.
+func BenchmarkExtractFromDirectory(b *testing.B) {
+	dir := filepath.Join(b.TempDir(), "Bach - Complete Cantatas (2000) [FLAC]")
+	for i := range 500 {
+		disc, track := i/100+1, i%100+1
+		title := fmt.Sprintf("Cantata BWV %d: Aria", i+1)
+		writeTaggedFLAC(b, filepath.Join(dir, fmt.Sprintf("CD%d", disc), fmt.Sprintf("%02d - %s.flac", track, title)),
+			"TITLE="+title,
+			"ALBUM=Complete Cantatas",
+			"ALBUMARTIST=Netherlands Bach Collegium",
+			"ARTIST=Netherlands Bach Collegium",
+			"COMPOSER=Johann Sebastian Bach",
+			"CONDUCTOR=Pieter Jan Leusink",
+			"GENRE=Classical",
+			"DATE=2000",
+			"LABEL=Brilliant Classics",
+			"CATALOGNUMBER=99363",
+			fmt.Sprintf("DISCNUMBER=%d", disc),
+			fmt.Sprintf("TRACKNUMBER=%d", track),
+		)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		album, err := ExtractFromDirectory(dir)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(album.Tracks) != 500 {
+			b.Fatalf("extracted %d tracks, want 500", len(album.Tracks))
+		}
+	}
+}
+
+// BenchmarkParseTracklist measures parsing a 500-line booklet tracklist.
+func BenchmarkParseTracklist(b *testing.B) {
+	var text strings.Builder
+	for disc := 1; disc <= 5; disc++ {
+		fmt.Fprintf(&text, "CD %d\n", disc)
+		for track := 1; track <= 100; track++ {
+			fmt.Fprintf(&text, "%d. Johann Sebastian Bach: Cantata BWV %d: Aria %d:%02d\n", track, (disc-1)*100+track, track%9, track%60)
+		}
+	}
+	input := text.String()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := ParseTracklist(strings.NewReader(input), []string{"Johann Sebastian Bach"}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
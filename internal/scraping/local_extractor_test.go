@@ -1,7 +1,10 @@
 package scraping
 
 import (
+	"slices"
 	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
 )
 
 func TestParseDirectoryName(t *testing.T) {
@@ -67,6 +70,60 @@ func TestParseDirectoryName(t *testing.T) {
 	}
 }
 
+func TestParseDirectoryCredits(t *testing.T) {
+	tests := []struct {
+		Name           string
+		DirPath        string
+		WantComposer   string
+		WantWork       string
+		WantPerformers []domain.Artist
+	}{
+		{
+			Name:         "composer, work, performers",
+			DirPath:      "/music/Beethoven - Symphony No. 9 - Chicago Symphony Orchestra, Georg Solti (1972) [FLAC]",
+			WantComposer: "Ludwig van Beethoven",
+			WantWork:     "Symphony No. 9",
+			WantPerformers: []domain.Artist{
+				{Name: "Chicago Symphony Orchestra", Role: domain.RoleEnsemble},
+				{Name: "Georg Solti", Role: domain.RoleConductor},
+			},
+		},
+		{
+			Name:           "performer, composer: work",
+			DirPath:        "/music/Glenn Gould - Bach: Goldberg Variations [1981]",
+			WantComposer:   "Bach", // Ambiguous surname kept as is
+			WantWork:       "Goldberg Variations",
+			WantPerformers: []domain.Artist{{Name: "Glenn Gould", Role: domain.RoleSoloist}},
+		},
+		{
+			Name:         "composer and recognizable work",
+			DirPath:      "/music/Schubert - Piano Sonata D. 960: Molto moderato",
+			WantComposer: "Franz Schubert",
+			WantWork:     "Piano Sonata D. 960: Molto moderato",
+		},
+		{
+			Name:    "artist and album",
+			DirPath: "/music/Glenn Gould - The Complete Recordings",
+		},
+		{
+			Name:    "title only",
+			DirPath: "/music/Goldberg Variations [FLAC]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			got := parseDirectoryCredits(tt.DirPath)
+			if got.Composer != tt.WantComposer || got.Work != tt.WantWork {
+				t.Errorf("parseDirectoryCredits() = %q, %q; want %q, %q", got.Composer, got.Work, tt.WantComposer, tt.WantWork)
+			}
+			if !slices.Equal(got.Performers, tt.WantPerformers) {
+				t.Errorf("parseDirectoryCredits() performers = %v, want %v", got.Performers, tt.WantPerformers)
+			}
+		})
+	}
+}
+
 func TestExtractTrackNumberFromFilename(t *testing.T) {
 	tests := []struct {
 		Name     string
@@ -94,6 +151,22 @@ func TestExtractTrackNumberFromFilename(t *testing.T) {
 	}
 }
 
+func TestIsHiddenTrackFilename(t *testing.T) {
+	tests := map[string]bool{
+		"/music/00 - Pregap.flac":  true,
+		"/music/0 Hidden.flac":     true,
+		"/music/000_Hidden.flac":   true,
+		"/music/01 - Aria.flac":    false,
+		"/music/007 Overture.flac": false,
+		"/music/Hidden Track.flac": false,
+	}
+	for filename, want := range tests {
+		if got := isHiddenTrackFilename(filename); got != want {
+			t.Errorf("isHiddenTrackFilename(%q) = %v, want %v", filename, got, want)
+		}
+	}
+}
+
 func TestExtractDiscFromPath(t *testing.T) {
 	tests := []struct {
 		Name string
@@ -270,6 +343,19 @@ func TestExtractEditionFromTags(t *testing.T) {
 	}
 }
 
+func TestExtractEditionFromTags_Identifiers(t *testing.T) {
+	got := extractEditionFromTags(map[string]string{
+		"DISCOGS_RELEASE_ID":  " 1234567 ",
+		"MUSICBRAINZ_ALBUMID": "5d2a6a0e-8c7f-4f3b-9b1e-0c7d2e4a6b8f",
+	})
+	if got == nil || got.DiscogsReleaseID != 1234567 || got.MusicBrainzAlbumID != "5d2a6a0e-8c7f-4f3b-9b1e-0c7d2e4a6b8f" {
+		t.Errorf("extractEditionFromTags() = %+v, want both identifiers", got)
+	}
+	if got := extractEditionFromTags(map[string]string{"DISCOGS_RELEASE_ID": "r1234567"}); got != nil {
+		t.Errorf("extractEditionFromTags() = %+v, want nil for a malformed ID", got)
+	}
+}
+
 func TestExtractEditionFromComment(t *testing.T) {
 	tests := []struct {
 		Name        string
@@ -337,3 +423,23 @@ func TestExtractEditionFromComment(t *testing.T) {
 		})
 	}
 }
+
+func TestIsClassicalGenre(t *testing.T) {
+	tests := map[string]bool{
+		"Classical":                 true,
+		"Baroque":                   true,
+		"Opera":                     true,
+		"Chamber Music":             true,
+		"Klassik":                   true,
+		"Stage & Screen; Classical": true,
+		"Jazz":                      false,
+		"Pop":                       false,
+		"Soundtrack":                false,
+		"Contemporary Jazz":         false,
+	}
+	for genre, want := range tests {
+		if got := isClassicalGenre(genre); got != want {
+			t.Errorf("isClassicalGenre(%q) = %v, want %v", genre, got, want)
+		}
+	}
+}
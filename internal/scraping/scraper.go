@@ -86,6 +86,9 @@ func DefaultRegistry() *Registry {
 	// registry.Register(NewNaxosExtractor())
 	// etc.
 
+	// Generic schema.org JSON-LD handles any URL, so it must come last
+	registry.Register(NewJSONLDExtractor())
+
 	return registry
 }
 
@@ -3,9 +3,12 @@ package scraping
 import (
 	"html"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+	"github.com/cehbz/classical-tagger/internal/titlecase"
 )
 
 // decodeHTMLEntities decodes HTML entities to their Unicode equivalents.
@@ -58,6 +61,8 @@ func cleanWhitespace(s string) string {
 }
 
 // toTitleCase converts ALL CAPS to Title Case while preserving some exceptions.
+// Protected words ("BWV", "RIAS", "II", plus any from config) keep their spelling,
+// including within hyphenated words ("RIAS-KAMMERCHOR" -> "RIAS-Kammerchor").
 func toTitleCase(s string) string {
 	words := strings.Fields(s)
 
@@ -75,21 +80,28 @@ func toTitleCase(s string) string {
 			continue
 		}
 
-		wordLower := strings.ToLower(word)
+		parts := strings.Split(word, "-")
+		for j, part := range parts {
+			if spelled, ok := titlecase.Lookup(part); ok {
+				parts[j] = spelled
+				continue
+			}
+			if part == "" {
+				continue
+			}
 
-		// First word always capitalized
-		if i == 0 {
-			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
-			continue
-		}
+			partLower := strings.ToLower(part)
+
+			// Small words stay lowercase, except as the first word
+			if i > 0 && j == 0 && len(parts) == 1 && lowercase[partLower] && len(part) <= 3 {
+				parts[j] = partLower
+				continue
+			}
 
-		// Check if it should be lowercase
-		if lowercase[wordLower] && len(word) <= 3 {
-			words[i] = wordLower
-		} else {
 			// Title case: First letter upper, rest lower
-			words[i] = strings.ToUpper(word[:1]) + strings.ToLower(word[1:])
+			parts[j] = strings.ToUpper(part[:1]) + partLower[1:]
 		}
+		words[i] = strings.Join(parts, "-")
 	}
 
 	return strings.Join(words, " ")
@@ -193,6 +205,36 @@ func mergePerformers(existing []domain.Artist, additional []domain.Artist) []dom
 	return result
 }
 
+// propagateAlbumArtists copies album artists onto tracks according to opts.ArtistPropagation.
+func propagateAlbumArtists(tracks []*domain.Track, albumArtists []domain.Artist, opts ExtractOptions) {
+	switch opts.ArtistPropagation {
+	case domain.ArtistKeepSparse:
+		return
+	case domain.ArtistPrompt:
+		for _, artist := range albumArtists {
+			missing := tracksMissingArtist(tracks, artist)
+			if len(missing) > 0 && opts.ConfirmPropagation != nil && opts.ConfirmPropagation(artist, missing) {
+				ensureArtistsOnTracks(missing, []domain.Artist{artist})
+			}
+		}
+	default:
+		ensureArtistsOnTracks(tracks, albumArtists)
+	}
+}
+
+// tracksMissingArtist returns the tracks that don't credit artist (by name and role).
+func tracksMissingArtist(tracks []*domain.Track, artist domain.Artist) []*domain.Track {
+	var missing []*domain.Track
+	for _, track := range tracks {
+		if !slices.ContainsFunc(track.Artists, func(a domain.Artist) bool {
+			return a.Name == artist.Name && a.Role == artist.Role
+		}) {
+			missing = append(missing, track)
+		}
+	}
+	return missing
+}
+
 // ensureArtistsOnTracks ensures the given artists exist on every track's artist list.
 // Matching is done by name AND role. Missing artists are appended to the track's artists.
 func ensureArtistsOnTracks(tracks []*domain.Track, artistsToEnsure []domain.Artist) {
@@ -211,3 +253,86 @@ func ensureArtistsOnTracks(tracks []*domain.Track, artistsToEnsure []domain.Arti
 		}
 	}
 }
+
+// applyPerformerCredits records instruments from a PERFORMER tag ("Name (instrument)",
+// several separated by semicolons) on the track's matching artists, and the
+// characters singers sing ("Name (soprano, Violetta)"). Credited performers
+// without a role become soloists, and ones missing from the ARTIST tag are added.
+func applyPerformerCredits(track *domain.Track, performerTag string) {
+	for _, credit := range strings.Split(performerTag, ";") {
+		name, instrument := domain.ParseCredit(credit)
+		if name == "" {
+			continue
+		}
+		instrument, character := domain.SplitCharacter(instrument)
+		found := false
+		for i := range track.Artists {
+			a := &track.Artists[i]
+			if a.Role == domain.RoleComposer || normalize.Name(a.Name) != normalize.Name(name) {
+				continue
+			}
+			found = true
+			if a.Role == domain.RoleUnknown {
+				a.Role = domain.RoleSoloist
+			}
+			if a.Instrument == "" {
+				a.Instrument = instrument
+			}
+			if a.Character == "" {
+				a.Character = character
+			}
+		}
+		if !found {
+			track.Artists = append(track.Artists, domain.Artist{Name: name, Role: domain.RoleSoloist, Instrument: instrument, Character: character})
+		}
+	}
+}
+
+// applyConductorCredit records the conductor from a CONDUCTOR tag. A matching artist
+// without a role becomes the conductor; one credited with another role (a soloist
+// directing from the keyboard, listed once in the ARTIST tag) gains the conductor
+// credit beside it, as does a conductor missing from the ARTIST tag.
+func applyConductorCredit(track *domain.Track, conductorTag string) {
+	name := strings.TrimSpace(conductorTag)
+	if name == "" {
+		return
+	}
+	for i := range track.Artists {
+		a := &track.Artists[i]
+		if normalize.Name(a.Name) != normalize.Name(name) {
+			continue
+		}
+		switch a.Role {
+		case domain.RoleConductor:
+			return
+		case domain.RoleUnknown:
+			a.Role = domain.RoleConductor
+			return
+		}
+		if a.Role != domain.RoleComposer {
+			name = a.Name
+		}
+	}
+	track.Artists = append(track.Artists, domain.Artist{Name: name, Role: domain.RoleConductor})
+}
+
+// applyEnsembleCredit records an ensemble from an ENSEMBLE tag field. A matching
+// artist without a role becomes the ensemble; an ensemble missing from the ARTIST
+// tag is added.
+func applyEnsembleCredit(track *domain.Track, ensembleTag string) {
+	name := strings.TrimSpace(ensembleTag)
+	if name == "" {
+		return
+	}
+	for i := range track.Artists {
+		a := &track.Artists[i]
+		if normalize.Name(a.Name) != normalize.Name(name) {
+			continue
+		}
+		if a.Role == domain.RoleUnknown {
+			a.Role = domain.RoleEnsemble
+		}
+		return
+	}
+	track.Artists = append(track.Artists, domain.Artist{Name: name, Role: domain.RoleEnsemble})
+}
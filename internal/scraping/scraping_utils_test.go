@@ -1,6 +1,7 @@
 package scraping
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
@@ -171,6 +172,21 @@ func TestToTitleCase(t *testing.T) {
 			Input: "MUSIC OF LA RUE",
 			Want:  "Music of la Rue",
 		},
+		{
+			Name:  "protected catalogue prefix",
+			Input: "MASS IN B MINOR, BWV 232",
+			Want:  "Mass in B Minor, BWV 232",
+		},
+		{
+			Name:  "protected ensemble and numeral",
+			Input: "RIAS KAMMERCHOR - PART II",
+			Want:  "RIAS Kammerchor - Part II",
+		},
+		{
+			Name:  "protected word in hyphenated word",
+			Input: "WDR-SINFONIEORCHESTER",
+			Want:  "WDR-Sinfonieorchester",
+		},
 	}
 
 	for _, tt := range tests {
@@ -641,3 +657,158 @@ func TestMergePerformers(t *testing.T) {
 		})
 	}
 }
+
+func TestPropagateAlbumArtists(t *testing.T) {
+	composer := domain.Artist{Name: "Bach", Role: domain.RoleComposer}
+	choir := domain.Artist{Name: "RIAS-Kammerchor", Role: domain.RoleEnsemble}
+	orchestra := domain.Artist{Name: "Akademie für Alte Musik Berlin", Role: domain.RoleEnsemble}
+	albumArtists := []domain.Artist{choir, orchestra}
+
+	build := func() []*domain.Track {
+		return []*domain.Track{
+			{Track: 1, Artists: []domain.Artist{composer, choir}},
+			{Track: 2, Artists: []domain.Artist{composer, orchestra}},
+		}
+	}
+
+	tests := []struct {
+		Name    string
+		Opts    ExtractOptions
+		Confirm []string // album artists the prompt accepts
+		Want    []int    // artist counts per track
+	}{
+		{Name: "propagate by default", Want: []int{3, 3}},
+		{Name: "keep sparse", Opts: ExtractOptions{ArtistPropagation: domain.ArtistKeepSparse}, Want: []int{2, 2}},
+		{Name: "prompt accepts one", Opts: ExtractOptions{ArtistPropagation: domain.ArtistPrompt}, Confirm: []string{"RIAS-Kammerchor"}, Want: []int{2, 3}},
+		{Name: "prompt without callback", Opts: ExtractOptions{ArtistPropagation: domain.ArtistPrompt}, Want: []int{2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if tt.Confirm != nil {
+				tt.Opts.ConfirmPropagation = func(artist domain.Artist, missing []*domain.Track) bool {
+					if len(missing) != 1 {
+						t.Errorf("%s missing from %d tracks, want 1", artist.Name, len(missing))
+					}
+					for _, name := range tt.Confirm {
+						if name == artist.Name {
+							return true
+						}
+					}
+					return false
+				}
+			}
+
+			tracks := build()
+			propagateAlbumArtists(tracks, albumArtists, tt.Opts)
+			for i, track := range tracks {
+				if len(track.Artists) != tt.Want[i] {
+					t.Errorf("track %d has %d artists, want %d: %v", track.Track, len(track.Artists), tt.Want[i], track.Artists)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyPerformerCredits(t *testing.T) {
+	track := &domain.Track{Artists: []domain.Artist{
+		{Name: "Franz Schubert", Role: domain.RoleComposer},
+		{Name: "Dietrich Fischer-Dieskau", Role: domain.RoleUnknown},
+		{Name: "Alfred Brendel", Role: domain.RoleSoloist},
+	}}
+
+	applyPerformerCredits(track, "Dietrich Fischer-Dieskau (baritone); Alfred Brendel (piano); Aurèle Nicolet (flute)")
+
+	want := []domain.Artist{
+		{Name: "Franz Schubert", Role: domain.RoleComposer},
+		{Name: "Dietrich Fischer-Dieskau", Role: domain.RoleSoloist, Instrument: "baritone"},
+		{Name: "Alfred Brendel", Role: domain.RoleSoloist, Instrument: "piano"},
+		{Name: "Aurèle Nicolet", Role: domain.RoleSoloist, Instrument: "flute"},
+	}
+	if len(track.Artists) != len(want) {
+		t.Fatalf("artists = %v, want %v", track.Artists, want)
+	}
+	for i := range want {
+		if track.Artists[i] != want[i] {
+			t.Errorf("artist %d = %+v, want %+v", i, track.Artists[i], want[i])
+		}
+	}
+
+	// An empty tag changes nothing
+	applyPerformerCredits(track, "")
+	if len(track.Artists) != len(want) {
+		t.Errorf("empty PERFORMER tag added artists: %v", track.Artists)
+	}
+}
+
+func TestApplyPerformerCredits_Characters(t *testing.T) {
+	track := &domain.Track{Artists: []domain.Artist{
+		{Name: "Anna Netrebko", Role: domain.RoleUnknown},
+	}}
+
+	applyPerformerCredits(track, "Anna Netrebko (soprano, Violetta); Rolando Villazón (tenor, Alfredo); Eduard Brunner (clarinet)")
+
+	want := []domain.Artist{
+		{Name: "Anna Netrebko", Role: domain.RoleSoloist, Instrument: "soprano", Character: "Violetta"},
+		{Name: "Rolando Villazón", Role: domain.RoleSoloist, Instrument: "tenor", Character: "Alfredo"},
+		{Name: "Eduard Brunner", Role: domain.RoleSoloist, Instrument: "clarinet"},
+	}
+	if !slices.Equal(track.Artists, want) {
+		t.Errorf("artists = %+v, want %+v", track.Artists, want)
+	}
+}
+
+func TestApplyConductorCredit(t *testing.T) {
+	// A soloist directing from the keyboard is listed once in ARTIST
+	track := &domain.Track{Artists: []domain.Artist{
+		{Name: "Wolfgang Amadeus Mozart", Role: domain.RoleComposer},
+		{Name: "Murray Perahia", Role: domain.RoleSoloist, Instrument: "piano"},
+		{Name: "English Chamber Orchestra", Role: domain.RoleUnknown},
+	}}
+	applyConductorCredit(track, "Murray Perahia")
+	applyConductorCredit(track, "Murray Perahia") // Already credited
+	want := []domain.Artist{
+		{Name: "Wolfgang Amadeus Mozart", Role: domain.RoleComposer},
+		{Name: "Murray Perahia", Role: domain.RoleSoloist, Instrument: "piano"},
+		{Name: "English Chamber Orchestra", Role: domain.RoleUnknown},
+		{Name: "Murray Perahia", Role: domain.RoleConductor},
+	}
+	if !slices.Equal(track.Artists, want) {
+		t.Errorf("artists = %+v, want %+v", track.Artists, want)
+	}
+
+	// A conductor without a role from ARTIST takes the role
+	track = &domain.Track{Artists: []domain.Artist{
+		{Name: "Berliner Philharmoniker", Role: domain.RoleUnknown},
+		{Name: "Herbert von Karajan", Role: domain.RoleUnknown},
+	}}
+	applyConductorCredit(track, "Herbert von Karajan")
+	if len(track.Artists) != 2 || track.Artists[1].Role != domain.RoleConductor {
+		t.Errorf("artists = %+v, want Karajan as conductor", track.Artists)
+	}
+
+	// An empty tag changes nothing
+	applyConductorCredit(track, "")
+	if len(track.Artists) != 2 {
+		t.Errorf("empty CONDUCTOR tag added artists: %v", track.Artists)
+	}
+}
+
+func TestApplyEnsembleCredit(t *testing.T) {
+	track := &domain.Track{Artists: []domain.Artist{
+		{Name: "Claudio Abbado", Role: domain.RoleConductor},
+		{Name: "Berliner Philharmoniker", Role: domain.RoleUnknown},
+	}}
+	applyEnsembleCredit(track, "Berliner Philharmoniker")
+	applyEnsembleCredit(track, "Rundfunkchor Berlin") // Missing from ARTIST
+	applyEnsembleCredit(track, "claudio abbado")      // Credited already
+	applyEnsembleCredit(track, "")
+	want := []domain.Artist{
+		{Name: "Claudio Abbado", Role: domain.RoleConductor},
+		{Name: "Berliner Philharmoniker", Role: domain.RoleEnsemble},
+		{Name: "Rundfunkchor Berlin", Role: domain.RoleEnsemble},
+	}
+	if !slices.Equal(track.Artists, want) {
+		t.Errorf("artists = %+v, want %+v", track.Artists, want)
+	}
+}
@@ -0,0 +1,127 @@
+package scraping
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/tagging"
+)
+
+// AlbumGroup is the share of one album in a directory holding several.
+type AlbumGroup struct {
+	Album         string   // ALBUM tag, without a disc designation
+	CatalogNumber string   // CATALOGNUMBER tag of its first file, if any
+	Files         []string // Audio files, relative to the directory, sorted
+}
+
+// String names the group, e.g. "Goldberg Variations [479 1234]".
+func (g AlbumGroup) String() string {
+	if g.CatalogNumber == "" {
+		return fmt.Sprintf("%q", g.Album)
+	}
+	return fmt.Sprintf("%q [%s]", g.Album, g.CatalogNumber)
+}
+
+// discSuffixPattern matches a disc designation ending an ALBUM tag, e.g.
+// " (CD 2)", " - Disc 1" or " [Disk 3]", as multi-disc rips often add.
+var discSuffixPattern = regexp.MustCompile(`(?i)[\s\-–:,]*[(\[]?\s*(?:CD|Disc|Disk)\s*\d+\s*[)\]]?\s*$`)
+
+// mergedFile is what DetectMergedAlbums reads of one audio file.
+type mergedFile struct {
+	path          string // Relative to the directory
+	album         string
+	catalogNumber string
+	position      [2]int // Disc and track number
+}
+
+// DetectMergedAlbums reports whether the audio files in dirPath belong to
+// more than one album, returning each album's files in order of their first
+// file, or nil for one album. Files are told apart by ALBUM tag, ignoring
+// case and disc designations ("CD 2"), or when those agree by CATALOGNUMBER;
+// every file must carry the tag. Groups only count as separate albums when
+// two of them share a disc and track number, so a box set whose discs carry
+// their own titles or catalog numbers stays whole.
+func DetectMergedAlbums(dirPath string) ([]AlbumGroup, error) {
+	paths, err := findFLACFiles(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("error finding FLAC files: %w", err)
+	}
+
+	files := make([]mergedFile, 0, len(paths))
+	for _, path := range paths {
+		metadata, err := tagging.ReadTags(path)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(dirPath, path)
+		if err != nil {
+			return nil, err
+		}
+		f := mergedFile{
+			path:          rel,
+			album:         strings.TrimSpace(discSuffixPattern.ReplaceAllString(metadata.Album(), "")),
+			catalogNumber: strings.TrimSpace(readVorbisCommentTags(path)["CATALOGNUMBER"]),
+			position:      [2]int{1, extractTrackNumberFromFilename(path)},
+		}
+		if track, _ := metadata.Track(); track > 0 {
+			f.position[1] = track
+		}
+		if disc, _ := metadata.Disc(); disc > 0 {
+			f.position[0] = disc
+		} else {
+			f.position[0] = extractDiscFromPath(rel)
+		}
+		files = append(files, f)
+	}
+
+	if groups := groupMergedFiles(files, func(f mergedFile) string { return strings.ToLower(f.album) }); groups != nil {
+		return groups, nil
+	}
+	return groupMergedFiles(files, func(f mergedFile) string { return strings.ToUpper(f.catalogNumber) }), nil
+}
+
+// groupMergedFiles groups files by key, returning nil unless there are several
+// groups, no file's key is empty and two groups share a disc and track number.
+func groupMergedFiles(files []mergedFile, key func(mergedFile) string) []AlbumGroup {
+	var groups []AlbumGroup
+	index := make(map[string]int)
+	owner := make(map[[2]int]string)
+	overlap := false
+	for _, f := range files {
+		k := key(f)
+		if k == "" {
+			return nil
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, AlbumGroup{Album: f.album, CatalogNumber: f.catalogNumber})
+		}
+		groups[i].Files = append(groups[i].Files, f.path)
+
+		if f.position[1] > 0 {
+			if other, ok := owner[f.position]; ok && other != k {
+				overlap = true
+			}
+			owner[f.position] = k
+		}
+	}
+	if len(groups) < 2 || !overlap {
+		return nil
+	}
+	return groups
+}
+
+// ExtractGroup extracts the metadata of one album of a directory holding
+// several from the group's files alone, e.g. to name its own directory.
+func ExtractGroup(dirPath string, group AlbumGroup, opts ExtractOptions) (*domain.Album, error) {
+	files := make([]string, len(group.Files))
+	for i, file := range group.Files {
+		files[i] = filepath.Join(dirPath, file)
+	}
+	return extractFromFiles(files, dirPath, opts)
+}
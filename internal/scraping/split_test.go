@@ -0,0 +1,72 @@
+package scraping
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetectMergedAlbums(t *testing.T) {
+	type file struct {
+		Path     string
+		Comments []string
+	}
+	tests := []struct {
+		Name  string
+		Files []file
+		Want  []AlbumGroup
+	}{
+		{
+			Name: "two albums",
+			Files: []file{
+				{"01 Aria.flac", []string{"ALBUM=Goldberg Variations", "TRACKNUMBER=1"}},
+				{"01 Prelude.flac", []string{"ALBUM=Cello Suites", "TRACKNUMBER=1"}},
+				{"02 Variatio 1.flac", []string{"ALBUM=Goldberg Variations", "TRACKNUMBER=2"}},
+			},
+			Want: []AlbumGroup{
+				{Album: "Goldberg Variations", Files: []string{"01 Aria.flac", "02 Variatio 1.flac"}},
+				{Album: "Cello Suites", Files: []string{"01 Prelude.flac"}},
+			},
+		},
+		{
+			Name: "disc designations",
+			Files: []file{
+				{"CD1/01.flac", []string{"ALBUM=Cantatas (CD 1)", "TRACKNUMBER=1"}},
+				{"CD2/01.flac", []string{"ALBUM=Cantatas (CD 2)", "TRACKNUMBER=1"}},
+			},
+		},
+		{
+			Name: "box set with a title per disc",
+			Files: []file{
+				{"01.flac", []string{"ALBUM=Symphonies 1 & 2", "DISCNUMBER=1", "TRACKNUMBER=1"}},
+				{"02.flac", []string{"ALBUM=Symphonies 3 & 4", "DISCNUMBER=2", "TRACKNUMBER=1"}},
+			},
+		},
+		{
+			Name: "disjoint catalog numbers",
+			Files: []file{
+				{"a/01.flac", []string{"ALBUM=Piano Sonatas", "CATALOGNUMBER=479 1234", "TRACKNUMBER=1"}},
+				{"b/01.flac", []string{"ALBUM=Piano Sonatas", "CATALOGNUMBER=HMC 901", "TRACKNUMBER=1"}},
+			},
+			Want: []AlbumGroup{
+				{Album: "Piano Sonatas", CatalogNumber: "479 1234", Files: []string{filepath.Join("a", "01.flac")}},
+				{Album: "Piano Sonatas", CatalogNumber: "HMC 901", Files: []string{filepath.Join("b", "01.flac")}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range tt.Files {
+				writeTaggedFLAC(t, filepath.Join(dir, f.Path), f.Comments...)
+			}
+			got, err := DetectMergedAlbums(dir)
+			if err != nil {
+				t.Fatalf("DetectMergedAlbums() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.Want) {
+				t.Errorf("DetectMergedAlbums() = %+v, want %+v", got, tt.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package scraping
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+var (
+	// "1. Title", "1) Title", "01 - Title", "01 Title", "Track 1: Title", "2-05 Title" (disc-track)
+	tracklistLinePattern = regexp.MustCompile(`^(?i:track\s+)?(?:(\d+)[-.](\d+)|(\d+))\s*(?:[.):]|\s-|–)?\s+(.+)$`)
+	// Trailing durations: "4:32", "(4:32)", "[1:02:03]", optionally tab-separated
+	trackDurationPattern = regexp.MustCompile(`\s*[(\[]?\b\d{1,2}:\d{2}(?::\d{2})?[)\]]?\s*$`)
+)
+
+// nameParticles are lowercase words allowed inside personal names.
+var nameParticles = map[string]bool{
+	"van": true, "von": true, "de": true, "di": true, "da": true, "du": true,
+	"del": true, "della": true, "der": true, "le": true, "la": true, "y": true,
+}
+
+// workWords indicate that a "prefix: title" prefix is a work title rather than a composer.
+var workWords = []string{
+	"variations", "symphony", "sonata", "concerto", "suite", "mass", "messe", "requiem",
+	"quartet", "quintet", "trio", "partita", "prelude", "fugue", "cantata", "oratorio",
+	"opera", "act", "overture", "étude", "etude", "nocturne", "magnificat", "vespers",
+}
+
+// ParseTracklist parses a plain-text tracklist, such as one typed from a booklet.
+// Each track line starts with a track number ("1.", "01 -", "2-05"); disc headers
+// ("CD 2", "Disc 2") and track numbers restarting at 1 start a new disc.
+// Trailing durations are dropped. A "Composer: Title" prefix is read as the composer
+// when it looks like a personal name; single-word prefixes must match knownComposers
+// (full or last names) to avoid mistaking work titles for composers.
+func ParseTracklist(r io.Reader, knownComposers []string) (*ExtractionResult, error) {
+	result := &ExtractionResult{
+		Torrent:    &domain.Torrent{},
+		Source:     "tracklist",
+		Confidence: 0.5,
+	}
+
+	known := make(map[string]bool)
+	for _, name := range knownComposers {
+		known[strings.ToLower(name)] = true
+		if fields := strings.Fields(name); len(fields) > 0 {
+			known[strings.ToLower(fields[len(fields)-1])] = true
+		}
+	}
+
+	disc := 1
+	lastTrack := 0
+	lineNum := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if n, ok := ExtractDiscNumber(strings.TrimSuffix(line, ":")); ok {
+			disc = n
+			lastTrack = 0
+			continue
+		}
+
+		matches := tracklistLinePattern.FindStringSubmatch(line)
+		if matches == nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("line %d not recognized as a track: %q", lineNum, line))
+			continue
+		}
+
+		var trackNum int
+		if matches[1] != "" {
+			disc, _ = strconv.Atoi(matches[1])
+			trackNum, _ = strconv.Atoi(matches[2])
+		} else {
+			trackNum, _ = strconv.Atoi(matches[3])
+			// Track numbering restarting at 1 without a header starts a new disc
+			if trackNum == 1 && lastTrack > 1 {
+				disc++
+			}
+		}
+		lastTrack = trackNum
+
+		title := strings.TrimSpace(trackDurationPattern.ReplaceAllString(matches[4], ""))
+		track := &domain.Track{Disc: disc, Track: trackNum, Title: title}
+		if composer, rest, ok := splitComposerPrefix(title, known); ok {
+			track.Title = rest
+			track.Artists = []domain.Artist{{Name: composer, Role: domain.RoleComposer}}
+		}
+		if track.Title == "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("line %d has no title: %q", lineNum, line))
+			continue
+		}
+		result.Torrent.Files = append(result.Torrent.Files, track)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tracklist: %w", err)
+	}
+	if len(result.Torrent.Files) == 0 {
+		return nil, fmt.Errorf("%w: no tracks found in tracklist", ErrExtractionFailed)
+	}
+
+	return result, nil
+}
+
+// splitComposerPrefix splits "Composer: Title" when the prefix looks like a composer name.
+func splitComposerPrefix(title string, known map[string]bool) (string, string, bool) {
+	prefix, rest, ok := strings.Cut(title, ":")
+	if !ok {
+		return "", "", false
+	}
+	prefix = strings.TrimSpace(prefix)
+	rest = strings.TrimSpace(rest)
+	if prefix == "" || rest == "" {
+		return "", "", false
+	}
+
+	if known[strings.ToLower(prefix)] {
+		return prefix, rest, true
+	}
+
+	words := strings.Fields(prefix)
+	if len(words) < 2 || len(words) > 5 {
+		return "", "", false
+	}
+	lower := strings.ToLower(prefix)
+	for _, w := range workWords {
+		if strings.Contains(lower, w) {
+			return "", "", false
+		}
+	}
+	for _, w := range words {
+		if nameParticles[w] {
+			continue
+		}
+		first := []rune(w)[0]
+		if !unicode.IsUpper(first) || strings.ContainsFunc(w, unicode.IsDigit) {
+			return "", "", false
+		}
+	}
+	return prefix, rest, true
+}
+
+// ApplyTracklist copies titles (and composers, where the tracklist names one) from a parsed
+// tracklist onto the matching disc/track of target. Returns warnings for tracks that
+// appear in only one of them.
+func ApplyTracklist(target, tracklist *domain.Torrent) []string {
+	type key struct{ disc, track int }
+	byPosition := make(map[key]*domain.Track)
+	for _, t := range tracklist.Tracks() {
+		byPosition[key{t.Disc, t.Track}] = t
+	}
+
+	var warnings []string
+	for _, t := range target.Tracks() {
+		disc := max(t.Disc, 1)
+		source, ok := byPosition[key{disc, t.Track}]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("disc %d track %d not in tracklist", disc, t.Track))
+			continue
+		}
+		delete(byPosition, key{disc, t.Track})
+
+		t.Title = source.Title
+		for _, composer := range source.Artists {
+			replaced := false
+			for i := range t.Artists {
+				if t.Artists[i].Role == domain.RoleComposer {
+					t.Artists[i] = composer
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				t.Artists = append([]domain.Artist{composer}, t.Artists...)
+			}
+		}
+	}
+	for _, t := range tracklist.Tracks() {
+		if _, ok := byPosition[key{t.Disc, t.Track}]; ok {
+			warnings = append(warnings, fmt.Sprintf("tracklist disc %d track %d (%s) has no matching file", t.Disc, t.Track, t.Title))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,107 @@
+package scraping
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestParseTracklist(t *testing.T) {
+	input := `Goldberg Variations
+
+CD 1
+1. Johann Sebastian Bach: Aria (3:02)
+2. Variatio 1 a 1 Clav.	1:51
+03 - Goldberg Variations: Variatio 2 [1:40]
+Track 4: Bach: Variatio 3. Canone all'Unisono
+CD 2
+1) Messiah: Overture
+2-02 Quodlibet 1:02:03
+`
+	result, err := ParseTracklist(strings.NewReader(input), []string{"Johann Sebastian Bach"})
+	if err != nil {
+		t.Fatalf("ParseTracklist() error = %v", err)
+	}
+
+	want := []struct {
+		Disc, Track int
+		Title       string
+		Composer    string
+	}{
+		{1, 1, "Aria", "Johann Sebastian Bach"},
+		{1, 2, "Variatio 1 a 1 Clav.", ""},
+		{1, 3, "Goldberg Variations: Variatio 2", ""},
+		{1, 4, "Variatio 3. Canone all'Unisono", "Bach"},
+		{2, 1, "Messiah: Overture", ""},
+		{2, 2, "Quodlibet", ""},
+	}
+	tracks := result.Torrent.Tracks()
+	if len(tracks) != len(want) {
+		t.Fatalf("got %d tracks, want %d", len(tracks), len(want))
+	}
+	for i, w := range want {
+		got := tracks[i]
+		if got.Disc != w.Disc || got.Track != w.Track || got.Title != w.Title {
+			t.Errorf("track %d = %d-%d %q, want %d-%d %q", i, got.Disc, got.Track, got.Title, w.Disc, w.Track, w.Title)
+		}
+		composer := ""
+		if len(got.Artists) > 0 {
+			composer = got.Artists[0].Name
+		}
+		if composer != w.Composer {
+			t.Errorf("track %d composer = %q, want %q", i, composer, w.Composer)
+		}
+	}
+
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "Goldberg Variations") {
+		t.Errorf("Warnings = %v, want one warning for the heading line", result.Warnings)
+	}
+}
+
+func TestParseTracklist_RestartedNumberingStartsNewDisc(t *testing.T) {
+	input := "1. First\n2. Second\n1. Third\n"
+	result, err := ParseTracklist(strings.NewReader(input), nil)
+	if err != nil {
+		t.Fatalf("ParseTracklist() error = %v", err)
+	}
+	tracks := result.Torrent.Tracks()
+	if len(tracks) != 3 || tracks[2].Disc != 2 || tracks[2].Track != 1 {
+		t.Errorf("third track = %+v, want disc 2 track 1", tracks[2])
+	}
+}
+
+func TestParseTracklist_Empty(t *testing.T) {
+	_, err := ParseTracklist(strings.NewReader("no tracks here\n"), nil)
+	if !errors.Is(err, ErrExtractionFailed) {
+		t.Errorf("error = %v, want ErrExtractionFailed", err)
+	}
+}
+
+func TestApplyTracklist(t *testing.T) {
+	target := &domain.Torrent{Files: []domain.FileLike{
+		&domain.Track{File: domain.File{Path: "01.flac"}, Disc: 1, Track: 1, Title: "Track 01",
+			Artists: []domain.Artist{{Name: "Unknown", Role: domain.RoleComposer}, {Name: "Glenn Gould", Role: domain.RoleSoloist}}},
+		&domain.Track{File: domain.File{Path: "02.flac"}, Disc: 1, Track: 2, Title: "Track 02"},
+		&domain.Track{File: domain.File{Path: "03.flac"}, Disc: 1, Track: 3, Title: "Track 03"},
+	}}
+	tracklist := &domain.Torrent{Files: []domain.FileLike{
+		&domain.Track{Disc: 1, Track: 1, Title: "Aria", Artists: []domain.Artist{{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}}},
+		&domain.Track{Disc: 1, Track: 2, Title: "Variatio 1"},
+		&domain.Track{Disc: 1, Track: 4, Title: "Variatio 3"},
+	}}
+
+	warnings := ApplyTracklist(target, tracklist)
+
+	tracks := target.Tracks()
+	if tracks[0].Title != "Aria" || tracks[1].Title != "Variatio 1" || tracks[2].Title != "Track 03" {
+		t.Errorf("titles = %q, %q, %q", tracks[0].Title, tracks[1].Title, tracks[2].Title)
+	}
+	if len(tracks[0].Artists) != 2 || tracks[0].Artists[0].Name != "Johann Sebastian Bach" {
+		t.Errorf("track 1 artists = %v, want composer replaced and soloist kept", tracks[0].Artists)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("warnings = %v, want one for track 3 and one for tracklist track 4", warnings)
+	}
+}
@@ -0,0 +1,64 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// artistRolesFile holds the roles assigned to artists by hand, by name.
+const artistRolesFile = "artist_roles.json"
+
+// LoadArtistRoles returns the roles assigned to artists by hand in earlier runs,
+// keyed by the artist's name as it was credited. No store yet is not an error.
+func LoadArtistRoles() (map[string]domain.Role, error) {
+	roles := make(map[string]domain.Role)
+	data, err := os.ReadFile(filepath.Join(Dir(), artistRolesFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return roles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artist roles: %w", err)
+	}
+	if err := json.Unmarshal(data, &roles); err != nil {
+		return nil, fmt.Errorf("failed to parse artist roles: %w", err)
+	}
+	return roles, nil
+}
+
+// RememberArtistRole adds an artist's role to the store, replacing any role
+// remembered before, so later albums by the same artist need not ask again.
+func RememberArtistRole(name string, role domain.Role) error {
+	lock, err := Acquire(artistRolesFile)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	roles, err := LoadArtistRoles()
+	if err != nil {
+		return err
+	}
+	roles[name] = role
+	data, err := json.MarshalIndent(roles, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write a temporary file and rename it so readers never see a partial store
+	path := filepath.Join(Dir(), artistRolesFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write artist roles: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write artist roles: %w", err)
+	}
+	return nil
+}
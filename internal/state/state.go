@@ -0,0 +1,219 @@
+// Package state manages the persistent state directory and per-album lockfiles
+// that keep concurrent invocations from clobbering each other's output.
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Dir returns the state directory, respecting XDG_STATE_HOME.
+func Dir() string {
+	if xdgState := os.Getenv("XDG_STATE_HOME"); xdgState != "" {
+		return filepath.Join(xdgState, "classical-tagger")
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		homeDir = os.Getenv("HOME")
+	}
+	return filepath.Join(homeDir, ".local", "state", "classical-tagger")
+}
+
+// unreadableLockGrace is how long an unparseable lockfile is assumed to be mid-write.
+const unreadableLockGrace = 10 * time.Second
+
+// LockInfo records which process holds a lock.
+type LockInfo struct {
+	Target  string    `json:"target"`
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Command string    `json:"command"`
+	Started time.Time `json:"started"`
+}
+
+// String describes the lock holder.
+func (i LockInfo) String() string {
+	return fmt.Sprintf("pid %d on %s (%s) since %s", i.PID, i.Host, i.Command, i.Started.Format(time.RFC3339))
+}
+
+// LockedError is returned when a target is locked by another live process.
+type LockedError struct {
+	Holder LockInfo
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s is locked by %s", e.Holder.Target, e.Holder)
+}
+
+// Lock is an acquired lockfile.
+type Lock struct {
+	Path string
+	Info LockInfo
+
+	released bool
+}
+
+// AcquireDir takes the lock for an album directory.
+// The directory is resolved to an absolute path so different spellings share a lock.
+func AcquireDir(dir string) (*Lock, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	return Acquire(abs)
+}
+
+// Acquire takes the lock for target, an arbitrary key such as "torrent:123".
+// A lock left behind by a process that no longer exists on this host is treated as stale and replaced.
+func Acquire(target string) (*Lock, error) {
+	lockDir := filepath.Join(Dir(), "locks")
+	if err := os.MkdirAll(lockDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(target))
+	path := filepath.Join(lockDir, hex.EncodeToString(sum[:8])+".lock")
+
+	host, _ := os.Hostname()
+	info := LockInfo{
+		Target:  target,
+		PID:     os.Getpid(),
+		Host:    host,
+		Command: strings.Join(os.Args, " "),
+		Started: time.Now(),
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retry once after removing a stale lock
+	for range 2 {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, werr := f.Write(data)
+			cerr := f.Close()
+			if werr != nil || cerr != nil {
+				os.Remove(path)
+				return nil, fmt.Errorf("failed to write lock file: %w", errors.Join(werr, cerr))
+			}
+			return &Lock{Path: path, Info: info}, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		seen, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read lock file: %w", err)
+		}
+		var holder LockInfo
+		if err := json.Unmarshal(seen, &holder); err != nil {
+			// An unreadable lock may still be being written; only reclaim it once it is old
+			if fi, statErr := os.Stat(path); statErr == nil && time.Since(fi.ModTime()) < unreadableLockGrace {
+				return nil, &LockedError{Holder: LockInfo{Target: target, Started: fi.ModTime()}}
+			}
+		} else if !isStale(holder, host) {
+			return nil, &LockedError{Holder: holder}
+		}
+		if err := reclaim(path, seen); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("failed to acquire lock for %s", target)
+}
+
+// reclaim removes the stale lockfile at path, whose contents were seen. Another
+// process may have reclaimed it and taken the lock between the read and now, so
+// the file is renamed aside (atomically: only one process gets it) and checked
+// to be the one judged stale; a live lock taken meanwhile is put back and
+// reported as held.
+func reclaim(path string, seen []byte) error {
+	aside := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, aside); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil // Reclaimed by another process; retry
+		}
+		return fmt.Errorf("failed to remove stale lock: %w", err)
+	}
+	defer os.Remove(aside)
+
+	got, err := os.ReadFile(aside)
+	if err != nil {
+		return fmt.Errorf("failed to remove stale lock: %w", err)
+	}
+	if string(got) == string(seen) {
+		return nil
+	}
+	// Link rather than rename back, so a lock taken since is not overwritten
+	if err := os.Link(aside, path); err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("failed to restore lock: %w", err)
+	}
+	var holder LockInfo
+	json.Unmarshal(got, &holder)
+	return &LockedError{Holder: holder}
+}
+
+// Release removes the lockfile, unless it no longer records this lock (it was
+// reclaimed as stale and another process holds it now). It is safe to call on a
+// nil Lock and more than once.
+func (l *Lock) Release() error {
+	if l == nil || l.released {
+		return nil
+	}
+	l.released = true
+	holder, err := readLockInfo(l.Path)
+	if err != nil || !holder.Started.Equal(l.Info.Started) || holder.PID != l.Info.PID || holder.Host != l.Info.Host {
+		return nil
+	}
+	if err := os.Remove(l.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
+
+func readLockInfo(path string) (LockInfo, error) {
+	var info LockInfo
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
+
+// isStale reports whether the lock holder has exited.
+// Locks held on other hosts (shared storage) cannot be checked and are never stale.
+func isStale(holder LockInfo, host string) bool {
+	if holder.Host != host {
+		return false
+	}
+	return !processAlive(holder.PID)
+}
+
+// processAlive reports whether a process with the given PID exists.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = process.Signal(syscall.Signal(0))
+	// EPERM means the process exists but belongs to another user
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,291 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestDir(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/tmp/state-home")
+	if got := Dir(); got != filepath.Join("/tmp/state-home", "classical-tagger") {
+		t.Errorf("Dir() = %q", got)
+	}
+
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", "/home/user")
+	if got := Dir(); got != filepath.Join("/home/user", ".local", "state", "classical-tagger") {
+		t.Errorf("Dir() = %q", got)
+	}
+}
+
+func TestAcquire_Exclusive(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	lock, err := AcquireDir("album")
+	if err != nil {
+		t.Fatalf("AcquireDir() error = %v", err)
+	}
+
+	// Same directory spelled differently shares the lock
+	abs, _ := filepath.Abs("album")
+	_, err = AcquireDir(abs)
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("expected LockedError, got %v", err)
+	}
+	if locked.Holder.PID != os.Getpid() {
+		t.Errorf("Holder.PID = %d, want %d", locked.Holder.PID, os.Getpid())
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	relock, err := AcquireDir("album")
+	if err != nil {
+		t.Fatalf("AcquireDir() after release error = %v", err)
+	}
+	relock.Release()
+}
+
+func TestAcquire_IndependentTargets(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	a, err := Acquire("torrent:1")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer a.Release()
+	b, err := Acquire("torrent:2")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer b.Release()
+}
+
+func TestAcquire_ReplacesStaleLock(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	lock, err := Acquire("torrent:3")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// Simulate a crashed holder on this host
+	host, _ := os.Hostname()
+	stale := LockInfo{Target: "torrent:3", PID: 1 << 30, Host: host, Command: "extract", Started: time.Now()}
+	data, _ := json.Marshal(stale)
+	if err := os.WriteFile(lock.Path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	relock, err := Acquire("torrent:3")
+	if err != nil {
+		t.Fatalf("Acquire() over stale lock error = %v", err)
+	}
+	if relock.Info.PID != os.Getpid() {
+		t.Errorf("Info.PID = %d, want %d", relock.Info.PID, os.Getpid())
+	}
+	relock.Release()
+}
+
+func TestAcquire_ConcurrentReclaimHasOneWinner(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	lock, err := Acquire("torrent:5")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	host, _ := os.Hostname()
+	stale := LockInfo{Target: "torrent:5", PID: 1 << 30, Host: host, Command: "extract", Started: time.Now()}
+	data, _ := json.Marshal(stale)
+	if err := os.WriteFile(lock.Path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 8
+	locks := make(chan *Lock, n)
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if l, err := Acquire("torrent:5"); err == nil {
+				locks <- l
+			}
+		}()
+	}
+	wg.Wait()
+	close(locks)
+	if len(locks) != 1 {
+		t.Errorf("%d processes reclaimed the stale lock, want 1", len(locks))
+	}
+	for l := range locks {
+		l.Release()
+	}
+}
+
+func TestReclaim_RestoresLockTakenMeanwhile(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	lock, err := Acquire("torrent:6")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer lock.Release()
+	live, _ := os.ReadFile(lock.Path)
+
+	// The lock judged stale was replaced by this live one before the reclaim
+	var locked *LockedError
+	if err := reclaim(lock.Path, []byte(`{"pid":1}`)); !errors.As(err, &locked) || locked.Holder.PID != os.Getpid() {
+		t.Errorf("reclaim() error = %v, want LockedError held by pid %d", err, os.Getpid())
+	}
+	if got, err := os.ReadFile(lock.Path); err != nil || string(got) != string(live) {
+		t.Errorf("lock file = %q, %v; want the live lock restored", got, err)
+	}
+	if matches, _ := filepath.Glob(lock.Path + ".stale-*"); len(matches) != 0 {
+		t.Errorf("left %v behind", matches)
+	}
+}
+
+func TestAcquire_RemoteHolderIsNotStale(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	lock, err := Acquire("torrent:4")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer lock.Release()
+
+	remote := LockInfo{Target: "torrent:4", PID: 1 << 30, Host: "other-host", Command: "upload", Started: time.Now()}
+	data, _ := json.Marshal(remote)
+	os.WriteFile(lock.Path, data, 0644)
+
+	var locked *LockedError
+	if _, err := Acquire("torrent:4"); !errors.As(err, &locked) || locked.Holder.Host != "other-host" {
+		t.Errorf("expected LockedError held by other-host, got %v", err)
+	}
+}
+
+func TestLock_ReleaseOnlyOwnLock(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	lock, err := Acquire("album")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+
+	// Another holder takes the lock; releasing the first lock again leaves it alone
+	other, err := Acquire("album")
+	if err != nil {
+		t.Fatalf("Acquire() after release error = %v", err)
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatalf("second Release() error = %v", err)
+	}
+	if _, err := os.Stat(other.Path); err != nil {
+		t.Fatalf("second Release() removed another holder's lock: %v", err)
+	}
+
+	// A lock reclaimed by another process is not removed by its former holder
+	stale := *other
+	info := other.Info
+	info.PID = other.Info.PID + 1
+	data, _ := json.Marshal(info)
+	if err := os.WriteFile(other.Path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := stale.Release(); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if _, err := os.Stat(other.Path); err != nil {
+		t.Errorf("Release() removed a lock recording another PID: %v", err)
+	}
+}
+
+func TestLock_ReleaseNil(t *testing.T) {
+	var lock *Lock
+	if err := lock.Release(); err != nil {
+		t.Errorf("Release() on nil lock error = %v", err)
+	}
+}
+
+func TestArtistRoles(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	roles, err := LoadArtistRoles()
+	if err != nil || len(roles) != 0 {
+		t.Fatalf("LoadArtistRoles() = %v, %v; want an empty store", roles, err)
+	}
+
+	if err := RememberArtistRole("Hans-Christoph Rademann", domain.RoleEnsemble); err != nil {
+		t.Fatal(err)
+	}
+	if err := RememberArtistRole("Andreas Scholl", domain.RoleSoloist); err != nil {
+		t.Fatal(err)
+	}
+	// A later assignment replaces the earlier one
+	if err := RememberArtistRole("Hans-Christoph Rademann", domain.RoleConductor); err != nil {
+		t.Fatal(err)
+	}
+
+	roles, err = LoadArtistRoles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]domain.Role{"Hans-Christoph Rademann": domain.RoleConductor, "Andreas Scholl": domain.RoleSoloist}
+	if !maps.Equal(roles, want) {
+		t.Errorf("LoadArtistRoles() = %v, want %v", roles, want)
+	}
+}
+
+func TestUploads(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	uploads, err := LoadUploads()
+	if err != nil || len(uploads) != 0 {
+		t.Fatalf("LoadUploads() = %v, %v; want an empty ledger", uploads, err)
+	}
+
+	when := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	prepared := Upload{Dir: "/music/A", GroupID: 10, TorrentID: 100, Edition: "2010 / CD / Lossless", When: when}
+	other := Upload{Dir: "/music/B", GroupID: 20, TorrentID: 200, Edition: "CD / Lossless", Uploaded: true, When: when}
+	uploaded := prepared
+	uploaded.Uploaded = true
+	uploaded.When = when.Add(time.Hour)
+	for _, u := range []Upload{prepared, other, uploaded} {
+		if err := RecordUpload(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A later dry run of the same directory does not replace the upload
+	rerun := prepared
+	rerun.When = when.Add(2 * time.Hour)
+	if err := RecordUpload(rerun); err != nil {
+		t.Fatal(err)
+	}
+
+	uploads, err = LoadUploads()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Upload{other, uploaded}
+	if len(uploads) != len(want) {
+		t.Fatalf("LoadUploads() = %+v, want %+v", uploads, want)
+	}
+	for i := range want {
+		if !uploads[i].When.Equal(want[i].When) || uploads[i].Dir != want[i].Dir || uploads[i].Status() != want[i].Status() {
+			t.Errorf("LoadUploads()[%d] = %+v, want %+v", i, uploads[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// uploadQueueFile holds the uploads prepared under an upload schedule and
+// waiting for their submission slot.
+const uploadQueueFile = "upload_queue.json"
+
+// QueuedUpload is a prepared upload waiting to be submitted: the .torrent is
+// built and the upload form filled, so submitting needs no more than the API.
+type QueuedUpload struct {
+	Dir         string `json:"dir"`
+	TorrentPath string `json:"torrent_path"`
+	GroupID     int    `json:"group_id"`
+	// Form is the upload form, as the uploader encodes it
+	Form     json.RawMessage `json:"form"`
+	Collages []int           `json:"collages,omitempty"`
+	// Ledger is the ledger entry recorded once the upload is submitted (nil:
+	// not recorded)
+	Ledger *Upload   `json:"ledger,omitempty"`
+	Queued time.Time `json:"queued"`
+}
+
+// UploadQueue is the persisted upload queue.
+type UploadQueue struct {
+	Pending []QueuedUpload `json:"pending"` // Oldest first
+	// LastSubmitted is when the last upload under the schedule was submitted,
+	// queued or not, for spacing the next one
+	LastSubmitted time.Time `json:"last_submitted"`
+}
+
+// UploadQueuePath returns the path of the upload queue file.
+func UploadQueuePath() string {
+	return filepath.Join(Dir(), uploadQueueFile)
+}
+
+// LoadUploadQueue returns the upload queue. No queue yet is not an error.
+func LoadUploadQueue() (*UploadQueue, error) {
+	queue := &UploadQueue{}
+	data, err := os.ReadFile(UploadQueuePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return queue, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload queue: %w", err)
+	}
+	if err := json.Unmarshal(data, queue); err != nil {
+		return nil, fmt.Errorf("failed to parse upload queue: %w", err)
+	}
+	return queue, nil
+}
+
+// EnqueueUpload adds a prepared upload to the end of the queue, replacing one
+// queued earlier from the same directory.
+func EnqueueUpload(upload QueuedUpload) error {
+	return updateUploadQueue(func(queue *UploadQueue) {
+		queue.Pending = removeQueued(queue.Pending, upload.Dir)
+		queue.Pending = append(queue.Pending, upload)
+	})
+}
+
+// DequeueUpload removes the upload queued from dir, recording it as submitted
+// at when unless when is zero. Submissions not from the queue are recorded with
+// an empty dir.
+func DequeueUpload(dir string, when time.Time) error {
+	return updateUploadQueue(func(queue *UploadQueue) {
+		if dir != "" {
+			queue.Pending = removeQueued(queue.Pending, dir)
+		}
+		if !when.IsZero() {
+			queue.LastSubmitted = when
+		}
+	})
+}
+
+// removeQueued returns pending without the upload queued from dir.
+func removeQueued(pending []QueuedUpload, dir string) []QueuedUpload {
+	kept := pending[:0]
+	for _, u := range pending {
+		if u.Dir != dir {
+			kept = append(kept, u)
+		}
+	}
+	return kept
+}
+
+// updateUploadQueue applies update to the queue under its lock.
+func updateUploadQueue(update func(*UploadQueue)) error {
+	lock, err := Acquire(uploadQueueFile)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	queue, err := LoadUploadQueue()
+	if err != nil {
+		return err
+	}
+	update(queue)
+	data, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write a temporary file and rename it so readers never see a partial queue
+	path := UploadQueuePath()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write upload queue: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write upload queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// uploadsFile is the ledger of albums prepared or uploaded with the upload command.
+const uploadsFile = "uploads.json"
+
+// Upload is a ledger entry: an album directory uploaded to a group, or
+// prepared for it by a dry run.
+type Upload struct {
+	Dir     string `json:"dir"`
+	Title   string `json:"title"`
+	GroupID int    `json:"group_id"`
+	// TorrentID is the torrent trumped, or the group's torrent named to add a
+	// new edition next to when NewEdition is set
+	TorrentID  int  `json:"torrent_id"`
+	NewEdition bool `json:"new_edition,omitempty"`
+	// Edition describes the edition and format uploaded, e.g.
+	// "2010 / Harmonia Mundi / HMC902170 / CD / Lossless"
+	Edition  string    `json:"edition"`
+	Uploaded bool      `json:"uploaded"` // false: prepared by a dry run
+	When     time.Time `json:"when"`
+}
+
+// Status is "uploaded" or "prepared".
+func (u Upload) Status() string {
+	if u.Uploaded {
+		return "uploaded"
+	}
+	return "prepared"
+}
+
+// sameTarget reports whether u and other upload the same directory for the
+// same torrent.
+func (u Upload) sameTarget(other Upload) bool {
+	return u.Dir == other.Dir && u.GroupID == other.GroupID &&
+		u.TorrentID == other.TorrentID && u.NewEdition == other.NewEdition
+}
+
+// LoadUploads returns the ledger entries, oldest first. No ledger yet is not
+// an error.
+func LoadUploads() ([]Upload, error) {
+	var uploads []Upload
+	data, err := os.ReadFile(filepath.Join(Dir(), uploadsFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, &uploads); err != nil {
+		return nil, fmt.Errorf("failed to parse upload ledger: %w", err)
+	}
+	return uploads, nil
+}
+
+// RecordUpload adds an entry to the ledger, replacing the entry for the same
+// directory and torrent. A dry run does not replace a recorded upload.
+func RecordUpload(upload Upload) error {
+	lock, err := Acquire(uploadsFile)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	uploads, err := LoadUploads()
+	if err != nil {
+		return err
+	}
+	kept := uploads[:0]
+	for _, u := range uploads {
+		if u.sameTarget(upload) {
+			if u.Uploaded && !upload.Uploaded {
+				return nil
+			}
+			continue
+		}
+		kept = append(kept, u)
+	}
+	data, err := json.MarshalIndent(append(kept, upload), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write a temporary file and rename it so readers never see a partial ledger
+	path := filepath.Join(Dir(), uploadsFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write upload ledger: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write upload ledger: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+var (
+	// ErrNewerSchema is returned for metadata written by a newer version of the tools.
+	ErrNewerSchema = errors.New("metadata schema is newer than this program supports")
+	// ErrNotMetadata is returned when a JSON document is not album metadata.
+	ErrNotMetadata = errors.New("not an album metadata file")
+)
+
+// document is metadata JSON decoded generically, so migrations can reshape it
+// before it reaches the domain model.
+type document map[string]any
+
+// migrations[i] upgrades a document from schema version i to i+1.
+var migrations = []func(document) error{
+	migrateV0,
+}
+
+// Migrate upgrades metadata JSON to domain.SchemaVersion. It returns the upgraded
+// JSON and the version the input was written with (0 for files that predate
+// versioning). Input already at the current version is returned unchanged.
+func Migrate(data []byte) ([]byte, int, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	if doc == nil {
+		return data, 0, nil
+	}
+
+	version := 0
+	if v, ok := doc["schema_version"].(float64); ok {
+		version = int(v)
+	}
+	if version > domain.SchemaVersion {
+		return nil, version, fmt.Errorf("%w: version %d, supported %d", ErrNewerSchema, version, domain.SchemaVersion)
+	}
+	if version == domain.SchemaVersion {
+		return data, version, nil
+	}
+
+	for v := version; v < domain.SchemaVersion; v++ {
+		if err := migrations[v](doc); err != nil {
+			return nil, version, fmt.Errorf("failed to migrate schema version %d: %w", v, err)
+		}
+	}
+	doc["schema_version"] = domain.SchemaVersion
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, version, fmt.Errorf("failed to marshal migrated JSON: %w", err)
+	}
+	return migrated, version, nil
+}
+
+// migrateV0 upgrades unversioned files: the album folder was saved as
+// "folder_name" before the Torrent model, and roles were saved as numbers
+// before Role had a text form.
+func migrateV0(doc document) error {
+	if folder, ok := doc["folder_name"]; ok {
+		if _, ok := doc["root_path"]; !ok {
+			doc["root_path"] = folder
+		}
+		delete(doc, "folder_name")
+	}
+
+	if err := migrateRoles(doc["album_artist"]); err != nil {
+		return err
+	}
+	files, _ := doc["files"].([]any)
+	for _, f := range files {
+		if file, ok := f.(map[string]any); ok {
+			if err := migrateRoles(file["artists"]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// migrateRoles rewrites numeric roles in a list of artists as role names.
+func migrateRoles(artists any) error {
+	list, _ := artists.([]any)
+	for _, a := range list {
+		artist, ok := a.(map[string]any)
+		if !ok {
+			continue
+		}
+		n, ok := artist["role"].(float64)
+		if !ok {
+			continue
+		}
+		role := domain.Role(n)
+		if float64(role) != n || role < domain.RoleUnknown || role > domain.RoleMax {
+			return fmt.Errorf("%w: %s", domain.ErrInvalidRole, strconv.FormatFloat(n, 'g', -1, 64))
+		}
+		artist["role"] = role.String()
+	}
+	return nil
+}
+
+// MigrateFile upgrades a metadata file in place, reporting the version it had.
+// Files already at the current version are not rewritten; with dryRun nothing is written.
+func (r *Repository) MigrateFile(path string, dryRun bool) (int, error) {
+	data, err := fsys.Or(r.FS).ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Bulk migration walks directories, so leave unrelated JSON alone
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+	_, hasFiles := doc["files"]
+	_, hasRoot := doc["root_path"]
+	_, hasFolder := doc["folder_name"]
+	if !hasFiles && !hasRoot && !hasFolder {
+		return 0, ErrNotMetadata
+	}
+
+	migrated, from, err := Migrate(data)
+	if err != nil || from == domain.SchemaVersion || dryRun {
+		return from, err
+	}
+
+	// Round-trip through the model so upgraded files are formatted like freshly saved ones
+	torrent, err := r.LoadFromJSON(migrated)
+	if err != nil {
+		return from, err
+	}
+	return from, r.SaveToFile(torrent, path)
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// legacyJSON is an unversioned file from before the Torrent model and text roles.
+const legacyJSON = `{
+  "folder_name": "Bruckner - Motets (2013) - FLAC",
+  "title": "Motets",
+  "original_year": 2013,
+  "album_artist": [{"name": "RIAS Kammerchor", "role": 3}],
+  "files": [
+    {"path": "01 - Ave Maria.flac", "disc": 1, "track": 1, "title": "Ave Maria",
+     "artists": [{"name": "Anton Bruckner", "role": 1}, {"name": "RIAS Kammerchor", "role": 3}]},
+    {"path": "folder.jpg"}
+  ]
+}`
+
+func TestMigrate_Legacy(t *testing.T) {
+	data, from, err := Migrate([]byte(legacyJSON))
+	if err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+	if from != 0 {
+		t.Errorf("Migrate() from = %d, want 0", from)
+	}
+
+	torrent, err := NewRepository().LoadFromJSON(data)
+	if err != nil {
+		t.Fatalf("LoadFromJSON(migrated) error = %v", err)
+	}
+	if torrent.RootPath != "Bruckner - Motets (2013) - FLAC" {
+		t.Errorf("RootPath = %q", torrent.RootPath)
+	}
+	if len(torrent.AlbumArtist) != 1 || torrent.AlbumArtist[0].Role != domain.RoleEnsemble {
+		t.Errorf("AlbumArtist = %v", torrent.AlbumArtist)
+	}
+	tracks := torrent.Tracks()
+	if len(tracks) != 1 || len(torrent.Files) != 2 {
+		t.Fatalf("got %d tracks in %d files, want 1 in 2", len(tracks), len(torrent.Files))
+	}
+	if tracks[0].Artists[0].Role != domain.RoleComposer {
+		t.Errorf("track artists = %v", tracks[0].Artists)
+	}
+}
+
+func TestMigrate_Versions(t *testing.T) {
+	current, err := json.Marshal(&domain.Torrent{Title: "Motets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(current), `"schema_version":1`) {
+		t.Errorf("saved JSON has no schema version: %s", current)
+	}
+	data, from, err := Migrate(current)
+	if err != nil || from != domain.SchemaVersion || string(data) != string(current) {
+		t.Errorf("Migrate(current) = %s, %d, %v; want unchanged", data, from, err)
+	}
+
+	if _, _, err := Migrate([]byte(`{"schema_version": 99, "title": "x"}`)); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("Migrate(newer) error = %v, want ErrNewerSchema", err)
+	}
+	if _, _, err := Migrate([]byte(`{"album_artist": [{"name": "X", "role": 42}]}`)); !errors.Is(err, domain.ErrInvalidRole) {
+		t.Errorf("Migrate(bad role) error = %v, want ErrInvalidRole", err)
+	}
+	if _, err := NewRepository().LoadFromJSON([]byte(`{}`)); err != nil {
+		t.Errorf("LoadFromJSON({}) error = %v", err)
+	}
+}
+
+func TestRepository_MigrateFile(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "album.json")
+	other := filepath.Join(dir, "settings.json")
+	if err := os.WriteFile(legacy, []byte(legacyJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(other, []byte(`{"theme": "dark"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := NewRepository()
+	if from, err := repo.MigrateFile(legacy, true); err != nil || from != 0 {
+		t.Fatalf("MigrateFile(dry run) = %d, %v", from, err)
+	}
+	if data, _ := os.ReadFile(legacy); string(data) != legacyJSON {
+		t.Error("dry run rewrote the file")
+	}
+
+	if from, err := repo.MigrateFile(legacy, false); err != nil || from != 0 {
+		t.Fatalf("MigrateFile() = %d, %v", from, err)
+	}
+	if from, err := repo.MigrateFile(legacy, false); err != nil || from != domain.SchemaVersion {
+		t.Errorf("MigrateFile(again) = %d, %v; want current version", from, err)
+	}
+	data, _ := os.ReadFile(legacy)
+	if strings.Contains(string(data), "folder_name") || !strings.Contains(string(data), `"role": "composer"`) {
+		t.Errorf("migrated file:\n%s", data)
+	}
+
+	if _, err := repo.MigrateFile(other, false); !errors.Is(err, ErrNotMetadata) {
+		t.Errorf("MigrateFile(unrelated) error = %v, want ErrNotMetadata", err)
+	}
+}
@@ -3,14 +3,18 @@ package storage
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"slices"
+	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
 )
 
 // Repository handles JSON serialization and deserialization of torrents.
 // No DTOs needed - domain objects serialize directly with JSON tags.
-type Repository struct{}
+type Repository struct {
+	FS fsys.FS // Where files are read and written (nil: the operating system)
+}
 
 // NewRepository creates a new Repository.
 func NewRepository() *Repository {
@@ -27,9 +31,15 @@ func (r *Repository) SaveToJSON(torrent *domain.Torrent) ([]byte, error) {
 	return data, nil
 }
 
-// LoadFromJSON deserializes a torrent from JSON bytes.
+// LoadFromJSON deserializes a torrent from JSON bytes, migrating files written
+// with an older schema version first.
 // Domain objects have JSON tags, so no DTO conversion needed.
 func (r *Repository) LoadFromJSON(data []byte) (*domain.Torrent, error) {
+	data, _, err := Migrate(data)
+	if err != nil {
+		return nil, err
+	}
+
 	var torrent domain.Torrent
 	if err := json.Unmarshal(data, &torrent); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
@@ -44,7 +54,7 @@ func (r *Repository) SaveToFile(torrent *domain.Torrent, path string) error {
 		return err
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := fsys.Or(r.FS).WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
@@ -53,10 +63,82 @@ func (r *Repository) SaveToFile(torrent *domain.Torrent, path string) error {
 
 // LoadFromFile loads a torrent from a JSON file.
 func (r *Repository) LoadFromFile(path string) (*domain.Torrent, error) {
-	data, err := os.ReadFile(path)
+	data, err := fsys.Or(r.FS).ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	return r.LoadFromJSON(data)
 }
+
+// SplitPaths splits a comma-separated list of metadata files.
+func SplitPaths(s string) []string {
+	var paths []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// LoadSet loads the metadata of an album from one JSON file, or of a set from
+// the set's file and disc files covering single discs, assembled with
+// domain.AssembleDiscs.
+func (r *Repository) LoadSet(paths []string) (*domain.Torrent, error) {
+	var set *domain.Torrent
+	var discs []*domain.Torrent
+	for _, path := range paths {
+		torrent, err := r.LoadFromFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		switch {
+		case torrent.Disc > 0:
+			discs = append(discs, torrent)
+		case set != nil:
+			return nil, fmt.Errorf("%w: %s and another file both cover the whole set", domain.ErrDiscMetadata, path)
+		default:
+			set = torrent
+		}
+	}
+	if set == nil {
+		if len(discs) == 0 {
+			return nil, fmt.Errorf("%w: no metadata files given", domain.ErrNoTracks)
+		}
+		return nil, fmt.Errorf("%w: disc metadata needs the set's metadata file too", domain.ErrDiscMetadata)
+	}
+	if len(discs) == 0 {
+		return set, nil
+	}
+	return domain.AssembleDiscs(set, discs)
+}
+
+// RecordRemovedTags writes the junk tags removed from the torrent's tracks back
+// to the metadata files it was loaded from, in every file that lists the track.
+func (r *Repository) RecordRemovedTags(torrent *domain.Torrent, paths []string) error {
+	removed := make(map[string][]string)
+	for _, track := range torrent.Tracks() {
+		removed[track.File.Path] = track.RemovedTags
+	}
+	for _, path := range paths {
+		file, err := r.LoadFromFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		changed := false
+		for _, track := range file.Tracks() {
+			if tags, ok := removed[track.File.Path]; ok && !slices.Equal(tags, track.RemovedTags) {
+				track.RemovedTags = tags
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := r.SaveToFile(file, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,9 +2,11 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
 )
 
 func TestRepository_SaveAndLoad(t *testing.T) {
@@ -165,3 +167,74 @@ func TestRepository_RoleJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestRepository_FileRoundTripInMemory(t *testing.T) {
+	mem := fsys.NewMem()
+	mem.MkdirAll("/music/album", 0755)
+	repo := &Repository{FS: mem}
+
+	torrent := &domain.Torrent{RootPath: "album", Title: "Requiem", Files: []domain.FileLike{
+		&domain.Track{File: domain.File{Path: "01.flac"}, Disc: 1, Track: 1, Title: "Introitus"},
+	}}
+	if err := repo.SaveToFile(torrent, "/music/album/metadata.json"); err != nil {
+		t.Fatalf("SaveToFile() error = %v", err)
+	}
+	loaded, err := repo.LoadFromFile("/music/album/metadata.json")
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+	if loaded.Title != "Requiem" || len(loaded.Tracks()) != 1 {
+		t.Errorf("loaded %+v", loaded)
+	}
+	if _, err := repo.LoadFromFile("/music/other.json"); err == nil {
+		t.Error("LoadFromFile() of a missing file should fail")
+	}
+}
+
+func TestRepository_LoadSet(t *testing.T) {
+	mem := fsys.NewMem()
+	mem.MkdirAll("/music/box", 0755)
+	repo := &Repository{FS: mem}
+
+	set := &domain.Torrent{Title: "Box", Files: []domain.FileLike{
+		&domain.Track{File: domain.File{Path: "CD1/01.flac"}, Disc: 1, Track: 1, Title: "One"},
+		&domain.Track{File: domain.File{Path: "CD2/01.flac"}, Disc: 2, Track: 1, Title: "Two"},
+	}}
+	disc := &domain.Torrent{Disc: 2, Title: "Live", Files: []domain.FileLike{
+		&domain.Track{File: domain.File{Path: "CD2/01.flac"}, Disc: 2, Track: 1, Title: "Two (corrected)"},
+	}}
+	repo.SaveToFile(set, "/music/box/set.json")
+	repo.SaveToFile(disc, "/music/box/disc2.json")
+
+	paths := SplitPaths("/music/box/disc2.json, /music/box/set.json")
+	got, err := repo.LoadSet(paths)
+	if err != nil {
+		t.Fatalf("LoadSet() error = %v", err)
+	}
+	if tracks := got.Tracks(); len(tracks) != 2 || tracks[1].Title != "Two (corrected)" || tracks[1].DiscSubtitle != "Live" {
+		t.Errorf("LoadSet() tracks = %+v", tracks)
+	}
+	if _, err := repo.LoadSet(paths[:1]); !errors.Is(err, domain.ErrDiscMetadata) {
+		t.Errorf("LoadSet(disc only) error = %v, want ErrDiscMetadata", err)
+	}
+	if _, err := repo.LoadSet([]string{paths[1], paths[1]}); !errors.Is(err, domain.ErrDiscMetadata) {
+		t.Errorf("LoadSet(two sets) error = %v, want ErrDiscMetadata", err)
+	}
+
+	// Removed tags go back to each file listing the track
+	got.Tracks()[1].RemovedTags = []string{"COMMENT=rip (junk)"}
+	if err := repo.RecordRemovedTags(got, paths); err != nil {
+		t.Fatalf("RecordRemovedTags() error = %v", err)
+	}
+	for _, path := range paths {
+		saved, err := repo.LoadFromFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, track := range saved.Tracks() {
+			if want := track.Disc == 2; (len(track.RemovedTags) == 1) != want {
+				t.Errorf("%s: %s removed tags = %v", path, track.File.Path, track.RemovedTags)
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package tagging
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-flac/flacvorbis"
+	"github.com/go-flac/go-flac"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+// JunkRule recognizes a nonstandard tag that players and trackers do not need:
+// normalization data from iTunes, MQA markers, ripper signatures.
+type JunkRule struct {
+	Name  string
+	Match func(key, value string) bool
+}
+
+// ripperComment matches COMMENT and DESCRIPTION values injected by rippers and
+// AccurateRip verification.
+var ripperComment = regexp.MustCompile(`(?i)exact audio copy|\bEAC\b|dbpoweramp|\bXLD\b|cueripper|accuraterip|ripped (by|with)`)
+
+// JunkRules are the cleanup rules applied when writing tags.
+var JunkRules = []JunkRule{
+	{"iTunes", func(key, value string) bool {
+		return strings.HasPrefix(key, "ITUN") || strings.Contains(value, "iTunNORM") || strings.Contains(value, "iTunSMPB")
+	}},
+	{"MQA", func(key, _ string) bool {
+		return strings.HasPrefix(key, "MQA") || key == "ORIGINALSAMPLERATE"
+	}},
+	{"AccurateRip", func(key, _ string) bool {
+		return strings.HasPrefix(key, "ACCURATERIP")
+	}},
+	{"ripper comment", func(key, value string) bool {
+		return (key == "COMMENT" || key == "DESCRIPTION") && ripperComment.MatchString(value)
+	}},
+}
+
+// managedTags are the tags MetadataToVorbisComment may write, plus the totals
+// tied to its numbering. The writer owns them: existing values are replaced or,
+// when the metadata has none, dropped rather than carried over stale.
+var managedTags = []string{
+	"TITLE", "ALBUM", "TRACKNUMBER", "DISCNUMBER", "DISCSUBTITLE", "COMPOSER", "ARTIST",
+	"PERFORMER", "ENSEMBLE", "CONDUCTOR", "ORIGINALDATE", "COMPOSITIONDATE", "RECORDINGDATE",
+	"DATE", "LABEL", "CATALOGNUMBER", "ALBUMARTIST",
+	"TRACKTOTAL", "TOTALTRACKS", "DISCTOTAL", "TOTALDISCS",
+}
+
+// cleanComments merges existing "KEY=value" comments with the tags being
+// written: managed tags are replaced by tags, junk is removed unless its key is
+// in preserve, and other comments are carried over. A matching retention rule
+// overrides all of this, and may remove keys from tags: those kept from the
+// file or dropped. Returns the comments to keep and the tags removed, as
+// "KEY=value (rule)".
+func cleanComments(existing []string, tags map[string]string, preserve []string, retention domain.TagRetentionRules) (kept, removed []string) {
+	for _, comment := range existing {
+		key, value, ok := strings.Cut(comment, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToUpper(key)
+		switch policy, ruled := retention.Lookup(key); {
+		case ruled && policy == domain.TagKeep:
+			kept = append(kept, key+"="+value)
+			delete(tags, key)
+			continue
+		case ruled && (policy == domain.TagDrop || !slices.Contains(managedTags, key)):
+			// Overwriting a tag the writer doesn't manage leaves nothing in its place
+			removed = append(removed, key+"="+value+" ("+string(policy)+" policy)")
+			continue
+		case ruled:
+			continue
+		}
+		if slices.Contains(managedTags, key) {
+			continue
+		}
+		if rule := junkRule(key, value); rule != "" && !slices.ContainsFunc(preserve, func(p string) bool { return strings.EqualFold(p, key) }) {
+			removed = append(removed, key+"="+value+" ("+rule+")")
+			continue
+		}
+		kept = append(kept, key+"="+value)
+	}
+	for key := range tags {
+		if policy, _ := retention.Lookup(key); policy == domain.TagDrop {
+			delete(tags, key)
+		}
+	}
+	return kept, removed
+}
+
+// junkRule returns the name of the first rule matching the tag, or "".
+func junkRule(key, value string) string {
+	for _, rule := range JunkRules {
+		if rule.Match(key, value) {
+			return rule.Name
+		}
+	}
+	return ""
+}
+
+// JunkTags returns the junk and retention-dropped tags writing the file's tags
+// would remove, as "KEY=value (rule)", for previews such as tag -dry-run.
+func JunkTags(path string, preserve []string, retention domain.TagRetentionRules) ([]string, error) {
+	comments, err := readComments(fsys.OS, path)
+	if err != nil {
+		return nil, err
+	}
+	_, removed := cleanComments(comments, nil, preserve, retention)
+	return removed, nil
+}
+
+// readComments returns the tags of the file at path in files as "KEY=value"
+// comments: a FLAC file's Vorbis comments, or a DSD file's ID3 frames under
+// their Vorbis names.
+func readComments(files fsys.FS, path string) ([]string, error) {
+	if IsDSD(path) {
+		tags, err := readDSDTags(files, path)
+		if err != nil {
+			return nil, err
+		}
+		comments := make([]string, 0, len(tags))
+		for key, value := range tags {
+			comments = append(comments, key+"="+value)
+		}
+		slices.Sort(comments)
+		return comments, nil
+	}
+
+	f, err := files.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	flacFile, err := flac.ParseMetadata(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FLAC: %w", err)
+	}
+	for _, meta := range flacFile.Meta {
+		if meta.Type == flac.VorbisComment {
+			cmt, err := flacvorbis.ParseFromMetaDataBlock(*meta)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse vorbis comment: %w", err)
+			}
+			return cmt.Comments, nil
+		}
+	}
+	return nil, nil
+}
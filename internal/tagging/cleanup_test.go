@@ -0,0 +1,163 @@
+package tagging
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-flac/flacvorbis"
+	"github.com/go-flac/go-flac"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+func TestCleanComments(t *testing.T) {
+	existing := []string{
+		"TITLE=Old Title",
+		"TOTALTRACKS=9",
+		"ITUNNORM= 00000264 0000027F",
+		"COMMENT= 00000000 00000210 iTunSMPB",
+		"MQAENCODER=MQAEncode v1.1",
+		"originalsamplerate=96000",
+		"ACCURATERIPRESULT=AccurateRip: Accurate (confidence 12)",
+		"COMMENT=Ripped with EAC v1.6",
+		"DESCRIPTION=Liner notes scanned separately",
+		"REPLAYGAIN_TRACK_GAIN=-7.1 dB",
+		"malformed",
+	}
+
+	kept, removed := cleanComments(existing, nil, []string{"mqaencoder"}, nil)
+
+	wantKept := []string{"MQAENCODER=MQAEncode v1.1", "DESCRIPTION=Liner notes scanned separately", "REPLAYGAIN_TRACK_GAIN=-7.1 dB"}
+	if !slices.Equal(kept, wantKept) {
+		t.Errorf("kept = %q, want %q", kept, wantKept)
+	}
+	wantRemoved := []string{
+		"ITUNNORM= 00000264 0000027F (iTunes)",
+		"COMMENT= 00000000 00000210 iTunSMPB (iTunes)",
+		"ORIGINALSAMPLERATE=96000 (MQA)",
+		"ACCURATERIPRESULT=AccurateRip: Accurate (confidence 12) (AccurateRip)",
+		"COMMENT=Ripped with EAC v1.6 (ripper comment)",
+	}
+	if !slices.Equal(removed, wantRemoved) {
+		t.Errorf("removed = %q, want %q", removed, wantRemoved)
+	}
+}
+
+func TestCleanComments_Retention(t *testing.T) {
+	existing := []string{
+		"TITLE=Hand-corrected Title",
+		"LABEL=Old Label",
+		"ACCURATERIPRESULT=AccurateRip: Accurate (confidence 12)",
+		"ENCODER=reference libFLAC 1.3.2",
+		"REPLAYGAIN_TRACK_GAIN=-7.1 dB",
+		"MUSICBRAINZ_TRACKID=0d3c4b1f",
+	}
+	tags := map[string]string{"TITLE": "Title", "LABEL": "Label", "CATALOGNUMBER": "479 1234", "DATE": "1982"}
+	retention, err := domain.ParseTagRetentionRules([]string{"TITLE=keep", "ACCURATERIP*=keep", "REPLAYGAIN_*=drop", "DATE=drop", "ENCODER=overwrite", "LABEL=overwrite"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept, removed := cleanComments(existing, tags, nil, retention)
+
+	wantKept := []string{"TITLE=Hand-corrected Title", "ACCURATERIPRESULT=AccurateRip: Accurate (confidence 12)", "MUSICBRAINZ_TRACKID=0d3c4b1f"}
+	if !slices.Equal(kept, wantKept) {
+		t.Errorf("kept = %q, want %q", kept, wantKept)
+	}
+	wantRemoved := []string{"ENCODER=reference libFLAC 1.3.2 (overwrite policy)", "REPLAYGAIN_TRACK_GAIN=-7.1 dB (drop policy)"}
+	if !slices.Equal(removed, wantRemoved) {
+		t.Errorf("removed = %q, want %q", removed, wantRemoved)
+	}
+	// The kept TITLE and dropped DATE are not written; LABEL is overwritten
+	wantTags := map[string]string{"LABEL": "Label", "CATALOGNUMBER": "479 1234"}
+	if !maps.Equal(tags, wantTags) {
+		t.Errorf("tags = %v, want %v", tags, wantTags)
+	}
+}
+
+func TestFLACWriter_StripsJunk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "01.flac")
+	writeSyntheticFLAC(t, path, 4096)
+
+	f, err := flac.ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmt := flacvorbis.New()
+	for _, comment := range []string{"TITLE=Old", "ITUNNORM= 00000264", "MQAENCODER=MQAEncode v1.1", "REPLAYGAIN_TRACK_GAIN=-7.1 dB"} {
+		key, value, _ := strings.Cut(comment, "=")
+		if err := cmt.Add(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	block := cmt.Marshal()
+	f.Meta = append(f.Meta, &block)
+	if err := f.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if junk, err := JunkTags(path, nil, nil); err != nil || len(junk) != 2 {
+		t.Errorf("JunkTags() = %q, %v; want the ITUNNORM and MQAENCODER tags", junk, err)
+	}
+
+	track, torrent := benchTrack()
+	if err := (&FLACWriter{Preserve: []string{"MQAENCODER"}}).WriteTrack(path, path, track, torrent); err != nil {
+		t.Fatalf("WriteTrack() error = %v", err)
+	}
+	if !slices.Equal(track.RemovedTags, []string{"ITUNNORM= 00000264 (iTunes)"}) {
+		t.Errorf("RemovedTags = %q", track.RemovedTags)
+	}
+
+	comments, err := readComments(fsys.OS, path)
+	if err != nil {
+		t.Fatalf("readComments() error = %v", err)
+	}
+	for _, want := range []string{"MQAENCODER=MQAEncode v1.1", "REPLAYGAIN_TRACK_GAIN=-7.1 dB", "TITLE=" + track.Title} {
+		if !slices.Contains(comments, want) {
+			t.Errorf("comments = %q, missing %q", comments, want)
+		}
+	}
+	if slices.Contains(comments, "TITLE=Old") || slices.ContainsFunc(comments, func(c string) bool { return c[:8] == "ITUNNORM" }) {
+		t.Errorf("comments = %q, want old title and ITUNNORM gone", comments)
+	}
+}
+
+func TestFLACWriter_InMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "01.flac")
+	frames := writeSyntheticFLAC(t, path, 4096)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem := fsys.NewMem()
+	mem.MkdirAll("/album", 0755)
+	mem.WriteFile("/album/01.flac", data, 0644)
+
+	track, torrent := benchTrack()
+	if err := (&FLACWriter{FS: mem}).WriteTrack("/album/01.flac", "/album/01.flac", track, torrent); err != nil {
+		t.Fatalf("WriteTrack() error = %v", err)
+	}
+
+	comments, err := readComments(mem, "/album/01.flac")
+	if err != nil || !slices.Contains(comments, "TITLE="+track.Title) {
+		t.Errorf("readComments() = %q, %v; want the new title", comments, err)
+	}
+	tagged, _ := mem.ReadFile("/album/01.flac")
+	if !bytes.HasSuffix(tagged, frames) {
+		t.Error("audio frames not preserved")
+	}
+	var leftovers []string
+	mem.WalkDir("/album", func(path string, _ os.DirEntry, _ error) error {
+		leftovers = append(leftovers, path)
+		return nil
+	})
+	if len(leftovers) != 2 {
+		t.Errorf("files after tagging = %q, want only the album directory and its track", leftovers)
+	}
+}
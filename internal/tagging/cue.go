@@ -0,0 +1,205 @@
+package tagging
+
+import (
+	"bytes"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// RipFile is a rip log or CUE sheet to copy into the tagged output.
+type RipFile struct {
+	From string // Path relative to the source directory
+	To   string // Path relative to the output directory
+	// Unresolved lists the CUE FILE entries that name no tagged file; they are
+	// copied as they were.
+	Unresolved []string
+	data       []byte
+}
+
+// IsRipFile reports whether path names a rip log or CUE sheet.
+func IsRipFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".log" || ext == ".cue"
+}
+
+// RipFiles plans the copy of the rip logs and CUE sheets under from into the
+// tagged output. moved maps each tagged audio file's path relative to from to
+// its path in the output. A rip file goes beside the tagged files that came
+// from its folder, so disc folders keep their own logs and cue sheets. Logs are
+// copied byte for byte, as their checksums cover the text; CUE sheets are
+// re-encoded as UTF-8 and their FILE entries rewritten to the renamed files.
+func RipFiles(files fsys.FS, from string, moved map[string]string) ([]RipFile, error) {
+	files = fsys.Or(files)
+
+	// The output folder each source folder's tracks went to, and the tagged
+	// files by source path, compared case-insensitively and NFC-normalized as
+	// cue sheets written on Windows may not match the case on disk
+	sources := make([]string, 0, len(moved))
+	for source := range moved {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	destDirs := make(map[string]string)
+	byPath := make(map[string]string)
+	byStem := make(map[string]string)
+	for _, source := range sources {
+		dest := moved[source]
+		if _, ok := destDirs[filepath.Dir(source)]; !ok {
+			destDirs[filepath.Dir(source)] = filepath.Dir(dest)
+		}
+		byPath[ripFileKey(source)] = dest
+		if stem := ripFileKey(strings.TrimSuffix(source, filepath.Ext(source))); byStem[stem] == "" {
+			byStem[stem] = dest
+		}
+	}
+
+	var ripFiles []RipFile
+	err := files.WalkDir(from, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !IsRipFile(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		data, err := files.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Dir(rel)
+		destDir, ok := destDirs[dir]
+		if !ok {
+			destDir = dir
+		}
+		ripFile := RipFile{From: rel, To: filepath.Join(destDir, filepath.Base(rel)), data: data}
+		if strings.EqualFold(filepath.Ext(rel), ".cue") {
+			text, unresolved := RewriteCueFiles(DecodeCueText(data), func(name string) (string, bool) {
+				source := filepath.Join(dir, filepath.FromSlash(strings.ReplaceAll(name, `\`, "/")))
+				dest, ok := byPath[ripFileKey(source)]
+				if !ok {
+					// Rips often name the WAV the FLAC was encoded from
+					dest, ok = byStem[ripFileKey(strings.TrimSuffix(source, filepath.Ext(source)))]
+				}
+				if !ok {
+					return "", false
+				}
+				target, err := filepath.Rel(destDir, dest)
+				if err != nil {
+					return "", false
+				}
+				return filepath.ToSlash(target), true
+			})
+			ripFile.data, ripFile.Unresolved = []byte(text), unresolved
+		}
+		ripFiles = append(ripFiles, ripFile)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ripFiles, nil
+}
+
+// Copy writes the rip file into the output directory to.
+func (r RipFile) Copy(files fsys.FS, to string) error {
+	files = fsys.Or(files)
+	path := filepath.Join(to, r.To)
+	if err := files.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return files.WriteFile(path, r.data, 0644)
+}
+
+// ripFileKey normalizes a relative path for matching CUE FILE entries.
+func ripFileKey(path string) string {
+	return strings.ToLower(normalize.NFC(filepath.ToSlash(filepath.Clean(path))))
+}
+
+// DecodeCueText returns a CUE sheet's text as UTF-8 without a byte order mark.
+// Rippers write UTF-8, UTF-16 with a byte order mark, or the Windows code page
+// (EAC's "ANSI"); text that is none of these is read as Windows-1252.
+func DecodeCueText(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return string(data[3:])
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}), bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		decoded, err := unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder().Bytes(data)
+		if err == nil {
+			return string(decoded)
+		}
+	case utf8.Valid(data):
+		return string(data)
+	}
+	decoded, _ := charmap.Windows1252.NewDecoder().Bytes(data) // Every byte decodes
+	return string(decoded)
+}
+
+// CueFiles returns the names in a CUE sheet's FILE entries, in order.
+func CueFiles(text string) []string {
+	var names []string
+	for _, line := range strings.Split(text, "\n") {
+		if name, _, ok := cueFileEntry(line); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// RewriteCueFiles rewrites the FILE entries of a CUE sheet with rename, keeping
+// each entry's file type and the sheet's line endings. Entries rename reports
+// no new name for are left as they were and returned.
+func RewriteCueFiles(text string, rename func(name string) (string, bool)) (string, []string) {
+	var unresolved []string
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		name, fileType, ok := cueFileEntry(line)
+		if !ok {
+			continue
+		}
+		to, ok := rename(name)
+		if !ok {
+			unresolved = append(unresolved, name)
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		rewritten := indent + `FILE "` + to + `"`
+		if fileType != "" {
+			rewritten += " " + fileType
+		}
+		if strings.HasSuffix(line, "\r") {
+			rewritten += "\r"
+		}
+		lines[i] = rewritten
+	}
+	return strings.Join(lines, "\n"), unresolved
+}
+
+// cueFileEntry parses a FILE line (`FILE "name.wav" WAVE`) into the file name
+// and type.
+func cueFileEntry(line string) (name, fileType string, ok bool) {
+	command, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+	if !strings.EqualFold(command, "FILE") {
+		return "", "", false
+	}
+	args = strings.TrimSpace(args)
+	if strings.HasPrefix(args, `"`) {
+		if end := strings.Index(args[1:], `"`); end >= 0 {
+			return args[1 : end+1], strings.TrimSpace(args[end+2:]), true
+		}
+	}
+	if i := strings.LastIndex(args, " "); i > 0 {
+		return args[:i], args[i+1:], true
+	}
+	return args, "", args != ""
+}
@@ -0,0 +1,89 @@
+package tagging
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+func TestDecodeCueText(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"UTF-8", []byte("TITLE \"Dvořák\""), "TITLE \"Dvořák\""},
+		{"UTF-8 with BOM", []byte("\xEF\xBB\xBFTITLE \"Dvořák\""), "TITLE \"Dvořák\""},
+		{"Windows-1252", []byte("TITLE \"Faur\xe9 \x96 Requiem\""), "TITLE \"Fauré – Requiem\""},
+		{"UTF-16LE", []byte{0xFF, 0xFE, 'F', 0, 0xE9, 0}, "Fé"},
+		{"UTF-16BE", []byte{0xFE, 0xFF, 0, 'F', 0, 0xE9}, "Fé"},
+	}
+	for _, tt := range tests {
+		if got := DecodeCueText(tt.data); got != tt.want {
+			t.Errorf("%s: DecodeCueText() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRewriteCueFiles(t *testing.T) {
+	text := "PERFORMER \"Quartet\"\r\nFILE \"01 Allegro.wav\" WAVE\r\n  TRACK 01 AUDIO\r\nFILE 02.flac WAVE\r\nFILE \"image.flac\" WAVE\r\n"
+	got, unresolved := RewriteCueFiles(text, func(name string) (string, bool) {
+		switch name {
+		case "01 Allegro.wav":
+			return "01 - Allegro.flac", true
+		case "02.flac":
+			return "02 - Adagio.flac", true
+		}
+		return "", false
+	})
+	want := "PERFORMER \"Quartet\"\r\nFILE \"01 - Allegro.flac\" WAVE\r\n  TRACK 01 AUDIO\r\nFILE \"02 - Adagio.flac\" WAVE\r\nFILE \"image.flac\" WAVE\r\n"
+	if got != want {
+		t.Errorf("RewriteCueFiles() = %q, want %q", got, want)
+	}
+	if !slices.Equal(unresolved, []string{"image.flac"}) {
+		t.Errorf("unresolved = %q, want [image.flac]", unresolved)
+	}
+	if names := CueFiles(got); !slices.Equal(names, []string{"01 - Allegro.flac", "02 - Adagio.flac", "image.flac"}) {
+		t.Errorf("CueFiles() = %q", names)
+	}
+}
+
+func TestRipFiles(t *testing.T) {
+	mem := fsys.NewMem()
+	mem.MkdirAll("/src/CD1", 0755)
+	mem.MkdirAll("/out", 0755)
+	log := []byte("Exact Audio Copy V1.6\r\nFaur\xe9\r\n==== Log checksum ABC ====\r\n")
+	mem.WriteFile("/src/CD1/rip.log", log, 0644)
+	mem.WriteFile("/src/CD1/rip.cue", []byte("TITLE \"Faur\xe9\"\r\nFILE \"01 Pie Jesu.wav\" WAVE\r\nFILE \"HTOA.wav\" WAVE\r\n"), 0644)
+	mem.WriteFile("/src/notes.log", []byte("notes"), 0644)
+
+	ripFiles, err := RipFiles(mem, "/src", map[string]string{
+		"CD1/01 Pie Jesu.flac": "Disc 1/01 - Pie Jesu.flac",
+	})
+	if err != nil {
+		t.Fatalf("RipFiles() error = %v", err)
+	}
+	var got []string
+	for _, r := range ripFiles {
+		got = append(got, r.From+" -> "+r.To)
+		if err := r.Copy(mem, "/out"); err != nil {
+			t.Fatalf("Copy(%s) error = %v", r.From, err)
+		}
+	}
+	want := []string{"CD1/rip.cue -> Disc 1/rip.cue", "CD1/rip.log -> Disc 1/rip.log", "notes.log -> notes.log"}
+	if !slices.Equal(got, want) {
+		t.Errorf("RipFiles() = %q, want %q", got, want)
+	}
+	if !slices.Equal(ripFiles[0].Unresolved, []string{"HTOA.wav"}) {
+		t.Errorf("Unresolved = %q, want [HTOA.wav]", ripFiles[0].Unresolved)
+	}
+
+	cue, _ := mem.ReadFile("/out/Disc 1/rip.cue")
+	if want := "TITLE \"Fauré\"\r\nFILE \"01 - Pie Jesu.flac\" WAVE\r\nFILE \"HTOA.wav\" WAVE\r\n"; string(cue) != want {
+		t.Errorf("copied cue = %q, want %q", cue, want)
+	}
+	if copied, _ := mem.ReadFile("/out/Disc 1/rip.log"); string(copied) != string(log) {
+		t.Errorf("copied log = %q, want it unchanged", copied)
+	}
+}
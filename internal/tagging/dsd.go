@@ -0,0 +1,423 @@
+package tagging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dhowden/tag"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+// ErrNotDSD is returned for files that are not valid DSF or DFF (DSDIFF) files.
+var ErrNotDSD = errors.New("not a DSF or DFF file")
+
+// IsDSD reports whether path names a DSD file (.dsf or .dff), which carry their
+// tags as ID3v2 rather than Vorbis comments.
+func IsDSD(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".dsf", ".dff":
+		return true
+	}
+	return false
+}
+
+// vorbisToID3 maps the Vorbis comments MetadataToVorbisComment writes to ID3v2.4
+// text frames. Other comments are written as TXXX frames described by their name.
+var vorbisToID3 = map[string]string{
+	"TITLE":        "TIT2",
+	"ARTIST":       "TPE1",
+	"ALBUMARTIST":  "TPE2",
+	"CONDUCTOR":    "TPE3",
+	"COMPOSER":     "TCOM",
+	"ALBUM":        "TALB",
+	"TRACKNUMBER":  "TRCK",
+	"DISCNUMBER":   "TPOS",
+	"DISCSUBTITLE": "TSST",
+	"DATE":         "TDRC",
+	"ORIGINALDATE": "TDOR",
+	"LABEL":        "TPUB",
+	"GENRE":        "TCON",
+}
+
+// DSDWriter writes ID3v2.4 tags to DSF and DFF files. The DSD audio is
+// stream-copied untouched; only the ID3 tag (in DSF, the metadata chunk the
+// header points at; in DFF, the "ID3 " chunk) is replaced. Existing text frames
+// are carried over and cleaned as FLACWriter does with Vorbis comments.
+type DSDWriter struct {
+	Preserve  []string                 // Tags to keep even when a junk rule matches them
+	Retention domain.TagRetentionRules // Per-tag keep/drop/overwrite policies, overriding the defaults
+	FS        fsys.FS                  // Where files are read and written (nil: the operating system)
+}
+
+// NewDSDWriter creates a new DSDWriter.
+func NewDSDWriter() *DSDWriter {
+	return &DSDWriter{}
+}
+
+// TrackWriter writes a track's tags to an audio file; see FLACWriter and DSDWriter.
+type TrackWriter interface {
+	WriteTrack(sourcePath, destPath string, track *domain.Track, torrent *domain.Torrent) error
+}
+
+// WriterFor returns the writer for the audio file at path, keeping the preserve
+// tags from junk cleanup and applying the retention rules.
+func WriterFor(path string, preserve []string, retention domain.TagRetentionRules) TrackWriter {
+	if IsDSD(path) {
+		return &DSDWriter{Preserve: preserve, Retention: retention}
+	}
+	return &FLACWriter{Preserve: preserve, Retention: retention}
+}
+
+// WriteTrack writes a track's metadata to a new DSF or DFF file with the source
+// file's audio. Like FLACWriter.WriteTrack it writes a temporary file and renames
+// it into place, so destPath may be sourcePath.
+func (w *DSDWriter) WriteTrack(sourcePath, destPath string, track *domain.Track, torrent *domain.Torrent) error {
+	files := fsys.Or(w.FS)
+	src, err := files.Open(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to open source DSD: %w", err)
+	}
+	defer src.Close()
+
+	tags := MetadataToVorbisComment(track, torrent)
+	existing, _ := readComments(files, sourcePath) // A file without a tag has nothing to keep
+	kept, removed := cleanComments(existing, tags, w.Preserve, w.Retention)
+	for _, comment := range kept {
+		key, value, _ := strings.Cut(comment, "=")
+		tags[key] = value
+	}
+	id3 := marshalID3(tags)
+
+	tmp, err := files.CreateTemp(filepath.Dir(destPath), "."+filepath.Base(destPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save DSD: %w", err)
+	}
+	defer files.Remove(tmp.Name()) // No-op once renamed
+
+	out := bufio.NewWriterSize(tmp, 1<<20)
+	if strings.EqualFold(filepath.Ext(sourcePath), ".dff") {
+		err = writeDFF(out, src, id3)
+	} else {
+		err = writeDSF(out, src, id3)
+	}
+	if err == nil {
+		err = out.Flush()
+	}
+	if err == nil {
+		err = tmp.Chmod(0644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save DSD: %w", err)
+	}
+	if err := files.Rename(tmp.Name(), destPath); err != nil {
+		return fmt.Errorf("failed to save DSD: %w", err)
+	}
+	track.RemovedTags = removed
+	return nil
+}
+
+// DSF layout: a 28-byte "DSD " chunk (little-endian chunk size, total file size
+// and offset of the ID3 metadata, 0 when there is none), a "fmt " chunk, a
+// "data" chunk, then the ID3v2 tag.
+const dsfHeaderSize = 28
+
+// dsfAudioEnd returns the end of the DSF data chunk, checking the DSD header.
+func dsfAudioEnd(r io.ReaderAt) (header []byte, end int64, err error) {
+	header = make([]byte, dsfHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil || string(header[:4]) != "DSD " {
+		return nil, 0, ErrNotDSD
+	}
+	chunk := make([]byte, 12)
+	end = dsfHeaderSize
+	for _, id := range []string{"fmt ", "data"} {
+		if _, err := r.ReadAt(chunk, end); err != nil || string(chunk[:4]) != id {
+			return nil, 0, fmt.Errorf("%w: missing %q chunk", ErrNotDSD, id)
+		}
+		end += int64(binary.LittleEndian.Uint64(chunk[4:]))
+	}
+	return header, end, nil
+}
+
+func writeDSF(w io.Writer, src io.ReaderAt, id3 []byte) error {
+	header, end, err := dsfAudioEnd(src)
+	if err != nil {
+		return err
+	}
+	binary.LittleEndian.PutUint64(header[12:], uint64(end)+uint64(len(id3)))
+	binary.LittleEndian.PutUint64(header[20:], uint64(end))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, io.NewSectionReader(src, dsfHeaderSize, end-dsfHeaderSize)); err != nil {
+		return fmt.Errorf("failed to copy DSD audio: %w", err)
+	}
+	_, err = w.Write(id3)
+	return err
+}
+
+// dffChunk is a top-level chunk of a DFF (DSDIFF) file: big-endian, padded to
+// an even length, inside a "FRM8" container of form type "DSD ".
+type dffChunk struct {
+	id     string
+	offset int64 // Of the chunk header
+	size   int64 // Of the data, excluding header and pad byte
+}
+
+func (c dffChunk) span() int64 { return 12 + c.size + c.size%2 }
+
+func dffChunks(r io.ReaderAt) ([]dffChunk, error) {
+	header := make([]byte, 16)
+	if _, err := r.ReadAt(header, 0); err != nil || string(header[:4]) != "FRM8" || string(header[12:]) != "DSD " {
+		return nil, ErrNotDSD
+	}
+	end := 12 + int64(binary.BigEndian.Uint64(header[4:12]))
+	var chunks []dffChunk
+	for offset := int64(16); offset+12 <= end; {
+		if _, err := r.ReadAt(header[:12], offset); err != nil {
+			return nil, fmt.Errorf("%w: truncated chunk at byte %d", ErrNotDSD, offset)
+		}
+		c := dffChunk{id: string(header[:4]), offset: offset, size: int64(binary.BigEndian.Uint64(header[4:12]))}
+		chunks = append(chunks, c)
+		offset += c.span()
+	}
+	return chunks, nil
+}
+
+func writeDFF(w io.Writer, src io.ReaderAt, id3 []byte) error {
+	chunks, err := dffChunks(src)
+	if err != nil {
+		return err
+	}
+	chunks = slices.DeleteFunc(chunks, func(c dffChunk) bool { return c.id == "ID3 " })
+	tagChunk := dffChunk{id: "ID3 ", size: int64(len(id3))}
+
+	size := int64(4) + tagChunk.span() // Form type, then chunks
+	for _, c := range chunks {
+		size += c.span()
+	}
+	header := make([]byte, 16)
+	copy(header, "FRM8")
+	binary.BigEndian.PutUint64(header[4:], uint64(size))
+	copy(header[12:], "DSD ")
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	for _, c := range chunks {
+		if _, err := io.Copy(w, io.NewSectionReader(src, c.offset, c.span())); err != nil {
+			return fmt.Errorf("failed to copy %q chunk: %w", c.id, err)
+		}
+	}
+
+	copy(header, tagChunk.id)
+	binary.BigEndian.PutUint64(header[4:], uint64(tagChunk.size))
+	if _, err := w.Write(header[:12]); err != nil {
+		return err
+	}
+	if _, err := w.Write(id3); err != nil {
+		return err
+	}
+	if tagChunk.size%2 == 1 {
+		_, err = w.Write([]byte{0})
+	}
+	return err
+}
+
+// marshalID3 encodes Vorbis-style tags as an ID3v2.4 tag with UTF-8 text frames.
+func marshalID3(tags map[string]string) []byte {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var frames bytes.Buffer
+	for _, key := range keys {
+		value := tags[key]
+		key = strings.ToUpper(key)
+		id, ok := vorbisToID3[key]
+		data := []byte{3} // UTF-8
+		if ok {
+			data = append(data, value...)
+		} else {
+			id = "TXXX"
+			data = append(append(append(data, key...), 0), value...)
+		}
+		frames.WriteString(id)
+		frames.Write(syncsafe(len(data)))
+		frames.Write([]byte{0, 0}) // Flags
+		frames.Write(data)
+	}
+
+	out := []byte{'I', 'D', '3', 4, 0, 0}
+	out = append(out, syncsafe(frames.Len())...)
+	return append(out, frames.Bytes()...)
+}
+
+// syncsafe encodes n in 4 bytes of 7 bits each.
+func syncsafe(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
+
+// readID3 reads the ID3v2 tag of a DSF or DFF file.
+func readID3(f fsys.File) (tag.Metadata, error) {
+	var section *io.SectionReader
+	if strings.EqualFold(filepath.Ext(f.Name()), ".dff") {
+		chunks, err := dffChunks(f)
+		if err != nil {
+			return nil, err
+		}
+		i := slices.IndexFunc(chunks, func(c dffChunk) bool { return c.id == "ID3 " })
+		if i < 0 {
+			return nil, tag.ErrNoTagsFound
+		}
+		section = io.NewSectionReader(f, chunks[i].offset+12, chunks[i].size)
+	} else {
+		header := make([]byte, dsfHeaderSize)
+		if _, err := f.ReadAt(header, 0); err != nil || string(header[:4]) != "DSD " {
+			return nil, ErrNotDSD
+		}
+		offset := int64(binary.LittleEndian.Uint64(header[20:]))
+		if offset == 0 {
+			return nil, tag.ErrNoTagsFound
+		}
+		section = io.NewSectionReader(f, offset, 1<<62)
+	}
+	m, err := tag.ReadID3v2Tags(section)
+	if err != nil {
+		return nil, err
+	}
+	return id3Metadata{m}, nil
+}
+
+// id3Metadata reads the year from the ID3v2.4 date frames, which tag reads only
+// as raw frames.
+type id3Metadata struct {
+	tag.Metadata
+}
+
+func (m id3Metadata) Year() int {
+	if year := m.Metadata.Year(); year > 0 {
+		return year
+	}
+	for _, id := range []string{"TDRC", "TDOR"} {
+		if s, ok := m.Raw()[id].(string); ok {
+			if years := domain.ParseYears(s); len(years) > 0 {
+				return years[0]
+			}
+		}
+	}
+	return 0
+}
+
+// ReadDSDTags returns a DSF or DFF file's ID3 frames under their Vorbis comment
+// names (uppercase), the form readers of FLAC files get: text frames by the
+// vorbisToID3 mapping, TXXX frames by their description.
+func ReadDSDTags(path string) (map[string]string, error) {
+	return readDSDTags(fsys.OS, path)
+}
+
+// readDSDTags is ReadDSDTags on files.
+func readDSDTags(files fsys.FS, path string) (map[string]string, error) {
+	f, err := files.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := readID3(f)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(vorbisToID3))
+	for name, id := range vorbisToID3 {
+		names[id] = name
+	}
+	tags := make(map[string]string)
+	for id, v := range m.Raw() {
+		switch v := v.(type) {
+		case string:
+			if name, ok := names[id]; ok {
+				tags[name] = v
+			}
+		case *tag.Comm:
+			if strings.HasPrefix(id, "TXXX") && v.Description != "" {
+				tags[strings.ToUpper(v.Description)] = v.Text
+			}
+		}
+	}
+	return tags, nil
+}
+
+// dsdDuration reads the sample rate and per-channel sample count from a DSF
+// file's format chunk.
+func dsdDuration(f fsys.File) (time.Duration, error) {
+	if strings.EqualFold(filepath.Ext(f.Name()), ".dff") {
+		return 0, fmt.Errorf("DFF durations are not supported")
+	}
+	if _, _, err := dsfAudioEnd(f); err != nil {
+		return 0, err
+	}
+	b := make([]byte, 16)
+	if _, err := f.ReadAt(b, dsfHeaderSize+28); err != nil {
+		return 0, err
+	}
+	rate := int64(binary.LittleEndian.Uint32(b))
+	samples := int64(binary.LittleEndian.Uint64(b[8:]))
+	if rate == 0 {
+		return 0, fmt.Errorf("%w: zero sample rate", ErrNotDSD)
+	}
+	return samplesDuration(samples, rate), nil
+}
+
+// dsdChannelCount returns the channel count from a DSF fmt chunk or DFF CHNL chunk.
+func dsdChannelCount(f fsys.File) (int, error) {
+	if !strings.EqualFold(filepath.Ext(f.Name()), ".dff") {
+		if _, _, err := dsfAudioEnd(f); err != nil {
+			return 0, err
+		}
+		b := make([]byte, 4)
+		if _, err := f.ReadAt(b, dsfHeaderSize+24); err != nil {
+			return 0, err
+		}
+		return int(binary.LittleEndian.Uint32(b)), nil
+	}
+
+	chunks, err := dffChunks(f)
+	if err != nil {
+		return 0, err
+	}
+	i := slices.IndexFunc(chunks, func(c dffChunk) bool { return c.id == "PROP" })
+	if i < 0 {
+		return 0, fmt.Errorf("%w: missing \"PROP\" chunk", ErrNotDSD)
+	}
+	header := make([]byte, 12)
+	prop := chunks[i]
+	for offset := prop.offset + 16; offset+12 <= prop.offset+12+prop.size; {
+		if _, err := f.ReadAt(header, offset); err != nil {
+			return 0, err
+		}
+		size := int64(binary.BigEndian.Uint64(header[4:]))
+		if string(header[:4]) == "CHNL" {
+			b := make([]byte, 2)
+			if _, err := f.ReadAt(b, offset+12); err != nil {
+				return 0, err
+			}
+			return int(binary.BigEndian.Uint16(b)), nil
+		}
+		offset += 12 + size + size%2
+	}
+	return 0, fmt.Errorf("%w: missing \"CHNL\" chunk", ErrNotDSD)
+}
@@ -0,0 +1,163 @@
+package tagging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeSyntheticDSF writes a 2-channel DSF file with the given sample data and
+// optional trailing ID3 tag.
+func writeSyntheticDSF(t *testing.T, path string, audio, id3 []byte) {
+	t.Helper()
+	le := binary.LittleEndian
+	var b bytes.Buffer
+	header := make([]byte, dsfHeaderSize)
+	copy(header, "DSD ")
+	le.PutUint64(header[4:], dsfHeaderSize)
+	b.Write(header)
+
+	format := make([]byte, 52)
+	copy(format, "fmt ")
+	le.PutUint64(format[4:], 52)
+	le.PutUint32(format[12:], 1)       // Version
+	le.PutUint32(format[20:], 2)       // Channel type: stereo
+	le.PutUint32(format[24:], 2)       // Channels
+	le.PutUint32(format[28:], 2822400) // DSD64
+	le.PutUint32(format[32:], 1)
+	le.PutUint64(format[36:], 3*2822400) // Sample count: 3 seconds
+	b.Write(format)
+
+	data := make([]byte, 12)
+	copy(data, "data")
+	le.PutUint64(data[4:], uint64(12+len(audio)))
+	b.Write(data)
+	b.Write(audio)
+
+	out := b.Bytes()
+	if id3 != nil {
+		le.PutUint64(out[20:], uint64(len(out)))
+		out = append(out, id3...)
+	}
+	le.PutUint64(out[12:], uint64(len(out)))
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeSyntheticDFF writes a 6-channel DFF file with the given sample data.
+func writeSyntheticDFF(t *testing.T, path string, audio []byte) {
+	t.Helper()
+	be := binary.BigEndian
+	chunk := func(id string, data []byte) []byte {
+		out := make([]byte, 12, 12+len(data)+1)
+		copy(out, id)
+		be.PutUint64(out[4:], uint64(len(data)))
+		out = append(out, data...)
+		if len(data)%2 == 1 {
+			out = append(out, 0)
+		}
+		return out
+	}
+
+	channels := []byte{0, 6}
+	for _, id := range []string{"SLFT", "SRGT", "MLFT", "MRGT", "LS  ", "RS  "} {
+		channels = append(channels, id...)
+	}
+	prop := append([]byte("SND "), chunk("FS  ", []byte{0, 0x2B, 0x11, 0})...)
+	prop = append(prop, chunk("CHNL", channels)...)
+
+	var body []byte
+	body = append(body, "DSD "...)
+	body = append(body, chunk("FVER", []byte{1, 5, 0, 0})...)
+	body = append(body, chunk("PROP", prop)...)
+	body = append(body, chunk("DSD ", audio)...)
+	if err := os.WriteFile(path, chunk("FRM8", body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDSDWriter_WriteTrack(t *testing.T) {
+	audio := bytes.Repeat([]byte{0x69, 0x96}, 4097) // Odd chunk sizes exercise DFF padding
+	track, torrent := benchTrack()
+
+	for _, name := range []string{"track.dsf", "track.dff"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			wantChannels := 2
+			if name == "track.dff" {
+				writeSyntheticDFF(t, path, audio[:len(audio)-1])
+				wantChannels = 6
+			} else {
+				writeSyntheticDSF(t, path, audio, nil)
+			}
+			before, _ := os.ReadFile(path)
+
+			// Tag twice in place: the second tag must replace the first
+			for range 2 {
+				if err := NewDSDWriter().WriteTrack(path, path, track, torrent); err != nil {
+					t.Fatalf("WriteTrack() error = %v", err)
+				}
+			}
+
+			metadata, err := ReadMetadata(path)
+			if err != nil {
+				t.Fatalf("ReadMetadata() error = %v", err)
+			}
+			if metadata.Title != track.Title || metadata.Composer != "Ludwig van Beethoven" || metadata.TrackNumber != "1" || metadata.Year != "1995" {
+				t.Errorf("ReadMetadata() = %+v", metadata)
+			}
+			if mismatches, err := VerifyTags(path, track, torrent); err != nil || len(mismatches) != 0 {
+				t.Errorf("VerifyTags() = %v, %v", mismatches, err)
+			}
+
+			tags, err := ReadDSDTags(path)
+			if err != nil {
+				t.Fatalf("ReadDSDTags() error = %v", err)
+			}
+			for key, want := range map[string]string{"CONDUCTOR": "Herbert von Karajan", "ORIGINALDATE": "1963", "CATALOGNUMBER": "447 401-2", "LABEL": "Deutsche Grammophon"} {
+				if tags[key] != want {
+					t.Errorf("ReadDSDTags()[%s] = %q, want %q", key, tags[key], want)
+				}
+			}
+
+			if channels, err := ReadChannelCount(path); err != nil || channels != wantChannels {
+				t.Errorf("ReadChannelCount() = %d, %v; want %d", channels, err, wantChannels)
+			}
+			if duration, err := ReadDuration(path); strings.HasSuffix(path, ".dsf") && (err != nil || duration != 3*time.Second) {
+				t.Errorf("ReadDuration() = %v, %v; want 3s", duration, err)
+			}
+
+			after, _ := os.ReadFile(path)
+			id3 := marshalID3(MetadataToVorbisComment(track, torrent))
+			if !bytes.Contains(after, audio[:len(audio)-1]) || len(after) > len(before)+len(id3)+13 {
+				t.Errorf("file is %d bytes, was %d with a %d-byte tag; audio must be kept and the old tag replaced", len(after), len(before), len(id3))
+			}
+		})
+	}
+}
+
+func TestDSDWriter_NotDSD(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.dsf")
+	writeSyntheticFLAC(t, path, 1024)
+	track, torrent := benchTrack()
+	if err := NewDSDWriter().WriteTrack(path, path, track, torrent); err == nil {
+		t.Error("WriteTrack() of a FLAC file named .dsf should fail")
+	}
+	if _, err := ReadMetadata(path); err == nil {
+		t.Error("ReadMetadata() of a FLAC file named .dsf should fail")
+	}
+}
+
+func TestWriterFor(t *testing.T) {
+	if _, ok := WriterFor("a/01.DFF", nil, nil).(*DSDWriter); !ok {
+		t.Error("WriterFor(.DFF) should return a DSDWriter")
+	}
+	if _, ok := WriterFor("a/01.flac", nil, nil).(*FLACWriter); !ok {
+		t.Error("WriterFor(.flac) should return a FLACWriter")
+	}
+}
@@ -6,28 +6,20 @@ import (
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 )
 
-// GenerateFilename generates a filename for a track following rule 2.3.13 format.
-// Format: "## - Track Title.flac"
-// Uses leading zeros if totalTracks > 9 (rule 2.3.14).
-func GenerateFilename(track *domain.Track, totalTracks int) string {
-	// Determine track number format
-	trackNumStr := fmt.Sprintf("%d", track.Track)
-	if totalTracks > 9 {
-		trackNumStr = fmt.Sprintf("%02d", track.Track)
-	}
-
+// GenerateFilename generates a filename for a track of torrent following the
+// filename policy, e.g. "01 - Track Title.flac" (rules 2.3.13 and 2.3.14).
+func GenerateFilename(track *domain.Track, torrent *domain.Torrent, policy domain.FilenamePolicy) string {
 	// Sanitize title for filename
 	sanitizedTitle := SanitizeFilename(track.Title)
 	if sanitizedTitle == "" {
 		sanitizedTitle = "Untitled"
 	}
 
-	// Format: "## - Title.flac"
-	filename := fmt.Sprintf("%s - %s.flac", trackNumStr, sanitizedTitle)
-
-	return filename
+	number := policy.TrackNumber(track.Track, len(torrent.Tracks()))
+	return policy.Filename(number, SanitizeFilename(policy.Composer(track, torrent)), sanitizedTitle, ".flac")
 }
 
 // SanitizeFilename sanitizes a string for use as a filename.
@@ -37,6 +29,9 @@ func SanitizeFilename(name string) string {
 		return ""
 	}
 
+	// Compose decomposed (NFD) tags, as typed on macOS, so names match on every system
+	name = normalize.NFC(name)
+
 	// Remove invalid filesystem characters: / \ : * ? " < > |
 	invalidChars := regexp.MustCompile(`[<>:"/\\|?*]`)
 	name = invalidChars.ReplaceAllString(name, "")
@@ -73,7 +68,8 @@ func SanitizeFilename(name string) string {
 }
 
 // GenerateDiscSubdirectoryName generates a subdirectory name for a disc.
-// Format: "Disc N" or disc title if available.
+// Format: "Disc N" or disc title if available. For configurable "CD1"/"Disc 1 - Subtitle"
+// styles with zero-padding, use domain.DiscDirectoryName.
 func GenerateDiscSubdirectoryName(discNum int, discTitle string) string {
 	if discTitle != "" {
 		sanitized := domain.SanitizeDirectoryName(discTitle)
@@ -62,7 +62,7 @@ func TestGenerateFilename(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.Name, func(t *testing.T) {
-			got := GenerateFilename(tt.Track, tt.TotalTracks)
+			got := GenerateFilename(tt.Track, albumWith(tt.Track, tt.TotalTracks), domain.FilenamePolicyPlain)
 			if got != tt.Want {
 				t.Errorf("GenerateFilename() = %q, want %q", got, tt.Want)
 			}
@@ -70,6 +70,45 @@ func TestGenerateFilename(t *testing.T) {
 	}
 }
 
+// albumWith returns a torrent of totalTracks tracks including track.
+func albumWith(track *domain.Track, totalTracks int) *domain.Torrent {
+	files := []domain.FileLike{track}
+	for i := 1; i < totalTracks; i++ {
+		files = append(files, &domain.Track{Track: track.Track + i})
+	}
+	return &domain.Torrent{Files: files}
+}
+
+func TestGenerateFilename_RedactedPolicy(t *testing.T) {
+	bach := domain.Artist{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}
+	handel := domain.Artist{Name: "George Frideric Handel", Role: domain.RoleComposer}
+	first := &domain.Track{Track: 1, Title: "Toccata", Artists: []domain.Artist{bach}}
+	second := &domain.Track{Track: 2, Title: "Passacaglia", Artists: []domain.Artist{handel}}
+	solo := &domain.Track{Track: 3, Title: "Fugue", Artists: []domain.Artist{bach}}
+
+	tests := []struct {
+		Name    string
+		Track   *domain.Track
+		Torrent *domain.Torrent
+		Want    string
+	}{
+		{"two digits on a short album", solo, albumWith(solo, 5), "03 - Fugue.flac"},
+		{"three digits on a long album", solo, albumWith(solo, 120), "003 - Fugue.flac"},
+		{"composer on a multi-composer album", second, &domain.Torrent{Files: []domain.FileLike{first, second}}, "02 - Handel - Passacaglia.flac"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			got := GenerateFilename(tt.Track, tt.Torrent, domain.FilenamePolicyRedacted)
+			if got != tt.Want {
+				t.Errorf("GenerateFilename() = %q, want %q", got, tt.Want)
+			}
+			if issues := domain.FilenamePolicyRedacted.Check(got, tt.Track, tt.Torrent); len(issues) != 0 {
+				t.Errorf("generated filename %q breaks its own policy: %v", got, issues)
+			}
+		})
+	}
+}
+
 func TestSanitizeFilename(t *testing.T) {
 	tests := []struct {
 		Name  string
@@ -96,6 +135,11 @@ func TestSanitizeFilename(t *testing.T) {
 			Input: "Multiple    Spaces   Here",
 			Want:  "Multiple Spaces Here",
 		},
+		{
+			Name:  "decomposed (NFD) title",
+			Input: "Dvor\u030ca\u0301k: Humoresque",
+			Want:  "Dvo\u0159\u00e1k Humoresque",
+		},
 		{
 			Name:  "Windows reserved name",
 			Input: "CON",
@@ -5,9 +5,11 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
 	"github.com/dhowden/tag"
+	"github.com/go-flac/go-flac"
 )
 
 // Metadata represents audio file metadata tags.
@@ -98,17 +100,31 @@ func parseTrackNumber(s string) (int, error) {
 	return strconv.Atoi(strings.TrimSpace(s))
 }
 
-// ReadFile reads metadata from a FLAC file.
-func ReadMetadata(path string) (Metadata, error) {
+// ReadTags reads the tags of a FLAC, DSF or DFF file.
+func ReadTags(path string) (tag.Metadata, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return Metadata{}, fmt.Errorf("failed to open file: %w", err)
+		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	m, err := tag.ReadFrom(file)
+	var m tag.Metadata
+	if IsDSD(path) {
+		m, err = readID3(file)
+	} else {
+		m, err = tag.ReadFrom(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tags: %w", err)
+	}
+	return m, nil
+}
+
+// ReadMetadata reads metadata from a FLAC, DSF or DFF file.
+func ReadMetadata(path string) (Metadata, error) {
+	m, err := ReadTags(path)
 	if err != nil {
-		return Metadata{}, fmt.Errorf("failed to read tags: %w", err)
+		return Metadata{}, err
 	}
 
 	track, _ := m.Track()
@@ -128,7 +144,84 @@ func ReadMetadata(path string) (Metadata, error) {
 	return metadata, nil
 }
 
-// ReadTrackFromFile reads a FLAC file and returns a domain Track.
+// ReadChannelCount returns the number of audio channels from a FLAC file's STREAMINFO,
+// or a DSD file's format chunk. Only the metadata is read.
+func ReadChannelCount(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	if IsDSD(path) {
+		return dsdChannelCount(file)
+	}
+
+	f, err := flac.ParseMetadata(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read FLAC metadata: %w", err)
+	}
+	info, err := f.GetStreamInfo()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read STREAMINFO: %w", err)
+	}
+	return info.ChannelCount, nil
+}
+
+// ReadBitDepth returns the bits per sample from a FLAC file's STREAMINFO; DSD
+// files are 1-bit. Only the metadata is read.
+func ReadBitDepth(path string) (int, error) {
+	if IsDSD(path) {
+		return 1, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	f, err := flac.ParseMetadata(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read FLAC metadata: %w", err)
+	}
+	info, err := f.GetStreamInfo()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read STREAMINFO: %w", err)
+	}
+	return info.BitDepth, nil
+}
+
+// ReadDuration returns a track's playing time from a FLAC file's STREAMINFO
+// sample count, or a DSF file's format chunk. DFF files are not supported.
+func ReadDuration(path string) (time.Duration, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+	if IsDSD(path) {
+		return dsdDuration(file)
+	}
+
+	f, err := flac.ParseMetadata(file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read FLAC metadata: %w", err)
+	}
+	info, err := f.GetStreamInfo()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read STREAMINFO: %w", err)
+	}
+	if info.SampleCount == 0 || info.SampleRate == 0 {
+		return 0, fmt.Errorf("STREAMINFO has no sample count")
+	}
+	return samplesDuration(info.SampleCount, int64(info.SampleRate)), nil
+}
+
+// samplesDuration converts a sample count at rate Hz to a duration.
+func samplesDuration(samples, rate int64) time.Duration {
+	return time.Duration(samples/rate*int64(time.Second) + samples%rate*int64(time.Second)/rate)
+}
+
+// ReadTrackFromFile reads a FLAC, DSF or DFF file and returns a domain Track.
 func ReadTrackFromFile(path string, expectedDisc, expectedTrack int) (*domain.Track, error) {
 	metadata, err := ReadMetadata(path)
 	if err != nil {
@@ -0,0 +1,38 @@
+package tagging
+
+import (
+	"fmt"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// VerifyTags reads the tags of a FLAC file and reports fields that differ from
+// what FLACWriter would write for the track.
+func VerifyTags(path string, track *domain.Track, torrent *domain.Torrent) ([]string, error) {
+	metadata, err := ReadMetadata(path)
+	if err != nil {
+		return nil, err
+	}
+	return compareTags(metadata, MetadataToVorbisComment(track, torrent)), nil
+}
+
+// compareTags compares read metadata against expected Vorbis comments.
+// Separated for unit testing without needing real FLAC files.
+func compareTags(actual Metadata, expected map[string]string) []string {
+	var mismatches []string
+	check := func(tag, got string) {
+		want, ok := expected[tag]
+		if !ok || got == want {
+			return
+		}
+		mismatches = append(mismatches, fmt.Sprintf("%s is %q, expected %q", tag, got, want))
+	}
+
+	check("TITLE", actual.Title)
+	check("ALBUM", actual.Album)
+	check("COMPOSER", actual.Composer)
+	check("TRACKNUMBER", actual.TrackNumber)
+	check("DISCNUMBER", actual.DiscNumber)
+
+	return mismatches
+}
@@ -0,0 +1,43 @@
+package tagging
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestCompareTags(t *testing.T) {
+	torrent := &domain.Torrent{Title: "Goldberg Variations"}
+	track := &domain.Track{
+		Disc:    1,
+		Track:   3,
+		Title:   "Variation 2",
+		Artists: []domain.Artist{{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}},
+	}
+	expected := MetadataToVorbisComment(track, torrent)
+
+	matching := Metadata{
+		Title:       "Variation 2",
+		Album:       "Goldberg Variations",
+		Composer:    "Johann Sebastian Bach",
+		TrackNumber: "3",
+		DiscNumber:  "1",
+	}
+	if got := compareTags(matching, expected); len(got) != 0 {
+		t.Errorf("compareTags() = %v, want no mismatches", got)
+	}
+
+	changed := matching
+	changed.Title = "Variation 3"
+	changed.TrackNumber = "4"
+	if got := compareTags(changed, expected); len(got) != 2 {
+		t.Errorf("compareTags() = %v, want 2 mismatches", got)
+	}
+}
+
+func TestVerifyTags_MissingFile(t *testing.T) {
+	track := &domain.Track{Disc: 1, Track: 1, Title: "Title"}
+	if _, err := VerifyTags("/nonexistent/file.flac", track, &domain.Torrent{}); err == nil {
+		t.Error("VerifyTags() expected error for missing file")
+	}
+}
@@ -1,7 +1,10 @@
 package tagging
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -9,11 +12,22 @@ import (
 	"github.com/go-flac/go-flac"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
 )
 
-// FLACWriter writes FLAC metadata using the mewkiz/flac library.
-// It preserves audio data bit-perfect while updating only metadata blocks.
-type FLACWriter struct{}
+// FLACWriter writes FLAC metadata using the go-flac library.
+// It preserves audio data bit-perfect while updating only metadata blocks:
+// audio frames are stream-copied, never decoded or held in memory, so
+// multi-gigabyte hi-res tracks are tagged in constant memory.
+//
+// Existing Vorbis comments the metadata does not cover (ReplayGain, ISRC, ...)
+// are carried over, except junk matched by JunkRules, which is removed and
+// recorded in the track's RemovedTags. Retention rules override this per tag.
+type FLACWriter struct {
+	Preserve  []string                 // Tags to keep even when a junk rule matches them
+	Retention domain.TagRetentionRules // Per-tag keep/drop/overwrite policies, overriding the defaults
+	FS        fsys.FS                  // Where files are read and written (nil: the operating system)
+}
 
 // NewFLACWriter creates a new FLACWriter.
 func NewFLACWriter() *FLACWriter {
@@ -22,26 +36,81 @@ func NewFLACWriter() *FLACWriter {
 
 // WriteTrack writes a track's metadata to a new FLAC file.
 // The source file's audio data is preserved bit-perfect.
-// The destination file is created in the output directory structure.
+// The destination file is created in the output directory structure; it is
+// written to a temporary file and renamed into place, so destPath may be sourcePath.
 func (w *FLACWriter) WriteTrack(sourcePath, destPath string, track *domain.Track, torrent *domain.Torrent) error {
-	// Parse source FLAC
-	flacFile, err := flac.ParseFile(sourcePath)
+	files := fsys.Or(w.FS)
+	src, err := files.Open(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse source FLAC: %w", err)
+	}
+	defer src.Close()
+
+	// Parse only the metadata blocks; the reader is left at the first audio frame
+	flacFile, err := flac.ParseMetadata(src)
 	if err != nil {
 		return fmt.Errorf("failed to parse source FLAC: %w", err)
 	}
+	sync := make([]byte, 2)
+	if _, err := io.ReadFull(src, sync); err != nil || sync[0] != 0xFF || sync[1]>>2 != 0x3E {
+		return fmt.Errorf("failed to parse source FLAC: %w", flac.ErrorNoSyncCode)
+	}
+
+	removed, err := setVorbisComment(flacFile, MetadataToVorbisComment(track, torrent), w.Preserve, w.Retention)
+	if err != nil {
+		return err
+	}
+	track.RemovedTags = removed
+
+	// Write metadata then stream-copy the frames to a temporary file beside destPath
+	tmp, err := files.CreateTemp(filepath.Dir(destPath), "."+filepath.Base(destPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save FLAC: %w", err)
+	}
+	defer files.Remove(tmp.Name()) // No-op once renamed
+
+	out := bufio.NewWriterSize(tmp, 1<<20)
+	out.WriteString("fLaC")
+	for i, meta := range flacFile.Meta {
+		out.Write(meta.Marshal(i == len(flacFile.Meta)-1))
+	}
+	out.Write(sync)
+	if _, err := io.Copy(out, src); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to copy audio frames: %w", err)
+	}
+	if err := out.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save FLAC: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save FLAC: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save FLAC: %w", err)
+	}
+	if err := files.Rename(tmp.Name(), destPath); err != nil {
+		return fmt.Errorf("failed to save FLAC: %w", err)
+	}
 
-	// Convert domain metadata to Vorbis comment tags
-	tags := MetadataToVorbisComment(track, torrent)
+	return nil
+}
 
+// setVorbisComment replaces the file's Vorbis comment block with tags and the
+// existing comments cleanComments keeps, inserting a block after STREAMINFO if
+// there is none. Returns the junk removed.
+func setVorbisComment(flacFile *flac.File, tags map[string]string, preserve []string, retention domain.TagRetentionRules) ([]string, error) {
 	// Find or create VorbisComment block
 	var cmtBlock *flacvorbis.MetaDataBlockVorbisComment
 	var cmtIdx int = -1
 
 	for idx, metaBlock := range flacFile.Meta {
 		if metaBlock.Type == flac.VorbisComment {
+			var err error
 			cmtBlock, err = flacvorbis.ParseFromMetaDataBlock(*metaBlock)
 			if err != nil {
-				return fmt.Errorf("failed to parse vorbis comment: %w", err)
+				return nil, fmt.Errorf("failed to parse vorbis comment: %w", err)
 			}
 			cmtIdx = idx
 			break
@@ -56,8 +125,9 @@ func (w *FLACWriter) WriteTrack(sourcePath, destPath string, track *domain.Track
 	// Set vendor
 	cmtBlock.Vendor = "classical-tagger"
 
-	// Clear existing comments and add new ones
-	cmtBlock.Comments = nil
+	// Replace the managed comments, keeping other existing ones minus junk
+	kept, removed := cleanComments(cmtBlock.Comments, tags, preserve, retention)
+	cmtBlock.Comments = kept
 	for key, value := range tags {
 		cmtBlock.Add(strings.ToUpper(key), value)
 	}
@@ -76,13 +146,7 @@ func (w *FLACWriter) WriteTrack(sourcePath, destPath string, track *domain.Track
 			flacFile.Meta = append(flacFile.Meta, &metaBlock)
 		}
 	}
-
-	// Write to destination
-	if err := flacFile.Save(destPath); err != nil {
-		return fmt.Errorf("failed to save FLAC: %w", err)
-	}
-
-	return nil
+	return removed, nil
 }
 
 // MetadataToVorbisComment converts domain Track and Torrent to Vorbis comment tags.
@@ -95,6 +159,9 @@ func MetadataToVorbisComment(track *domain.Track, torrent *domain.Torrent) map[s
 	tags["ALBUM"] = torrent.Title
 	tags["TRACKNUMBER"] = strconv.Itoa(track.Track)
 	tags["DISCNUMBER"] = strconv.Itoa(track.Disc)
+	if track.DiscSubtitle != "" {
+		tags["DISCSUBTITLE"] = track.DiscSubtitle
+	}
 
 	// Find composer and format performers
 	var composer *domain.Artist
@@ -123,11 +190,12 @@ func MetadataToVorbisComment(track *domain.Track, torrent *domain.Torrent) map[s
 		for _, artist := range performers {
 			switch artist.Role {
 			case domain.RoleSoloist:
-				// Add to PERFORMER field (can be multiple)
+				// Add to PERFORMER field (can be multiple), as "Name (instrument)" when known
+				// and "Name (voice, character)" for a singer's role
 				if existing, ok := tags["PERFORMER"]; ok {
-					tags["PERFORMER"] = existing + "; " + artist.Name
+					tags["PERFORMER"] = existing + "; " + artist.TagCredit()
 				} else {
-					tags["PERFORMER"] = artist.Name
+					tags["PERFORMER"] = artist.TagCredit()
 				}
 			case domain.RoleEnsemble:
 				tags["ENSEMBLE"] = artist.Name
@@ -138,14 +206,22 @@ func MetadataToVorbisComment(track *domain.Track, torrent *domain.Torrent) map[s
 	}
 
 	// Date fields following Vorbis/MusicBrainz conventions:
-	// - ORIGINALDATE: Year of original recording/release
+	// - ORIGINALDATE: Year of original release
 	// - DATE: Release date of this specific edition
 	if torrent.OriginalYear > 0 {
 		tags["ORIGINALDATE"] = strconv.Itoa(torrent.OriginalYear)
 	}
 
-	// Edition information (if present)
-	if edition := torrent.Edition; edition != nil {
+	// Composition and recording dates are kept apart from the release year
+	if track.CompositionYear > 0 {
+		tags["COMPOSITIONDATE"] = strconv.Itoa(track.CompositionYear)
+	}
+	if years := torrent.TrackRecordingYears(track); len(years) > 0 {
+		tags["RECORDINGDATE"] = domain.FormatYears(years)
+	}
+
+	// Edition information (if present), the disc's own in a set gathered from several releases
+	if edition := torrent.TrackEdition(track); edition != nil {
 		// DATE: Edition year (this specific release)
 		if edition.Year > 0 {
 			tags["DATE"] = strconv.Itoa(edition.Year)
@@ -156,6 +232,16 @@ func MetadataToVorbisComment(track *domain.Track, torrent *domain.Torrent) map[s
 		if edition.CatalogNumber != "" {
 			tags["CATALOGNUMBER"] = edition.CatalogNumber
 		}
+		// Release identifiers let later runs and other tools skip searching
+		if edition.DiscogsReleaseID > 0 {
+			tags["DISCOGS_RELEASE_ID"] = strconv.Itoa(edition.DiscogsReleaseID)
+		}
+		if edition.MusicBrainzAlbumID != "" {
+			tags["MUSICBRAINZ_ALBUMID"] = edition.MusicBrainzAlbumID
+		}
+	}
+	if track.MusicBrainzTrackID != "" {
+		tags["MUSICBRAINZ_TRACKID"] = track.MusicBrainzTrackID
 	}
 
 	// ALBUMARTIST tag (if set in torrent)
@@ -43,6 +43,24 @@ func TestMetadataToVorbisComment(t *testing.T) {
 				"ORIGINALDATE": "1981",
 			},
 		},
+		{
+			Name: "disc of a set from another release",
+			Track: &domain.Track{
+				Disc: 2, Track: 1, Title: "Symphony No. 3: I", DiscSubtitle: "Eroica",
+				Edition: &domain.Edition{Label: "DG", CatalogNumber: "4474442", Year: 1996},
+			},
+			Torrent: &domain.Torrent{Title: "The Complete Symphonies", Edition: &domain.Edition{Label: "DG", CatalogNumber: "BOX1", Year: 2020}},
+			WantTags: map[string]string{
+				"TITLE":         "Symphony No. 3: I",
+				"ALBUM":         "The Complete Symphonies",
+				"TRACKNUMBER":   "1",
+				"DISCNUMBER":    "2",
+				"DISCSUBTITLE":  "Eroica",
+				"DATE":          "1996",
+				"LABEL":         "DG",
+				"CATALOGNUMBER": "4474442",
+			},
+		},
 		{
 			Name: "multiple performers with roles",
 			Track: func() *domain.Track {
@@ -111,6 +129,123 @@ func TestMetadataToVorbisComment(t *testing.T) {
 				"CATALOGNUMBER": "HMC902170",
 			},
 		},
+		{
+			Name: "composition and recording years",
+			Track: func() *domain.Track {
+				composer := domain.Artist{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}
+				performer := domain.Artist{Name: "Glenn Gould", Role: domain.RoleSoloist}
+				return &domain.Track{
+					Disc:            1,
+					Track:           1,
+					Title:           "Goldberg Variations, BWV 988: Aria",
+					Artists:         []domain.Artist{composer, performer},
+					CompositionYear: 1741,
+				}
+			}(),
+			Torrent: func() *domain.Torrent {
+				return &domain.Torrent{RootPath: "goldberg", Title: "Goldberg Variations", OriginalYear: 1982, RecordingYears: []int{1981}}
+			}(),
+			WantTags: map[string]string{
+				"COMPOSER":        "Johann Sebastian Bach",
+				"ARTIST":          "Glenn Gould",
+				"PERFORMER":       "Glenn Gould",
+				"TITLE":           "Goldberg Variations, BWV 988: Aria",
+				"ALBUM":           "Goldberg Variations",
+				"TRACKNUMBER":     "1",
+				"DISCNUMBER":      "1",
+				"ORIGINALDATE":    "1982",
+				"COMPOSITIONDATE": "1741",
+				"RECORDINGDATE":   "1981",
+			},
+		},
+		{
+			Name: "release identifiers",
+			Track: func() *domain.Track {
+				composer := domain.Artist{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}
+				return &domain.Track{
+					Disc:               1,
+					Track:              1,
+					Title:              "Aria",
+					Artists:            []domain.Artist{composer},
+					MusicBrainzTrackID: "0ab3f2c4-1d7e-4b0a-9c55-2f8e6b9d1a10",
+				}
+			}(),
+			Torrent: func() *domain.Torrent {
+				return &domain.Torrent{RootPath: "goldberg", Title: "Goldberg Variations", Edition: &domain.Edition{
+					Label:              "Sony Classical",
+					DiscogsReleaseID:   1234567,
+					MusicBrainzAlbumID: "5d2a6a0e-8c7f-4f3b-9b1e-0c7d2e4a6b8f",
+				}}
+			}(),
+			WantTags: map[string]string{
+				"COMPOSER":            "Johann Sebastian Bach",
+				"TITLE":               "Aria",
+				"ALBUM":               "Goldberg Variations",
+				"TRACKNUMBER":         "1",
+				"DISCNUMBER":          "1",
+				"LABEL":               "Sony Classical",
+				"DISCOGS_RELEASE_ID":  "1234567",
+				"MUSICBRAINZ_ALBUMID": "5d2a6a0e-8c7f-4f3b-9b1e-0c7d2e4a6b8f",
+				"MUSICBRAINZ_TRACKID": "0ab3f2c4-1d7e-4b0a-9c55-2f8e6b9d1a10",
+			},
+		},
+		{
+			Name: "disc subtitle",
+			Track: func() *domain.Track {
+				composer := domain.Artist{Name: "Richard Wagner", Role: domain.RoleComposer}
+				conductor := domain.Artist{Name: "Georg Solti", Role: domain.RoleConductor}
+				return &domain.Track{
+					Disc:         2,
+					Track:        1,
+					Title:        "Vorspiel",
+					Artists:      []domain.Artist{composer, conductor},
+					DiscSubtitle: "Act II",
+				}
+			}(),
+			Torrent: func() *domain.Torrent {
+				return &domain.Torrent{RootPath: "tristan", Title: "Tristan und Isolde", OriginalYear: 1960}
+			}(),
+			WantTags: map[string]string{
+				"COMPOSER":     "Richard Wagner",
+				"ARTIST":       "Georg Solti",
+				"CONDUCTOR":    "Georg Solti",
+				"TITLE":        "Vorspiel",
+				"ALBUM":        "Tristan und Isolde",
+				"TRACKNUMBER":  "1",
+				"DISCNUMBER":   "2",
+				"DISCSUBTITLE": "Act II",
+				"ORIGINALDATE": "1960",
+			},
+		},
+		{
+			Name: "soloist instruments",
+			Track: func() *domain.Track {
+				composer := domain.Artist{Name: "Sergei Rachmaninoff", Role: domain.RoleComposer}
+				return &domain.Track{
+					Disc:  1,
+					Track: 1,
+					Title: "Suite No. 2, Op. 17: I. Introduction",
+					Artists: []domain.Artist{
+						composer,
+						{Name: "Martha Argerich", Role: domain.RoleSoloist, Instrument: "piano"},
+						{Name: "Nelson Freire", Role: domain.RoleSoloist, Instrument: "piano"},
+					},
+				}
+			}(),
+			Torrent: func() *domain.Torrent {
+				return &domain.Torrent{RootPath: "rachmaninoff", Title: "Rachmaninoff: Suites", OriginalYear: 1982}
+			}(),
+			WantTags: map[string]string{
+				"COMPOSER":     "Sergei Rachmaninoff",
+				"ARTIST":       "Martha Argerich, Nelson Freire",
+				"PERFORMER":    "Martha Argerich (piano); Nelson Freire (piano)",
+				"TITLE":        "Suite No. 2, Op. 17: I. Introduction",
+				"ALBUM":        "Rachmaninoff: Suites",
+				"TRACKNUMBER":  "1",
+				"DISCNUMBER":   "1",
+				"ORIGINALDATE": "1982",
+			},
+		},
 		{
 			Name: "original recording remastered - different years",
 			Track: func() *domain.Track {
@@ -0,0 +1,95 @@
+// Package titlecase holds the protected words shared by the title-casing fixer and
+// the capitalization rules: tokens such as "BWV", "RIAS" or "II" whose spelling is
+// fixed and must survive case changes untouched.
+package titlecase
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// DefaultProtectedWords are always protected: catalogue prefixes, broadcaster and
+// orchestra initialisms, roman numerals and names with unusual capitalization.
+var DefaultProtectedWords = []string{
+	// Catalogues
+	"BWV", "BuxWV", "HWV", "KV", "RV", "SWV", "TWV", "WoO",
+	// Broadcasters, orchestras and places
+	"BBC", "BR", "CBSO", "HR", "LPO", "LSO", "MDR", "NDR", "ORF", "ORTF", "RIAS", "RPO", "SWR", "USSR", "WDR",
+	// Formats
+	"CD", "DSD", "SACD",
+	// Roman numerals (movement and part numbers)
+	"II", "III", "IV", "VI", "VII", "VIII", "IX", "XI", "XII",
+	// Names
+	"musicAeterna", "d'Indy",
+}
+
+// ProtectedWords looks up protected tokens case-insensitively.
+type ProtectedWords struct {
+	byKey map[string]string // lowercase -> protected spelling
+}
+
+// NewProtectedWords builds a lookup from the given spellings. Later spellings of
+// the same word replace earlier ones, so user words can override the defaults.
+func NewProtectedWords(words ...string) *ProtectedWords {
+	p := &ProtectedWords{byKey: make(map[string]string, len(words))}
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			p.byKey[strings.ToLower(w)] = w
+		}
+	}
+	return p
+}
+
+// Lookup returns the protected spelling of token, ignoring case and surrounding
+// punctuation, with that punctuation restored: "bwv," -> "BWV,".
+func (p *ProtectedWords) Lookup(token string) (string, bool) {
+	start := strings.IndexFunc(token, isWordRune)
+	if start < 0 {
+		return "", false
+	}
+	end := strings.LastIndexFunc(token, isWordRune) + 1
+	word, ok := p.byKey[strings.ToLower(token[start:end])]
+	if !ok {
+		return "", false
+	}
+	return token[:start] + word + token[end:], true
+}
+
+// IsProtected reports whether token, less surrounding punctuation, is a protected
+// word spelled exactly as protected.
+func (p *ProtectedWords) IsProtected(token string) bool {
+	spelled, ok := p.Lookup(token)
+	return ok && spelled == token
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+var (
+	mu        sync.RWMutex
+	protected = NewProtectedWords(DefaultProtectedWords...)
+)
+
+// Configure adds user words (from config) to the defaults used by Lookup and IsProtected.
+func Configure(words []string) {
+	p := NewProtectedWords(append(append([]string(nil), DefaultProtectedWords...), words...)...)
+	mu.Lock()
+	protected = p
+	mu.Unlock()
+}
+
+// Lookup returns the protected spelling of token using the configured words.
+func Lookup(token string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return protected.Lookup(token)
+}
+
+// IsProtected reports whether token is a configured protected word spelled as protected.
+func IsProtected(token string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return protected.IsProtected(token)
+}
@@ -0,0 +1,51 @@
+package titlecase
+
+import "testing"
+
+func TestProtectedWords_Lookup(t *testing.T) {
+	p := NewProtectedWords(DefaultProtectedWords...)
+	tests := []struct {
+		Token  string
+		Want   string
+		WantOK bool
+	}{
+		{"bwv", "BWV", true},
+		{"BWV,", "BWV,", true},
+		{"(Rias)", "(RIAS)", true},
+		{"MUSICAETERNA", "musicAeterna", true},
+		{"D'INDY:", "d'Indy:", true},
+		{"ii", "II", true},
+		{"Symphony", "", false},
+		{"--", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := p.Lookup(tt.Token)
+		if got != tt.Want || ok != tt.WantOK {
+			t.Errorf("Lookup(%q) = %q, %v; want %q, %v", tt.Token, got, ok, tt.Want, tt.WantOK)
+		}
+	}
+
+	if !p.IsProtected("KV") || !p.IsProtected("USSR.") || p.IsProtected("Kv") {
+		t.Error("IsProtected should accept only the protected spelling")
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	defer Configure(nil)
+
+	Configure([]string{"NHK", "Bwv"})
+	if got, _ := Lookup("nhk"); got != "NHK" {
+		t.Errorf("Lookup(nhk) = %q, want user word NHK", got)
+	}
+	if !IsProtected("Bwv") || IsProtected("BWV") {
+		t.Error("user spelling should override the default")
+	}
+	if !IsProtected("RIAS") {
+		t.Error("defaults should remain protected")
+	}
+
+	Configure(nil)
+	if _, ok := Lookup("NHK"); ok {
+		t.Error("Configure should replace earlier user words")
+	}
+}
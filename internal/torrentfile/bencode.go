@@ -0,0 +1,175 @@
+package torrentfile
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// decoder reads bencoded values from a byte slice.
+// Decoded values are int64, string, []any, or map[string]any.
+type decoder struct {
+	data []byte
+	pos  int
+	// infoStart/infoEnd record the raw span of the top-level "info" dictionary
+	// so the info hash can be computed over the original bytes.
+	infoStart, infoEnd int
+	depth              int
+}
+
+// Decode decodes a single bencoded value.
+func Decode(data []byte) (any, error) {
+	d := &decoder{data: data}
+	v, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("bencode: trailing data at offset %d", d.pos)
+	}
+	return v, nil
+}
+
+func (d *decoder) value() (any, error) {
+	if d.pos >= len(d.data) {
+		return nil, fmt.Errorf("bencode: unexpected end of data")
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		return d.integer()
+	case c == 'l':
+		return d.list()
+	case c == 'd':
+		return d.dict()
+	case c >= '0' && c <= '9':
+		return d.str()
+	default:
+		return nil, fmt.Errorf("bencode: invalid token %q at offset %d", c, d.pos)
+	}
+}
+
+func (d *decoder) integer() (int64, error) {
+	end := bytes.IndexByte(d.data[d.pos:], 'e')
+	if end < 0 {
+		return 0, fmt.Errorf("bencode: unterminated integer at offset %d", d.pos)
+	}
+	n, err := strconv.ParseInt(string(d.data[d.pos+1:d.pos+end]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bencode: invalid integer at offset %d: %w", d.pos, err)
+	}
+	d.pos += end + 1
+	return n, nil
+}
+
+func (d *decoder) str() (string, error) {
+	colon := bytes.IndexByte(d.data[d.pos:], ':')
+	if colon < 0 {
+		return "", fmt.Errorf("bencode: invalid string length at offset %d", d.pos)
+	}
+	n, err := strconv.Atoi(string(d.data[d.pos : d.pos+colon]))
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("bencode: invalid string length at offset %d", d.pos)
+	}
+	start := d.pos + colon + 1
+	if start+n > len(d.data) {
+		return "", fmt.Errorf("bencode: string overruns data at offset %d", d.pos)
+	}
+	d.pos = start + n
+	return string(d.data[start:d.pos]), nil
+}
+
+func (d *decoder) list() ([]any, error) {
+	d.pos++ // 'l'
+	d.depth++
+	defer func() { d.depth-- }()
+	list := []any{}
+	for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	if d.pos >= len(d.data) {
+		return nil, fmt.Errorf("bencode: unterminated list")
+	}
+	d.pos++ // 'e'
+	return list, nil
+}
+
+func (d *decoder) dict() (map[string]any, error) {
+	d.pos++ // 'd'
+	d.depth++
+	defer func() { d.depth-- }()
+	dict := make(map[string]any)
+	for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+		key, err := d.str()
+		if err != nil {
+			return nil, err
+		}
+		start := d.pos
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		if d.depth == 1 && key == "info" {
+			d.infoStart, d.infoEnd = start, d.pos
+		}
+		dict[key] = v
+	}
+	if d.pos >= len(d.data) {
+		return nil, fmt.Errorf("bencode: unterminated dictionary")
+	}
+	d.pos++ // 'e'
+	return dict, nil
+}
+
+// Encode bencodes a value built from integers, strings, []byte, []any, and map[string]any.
+func Encode(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeValue(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, v any) error {
+	switch v := v.(type) {
+	case int:
+		fmt.Fprintf(buf, "i%de", v)
+	case int64:
+		fmt.Fprintf(buf, "i%de", v)
+	case string:
+		fmt.Fprintf(buf, "%d:%s", len(v), v)
+	case []byte:
+		fmt.Fprintf(buf, "%d:", len(v))
+		buf.Write(v)
+	case []any:
+		buf.WriteByte('l')
+		for _, item := range v {
+			if err := encodeValue(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('e')
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		// Dictionary keys must be sorted as raw strings
+		sort.Strings(keys)
+		buf.WriteByte('d')
+		for _, k := range keys {
+			fmt.Fprintf(buf, "%d:%s", len(k), k)
+			if err := encodeValue(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('e')
+	default:
+		return fmt.Errorf("bencode: unsupported type %T", v)
+	}
+	return nil
+}
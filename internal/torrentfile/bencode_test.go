@@ -0,0 +1,76 @@
+package torrentfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  any
+	}{
+		{"integer", "i42e", int64(42)},
+		{"negative integer", "i-3e", int64(-3)},
+		{"string", "4:spam", "spam"},
+		{"empty string", "0:", ""},
+		{"list", "l4:spami7ee", []any{"spam", int64(7)}},
+		{"dictionary", "d3:bar4:spam3:fooi42ee", map[string]any{"bar": "spam", "foo": int64(42)}},
+		{"nested", "d4:listli1ei2eee", map[string]any{"list": []any{int64(1), int64(2)}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Decode(%q) error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	inputs := []string{"", "i42", "ixe", "5:abc", "l4:spam", "d3:foo", "x", "i1ei2e"}
+	for _, input := range inputs {
+		if _, err := Decode([]byte(input)); err == nil {
+			t.Errorf("Decode(%q) expected error", input)
+		}
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	value := map[string]any{
+		"announce": "https://example.com/announce",
+		"info": map[string]any{
+			"name":         "Album",
+			"piece length": int64(16384),
+			"files":        []any{map[string]any{"length": int64(3), "path": []any{"a.flac"}}},
+		},
+	}
+
+	data, err := Encode(value)
+	if err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	got, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if !reflect.DeepEqual(got, value) {
+		t.Errorf("round trip = %#v, want %#v", got, value)
+	}
+}
+
+func TestEncode_SortsKeys(t *testing.T) {
+	data, err := Encode(map[string]any{"b": 1, "a": 2})
+	if err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	if string(data) != "d1:ai2e1:bi1ee" {
+		t.Errorf("Encode = %q", data)
+	}
+}
@@ -0,0 +1,84 @@
+package torrentfile
+
+import (
+	"crypto/sha1"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// pieceJob is a piece read and waiting to be hashed.
+type pieceJob struct {
+	index int
+	data  []byte
+}
+
+// HashPieces reads r to the end in pieces of pieceLength bytes and returns the
+// SHA-1 hash of each; the last piece is shorter when the content doesn't fill it.
+// Reading stays sequential, one piece-sized read at a time, while hashing runs
+// on workers goroutines (GOMAXPROCS when workers < 1). At most two pieces per
+// worker are held in memory.
+func HashPieces(r io.Reader, pieceLength int64, workers int) ([][sha1.Size]byte, error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var (
+		mu     sync.Mutex
+		hashes [][sha1.Size]byte
+		wg     sync.WaitGroup
+	)
+	jobs := make(chan pieceJob, workers)
+	free := make(chan []byte, 2*workers)
+	for range workers {
+		wg.Go(func() {
+			for job := range jobs {
+				sum := sha1.Sum(job.data)
+				mu.Lock()
+				hashes[job.index] = sum
+				mu.Unlock()
+				free <- job.data[:cap(job.data)]
+			}
+		})
+	}
+
+	var err error
+	allocated := 0
+	for index := 0; ; index++ {
+		// Reuse a hashed piece's buffer, allocating only up to the limit
+		var buf []byte
+		select {
+		case buf = <-free:
+		default:
+			if allocated < cap(free) {
+				buf = make([]byte, pieceLength)
+				allocated++
+			} else {
+				buf = <-free
+			}
+		}
+
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			mu.Lock()
+			hashes = append(hashes, [sha1.Size]byte{})
+			mu.Unlock()
+			jobs <- pieceJob{index: index, data: buf[:n]}
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			err = readErr
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
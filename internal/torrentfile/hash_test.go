@@ -0,0 +1,46 @@
+package torrentfile
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"io"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestHashPieces(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []int{0, 1, 64, 1000} {
+		data := make([]byte, size)
+		rng.Read(data)
+
+		// Sequential reference: one hash per 64-byte piece, the last one short
+		var want [][sha1.Size]byte
+		for start := 0; start < size; start += 64 {
+			want = append(want, sha1.Sum(data[start:min(start+64, size)]))
+		}
+		for _, workers := range []int{0, 1, 3, 16} {
+			got, err := HashPieces(bytes.NewReader(data), 64, workers)
+			if err != nil {
+				t.Fatalf("HashPieces(%d bytes, %d workers) error = %v", size, workers, err)
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("HashPieces(%d bytes, %d workers) = %d hashes, want the %d sequential ones", size, workers, len(got), len(want))
+			}
+		}
+	}
+}
+
+func TestHashPieces_ReadError(t *testing.T) {
+	failure := errors.New("disk on fire")
+	r := io.MultiReader(bytes.NewReader(make([]byte, 200)), &failingReader{err: failure})
+	if _, err := HashPieces(r, 64, 2); !errors.Is(err, failure) {
+		t.Errorf("HashPieces() error = %v, want %v", err, failure)
+	}
+}
+
+type failingReader struct{ err error }
+
+func (r *failingReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,141 @@
+package torrentfile
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"path"
+)
+
+// FileEntry is a file listed in a torrent's info dictionary.
+type FileEntry struct {
+	Path   string // Slash-separated path relative to the torrent root
+	Length int64
+}
+
+// MetaInfo is the subset of a .torrent file needed to verify content on disk.
+type MetaInfo struct {
+	Announce    string
+	Name        string
+	PieceLength int64
+	Pieces      [][sha1.Size]byte
+	Files       []FileEntry // Single-file torrents have one entry named Name
+	Private     bool
+	Source      string
+	InfoHash    [sha1.Size]byte
+}
+
+// TotalLength returns the combined length of all files.
+func (m *MetaInfo) TotalLength() int64 {
+	var total int64
+	for _, f := range m.Files {
+		total += f.Length
+	}
+	return total
+}
+
+// IsSingleFile reports whether the torrent contains a single file rather than a directory.
+func (m *MetaInfo) IsSingleFile() bool {
+	return len(m.Files) == 1 && m.Files[0].Path == m.Name
+}
+
+// Load reads and parses a .torrent file.
+func Load(filename string) (*MetaInfo, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read torrent file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse parses bencoded .torrent data.
+func Parse(data []byte) (*MetaInfo, error) {
+	d := &decoder{data: data}
+	v, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	root, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("torrent: top level is not a dictionary")
+	}
+	info, ok := root["info"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("torrent: missing info dictionary")
+	}
+
+	m := &MetaInfo{
+		InfoHash: sha1.Sum(data[d.infoStart:d.infoEnd]),
+	}
+	m.Announce, _ = root["announce"].(string)
+	m.Name, _ = info["name"].(string)
+	m.Source, _ = info["source"].(string)
+	if private, _ := info["private"].(int64); private == 1 {
+		m.Private = true
+	}
+	if m.Name == "" {
+		return nil, fmt.Errorf("torrent: missing name")
+	}
+
+	m.PieceLength, _ = info["piece length"].(int64)
+	if m.PieceLength <= 0 {
+		return nil, fmt.Errorf("torrent: invalid piece length %d", m.PieceLength)
+	}
+
+	pieces, _ := info["pieces"].(string)
+	if len(pieces)%sha1.Size != 0 {
+		return nil, fmt.Errorf("torrent: pieces length %d is not a multiple of %d", len(pieces), sha1.Size)
+	}
+	for i := 0; i < len(pieces); i += sha1.Size {
+		var h [sha1.Size]byte
+		copy(h[:], pieces[i:i+sha1.Size])
+		m.Pieces = append(m.Pieces, h)
+	}
+
+	if length, ok := info["length"].(int64); ok {
+		m.Files = []FileEntry{{Path: m.Name, Length: length}}
+	} else {
+		files, ok := info["files"].([]any)
+		if !ok {
+			return nil, fmt.Errorf("torrent: missing length and files")
+		}
+		for i, f := range files {
+			entry, err := parseFileEntry(f)
+			if err != nil {
+				return nil, fmt.Errorf("torrent: file %d: %w", i, err)
+			}
+			m.Files = append(m.Files, entry)
+		}
+	}
+
+	expected := (m.TotalLength() + m.PieceLength - 1) / m.PieceLength
+	if int64(len(m.Pieces)) != expected {
+		return nil, fmt.Errorf("torrent: has %d pieces, expected %d", len(m.Pieces), expected)
+	}
+
+	return m, nil
+}
+
+func parseFileEntry(v any) (FileEntry, error) {
+	dict, ok := v.(map[string]any)
+	if !ok {
+		return FileEntry{}, fmt.Errorf("entry is not a dictionary")
+	}
+	length, ok := dict["length"].(int64)
+	if !ok || length < 0 {
+		return FileEntry{}, fmt.Errorf("invalid length")
+	}
+	parts, ok := dict["path"].([]any)
+	if !ok || len(parts) == 0 {
+		return FileEntry{}, fmt.Errorf("missing path")
+	}
+	segments := make([]string, 0, len(parts))
+	for _, p := range parts {
+		s, ok := p.(string)
+		if !ok || s == "" || s == "." || s == ".." {
+			return FileEntry{}, fmt.Errorf("invalid path segment %v", p)
+		}
+		segments = append(segments, s)
+	}
+	return FileEntry{Path: path.Join(segments...), Length: length}, nil
+}
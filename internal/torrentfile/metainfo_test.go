@@ -0,0 +1,141 @@
+package torrentfile
+
+import (
+	"crypto/sha1"
+	"strings"
+	"testing"
+)
+
+// buildTorrent bencodes a multi-file torrent for the given files and contents.
+func buildTorrent(t testing.TB, name string, pieceLength int64, files []FileEntry, contents []string) []byte {
+	t.Helper()
+	var all strings.Builder
+	fileList := []any{}
+	for i, f := range files {
+		all.WriteString(contents[i])
+		parts := []any{}
+		for _, p := range strings.Split(f.Path, "/") {
+			parts = append(parts, p)
+		}
+		fileList = append(fileList, map[string]any{"length": f.Length, "path": parts})
+	}
+
+	var pieces strings.Builder
+	data := all.String()
+	for i := int64(0); i < int64(len(data)); i += pieceLength {
+		end := min(i+pieceLength, int64(len(data)))
+		h := sha1.Sum([]byte(data[i:end]))
+		pieces.Write(h[:])
+	}
+
+	encoded, err := Encode(map[string]any{
+		"announce": "https://flacsfor.me/announce",
+		"info": map[string]any{
+			"name":         name,
+			"piece length": pieceLength,
+			"pieces":       pieces.String(),
+			"files":        fileList,
+			"private":      1,
+			"source":       "RED",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	return encoded
+}
+
+func TestParse_MultiFile(t *testing.T) {
+	files := []FileEntry{
+		{Path: "01 - One.flac", Length: 5},
+		{Path: "CD2/01 - Two.flac", Length: 7},
+	}
+	data := buildTorrent(t, "Album", 4, files, []string{"aaaaa", "bbbbbbb"})
+
+	m, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if m.Name != "Album" {
+		t.Errorf("Name = %q, want Album", m.Name)
+	}
+	if m.Announce != "https://flacsfor.me/announce" {
+		t.Errorf("Announce = %q", m.Announce)
+	}
+	if !m.Private || m.Source != "RED" {
+		t.Errorf("Private = %v, Source = %q", m.Private, m.Source)
+	}
+	if len(m.Pieces) != 3 {
+		t.Errorf("Pieces = %d, want 3", len(m.Pieces))
+	}
+	if len(m.Files) != 2 || m.Files[1].Path != "CD2/01 - Two.flac" || m.Files[1].Length != 7 {
+		t.Errorf("Files = %+v", m.Files)
+	}
+	if m.TotalLength() != 12 {
+		t.Errorf("TotalLength = %d, want 12", m.TotalLength())
+	}
+	if m.IsSingleFile() {
+		t.Error("IsSingleFile = true, want false")
+	}
+}
+
+func TestParse_InfoHashCoversRawInfo(t *testing.T) {
+	data := buildTorrent(t, "Album", 4, []FileEntry{{Path: "a.flac", Length: 2}}, []string{"aa"})
+	m, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	start := strings.Index(string(data), "4:infod") + len("4:info")
+	want := sha1.Sum(data[start : len(data)-1])
+	if m.InfoHash != want {
+		t.Errorf("InfoHash = %x, want %x", m.InfoHash, want)
+	}
+}
+
+func TestParse_SingleFile(t *testing.T) {
+	h := sha1.Sum([]byte("abc"))
+	data, _ := Encode(map[string]any{
+		"info": map[string]any{
+			"name":         "track.flac",
+			"length":       3,
+			"piece length": 16384,
+			"pieces":       string(h[:]),
+		},
+	})
+
+	m, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if !m.IsSingleFile() {
+		t.Error("IsSingleFile = false, want true")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		info map[string]any
+	}{
+		{"missing name", map[string]any{"length": 1, "piece length": 1, "pieces": strings.Repeat("x", 20)}},
+		{"bad piece length", map[string]any{"name": "a", "length": 1, "piece length": 0, "pieces": strings.Repeat("x", 20)}},
+		{"bad pieces", map[string]any{"name": "a", "length": 1, "piece length": 1, "pieces": "short"}},
+		{"piece count mismatch", map[string]any{"name": "a", "length": 2, "piece length": 1, "pieces": strings.Repeat("x", 20)}},
+		{"no files", map[string]any{"name": "a", "piece length": 1, "pieces": ""}},
+		{"path traversal", map[string]any{"name": "a", "piece length": 1, "pieces": strings.Repeat("x", 20),
+			"files": []any{map[string]any{"length": 1, "path": []any{"..", "x"}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Encode(map[string]any{"info": tt.info})
+			if err != nil {
+				t.Fatalf("Encode error: %v", err)
+			}
+			if _, err := Parse(data); err == nil {
+				t.Error("Parse expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package torrentfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileResult is the verification outcome for a single torrent file.
+type FileResult struct {
+	Path         string
+	ExpectedSize int64
+	ActualSize   int64
+	Missing      bool
+	BadPieces    int // Pieces overlapping this file whose hash did not match
+}
+
+// OK reports whether the file is present with the expected size and content.
+func (r FileResult) OK() bool {
+	return !r.Missing && r.ActualSize == r.ExpectedSize && r.BadPieces == 0
+}
+
+// Problem describes why the file failed verification, or "" if it is OK.
+func (r FileResult) Problem() string {
+	switch {
+	case r.Missing:
+		return "missing"
+	case r.ActualSize != r.ExpectedSize:
+		return fmt.Sprintf("size %d, expected %d", r.ActualSize, r.ExpectedSize)
+	case r.BadPieces > 0:
+		return fmt.Sprintf("%d piece(s) failed hash check", r.BadPieces)
+	default:
+		return ""
+	}
+}
+
+// String describes the file's status.
+func (r FileResult) String() string {
+	if problem := r.Problem(); problem != "" {
+		return r.Path + ": " + problem
+	}
+	return r.Path + ": ok"
+}
+
+// Verify checks the content under dir against the torrent's file sizes and piece hashes.
+// For multi-file torrents dir is the torrent's root directory; for single-file torrents
+// it is the directory containing the file. Missing or truncated files are read as zeros
+// so the remaining files can still be checked.
+func Verify(dir string, m *MetaInfo) ([]FileResult, error) {
+	results := make([]FileResult, len(m.Files))
+	for i, f := range m.Files {
+		results[i] = FileResult{Path: f.Path, ExpectedSize: f.Length}
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(f.Path)))
+		if errors.Is(err, fs.ErrNotExist) {
+			results[i].Missing = true
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", f.Path, err)
+		}
+		results[i].ActualSize = info.Size()
+	}
+
+	// Stream all files as one contiguous byte sequence, hashing pieces in parallel
+	reader := &contentReader{dir: dir, files: m.Files, results: results}
+	hashes, err := HashPieces(reader, m.PieceLength, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	total := m.TotalLength()
+	fileIndex := 0
+	for i, want := range m.Pieces {
+		if i < len(hashes) && hashes[i] == want {
+			continue
+		}
+		// Attribute the failure to every file overlapping the piece
+		offset := int64(i) * m.PieceLength
+		end := min(offset+m.PieceLength, total)
+		for fileIndex < len(m.Files) && fileEnd(m.Files, fileIndex) <= offset {
+			fileIndex++
+		}
+		for j := fileIndex; j < len(m.Files) && fileStart(m.Files, j) < end; j++ {
+			if m.Files[j].Length > 0 {
+				results[j].BadPieces++
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// Extra lists the files under dir that the torrent does not contain, as
+// slash-separated paths relative to dir, for multi-file torrents whose root
+// directory dir is. Single-file torrents have none.
+func Extra(dir string, m *MetaInfo) ([]string, error) {
+	if m.IsSingleFile() {
+		return nil, nil
+	}
+	listed := make(map[string]bool, len(m.Files))
+	for _, f := range m.Files {
+		listed[f.Path] = true
+	}
+	var extra []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel = filepath.ToSlash(rel); !listed[rel] {
+			extra = append(extra, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	return extra, nil
+}
+
+func fileStart(files []FileEntry, i int) int64 {
+	var start int64
+	for _, f := range files[:i] {
+		start += f.Length
+	}
+	return start
+}
+
+func fileEnd(files []FileEntry, i int) int64 {
+	return fileStart(files, i) + files[i].Length
+}
+
+// contentReader concatenates torrent files in order, substituting zeros for
+// missing content and truncating files that are longer than expected.
+type contentReader struct {
+	dir     string
+	files   []FileEntry
+	results []FileResult
+	index   int
+	current io.Reader
+	closer  io.Closer
+}
+
+func (r *contentReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if r.index >= len(r.files) {
+				return 0, io.EOF
+			}
+			if err := r.open(); err != nil {
+				return 0, err
+			}
+		}
+		n, err := r.current.Read(p)
+		if errors.Is(err, io.EOF) {
+			if r.closer != nil {
+				r.closer.Close()
+			}
+			r.current, r.closer = nil, nil
+			r.index++
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *contentReader) open() error {
+	f := r.files[r.index]
+	zeros := io.LimitReader(zeroReader{}, f.Length)
+	if r.results[r.index].Missing {
+		r.current = zeros
+		return nil
+	}
+	file, err := os.Open(filepath.Join(r.dir, filepath.FromSlash(f.Path)))
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.Path, err)
+	}
+	r.closer = file
+	// Pad short files with zeros and cut long ones at the expected length
+	r.current = io.LimitReader(io.MultiReader(file, zeros), f.Length)
+	return nil
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
@@ -0,0 +1,74 @@
+package torrentfile
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// BenchmarkVerify measures piece hashing over a synthetic 500-track album
+// spread across five disc folders.
+func BenchmarkVerify(b *testing.B) {
+	const tracks, size = 500, 64 << 10
+	dir := b.TempDir()
+	rng := rand.New(rand.NewSource(1))
+	var files []FileEntry
+	var contents []string
+	for i := range tracks {
+		name := fmt.Sprintf("CD%d/%02d - Track.flac", i/100+1, i%100+1)
+		data := make([]byte, size)
+		rng.Read(data)
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			b.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			b.Fatal(err)
+		}
+		files = append(files, FileEntry{Path: name, Length: size})
+		contents = append(contents, string(data))
+	}
+	m, err := Parse(buildTorrent(b, "Album", 1<<18, files, contents))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(m.TotalLength())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		results, err := Verify(dir, m)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, r := range results {
+			if !r.OK() {
+				b.Fatalf("%s: %s", r.Path, r.Problem())
+			}
+		}
+	}
+}
+
+// BenchmarkHashPieces compares hashing 64 MB in 256 KB pieces on one worker
+// and on GOMAXPROCS workers.
+func BenchmarkHashPieces(b *testing.B) {
+	data := make([]byte, 64<<20)
+	rand.New(rand.NewSource(1)).Read(data)
+	for _, bench := range []struct {
+		name    string
+		workers int
+	}{{"sequential", 1}, {"parallel", 0}} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for range b.N {
+				if _, err := HashPieces(bytes.NewReader(data), 1<<18, bench.workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
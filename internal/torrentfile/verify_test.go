@@ -0,0 +1,118 @@
+package torrentfile
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func verifyFixture(t *testing.T) *MetaInfo {
+	t.Helper()
+	files := []FileEntry{
+		{Path: "01 - One.flac", Length: 6},
+		{Path: "CD2/01 - Two.flac", Length: 6},
+		{Path: "folder.jpg", Length: 4},
+	}
+	m, err := Parse(buildTorrent(t, "Album", 4, files, []string{"aaaaaa", "bbbbbb", "cccc"}))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	return m
+}
+
+func TestVerify_AllOK(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"01 - One.flac":     "aaaaaa",
+		"CD2/01 - Two.flac": "bbbbbb",
+		"folder.jpg":        "cccc",
+	})
+
+	results, err := Verify(dir, verifyFixture(t))
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	for _, r := range results {
+		if !r.OK() {
+			t.Errorf("%s", r)
+		}
+	}
+}
+
+func TestVerify_ReportsMismatchesPerFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"01 - One.flac": "aaaaaa",
+		// CD2/01 - Two.flac is missing
+		"folder.jpg": "ccccc", // one byte too long
+	})
+
+	results, err := Verify(dir, verifyFixture(t))
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+
+	// Piece 1 spans "aa"+"bb", so the first file is flagged alongside the missing one
+	if results[0].BadPieces != 1 || results[0].Missing {
+		t.Errorf("first file: %+v", results[0])
+	}
+	if !results[1].Missing {
+		t.Errorf("second file should be missing: %+v", results[1])
+	}
+	if results[2].ActualSize != 5 || results[2].OK() {
+		t.Errorf("third file should have a size mismatch: %+v", results[2])
+	}
+}
+
+func TestVerify_CorruptContent(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"01 - One.flac":     "aaaaaa",
+		"CD2/01 - Two.flac": "bbbbbb",
+		"folder.jpg":        "cccX",
+	})
+
+	results, err := Verify(dir, verifyFixture(t))
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	if !results[0].OK() || !results[1].OK() {
+		t.Errorf("audio files should verify: %v, %v", results[0], results[1])
+	}
+	if results[2].BadPieces != 1 {
+		t.Errorf("folder.jpg BadPieces = %d, want 1", results[2].BadPieces)
+	}
+}
+
+func TestExtra(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"01 - One.flac":     "aaaaaa",
+		"CD2/01 - Two.flac": "bbbbbb",
+		"CD2/01 - Two.log":  "log",
+		"folder.jpg":        "cccc",
+		"notes.txt":         "notes",
+	})
+
+	extra, err := Extra(dir, verifyFixture(t))
+	if err != nil {
+		t.Fatalf("Extra error: %v", err)
+	}
+	if !slices.Equal(extra, []string{"CD2/01 - Two.log", "notes.txt"}) {
+		t.Errorf("Extra = %q, want the log and notes", extra)
+	}
+}
@@ -0,0 +1,73 @@
+package uploader
+
+import (
+	"slices"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// capArtists splits artists, in conventional order, into those credited on the
+// upload form and those omitted from it, crediting at most limit (0: all).
+// Composers, conductors, ensembles, album artists and artists the group already
+// credits are always kept; the remaining places go to the other artists on the
+// most tracks. Large choral and opera releases credit dozens of soloists, more
+// than the upload form takes; the omitted still appear in the description's
+// performer list.
+func capArtists(artists []domain.Artist, local *domain.Torrent, credited []domain.Artist, limit int) (kept, omitted []domain.Artist) {
+	if limit <= 0 || len(artists) <= limit {
+		return artists, nil
+	}
+
+	principal := func(a domain.Artist) bool {
+		switch a.Role {
+		case domain.RoleComposer, domain.RoleConductor, domain.RoleEnsemble:
+			return true
+		}
+		return slices.ContainsFunc(local.AlbumArtist, func(b domain.Artist) bool { return b.Name == a.Name }) ||
+			slices.ContainsFunc(credited, func(b domain.Artist) bool { return b.Name == a.Name })
+	}
+
+	keep := make([]bool, len(artists))
+	var minor []int
+	places := limit
+	for i, a := range artists {
+		if principal(a) {
+			keep[i] = true
+			places--
+		} else {
+			minor = append(minor, i)
+		}
+	}
+
+	tracks := trackCounts(local)
+	slices.SortStableFunc(minor, func(i, j int) int {
+		return tracks[artists[j].Name] - tracks[artists[i].Name]
+	})
+	for _, i := range minor[:max(min(places, len(minor)), 0)] {
+		keep[i] = true
+	}
+
+	for i, a := range artists {
+		if keep[i] {
+			kept = append(kept, a)
+		} else {
+			omitted = append(omitted, a)
+		}
+	}
+	return kept, omitted
+}
+
+// trackCounts returns how many of the torrent's tracks credit each artist.
+func trackCounts(t *domain.Torrent) map[string]int {
+	counts := make(map[string]int)
+	for _, track := range t.Tracks() {
+		seen := make(map[string]bool)
+		for _, a := range track.Artists {
+			if !seen[a.Name] {
+				seen[a.Name] = true
+				counts[a.Name]++
+			}
+		}
+	}
+	return counts
+}
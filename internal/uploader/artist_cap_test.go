@@ -0,0 +1,74 @@
+package uploader
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// TestArtistCreditCap checks that an opera with more soloists than MaxArtists
+// credits its composer, ensembles, conductor and principal soloists, and lists
+// the rest in the description.
+func TestArtistCreditCap(t *testing.T) {
+	composer := domain.Artist{Name: "Richard Wagner", Role: domain.RoleComposer}
+	orchestra := domain.Artist{Name: "Wiener Philharmoniker", Role: domain.RoleEnsemble}
+	chorus := domain.Artist{Name: "Wiener Staatsopernchor", Role: domain.RoleEnsemble}
+	conductor := domain.Artist{Name: "Georg Solti", Role: domain.RoleConductor}
+	soloist := func(name, voice string) domain.Artist {
+		return domain.Artist{Name: name, Role: domain.RoleSoloist, Instrument: voice}
+	}
+	brunnhilde := soloist("Birgit Nilsson", "soprano")
+	siegfried := soloist("Wolfgang Windgassen", "tenor")
+	wotan := soloist("Hans Hotter", "bass-baritone")
+
+	local := &domain.Torrent{
+		Title:        "Götterdämmerung",
+		OriginalYear: 1964,
+		AlbumArtist:  []domain.Artist{brunnhilde, orchestra, conductor},
+	}
+	addTrack := func(artists ...domain.Artist) {
+		artists = append([]domain.Artist{composer, orchestra, chorus, conductor}, artists...)
+		local.Files = append(local.Files, &domain.Track{Disc: 1, Track: len(local.Files) + 1, Title: "Scene", Artists: artists})
+	}
+	for range 3 {
+		addTrack(brunnhilde, siegfried, wotan)
+	}
+	addTrack(siegfried, wotan)
+	var minor []string
+	for i := range 5 {
+		name := fmt.Sprintf("Rhinemaiden %d", i+1)
+		minor = append(minor, name)
+		addTrack(soloist(name, "mezzo-soprano"))
+	}
+
+	cmd := &UploadCommand{MaxArtists: 8}
+	merged := cmd.mergeMetadata(&Torrent{}, &TorrentGroup{Artists: []ArtistCredit{{Name: "Rhinemaiden 5"}}}, local, "")
+
+	var got []string
+	for _, a := range merged.Artists {
+		got = append(got, a.Name)
+	}
+	want := []string{"Birgit Nilsson", "Wolfgang Windgassen", "Hans Hotter", "Rhinemaiden 5",
+		"Wiener Philharmoniker", "Wiener Staatsopernchor", "Georg Solti", "Richard Wagner"}
+	if !slices.Equal(got, want) {
+		t.Errorf("artists = %q, want %q", got, want)
+	}
+
+	var omitted []string
+	for _, a := range merged.OmittedArtists {
+		omitted = append(omitted, a.Name)
+	}
+	if !slices.Equal(omitted, minor[:4]) {
+		t.Errorf("omitted = %q, want %q", omitted, minor[:4])
+	}
+	if !strings.Contains(merged.Description, "Rhinemaiden 1, Rhinemaiden 2, Rhinemaiden 3, Rhinemaiden 4, Rhinemaiden 5 (mezzo-soprano)") {
+		t.Errorf("description does not list the omitted soloists:\n%s", merged.Description)
+	}
+
+	if merged := (&UploadCommand{}).mergeMetadata(&Torrent{}, &TorrentGroup{}, local, ""); len(merged.OmittedArtists) > 0 {
+		t.Errorf("omitted %d artists without MaxArtists", len(merged.OmittedArtists))
+	}
+}
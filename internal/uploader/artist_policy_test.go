@@ -0,0 +1,134 @@
+package uploader
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// TestArtistCreditPolicy checks the artist list and performer block of typical
+// classical uploads: main artists in conventional order (soloists, ensemble,
+// conductor) and soloists grouped by instrument in the description.
+func TestArtistCreditPolicy(t *testing.T) {
+	composer := func(name string) domain.Artist { return domain.Artist{Name: name, Role: domain.RoleComposer} }
+	soloist := func(name, instrument string) domain.Artist {
+		return domain.Artist{Name: name, Role: domain.RoleSoloist, Instrument: instrument}
+	}
+	ensemble := func(name string) domain.Artist { return domain.Artist{Name: name, Role: domain.RoleEnsemble} }
+	conductor := func(name string) domain.Artist { return domain.Artist{Name: name, Role: domain.RoleConductor} }
+
+	tests := []struct {
+		Name           string
+		AlbumArtist    []domain.Artist
+		TrackArtists   [][]domain.Artist
+		WantArtists    []string
+		WantImportance []string
+		WantPerformers []string // Lines of the description's performer block; nil for no block
+	}{
+		{
+			Name:        "piano concerto",
+			AlbumArtist: []domain.Artist{conductor("Claudio Abbado"), ensemble("Berliner Philharmoniker"), soloist("Martha Argerich", "piano")},
+			TrackArtists: [][]domain.Artist{
+				{composer("Sergei Prokofiev"), conductor("Claudio Abbado"), ensemble("Berliner Philharmoniker"), soloist("Martha Argerich", "piano")},
+				{composer("Maurice Ravel"), conductor("Claudio Abbado"), ensemble("Berliner Philharmoniker"), soloist("Martha Argerich", "piano")},
+			},
+			WantArtists:    []string{"Martha Argerich", "Berliner Philharmoniker", "Claudio Abbado", "Sergei Prokofiev", "Maurice Ravel"},
+			WantImportance: []string{"1", "1", "5", "4", "4"},
+			WantPerformers: []string{"Martha Argerich (piano)", "Berliner Philharmoniker", "Claudio Abbado (conductor)"},
+		},
+		{
+			Name:        "piano duo",
+			AlbumArtist: []domain.Artist{soloist("Martha Argerich", "piano"), soloist("Nelson Freire", "piano")},
+			TrackArtists: [][]domain.Artist{
+				{composer("Sergei Rachmaninoff"), soloist("Martha Argerich", "piano"), soloist("Nelson Freire", "piano")},
+			},
+			WantArtists:    []string{"Martha Argerich", "Nelson Freire", "Sergei Rachmaninoff"},
+			WantImportance: []string{"1", "1", "4"},
+			WantPerformers: []string{"Martha Argerich, Nelson Freire (piano)"},
+		},
+		{
+			Name: "lieder recital",
+			TrackArtists: [][]domain.Artist{
+				{composer("Franz Schubert"), soloist("Alfred Brendel", "piano"), soloist("Dietrich Fischer-Dieskau", "baritone")},
+			},
+			WantArtists:    []string{"Alfred Brendel", "Dietrich Fischer-Dieskau", "Franz Schubert"},
+			WantImportance: []string{"1", "1", "4"},
+			WantPerformers: []string{"Alfred Brendel (piano)", "Dietrich Fischer-Dieskau (baritone)"},
+		},
+		{
+			Name:        "choral without instruments",
+			AlbumArtist: []domain.Artist{ensemble("RIAS Kammerchor"), conductor("Hans-Christoph Rademann")},
+			TrackArtists: [][]domain.Artist{
+				{composer("Felix Mendelssohn"), ensemble("RIAS Kammerchor"), conductor("Hans-Christoph Rademann")},
+			},
+			WantArtists:    []string{"RIAS Kammerchor", "Hans-Christoph Rademann", "Felix Mendelssohn"},
+			WantImportance: []string{"1", "5", "4"},
+		},
+		{
+			Name:        "piano concerto directed from the keyboard",
+			AlbumArtist: []domain.Artist{soloist("Murray Perahia", "piano"), ensemble("Academy of St Martin in the Fields"), conductor("Murray Perahia")},
+			TrackArtists: [][]domain.Artist{
+				{composer("Wolfgang Amadeus Mozart"), soloist("Murray Perahia", "piano"), ensemble("Academy of St Martin in the Fields"), conductor("Murray Perahia")},
+			},
+			WantArtists:    []string{"Murray Perahia", "Academy of St Martin in the Fields", "Murray Perahia", "Wolfgang Amadeus Mozart"},
+			WantImportance: []string{"1", "1", "5", "4"},
+			WantPerformers: []string{"Murray Perahia (piano & direction)", "Academy of St Martin in the Fields"},
+		},
+	}
+
+	cmd := &UploadCommand{}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			local := &domain.Torrent{Title: "Album", OriginalYear: 2000, AlbumArtist: tt.AlbumArtist}
+			for i, artists := range tt.TrackArtists {
+				local.Files = append(local.Files, &domain.Track{Disc: 1, Track: i + 1, Title: "Track", Artists: artists})
+			}
+
+			merged := cmd.mergeMetadata(&Torrent{Description: "Ripped with EAC"}, &TorrentGroup{}, local, "")
+			req := cmd.prepareUploadRequest(merged)
+			if !slices.Equal(req.Artists, tt.WantArtists) {
+				t.Errorf("artists = %q, want %q", req.Artists, tt.WantArtists)
+			}
+			if !slices.Equal(req.Importance, tt.WantImportance) {
+				t.Errorf("importance = %q, want %q", req.Importance, tt.WantImportance)
+			}
+
+			_, block, found := strings.Cut(merged.Description, "[b]Performers:[/b]\n")
+			switch {
+			case tt.WantPerformers == nil && found:
+				t.Errorf("unexpected performer block:\n%s", merged.Description)
+			case tt.WantPerformers != nil && block != strings.Join(tt.WantPerformers, "\n"):
+				t.Errorf("performer block = %q, want %q", block, tt.WantPerformers)
+			}
+		})
+	}
+}
+
+// TestMergeMetadata_Cast checks that an opera's description lists the cast,
+// character by character, after the performers.
+func TestMergeMetadata_Cast(t *testing.T) {
+	singer := func(name, voice, character string) domain.Artist {
+		return domain.Artist{Name: name, Role: domain.RoleSoloist, Instrument: voice, Character: character}
+	}
+	local := &domain.Torrent{Title: "La traviata", OriginalYear: 2005, Files: []domain.FileLike{
+		&domain.Track{Disc: 1, Track: 1, Title: "Preludio", Artists: []domain.Artist{
+			{Name: "Giuseppe Verdi", Role: domain.RoleComposer},
+			{Name: "Wiener Philharmoniker", Role: domain.RoleEnsemble},
+		}},
+		&domain.Track{Disc: 1, Track: 2, Title: "Libiamo ne' lieti calici", Artists: []domain.Artist{
+			{Name: "Giuseppe Verdi", Role: domain.RoleComposer},
+			singer("Anna Netrebko", "soprano", "Violetta"),
+			singer("Rolando Villazón", "tenor", "Alfredo"),
+			{Name: "Wiener Philharmoniker", Role: domain.RoleEnsemble},
+		}},
+	}}
+
+	merged := (&UploadCommand{}).mergeMetadata(&Torrent{}, &TorrentGroup{}, local, "")
+	want := "[b]Performers:[/b]\nAnna Netrebko (soprano)\nRolando Villazón (tenor)\nWiener Philharmoniker\n\n" +
+		"[b]Cast:[/b]\nVioletta – Anna Netrebko\nAlfredo – Rolando Villazón"
+	if merged.Description != want {
+		t.Errorf("description = %q, want %q", merged.Description, want)
+	}
+}
@@ -0,0 +1,75 @@
+package uploader
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+// findExistingArtists searches Redacted for the local artists the group does
+// not credit yet, and returns a warning for each that the site already has
+// under a spelling differing only by diacritics or transliteration
+// ("Rozhdestvensky" for "Rozhdestvenskiy"): uploading the local spelling would
+// create a duplicate artist page. Failed searches are logged and skipped.
+func (c *UploadCommand) findExistingArtists(ctx context.Context, local map[domain.Artist]struct{}, redacted []domain.Artist) []string {
+	credited := make(map[string]bool)
+	for _, a := range redacted {
+		credited[a.Name] = true
+	}
+	var artists []domain.Artist
+	for a := range local {
+		if !credited[a.Name] && !slices.ContainsFunc(artists, func(b domain.Artist) bool { return b.Name == a.Name }) {
+			artists = append(artists, a)
+		}
+	}
+	slices.SortFunc(artists, func(a, b domain.Artist) int { return strings.Compare(a.Name, b.Name) })
+
+	var warnings []string
+	for _, a := range artists {
+		term := artistSearchTerm(a)
+		if term == "" {
+			continue
+		}
+		c.log("Searching Redacted for artists named %q...", term)
+		found, err := c.Client.SearchArtists(ctx, term)
+		if err != nil {
+			c.log("Warning: artist search for %q failed: %v", term, err)
+			continue
+		}
+		if existing, ok := matchExistingArtist(a.Name, found); ok {
+			warnings = append(warnings, fmt.Sprintf("%s %q is new to this group, but Redacted has %q (%s/artist.php?id=%d); use that spelling to avoid a duplicate artist page",
+				a.Role, a.Name, existing.Name, c.Client.BaseURL, existing.ID))
+		}
+	}
+	return warnings
+}
+
+// artistSearchTerm is what to search for to find an artist's other spellings:
+// a person's last name, which transliterations of the first name do not
+// affect, or an ensemble's whole name, without diacritics.
+func artistSearchTerm(a domain.Artist) string {
+	if a.Role == domain.RoleEnsemble {
+		return normalize.Fold(a.Name)
+	}
+	return normalize.Fold(a.LastName())
+}
+
+// matchExistingArtist returns the artist in found spelling name differently
+// but sharing its transliteration key. None is returned when the exact
+// spelling exists.
+func matchExistingArtist(name string, found []ArtistCredit) (ArtistCredit, bool) {
+	if slices.ContainsFunc(found, func(a ArtistCredit) bool { return a.Name == name }) {
+		return ArtistCredit{}, false
+	}
+	key := normalize.Transliteration(name)
+	for _, a := range found {
+		if normalize.Transliteration(a.Name) == key {
+			return a, true
+		}
+	}
+	return ArtistCredit{}, false
+}
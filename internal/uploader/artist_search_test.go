@@ -0,0 +1,51 @@
+package uploader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+)
+
+func TestUploadCommand_FindExistingArtists(t *testing.T) {
+	var searches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("action") != "browse" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		searches = append(searches, r.URL.Query().Get("artistname"))
+		w.Write([]byte(`{"status": "success", "response": {"results": [
+			{"groupId": 1, "torrents": [{"artists": [{"id": 77, "name": "Gennady Rozhdestvensky"}, {"id": 78, "name": "BBC Symphony Orchestra"}]}]},
+			{"groupId": 2, "torrents": [{"artists": [{"id": 77, "name": "Gennady Rozhdestvensky"}, {"id": 79, "name": "Mstislav Rostropovich"}]}]}
+		]}}`))
+	}))
+	defer server.Close()
+
+	cmd := &UploadCommand{Client: &RedactedClient{
+		BaseURL:     server.URL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		RateLimiter: ratelimit.NewRateLimiter(10, 10*time.Second),
+	}}
+	local := map[domain.Artist]struct{}{
+		{Name: "Gennadi Rozhdestvenskiy", Role: domain.RoleConductor}: {},
+		{Name: "Mstislav Rostropovich", Role: domain.RoleSoloist}:     {},
+		{Name: "Dmitri Shostakovich", Role: domain.RoleComposer}:      {},
+		{Name: "BBC Symphony Orchestra", Role: domain.RoleEnsemble}:   {},
+	}
+	redacted := []domain.Artist{{Name: "Dmitri Shostakovich", Role: domain.RoleComposer}}
+
+	warnings := cmd.findExistingArtists(context.Background(), local, redacted)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `conductor "Gennadi Rozhdestvenskiy" is new to this group, but Redacted has "Gennady Rozhdestvensky"`) ||
+		!strings.Contains(warnings[0], server.URL+"/artist.php?id=77") {
+		t.Errorf("findExistingArtists() = %q", warnings)
+	}
+	// Artists the group credits are not searched; people are searched by last name
+	if strings.Join(searches, ",") != "BBC Symphony Orchestra,Rozhdestvenskiy,Rostropovich" {
+		t.Errorf("searched for %q", searches)
+	}
+}
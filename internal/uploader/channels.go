@@ -0,0 +1,43 @@
+package uploader
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// surroundTitlePattern recognizes edition titles that already name a multichannel mix.
+var surroundTitlePattern = regexp.MustCompile(`(?i)\b(surround|multi-?channel|quadraphonic|[4-7]\.[01])\b`)
+
+// remasterTitleWithLayout returns the edition title with layout ("5.1 Surround") appended,
+// unless the title already names a multichannel mix.
+func remasterTitleWithLayout(title, layout string) string {
+	if surroundTitlePattern.MatchString(title) {
+		return title
+	}
+	if title == "" {
+		return layout
+	}
+	return title + " / " + layout
+}
+
+// checkChannelEdition compares the files' channel layout with the edition being trumped.
+// Stereo files cannot trump a surround edition (err). Surround files in an edition whose
+// title doesn't say so only earn a warning suggesting the title the edition should have.
+func checkChannelEdition(local *domain.Torrent, meta *Metadata) (warning string, err error) {
+	layout := local.ChannelLayout()
+	if layout == "" {
+		return "", nil
+	}
+	surroundEdition := surroundTitlePattern.MatchString(meta.RemasterTitle)
+
+	switch {
+	case local.IsMultichannel() && !surroundEdition:
+		return fmt.Sprintf("files are %s but the edition title %q doesn't say so; surround mixes belong in their own edition (e.g. %q)",
+			layout, meta.RemasterTitle, remasterTitleWithLayout(meta.RemasterTitle, layout)), nil
+	case !local.IsMultichannel() && surroundEdition:
+		return "", domain.Classify(fmt.Errorf("files are %s but the edition being trumped is %q", layout, meta.RemasterTitle), domain.ErrValidation)
+	}
+	return "", nil
+}
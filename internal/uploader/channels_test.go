@@ -0,0 +1,51 @@
+package uploader
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRemasterTitleWithLayout(t *testing.T) {
+	tests := map[string]string{
+		"":                     "5.1 Surround",
+		"Hybrid SACD":          "Hybrid SACD / 5.1 Surround",
+		"Multichannel":         "Multichannel",
+		"SACD 5.1":             "SACD 5.1",
+		"Surround Sound Remix": "Surround Sound Remix",
+	}
+	for title, want := range tests {
+		if got := remasterTitleWithLayout(title, "5.1 Surround"); got != want {
+			t.Errorf("remasterTitleWithLayout(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestCheckChannelEdition(t *testing.T) {
+	surround := &domain.Torrent{Files: []domain.FileLike{&domain.Track{Track: 1, Channels: 6}}}
+	stereo := &domain.Torrent{Files: []domain.FileLike{&domain.Track{Track: 1, Channels: 2}}}
+	unknown := &domain.Torrent{Files: []domain.FileLike{&domain.Track{Track: 1}}}
+
+	tests := []struct {
+		Name        string
+		Local       *domain.Torrent
+		Title       string
+		WantWarning bool
+		WantErr     bool
+	}{
+		{Name: "stereo in stereo edition", Local: stereo, Title: "Remastered"},
+		{Name: "surround in surround edition", Local: surround, Title: "Hybrid SACD / 5.1 Surround"},
+		{Name: "surround in unlabelled edition", Local: surround, Title: "Hybrid SACD", WantWarning: true},
+		{Name: "stereo in surround edition", Local: stereo, Title: "Multichannel", WantErr: true},
+		{Name: "unknown layout", Local: unknown, Title: "Multichannel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			warning, err := checkChannelEdition(tt.Local, &Metadata{RemasterTitle: tt.Title})
+			if (warning != "") != tt.WantWarning || (err != nil) != tt.WantErr {
+				t.Errorf("checkChannelEdition() = %q, %v", warning, err)
+			}
+		})
+	}
+}
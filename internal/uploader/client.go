@@ -5,19 +5,72 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
-	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cehbz/classical-tagger/internal/cache"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
 	"github.com/cehbz/classical-tagger/internal/ratelimit"
 )
 
+// ErrUnauthorized is returned when Redacted rejects the API key.
+var ErrUnauthorized = errors.New("API key rejected")
+
+// actionScopes are the API key scopes the endpoints this client calls need,
+// by action, with what the endpoint is used for; the others (index, browse)
+// need none. The probes of CheckScope stand for everything needing the scope.
+var actionScopes = map[string]struct{ scope, use string }{
+	"torrent":      {"Torrent", "fetching torrent details"},
+	"torrentgroup": {"Torrent", "fetching torrent groups"},
+	"download":     {"Torrent", "downloading .torrent files"},
+	"upload":       {"Torrent", "uploading"},
+	"collage":      {"Torrent", "fetching collages"},
+	"addtocollage": {"Torrent", "adding to collages"},
+	"top10":        {"Torrent", "fetching torrents and uploading"},
+	"request":      {"Request", "fetching requests"},
+	"requests":     {"Request", "filling requests"},
+}
+
+// statusError returns the error for a response to action other than 200 OK:
+// a missing scope for 403 Forbidden on an action that needs one, otherwise
+// an API error with the status and body.
+func statusError(action string, resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusForbidden {
+		var apiResp struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &apiResp) != nil {
+			apiResp.Error = strings.TrimSpace(string(body))
+		}
+		if needs, ok := actionScopes[action]; ok {
+			return &domain.ErrMissingScope{Service: "Redacted", Scope: needs.scope, Action: needs.use, Message: apiResp.Error}
+		}
+		return domain.Classify(fmt.Errorf("%w: %s", ErrUnauthorized, apiResp.Error), domain.ErrAPI)
+	}
+	return fmt.Errorf("%w %d: %s", domain.ErrAPI, resp.StatusCode, string(body))
+}
+
+// failureError returns the error for a "failure" status from action: a
+// missing scope when the message says the key is not permitted, otherwise
+// an API error with the message.
+func failureError(action, message string) error {
+	lower := strings.ToLower(message)
+	if needs, ok := actionScopes[action]; ok && (strings.Contains(lower, "scope") || strings.Contains(lower, "permission")) {
+		return &domain.ErrMissingScope{Service: "Redacted", Scope: needs.scope, Action: needs.use, Message: message}
+	}
+	return fmt.Errorf("%w: %s", domain.ErrAPI, message)
+}
+
 // RedactedClient handles API communication with Redacted
 type RedactedClient struct {
 	BaseURL     string
@@ -25,6 +78,7 @@ type RedactedClient struct {
 	HTTPClient  *http.Client
 	RateLimiter *ratelimit.RateLimiter // Reuse the existing rate limiter
 	Cache       *cache.Cache
+	FS          fsys.FS // Where torrent files are read from (nil: the operating system)
 }
 
 // NewRedactedClient creates a new Redacted API client
@@ -83,14 +137,14 @@ func (c *RedactedClient) GetTorrent(ctx context.Context, torrentID int) (*Torren
 
 	// Handle rate limiting
 	if resp.StatusCode == http.StatusTooManyRequests {
-		retryAfter := resp.Header.Get("Retry-After")
-		return nil, fmt.Errorf("rate limited, retry after %s seconds", retryAfter)
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return nil, &ratelimit.ErrRateLimited{Service: "Redacted", RetryAfter: retryAfter}
 	}
 
 	// Handle errors
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, statusError("torrent", resp)
 	}
 
 	// Parse response
@@ -109,6 +163,8 @@ func (c *RedactedClient) GetTorrent(ctx context.Context, torrentID int) (*Torren
 				Format                  string `json:"format"`
 				Encoding                string `json:"encoding"`
 				Media                   string `json:"media"`
+				HasLog                  bool   `json:"hasLog"`
+				HasCue                  bool   `json:"hasCue"`
 				Remastered              bool   `json:"remastered"`
 				RemasterYear            int    `json:"remasterYear"`
 				RemasterTitle           string `json:"remasterTitle"`
@@ -116,6 +172,7 @@ func (c *RedactedClient) GetTorrent(ctx context.Context, torrentID int) (*Torren
 				RemasterCatalogueNumber string `json:"remasterCatalogueNumber"`
 				Description             string `json:"description"`
 				FileList                string `json:"fileList"`
+				FilePath                string `json:"filePath"`
 				Size                    int64  `json:"size"`
 			} `json:"torrent"`
 		} `json:"response"`
@@ -126,7 +183,7 @@ func (c *RedactedClient) GetTorrent(ctx context.Context, torrentID int) (*Torren
 	}
 
 	if apiResp.Status != "success" {
-		return nil, fmt.Errorf("API error: %s", apiResp.Error)
+		return nil, failureError("torrent", apiResp.Error)
 	}
 
 	// Convert to our domain model
@@ -139,6 +196,8 @@ func (c *RedactedClient) GetTorrent(ctx context.Context, torrentID int) (*Torren
 		Format:                  apiResp.Response.Torrent.Format,
 		Encoding:                apiResp.Response.Torrent.Encoding,
 		Media:                   apiResp.Response.Torrent.Media,
+		HasLog:                  apiResp.Response.Torrent.HasLog,
+		HasCue:                  apiResp.Response.Torrent.HasCue,
 		Remastered:              apiResp.Response.Torrent.Remastered,
 		RemasterYear:            apiResp.Response.Torrent.RemasterYear,
 		RemasterTitle:           apiResp.Response.Torrent.RemasterTitle,
@@ -146,6 +205,7 @@ func (c *RedactedClient) GetTorrent(ctx context.Context, torrentID int) (*Torren
 		RemasterCatalogueNumber: apiResp.Response.Torrent.RemasterCatalogueNumber,
 		Description:             apiResp.Response.Torrent.Description,
 		FileList:                apiResp.Response.Torrent.FileList,
+		FilePath:                apiResp.Response.Torrent.FilePath,
 		Size:                    apiResp.Response.Torrent.Size,
 	}
 
@@ -200,14 +260,14 @@ func (c *RedactedClient) GetTorrentGroup(ctx context.Context, groupID int) (*Tor
 
 	// Handle rate limiting
 	if resp.StatusCode == http.StatusTooManyRequests {
-		retryAfter := resp.Header.Get("Retry-After")
-		return nil, fmt.Errorf("rate limited, retry after %s seconds", retryAfter)
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return nil, &ratelimit.ErrRateLimited{Service: "Redacted", RetryAfter: retryAfter}
 	}
 
 	// Handle errors
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, statusError("torrentgroup", resp)
 	}
 
 	// Parse response
@@ -241,7 +301,7 @@ func (c *RedactedClient) GetTorrentGroup(ctx context.Context, groupID int) (*Tor
 	}
 
 	if apiResp.Status != "success" {
-		return nil, fmt.Errorf("API error: %s", apiResp.Error)
+		return nil, failureError("torrentgroup", apiResp.Error)
 	}
 
 	// Convert to our domain model
@@ -267,6 +327,448 @@ func (c *RedactedClient) GetTorrentGroup(ctx context.Context, groupID int) (*Tor
 	return metadata, nil
 }
 
+// GetRequest fetches a request's requirements from Redacted.
+// Requests are not cached since they can be filled at any time.
+func (c *RedactedClient) GetRequest(ctx context.Context, requestID int) (*Request, error) {
+	// Apply rate limiting
+	if err := c.RateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	// Build URL
+	u, err := url.Parse(c.BaseURL + "/ajax.php")
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("action", "request")
+	q.Set("id", strconv.Itoa(requestID))
+	u.RawQuery = q.Encode()
+
+	// Create request
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add API key header
+	req.Header.Set("Authorization", c.APIKey)
+	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
+
+	// Execute request
+	resp, err := c.HTTPClient.Do(req)
+	c.RateLimiter.OnResponse()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Handle rate limiting
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return nil, &ratelimit.ErrRateLimited{Service: "Redacted", RetryAfter: retryAfter}
+	}
+
+	// Handle errors
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("request", resp)
+	}
+
+	// Parse response
+	var apiResp struct {
+		Status   string  `json:"status"`
+		Error    string  `json:"error,omitempty"`
+		Response Request `json:"response"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if apiResp.Status != "success" {
+		return nil, failureError("request", apiResp.Error)
+	}
+
+	return &apiResp.Response, nil
+}
+
+// SearchArtists returns the artists credited on torrents found by an artist
+// name search (browse with artistname), each once.
+func (c *RedactedClient) SearchArtists(ctx context.Context, name string) ([]ArtistCredit, error) {
+	cacheKey := "artist_search_" + name
+	var cached []ArtistCredit
+	if c.Cache.LoadFrom(cacheKey, &cached, "redacted") {
+		return cached, nil
+	}
+
+	// Apply rate limiting
+	if err := c.RateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	// Build URL
+	u, err := url.Parse(c.BaseURL + "/ajax.php")
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("action", "browse")
+	q.Set("artistname", name)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.APIKey)
+	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
+
+	resp, err := c.HTTPClient.Do(req)
+	c.RateLimiter.OnResponse()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return nil, &ratelimit.ErrRateLimited{Service: "Redacted", RetryAfter: retryAfter}
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w %d: %s", domain.ErrAPI, resp.StatusCode, string(body))
+	}
+
+	var apiResp struct {
+		Status   string `json:"status"`
+		Error    string `json:"error,omitempty"`
+		Response struct {
+			Results []struct {
+				Torrents []struct {
+					Artists []ArtistCredit `json:"artists"`
+				} `json:"torrents"`
+			} `json:"results"`
+		} `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if apiResp.Status != "success" {
+		return nil, fmt.Errorf("%w: %s", domain.ErrAPI, apiResp.Error)
+	}
+
+	artists := []ArtistCredit{}
+	seen := make(map[int]bool)
+	for _, group := range apiResp.Response.Results {
+		for _, torrent := range group.Torrents {
+			for _, a := range torrent.Artists {
+				if !seen[a.ID] {
+					seen[a.ID] = true
+					artists = append(artists, ArtistCredit{ID: a.ID, Name: a.Name})
+				}
+			}
+		}
+	}
+	c.Cache.SaveTo(cacheKey, artists, "redacted")
+	return artists, nil
+}
+
+// Identity returns the name of the user the API key belongs to, checking the
+// key with a read-only request. A rejected key returns ErrUnauthorized.
+func (c *RedactedClient) Identity(ctx context.Context) (string, error) {
+	if err := c.RateLimiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/ajax.php?action=index", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", c.APIKey)
+	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
+
+	resp, err := c.HTTPClient.Do(req)
+	c.RateLimiter.OnResponse()
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", ErrUnauthorized
+	case http.StatusTooManyRequests:
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return "", &ratelimit.ErrRateLimited{Service: "Redacted", RetryAfter: retryAfter}
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("%w %d: %s", domain.ErrAPI, resp.StatusCode, string(body))
+	}
+
+	var apiResp struct {
+		Status   string `json:"status"`
+		Error    string `json:"error,omitempty"`
+		Response struct {
+			Username string `json:"username"`
+		} `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if apiResp.Status != "success" {
+		if strings.Contains(strings.ToLower(apiResp.Error), "credentials") {
+			return "", domain.Classify(fmt.Errorf("%w: %s", ErrUnauthorized, apiResp.Error), domain.ErrAPI)
+		}
+		return "", fmt.Errorf("%w: %s", domain.ErrAPI, apiResp.Error)
+	}
+	return apiResp.Response.Username, nil
+}
+
+// scopeProbes are cheap read-only requests that need each scope.
+var scopeProbes = map[string]url.Values{
+	"Torrent": {"action": {"top10"}, "type": {"torrents"}, "limit": {"10"}},
+	"Request": {"action": {"requests"}, "search": {""}},
+}
+
+// CheckScope checks that the API key was created with scope ("Torrent" or
+// "Request") by making a read-only request that needs it, so a command can
+// refuse up front rather than fail halfway. A missing scope returns
+// *domain.ErrMissingScope.
+func (c *RedactedClient) CheckScope(ctx context.Context, scope string) error {
+	probe, ok := scopeProbes[scope]
+	if !ok {
+		return fmt.Errorf("unknown API key scope %q", scope)
+	}
+	if err := c.RateLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	action := probe.Get("action")
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/ajax.php?"+probe.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.APIKey)
+	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
+
+	resp, err := c.HTTPClient.Do(req)
+	c.RateLimiter.OnResponse()
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return &ratelimit.ErrRateLimited{Service: "Redacted", RetryAfter: retryAfter}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return statusError(action, resp)
+	}
+	var apiResp struct {
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if apiResp.Status != "success" {
+		return failureError(action, apiResp.Error)
+	}
+	return nil
+}
+
+// GetCollage fetches a collage's name and torrent groups from Redacted.
+// Collages are not cached since groups are added to them at any time.
+func (c *RedactedClient) GetCollage(ctx context.Context, collageID int) (*Collage, error) {
+	// Apply rate limiting
+	if err := c.RateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	// Build URL
+	u, err := url.Parse(c.BaseURL + "/ajax.php")
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("action", "collage")
+	q.Set("id", strconv.Itoa(collageID))
+	q.Set("showonlygroups", "1")
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.APIKey)
+	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
+
+	resp, err := c.HTTPClient.Do(req)
+	c.RateLimiter.OnResponse()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return nil, &ratelimit.ErrRateLimited{Service: "Redacted", RetryAfter: retryAfter}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError("collage", resp)
+	}
+
+	var apiResp struct {
+		Status   string  `json:"status"`
+		Error    string  `json:"error,omitempty"`
+		Response Collage `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if apiResp.Status != "success" {
+		return nil, failureError("collage", apiResp.Error)
+	}
+
+	return &apiResp.Response, nil
+}
+
+// AddToCollage adds a torrent group to a collage. It reports whether the group
+// was added, and is not an error when the collage already holds the group.
+func (c *RedactedClient) AddToCollage(ctx context.Context, collageID, groupID int) (bool, error) {
+	// Apply rate limiting
+	if err := c.RateLimiter.Wait(ctx); err != nil {
+		return false, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	u, err := url.Parse(c.BaseURL + "/ajax.php")
+	if err != nil {
+		return false, err
+	}
+	q := u.Query()
+	q.Set("action", "addtocollage")
+	q.Set("collageid", strconv.Itoa(collageID))
+	u.RawQuery = q.Encode()
+
+	form := url.Values{"groupids": {strconv.Itoa(groupID)}}
+	req, err := http.NewRequestWithContext(ctx, "POST", u.String(), bytes.NewBufferString(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", c.APIKey)
+	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
+
+	resp, err := c.HTTPClient.Do(req)
+	c.RateLimiter.OnResponse()
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return false, &ratelimit.ErrRateLimited{Service: "Redacted", RetryAfter: retryAfter}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, statusError("addtocollage", resp)
+	}
+
+	var apiResp struct {
+		Status   string `json:"status"`
+		Error    string `json:"error,omitempty"`
+		Response struct {
+			Added      []int `json:"groupsadded"`
+			Rejected   []int `json:"groupsrejected"`
+			Duplicated []int `json:"groupsduplicated"`
+		} `json:"response"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if apiResp.Status != "success" {
+		return false, failureError("addtocollage", apiResp.Error)
+	}
+	if slices.Contains(apiResp.Response.Rejected, groupID) {
+		return false, fmt.Errorf("collage %d rejected group %d", collageID, groupID)
+	}
+
+	return slices.Contains(apiResp.Response.Added, groupID), nil
+}
+
+// DownloadTorrent downloads the .torrent file of a torrent, as the site
+// serves it to the API key's user. Torrent files carry the user's passkey, so
+// they are not cached. A rejected key returns ErrUnauthorized.
+func (c *RedactedClient) DownloadTorrent(ctx context.Context, torrentID int) ([]byte, error) {
+	// Apply rate limiting
+	if err := c.RateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	u, err := url.Parse(c.BaseURL + "/ajax.php")
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("action", "download")
+	q.Set("id", strconv.Itoa(torrentID))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.APIKey)
+	req.Header.Set("User-Agent", "ClassicalTagger/1.0")
+
+	resp, err := c.HTTPClient.Do(req)
+	c.RateLimiter.OnResponse()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, domain.Classify(ErrUnauthorized, domain.ErrAPI)
+	case http.StatusForbidden:
+		return nil, statusError("download", resp)
+	case http.StatusTooManyRequests:
+		retryAfter := ratelimit.ParseRetryAfter(resp.Header.Get("Retry-After"))
+		c.RateLimiter.OnRateLimited(retryAfter)
+		return nil, &ratelimit.ErrRateLimited{Service: "Redacted", RetryAfter: retryAfter}
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("%w %d: %s", domain.ErrAPI, resp.StatusCode, string(body))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read torrent file: %w", err)
+	}
+	// Failures come back as JSON with status 200; a .torrent is a bencoded dictionary
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var apiResp struct {
+			Status string `json:"status"`
+			Error  string `json:"error,omitempty"`
+		}
+		if err := json.Unmarshal(data, &apiResp); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil, failureError("download", apiResp.Error)
+	}
+	return data, nil
+}
+
 // Upload uploads a new torrent to Redacted
 func (c *RedactedClient) Upload(ctx context.Context, upload *Upload, torrentFilePath string) error {
 	// Do not cache upload requests
@@ -277,7 +779,7 @@ func (c *RedactedClient) Upload(ctx context.Context, upload *Upload, torrentFile
 	}
 
 	// Read torrent file
-	torrentData, err := os.ReadFile(torrentFilePath)
+	torrentData, err := fsys.Or(c.FS).ReadFile(torrentFilePath)
 	if err != nil {
 		return fmt.Errorf("failed to read torrent file: %w", err)
 	}
@@ -339,6 +841,11 @@ func (c *RedactedClient) Upload(ctx context.Context, upload *Upload, torrentFile
 		fields["trump_reason"] = upload.TrumpReason
 	}
 
+	// Fill the request in the same step
+	if upload.RequestID > 0 {
+		fields["requestid"] = strconv.Itoa(upload.RequestID)
+	}
+
 	// Write all fields
 	for key, val := range fields {
 		if err := w.WriteField(key, val); err != nil {
@@ -387,9 +894,12 @@ func (c *RedactedClient) Upload(ctx context.Context, upload *Upload, torrentFile
 	defer resp.Body.Close()
 
 	// Check response
+	if resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("upload failed: %w", statusError("upload", resp))
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("upload failed with %w %d: %s", domain.ErrAPI, resp.StatusCode, string(body))
 	}
 
 	return nil
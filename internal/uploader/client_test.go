@@ -0,0 +1,88 @@
+package uploader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+)
+
+func TestRedactedClient_DownloadTorrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("action") != "download" {
+			t.Errorf("action = %q, want download", r.URL.Query().Get("action"))
+		}
+		switch r.URL.Query().Get("id") {
+		case "1":
+			w.Header().Set("Content-Type", "application/x-bittorrent")
+			w.Write([]byte("d8:announce3:url4:infod4:name5:Albumee"))
+		case "2":
+			w.Write([]byte(`{"status": "failure", "error": "bad id parameter"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	client := &RedactedClient{
+		BaseURL:     server.URL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		RateLimiter: ratelimit.NewRateLimiter(10, 10*time.Second),
+	}
+	data, err := client.DownloadTorrent(context.Background(), 1)
+	if err != nil || string(data) != "d8:announce3:url4:infod4:name5:Albumee" {
+		t.Errorf("DownloadTorrent(1) = %q, %v; want the .torrent", data, err)
+	}
+	if _, err := client.DownloadTorrent(context.Background(), 2); !errors.Is(err, domain.ErrAPI) || err.Error() != "API error: bad id parameter" {
+		t.Errorf("DownloadTorrent(2) error = %v, want the API's error", err)
+	}
+	if _, err := client.DownloadTorrent(context.Background(), 3); !errors.Is(err, ErrUnauthorized) || !errors.Is(err, domain.ErrAPI) {
+		t.Errorf("DownloadTorrent(3) error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestRedactedClient_MissingScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("action") {
+		case "torrent", "top10":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"status": "failure", "error": "This API key does not have access to this endpoint"}`))
+		case "request":
+			w.Write([]byte(`{"status": "failure", "error": "Insufficient API key permissions"}`))
+		case "requests":
+			w.Write([]byte(`{"status": "success", "response": {"results": []}}`))
+		default:
+			w.Write([]byte(`{"status": "failure", "error": "bad id parameter"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &RedactedClient{
+		BaseURL:     server.URL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		RateLimiter: ratelimit.NewRateLimiter(10, 10*time.Second),
+	}
+	var missing *domain.ErrMissingScope
+	_, err := client.GetTorrent(context.Background(), 1)
+	if !errors.As(err, &missing) || missing.Scope != "Torrent" || !errors.Is(err, domain.ErrAPI) {
+		t.Errorf("GetTorrent() error = %v, want a missing Torrent scope", err)
+	}
+	if _, err := client.GetRequest(context.Background(), 1); !errors.As(err, &missing) || missing.Scope != "Request" {
+		t.Errorf("GetRequest() error = %v, want a missing Request scope", err)
+	}
+	if _, err := client.GetCollage(context.Background(), 1); errors.As(err, &missing) || !errors.Is(err, domain.ErrAPI) {
+		t.Errorf("GetCollage() error = %v, want a plain API error", err)
+	}
+
+	if err := client.CheckScope(context.Background(), "Torrent"); !errors.As(err, &missing) || missing.Scope != "Torrent" {
+		t.Errorf("CheckScope(Torrent) error = %v, want a missing Torrent scope", err)
+	}
+	if err := client.CheckScope(context.Background(), "Request"); err != nil {
+		t.Errorf("CheckScope(Request) error = %v, want nil", err)
+	}
+}
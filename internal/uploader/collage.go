@@ -0,0 +1,42 @@
+package uploader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/cehbz/classical-tagger/internal/console"
+)
+
+// updateCollages adds the group to each collage in Collages, reporting the ones
+// that already hold it. With DryRun the additions are only reported. Failures
+// only warn: the upload has already happened.
+func (c *UploadCommand) updateCollages(ctx context.Context, groupID int) {
+	for _, id := range c.Collages {
+		collage, err := c.Client.GetCollage(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch collage %d: %v\n", id, err)
+			continue
+		}
+		switch {
+		case slices.Contains(collage.GroupIDs, groupID):
+			console.Infof("Collage %q (%d) already contains the group\n", collage.Name, id)
+		case collage.Locked:
+			fmt.Fprintf(os.Stderr, "Warning: collage %q (%d) is locked; the group was not added\n", collage.Name, id)
+		case c.DryRun:
+			console.Infof("Would add the group to collage %q (%d)\n", collage.Name, id)
+		default:
+			added, err := c.Client.AddToCollage(ctx, id, groupID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to add the group to collage %q (%d): %v\n", collage.Name, id, err)
+				continue
+			}
+			if added {
+				console.Infof("Added the group to collage %q (%d)\n", collage.Name, id)
+			} else {
+				console.Infof("Collage %q (%d) already contains the group\n", collage.Name, id)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package uploader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+)
+
+func TestUploadCommand_UpdateCollages(t *testing.T) {
+	var added []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch q.Get("action") {
+		case "collage":
+			switch q.Get("id") {
+			case "1":
+				w.Write([]byte(`{"status": "success", "response": {"id": 1, "name": "Complete Beethoven Cycles", "torrentGroupIDList": [7, 42]}}`))
+			case "2":
+				w.Write([]byte(`{"status": "success", "response": {"id": 2, "name": "Harmonia Mundi", "torrentGroupIDList": [7]}}`))
+			case "3":
+				w.Write([]byte(`{"status": "success", "response": {"id": 3, "name": "Staff Picks", "locked": true, "torrentGroupIDList": []}}`))
+			default:
+				w.Write([]byte(`{"status": "failure", "error": "bad id parameter"}`))
+			}
+		case "addtocollage":
+			if r.Method != http.MethodPost {
+				t.Errorf("addtocollage method = %s, want POST", r.Method)
+			}
+			r.ParseForm()
+			added = append(added, q.Get("collageid")+":"+r.PostForm.Get("groupids"))
+			w.Write([]byte(`{"status": "success", "response": {"groupsadded": [42], "groupsrejected": [], "groupsduplicated": []}}`))
+		default:
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+	}))
+	defer server.Close()
+
+	cmd := &UploadCommand{
+		Client: &RedactedClient{
+			BaseURL:     server.URL,
+			HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+			RateLimiter: ratelimit.NewRateLimiter(10, 10*time.Second),
+		},
+		Collages: []int{1, 2, 3, 4},
+	}
+
+	// Only collage 2 lacks the group and accepts additions
+	cmd.updateCollages(context.Background(), 42)
+	if want := []string{"2:42"}; !slices.Equal(added, want) {
+		t.Errorf("added %v, want %v", added, want)
+	}
+
+	added = nil
+	cmd.DryRun = true
+	cmd.updateCollages(context.Background(), 42)
+	if len(added) != 0 {
+		t.Errorf("dry run added %v", added)
+	}
+}
+
+func TestRedactedClient_AddToCollageRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "success", "response": {"groupsadded": [], "groupsrejected": [42], "groupsduplicated": []}}`))
+	}))
+	defer server.Close()
+
+	client := &RedactedClient{
+		BaseURL:     server.URL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		RateLimiter: ratelimit.NewRateLimiter(10, 10*time.Second),
+	}
+	if added, err := client.AddToCollage(context.Background(), 2, 42); added || err == nil {
+		t.Errorf("AddToCollage() = %v, %v; want a rejection error", added, err)
+	}
+}
@@ -2,6 +2,7 @@
 package uploader
 
 import (
+	"html"
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
@@ -20,6 +21,8 @@ type Torrent struct {
 	Format                  string `json:"format"`
 	Encoding                string `json:"encoding"`
 	Media                   string `json:"media"`
+	HasLog                  bool   `json:"hasLog"`
+	HasCue                  bool   `json:"hasCue"`
 	Remastered              bool   `json:"remastered"`
 	RemasterYear            int    `json:"remasterYear,omitempty"`
 	RemasterTitle           string `json:"remasterTitle,omitempty"`
@@ -27,6 +30,7 @@ type Torrent struct {
 	RemasterCatalogueNumber string `json:"remasterCatalogueNumber,omitempty"`
 	Description             string `json:"description"`
 	FileList                string `json:"fileList"`
+	FilePath                string `json:"filePath"` // Torrent's root directory name
 	Size                    int64  `json:"size"`
 }
 
@@ -48,6 +52,29 @@ type TorrentGroup struct {
 	VanityHouse   bool           `json:"vanityHouse"`
 }
 
+// Request represents data from the Redacted request endpoint
+type Request struct {
+	ID              int      `json:"requestId"`
+	GroupID         int      `json:"groupId"` // 0 if the request is not tied to a group
+	Title           string   `json:"title"`
+	Year            int      `json:"year"`
+	RecordLabel     string   `json:"recordLabel"`
+	CatalogueNumber string   `json:"catalogueNumber"`
+	Formats         []string `json:"formatList"`  // "Any" or e.g. "FLAC"
+	Encodings       []string `json:"bitrateList"` // "Any" or e.g. "Lossless", "24bit Lossless"
+	Media           []string `json:"mediaList"`   // "Any" or e.g. "CD", "WEB"
+	LogCue          string   `json:"logCue"`      // e.g. "Log (100%) + Cue", "" if not required
+	IsFilled        bool     `json:"isFilled"`
+}
+
+// Collage represents data from the Redacted collage endpoint
+type Collage struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Locked   bool   `json:"locked"`
+	GroupIDs []int  `json:"torrentGroupIDList"`
+}
+
 // ArtistCredit represents an artist with their role
 type ArtistCredit struct {
 	ID   int    `json:"id"`
@@ -61,7 +88,8 @@ type Metadata struct {
 	Title string `json:"title"`
 	Year  int    `json:"year"`
 
-	Artists []domain.Artist `json:"artists"`
+	Artists        []domain.Artist `json:"artists"`
+	OmittedArtists []domain.Artist `json:"-"` // Left out of Artists by UploadCommand.MaxArtists
 
 	// Release info - from local files/Discogs
 	Label         string `json:"recordLabel,omitempty"`
@@ -80,11 +108,13 @@ type Metadata struct {
 	// Site metadata - from Redacted
 	Tags        []string `json:"tags"`
 	Description string   `json:"description"`
+	Lineage     Lineage  `json:"-"` // Parsed from the trumped torrent's description
 
 	// Upload specific
 	TrumpReason string `json:"trumpReason"`
 	GroupID     int    `json:"groupId"`
-	TorrentID   int    `json:"torrentId"` // ID being trumped
+	TorrentID   int    `json:"torrentId"`           // ID being trumped
+	RequestID   int    `json:"requestId,omitempty"` // Request filled by this upload
 }
 
 // Upload represents the final upload payload
@@ -124,6 +154,9 @@ type Upload struct {
 	VanityHouse  bool   `json:"vanity_house"`
 	TrumpTorrent int    `json:"trump_torrent,omitempty"` // ID to trump
 	TrumpReason  string `json:"trump_reason,omitempty"`
+
+	// Request filled by the upload (the bounty is claimed on success)
+	RequestID int `json:"requestid,omitempty"`
 }
 
 // ValidationError represents an error during validation
@@ -217,3 +250,26 @@ func RedactedImportance(role domain.Role) string {
 	}
 	return "1" // Default to main artist
 }
+
+// DomainArtists returns the group's musicInfo credits as domain artists, with
+// each credit's role taken from the list it appears in.
+func (g *TorrentGroup) DomainArtists() []domain.Artist {
+	var artists []domain.Artist
+	for _, list := range []struct {
+		credits []ArtistCredit
+		role    domain.Role
+	}{
+		{g.Composers, domain.RoleComposer},
+		{g.Conductors, domain.RoleConductor},
+		{g.Artists, domain.RolePerformer},
+		{g.With, domain.RoleGuest},
+		{g.Producer, domain.RoleProducer},
+		{g.DJ, domain.RoleDJ},
+		{g.RemixedBy, domain.RoleRemixer},
+	} {
+		for _, c := range list.credits {
+			artists = append(artists, domain.Artist{Name: html.UnescapeString(c.Name), Role: list.role})
+		}
+	}
+	return artists
+}
@@ -0,0 +1,73 @@
+package uploader
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// checkEdition compares the local edition (label, catalog number, year) with the
+// remaster fields of the torrent being trumped and returns the contradictions.
+// Fields missing on either side, and torrents filed as the original release,
+// which carry no edition fields to compare, are not contradictions.
+func checkEdition(local *domain.Edition, torrent *Torrent) []error {
+	if local == nil || !torrent.Remastered {
+		return nil
+	}
+	var errs []error
+	if label := html.UnescapeString(torrent.RemasterRecordLabel); local.Label != "" && label != "" && !sameLabel(local.Label, label) {
+		errs = append(errs, fmt.Errorf("label %q differs from the edition's %q", local.Label, label))
+	}
+	if catalog := html.UnescapeString(torrent.RemasterCatalogueNumber); local.CatalogNumber != "" && catalog != "" && !sameCatalogNumber(local.CatalogNumber, catalog) {
+		errs = append(errs, fmt.Errorf("catalog number %q differs from the edition's %q", local.CatalogNumber, catalog))
+	}
+	if local.Year > 0 && torrent.RemasterYear > 0 && local.Year != torrent.RemasterYear {
+		errs = append(errs, fmt.Errorf("year %d differs from the edition's %d", local.Year, torrent.RemasterYear))
+	}
+	return errs
+}
+
+// sameLabel reports whether two label names are the same label, ignoring case,
+// punctuation and a company suffix on one of them ("Deutsche Grammophon GmbH").
+func sameLabel(a, b string) bool {
+	a, b = alphanumeric(a), alphanumeric(b)
+	return a == b || strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// sameCatalogNumber reports whether two catalog numbers are the same, ignoring
+// case, spaces and punctuation ("479 1234" and "4791234").
+func sameCatalogNumber(a, b string) bool {
+	return alphanumeric(a) == alphanumeric(b)
+}
+
+// alphanumeric returns s lowercased with everything but letters and digits removed.
+func alphanumeric(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// applyLocalEdition fills the remaster fields of a new edition from the local
+// edition and channel layout instead of the torrent being trumped. Returns an
+// error when there is no local edition to describe the new one.
+func applyLocalEdition(meta *Metadata, local *domain.Torrent) error {
+	edition := local.Edition
+	if edition == nil || (edition.Label == "" && edition.CatalogNumber == "" && edition.Year == 0) {
+		return domain.Classify(fmt.Errorf("a new edition needs a local edition (label, catalog number or year) to fill the remaster fields"), domain.ErrValidation)
+	}
+	meta.Remastered = true
+	meta.RemasterYear = edition.Year
+	meta.RemasterRecordLabel = edition.Label
+	meta.RemasterCatalogueNumber = edition.CatalogNumber
+	meta.RemasterTitle = ""
+	if local.IsMultichannel() {
+		meta.RemasterTitle = local.ChannelLayout()
+	}
+	return nil
+}
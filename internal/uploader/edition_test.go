@@ -0,0 +1,99 @@
+package uploader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestCheckEdition(t *testing.T) {
+	remaster := &Torrent{
+		Remastered:              true,
+		RemasterYear:            2013,
+		RemasterRecordLabel:     "Harmonia Mundi",
+		RemasterCatalogueNumber: "HMC 902170",
+	}
+	tests := []struct {
+		name    string
+		local   *domain.Edition
+		torrent *Torrent
+		want    []string
+	}{
+		{"same edition", &domain.Edition{Label: "Harmonia Mundi", CatalogNumber: "HMC 902170", Year: 2013}, remaster, nil},
+		{"label suffix and catalog spacing", &domain.Edition{Label: "harmonia mundi s.a.", CatalogNumber: "HMC902170"}, remaster, nil},
+		{"missing local fields", &domain.Edition{Year: 2013}, remaster, nil},
+		{"no local edition", nil, remaster, nil},
+		{"original release", &domain.Edition{Label: "Decca"}, &Torrent{RemasterRecordLabel: "Harmonia Mundi"}, nil},
+		{"different release", &domain.Edition{Label: "Decca", CatalogNumber: "478 1234", Year: 2010}, remaster,
+			[]string{`label "Decca"`, `catalog number "478 1234"`, "year 2010"}},
+		{"escaped label", &domain.Edition{Label: "Brilliant Classics"}, &Torrent{Remastered: true, RemasterRecordLabel: "Deutsche Grammophon &amp; Co"},
+			[]string{`differs from the edition's "Deutsche Grammophon & Co"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := checkEdition(tt.local, tt.torrent)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("checkEdition() = %v, want %d errors", errs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i].Error(), want) {
+					t.Errorf("error %d = %q, want it to contain %q", i, errs[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyLocalEdition(t *testing.T) {
+	local := &domain.Torrent{
+		Edition: &domain.Edition{Label: "Channel Classics", CatalogNumber: "CCS SA 38817", Year: 2017},
+		Files: []domain.FileLike{
+			&domain.Track{File: domain.File{Path: "01.flac"}, Track: 1, Channels: 6},
+		},
+	}
+	meta := &Metadata{Remastered: true, RemasterYear: 1999, RemasterRecordLabel: "Philips", RemasterTitle: "Remastered"}
+	if err := applyLocalEdition(meta, local); err != nil {
+		t.Fatalf("applyLocalEdition() error = %v", err)
+	}
+	if !meta.Remastered || meta.RemasterYear != 2017 || meta.RemasterRecordLabel != "Channel Classics" ||
+		meta.RemasterCatalogueNumber != "CCS SA 38817" || meta.RemasterTitle != "5.1 Surround" {
+		t.Errorf("remaster fields = %d %q %q %q, want the local edition and layout",
+			meta.RemasterYear, meta.RemasterRecordLabel, meta.RemasterCatalogueNumber, meta.RemasterTitle)
+	}
+
+	// Stereo files need no edition title
+	local.Tracks()[0].Channels = 2
+	if err := applyLocalEdition(meta, local); err != nil || meta.RemasterTitle != "" {
+		t.Errorf("stereo RemasterTitle = %q (err %v), want empty", meta.RemasterTitle, err)
+	}
+
+	if err := applyLocalEdition(&Metadata{}, &domain.Torrent{}); err == nil {
+		t.Error("applyLocalEdition() without a local edition, want error")
+	}
+}
+
+func TestUploadCommand_MergeMetadataNewEdition(t *testing.T) {
+	torrentMeta := &Torrent{
+		TorrentID:   12345,
+		GroupID:     98765,
+		Media:       "CD",
+		Description: "Ripped with EAC\nRip date: 2015-01-01",
+	}
+	local := &domain.Torrent{
+		Title:   "Christmas Album",
+		Edition: &domain.Edition{Label: "Harmonia Mundi", Year: 2013},
+	}
+
+	cmd := &UploadCommand{NewEdition: true}
+	result := cmd.mergeMetadata(torrentMeta, &TorrentGroup{}, local, "")
+	if result.TorrentID != 0 {
+		t.Errorf("TorrentID = %d, want 0 so nothing is trumped", result.TorrentID)
+	}
+	if result.GroupID != 98765 {
+		t.Errorf("GroupID = %d, want the torrent's group", result.GroupID)
+	}
+	if result.Description != "" || result.Lineage.Found() {
+		t.Errorf("Description = %q, want none of the other torrent's lineage", result.Description)
+	}
+}
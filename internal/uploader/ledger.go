@@ -0,0 +1,89 @@
+package uploader
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/state"
+)
+
+// editionKey describes the edition and format of an upload for the ledger, e.g.
+// "2010 / Harmonia Mundi / HMC902170 / CD / Lossless". Uploads to the same group
+// with the same key are duplicates.
+func editionKey(meta *Metadata) string {
+	fields := []string{"Original release"}
+	if meta.Remastered {
+		fields = []string{strconv.Itoa(meta.RemasterYear), meta.RemasterTitle, meta.RemasterRecordLabel, meta.RemasterCatalogueNumber}
+	}
+	fields = append(fields, meta.Media, meta.Encoding)
+	var key []string
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" && f != "0" {
+			key = append(key, f)
+		}
+	}
+	return strings.Join(key, " / ")
+}
+
+// ledgerEntry is the ledger entry for uploading meta from the torrent directory.
+func (c *UploadCommand) ledgerEntry(meta *Metadata) state.Upload {
+	return state.Upload{
+		Dir:        c.TorrentDir,
+		Title:      meta.Title,
+		GroupID:    meta.GroupID,
+		TorrentID:  c.TorrentID,
+		NewEdition: c.NewEdition,
+		Edition:    editionKey(meta),
+		Uploaded:   !c.DryRun,
+		When:       time.Now(),
+	}
+}
+
+// ledgerWarnings compares an upload with the earlier ones in the ledger: a second
+// trump of the same torrent, an edition of the group already seeded from another
+// directory, or a directory uploaded before.
+func ledgerWarnings(ledger []state.Upload, upload state.Upload) []string {
+	var warnings []string
+	for _, u := range ledger {
+		when := u.Status() + " " + u.When.Format(time.DateOnly)
+		sameDir := u.Dir == upload.Dir
+		switch {
+		case !upload.NewEdition && !u.NewEdition && u.TorrentID == upload.TorrentID:
+			if !sameDir {
+				warnings = append(warnings, fmt.Sprintf("torrent %d is already trumped by %s (%s)", u.TorrentID, u.Dir, when))
+			} else if u.Uploaded {
+				warnings = append(warnings, fmt.Sprintf("this directory already trumped torrent %d (%s)", u.TorrentID, when))
+			}
+		case u.GroupID != upload.GroupID || !strings.EqualFold(u.Edition, upload.Edition):
+		case !sameDir:
+			warnings = append(warnings, fmt.Sprintf("group %d already has the %s edition from %s (%s)", u.GroupID, u.Edition, u.Dir, when))
+		case u.Uploaded:
+			warnings = append(warnings, fmt.Sprintf("this directory was already uploaded to group %d as the %s edition (%s)", u.GroupID, u.Edition, when))
+		}
+	}
+	return warnings
+}
+
+// checkLedger warns about uploading what the ledger says was already trumped
+// or seeded. An unreadable ledger only earns a warning.
+func (c *UploadCommand) checkLedger(meta *Metadata) {
+	c.log("Checking the upload ledger for duplicates...")
+	ledger, err := state.LoadUploads()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	for _, warning := range ledgerWarnings(ledger, c.ledgerEntry(meta)) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+}
+
+// recordLedger adds the upload, or the dry run preparing it, to the ledger.
+func (c *UploadCommand) recordLedger(meta *Metadata) {
+	if err := state.RecordUpload(c.ledgerEntry(meta)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record the upload: %v\n", err)
+	}
+}
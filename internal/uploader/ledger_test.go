@@ -0,0 +1,111 @@
+package uploader
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/state"
+)
+
+func TestEditionKey(t *testing.T) {
+	tests := []struct {
+		Name string
+		Meta *Metadata
+		Want string
+	}{
+		{
+			Name: "original release",
+			Meta: &Metadata{Media: "CD", Encoding: "Lossless"},
+			Want: "Original release / CD / Lossless",
+		},
+		{
+			Name: "remaster",
+			Meta: &Metadata{Remastered: true, RemasterYear: 2010, RemasterRecordLabel: "Harmonia Mundi", RemasterCatalogueNumber: "HMC902170", Media: "CD", Encoding: "Lossless"},
+			Want: "2010 / Harmonia Mundi / HMC902170 / CD / Lossless",
+		},
+		{
+			Name: "remaster without year",
+			Meta: &Metadata{Remastered: true, RemasterTitle: "Hybrid SACD", Media: "SACD", Encoding: "24bit Lossless"},
+			Want: "Hybrid SACD / SACD / 24bit Lossless",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := editionKey(tt.Meta); got != tt.Want {
+				t.Errorf("editionKey() = %q, want %q", got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestLedgerWarnings(t *testing.T) {
+	when := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	trump := state.Upload{Dir: "/music/A", GroupID: 10, TorrentID: 100, Edition: "2010 / CD / Lossless", Uploaded: true, When: when}
+	prepared := state.Upload{Dir: "/music/A", GroupID: 10, TorrentID: 100, Edition: "2010 / CD / Lossless", When: when}
+	edition := state.Upload{Dir: "/music/A", GroupID: 10, TorrentID: 100, NewEdition: true, Edition: "2010 / CD / Lossless", Uploaded: true, When: when}
+
+	tests := []struct {
+		Name   string
+		Ledger []state.Upload
+		Upload state.Upload
+		Want   []string
+	}{
+		{
+			Name:   "empty ledger",
+			Upload: trump,
+		},
+		{
+			Name:   "same torrent trumped from another directory",
+			Ledger: []state.Upload{trump},
+			Upload: state.Upload{Dir: "/music/B", GroupID: 10, TorrentID: 100, Edition: "2010 / CD / Lossless"},
+			Want:   []string{"torrent 100 is already trumped by /music/A (uploaded 2026-10-01)"},
+		},
+		{
+			Name:   "same torrent prepared from another directory",
+			Ledger: []state.Upload{prepared},
+			Upload: state.Upload{Dir: "/music/B", GroupID: 10, TorrentID: 100, Edition: "2010 / CD / Lossless"},
+			Want:   []string{"torrent 100 is already trumped by /music/A (prepared 2026-10-01)"},
+		},
+		{
+			Name:   "same directory trumped again",
+			Ledger: []state.Upload{trump},
+			Upload: prepared,
+			Want:   []string{"this directory already trumped torrent 100 (uploaded 2026-10-01)"},
+		},
+		{
+			Name:   "dry run repeated",
+			Ledger: []state.Upload{prepared},
+			Upload: prepared,
+		},
+		{
+			Name:   "edition seeded from another directory",
+			Ledger: []state.Upload{edition},
+			Upload: state.Upload{Dir: "/music/B", GroupID: 10, TorrentID: 101, NewEdition: true, Edition: "2010 / cd / Lossless"},
+			Want:   []string{"group 10 already has the 2010 / CD / Lossless edition from /music/A (uploaded 2026-10-01)"},
+		},
+		{
+			Name:   "edition uploaded again from the same directory",
+			Ledger: []state.Upload{edition},
+			Upload: state.Upload{Dir: "/music/A", GroupID: 10, TorrentID: 101, NewEdition: true, Edition: "2010 / CD / Lossless"},
+			Want:   []string{"this directory was already uploaded to group 10 as the 2010 / CD / Lossless edition (uploaded 2026-10-01)"},
+		},
+		{
+			Name:   "other edition of the group",
+			Ledger: []state.Upload{edition},
+			Upload: state.Upload{Dir: "/music/B", GroupID: 10, TorrentID: 100, NewEdition: true, Edition: "2010 / CD / 24bit Lossless"},
+		},
+		{
+			Name:   "other group",
+			Ledger: []state.Upload{trump},
+			Upload: state.Upload{Dir: "/music/B", GroupID: 20, TorrentID: 200, Edition: "2010 / CD / Lossless"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := ledgerWarnings(tt.Ledger, tt.Upload); !slices.Equal(got, tt.Want) {
+				t.Errorf("ledgerWarnings() = %q, want %q", got, tt.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package uploader
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// bbcodeTag matches BBCode markup such as [b], [/quote], [url=...]
+	bbcodeTag = regexp.MustCompile(`\[/?[a-zA-Z*]+(?:=[^\]]*)?\]`)
+	// lineageKeyword marks a description line as rip lineage rather than release notes
+	lineageKeyword = regexp.MustCompile(`(?i)\b(?:log|logs|logfile|cue|accuraterip|ctdb|ripped|rip|ripper|lineage|drive|extraction|read offset)\b`)
+	// ripDate finds dates such as "2013-03-16", "16/03/2013" or EAC's "16. March 2013"
+	ripDate = regexp.MustCompile(`\b(\d{4}-\d{2}-\d{2}|\d{1,2}/\d{1,2}/\d{4}|\d{1,2}\.? [A-Z][a-z]+ \d{4})\b`)
+)
+
+// rippers maps lowercase ripper names found in descriptions to their canonical names.
+// Longer names come first so "exact audio copy" wins over a bare "eac".
+var rippers = []struct{ pattern, name string }{
+	{"exact audio copy", "EAC"},
+	{"x lossless decoder", "XLD"},
+	{"dbpoweramp", "dBpoweramp"},
+	{"cueripper", "CUERipper"},
+	{"cuetools", "CUETools"},
+	{"rubyripper", "Rubyripper"},
+	{"whipper", "whipper"},
+	{"morituri", "morituri"},
+	{"eac", "EAC"},
+	{"xld", "XLD"},
+}
+
+// Lineage is the rip provenance found in an existing torrent's description.
+type Lineage struct {
+	Ripper      string   // Canonical ripper name ("EAC", "XLD", ...), if mentioned
+	RipDate     string   // Rip date as written in the description, if found
+	AccurateRip bool     // Description mentions AccurateRip/CTDB verification
+	Lines       []string // Description lines carrying lineage, BBCode stripped
+}
+
+// Found reports whether any lineage was found.
+func (l Lineage) Found() bool {
+	return len(l.Lines) > 0
+}
+
+// Description renders the lineage lines as a BBCode section for a new upload's description.
+func (l Lineage) Description() string {
+	if !l.Found() {
+		return ""
+	}
+	return "[b]Lineage[/b] (from the original upload)\n" + strings.Join(l.Lines, "\n")
+}
+
+// ParseLineage extracts rip lineage (ripper, rip date, log/AccurateRip mentions)
+// from a torrent description. Lines unrelated to the rip are ignored.
+func ParseLineage(description string) Lineage {
+	var l Lineage
+	for _, line := range strings.Split(description, "\n") {
+		line = strings.TrimSpace(bbcodeTag.ReplaceAllString(line, ""))
+		if line == "" {
+			continue
+		}
+
+		ripper := findRipper(line)
+		if ripper == "" && !lineageKeyword.MatchString(line) {
+			continue
+		}
+		l.Lines = append(l.Lines, line)
+
+		if l.Ripper == "" {
+			l.Ripper = ripper
+		}
+		if l.RipDate == "" {
+			if m := ripDate.FindString(line); m != "" {
+				l.RipDate = m
+			}
+		}
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "accuraterip") || strings.Contains(lower, "ctdb") {
+			l.AccurateRip = true
+		}
+	}
+	return l
+}
+
+// findRipper returns the canonical name of the first ripper mentioned in line, or "".
+func findRipper(line string) string {
+	words := titleWords(line)
+	lower := strings.ToLower(line)
+	for _, r := range rippers {
+		if strings.Contains(r.pattern, " ") {
+			if strings.Contains(lower, r.pattern) {
+				return r.name
+			}
+			continue
+		}
+		// Short names must be whole words ("eac" should not match "peace")
+		if _, ok := words[r.pattern]; ok {
+			return r.name
+		}
+	}
+	return ""
+}
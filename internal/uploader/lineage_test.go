@@ -0,0 +1,84 @@
+package uploader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestParseLineage(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Description     string
+		WantRipper      string
+		WantDate        string
+		WantAccurateRip bool
+		WantLines       int
+	}{
+		{
+			Name: "EAC with BBCode",
+			Description: "[b]Beautiful recording[/b]\n" +
+				"[quote]Exact Audio Copy V1.0 beta 3 from 29. August 2011\n" +
+				"EAC extraction logfile from 16. March 2013, 14:09[/quote]\n" +
+				"Tracks verified with AccurateRip\n" +
+				"Scans at 300dpi",
+			WantRipper:      "EAC",
+			WantDate:        "29. August 2011",
+			WantAccurateRip: true,
+			WantLines:       3,
+		},
+		{
+			Name:        "XLD rip date",
+			Description: "Ripped with XLD on 2019-05-02, log and cue included",
+			WantRipper:  "XLD",
+			WantDate:    "2019-05-02",
+			WantLines:   1,
+		},
+		{
+			Name:        "no lineage",
+			Description: "Peaceful performance, great sound",
+			WantLines:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			got := ParseLineage(tt.Description)
+			if got.Ripper != tt.WantRipper {
+				t.Errorf("Ripper = %q, want %q", got.Ripper, tt.WantRipper)
+			}
+			if got.RipDate != tt.WantDate {
+				t.Errorf("RipDate = %q, want %q", got.RipDate, tt.WantDate)
+			}
+			if got.AccurateRip != tt.WantAccurateRip {
+				t.Errorf("AccurateRip = %v, want %v", got.AccurateRip, tt.WantAccurateRip)
+			}
+			if len(got.Lines) != tt.WantLines {
+				t.Errorf("Lines = %q, want %d lines", got.Lines, tt.WantLines)
+			}
+			for _, line := range got.Lines {
+				if strings.Contains(line, "[") {
+					t.Errorf("line %q still contains BBCode", line)
+				}
+			}
+		})
+	}
+}
+
+func TestUploadCommand_MergeMetadata_Lineage(t *testing.T) {
+	torrentMeta := &Torrent{
+		Media:       "CD",
+		Description: "Tags by the original uploader\nRipped with EAC, log attached",
+	}
+	cmd := &UploadCommand{}
+	result := cmd.mergeMetadata(torrentMeta, &TorrentGroup{}, &domain.Torrent{Title: "Christmas Album"}, "Fixed tags")
+
+	want := "[b]Lineage[/b] (from the original upload)\nRipped with EAC, log attached\n\n[Trump Upload] Fixed: Fixed tags"
+	if result.Description != want {
+		t.Errorf("Description =\n%s\nwant:\n%s", result.Description, want)
+	}
+	if result.Lineage.Ripper != "EAC" {
+		t.Errorf("Lineage.Ripper = %q, want EAC", result.Lineage.Ripper)
+	}
+}
@@ -0,0 +1,236 @@
+package uploader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+	"github.com/cehbz/classical-tagger/internal/tagging"
+)
+
+const (
+	// masteringSizeTolerance is the largest relative difference in total FLAC
+	// size put down to tags, artwork and compression level rather than audio.
+	masteringSizeTolerance = 0.2
+	// masteringTrackTolerance is the largest relative difference in a track's
+	// share of the total size accepted when pairing tracks; tracks of another
+	// length or level stand out against the rest.
+	masteringTrackTolerance = 0.25
+	// masteringMinShare is the smallest share of the total size a track must
+	// have for its share to be compared; tiny tracks vary too much.
+	masteringMinShare = 0.01
+)
+
+// masteringFile is a FLAC file's size and bit depth.
+type masteringFile struct {
+	Path     string
+	Disc     int // 0 if unknown
+	Track    int // 0 if unknown
+	Size     int64
+	BitDepth int // 0 if unknown
+}
+
+// localMasteringFiles returns the size and bit depth of the local tracks'
+// files. Files that cannot be read leave the fingerprint incomplete, so none
+// is returned.
+func (c *UploadCommand) localMasteringFiles(local *domain.Torrent) []masteringFile {
+	var files []masteringFile
+	for _, track := range local.Tracks() {
+		path := filepath.Join(c.TorrentDir, track.Path)
+		info, err := os.Stat(path)
+		if err != nil {
+			c.log("Skipping the mastering check: %v", err)
+			return nil
+		}
+		depth, _ := tagging.ReadBitDepth(path)
+		files = append(files, masteringFile{
+			Path:     normalize.NFC(filepath.ToSlash(track.Path)),
+			Disc:     track.Disc,
+			Track:    track.Track,
+			Size:     info.Size(),
+			BitDepth: depth,
+		})
+	}
+	return files
+}
+
+// compareMastering compares the local FLAC files with the trumped torrent's
+// declared encoding and file list, returning the differences suggesting the
+// files are another mastering (a remaster, or another edition's transfer)
+// rather than the same audio retagged. Redacted does not publish track
+// durations, so each track's share of the album's size stands in for them.
+func compareMastering(local []masteringFile, torrent *Torrent) []string {
+	if len(local) == 0 {
+		return nil
+	}
+	var differences []string
+
+	// 24-bit files cannot trump a 16-bit torrent's audio, nor the reverse
+	depth := 0
+	for _, f := range local {
+		depth = max(depth, f.BitDepth)
+	}
+	if want := encodingBitDepth(torrent.Encoding); want > 0 && depth > 0 && depth != want {
+		differences = append(differences, fmt.Sprintf("the files are %d-bit but torrent %d is %d-bit (%s)", depth, torrent.TorrentID, want, torrent.Encoding))
+	}
+
+	remote := parseFileSizes(torrent.FileList)
+	if len(remote) == 0 {
+		return differences
+	}
+	if len(remote) != len(local) {
+		differences = append(differences, fmt.Sprintf("%d FLAC files but torrent %d has %d", len(local), torrent.TorrentID, len(remote)))
+	}
+
+	var localTotal, remoteTotal int64
+	for _, f := range local {
+		localTotal += f.Size
+	}
+	for _, f := range remote {
+		remoteTotal += f.Size
+	}
+	if localTotal == 0 || remoteTotal == 0 {
+		return differences
+	}
+	if change := float64(localTotal-remoteTotal) / float64(remoteTotal); change > masteringSizeTolerance || change < -masteringSizeTolerance {
+		differences = append(differences, fmt.Sprintf("the FLAC files total %s but torrent %d's %s (%+.0f%%)",
+			formatMB(localTotal), torrent.TorrentID, formatMB(remoteTotal), change*100))
+	}
+
+	// Pair tracks, when the counts agree
+	if len(remote) != len(local) {
+		return differences
+	}
+	var mismatched []string
+	for _, pair := range pairTracks(local, remote) {
+		f := pair[0]
+		localShare := float64(f.Size) / float64(localTotal)
+		remoteShare := float64(pair[1].Size) / float64(remoteTotal)
+		if remoteShare < masteringMinShare {
+			continue
+		}
+		if change := localShare/remoteShare - 1; change > masteringTrackTolerance || change < -masteringTrackTolerance {
+			mismatched = append(mismatched, filepath.Base(f.Path))
+		}
+	}
+	if len(mismatched) > 0 {
+		differences = append(differences, fmt.Sprintf("%d track(s) differ in length or level from torrent %d's: %s",
+			len(mismatched), torrent.TorrentID, strings.Join(mismatched[:min(3, len(mismatched))], ", ")))
+	}
+	return differences
+}
+
+// pairTracks pairs each local file with the remote file of the same disc and
+// track number, so the two sides may name and arrange their files differently.
+// Unless every file has a number found on the other side too, files are paired
+// by position instead, both lists in disc and track order. The lists are the
+// same length.
+func pairTracks(local, remote []masteringFile) [][2]masteringFile {
+	local, remote = slices.Clone(local), slices.Clone(remote)
+	slices.SortFunc(local, compareTrackOrder)
+	slices.SortFunc(remote, compareTrackOrder)
+
+	byNumber := make(map[[2]int]masteringFile)
+	for _, f := range remote {
+		byNumber[[2]int{f.Disc, f.Track}] = f
+	}
+	pairs := make([][2]masteringFile, len(local))
+	numbered := len(byNumber) == len(remote)
+	for i, f := range local {
+		r, ok := byNumber[[2]int{f.Disc, f.Track}]
+		if !ok || f.Track == 0 {
+			numbered = false
+			break
+		}
+		pairs[i] = [2]masteringFile{f, r}
+	}
+	if !numbered {
+		for i := range local {
+			pairs[i] = [2]masteringFile{local[i], remote[i]}
+		}
+	}
+	return pairs
+}
+
+// compareTrackOrder orders files by disc and track number, then path.
+func compareTrackOrder(a, b masteringFile) int {
+	if a.Disc != b.Disc {
+		return a.Disc - b.Disc
+	}
+	if a.Track != b.Track {
+		return a.Track - b.Track
+	}
+	return strings.Compare(a.Path, b.Path)
+}
+
+// trackPosition returns the disc and track number a torrent file's path gives
+// it: the disc from a "CD2"/"Disc 2" folder or a "2-01" file name prefix
+// (default 1), the track from the file name's leading number (0 if none).
+func trackPosition(path string) (disc, track int) {
+	disc = 1
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for _, part := range parts[:len(parts)-1] {
+		if _, n, ok := domain.ParseDiscFolder(part); ok {
+			disc = n
+		}
+	}
+	m := trackPrefixPattern.FindStringSubmatch(parts[len(parts)-1])
+	switch {
+	case m == nil:
+		return disc, 0
+	case m[1] != "":
+		disc, _ = strconv.Atoi(m[1])
+		track, _ = strconv.Atoi(m[2])
+	default:
+		track, _ = strconv.Atoi(m[3])
+	}
+	return disc, track
+}
+
+// trackPrefixPattern matches a file name's "01 ", "1. ", "01-" or "2-01 " prefix.
+var trackPrefixPattern = regexp.MustCompile(`^(?:(\d{1,2})-(\d{2})|(\d{1,3}))[\s._-]`)
+
+// encodingBitDepth returns the bit depth a Redacted encoding declares, or 0
+// for a lossy or unknown one.
+func encodingBitDepth(encoding string) int {
+	switch encoding {
+	case "Lossless":
+		return 16
+	case "24bit Lossless":
+		return 24
+	}
+	return 0
+}
+
+// parseFileSizes returns the FLAC files in a Redacted file list
+// ("path{{{size}}}|||path{{{size}}}") with their sizes and positions (see
+// trackPosition), in disc and track order.
+func parseFileSizes(fileList string) []masteringFile {
+	var files []masteringFile
+	for _, entry := range strings.Split(fileList, "}}}") {
+		entry = strings.TrimPrefix(entry, "|||")
+		path, size, found := strings.Cut(entry, "{{{")
+		if !found || !strings.HasSuffix(strings.ToLower(path), ".flac") {
+			continue
+		}
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			continue
+		}
+		disc, track := trackPosition(path)
+		files = append(files, masteringFile{Path: normalize.NFC(path), Disc: disc, Track: track, Size: n})
+	}
+	slices.SortFunc(files, compareTrackOrder)
+	return files
+}
+
+// formatMB writes a size in megabytes.
+func formatMB(size int64) string {
+	return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+}
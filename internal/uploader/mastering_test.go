@@ -0,0 +1,145 @@
+package uploader
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompareMastering(t *testing.T) {
+	const mb = 1 << 20
+	fileList := "01 - Allegro.flac{{{30000000}}}|||02 - Adagio.flac{{{40000000}}}|||03 - Presto.flac{{{30000000}}}|||folder.jpg{{{200000}}}"
+	files := func(sizes ...int64) []masteringFile {
+		names := []string{"01 - Allegro.flac", "02 - Adagio.flac", "03 - Presto.flac"}
+		var files []masteringFile
+		for i, size := range sizes {
+			files = append(files, masteringFile{Path: names[i], Size: size, BitDepth: 16})
+		}
+		return files
+	}
+
+	tests := []struct {
+		Name     string
+		Local    []masteringFile
+		Encoding string
+		Want     []string // Substrings of the differences, in order
+	}{
+		{
+			Name:     "same audio retagged and recompressed",
+			Local:    files(31000000, 41500000, 30500000),
+			Encoding: "Lossless",
+		},
+		{
+			Name:     "24-bit files for a 16-bit torrent",
+			Local:    []masteringFile{{Path: "01 - Allegro.flac", Size: 30000000, BitDepth: 24}, {Path: "02 - Adagio.flac", Size: 40000000, BitDepth: 24}, {Path: "03 - Presto.flac", Size: 30000000, BitDepth: 24}},
+			Encoding: "Lossless",
+			Want:     []string{"the files are 24-bit but torrent 7 is 16-bit"},
+		},
+		{
+			Name:     "louder remaster",
+			Local:    files(40*mb, 55*mb, 40*mb),
+			Encoding: "Lossless",
+			Want:     []string{"the FLAC files total 135.0 MB but torrent 7's 95.4 MB (+42%)"},
+		},
+		{
+			Name:     "different track lengths",
+			Local:    files(20000000, 55000000, 25000000),
+			Encoding: "Lossless",
+			Want:     []string{"2 track(s) differ in length or level from torrent 7's: 01 - Allegro.flac, 02 - Adagio.flac"},
+		},
+		{
+			Name:     "missing track",
+			Local:    files(30000000, 40000000),
+			Encoding: "Lossless",
+			Want:     []string{"2 FLAC files but torrent 7 has 3", "total"},
+		},
+		{
+			Name:     "unreadable local files",
+			Encoding: "24bit Lossless",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			got := compareMastering(tt.Local, &Torrent{TorrentID: 7, Encoding: tt.Encoding, FileList: fileList})
+			if len(got) != len(tt.Want) {
+				t.Fatalf("compareMastering() = %q, want %d differences", got, len(tt.Want))
+			}
+			for i, want := range tt.Want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("difference %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareMastering_PairsByTrackNumber(t *testing.T) {
+	// Sizes differ enough between tracks that any mispairing is flagged
+	sizes := []int64{20000000, 45000000, 30000000, 60000000}
+	local := func(names ...string) []masteringFile {
+		var files []masteringFile
+		for i, name := range names {
+			files = append(files, masteringFile{Path: name, Disc: i/2 + 1, Track: i%2 + 1, Size: sizes[i], BitDepth: 16})
+		}
+		return files
+	}
+	remote := func(names ...string) string {
+		var entries []string
+		for i, name := range names {
+			entries = append(entries, fmt.Sprintf("%s{{{%d}}}", name, sizes[i]))
+		}
+		return strings.Join(append(entries, "Scans/booklet.pdf{{{3000000}}}"), "|||")
+	}
+
+	tests := []struct {
+		Name     string
+		Local    []masteringFile
+		FileList string
+	}{
+		{
+			Name:     "titles only locally, numbered remotely",
+			Local:    local("Sonata - I. Vivace.flac", "Sonata - II. Adagio.flac", "Concerto - I. Allegro.flac", "Concerto - II. Largo.flac"),
+			FileList: remote("1. Vivace.flac", "2. Adagio.flac", "CD2/1. Allegro.flac", "CD2/2. Largo.flac"),
+		},
+		{
+			Name:     "disc folders locally, disc-track prefixes remotely",
+			Local:    local("Disc 1/01 - Vivace.flac", "Disc 1/02 - Adagio.flac", "Disc 2/01 - Allegro.flac", "Disc 2/02 - Largo.flac"),
+			FileList: remote("1-01 Vivace.flac", "1-02 Adagio.flac", "2-01 Allegro.flac", "2-02 Largo.flac"),
+		},
+		{
+			Name:     "flat locally, disc folders remotely",
+			Local:    local("101 Vivace.flac", "102 Adagio.flac", "201 Allegro.flac", "202 Largo.flac"),
+			FileList: remote("CD1/01 Vivace.flac", "CD1/02 Adagio.flac", "CD2/01 Allegro.flac", "CD2/02 Largo.flac"),
+		},
+		{
+			Name:     "no numbers remotely, paired by position",
+			Local:    local("z.flac", "y.flac", "x.flac", "w.flac"),
+			FileList: remote("a Vivace.flac", "b Adagio.flac", "c Allegro.flac", "d Largo.flac"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := compareMastering(tt.Local, &Torrent{TorrentID: 7, Encoding: "Lossless", FileList: tt.FileList}); len(got) != 0 {
+				t.Errorf("compareMastering() = %q, want the same mastering", got)
+			}
+		})
+	}
+}
+
+func TestTrackPosition(t *testing.T) {
+	tests := map[string][2]int{
+		"01 - Allegro.flac":            {1, 1},
+		"1. Allegro.flac":              {1, 1},
+		"12_Allegro.flac":              {1, 12},
+		"2-03 Allegro.flac":            {2, 3},
+		"CD2/03 Allegro.flac":          {2, 3},
+		"Album/Disc 3/1-04 Largo.flac": {1, 4},
+		"Allegro.flac":                 {1, 0},
+		"Disc 2/Allegro.flac":          {2, 0},
+	}
+	for path, want := range tests {
+		if disc, track := trackPosition(path); disc != want[0] || track != want[1] {
+			t.Errorf("trackPosition(%q) = %d, %d, want %d, %d", path, disc, track, want[0], want[1])
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package uploader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// Media lists the media Redacted accepts, in the upload form's spelling.
+var Media = []string{"CD", "DVD", "Vinyl", "Soundboard", "SACD", "DAT", "Cassette", "WEB", "Blu-Ray"}
+
+// ParseMedia returns the upload form's spelling of media, matched ignoring case.
+func ParseMedia(media string) (string, error) {
+	for _, m := range Media {
+		if strings.EqualFold(m, strings.TrimSpace(media)) {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown media %q (want one of %s)", media, strings.Join(Media, ", "))
+}
+
+// detectMedia returns the media of the local files and what says so: the
+// metadata's declared media, else "CD" when a rip log or cue sheet is present.
+// Returns "" when nothing tells; WEB and lossless vinyl rips carry no marker.
+func (c *UploadCommand) detectMedia(local *domain.Torrent) (media, evidence string) {
+	if local.SiteMetadata != nil && local.SiteMetadata.Media != "" {
+		return local.SiteMetadata.Media, "declared in the metadata"
+	}
+	if c.hasFileWithExt(".log") {
+		return "CD", "rip log present"
+	}
+	if c.hasFileWithExt(".cue") {
+		return "CD", "cue sheet present"
+	}
+	return "", ""
+}
+
+// checkMedia compares the local media with the torrent being trumped. A
+// different media is a different edition, which cannot trump (err). Files with
+// no media marker earn a warning when the torrent is a CD rip with a log, since
+// files without one may not be from a CD at all.
+func (c *UploadCommand) checkMedia(media, evidence string, torrent *Torrent) (warning string, err error) {
+	switch {
+	case media != "" && torrent.Media != "" && !strings.EqualFold(media, torrent.Media):
+		return "", &domain.ErrMediaMismatch{Local: media, Evidence: evidence, Trumped: torrent.Media, TorrentID: torrent.TorrentID}
+	case media == "" && torrent.HasLog:
+		return fmt.Sprintf("torrent %d is a CD rip with a log but the files have no log or cue sheet; if they are not from the CD, upload with -new-edition -media <media>", torrent.TorrentID), nil
+	}
+	return "", nil
+}
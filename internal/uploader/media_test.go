@@ -0,0 +1,88 @@
+package uploader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestParseMedia(t *testing.T) {
+	tests := map[string]string{"cd": "CD", "Web": "WEB", " vinyl ": "Vinyl", "blu-ray": "Blu-Ray"}
+	for input, want := range tests {
+		if got, err := ParseMedia(input); err != nil || got != want {
+			t.Errorf("ParseMedia(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseMedia("minidisc"); err == nil {
+		t.Error("ParseMedia(minidisc) succeeded, want error")
+	}
+}
+
+func TestDetectMedia(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Files    []string
+		Declared string
+		Want     string
+	}{
+		{Name: "no markers", Files: []string{"01 - Allegro.flac"}},
+		{Name: "rip log", Files: []string{"01 - Allegro.flac", "Album.log"}, Want: "CD"},
+		{Name: "cue sheet on disc", Files: []string{"Disc 1/01 - Allegro.flac", "Disc 1/Album.cue"}, Want: "CD"},
+		{Name: "declared wins", Files: []string{"Album.log"}, Declared: "Vinyl", Want: "Vinyl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.Files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			local := &domain.Torrent{}
+			if tt.Declared != "" {
+				local.SiteMetadata = &domain.SiteMetadata{Media: tt.Declared}
+			}
+			c := &UploadCommand{TorrentDir: dir}
+			if got, _ := c.detectMedia(local); got != tt.Want {
+				t.Errorf("detectMedia() = %q, want %q", got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestCheckMedia(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Media       string
+		Torrent     Torrent
+		WantWarning bool
+		WantErr     bool
+	}{
+		{Name: "same media", Media: "CD", Torrent: Torrent{Media: "CD", HasLog: true}},
+		{Name: "case differs", Media: "web", Torrent: Torrent{Media: "WEB"}},
+		{Name: "CD rip against WEB", Media: "CD", Torrent: Torrent{Media: "WEB"}, WantErr: true},
+		{Name: "vinyl against CD", Media: "Vinyl", Torrent: Torrent{Media: "CD", HasLog: true}, WantErr: true},
+		{Name: "unknown against logged CD", Torrent: Torrent{Media: "CD", HasLog: true}, WantWarning: true},
+		{Name: "unknown against WEB", Torrent: Torrent{Media: "WEB"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			c := &UploadCommand{}
+			warning, err := c.checkMedia(tt.Media, "test", &tt.Torrent)
+			if (warning != "") != tt.WantWarning || (err != nil) != tt.WantErr {
+				t.Errorf("checkMedia() = %q, %v", warning, err)
+			}
+			var mismatch *domain.ErrMediaMismatch
+			if tt.WantErr && !errors.As(err, &mismatch) {
+				t.Errorf("checkMedia() error = %v, want *domain.ErrMediaMismatch", err)
+			}
+		})
+	}
+}
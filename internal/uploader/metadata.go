@@ -0,0 +1,134 @@
+package uploader
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+	"github.com/cehbz/classical-tagger/internal/storage"
+	"github.com/cehbz/classical-tagger/internal/tagging"
+)
+
+// loadMetadataFile loads the curated metadata JSON, the single source of truth
+// for the upload, and checks it describes the files in the torrent directory:
+// every track's file exists, no audio file is left out, and the files carry the
+// tags the JSON gives them. Mismatches fail the upload, except in dry runs.
+// Channel counts and durations the JSON lacks are read from the files. A set
+// can be given as a comma-separated list of its file and disc files.
+func (c *UploadCommand) loadMetadataFile() (*domain.Torrent, error) {
+	repo := &storage.Repository{FS: c.FS}
+	torrent, err := repo.LoadSet(storage.SplitPaths(c.MetadataFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata %s: %w", c.MetadataFile, err)
+	}
+	if len(torrent.Tracks()) == 0 {
+		return nil, fmt.Errorf("metadata %s: %w", c.MetadataFile, domain.ErrNoTracks)
+	}
+	// Paths in the JSON are relative to the album folder being uploaded
+	torrent.RootPath = c.TorrentDir
+
+	c.log("Checking %s against the files...", c.MetadataFile)
+	problems, err := checkMetadataFiles(fsys.Or(c.FS), c.TorrentDir, torrent)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) == 0 {
+		// Tags can only be compared once the file lists agree
+		problems = checkMetadataTags(c.TorrentDir, torrent)
+	}
+	if len(problems) > 0 {
+		for _, e := range problems {
+			fmt.Fprintf(os.Stderr, "Metadata error: %v\n", e)
+		}
+		if !c.DryRun {
+			return nil, fmt.Errorf("%s: %w in %s (%d errors)", c.MetadataFile, domain.ErrMetadataMismatch, c.TorrentDir, len(problems))
+		}
+		c.log("Dry run mode - continuing despite metadata mismatches")
+	}
+	return torrent, nil
+}
+
+// checkMetadataTags compares the tags of the files under dir with the tracks
+// in torrent, filling in channel counts and durations the tracks lack.
+func checkMetadataTags(dir string, torrent *domain.Torrent) []error {
+	var problems []error
+
+	for _, track := range torrent.Tracks() {
+		path := filepath.Join(dir, track.File.Path)
+		if track.Channels == 0 {
+			if channels, err := tagging.ReadChannelCount(path); err == nil {
+				track.Channels = channels
+			}
+		}
+		if track.Duration == 0 {
+			if duration, err := tagging.ReadDuration(path); err == nil {
+				track.Duration = duration
+			}
+		}
+		mismatches, err := tagging.VerifyTags(path, track, torrent)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", track.File.Path, err))
+			continue
+		}
+		for _, m := range mismatches {
+			problems = append(problems, fmt.Errorf("%s: %s", track.File.Path, m))
+		}
+	}
+	return problems
+}
+
+// checkMetadataFiles compares the tracks in torrent with the audio files under
+// dir, reporting tracks whose file is missing and audio files no track names.
+// Paths are compared NFC-normalized, as file systems may store either form.
+func checkMetadataFiles(files fsys.FS, dir string, torrent *domain.Torrent) ([]error, error) {
+	onDisk := make(map[string]string) // Normalized relative path to the path on disk
+	err := files.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isAudioFile(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		onDisk[normalize.NFC(filepath.ToSlash(rel))] = rel
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	var problems []error
+	listed := make(map[string]bool)
+	for _, track := range torrent.Tracks() {
+		key := normalize.NFC(filepath.ToSlash(track.File.Path))
+		listed[key] = true
+		if _, ok := onDisk[key]; !ok {
+			problems = append(problems, fmt.Errorf("disc %d track %d: %s is not in %s", track.Disc, track.Track, track.File.Path, dir))
+		}
+	}
+	var extra []string
+	for key, rel := range onDisk {
+		if !listed[key] {
+			extra = append(extra, rel)
+		}
+	}
+	slices.Sort(extra)
+	for _, rel := range extra {
+		problems = append(problems, fmt.Errorf("%s is not in the metadata", rel))
+	}
+	return problems, nil
+}
+
+// isAudioFile reports whether path names a FLAC or DSD file.
+func isAudioFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".flac") || tagging.IsDSD(path)
+}
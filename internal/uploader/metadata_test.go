@@ -0,0 +1,62 @@
+package uploader
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+	"github.com/cehbz/classical-tagger/internal/storage"
+)
+
+func TestCheckMetadataFiles(t *testing.T) {
+	mem := fsys.NewMem()
+	mem.MkdirAll("/music/album/CD1", 0755)
+	for _, name := range []string{"CD1/01 - Kyrie.flac", "CD1/02 - Gloria.flac", "CD1/03 - Credo.flac", "cover.jpg"} {
+		mem.WriteFile("/music/album/"+name, nil, 0644)
+	}
+	torrent := &domain.Torrent{Files: []domain.FileLike{
+		&domain.Track{File: domain.File{Path: "CD1/01 - Kyrie.flac"}, Disc: 1, Track: 1, Title: "Kyrie"},
+		&domain.Track{File: domain.File{Path: "CD1/02 - Gloria.flac"}, Disc: 1, Track: 2, Title: "Gloria"},
+		&domain.Track{File: domain.File{Path: "CD1/04 - Sanctus.flac"}, Disc: 1, Track: 4, Title: "Sanctus"},
+	}}
+
+	problems, err := checkMetadataFiles(mem, "/music/album", torrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 2 ||
+		!strings.Contains(problems[0].Error(), "disc 1 track 4: CD1/04 - Sanctus.flac is not in /music/album") ||
+		problems[1].Error() != "CD1/03 - Credo.flac is not in the metadata" {
+		t.Errorf("checkMetadataFiles() = %v", problems)
+	}
+}
+
+func TestUploadCommand_LoadMetadataFile(t *testing.T) {
+	mem := fsys.NewMem()
+	mem.MkdirAll("/music/album", 0755)
+	mem.WriteFile("/music/album/01 - Kyrie.flac", nil, 0644)
+	torrent := &domain.Torrent{RootPath: "album", Title: "Missa", Files: []domain.FileLike{
+		&domain.Track{File: domain.File{Path: "01 - Kyrie.flac"}, Disc: 1, Track: 1, Title: "Kyrie"},
+		&domain.Track{File: domain.File{Path: "02 - Gloria.flac"}, Disc: 1, Track: 2, Title: "Gloria"},
+	}}
+	if err := (&storage.Repository{FS: mem}).SaveToFile(torrent, "/music/album.json"); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &UploadCommand{TorrentDir: "/music/album", MetadataFile: "/music/album.json", FS: mem}
+	if _, err := cmd.loadLocalTorrent(); !errors.Is(err, domain.ErrMetadataMismatch) {
+		t.Errorf("loadLocalTorrent() error = %v, want ErrMetadataMismatch", err)
+	}
+
+	// Dry runs report the mismatch and carry on with the JSON
+	cmd.DryRun = true
+	loaded, err := cmd.loadLocalTorrent()
+	if err != nil {
+		t.Fatalf("loadLocalTorrent() dry run error = %v", err)
+	}
+	if loaded.Title != "Missa" || loaded.RootPath != "/music/album" || len(loaded.Tracks()) != 2 {
+		t.Errorf("loadLocalTorrent() = %+v", loaded)
+	}
+}
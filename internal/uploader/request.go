@@ -0,0 +1,85 @@
+package uploader
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// requestAllows reports whether value satisfies a request's list of accepted values.
+// An empty list, or one containing "Any" or "All", accepts everything.
+func requestAllows(accepted []string, value string) bool {
+	if len(accepted) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(accepted, func(a string) bool {
+		return strings.EqualFold(a, "Any") || strings.EqualFold(a, "All") || strings.EqualFold(a, value)
+	})
+}
+
+// normalizeCatalogueNumber removes spacing and punctuation so "HMC 902170" matches "HMC-902170".
+func normalizeCatalogueNumber(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// checkRequest verifies the prepared upload satisfies a request's requirements
+// (format, encoding, media, catalogue number, log/cue) and returns every violation.
+func (c *UploadCommand) checkRequest(req *Request, meta *Metadata) []error {
+	var errs []error
+
+	if req.IsFilled {
+		errs = append(errs, fmt.Errorf("request %d is already filled", req.ID))
+	}
+	if req.GroupID != 0 && req.GroupID != meta.GroupID {
+		errs = append(errs, fmt.Errorf("request %d is for group %d, upload is for group %d", req.ID, req.GroupID, meta.GroupID))
+	}
+	if !requestAllows(req.Formats, meta.Format) {
+		errs = append(errs, fmt.Errorf("format %q not accepted (request wants %s)", meta.Format, strings.Join(req.Formats, ", ")))
+	}
+	if !requestAllows(req.Encodings, meta.Encoding) {
+		errs = append(errs, fmt.Errorf("encoding %q not accepted (request wants %s)", meta.Encoding, strings.Join(req.Encodings, ", ")))
+	}
+	if !requestAllows(req.Media, meta.Media) {
+		errs = append(errs, fmt.Errorf("media %q not accepted (request wants %s)", meta.Media, strings.Join(req.Media, ", ")))
+	}
+
+	if req.CatalogueNumber != "" {
+		catalogue := meta.CatalogNumber
+		if meta.Remastered && meta.RemasterCatalogueNumber != "" {
+			catalogue = meta.RemasterCatalogueNumber
+		}
+		if normalizeCatalogueNumber(catalogue) != normalizeCatalogueNumber(req.CatalogueNumber) {
+			errs = append(errs, fmt.Errorf("catalogue number %q does not match request's %q", catalogue, req.CatalogueNumber))
+		}
+	}
+
+	// Log/cue requirements only apply to CD rips
+	if meta.Media == "CD" && req.LogCue != "" {
+		logCue := strings.ToLower(req.LogCue)
+		if strings.Contains(logCue, "log") && !c.hasFileWithExt(".log") {
+			errs = append(errs, fmt.Errorf("request requires a log (%s) but none found in %s", req.LogCue, c.TorrentDir))
+		}
+		if strings.Contains(logCue, "cue") && !c.hasFileWithExt(".cue") {
+			errs = append(errs, fmt.Errorf("request requires a cue sheet but none found in %s", c.TorrentDir))
+		}
+	}
+
+	return errs
+}
+
+// hasFileWithExt reports whether the torrent directory (or a disc subdirectory) contains a file with ext.
+func (c *UploadCommand) hasFileWithExt(ext string) bool {
+	for _, pattern := range []string{"*" + ext, filepath.Join("*", "*"+ext)} {
+		if matches, _ := filepath.Glob(filepath.Join(c.TorrentDir, pattern)); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package uploader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+)
+
+func TestRedactedClient_GetRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("action") != "request" || r.URL.Query().Get("id") != "4242" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{
+			"status": "success",
+			"response": {
+				"requestId": 4242,
+				"groupId": 98765,
+				"title": "Christmas Album",
+				"catalogueNumber": "HMC 902170",
+				"formatList": ["FLAC"],
+				"bitrateList": ["Lossless", "24bit Lossless"],
+				"mediaList": ["CD"],
+				"logCue": "Log (100%) + Cue",
+				"isFilled": false
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := &RedactedClient{
+		BaseURL:     server.URL,
+		APIKey:      "test-key",
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+		RateLimiter: ratelimit.NewRateLimiter(10, 10*time.Second),
+	}
+
+	req, err := client.GetRequest(context.Background(), 4242)
+	if err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if req.ID != 4242 || req.GroupID != 98765 || req.CatalogueNumber != "HMC 902170" || len(req.Encodings) != 2 || req.LogCue == "" {
+		t.Errorf("GetRequest() = %+v", req)
+	}
+}
+
+func TestUploadCommand_CheckRequest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "rip.log"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta := &Metadata{
+		GroupID:       98765,
+		Format:        "FLAC",
+		Encoding:      "Lossless",
+		Media:         "CD",
+		CatalogNumber: "HMC-902170",
+	}
+
+	tests := []struct {
+		Name      string
+		Request   Request
+		WantCount int
+	}{
+		{
+			Name:    "satisfied",
+			Request: Request{ID: 1, GroupID: 98765, Formats: []string{"FLAC"}, Encodings: []string{"Lossless"}, Media: []string{"CD", "WEB"}, CatalogueNumber: "HMC 902170", LogCue: "Log"},
+		},
+		{
+			Name:    "any accepts everything",
+			Request: Request{ID: 1, Formats: []string{"Any"}, Encodings: []string{"Any"}, Media: []string{"Any"}},
+		},
+		{
+			Name:      "wrong encoding and media",
+			Request:   Request{ID: 1, Encodings: []string{"24bit Lossless"}, Media: []string{"Vinyl"}},
+			WantCount: 2,
+		},
+		{
+			Name:      "already filled, other group, catalogue mismatch",
+			Request:   Request{ID: 1, GroupID: 1, IsFilled: true, CatalogueNumber: "HMC 901234"},
+			WantCount: 3,
+		},
+		{
+			Name:      "missing cue",
+			Request:   Request{ID: 1, LogCue: "Log (100%) + Cue"},
+			WantCount: 1,
+		},
+	}
+
+	cmd := &UploadCommand{TorrentDir: dir}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			errs := cmd.checkRequest(&tt.Request, meta)
+			if len(errs) != tt.WantCount {
+				t.Errorf("checkRequest() = %v, want %d errors", errs, tt.WantCount)
+			}
+		})
+	}
+}
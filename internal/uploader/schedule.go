@@ -0,0 +1,197 @@
+package uploader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/state"
+)
+
+// Window is a daily span of local time uploads may be submitted in. An End
+// before Start wraps past midnight ("22:00-06:00").
+type Window struct {
+	Start, End time.Duration // Since midnight
+}
+
+// ParseWindow parses a window written "HH:MM-HH:MM".
+func ParseWindow(s string) (*Window, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid upload window %q (want HH:MM-HH:MM)", s)
+	}
+	var w Window
+	for _, bound := range []struct {
+		text string
+		into *time.Duration
+	}{{from, &w.Start}, {to, &w.End}} {
+		t, err := time.Parse("15:04", strings.TrimSpace(bound.text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid upload window %q (want HH:MM-HH:MM)", s)
+		}
+		*bound.into = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	if w.Start == w.End {
+		return nil, fmt.Errorf("upload window %q is empty", s)
+	}
+	return &w, nil
+}
+
+// String writes the window as ParseWindow reads it.
+func (w Window) String() string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return clock(w.Start) + "-" + clock(w.End)
+}
+
+// next returns t if it falls in the window, else the window's next start.
+func (w Window) next(t time.Time) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	if w.Start < w.End && offset >= w.Start && offset < w.End ||
+		w.Start > w.End && (offset >= w.Start || offset < w.End) {
+		return t
+	}
+	if offset < w.Start {
+		return midnight.Add(w.Start)
+	}
+	return midnight.AddDate(0, 0, 1).Add(w.Start)
+}
+
+// Schedule defers upload submissions to a daily window and spaces them out,
+// as site etiquette asks of mass trumps. Everything but the submission itself
+// happens at once; prepared uploads wait in the persisted upload queue.
+type Schedule struct {
+	Window  *Window       // nil: any time of day
+	Spacing time.Duration // Least time between two submissions
+}
+
+// IsZero reports whether the schedule submits uploads at once.
+func (s Schedule) IsZero() bool {
+	return s.Window == nil && s.Spacing <= 0
+}
+
+// Next returns the earliest time at or after now an upload may be submitted,
+// the last one having been submitted at last (zero: never).
+func (s Schedule) Next(now, last time.Time) time.Time {
+	next := now
+	if !last.IsZero() && last.Add(s.Spacing).After(next) {
+		next = last.Add(s.Spacing)
+	}
+	if s.Window != nil {
+		next = s.Window.next(next)
+	}
+	return next
+}
+
+// queueUpload adds the prepared upload to the upload queue, then submits the
+// uploads whose turn has come.
+func (c *UploadCommand) queueUpload(ctx context.Context, upload *Upload, torrentPath string, meta *Metadata) error {
+	form, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+	queued := state.QueuedUpload{
+		Dir:         c.TorrentDir,
+		TorrentPath: torrentPath,
+		GroupID:     upload.GroupID,
+		Form:        form,
+		Collages:    c.Collages,
+		Queued:      clock.Or(c.Clock).Now(),
+	}
+	if c.Ledger {
+		entry := c.ledgerEntry(meta)
+		queued.Ledger = &entry
+	}
+	if err := state.EnqueueUpload(queued); err != nil {
+		return err
+	}
+	c.log("Upload prepared and queued")
+	return c.RunQueue(ctx, false)
+}
+
+// uploadQueueRunner is the lock held while submitting from the upload queue.
+const uploadQueueRunner = "upload-queue:run"
+
+// RunQueue submits the queued uploads in order as the schedule allows. With
+// wait, it sleeps until each upload's slot and returns once the queue is
+// empty; without, it submits those due now and reports when the next is due.
+// A failed submission stays at the head of the queue.
+func (c *UploadCommand) RunQueue(ctx context.Context, wait bool) error {
+	// Only one process may submit from the queue
+	lock, err := state.Acquire(uploadQueueRunner)
+	var locked *state.LockedError
+	if errors.As(err, &locked) && !wait {
+		c.log("Queue is being submitted by %s", locked.Holder)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	now := clock.Or(c.Clock).Now
+	for {
+		queue, err := state.LoadUploadQueue()
+		if err != nil {
+			return err
+		}
+		if len(queue.Pending) == 0 {
+			return nil
+		}
+		next := c.Schedule.Next(now(), queue.LastSubmitted)
+		if next.After(now()) {
+			if !wait {
+				console.Infof("%d upload(s) queued; the next (%s) is due at %s (submit with -run-queue)\n",
+					len(queue.Pending), queue.Pending[0].Dir, next.Format(time.DateTime))
+				return nil
+			}
+			c.log("Waiting until %s to submit %s...", next.Format(time.DateTime), queue.Pending[0].Dir)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-clock.Or(c.Clock).After(next.Sub(now())):
+			}
+			continue
+		}
+		if err := c.submitQueued(ctx, queue.Pending[0]); err != nil {
+			return err
+		}
+	}
+}
+
+// submitQueued submits a queued upload and removes it from the queue.
+func (c *UploadCommand) submitQueued(ctx context.Context, queued state.QueuedUpload) error {
+	var upload Upload
+	if err := json.Unmarshal(queued.Form, &upload); err != nil {
+		return fmt.Errorf("queued upload of %s: %w", queued.Dir, err)
+	}
+	c.log("Uploading %s...", queued.Dir)
+	if err := c.Client.Upload(ctx, &upload, queued.TorrentPath); err != nil {
+		return fmt.Errorf("upload of %s failed: %w", queued.Dir, err)
+	}
+	console.Resultf(queued.Dir, "Uploaded %s\n", queued.Dir)
+	when := clock.Or(c.Clock).Now()
+	if err := state.DequeueUpload(queued.Dir, when); err != nil {
+		return fmt.Errorf("%s was uploaded but is still queued; remove it from %s before the next run: %w", queued.Dir, state.UploadQueuePath(), err)
+	}
+	if queued.Ledger != nil {
+		entry := *queued.Ledger
+		entry.Uploaded, entry.When = true, when
+		if err := state.RecordUpload(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record the upload: %v\n", err)
+		}
+	}
+	collages := c.Collages
+	c.Collages = queued.Collages
+	c.updateCollages(ctx, queued.GroupID)
+	c.Collages = collages
+	return nil
+}
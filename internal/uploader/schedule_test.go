@@ -0,0 +1,132 @@
+package uploader
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
+	"github.com/cehbz/classical-tagger/internal/ratelimit"
+	"github.com/cehbz/classical-tagger/internal/state"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"01:00-06:30", "01:00-06:30", false},
+		{" 22:00 - 6:00 ", "22:00-06:00", false},
+		{"02:00", "", true},
+		{"25:00-06:00", "", true},
+		{"03:00-03:00", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWindow(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseWindow(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("ParseWindow(%q) = %v, %v; want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 3, day, hour, minute, 0, 0, time.UTC)
+	}
+	night, _ := ParseWindow("22:00-06:00")
+	early, _ := ParseWindow("01:00-05:00")
+
+	tests := []struct {
+		name      string
+		schedule  Schedule
+		now, last time.Time
+		want      time.Time
+	}{
+		{"no schedule", Schedule{}, at(1, 12, 0), at(1, 11, 59), at(1, 12, 0)},
+		{"spaced", Schedule{Spacing: 30 * time.Minute}, at(1, 12, 0), at(1, 11, 50), at(1, 12, 20)},
+		{"spacing passed", Schedule{Spacing: 30 * time.Minute}, at(1, 12, 0), at(1, 11, 0), at(1, 12, 0)},
+		{"in window", Schedule{Window: early}, at(1, 2, 0), time.Time{}, at(1, 2, 0)},
+		{"before window", Schedule{Window: early}, at(1, 0, 30), time.Time{}, at(1, 1, 0)},
+		{"after window", Schedule{Window: early}, at(1, 5, 0), time.Time{}, at(2, 1, 0)},
+		{"window past midnight", Schedule{Window: night}, at(1, 23, 0), time.Time{}, at(1, 23, 0)},
+		{"outside window past midnight", Schedule{Window: night}, at(1, 12, 0), time.Time{}, at(1, 22, 0)},
+		{"spacing runs out of window", Schedule{Window: early, Spacing: time.Hour}, at(1, 4, 30), at(1, 4, 15), at(2, 1, 0)},
+	}
+	for _, tt := range tests {
+		if got := tt.schedule.Next(tt.now, tt.last); !got.Equal(tt.want) {
+			t.Errorf("%s: Next() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUploadCommand_RunQueue(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	var uploaded []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/upload.php" {
+			http.NotFound(w, r)
+			return
+		}
+		uploaded = append(uploaded, r.FormValue("groupid"))
+	}))
+	defer server.Close()
+
+	torrentPath := filepath.Join(t.TempDir(), "album.torrent")
+	if err := os.WriteFile(torrentPath, []byte("d4:infod4:name5:Albumee"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i, dir := range []string{"/music/A", "/music/B"} {
+		form, _ := json.Marshal(&Upload{GroupID: 10 + i, Title: dir})
+		queued := state.QueuedUpload{Dir: dir, TorrentPath: torrentPath, GroupID: 10 + i, Form: form,
+			Ledger: &state.Upload{Dir: dir, GroupID: 10 + i}}
+		if err := state.EnqueueUpload(queued); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fake := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	c := &UploadCommand{
+		Client: &RedactedClient{
+			BaseURL:     server.URL,
+			HTTPClient:  server.Client(),
+			RateLimiter: ratelimit.NewRateLimiter(10, time.Second),
+		},
+		Schedule: Schedule{Spacing: 30 * time.Minute},
+		Clock:    fake,
+	}
+	if err := c.RunQueue(context.Background(), false); err != nil {
+		t.Fatalf("RunQueue() error = %v", err)
+	}
+	if !slices.Equal(uploaded, []string{"10"}) {
+		t.Fatalf("uploaded groups %v, want only the first before the spacing runs out", uploaded)
+	}
+
+	fake.Advance(30 * time.Minute)
+	if err := c.RunQueue(context.Background(), false); err != nil {
+		t.Fatalf("RunQueue() error = %v", err)
+	}
+	if !slices.Equal(uploaded, []string{"10", "11"}) {
+		t.Errorf("uploaded groups %v, want both", uploaded)
+	}
+	queue, err := state.LoadUploadQueue()
+	if err != nil || len(queue.Pending) != 0 || !queue.LastSubmitted.Equal(fake.Now()) {
+		t.Errorf("queue after run = %+v, %v; want it empty, last submitted now", queue, err)
+	}
+	ledger, _ := state.LoadUploads()
+	if len(ledger) != 2 || !ledger[1].Uploaded || ledger[1].Dir != "/music/B" {
+		t.Errorf("ledger = %+v, want both uploads recorded", ledger)
+	}
+}
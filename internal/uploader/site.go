@@ -0,0 +1,40 @@
+package uploader
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TorrentSite is a tracker to build a .torrent for: its announce URL and the
+// info "source" field that gives its torrent of the same files a distinct info
+// hash, so the files can be cross-seeded.
+type TorrentSite struct {
+	Name     string // Profile name, e.g. "ops"
+	Announce string
+	Source   string // "" for no source field
+}
+
+// RedactedSite is the site uploads go to when UploadCommand.Site is unset.
+var RedactedSite = TorrentSite{Name: "red", Announce: "https://flacsfor.me/announce", Source: "RED"}
+
+// site returns the tracker the uploaded .torrent is built for.
+func (c *UploadCommand) site() TorrentSite {
+	if c.Site.Announce == "" {
+		return RedactedSite
+	}
+	return c.Site
+}
+
+// torrentFileName names the cached .torrent built for site, one per source so
+// each site's torrent is kept apart, and one with extras apart from the one
+// without.
+func (c *UploadCommand) torrentFileName(site TorrentSite) string {
+	name := fmt.Sprintf("torrent_%d", c.TorrentID)
+	if site.Source != "" {
+		name += "_" + strings.ToLower(site.Source)
+	}
+	if c.IncludeExtras {
+		name += "_extras"
+	}
+	return name + ".torrent"
+}
@@ -0,0 +1,194 @@
+package uploader
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+	"github.com/cehbz/classical-tagger/internal/validation"
+)
+
+// TrumpFindings summarizes what an upload fixes relative to the torrent it trumps.
+// It is the data passed to trump reason templates.
+type TrumpFindings struct {
+	Files        int      // FLAC files in the new upload
+	RenamedFiles int      // FLAC files whose path differs from the original torrent
+	AddedArtists int      // Artist credits in the new tags that the group page lacks
+	Rules        []string // IDs of rules the original file layout breaks and the upload satisfies
+}
+
+// Built-in trump reason templates, selectable by name.
+var trumpReasonTemplates = map[string]string{
+	"default": `{{if .RenamedFiles}}Renamed {{.RenamedFiles}} of {{.Files}} files{{else}}Retagged {{.Files}} files{{end}}` +
+		`{{if .AddedArtists}}, added {{.AddedArtists}} missing artist credits{{end}} per classical guidelines` +
+		`{{with .Rules}} (rules {{join . ", "}}){{end}}`,
+	"detailed": `{{.RenamedFiles}} of {{.Files}} files renamed, {{.AddedArtists}} artist credits added, ` +
+		`rules addressed: {{if .Rules}}{{join .Rules ", "}}{{else}}none{{end}}`,
+	"brief": `Corrected tags and filenames according to classical music guidelines`,
+}
+
+// ParseTrumpReasonTemplate returns the built-in template with the given name, or parses
+// s as a text/template over TrumpFindings. An empty s selects the default template.
+// Templates may use {{join .Rules ", "}} to list rule IDs.
+func ParseTrumpReasonTemplate(s string) (*template.Template, error) {
+	if s == "" {
+		s = "default"
+	}
+	if builtin, ok := trumpReasonTemplates[s]; ok {
+		s = builtin
+	}
+	tmpl, err := template.New("trump_reason").
+		Funcs(template.FuncMap{"join": strings.Join}).
+		Option("missingkey=error").
+		Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trump reason template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// analyzeTrump compares the local torrent with the original's file list and group credits.
+func analyzeTrump(original *Torrent, redactedArtists []domain.Artist, local *domain.Torrent) TrumpFindings {
+	var findings TrumpFindings
+
+	localTracks := local.Tracks()
+	findings.Files = len(localTracks)
+
+	// File renames: pair original and local FLACs in sorted order when the counts agree,
+	// otherwise count original paths that no longer exist
+	originalPaths := parseFileList(original.FileList)
+	localPaths := make([]string, len(localTracks))
+	for i, track := range localTracks {
+		localPaths[i] = normalize.NFC(filepath.ToSlash(track.Path))
+	}
+	paired := len(originalPaths) == len(localPaths)
+	if paired {
+		sorted := slices.Clone(localPaths)
+		slices.Sort(sorted)
+		for i := range originalPaths {
+			if originalPaths[i] != sorted[i] {
+				findings.RenamedFiles++
+			}
+		}
+	} else {
+		for _, p := range originalPaths {
+			if !slices.Contains(localPaths, p) {
+				findings.RenamedFiles++
+			}
+		}
+	}
+
+	// Artist credits missing from the group page
+	onRedacted := make(map[string]bool)
+	for _, a := range redactedArtists {
+		onRedacted[normalize.Name(a.Name)] = true
+	}
+	added := make(map[string]bool)
+	for _, a := range allArtists(local) {
+		key := normalize.Name(a.Name)
+		if !onRedacted[key] && !added[key] {
+			added[key] = true
+			findings.AddedArtists++
+		}
+	}
+
+	// Rules addressed: validate the local tags laid out under the original paths.
+	// Only filename and directory rules can differ, since the tags are the same.
+	if paired && findings.RenamedFiles > 0 {
+		failing := func(issues []domain.ValidationIssue) map[string]bool {
+			rules := make(map[string]bool)
+			for _, issue := range issues {
+				if issue.Level != domain.LevelInfo {
+					rules[issue.Rule] = true
+				}
+			}
+			return rules
+		}
+		before := failing(validation.Check(withOriginalPaths(local, original, originalPaths), nil))
+		after := failing(validation.Check(local, nil))
+		for rule := range before {
+			if !after[rule] {
+				findings.Rules = append(findings.Rules, rule)
+			}
+		}
+		slices.Sort(findings.Rules)
+	}
+
+	return findings
+}
+
+// withOriginalPaths returns a copy of local whose tracks, in path order, take the
+// original torrent's paths and whose root directory takes the original's name.
+func withOriginalPaths(local *domain.Torrent, original *Torrent, originalPaths []string) *domain.Torrent {
+	copied := *local
+	if original.FilePath != "" {
+		copied.RootPath = filepath.Join(filepath.Dir(local.RootPath), original.FilePath)
+	}
+
+	tracks := local.Tracks()
+	slices.SortFunc(tracks, func(a, b *domain.Track) int {
+		return strings.Compare(normalize.NFC(filepath.ToSlash(a.Path)), normalize.NFC(filepath.ToSlash(b.Path)))
+	})
+	copied.Files = make([]domain.FileLike, 0, len(local.Files))
+	for _, f := range local.Files {
+		if _, ok := f.(*domain.Track); !ok {
+			copied.Files = append(copied.Files, f)
+		}
+	}
+	for i, track := range tracks {
+		t := *track
+		t.Path = filepath.FromSlash(originalPaths[i])
+		copied.Files = append(copied.Files, &t)
+	}
+	return &copied
+}
+
+// allArtists returns album and track artists, album artists first.
+func allArtists(t *domain.Torrent) []domain.Artist {
+	artists := slices.Clone(t.AlbumArtist)
+	for _, track := range t.Tracks() {
+		artists = append(artists, track.Artists...)
+	}
+	return artists
+}
+
+// parseFileList returns the sorted FLAC paths from a Redacted file list
+// ("path{{{size}}}|||path{{{size}}}").
+func parseFileList(fileList string) []string {
+	var paths []string
+	for _, entry := range strings.Split(fileList, "}}}") {
+		entry = strings.TrimPrefix(entry, "|||")
+		path, _, found := strings.Cut(entry, "{{{")
+		if !found || !strings.HasSuffix(strings.ToLower(path), ".flac") {
+			continue
+		}
+		paths = append(paths, normalize.NFC(path))
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// generateTrumpReason renders the trump reason template with what this upload fixes.
+func (c *UploadCommand) generateTrumpReason(original *Torrent, redactedArtists []domain.Artist, local *domain.Torrent) (string, error) {
+	tmpl := c.TrumpReasonTemplate
+	if tmpl == nil {
+		var err error
+		if tmpl, err = ParseTrumpReasonTemplate(""); err != nil {
+			return "", err
+		}
+	}
+
+	findings := analyzeTrump(original, redactedArtists, local)
+	c.log("Trump findings: %d of %d files renamed, %d artist credits added, rules %v",
+		findings.RenamedFiles, findings.Files, findings.AddedArtists, findings.Rules)
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, findings); err != nil {
+		return "", fmt.Errorf("failed to render trump reason: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
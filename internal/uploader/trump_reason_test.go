@@ -0,0 +1,123 @@
+package uploader
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestParseFileList(t *testing.T) {
+	got := parseFileList("CD2/01 b.flac{{{200}}}|||cover.jpg{{{5}}}|||CD1/01 a.FLAC{{{100}}}")
+	want := []string{"CD1/01 a.FLAC", "CD2/01 b.flac"}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseFileList() = %q, want %q", got, want)
+	}
+	if got := parseFileList(""); len(got) != 0 {
+		t.Errorf("parseFileList(\"\") = %q, want none", got)
+	}
+}
+
+func trumpTestTorrent(paths ...string) *domain.Torrent {
+	composer := domain.Artist{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}
+	soloist := domain.Artist{Name: "Glenn Gould", Role: domain.RoleSoloist}
+	torrent := &domain.Torrent{
+		RootPath:     "/music/Bach - Goldberg Variations (1981) - FLAC",
+		Title:        "Goldberg Variations",
+		OriginalYear: 1981,
+		AlbumArtist:  []domain.Artist{soloist},
+	}
+	for i, p := range paths {
+		torrent.Files = append(torrent.Files, &domain.Track{
+			File:    domain.File{Path: p},
+			Disc:    1,
+			Track:   i + 1,
+			Title:   "Variation " + string(rune('1'+i)),
+			Artists: []domain.Artist{composer, soloist},
+		})
+	}
+	return torrent
+}
+
+func TestAnalyzeTrump(t *testing.T) {
+	local := trumpTestTorrent("01 Variation 1.flac", "02 Variation 2.flac")
+	redacted := []domain.Artist{{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}}
+
+	tests := []struct {
+		Name        string
+		FileList    string
+		WantRenamed int
+		WantRules   bool
+	}{
+		{
+			Name:     "same layout",
+			FileList: "01 Variation 1.flac{{{1}}}|||02 Variation 2.flac{{{2}}}",
+		},
+		{
+			Name:        "untitled files renamed",
+			FileList:    "Track1.flac{{{1}}}|||Track2.flac{{{2}}}",
+			WantRenamed: 2,
+			WantRules:   true,
+		},
+		{
+			Name:        "file count changed",
+			FileList:    "01 Variation 1.flac{{{1}}}|||02 Variation 2.flac{{{2}}}|||03 Variation 3.flac{{{3}}}",
+			WantRenamed: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			got := analyzeTrump(&Torrent{FileList: tt.FileList}, redacted, local)
+			if got.Files != 2 || got.RenamedFiles != tt.WantRenamed || got.AddedArtists != 1 {
+				t.Errorf("analyzeTrump() = %+v, want 2 files, %d renamed, 1 artist added", got, tt.WantRenamed)
+			}
+			if (len(got.Rules) > 0) != tt.WantRules {
+				t.Errorf("analyzeTrump() rules = %v, want rules: %v", got.Rules, tt.WantRules)
+			}
+		})
+	}
+
+	// The local torrent is left untouched
+	if local.Tracks()[0].Path != "01 Variation 1.flac" {
+		t.Errorf("analyzeTrump() modified local paths: %q", local.Tracks()[0].Path)
+	}
+}
+
+func TestParseTrumpReasonTemplate(t *testing.T) {
+	findings := TrumpFindings{Files: 12, RenamedFiles: 12, AddedArtists: 2, Rules: []string{"2.3.11", "2.3.13"}}
+
+	tests := []struct {
+		Name     string
+		Template string
+		Findings TrumpFindings
+		Want     string
+	}{
+		{"default", "", findings, "Renamed 12 of 12 files, added 2 missing artist credits per classical guidelines (rules 2.3.11, 2.3.13)"},
+		{"default without renames", "default", TrumpFindings{Files: 3}, "Retagged 3 files per classical guidelines"},
+		{"detailed", "detailed", TrumpFindings{Files: 3, RenamedFiles: 1}, "1 of 3 files renamed, 0 artist credits added, rules addressed: none"},
+		{"brief", "brief", findings, "Corrected tags and filenames according to classical music guidelines"},
+		{"custom", "Fixed {{len .Rules}} rules: {{join .Rules \"/\"}}", findings, "Fixed 2 rules: 2.3.11/2.3.13"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			tmpl, err := ParseTrumpReasonTemplate(tt.Template)
+			if err != nil {
+				t.Fatalf("ParseTrumpReasonTemplate(%q) error = %v", tt.Template, err)
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, tt.Findings); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if b.String() != tt.Want {
+				t.Errorf("reason = %q, want %q", b.String(), tt.Want)
+			}
+		})
+	}
+
+	if _, err := ParseTrumpReasonTemplate("{{.Renamed"); err == nil {
+		t.Error("ParseTrumpReasonTemplate() accepted an unterminated action")
+	}
+}
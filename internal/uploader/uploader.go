@@ -4,29 +4,105 @@ package uploader
 import (
 	"context"
 	"fmt"
+	"html"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"text/template"
+	"unicode"
 
+	"github.com/cehbz/classical-tagger/internal/artwork"
 	"github.com/cehbz/classical-tagger/internal/cache"
+	"github.com/cehbz/classical-tagger/internal/clock"
+	"github.com/cehbz/classical-tagger/internal/console"
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/filesystem"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 	"github.com/cehbz/classical-tagger/internal/tagging"
 )
 
 // UploadCommand handles the upload workflow
 type UploadCommand struct {
-	Client      *RedactedClient
-	Cache       *cache.Cache // Reuse common cache implementation
-	TorrentDir  string
-	TorrentID   int
-	TrumpReason string
-	CacheDir    string
-	DryRun      bool
-	Verbose     bool
+	Client       *RedactedClient
+	Cache        *cache.Cache // Reuse common cache implementation
+	TorrentDir   string
+	TorrentID    int
+	TrumpReason  string
+	CacheDir     string
+	DryRun       bool
+	Verbose      bool
+	ConfirmGroup bool // Proceed even if the local title does not resemble the group name
+	RequestID    int  // Request to fill with the upload (0: none)
+	// ConfirmMastering trumps even when the files look like another mastering than
+	// the torrent's; the trump reason notes the differences
+	ConfirmMastering bool
+	// MetadataFile is curated metadata JSON to upload from instead of re-reading the files' tags,
+	// or a comma-separated list of a set's metadata file and disc metadata files
+	MetadataFile string
+	// SkipArtistSearch skips searching Redacted for existing spellings of artists new to the group
+	SkipArtistSearch bool
+	// WikiFile receives a suggested group description after a successful upload
+	// ("": group_<id>_wiki.txt in the working directory)
+	WikiFile string
+	// ArtistAliases maps artist spelling variants to canonical names (from config)
+	ArtistAliases domain.AliasTable
+	// TrumpReasonTemplate renders the reason when TrumpReason is empty (nil: built-in default)
+	TrumpReasonTemplate *template.Template
+	// Collages are the IDs of collages to add the group to after uploading
+	Collages []int
+	// NewEdition uploads to the torrent's group as a new edition instead of trumping
+	// the torrent; the remaster fields come from the local edition
+	NewEdition bool
+	// Media of the local files, filled into a new edition ("": detected from a rip
+	// log or cue sheet, else the trumped torrent's)
+	Media string
+	// IncludeExtras keeps extras folders (a bonus DVD or other video) in the built .torrent
+	IncludeExtras bool
+	// MaxArtists is the most artists credited on the upload form (0: all); minor
+	// soloists beyond it are only listed in the description
+	MaxArtists int
+	// Site is the tracker the uploaded .torrent is built for (zero: RedactedSite)
+	Site TorrentSite
+	// CrossSeed lists other trackers to build a .torrent for from the same files
+	CrossSeed []TorrentSite
+	// FS holds the cached .torrent files (nil: the operating system)
+	FS fsys.FS
+	// Ledger checks the upload against the local ledger of earlier uploads and
+	// dry runs, warning about duplicates, and records it there
+	Ledger bool
+	// Schedule defers the submission to an upload window or spaces it from the
+	// last one, queueing the prepared upload (zero: submit at once)
+	Schedule Schedule
+	// Clock tells the time for the schedule (nil: the system clock)
+	Clock clock.Clock
+	// Workers is how many threads mktorrent hashes pieces with (0 or 1: one)
+	Workers int
 }
 
+// checkScopes checks that the API key has the scopes the upload needs:
+// Torrent always, and Request to fill a request.
+func (c *UploadCommand) checkScopes(ctx context.Context) error {
+	scopes := []string{"Torrent"}
+	if c.RequestID > 0 {
+		scopes = append(scopes, "Request")
+	}
+	for _, scope := range scopes {
+		c.log("Checking the API key's %s scope...", scope)
+		if err := c.Client.CheckScope(ctx, scope); err != nil {
+			return fmt.Errorf("API key check failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// minGroupTitleSimilarity is the lowest title/group-name similarity accepted without ConfirmGroup
+const minGroupTitleSimilarity = 0.5
+
 // NewUploadCommand creates a new upload command
 func NewUploadCommand(apiKey string, torrentDir string, torrentID int) *UploadCommand {
 	// Use common cache implementation
@@ -45,6 +121,11 @@ func NewUploadCommand(apiKey string, torrentDir string, torrentID int) *UploadCo
 func (c *UploadCommand) Execute(ctx context.Context) error {
 	c.log("Starting upload workflow for torrent ID %d", c.TorrentID)
 
+	// Step 0: Refuse up front if the API key cannot upload, rather than after building the torrent
+	if err := c.checkScopes(ctx); err != nil {
+		return err
+	}
+
 	// Step 1: Fetch metadata from Redacted
 	c.log("Fetching torrent metadata...")
 	torrentMeta, err := c.fetchTorrentMetadata(ctx)
@@ -65,60 +146,245 @@ func (c *UploadCommand) Execute(ctx context.Context) error {
 		return fmt.Errorf("failed to load local torrent: %w", err)
 	}
 
+	// Step 2b: Make artist spellings consistent ("The English Concert" vs "English Concert"),
+	// adopting Redacted's spelling so uploads don't create duplicate artist pages
+	redactedArtists := c.combineArtists(groupMeta)
+	aliases := maps.Clone(c.ArtistAliases)
+	if aliases == nil {
+		aliases = domain.AliasTable{}
+	}
+	for _, a := range redactedArtists {
+		aliases.Prefer(a.Name)
+	}
+	for _, note := range localTorrent.NormalizeArtistNames(aliases) {
+		c.log("Normalized artist name %s", note)
+	}
+
 	// Step 3: Validate that local artists are a superset of Redacted artists
 	c.log("Validating artist consistency...")
 	allLocalArtists := c.collectAllLocalArtists(localTorrent)
-	redactedArtists := c.combineArtists(groupMeta)
+	if !c.SkipArtistSearch {
+		for _, warning := range c.findExistingArtists(ctx, allLocalArtists, redactedArtists) {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+	}
 	validationErrors := c.validateArtistsSuperset(redactedArtists, allLocalArtists)
+	validationErrors = append(validationErrors, validateTrackTitles(localTorrent)...)
 
 	if len(validationErrors) > 0 {
 		for _, e := range validationErrors {
 			fmt.Fprintf(os.Stderr, "Validation error: %v\n", e)
 		}
 		if !c.DryRun {
-			return fmt.Errorf("validation failed with %d errors", len(validationErrors))
+			return fmt.Errorf("%w with %d errors", domain.ErrValidation, len(validationErrors))
 		}
 		c.log("Dry run mode - continuing despite validation errors")
 	}
 
+	// Step 3b: Guard against trumping into the wrong group (e.g. a mistyped torrent ID)
+	c.log("Checking album title against group name...")
+	if err := c.validateGroupMatch(localTorrent, groupMeta); err != nil {
+		if !c.DryRun {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Validation error: %v\n", err)
+		c.log("Dry run mode - continuing despite group mismatch")
+	}
+
+	// Step 3c: A trump must be the same edition; a contradiction means a different release
+	if !c.NewEdition {
+		c.log("Checking local edition against the torrent's remaster fields...")
+		if editionErrors := checkEdition(localTorrent.Edition, torrentMeta); len(editionErrors) > 0 {
+			for _, e := range editionErrors {
+				fmt.Fprintf(os.Stderr, "Edition error: %v\n", e)
+			}
+			if !c.DryRun {
+				return domain.Classify(fmt.Errorf("local edition contradicts the edition of torrent %d (%d differences); upload with -new-edition if it is a different release", c.TorrentID, len(editionErrors)), domain.ErrValidation)
+			}
+			c.log("Dry run mode - continuing despite edition differences")
+		}
+	} else if torrentMeta.Remastered && localTorrent.Edition != nil && len(checkEdition(localTorrent.Edition, torrentMeta)) == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: local edition matches the edition of torrent %d; trump it rather than adding a new edition\n", c.TorrentID)
+	}
+
+	// Step 3d: A CD rip cannot trump a WEB torrent (or the reverse); other media are other editions
+	media, evidence := c.detectMedia(localTorrent)
+	if c.Media != "" {
+		media, evidence = c.Media, "given with -media"
+	}
+	if !c.NewEdition {
+		c.log("Checking local media against the torrent's...")
+		warning, err := c.checkMedia(media, evidence, torrentMeta)
+		if warning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+		if err != nil {
+			if !c.DryRun {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Validation error: %v\n", err)
+			c.log("Dry run mode - continuing despite media mismatch")
+		}
+	}
+
+	// Step 3e: A trump replaces the same audio; sizes, bit depth or track lengths far from
+	// the torrent's suggest another remaster
+	var masteringNote string
+	if !c.NewEdition {
+		c.log("Comparing the files with the torrent's file list...")
+		if differences := compareMastering(c.localMasteringFiles(localTorrent), torrentMeta); len(differences) > 0 {
+			for _, d := range differences {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", d)
+			}
+			switch {
+			case c.ConfirmMastering:
+				masteringNote = "Note: differs from the trumped torrent (" + strings.Join(differences, "; ") + ")"
+			case !c.DryRun:
+				return domain.Classify(fmt.Errorf("files look like a different mastering from torrent %d; check the edition, upload with -new-edition, or pass --confirm-mastering to trump it anyway", c.TorrentID), domain.ErrValidation)
+			default:
+				c.log("Dry run mode - continuing despite mastering differences")
+			}
+		}
+	}
+
 	// Step 4: Merge metadata
 	c.log("Merging metadata...")
 	trumpReason := c.TrumpReason
-	if trumpReason == "" {
-		trumpReason = c.generateTrumpReason(localTorrent)
+	if trumpReason == "" && !c.NewEdition {
+		trumpReason, err = c.generateTrumpReason(torrentMeta, redactedArtists, localTorrent)
+		if err != nil {
+			return err
+		}
+	}
+	if masteringNote != "" {
+		trumpReason += ". " + masteringNote
 	}
 
 	merged := c.mergeMetadata(torrentMeta, groupMeta, localTorrent, trumpReason)
+	if c.NewEdition {
+		if err := applyLocalEdition(merged, localTorrent); err != nil {
+			return err
+		}
+		if media != "" {
+			merged.Media = media
+		}
+	}
+	if len(merged.OmittedArtists) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: crediting %d of %d artists; the other %d are only listed in the description (see --max-artists)\n",
+			len(merged.Artists), len(merged.Artists)+len(merged.OmittedArtists), len(merged.OmittedArtists))
+	}
+	if merged.Lineage.Found() {
+		c.log("Found lineage in original description (ripper %q, rip date %q)", merged.Lineage.Ripper, merged.Lineage.RipDate)
+	} else if merged.Media == "CD" && !c.NewEdition {
+		fmt.Fprintf(os.Stderr, "Warning: original description has no rip lineage (EAC/XLD log, rip date) for CD media\n")
+	}
+
+	// Step 4b: Stereo and surround mixes are separate editions
+	warning, err := checkChannelEdition(localTorrent, merged)
+	if warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if err != nil {
+		if !c.DryRun {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Validation error: %v\n", err)
+		c.log("Dry run mode - continuing despite channel layout mismatch")
+	}
+
+	// Step 4c: Artwork problems are a common reason uploads get flagged
+	for _, issue := range artwork.Check(nil, c.TorrentDir) {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", issue.Message)
+	}
 
 	// Step 5: Validate required fields
 	if err := c.validateRequiredFields(merged); err != nil {
-		return fmt.Errorf("required field validation failed: %w", err)
+		return domain.Classify(fmt.Errorf("required field validation failed: %w", err), domain.ErrValidation)
+	}
+
+	// Step 5b: Check the upload satisfies the request being filled
+	if c.RequestID > 0 {
+		c.log("Fetching request %d...", c.RequestID)
+		req, err := c.Client.GetRequest(ctx, c.RequestID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch request: %w", err)
+		}
+		if requestErrors := c.checkRequest(req, merged); len(requestErrors) > 0 {
+			for _, e := range requestErrors {
+				fmt.Fprintf(os.Stderr, "Request error: %v\n", e)
+			}
+			if !c.DryRun {
+				return domain.Classify(fmt.Errorf("upload does not satisfy request %d (%d errors)", c.RequestID, len(requestErrors)), domain.ErrValidation)
+			}
+			c.log("Dry run mode - continuing despite request errors")
+		}
+		merged.RequestID = c.RequestID
+	}
+
+	// Step 5c: Warn about trumping the same torrent twice or seeding an edition twice
+	if c.Ledger {
+		c.checkLedger(merged)
 	}
 
 	// Step 6: Create torrent file
 	c.log("Creating torrent file...")
-	torrentPath, err := c.createTorrentFile(ctx, c.TorrentDir, "https://flacsfor.me/announce")
+	torrentPath, err := c.createTorrentFile(ctx, c.TorrentDir, c.site())
 	if err != nil {
 		return fmt.Errorf("failed to create torrent file: %w", err)
 	}
+	crossSeeds := make([]string, len(c.CrossSeed))
+	for i, site := range c.CrossSeed {
+		if crossSeeds[i], err = c.createTorrentFile(ctx, c.TorrentDir, site); err != nil {
+			return fmt.Errorf("failed to create %s torrent file: %w", site.Name, err)
+		}
+	}
 
 	// Step 7: Upload (or dry run)
 	if c.DryRun {
 		c.log("Dry run mode - would upload with the following metadata:")
 		c.printMergedMetadata(merged)
+		c.log("Would write a suggested group description to %s", c.wikiFile(groupMeta))
+		c.updateCollages(ctx, groupMeta.ID)
+		c.printCrossSeeds(crossSeeds)
+		if !c.Schedule.IsZero() {
+			c.log("Would queue the upload for the schedule")
+		}
+		if c.Ledger {
+			c.recordLedger(merged)
+		}
 		return nil
 	}
 
-	c.log("Uploading torrent...")
 	uploadReq := c.prepareUploadRequest(merged)
+	if !c.Schedule.IsZero() {
+		c.writeWikiSuggestion(groupMeta, localTorrent)
+		c.printCrossSeeds(crossSeeds)
+		return c.queueUpload(ctx, uploadReq, torrentPath, merged)
+	}
+
+	c.log("Uploading torrent...")
 	if err := c.Client.Upload(ctx, uploadReq, torrentPath); err != nil {
 		return fmt.Errorf("upload failed: %w", err)
 	}
 
 	c.log("Upload successful!")
+	if c.Ledger {
+		c.recordLedger(merged)
+	}
+	c.writeWikiSuggestion(groupMeta, localTorrent)
+	c.updateCollages(ctx, groupMeta.ID)
+	c.printCrossSeeds(crossSeeds)
 	return nil
 }
 
+// printCrossSeeds lists the .torrent files built for the CrossSeed sites.
+func (c *UploadCommand) printCrossSeeds(paths []string) {
+	for i, path := range paths {
+		site := c.CrossSeed[i]
+		console.Resultf(path, "%s torrent (source %q) for cross-seeding: %s\n", site.Name, site.Source, path)
+	}
+}
+
 // fetchTorrentMetadata fetches torrent metadata with caching
 func (c *UploadCommand) fetchTorrentMetadata(ctx context.Context) (*Torrent, error) {
 	cacheKey := fmt.Sprintf("torrent_%d", c.TorrentID)
@@ -161,8 +427,13 @@ func (c *UploadCommand) fetchGroupMetadata(ctx context.Context, groupID int) (*T
 	return meta, nil
 }
 
-// loadLocalTorrent loads metadata from the local torrent directory
+// loadLocalTorrent loads metadata from MetadataFile when set, otherwise from
+// the tags of the files in the local torrent directory
 func (c *UploadCommand) loadLocalTorrent() (*domain.Torrent, error) {
+	if c.MetadataFile != "" {
+		return c.loadMetadataFile()
+	}
+
 	// Try to load from extracted JSON files
 	torrent := &domain.Torrent{
 		RootPath: c.TorrentDir,
@@ -208,6 +479,13 @@ func (c *UploadCommand) extractFromFLACs(torrent *domain.Torrent) error {
 				return nil // Continue with other files
 			}
 
+			if channels, err := tagging.ReadChannelCount(path); err == nil {
+				track.Channels = channels
+			}
+			if duration, err := tagging.ReadDuration(path); err == nil {
+				track.Duration = duration
+			}
+
 			// Parse composers (may be comma-separated) - ToTrack only gets first one
 			// Replace the single composer from ToTrack with all composers
 			trackArtists := make([]domain.Artist, 0)
@@ -357,6 +635,66 @@ func (c *UploadCommand) validateArtistsSuperset(redacted []domain.Artist, local
 	return errors
 }
 
+// validateTrackTitles rejects empty and placeholder ("Track 01", "Unknown") track titles,
+// which moderators treat as missing tags.
+func validateTrackTitles(local *domain.Torrent) []error {
+	var errors []error
+	for _, track := range local.PlaceholderTitleTracks() {
+		if strings.TrimSpace(track.Title) == "" {
+			errors = append(errors, fmt.Errorf("disc %d track %d (%s) has no title", track.Disc, track.Track, track.File.Path))
+			continue
+		}
+		errors = append(errors, fmt.Errorf("disc %d track %d (%s) has placeholder title %q", track.Disc, track.Track, track.File.Path, track.Title))
+	}
+	return errors
+}
+
+// validateGroupMatch checks that the local album title resembles the Redacted group name.
+// A low similarity usually means the torrent ID points at a different release group.
+func (c *UploadCommand) validateGroupMatch(local *domain.Torrent, group *TorrentGroup) error {
+	similarity := titleSimilarity(local.Title, html.UnescapeString(group.Name))
+	c.log("Title similarity to group %q: %.2f", group.Name, similarity)
+	if similarity >= minGroupTitleSimilarity {
+		return nil
+	}
+	if c.ConfirmGroup {
+		c.log("Group mismatch confirmed by user, continuing")
+		return nil
+	}
+	return domain.Classify(fmt.Errorf("local title %q does not match group %q (similarity %.2f); check the torrent ID or pass --confirm-group",
+		local.Title, group.Name, similarity), domain.ErrValidation)
+}
+
+// titleSimilarity returns the Dice coefficient of the normalized word sets of two titles (0.0-1.0).
+func titleSimilarity(a, b string) float64 {
+	wordsA := titleWords(a)
+	wordsB := titleWords(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+
+	common := 0
+	for w := range wordsA {
+		if _, ok := wordsB[w]; ok {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(wordsA)+len(wordsB))
+}
+
+// titleWords splits a title into a set of lowercase alphanumeric words. The
+// title is composed to NFC first, so the combining marks of a decomposed
+// "Dvořák" don't split it.
+func titleWords(title string) map[string]struct{} {
+	words := make(map[string]struct{})
+	for _, w := range strings.FieldsFunc(normalize.Key(title, 0), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		words[w] = struct{}{}
+	}
+	return words
+}
+
 // rolesCompatible checks if two roles are compatible (allows some flexibility)
 func (c *UploadCommand) rolesCompatible(redactedRole, localRole domain.Role) bool {
 	// Exact match
@@ -377,20 +715,23 @@ func (c *UploadCommand) rolesCompatible(redactedRole, localRole domain.Role) boo
 
 // mergeMetadata merges all metadata sources
 // Uses local artists for upload (local is superset of Redacted)
-func (c *UploadCommand) mergeMetadata(torrent *Torrent, _ *TorrentGroup, local *domain.Torrent, trumpReason string) *Metadata {
-	// Collect all local artists (flat list)
-	allLocalArtistsMap := c.collectAllLocalArtists(local)
-	allLocalArtists := make([]domain.Artist, 0, len(allLocalArtistsMap))
-	for a := range allLocalArtistsMap {
-		allLocalArtists = append(allLocalArtists, a)
+func (c *UploadCommand) mergeMetadata(torrent *Torrent, group *TorrentGroup, local *domain.Torrent, trumpReason string) *Metadata {
+	// All local artists, in conventional order (soloists, ensembles, conductor, ...),
+	// up to MaxArtists of them
+	artists := domain.OrderArtists(allArtists(local))
+	var credited []domain.Artist
+	if group != nil {
+		credited = group.DomainArtists()
 	}
+	kept, omitted := capArtists(artists, local, credited, c.MaxArtists)
 
 	merged := &Metadata{
 		// From local/extracted
 		Title: local.Title,
 		Year:  local.OriginalYear,
 
-		Artists: allLocalArtists,
+		Artists:        kept,
+		OmittedArtists: omitted,
 
 		// From Redacted torrent
 		Format:    torrent.Format,
@@ -417,8 +758,37 @@ func (c *UploadCommand) mergeMetadata(torrent *Torrent, _ *TorrentGroup, local *
 		merged.CatalogNumber = local.Edition.CatalogNumber
 	}
 
+	// Carry over the rip lineage; the rest of the old description describes the old tags.
+	// Without recognizable lineage the description is kept as is. A new edition trumps
+	// nothing, and the other torrent's lineage is not its rip's.
+	if c.NewEdition {
+		merged.TorrentID = 0
+	} else {
+		merged.Lineage = ParseLineage(torrent.Description)
+		merged.Description = torrent.Description
+		if merged.Lineage.Found() {
+			merged.Description = merged.Lineage.Description()
+		}
+	}
+
+	// List performers with their instruments when any are known, and always when
+	// some are missing from the artist credits
+	if block := performersBlock(artists, len(omitted) > 0); block != "" && merged.Description != "" {
+		merged.Description += "\n\n" + block
+	} else if block != "" {
+		merged.Description = block
+	}
+
+	// Opera releases list the cast, character by character
+	if cast := domain.CastList(artists); len(cast) > 0 {
+		block := "[b]Cast:[/b]\n" + strings.Join(cast, "\n")
+		if merged.Description != "" {
+			block = merged.Description + "\n\n" + block
+		}
+		merged.Description = block
+	}
+
 	// Append trump reason to description
-	merged.Description = torrent.Description
 	if trumpReason != "" {
 		merged.Description += "\n\n[Trump Upload] Fixed: " + trumpReason
 	}
@@ -426,10 +796,14 @@ func (c *UploadCommand) mergeMetadata(torrent *Torrent, _ *TorrentGroup, local *
 	return merged
 }
 
-// generateTrumpReason generates an automatic trump reason
-func (c *UploadCommand) generateTrumpReason(_ *domain.Torrent) string {
-	// TODO: Analyze what was fixed based on validation results
-	return "Corrected tags and filenames according to classical music guidelines"
+// performersBlock returns the description's performer list, soloists grouped by
+// instrument ("Martha Argerich, Nelson Freire (piano)"), or "" when no instrument is
+// known and the list would only repeat the artist credits, unless always is set.
+func performersBlock(artists []domain.Artist, always bool) string {
+	if !always && !slices.ContainsFunc(artists, func(a domain.Artist) bool { return a.Instrument != "" }) {
+		return ""
+	}
+	return "[b]Performers:[/b]\n" + strings.Join(domain.PerformerCredits(artists), "\n")
 }
 
 // validateRequiredFields checks all required fields are present
@@ -490,6 +864,8 @@ func (c *UploadCommand) prepareUploadRequest(meta *Metadata) *Upload {
 
 		TrumpTorrent: meta.TorrentID,
 		TrumpReason:  meta.TrumpReason,
+
+		RequestID: meta.RequestID,
 	}
 
 	// Convert artists to string arrays with importance values
@@ -503,23 +879,47 @@ func (c *UploadCommand) prepareUploadRequest(meta *Metadata) *Upload {
 	return req
 }
 
-// createTorrentFile creates a .torrent file
-func (c *UploadCommand) createTorrentFile(ctx context.Context, sourceDir string, announceURL string) (string, error) {
-	// Check cache first
-	torrentPath := filepath.Join(c.CacheDir, fmt.Sprintf("torrent_%d.torrent", c.TorrentID))
-	if _, err := os.Stat(torrentPath); err == nil {
+// createTorrentFile creates a .torrent file for site
+func (c *UploadCommand) createTorrentFile(ctx context.Context, sourceDir string, site TorrentSite) (string, error) {
+	var extras []string
+	if !c.IncludeExtras {
+		var err error
+		if extras, err = filesystem.ScanExtrasFolders(c.FS, sourceDir); err != nil {
+			return "", fmt.Errorf("failed to classify folders: %w", err)
+		}
+	}
+
+	// mktorrent packages whatever it finds, so refuse before it runs, or before
+	// a cached torrent of files since found disallowed is reused
+	if err := checkDisallowedFiles(c.FS, sourceDir, extras); err != nil {
+		return "", err
+	}
+
+	torrentPath := filepath.Join(c.CacheDir, c.torrentFileName(site))
+	if _, err := fsys.Or(c.FS).Stat(torrentPath); err == nil {
 		c.log("Using cached torrent file")
 		return torrentPath, nil
 	}
 
+	if len(extras) > 0 {
+		c.log("Leaving extras out of the torrent: %s", strings.Join(extras, ", "))
+		staged, cleanup, err := stageWithout(sourceDir, extras)
+		if err != nil {
+			return "", fmt.Errorf("failed to stage torrent contents: %w", err)
+		}
+		defer cleanup()
+		sourceDir = staged
+	}
+
+	// A dry run neither builds nor caches a torrent
+	if c.DryRun {
+		c.log("Dry run mode - would create %s with mktorrent", torrentPath)
+		return torrentPath, nil
+	}
+
 	// Create torrent using mktorrent
-	cmd := exec.CommandContext(ctx, "mktorrent",
-		"-p",       // Private torrent
-		"-l", "18", // Piece length 2^18 = 256KB
-		"-a", announceURL, // Announce URL
-		"-o", torrentPath, // Output file
-		sourceDir, // Source directory
-	)
+	args := c.mktorrentArgs(torrentPath, site)
+	cmd := exec.CommandContext(ctx, "mktorrent", append(args, sourceDir)...)
 
 	if c.Verbose {
 		cmd.Stdout = os.Stdout
@@ -533,8 +933,78 @@ func (c *UploadCommand) createTorrentFile(ctx context.Context, sourceDir string,
 	return torrentPath, nil
 }
 
-// printMergedMetadata prints metadata for dry run
+// mktorrentArgs returns the mktorrent options, before the source directory,
+// that build torrentPath for site.
+func (c *UploadCommand) mktorrentArgs(torrentPath string, site TorrentSite) []string {
+	args := []string{
+		"-p",       // Private torrent
+		"-l", "18", // Piece length 2^18 = 256KB
+		"-a", site.Announce, // Announce URL
+		"-o", torrentPath, // Output file
+	}
+	if site.Source != "" {
+		args = append(args, "-s", site.Source) // Source field, distinct per site
+	}
+	if c.Workers > 1 {
+		args = append(args, "-t", strconv.Itoa(c.Workers)) // Hashing threads
+	}
+	return args
+}
+
+// checkDisallowedFiles lists the files under sourceDir, outside the skipped
+// top-level folders, that must not be uploaded (lossy audio mixed with FLAC,
+// archives, executables, nested torrents) and returns an error if there are any.
+func checkDisallowedFiles(files fsys.FS, sourceDir string, skip []string) error {
+	disallowed, err := filesystem.FindDisallowedFiles(files, sourceDir, skip)
+	if err != nil {
+		return fmt.Errorf("failed to scan torrent contents: %w", err)
+	}
+	for _, f := range disallowed {
+		fmt.Fprintf(os.Stderr, "Disallowed file: %s (%s)\n", f.Path, f.Reason)
+	}
+	if len(disallowed) > 0 {
+		return domain.Classify(fmt.Errorf("%d disallowed files in %s; remove them before uploading", len(disallowed), sourceDir), domain.ErrValidation)
+	}
+	return nil
+}
+
+// stageWithout links every top-level entry of sourceDir except the excluded ones
+// into a temporary directory of the same name, so that mktorrent (which follows
+// symbolic links) builds a torrent with the same name but without them. The
+// returned func removes the staging directory.
+func stageWithout(sourceDir string, excluded []string) (string, func(), error) {
+	entries, err := os.ReadDir(sourceDir)
+	if err != nil {
+		return "", nil, err
+	}
+	tmp, err := os.MkdirTemp("", "classical-tagger-stage-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+
+	staged := filepath.Join(tmp, filepath.Base(sourceDir))
+	if err := os.Mkdir(staged, 0755); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	for _, entry := range entries {
+		if slices.Contains(excluded, entry.Name()) {
+			continue
+		}
+		if err := os.Symlink(filepath.Join(sourceDir, entry.Name()), filepath.Join(staged, entry.Name())); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+	return staged, cleanup, nil
+}
+
+// printMergedMetadata prints metadata for dry run, unless quiet
 func (c *UploadCommand) printMergedMetadata(meta *Metadata) {
+	if console.Quiet() {
+		return
+	}
 	fmt.Printf("\n=== Upload Metadata ===\n")
 	fmt.Printf("Title: %s\n", meta.Title)
 	fmt.Printf("Year: %d\n", meta.Year)
@@ -552,7 +1022,7 @@ func (c *UploadCommand) printMergedMetadata(meta *Metadata) {
 	// Group by role for display
 	byRole := make([][]string, domain.RoleMax+1)
 	for _, a := range meta.Artists {
-		byRole[int(a.Role)] = append(byRole[int(a.Role)], a.Name)
+		byRole[int(a.Role)] = append(byRole[int(a.Role)], a.Credit())
 	}
 
 	for role, artists := range byRole {
@@ -574,14 +1044,28 @@ func (c *UploadCommand) printMergedMetadata(meta *Metadata) {
 		}
 	}
 
+	if len(meta.OmittedArtists) > 0 {
+		fmt.Printf("\nOmitted from the artist credits (%d, listed in the description's performers):\n", len(meta.OmittedArtists))
+		for _, a := range meta.OmittedArtists {
+			fmt.Printf("  - %s (%s)\n", a.Credit(), a.Role)
+		}
+	}
+
 	fmt.Printf("\nTags: %s\n", strings.Join(meta.Tags, ", "))
-	fmt.Printf("\nTrump Reason: %s\n", meta.TrumpReason)
+	if meta.TrumpReason != "" {
+		fmt.Printf("\nTrump Reason: %s\n", meta.TrumpReason)
+	} else {
+		fmt.Printf("\nNew Edition: %d %s / %s / %s\n", meta.RemasterYear, meta.RemasterRecordLabel, meta.RemasterCatalogueNumber, meta.RemasterTitle)
+	}
+	if meta.RequestID > 0 {
+		fmt.Printf("Fills Request: %d\n", meta.RequestID)
+	}
 	fmt.Printf("\nDescription:\n%s\n", meta.Description)
 }
 
 // log logs a message if verbose mode is enabled
 func (c *UploadCommand) log(format string, args ...any) {
 	if c.Verbose {
-		fmt.Printf("[UPLOAD] "+format+"\n", args...)
+		fmt.Fprintf(os.Stderr, "[UPLOAD] "+format+"\n", args...)
 	}
 }
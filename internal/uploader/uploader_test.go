@@ -3,16 +3,20 @@ package uploader
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
 	"github.com/cehbz/classical-tagger/internal/ratelimit"
+	"golang.org/x/text/unicode/norm"
 )
 
 func TestRedactedClient_GetTorrent(t *testing.T) {
@@ -99,10 +103,10 @@ func TestRedactedClient_GetTorrent(t *testing.T) {
 					t.Errorf("expected action=torrent, got %s", r.URL.Query().Get("action"))
 				}
 
-				w.WriteHeader(tt.statusCode)
 				if tt.statusCode == http.StatusTooManyRequests {
 					w.Header().Set("Retry-After", "5")
 				}
+				w.WriteHeader(tt.statusCode)
 				w.Write([]byte(tt.response))
 			}))
 			defer server.Close()
@@ -119,6 +123,10 @@ func TestRedactedClient_GetTorrent(t *testing.T) {
 				t.Errorf("GetTorrent() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			var rateLimited *ratelimit.ErrRateLimited
+			if tt.statusCode == http.StatusTooManyRequests && (!errors.As(err, &rateLimited) || rateLimited.RetryAfter != 5*time.Second) {
+				t.Errorf("GetTorrent() error = %v, want ErrRateLimited retrying after 5s", err)
+			}
 
 			if !tt.wantErr && tt.validateFunc != nil {
 				tt.validateFunc(t, result)
@@ -282,6 +290,22 @@ func TestUploadCommand_ValidateArtists(t *testing.T) {
 	}
 }
 
+func TestValidateTrackTitles(t *testing.T) {
+	local := &domain.Torrent{Files: []domain.FileLike{
+		&domain.Track{File: domain.File{Path: "01 Aria.flac"}, Disc: 1, Track: 1, Title: "Aria"},
+		&domain.Track{File: domain.File{Path: "02 Track 02.flac"}, Disc: 1, Track: 2, Title: "Track 02"},
+		&domain.Track{File: domain.File{Path: "03.flac"}, Disc: 1, Track: 3},
+	}}
+
+	errs := validateTrackTitles(local)
+	if len(errs) != 2 {
+		t.Fatalf("validateTrackTitles() = %v, want errors for tracks 2 and 3", errs)
+	}
+	if !strings.Contains(errs[0].Error(), `"Track 02"`) || !strings.Contains(errs[1].Error(), "no title") {
+		t.Errorf("validateTrackTitles() = %v", errs)
+	}
+}
+
 func TestUploadCommand_MergeMetadata(t *testing.T) {
 	torrentMeta := &Torrent{
 		GroupID:     98765,
@@ -361,7 +385,7 @@ func TestUploadCommand_CreateTorrentFile(t *testing.T) {
 		CacheDir: t.TempDir(),
 	}
 
-	torrentPath, err := cmd.createTorrentFile(context.Background(), tmpDir, "http://tracker.example.com/announce")
+	torrentPath, err := cmd.createTorrentFile(context.Background(), tmpDir, TorrentSite{Announce: "http://tracker.example.com/announce", Source: "TEST"})
 	if err != nil {
 		// We expect this to fail without mktorrent installed
 		if strings.Contains(err.Error(), "executable file not found") {
@@ -376,6 +400,108 @@ func TestUploadCommand_CreateTorrentFile(t *testing.T) {
 	}
 }
 
+func TestUploadCommand_MktorrentArgs(t *testing.T) {
+	site := TorrentSite{Announce: "http://tracker.example.com/announce", Source: "RED"}
+	want := "-p -l 18 -a http://tracker.example.com/announce -o /cache/t.torrent -s RED"
+
+	cmd := &UploadCommand{}
+	if got := strings.Join(cmd.mktorrentArgs("/cache/t.torrent", site), " "); got != want {
+		t.Errorf("mktorrentArgs() = %q, want %q", got, want)
+	}
+
+	// Pieces are hashed with the configured workers
+	cmd.Workers = 8
+	if got := strings.Join(cmd.mktorrentArgs("/cache/t.torrent", site), " "); got != want+" -t 8" {
+		t.Errorf("mktorrentArgs(8 workers) = %q, want %q", got, want+" -t 8")
+	}
+}
+
+func TestUploadCommand_CreateTorrentFileCached(t *testing.T) {
+	mem := fsys.NewMem()
+	mem.MkdirAll("/cache", 0755)
+	mem.WriteFile("/cache/torrent_42.torrent", []byte("d4:infoe"), 0644)
+	mem.MkdirAll("/music/album", 0755)
+	mem.WriteFile("/music/album/01 Kyrie.flac", []byte("x"), 0644)
+	cmd := &UploadCommand{CacheDir: "/cache", TorrentID: 42, FS: mem}
+
+	// The cached file is reused without running mktorrent
+	torrentPath, err := cmd.createTorrentFile(context.Background(), "/music/album", TorrentSite{Announce: "http://tracker.example.com/announce"})
+	if err != nil || torrentPath != "/cache/torrent_42.torrent" {
+		t.Errorf("createTorrentFile() = %q, %v; want the cached file", torrentPath, err)
+	}
+
+	// Each source has its own cached file
+	mem.WriteFile("/cache/torrent_42_ops.torrent", []byte("d4:infoe"), 0644)
+	torrentPath, err = cmd.createTorrentFile(context.Background(), "/music/album", TorrentSite{Announce: "https://home.opsfet.ch/announce", Source: "OPS"})
+	if err != nil || torrentPath != "/cache/torrent_42_ops.torrent" {
+		t.Errorf("createTorrentFile(OPS) = %q, %v; want the cached OPS file", torrentPath, err)
+	}
+
+	// A torrent with extras is cached apart from the one without
+	cmd.IncludeExtras = true
+	cmd.DryRun = true
+	torrentPath, err = cmd.createTorrentFile(context.Background(), "/music/album", TorrentSite{Announce: "http://tracker.example.com/announce"})
+	if err != nil || torrentPath != "/cache/torrent_42_extras.torrent" {
+		t.Errorf("createTorrentFile(extras) = %q, %v; want the extras file", torrentPath, err)
+	}
+	if _, err := mem.Stat(torrentPath); err == nil {
+		t.Errorf("dry run created %s", torrentPath)
+	}
+
+	// A disallowed file added since the torrent was cached is still refused
+	cmd.IncludeExtras, cmd.DryRun = false, false
+	mem.WriteFile("/music/album/01 Kyrie.mp3", []byte("x"), 0644)
+	if _, err := cmd.createTorrentFile(context.Background(), "/music/album", TorrentSite{Announce: "http://tracker.example.com/announce"}); err == nil || !strings.Contains(err.Error(), "1 disallowed files") {
+		t.Errorf("createTorrentFile() error = %v, want the cached torrent refused", err)
+	}
+
+	if got := cmd.site(); got != RedactedSite {
+		t.Errorf("site() = %+v, want RedactedSite when unset", got)
+	}
+}
+
+func TestStageWithout(t *testing.T) {
+	album := filepath.Join(t.TempDir(), "Bach - Mass in B minor (2010) [FLAC]")
+	for _, path := range []string{"CD1/01 Kyrie.flac", "CD2/01 Credo.flac", "Bonus DVD/VIDEO_TS/VTS_01_1.VOB", "folder.jpg"} {
+		full := filepath.Join(album, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	staged, cleanup, err := stageWithout(album, []string{"Bonus DVD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if filepath.Base(staged) != filepath.Base(album) {
+		t.Errorf("staged directory %q, want the album's name", staged)
+	}
+	entries, err := os.ReadDir(staged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if want := []string{"CD1", "CD2", "folder.jpg"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("staged entries = %v, want %v", names, want)
+	}
+	if _, err := os.Stat(filepath.Join(staged, "CD1", "01 Kyrie.flac")); err != nil {
+		t.Errorf("staged CD1 does not reach the album's files: %v", err)
+	}
+
+	cleanup()
+	if _, err := os.Stat(album); err != nil {
+		t.Errorf("cleanup removed the album: %v", err)
+	}
+}
+
 func TestUploadCommand_ValidateRequiredFields(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -470,3 +596,51 @@ func TestRateLimiter_Integration(t *testing.T) {
 		t.Errorf("third request didn't wait long enough: %v", elapsed)
 	}
 }
+
+func TestUploadCommand_ValidateGroupMatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		localTitle   string
+		groupName    string
+		confirmGroup bool
+		wantErr      bool
+	}{
+		{"identical", "Goldberg Variations", "Goldberg Variations", false, false},
+		{"case and punctuation", "Goldberg-Variations, BWV 988", "Goldberg Variations BWV 988", false, false},
+		{"html escaped group name", "Bach & Handel: Arias", "Bach &amp; Handel: Arias", false, false},
+		{"extra subtitle", "Symphony No. 9", "Symphony No. 9 (Live)", false, false},
+		{"decomposed accents", norm.NFD.String("Dvořák: Rusalka"), "Dvořák: Rusalka", false, false},
+		{"different release", "Goldberg Variations", "The Four Seasons", false, true},
+		{"different release confirmed", "Goldberg Variations", "The Four Seasons", true, false},
+		{"empty local title", "", "The Four Seasons", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &UploadCommand{ConfirmGroup: tt.confirmGroup}
+			err := cmd.validateGroupMatch(&domain.Torrent{Title: tt.localTitle}, &TorrentGroup{Name: tt.groupName})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateGroupMatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateTorrentFile_RefusesDisallowedFiles(t *testing.T) {
+	album := filepath.Join(t.TempDir(), "album")
+	for _, path := range []string{"01 Kyrie.flac", "01 Kyrie.mp3", "scans.rar"} {
+		full := filepath.Join(album, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &UploadCommand{CacheDir: t.TempDir(), TorrentID: 1}
+	_, err := c.createTorrentFile(context.Background(), album, RedactedSite)
+	if err == nil || !strings.Contains(err.Error(), "2 disallowed files") {
+		t.Errorf("createTorrentFile() error = %v, want 2 disallowed files", err)
+	}
+}
@@ -0,0 +1,152 @@
+package uploader
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/console"
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+// WikiSuggestion renders a group description (BBCode, for the site's group
+// editor) from the uploaded album's metadata: composers, performers, recording
+// and release details, and the tracklist, grouped by work with playing times.
+// Trumps often show the group's own description to be wrong too; the
+// suggestion is for pasting, never sent.
+func WikiSuggestion(local *domain.Torrent) string {
+	var b strings.Builder
+
+	composers := wikiComposers(local)
+	if len(composers) > 0 {
+		b.WriteString("[b]Composers:[/b]\n")
+		for _, name := range composers {
+			b.WriteString(name)
+			if lifetime, ok := domain.ComposerLifetime(name); ok {
+				b.WriteString(" (" + lifetime.String() + ")")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if performers := domain.PerformerCredits(allArtists(local)); len(performers) > 0 {
+		b.WriteString("[b]Performers:[/b]\n" + strings.Join(performers, "\n") + "\n\n")
+	}
+
+	if len(local.RecordingYears) > 0 {
+		fmt.Fprintf(&b, "[b]Recorded:[/b] %s\n", domain.FormatYears(local.RecordingYears))
+	}
+	if local.OriginalYear > 0 {
+		fmt.Fprintf(&b, "[b]Released:[/b] %d\n", local.OriginalYear)
+	}
+	if edition := local.Edition; edition != nil && edition.Label != "" {
+		release := edition.Label
+		if edition.CatalogNumber != "" {
+			release += " – " + edition.CatalogNumber
+		}
+		if edition.Year > 0 && edition.Year != local.OriginalYear {
+			release += fmt.Sprintf(" (%d)", edition.Year)
+		}
+		fmt.Fprintf(&b, "[b]Label:[/b] %s\n", release)
+	}
+
+	b.WriteString("\n[b]Tracklist:[/b]\n")
+	multiComposer := len(composers) > 1
+	disc := 0
+	for _, work := range local.Works() {
+		if first := work.Tracks[0]; local.IsMultiDisc() && first.Disc != disc {
+			disc = first.Disc
+			heading := fmt.Sprintf("Disc %d", disc)
+			if first.DiscSubtitle != "" {
+				heading += ": " + first.DiscSubtitle
+			}
+			fmt.Fprintf(&b, "\n[u]%s[/u]\n", heading)
+		}
+		composer := ""
+		if multiComposer && work.Composer != "" {
+			composer = work.Composer + ": "
+		}
+
+		// Movements are listed under their work, headed by its total playing time
+		if len(work.Tracks) > 1 {
+			fmt.Fprintf(&b, "[b]%s%s[/b]%s\n", composer, work.Title, durationSuffix(work.Duration()))
+			composer = ""
+		}
+		for _, track := range work.Tracks {
+			title := track.Title
+			if len(work.Tracks) > 1 {
+				title = work.Movement(track)
+			}
+			fmt.Fprintf(&b, "%02d. %s%s", track.Track, composer, title)
+			if len(track.RecordingYears) > 0 {
+				fmt.Fprintf(&b, " (rec. %s)", domain.FormatYears(track.RecordingYears))
+			}
+			b.WriteString(durationSuffix(track.Duration) + "\n")
+		}
+	}
+	return b.String()
+}
+
+// durationSuffix formats a playing time to follow a title, or "" when unknown.
+func durationSuffix(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return " – " + domain.FormatDuration(d)
+}
+
+// wikiComposers returns the album's composers in order of first appearance.
+func wikiComposers(local *domain.Torrent) []string {
+	var names []string
+	for _, artist := range allArtists(local) {
+		if artist.Role == domain.RoleComposer && artist.Name != "" && !slices.Contains(names, artist.Name) {
+			names = append(names, artist.Name)
+		}
+	}
+	return names
+}
+
+// wikiNotes lists what the group's current description gets wrong or leaves
+// out, judged against the uploaded album: composers it never names.
+func wikiNotes(group *TorrentGroup, local *domain.Torrent) []string {
+	if strings.TrimSpace(group.WikiBody) == "" {
+		return []string{"the group has no description"}
+	}
+	body := normalize.Key(group.WikiBody, normalize.FoldDiacritics)
+	var notes []string
+	for _, name := range wikiComposers(local) {
+		surname := domain.Artist{Name: name, Role: domain.RoleComposer}.LastName()
+		if !strings.Contains(body, normalize.Key(surname, normalize.FoldDiacritics)) {
+			notes = append(notes, fmt.Sprintf("the description does not mention %s", name))
+		}
+	}
+	return notes
+}
+
+// writeWikiSuggestion writes the suggested group description to WikiFile and
+// prints where the current one falls short. Failing to write it only warns:
+// the upload has already happened.
+func (c *UploadCommand) writeWikiSuggestion(group *TorrentGroup, local *domain.Torrent) {
+	path := c.wikiFile(group)
+	if err := fsys.Or(c.FS).WriteFile(path, []byte(WikiSuggestion(local)), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to write suggested group description: %v\n", err)
+		return
+	}
+	console.Resultf(path, "Suggested group description written to %s (edit at %s/torrents.php?action=editgroup&groupid=%d)\n", path, c.Client.BaseURL, group.ID)
+	for _, note := range wikiNotes(group, local) {
+		console.Infof("  Note: %s\n", note)
+	}
+}
+
+// wikiFile returns where the suggested group description goes.
+func (c *UploadCommand) wikiFile(group *TorrentGroup) string {
+	if c.WikiFile != "" {
+		return c.WikiFile
+	}
+	return fmt.Sprintf("group_%d_wiki.txt", group.ID)
+}
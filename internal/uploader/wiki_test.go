@@ -0,0 +1,71 @@
+package uploader
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestWikiSuggestion(t *testing.T) {
+	bach := domain.Artist{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}
+	beethoven := domain.Artist{Name: "Ludwig van Beethoven", Role: domain.RoleComposer}
+	busoni := domain.Artist{Name: "Ferruccio Busoni", Role: domain.RoleComposer}
+	pianist := domain.Artist{Name: "Hélène Grimaud", Role: domain.RoleSoloist, Instrument: "piano"}
+	local := &domain.Torrent{
+		Title:          "Bach – Busoni",
+		OriginalYear:   2008,
+		RecordingYears: []int{2007, 2008},
+		Edition:        &domain.Edition{Label: "Deutsche Grammophon", CatalogNumber: "477 7978", Year: 2008},
+		AlbumArtist:    []domain.Artist{pianist},
+		Files: []domain.FileLike{
+			&domain.Track{Disc: 1, Track: 1, Title: "Prelude and Fugue No. 2", Artists: []domain.Artist{bach, pianist}},
+			&domain.Track{Disc: 1, Track: 2, Title: "Chaconne", Artists: []domain.Artist{busoni, pianist}, RecordingYears: []int{2006}},
+		},
+	}
+
+	want := `[b]Composers:[/b]
+Johann Sebastian Bach (1685–1750)
+Ferruccio Busoni
+
+[b]Performers:[/b]
+Hélène Grimaud (piano)
+
+[b]Recorded:[/b] 2007-2008
+[b]Released:[/b] 2008
+[b]Label:[/b] Deutsche Grammophon – 477 7978
+
+[b]Tracklist:[/b]
+01. Johann Sebastian Bach: Prelude and Fugue No. 2
+02. Ferruccio Busoni: Chaconne (rec. 2006)
+`
+	if got := WikiSuggestion(local); got != want {
+		t.Errorf("WikiSuggestion() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Movements are grouped under their work, with the work's total playing time
+	symphony := &domain.Torrent{Files: []domain.FileLike{
+		&domain.Track{Disc: 1, Track: 1, Title: "Symphony No. 5 in C minor, Op. 67: I. Allegro con brio", Artists: []domain.Artist{beethoven}, Duration: 7*time.Minute + 20*time.Second},
+		&domain.Track{Disc: 1, Track: 2, Title: "Symphony No. 5 in C minor, Op. 67: II. Andante con moto", Artists: []domain.Artist{beethoven}, Duration: 9*time.Minute + 41*time.Second},
+		&domain.Track{Disc: 1, Track: 3, Title: "Egmont, Op. 84: Overture", Artists: []domain.Artist{beethoven}, Duration: 8 * time.Minute},
+	}}
+	wantTracklist := `[b]Tracklist:[/b]
+[b]Symphony No. 5 in C minor, Op. 67[/b] – 17:01
+01. I. Allegro con brio – 7:20
+02. II. Andante con moto – 9:41
+03. Egmont, Op. 84: Overture – 8:00
+`
+	if got := WikiSuggestion(symphony); !strings.HasSuffix(got, wantTracklist) {
+		t.Errorf("WikiSuggestion() =\n%s\nwant it to end\n%s", got, wantTracklist)
+	}
+
+	group := &TorrentGroup{WikiBody: "Hélène Grimaud plays Bach's Prelude and Fugue No. 2."}
+	if notes := wikiNotes(group, local); !slices.Equal(notes, []string{"the description does not mention Ferruccio Busoni"}) {
+		t.Errorf("wikiNotes() = %q", notes)
+	}
+	if notes := wikiNotes(&TorrentGroup{}, local); len(notes) != 1 {
+		t.Errorf("wikiNotes() without a description = %q, want one note", notes)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/titlecase"
 )
 
 // FilenameCapitalization checks that filenames use proper Title Case (rule 2.3.11.1)
@@ -109,8 +110,8 @@ func validCasualTitleCase(s string) bool {
 		if tok == "" {
 			continue
 		}
-		// Allow acronyms/roman/catalog anywhere
-		if isAcronym(tok) || isRomanNumeral(tok) || isCatalogToken(tok) {
+		// Allow acronyms/roman/catalog and protected words anywhere
+		if isAcronym(tok) || isRomanNumeral(tok) || isCatalogToken(tok) || titlecase.IsProtected(tok) {
 			continue
 		}
 		// First letter uppercase if it's a letter
@@ -134,7 +135,7 @@ func isTitleCase(title string) bool { return validTitleCase(title) }
 // - First and last token of each segment capitalized
 // - Small words lowercase unless segment boundary (first/last)
 // - Major words capitalized
-// - Acronyms/initialisms, roman numerals, catalog tokens allowed anywhere
+// - Acronyms/initialisms, roman numerals, catalog tokens and protected words allowed anywhere
 func validTitleCase(title string) bool {
 	segments := splitOnDelimiters(title)
 	for _, seg := range segments {
@@ -157,7 +158,7 @@ func validTitleCase(title string) bool {
 				if isKeyToken(part) {
 					continue
 				}
-				if isAcronym(part) || isRomanNumeral(part) || isCatalogToken(part) {
+				if isAcronym(part) || isRomanNumeral(part) || isCatalogToken(part) || titlecase.IsProtected(part) {
 					continue
 				}
 				if isSmallWord(lower) && !isBoundary {
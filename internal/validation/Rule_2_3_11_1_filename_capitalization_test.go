@@ -120,9 +120,13 @@ func TestAdditionalCapitalizationCases(t *testing.T) {
 		{"R&B Anthology", true},
 		{"Messa da Requiem", true},
 		{"Concerto per pianoforte", true},
-		{"RIAS Kammerchor", true}, // RIAS should be detected as acronym
-		{"HMC 902170", true},      // HMC should be detected as acronym
-		{"SYMPHONY NO. 5", false}, // SYMPHONY is too long to be an acronym
+		{"RIAS Kammerchor", true},                    // RIAS should be detected as acronym
+		{"HMC 902170", true},                         // HMC should be detected as acronym
+		{"Orchestral Works of Vincent d'Indy", true}, // Protected words keep their own spelling
+		{"Dido and Aeneas - musicAeterna", true},
+		{"Vincent D'indy", true},            // Casual Title Case still accepted
+		{"Symphony by musicaeterna", false}, // Not the protected spelling
+		{"SYMPHONY NO. 5", false},           // SYMPHONY is too long to be an acronym
 	}
 	for _, c := range cases {
 		got := checkCapitalization(c.Title)
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 )
 
 // filenameTrackPattern extracts the title portion from filename
@@ -73,8 +74,8 @@ func (r *Rules) FilenamesMatchTitles(actualTrack, _ *domain.Track, _, _ *domain.
 
 // normalizeTitle normalizes a title for comparison
 func normalizeTitle(title string) string {
-	// Convert to lowercase
-	normalized := strings.ToLower(title)
+	// Convert to lowercase; filenames from macOS are often decomposed (NFD)
+	normalized := strings.ToLower(normalize.NFC(title))
 
 	// Remove common punctuation that might differ
 	normalized = strings.ReplaceAll(normalized, ":", "")
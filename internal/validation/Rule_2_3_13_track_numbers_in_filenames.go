@@ -48,6 +48,8 @@ func (r *Rules) TrackNumbersInFilenames(actual, _ *domain.Torrent) RuleResult {
 			issues = append(issues, domain.ValidationIssue{
 				Level:   domain.LevelError,
 				Track:   track.Track,
+				Disc:    track.Disc,
+				Path:    track.File.Path,
 				Rule:    meta.ID,
 				Message: fmt.Sprintf("Filename missing track number: %s", fileName),
 			})
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 )
 
 // artistBeforeTrackPattern matches filenames with artist before track number
@@ -75,12 +76,12 @@ func isMultiComposerAlbum(album *domain.Torrent) bool {
 
 // containsArtistName checks if any artist name appears in the filename prefix
 func containsArtistName(filename string, artists []domain.Artist) bool {
-	filenameLower := strings.ToLower(filename)
+	filenameLower := normalize.Name(filename)
 
 	for _, artist := range artists {
 		// Check for last name or full name
 		name := artist.Name
-		nameLower := strings.ToLower(name)
+		nameLower := normalize.Name(name)
 
 		// Extract last name
 		nameParts := strings.Fields(name)
@@ -101,7 +102,7 @@ func containsArtistName(filename string, artists []domain.Artist) bool {
 			if strings.Contains(prefix, nameLower) {
 				return true
 			}
-			if lastName != "" && strings.Contains(prefix, strings.ToLower(lastName)) {
+			if lastName != "" && strings.Contains(prefix, normalize.Name(lastName)) {
 				return true
 			}
 		}
@@ -137,6 +137,12 @@ func TestContainsArtistName(t *testing.T) {
 			Artists:  []domain.Artist{vivaldi},
 			Want:     true,
 		},
+		{
+			Name:     "last name without diacritics",
+			Filename: "Dvorak - 01 - Allegro.flac",
+			Artists:  []domain.Artist{{Name: "Antonín Dvořák", Role: domain.RoleComposer}},
+			Want:     true,
+		},
 		{
 			Name:     "no artist in filename",
 			Filename: "01 - Prelude.flac",
@@ -25,13 +25,19 @@ func (r *Rules) FilenameSortingOrder(actual, _ *domain.Torrent) RuleResult {
 
 	// Group tracks by disc
 	discTracks := make(map[int][]*domain.Track)
+	var discs []int
 	for _, track := range tracks {
 		disc := track.Disc
+		if _, ok := discTracks[disc]; !ok {
+			discs = append(discs, disc)
+		}
 		discTracks[disc] = append(discTracks[disc], track)
 	}
+	sort.Ints(discs)
 
-	// Check each disc separately
-	for disc, discTrackList := range discTracks {
+	// Check each disc separately, in disc order so issues come out the same every run
+	for _, disc := range discs {
+		discTrackList := discTracks[disc]
 		if len(discTrackList) <= 1 {
 			continue
 		}
@@ -59,6 +65,8 @@ func (r *Rules) FilenameSortingOrder(actual, _ *domain.Torrent) RuleResult {
 				issues = append(issues, domain.ValidationIssue{
 					Level: domain.LevelError,
 					Track: b.Track,
+					Disc:  b.Disc,
+					Path:  b.File.Path,
 					Rule:  meta.ID,
 					Message: fmt.Sprintf("Disc %d: Filename sorting differs at position %d: got '%s' (track %d), expected '%s' (track %d)",
 						disc, i+1, a.File.Path, a.Track, b.File.Path, b.Track),
@@ -23,6 +23,18 @@ func (r *Rules) MultiDiscTrackNumbering(actualTorrent, _ *domain.Torrent) RuleRe
 	maxDisc := 1
 
 	for _, track := range actualTorrent.Tracks() {
+		// Hidden pregap tracks (track 0) precede track 1 and are not part of the numbering
+		if track.IsHidden() {
+			continue
+		}
+		if track.Track <= 0 {
+			issues = append(issues, domain.ValidationIssue{
+				Level:   domain.LevelError,
+				Rule:    meta.ID,
+				Message: "Track has no track number (and is not a hidden pregap track)",
+			}.ForTrack(track))
+			continue
+		}
 		disc := track.Disc
 		if disc > maxDisc {
 			maxDisc = disc
@@ -11,7 +11,7 @@ func TestRules_MultiDiscTrackNumbering(t *testing.T) {
 
 	tests := []struct {
 		Name         string
-		Actual *domain.Torrent
+		Actual       *domain.Torrent
 		WantPass     bool
 		WantErrors   int
 		WantWarnings int
@@ -41,6 +41,21 @@ func TestRules_MultiDiscTrackNumbering(t *testing.T) {
 			),
 			WantPass: true,
 		},
+		{
+			Name: "valid - hidden pregap track 0 before track 1",
+			Actual: withHiddenFirstTrack(buildTorrentWithDiscTracks(
+				[]discTrack{{1, 0}, {1, 1}, {1, 2}, {2, 1}, {2, 2}},
+			)),
+			WantPass: true,
+		},
+		{
+			Name: "invalid - track without a number is not hidden",
+			Actual: buildTorrentWithDiscTracks(
+				[]discTrack{{1, 0}, {1, 1}, {1, 2}, {2, 1}, {2, 2}},
+			),
+			WantPass:   false,
+			WantErrors: 1,
+		},
 		{
 			Name: "invalid - disc 2 doesn't start at 1",
 			Actual: buildTorrentWithDiscTracks(
@@ -129,3 +144,8 @@ func TestRules_MultiDiscTrackNumbering(t *testing.T) {
 	}
 }
 
+// withHiddenFirstTrack marks the torrent's first track as a hidden pregap track.
+func withHiddenFirstTrack(torrent *domain.Torrent) *domain.Torrent {
+	torrent.Tracks()[0].Hidden = true
+	return torrent
+}
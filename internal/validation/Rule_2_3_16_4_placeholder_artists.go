@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// PlaceholderArtists checks that composer and performer credits are real
+// artists, not placeholders such as "Unknown", "Various", "VA" or "N/A", or a
+// ripper's or tracker's signature (rule 2.3.16.4). "Various Artists" is allowed
+// as the album artist of a compilation, and "Anonymous" and "Traditional"
+// are real composer credits. Empty names are reported by the rules requiring
+// each credit.
+// ERROR level - a placeholder credit is as good as a missing one and blocks upload.
+func (r *Rules) PlaceholderArtists(actual, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "2.3.16.4-artist",
+		Name:   "Artist credits must be real artists, not placeholders",
+		Level:  domain.LevelError,
+		Weight: 1.0,
+	}
+
+	var issues []domain.ValidationIssue
+	for _, a := range actual.AlbumArtist {
+		name := strings.TrimSpace(a.Name)
+		if name == "" || !domain.IsPlaceholderArtist(name) || strings.EqualFold(name, "Various Artists") {
+			continue
+		}
+		issues = append(issues, domain.ValidationIssue{
+			Level:   domain.LevelError,
+			Track:   0,
+			Rule:    meta.ID,
+			Message: fmt.Sprintf("Album artist '%s' is a placeholder; credit the principal performers, or 'Various Artists' for a compilation", a.Name),
+		})
+	}
+	for _, track := range actual.Tracks() {
+		for _, a := range track.Artists {
+			if strings.TrimSpace(a.Name) == "" || !domain.IsPlaceholderArtist(a.Name) {
+				continue
+			}
+			issues = append(issues, domain.ValidationIssue{
+				Level: domain.LevelError,
+				Rule:  meta.ID,
+				Message: fmt.Sprintf("Track %s: %s '%s' is a placeholder; take the real credit from an online source (extract) or the booklet",
+					formatTrackNumber(track), a.Role, a.Name),
+			}.ForTrack(track))
+		}
+	}
+	return RuleResult{Meta: meta, Issues: issues}
+}
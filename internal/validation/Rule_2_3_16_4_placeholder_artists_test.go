@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_PlaceholderArtists(t *testing.T) {
+	rules := NewRules()
+	bach := domain.Artist{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}
+	gould := domain.Artist{Name: "Glenn Gould", Role: domain.RoleSoloist, Instrument: "piano"}
+
+	tests := []struct {
+		Name        string
+		AlbumArtist []domain.Artist
+		Artists     []domain.Artist
+		WantIssues  int
+		WantMessage string
+	}{
+		{Name: "pass - real credits", AlbumArtist: []domain.Artist{gould}, Artists: []domain.Artist{bach, gould}},
+		{Name: "pass - anonymous composer", Artists: []domain.Artist{{Name: "Anonymous", Role: domain.RoleComposer}, gould}},
+		{Name: "pass - various artists compilation", AlbumArtist: []domain.Artist{{Name: "Various Artists", Role: domain.RolePerformer}}, Artists: []domain.Artist{bach, gould}},
+		{Name: "pass - missing name reported elsewhere", Artists: []domain.Artist{{Name: "", Role: domain.RoleComposer}, gould}},
+		{Name: "error - various composer", Artists: []domain.Artist{{Name: "Various", Role: domain.RoleComposer}, gould}, WantIssues: 1, WantMessage: "composer 'Various'"},
+		{Name: "error - VA composer", Artists: []domain.Artist{{Name: "VA", Role: domain.RoleComposer}, gould}, WantIssues: 1},
+		{Name: "error - unknown performer", Artists: []domain.Artist{bach, {Name: "Unknown Artist", Role: domain.RoleSoloist}}, WantIssues: 1, WantMessage: "soloist 'Unknown Artist'"},
+		{Name: "error - ripper signature", Artists: []domain.Artist{bach, {Name: "Ripped by someone", Role: domain.RolePerformer}}, WantIssues: 1},
+		{Name: "error - placeholder album artist", AlbumArtist: []domain.Artist{{Name: "N/A", Role: domain.RolePerformer}}, Artists: []domain.Artist{bach, gould}, WantIssues: 1, WantMessage: "Album artist 'N/A'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			torrent := NewTorrent().ClearTracks().AddTrack().WithTrack(1).WithTitle("Aria").ClearArtists().WithArtists(tt.Artists...).Build().Build()
+			torrent.AlbumArtist = tt.AlbumArtist
+			result := rules.PlaceholderArtists(torrent, nil)
+			if len(result.Issues) != tt.WantIssues {
+				t.Fatalf("Issues = %d, want %d: %v", len(result.Issues), tt.WantIssues, result.Issues)
+			}
+			for _, issue := range result.Issues {
+				if issue.Level != domain.LevelError {
+					t.Errorf("Level = %v, want error", issue.Level)
+				}
+				if !strings.Contains(issue.Message, tt.WantMessage) {
+					t.Errorf("Message = %q, want it to contain %q", issue.Message, tt.WantMessage)
+				}
+			}
+		})
+	}
+}
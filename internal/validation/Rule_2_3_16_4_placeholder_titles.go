@@ -0,0 +1,33 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// PlaceholderTitles checks that track titles are not ripper placeholders such as
+// "Track 01", "Unknown" or "Untitled" (rule 2.3.16.4). Missing titles are reported
+// by RequiredTrackTags.
+// ERROR level - placeholder titles are as good as missing and block upload.
+func (r *Rules) PlaceholderTitles(actualTrack, _ *domain.Track, _, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "2.3.16.4-title",
+		Name:   "Track titles must be real titles, not placeholders",
+		Level:  domain.LevelError,
+		Weight: 1.0,
+	}
+
+	if actualTrack == nil || strings.TrimSpace(actualTrack.Title) == "" || !domain.IsPlaceholderTitle(actualTrack.Title) {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	return RuleResult{Meta: meta, Issues: []domain.ValidationIssue{{
+		Level: domain.LevelError,
+		Track: actualTrack.Track,
+		Rule:  meta.ID,
+		Message: fmt.Sprintf("Track %s: Title '%s' is a placeholder; take real titles from an online source (extract) or the booklet (extract -tracklist)",
+			formatTrackNumber(actualTrack), actualTrack.Title),
+	}}}
+}
@@ -0,0 +1,39 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_PlaceholderTitles(t *testing.T) {
+	rules := NewRules()
+
+	tests := []struct {
+		Name       string
+		Title      string
+		WantIssues int
+	}{
+		{Name: "pass - real title", Title: "Goldberg Variations, BWV 988: Aria"},
+		{Name: "pass - missing title reported elsewhere", Title: ""},
+		{Name: "pass - hidden track", Title: domain.HiddenTrackTitle},
+		{Name: "error - track number", Title: "Track 01", WantIssues: 1},
+		{Name: "error - unknown", Title: "Unknown", WantIssues: 1},
+		{Name: "error - untitled", Title: "Untitled", WantIssues: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			track := &domain.Track{Disc: 1, Track: 1, Title: tt.Title}
+			result := rules.PlaceholderTitles(track, nil, nil, nil)
+			if len(result.Issues) != tt.WantIssues {
+				t.Errorf("Issues = %d, want %d: %v", len(result.Issues), tt.WantIssues, result.Issues)
+			}
+			for _, issue := range result.Issues {
+				if issue.Level != domain.LevelError {
+					t.Errorf("Level = %v, want error", issue.Level)
+				}
+			}
+		})
+	}
+}
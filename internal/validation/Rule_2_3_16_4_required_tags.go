@@ -38,7 +38,7 @@ func (r *Rules) RequiredAlbumTags(actualTorrent, _ *domain.Torrent) RuleResult {
 			Level:   domain.LevelWarning,
 			Track:   0,
 			Rule:    meta.ID,
-			Message: "Year tag is missing (strongly recommended)",
+			Message: domain.MessageYearTagMissing,
 		})
 	}
 	return RuleResult{Meta: meta, Issues: issues}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 )
 
 // TorrentArtistFullComposerName checks that the torrent artist uses full composer name (rule 2.3.17)
@@ -33,9 +34,10 @@ func (r *Rules) TorrentArtistFullComposerName(actual, _ *domain.Torrent) RuleRes
 
 	for _, track := range tracks {
 		for _, artist := range track.Artists {
-			if artist.Role == domain.RoleComposer {
+			// Special composers are not named in album titles
+			if artist.Role == domain.RoleComposer && !artist.IsSpecialComposer() {
 				name := artist.Name
-				lastName := lastName(name)
+				lastName := artistLastName(artist)
 				composerCounts[lastName]++
 				composerFullNames[lastName] = name
 			}
@@ -104,7 +106,7 @@ func (r *Rules) TorrentArtistFullComposerName(actual, _ *domain.Torrent) RuleRes
 // isSurnameAloneAcceptable returns true for composers where the surname alone is widely accepted
 // in album titles without initials, to avoid over-warning in common cataloging practices.
 func isSurnameAloneAcceptable(lastName string) bool {
-	switch strings.ToLower(strings.TrimSpace(lastName)) {
+	switch normalize.Name(lastName) {
 	case "vivaldi":
 		return true
 	default:
@@ -114,8 +116,8 @@ func isSurnameAloneAcceptable(lastName string) bool {
 
 // containsWord checks if a word appears in text (case-insensitive, word boundary)
 func containsWord(text, word string) bool {
-	textLower := strings.ToLower(text)
-	wordLower := strings.ToLower(word)
+	textLower := normalize.Name(text)
+	wordLower := normalize.Name(word)
 
 	// Simple word boundary check
 	words := strings.FieldsFunc(textLower, func(r rune) bool {
@@ -132,7 +134,7 @@ func containsWord(text, word string) bool {
 
 // containsPhrase checks if a phrase (possibly multi-word) appears in text (case-insensitive)
 func containsPhrase(text, phrase string) bool {
-	return strings.Contains(strings.ToLower(text), strings.ToLower(phrase))
+	return strings.Contains(normalize.Name(text), normalize.Name(phrase))
 }
 
 // isAcceptableAbbreviation checks if the title contains an acceptable abbreviation
@@ -140,7 +142,7 @@ func containsPhrase(text, phrase string) bool {
 func isAcceptableAbbreviation(title, fullName string) bool {
 	// Accept initial-first-letter abbreviations for given names with or without spaces between initials
 	// Examples: "J.S. Bach", "J. S. Bach" for "Johann Sebastian Bach"
-	parts := strings.Fields(fullName)
+	parts := strings.Fields(normalize.Name(fullName))
 	if len(parts) < 2 {
 		return false
 	}
@@ -148,10 +150,10 @@ func isAcceptableAbbreviation(title, fullName string) bool {
 	// Build two variants: compact "J.S." and spaced "J. S."
 	var compact, spaced strings.Builder
 	for i := 0; i < len(parts)-1; i++ {
-		if len(parts[i]) > 0 {
-			compact.WriteString(string(parts[i][0]))
+		if initial := []rune(parts[i]); len(initial) > 0 {
+			compact.WriteRune(initial[0])
 			compact.WriteString(".")
-			spaced.WriteString(string(parts[i][0]))
+			spaced.WriteRune(initial[0])
 			spaced.WriteString(".")
 			if i < len(parts)-2 {
 				spaced.WriteString(" ")
@@ -163,11 +165,11 @@ func isAcceptableAbbreviation(title, fullName string) bool {
 	compact.WriteString(parts[len(parts)-1])
 	spaced.WriteString(parts[len(parts)-1])
 
-	tl := strings.ToLower(title)
-	if strings.Contains(tl, strings.ToLower(compact.String())) {
+	tl := normalize.Name(title)
+	if strings.Contains(tl, compact.String()) {
 		return true
 	}
-	if strings.Contains(tl, strings.ToLower(spaced.String())) {
+	if strings.Contains(tl, spaced.String()) {
 		return true
 	}
 	return false
@@ -152,6 +152,8 @@ func TestContainsWord(t *testing.T) {
 		{"with dash", "J.S. Bach - Works", "Bach", true},
 		{"not present", "Vivaldi Concertos", "Bach", false},
 		{"abbreviation", "J.S. Bach", "J.S.", true},
+		{"diacritics folded", "Dvorak: Symphony No. 9", "Dvořák", true},
+		{"decomposed title", "Dvor\u030ca\u0301k: Symphony No. 9", "Dvořák", true},
 	}
 
 	for _, tt := range tests {
@@ -189,6 +191,12 @@ func TestIsAcceptableAbbreviation(t *testing.T) {
 			"Carl Philipp Emanuel Bach",
 			true,
 		},
+		{
+			"A. Dvořák for Antonín Dvořák, without diacritics",
+			"A. Dvorak - Symphonies",
+			"Antonín Dvořák",
+			true,
+		},
 		{
 			"no abbreviation present",
 			"Bach - Cello Suites",
@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
+)
+
+// UnicodeNormalization checks that file and folder names and tags use composed
+// Unicode (NFC) (2.3.18.1-nfc). macOS rips often have decomposed (NFD) names:
+// "Dvořák" spelled with combining marks, which looks identical but no longer
+// matches the NFC tags or displays consistently on the site.
+// ERROR level when one album mixes both forms, WARNING when it is decomposed throughout.
+func (r *Rules) UnicodeNormalization(actualTorrent, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "2.3.18.1-nfc",
+		Name:   "File names and tags must use composed Unicode (NFC)",
+		Level:  domain.LevelError,
+		Weight: 0.5,
+	}
+
+	if actualTorrent == nil {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	// Names without accents are both NFC and NFD; only the rest tell the forms apart
+	var composed, decomposed []string
+	classify := func(name string) {
+		if name == "" || isASCII(name) || slices.Contains(composed, name) || slices.Contains(decomposed, name) {
+			return
+		}
+		if normalize.IsNFC(name) {
+			composed = append(composed, name)
+		} else {
+			decomposed = append(decomposed, name)
+		}
+	}
+	classify(actualTorrent.RootPath)
+	for _, track := range actualTorrent.Tracks() {
+		for _, part := range strings.Split(filepath.ToSlash(track.Path), "/") {
+			classify(part)
+		}
+	}
+
+	var issues []domain.ValidationIssue
+	const fix = "rename them to NFC with extract -fix-nfc"
+	switch {
+	case len(decomposed) > 0 && len(composed) > 0:
+		issues = append(issues, domain.ValidationIssue{
+			Level: domain.LevelError,
+			Track: 0,
+			Rule:  meta.ID,
+			Message: fmt.Sprintf("File and folder names mix composed (NFC) and decomposed (NFD) Unicode: %d of %d are decomposed, e.g. %q; %s",
+				len(decomposed), len(decomposed)+len(composed), decomposed[0], fix),
+		})
+	case len(decomposed) > 0:
+		issues = append(issues, domain.ValidationIssue{
+			Level: domain.LevelWarning,
+			Track: 0,
+			Rule:  meta.ID,
+			Message: fmt.Sprintf("File and folder names use decomposed Unicode (NFD), e.g. %q, unlike tags and the site; %s",
+				decomposed[0], fix),
+		})
+	}
+
+	if !normalize.IsNFC(actualTorrent.Title) {
+		issues = append(issues, domain.ValidationIssue{
+			Level:   domain.LevelWarning,
+			Track:   0,
+			Rule:    meta.ID,
+			Message: fmt.Sprintf("Album title uses decomposed Unicode (NFD): %q", actualTorrent.Title),
+		})
+	}
+	for _, track := range actualTorrent.Tracks() {
+		if !normalize.IsNFC(track.Title) {
+			issues = append(issues, domain.ValidationIssue{
+				Level:   domain.LevelWarning,
+				Track:   track.Track,
+				Disc:    track.Disc,
+				Path:    track.File.Path,
+				Rule:    meta.ID,
+				Message: fmt.Sprintf("Track %s: Title uses decomposed Unicode (NFD): %q", formatTrackNumber(track), track.Title),
+			})
+		}
+	}
+
+	return RuleResult{Meta: meta, Issues: issues}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_UnicodeNormalization(t *testing.T) {
+	rules := NewRules()
+
+	build := func(root, title string, paths ...string) *domain.Torrent {
+		files := make([]domain.FileLike, len(paths))
+		for i, path := range paths {
+			files[i] = &domain.Track{File: domain.File{Path: path}, Disc: 1, Track: i + 1, Title: title}
+		}
+		return &domain.Torrent{Title: "Slavonic Dances", RootPath: root, Files: files}
+	}
+
+	tests := []struct {
+		Name       string
+		Actual     *domain.Torrent
+		WantLevels []domain.Level
+	}{
+		{Name: "pass - ASCII", Actual: build("Dvorak - Slavonic Dances", "Dance", "01 - Dance.flac")},
+		{Name: "pass - composed", Actual: build("Dvořák - Slavonic Dances", "Furiant", "CD1/01 - Dvořák - Furiant.flac")},
+		{
+			Name:       "warning - decomposed throughout",
+			Actual:     build("Dvor\u030ca\u0301k - Slavonic Dances", "Furiant", "01 - Dvor\u030ca\u0301k - Furiant.flac"),
+			WantLevels: []domain.Level{domain.LevelWarning},
+		},
+		{
+			Name:       "error - mixed forms",
+			Actual:     build("Dvořák - Slavonic Dances", "Furiant", "01 - Dvořák - Furiant.flac", "02 - Dvor\u030ca\u0301k - Furiant.flac"),
+			WantLevels: []domain.Level{domain.LevelError},
+		},
+		{
+			Name:       "warning - decomposed tag",
+			Actual:     build("Dvořák - Slavonic Dances", "Skočna\u0301", "01 - Skočná.flac"),
+			WantLevels: []domain.Level{domain.LevelWarning},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := rules.UnicodeNormalization(tt.Actual, nil)
+			if len(result.Issues) != len(tt.WantLevels) {
+				t.Fatalf("Issues = %v, want %d", result.Issues, len(tt.WantLevels))
+			}
+			for i, issue := range result.Issues {
+				if issue.Level != tt.WantLevels[i] {
+					t.Errorf("issue %d level = %v, want %v: %s", i, issue.Level, tt.WantLevels[i], issue.Message)
+				}
+			}
+		})
+	}
+}
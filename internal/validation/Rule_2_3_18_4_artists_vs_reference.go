@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// ArtistsVsReference checks each track's artists against the reference track's
+// (rule 2.3.18.4). Renames are the same artist spelled differently (see
+// domain.SpellingKey); validate -apply-renames adopts the reference spelling.
+// WARNING level - the reference is often incomplete, so artists it lacks are INFO.
+func (r *Rules) ArtistsVsReference(actual, reference *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "2.3.18.4-artists",
+		Name:   "Track artists must match reference data",
+		Level:  domain.LevelWarning,
+		Weight: 0.5,
+	}
+
+	var issues []domain.ValidationIssue
+	for _, diff := range actual.DiffArtists(reference) {
+		for _, change := range diff.Changes {
+			level := domain.LevelWarning
+			message := fmt.Sprintf("Track %s: %s", formatTrackNumber(diff.Track), change)
+			switch change.Kind {
+			case domain.ArtistExtra:
+				level = domain.LevelInfo
+			case domain.ArtistRenamed:
+				message += " (validate -apply-renames adopts it)"
+			}
+			issues = append(issues, domain.ValidationIssue{
+				Level:   level,
+				Rule:    meta.ID,
+				Message: message,
+			}.ForTrack(diff.Track))
+		}
+	}
+
+	return RuleResult{Meta: meta, Issues: issues}
+}
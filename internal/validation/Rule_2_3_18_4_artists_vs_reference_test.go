@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_ArtistsVsReference(t *testing.T) {
+	rules := NewRules()
+
+	build := func(artists ...domain.Artist) *domain.Torrent {
+		return &domain.Torrent{Title: "Album", Files: []domain.FileLike{
+			&domain.Track{Disc: 1, Track: 1, Title: "Symphony No. 9: I. Adagio", Artists: artists},
+		}}
+	}
+	dvorak := domain.Artist{Name: "Antonín Dvořák", Role: domain.RoleComposer}
+	kubelik := domain.Artist{Name: "Rafael Kubelík", Role: domain.RoleConductor}
+	bpo := domain.Artist{Name: "Berliner Philharmoniker", Role: domain.RoleEnsemble}
+	reference := build(dvorak, kubelik, bpo)
+
+	tests := []struct {
+		Name      string
+		Actual    *domain.Torrent
+		Reference *domain.Torrent
+		WantLevel []domain.Level
+		WantText  string
+	}{
+		{Name: "pass - no reference", Actual: build(dvorak), Reference: nil},
+		{Name: "pass - artists match", Actual: build(dvorak, kubelik, bpo), Reference: reference},
+		{
+			Name:      "warning - rename",
+			Actual:    build(domain.Artist{Name: "Antonin Dvorak", Role: domain.RoleComposer}, kubelik, bpo),
+			Reference: reference,
+			WantLevel: []domain.Level{domain.LevelWarning},
+			WantText:  `Track 1: "Antonin Dvorak" is spelled "Antonín Dvořák" in the reference (validate -apply-renames adopts it)`,
+		},
+		{
+			Name:      "warning - missing artist",
+			Actual:    build(dvorak, kubelik),
+			Reference: reference,
+			WantLevel: []domain.Level{domain.LevelWarning},
+			WantText:  "Berliner Philharmoniker (ensemble) is missing",
+		},
+		{
+			Name:      "warning - role changed",
+			Actual:    build(dvorak, domain.Artist{Name: "Rafael Kubelík", Role: domain.RoleSoloist}, bpo),
+			Reference: reference,
+			WantLevel: []domain.Level{domain.LevelWarning},
+			WantText:  "is soloist but conductor in the reference",
+		},
+		{
+			Name:      "info - artist the reference lacks",
+			Actual:    build(dvorak, kubelik, bpo, domain.Artist{Name: "Josef Suk", Role: domain.RoleSoloist}),
+			Reference: reference,
+			WantLevel: []domain.Level{domain.LevelInfo},
+			WantText:  "Josef Suk (soloist) is not in the reference",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := rules.ArtistsVsReference(tt.Actual, tt.Reference)
+			if len(result.Issues) != len(tt.WantLevel) {
+				t.Fatalf("Issues = %d, want %d: %v", len(result.Issues), len(tt.WantLevel), result.Issues)
+			}
+			for i, issue := range result.Issues {
+				if issue.Level != tt.WantLevel[i] {
+					t.Errorf("Issues[%d].Level = %v, want %v", i, issue.Level, tt.WantLevel[i])
+				}
+				if !strings.Contains(issue.Message, tt.WantText) {
+					t.Errorf("Issues[%d].Message = %q, want it to contain %q", i, issue.Message, tt.WantText)
+				}
+			}
+		})
+	}
+}
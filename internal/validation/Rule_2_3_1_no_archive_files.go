@@ -50,6 +50,7 @@ func (r *Rules) NoArchiveFiles(actualTrack, refTrack *domain.Track, actualTorren
 				issues = append(issues, domain.ValidationIssue{
 					Level:   domain.LevelError,
 					Track:   trackNum,
+					Path:    filePath,
 					Rule:    meta.ID,
 					Message: fmt.Sprintf("Archive file found '%s' (archives not allowed in torrents)", filePath),
 				})
@@ -58,6 +58,20 @@ func (r *Rules) RecordingDateVsYear(actual, reference *domain.Torrent) RuleResul
 		}
 	}
 
+	// Recording years are tracked separately; a release can't predate its recording
+	if n := len(actual.RecordingYears); year != 0 && n > 0 {
+		lastRecording := actual.RecordingYears[n-1]
+		if year < lastRecording {
+			issues = append(issues, domain.ValidationIssue{
+				Level: domain.LevelInfo,
+				Track: 0,
+				Rule:  meta.ID,
+				Message: fmt.Sprintf("Original release year (%d) is before the recording year (%s) - check if correct",
+					year, domain.FormatYears(actual.RecordingYears)),
+			})
+		}
+	}
+
 	// If we have reference, check consistency (informational only)
 	if reference != nil && reference.OriginalYear != 0 {
 		refYear := reference.OriginalYear
@@ -49,6 +49,25 @@ func TestRules_RecordingDateVsYear(t *testing.T) {
 			WantPass: false,
 			WantInfo: 1,
 		},
+		{
+			Name: "pass - recorded before release",
+			Actual: func() *domain.Torrent {
+				torrent := NewTorrent().WithOriginalYear(1982).Build()
+				torrent.RecordingYears = []int{1981}
+				return torrent
+			}(),
+			WantPass: true,
+		},
+		{
+			Name: "info - released before recording",
+			Actual: func() *domain.Torrent {
+				torrent := NewTorrent().WithOriginalYear(1980).Build()
+				torrent.RecordingYears = []int{1980, 1981}
+				return torrent
+			}(),
+			WantPass: false,
+			WantInfo: 1,
+		},
 		{
 			Name:      "info - differs from reference",
 			Actual:    NewTorrent().WithOriginalYear(1963).WithEdition("Label", "CAT123", 1963).Build(),
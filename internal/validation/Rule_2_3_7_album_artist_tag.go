@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 )
 
 // AlbumArtistTag checks for album artist tag presence (rule 2.3.7)
@@ -33,7 +34,7 @@ func (r *Rules) AlbumArtistTag(actualTorrent, _ *domain.Torrent) RuleResult {
 			present := make(map[string]bool)
 			for _, track := range actualTorrent.Tracks() {
 				for _, ta := range track.Artists {
-					norm := normalizeNameForInclusion(ta.Name)
+					norm := normalize.Compact(ta.Name)
 					if norm != "" {
 						present[norm] = true
 					}
@@ -42,7 +43,7 @@ func (r *Rules) AlbumArtistTag(actualTorrent, _ *domain.Torrent) RuleResult {
 
 			// For each album artist, require presence in at least one track by normalized name
 			for _, aa := range actualTorrent.AlbumArtist {
-				normAA := normalizeNameForInclusion(aa.Name)
+				normAA := normalize.Compact(aa.Name)
 				if normAA == "" || !present[normAA] {
 					issues = append(issues, domain.ValidationIssue{
 						Level: domain.LevelError,
@@ -216,6 +216,19 @@ func TestRules_AlbumArtist_LaxInclusion(t *testing.T) {
 	if res2.Passed() {
 		t.Errorf("Expected failure when an AlbumArtist name is missing from all tracks")
 	}
+
+	// Decomposed (NFD) spelling on the track still counts as the same artist
+	torrentNFD := &domain.Torrent{
+		Title:        "Album",
+		OriginalYear: 2000,
+		AlbumArtist:  []domain.Artist{{Name: "Bohuslav Martinů", Role: domain.RoleUnknown}},
+		Files: []domain.FileLike{
+			&domain.Track{Disc: 1, Track: 1, Title: "Work 1", Artists: []domain.Artist{{Name: "Bohuslav Martinu\u030a", Role: domain.RoleComposer}}},
+		},
+	}
+	if res3 := rules.AlbumArtistTag(torrentNFD, nil); !res3.Passed() {
+		t.Errorf("Expected pass when AlbumArtist differs only in normalization form: %v", res3.Issues)
+	}
 }
 
 func TestRules_AlbumArtist_InclusionInvariant(t *testing.T) {
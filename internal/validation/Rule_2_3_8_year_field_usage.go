@@ -29,7 +29,7 @@ func (r *Rules) YearFieldUsage(actual, reference *domain.Torrent) RuleResult {
 				Level:   domain.LevelWarning,
 				Track:   0,
 				Rule:    meta.ID,
-				Message: "Year is missing (should include recording/original year)",
+				Message: domain.MessageYearMissing,
 			})
 		}
 	}
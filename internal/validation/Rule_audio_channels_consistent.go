@@ -0,0 +1,72 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// ChannelsConsistent checks that stereo and multichannel files aren't mixed in one upload.
+// Surround and stereo mixes of the same release are separate editions on the tracker.
+// ERROR level - a mixed torrent can't be uploaded as either edition.
+func (r *Rules) ChannelsConsistent(actualTorrent, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "audio.channels",
+		Name:   "Stereo and multichannel files must not be mixed in one upload",
+		Level:  domain.LevelError,
+		Weight: 1.0,
+	}
+
+	if actualTorrent == nil {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	// Mono and stereo tracks legitimately share historical reissues; only a mix of
+	// stereo (or mono) with surround is a problem
+	counts := actualTorrent.ChannelCounts()
+	if len(counts) <= 1 || counts[0] > 2 || counts[len(counts)-1] <= 2 {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	// Count tracks per layout so the odd ones out can be reported
+	tracksByCount := make(map[int][]*domain.Track)
+	for _, track := range actualTorrent.Tracks() {
+		if track.Channels > 0 {
+			tracksByCount[track.Channels] = append(tracksByCount[track.Channels], track)
+		}
+	}
+	majority := counts[0]
+	layouts := make([]string, 0, len(counts))
+	for _, count := range counts {
+		layouts = append(layouts, fmt.Sprintf("%d %s", len(tracksByCount[count]), domain.ChannelLayout(count)))
+		if len(tracksByCount[count]) > len(tracksByCount[majority]) {
+			majority = count
+		}
+	}
+
+	issues := []domain.ValidationIssue{{
+		Level:   domain.LevelError,
+		Track:   0,
+		Rule:    meta.ID,
+		Message: fmt.Sprintf("Upload mixes channel layouts (%s tracks)", strings.Join(layouts, ", ")),
+	}}
+	for _, count := range counts {
+		if (count > 2) == (majority > 2) {
+			continue
+		}
+		for _, track := range tracksByCount[count] {
+			issues = append(issues, domain.ValidationIssue{
+				Level: domain.LevelError,
+				Track: track.Track,
+				Disc:  track.Disc,
+				Path:  track.File.Path,
+				Rule:  meta.ID,
+				Message: fmt.Sprintf("Track %s is %s; most tracks are %s",
+					formatTrackNumber(track), domain.ChannelLayout(count), domain.ChannelLayout(majority)),
+			})
+		}
+	}
+
+	return RuleResult{Meta: meta, Issues: issues}
+}
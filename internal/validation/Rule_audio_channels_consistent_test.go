@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_ChannelsConsistent(t *testing.T) {
+	rules := NewRules()
+
+	build := func(channels ...int) *domain.Torrent {
+		files := make([]domain.FileLike, len(channels))
+		for i, c := range channels {
+			files[i] = &domain.Track{Disc: 1, Track: i + 1, Title: "Track", Channels: c}
+		}
+		return &domain.Torrent{Title: "Album", Files: files}
+	}
+
+	tests := []struct {
+		Name       string
+		Actual     *domain.Torrent
+		WantPass   bool
+		WantIssues int
+	}{
+		{Name: "pass - all stereo", Actual: build(2, 2, 2), WantPass: true},
+		{Name: "pass - all surround", Actual: build(6, 6), WantPass: true},
+		{Name: "pass - mono and stereo", Actual: build(1, 2, 2), WantPass: true},
+		{Name: "pass - unknown counts", Actual: build(0, 6, 6), WantPass: true},
+		{Name: "error - stereo bonus track on surround disc", Actual: build(6, 6, 6, 2), WantPass: false, WantIssues: 2},
+		{Name: "error - surround tracks on stereo disc", Actual: build(2, 2, 2, 6, 6), WantPass: false, WantIssues: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := rules.ChannelsConsistent(tt.Actual, nil)
+			if result.Passed() != tt.WantPass {
+				t.Errorf("Passed = %v, want %v: %v", result.Passed(), tt.WantPass, result.Issues)
+			}
+			if len(result.Issues) != tt.WantIssues {
+				t.Errorf("Issues = %d, want %d: %v", len(result.Issues), tt.WantIssues, result.Issues)
+			}
+		})
+	}
+}
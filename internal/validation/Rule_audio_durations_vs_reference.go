@@ -0,0 +1,50 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// DurationsMatchReference checks that the files' playing times agree with the
+// reference tracklist's, within domain.DurationTolerance. Durations far apart
+// mean the reference (usually the Discogs release) is another recording or edition.
+// WARNING level - one mistyped duration on Discogs is common.
+func (r *Rules) DurationsMatchReference(actual, reference *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "audio.durations",
+		Name:   "Track durations must match the reference tracklist",
+		Level:  domain.LevelWarning,
+		Weight: 0.5,
+	}
+
+	check := actual.CompareDurations(reference)
+	if len(check.Mismatches) == 0 {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	var issues []domain.ValidationIssue
+	if check.DifferentRecording() {
+		issues = append(issues, domain.ValidationIssue{
+			Level:   domain.LevelWarning,
+			Track:   0,
+			Rule:    meta.ID,
+			Message: fmt.Sprintf("%s from the reference; it may be a different recording or edition", check),
+		})
+	}
+	for _, track := range actual.Tracks() {
+		for _, m := range check.Mismatches {
+			if m.Disc != track.Disc || m.Track != track.Track {
+				continue
+			}
+			issues = append(issues, domain.ValidationIssue{
+				Level: domain.LevelWarning,
+				Rule:  meta.ID,
+				Message: fmt.Sprintf("Track %s plays %s but the reference says %s",
+					formatTrackNumber(track), domain.FormatDuration(m.Local), domain.FormatDuration(m.Source)),
+			}.ForTrack(track))
+		}
+	}
+
+	return RuleResult{Meta: meta, Issues: issues}
+}
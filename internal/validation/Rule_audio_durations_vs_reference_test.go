@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_DurationsMatchReference(t *testing.T) {
+	rules := NewRules()
+
+	build := func(seconds ...int) *domain.Torrent {
+		files := make([]domain.FileLike, len(seconds))
+		for i, s := range seconds {
+			files[i] = &domain.Track{Disc: 1, Track: i + 1, Title: "Track", Duration: time.Duration(s) * time.Second}
+		}
+		return &domain.Torrent{Title: "Album", Files: files}
+	}
+	local := build(180, 240, 300, 360, 420, 480, 540, 600)
+
+	tests := []struct {
+		Name       string
+		Reference  *domain.Torrent
+		WantPass   bool
+		WantIssues int
+	}{
+		{Name: "pass - no reference", Reference: nil, WantPass: true},
+		{Name: "pass - durations match", Reference: build(181, 239, 300, 362, 420, 480, 541, 600), WantPass: true},
+		{Name: "pass - reference without durations", Reference: build(0, 0, 0, 0, 0, 0, 0, 0), WantPass: true},
+		{Name: "warning - one track differs", Reference: build(180, 240, 300, 360, 420, 480, 540, 700), WantPass: false, WantIssues: 1},
+		{Name: "warning - different recording", Reference: build(200, 260, 330, 360, 420, 480, 540, 600), WantPass: false, WantIssues: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := rules.DurationsMatchReference(local, tt.Reference)
+			if result.Passed() != tt.WantPass {
+				t.Errorf("Passed = %v, want %v: %v", result.Passed(), tt.WantPass, result.Issues)
+			}
+			if len(result.Issues) != tt.WantIssues {
+				t.Errorf("Issues = %d, want %d: %v", len(result.Issues), tt.WantIssues, result.Issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// AlbumTitleJunk checks that the album title carries no format, bitrate or year
+// indicators ("[FLAC]", "{24-96}", "[2013]"), which leak into ALBUM tags from
+// folder names during extraction.
+// ERROR level - the indicators are not part of the title.
+func (r *Rules) AlbumTitleJunk(actualTorrent, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "classical.album_title_junk",
+		Name:   "Album title must not contain format, bitrate or year indicators",
+		Level:  domain.LevelError,
+		Weight: 1.0,
+	}
+
+	if actualTorrent == nil {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	var issues []domain.ValidationIssue
+	for _, title := range actualTorrent.TitleVariants() {
+		cleaned, junk := domain.StripTitleJunk(title)
+		if len(junk) == 0 {
+			continue
+		}
+		issues = append(issues, domain.ValidationIssue{
+			Level:   meta.Level,
+			Track:   0,
+			Rule:    meta.ID,
+			Message: fmt.Sprintf("Album title '%s' contains %s; use '%s'", title, strings.Join(junk, " "), cleaned),
+		})
+	}
+
+	return RuleResult{Meta: meta, Issues: issues}
+}
@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_AlbumTitleJunk(t *testing.T) {
+	rules := NewRules()
+
+	tests := []struct {
+		Name      string
+		Title     string
+		Alternate []string
+		Want      []string
+	}{
+		{
+			Name:  "pass - clean title",
+			Title: "Beethoven: Symphonies Nos. 1-9",
+		},
+		{
+			Name:  "pass - version year in parentheses",
+			Title: "Bruckner: Symphony No. 4 (1874)",
+		},
+		{
+			Name:  "error - format, year and bitrate",
+			Title: "Beethoven Symphonies [FLAC] [2013] {24-96}",
+			Want:  []string{"Album title 'Beethoven Symphonies [FLAC] [2013] {24-96}' contains [FLAC] [2013] {24-96}; use 'Beethoven Symphonies'"},
+		},
+		{
+			Name:  "error - combined indicator after a separator",
+			Title: "Goldberg Variations - (FLAC 24bit/96kHz)",
+			Want:  []string{"Album title 'Goldberg Variations - (FLAC 24bit/96kHz)' contains (FLAC 24bit/96kHz); use 'Goldberg Variations'"},
+		},
+		{
+			Name:      "error - in an alternate title",
+			Title:     "Weihnachten",
+			Alternate: []string{"Christmas [WEB FLAC]", "Noël [Hi-Res]"},
+			Want: []string{
+				"Album title 'Christmas [WEB FLAC]' contains [WEB FLAC]; use 'Christmas'",
+				"Album title 'Noël [Hi-Res]' contains [Hi-Res]; use 'Noël'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := rules.AlbumTitleJunk(&domain.Torrent{Title: tt.Title, AlternateTitles: tt.Alternate}, nil)
+			var got []string
+			for _, issue := range result.Issues {
+				got = append(got, issue.Message)
+			}
+			if !slices.Equal(got, tt.Want) {
+				t.Errorf("Issues = %q, want %q", got, tt.Want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 )
 
 // ArrangerCredit checks that arrangements are properly credited (classical.arrangement)
@@ -37,8 +38,8 @@ func (r *Rules) ArrangerCredit(actualTrack, _ *domain.Track, _, _ *domain.Torren
 	arrangerName := arranger.Name
 
 	// Check if arranger is mentioned in title
-	titleLower := strings.ToLower(title)
-	nameLower := strings.ToLower(arrangerName)
+	titleLower := normalize.Name(title)
+	nameLower := normalize.Name(arrangerName)
 
 	// Extract last name
 	nameParts := strings.Fields(nameLower)
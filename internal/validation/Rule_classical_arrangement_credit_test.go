@@ -47,6 +47,11 @@ func TestRules_ArrangerCredit(t *testing.T) {
 			WantPass: false,
 			WantInfo: 1,
 		},
+		{
+			Name:     "valid - arranger without diacritics in title",
+			Actual:   NewTorrent().ClearTracks().AddTrack().WithTitle("Humoresque, arr. Dvorak").ClearArtists().WithArtists(domain.Artist{Name: "Bach", Role: domain.RoleComposer}, domain.Artist{Name: "Antonín Dvořák", Role: domain.RoleArranger}, domain.Artist{Name: "Orchestra", Role: domain.RoleEnsemble}).Build().Build(),
+			WantPass: true,
+		},
 		{
 			Name:     "valid - last name sufficient",
 			Actual:   NewTorrent().ClearTracks().AddTrack().WithTitle("Theme, arr. Rachmaninoff").ClearArtists().WithArtists(domain.Artist{Name: "Bach", Role: domain.RoleComposer}, domain.Artist{Name: "Rachmaninoff", Role: domain.RoleArranger}, domain.Artist{Name: "Orchestra", Role: domain.RoleEnsemble}).Build().Build(),
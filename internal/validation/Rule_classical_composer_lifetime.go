@@ -0,0 +1,106 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// premiereClaim matches titles claiming a first recording, in the languages
+// labels commonly print it in.
+var premiereClaim = regexp.MustCompile(`(?i)\b(?:world\s+)?premi[eè]re\s+recordings?\b|\bfirst\s+recordings?\b|ersteinspielung|premier\s+enregistrement`)
+
+// ComposerLifetime checks years against the lifetimes of well-known composers
+// (classical.composer_lifetime): a release year before the composer's birth, a
+// composition year outside their lifetime, or a premiere recording recorded
+// before the work was written. These are usually data-entry mistakes from
+// scraped sources, such as a composition year in the release year field.
+// WARNING level - the lifetime table covers only frequently recorded composers.
+func (r *Rules) ComposerLifetime(actualTorrent, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "classical.composer_lifetime",
+		Name:   "Years must fall within the composer's lifetime",
+		Level:  domain.LevelWarning,
+		Weight: 0.5,
+	}
+
+	if actualTorrent == nil {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	var issues []domain.ValidationIssue
+	reportedBirth := make(map[string]bool)
+	albumClaim := premiereClaim.MatchString(actualTorrent.Title)
+	for _, track := range actualTorrent.Tracks() {
+		for _, artist := range track.Artists {
+			if artist.Role != domain.RoleComposer {
+				continue
+			}
+			lifetime, ok := domain.ComposerLifetime(artist.Name)
+			if !ok {
+				continue
+			}
+
+			if year := actualTorrent.OriginalYear; year > 0 && year < lifetime.Born && !reportedBirth[artist.Name] {
+				reportedBirth[artist.Name] = true
+				issues = append(issues, domain.ValidationIssue{
+					Level: domain.LevelWarning,
+					Track: 0,
+					Rule:  meta.ID,
+					Message: fmt.Sprintf("Year %d predates the birth of %s (%d); is it the composition year?",
+						year, artist.Name, lifetime.Born),
+				})
+			}
+
+			if year := track.CompositionYear; year > 0 && !lifetime.Contains(year) {
+				issues = append(issues, domain.ValidationIssue{
+					Level: domain.LevelWarning,
+					Track: track.Track,
+					Disc:  track.Disc,
+					Path:  track.File.Path,
+					Rule:  meta.ID,
+					Message: fmt.Sprintf("Track %s: Composition year %d is outside the lifetime of %s (%s)",
+						formatTrackNumber(track), year, artist.Name, lifetime),
+				})
+			}
+
+			if albumClaim || premiereClaim.MatchString(track.Title) {
+				if message := impossiblePremiere(actualTorrent, track, artist.Name, lifetime); message != "" {
+					issues = append(issues, domain.ValidationIssue{
+						Level:   domain.LevelWarning,
+						Track:   track.Track,
+						Disc:    track.Disc,
+						Path:    track.File.Path,
+						Rule:    meta.ID,
+						Message: fmt.Sprintf("Track %s: %s", formatTrackNumber(track), message),
+					})
+				}
+			}
+		}
+	}
+
+	return RuleResult{Meta: meta, Issues: issues}
+}
+
+// impossiblePremiere explains why a premiere recording claim can't be right
+// for track, or returns "" when it's plausible: the recording can't predate
+// the work's composition, or (without a composition year) the composer's birth.
+func impossiblePremiere(torrent *domain.Torrent, track *domain.Track, composer string, lifetime domain.Lifetime) string {
+	recorded := torrent.TrackRecordingYears(track)
+	if len(recorded) == 0 && torrent.OriginalYear > 0 {
+		recorded = []int{torrent.OriginalYear}
+	}
+	if len(recorded) == 0 {
+		return ""
+	}
+	first := slices.Min(recorded)
+	switch {
+	case track.CompositionYear > 0 && first < track.CompositionYear:
+		return fmt.Sprintf("Claims a premiere recording made in %d, before the work was composed (%d)", first, track.CompositionYear)
+	case first < lifetime.Born:
+		return fmt.Sprintf("Claims a premiere recording made in %d, before %s was born (%d)", first, composer, lifetime.Born)
+	}
+	return ""
+}
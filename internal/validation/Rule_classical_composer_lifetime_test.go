@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_ComposerLifetime(t *testing.T) {
+	rules := NewRules()
+
+	build := func(album string, year int, composer string, track domain.Track) *domain.Torrent {
+		track.Disc, track.Track = 1, 1
+		track.Artists = []domain.Artist{{Name: composer, Role: domain.RoleComposer}}
+		return &domain.Torrent{Title: album, OriginalYear: year, Files: []domain.FileLike{&track}}
+	}
+
+	tests := []struct {
+		Name       string
+		Actual     *domain.Torrent
+		WantIssues int
+	}{
+		{Name: "pass - plausible years", Actual: build("Goldberg Variations", 1982, "Johann Sebastian Bach", domain.Track{Title: "Aria", CompositionYear: 1741})},
+		{Name: "pass - unknown composer", Actual: build("Songs", 1700, "Jane Doe", domain.Track{Title: "Song", CompositionYear: 1900})},
+		{Name: "pass - surname alone is ambiguous", Actual: build("Waltzes", 1750, "Strauss", domain.Track{Title: "Waltz"})},
+		{Name: "pass - living composer", Actual: build("Tabula Rasa", 1984, "Arvo Pärt", domain.Track{Title: "Fratres", CompositionYear: 2020})},
+		{Name: "warning - composition year as release year", Actual: build("Symphony No. 5", 1808, "Johannes Brahms", domain.Track{Title: "Allegro"}), WantIssues: 1},
+		{Name: "warning - composed after death", Actual: build("Requiem", 1991, "Wolfgang Amadeus Mozart", domain.Track{Title: "Lacrimosa", CompositionYear: 1792}), WantIssues: 1},
+		{Name: "warning - diacritics folded", Actual: build("Symphony No. 9", 1990, "Antonin Dvorak", domain.Track{Title: "Largo", CompositionYear: 1830}), WantIssues: 1},
+		{
+			Name:   "pass - premiere recording after composition",
+			Actual: build("Rediscovered Concertos (World Premiere Recordings)", 2015, "Antonio Vivaldi", domain.Track{Title: "Concerto RV 820", CompositionYear: 1720, RecordingYears: []int{2014}}),
+		},
+		{
+			Name:       "warning - premiere recording before composition",
+			Actual:     build("Late Works", 1960, "Dmitri Shostakovich", domain.Track{Title: "Symphony No. 15 (First Recording)", CompositionYear: 1971, RecordingYears: []int{1958}}),
+			WantIssues: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := rules.ComposerLifetime(tt.Actual, nil)
+			if result.Passed() != (tt.WantIssues == 0) {
+				t.Errorf("Passed = %v, want %v: %v", result.Passed(), tt.WantIssues == 0, result.Issues)
+			}
+			if len(result.Issues) != tt.WantIssues {
+				t.Errorf("Issues = %d, want %d: %v", len(result.Issues), tt.WantIssues, result.Issues)
+			}
+		})
+	}
+}
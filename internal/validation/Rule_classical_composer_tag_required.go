@@ -14,7 +14,9 @@ import (
 var composerNamePattern = regexp.MustCompile(`^[A-Z]\S*[\s\.]+\S+|^\S+\s+\S+`)
 
 // ComposerTagRequired checks that composer tag is present and uniquely identifiable (classical.composer)
-func (r *Rules) ComposerTagRequired(actualTrack, _ *domain.Track, _, _ *domain.Torrent) RuleResult {
+// On albums marked AllowMissingComposer (crossover or recital discs) a missing composer is only a warning.
+// Special composers (Anonymous, Traditional, Gregorian Chant) need no first name.
+func (r *Rules) ComposerTagRequired(actualTrack, _ *domain.Track, actualTorrent, _ *domain.Torrent) RuleResult {
 	meta := RuleMetadata{
 		ID:     "classical.composer",
 		Name:   "Composer tag required with identifiable name",
@@ -27,8 +29,12 @@ func (r *Rules) ComposerTagRequired(actualTrack, _ *domain.Track, _, _ *domain.T
 	// Find the composer
 	composers := actualTrack.Composers()
 	if len(composers) == 0 {
+		level := domain.LevelError
+		if actualTorrent != nil && actualTorrent.AllowMissingComposer {
+			level = domain.LevelWarning
+		}
 		issues = append(issues, domain.ValidationIssue{
-			Level:   domain.LevelError,
+			Level:   level,
 			Track:   actualTrack.Track,
 			Rule:    meta.ID,
 			Message: fmt.Sprintf("Track %s: Composer tag is missing", formatTrackNumber(actualTrack)),
@@ -37,6 +43,9 @@ func (r *Rules) ComposerTagRequired(actualTrack, _ *domain.Track, _, _ *domain.T
 	}
 
 	for _, composer := range composers {
+		if composer.IsSpecialComposer() {
+			continue
+		}
 		composerName := composer.Name
 		// Check that composer name is uniquely identifiable
 		// Must have at least first name or initial, not just last name
@@ -47,6 +47,12 @@ func TestRules_ComposerTagRequired(t *testing.T) {
 			WantPass: true,
 			Expect:   CaseExpectation{{Errors: 0, Warnings: 0, Info: 0}},
 		},
+		{
+			Name:     "valid - special composer",
+			Actual:   NewTorrent().WithTitle("Chant").ClearTracks().AddTrack().WithTitle("Kyrie").ClearArtists().WithArtists(domain.Artist{Name: "Anon.", Role: domain.RoleComposer}, domain.Artist{Name: "Schola Gregoriana", Role: domain.RoleEnsemble}).Build().Build(),
+			WantPass: true,
+			Expect:   CaseExpectation{{Errors: 0, Warnings: 0, Info: 0}},
+		},
 		{
 			Name:       "invalid - last name only (ambiguous)",
 			Actual:     NewTorrent().WithTitle("Beethoven Symphonies").ClearTracks().AddTrack().WithTitle("Symphony No. 5").ClearArtists().WithArtists(domain.Artist{Name: "Bach", Role: domain.RoleComposer}, domain.Artist{Name: "Vienna Philharmonic", Role: domain.RoleEnsemble}, domain.Artist{Name: "Herbert von Karajan", Role: domain.RoleConductor}).Build().Build(),
@@ -72,6 +78,24 @@ func TestRules_ComposerTagRequired(t *testing.T) {
 			WantErrors: 1,
 			Expect:     CaseExpectation{{Errors: 1, Warnings: 0, Info: 0}},
 		},
+		{
+			Name: "missing composer on crossover album - warning",
+			Actual: func() *domain.Torrent {
+				soloist := domain.Artist{Name: "Jacques Loussier", Role: domain.RoleSoloist}
+				track := &domain.Track{
+					File:    domain.File{Path: "01.flac"},
+					Disc:    1,
+					Track:   1,
+					Title:   "Improvisation",
+					Artists: []domain.Artist{soloist},
+				}
+				album := &domain.Album{Title: "Play Bach", OriginalYear: 1959, Tracks: []*domain.Track{track}, AllowMissingComposer: true}
+				return album.ToTorrent("test")
+			}(),
+			WantPass:     false,
+			WantWarnings: 1,
+			Expect:       CaseExpectation{{Errors: 0, Warnings: 1, Info: 0}},
+		},
 		{
 			Name: "multiple tracks, one missing composer",
 			Actual: func() *domain.Torrent {
@@ -159,7 +183,7 @@ func TestRules_ComposerTagRequired(t *testing.T) {
 				name = name + "/track#" + string(rune('1'+i))
 			}
 			t.Run(name, func(t *testing.T) {
-				result := rules.ComposerTagRequired(track, nil, nil, nil)
+				result := rules.ComposerTagRequired(track, nil, tt.Actual, nil)
 
 				errors, warnings, info := 0, 0, 0
 				for _, issue := range result.Issues {
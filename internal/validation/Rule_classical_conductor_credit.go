@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// ConductorCredited checks that orchestral tracks credit a conductor
+// (classical.conductor). A track needs one when it credits an orchestra, or
+// credits an ensemble of unknown size playing a symphony, concerto or
+// overture. Chamber ensembles (string quartets, piano trios, consorts) play
+// without a conductor and are never flagged.
+// WARNING level - orchestras directed from the violin or keyboard have none.
+func (r *Rules) ConductorCredited(actual, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "classical.conductor",
+		Name:   "Orchestral tracks should credit the conductor",
+		Level:  domain.LevelWarning,
+		Weight: 0.3,
+	}
+
+	var orchestral, missing []*domain.Track
+	var ensembles []string
+	for _, track := range actual.Tracks() {
+		ensemble, ok := needsConductor(track)
+		if !ok {
+			continue
+		}
+		orchestral = append(orchestral, track)
+		if !slices.ContainsFunc(track.Artists, func(a domain.Artist) bool { return a.Role == domain.RoleConductor }) {
+			missing = append(missing, track)
+			if !slices.Contains(ensembles, ensemble) {
+				ensembles = append(ensembles, ensemble)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	// No orchestral track credits a conductor: one album-level issue rather
+	// than one per track
+	if len(missing) == len(orchestral) && len(missing) > 1 {
+		return RuleResult{Meta: meta, Issues: []domain.ValidationIssue{{
+			Level: domain.LevelWarning,
+			Track: 0,
+			Rule:  meta.ID,
+			Message: fmt.Sprintf("No conductor credited on %d orchestral tracks (%s); add the conductor, or the soloist directing from the instrument",
+				len(missing), strings.Join(ensembles, ", ")),
+		}}}
+	}
+
+	var issues []domain.ValidationIssue
+	for _, track := range missing {
+		ensemble, _ := needsConductor(track)
+		issues = append(issues, domain.ValidationIssue{
+			Level:   domain.LevelWarning,
+			Rule:    meta.ID,
+			Message: fmt.Sprintf("Track %s credits %s but no conductor", formatTrackNumber(track), ensemble),
+		}.ForTrack(track))
+	}
+	return RuleResult{Meta: meta, Issues: issues}
+}
+
+// needsConductor returns the ensemble that makes track orchestral, and
+// whether there is one: an orchestra, or an ensemble of unknown size playing
+// an orchestral work. A chamber ensemble on the track rules it out.
+func needsConductor(track *domain.Track) (string, bool) {
+	var orchestra, other string
+	for _, artist := range track.Artists {
+		if artist.Role != domain.RoleEnsemble {
+			continue
+		}
+		switch artist.EnsembleScale() {
+		case domain.EnsembleChamber:
+			return "", false
+		case domain.EnsembleOrchestral:
+			if orchestra == "" {
+				orchestra = artist.Name
+			}
+		default:
+			if other == "" {
+				other = artist.Name
+			}
+		}
+	}
+	switch {
+	case orchestra != "":
+		return orchestra, true
+	case other != "" && domain.IsOrchestralWork(track.Title):
+		return other, true
+	}
+	return "", false
+}
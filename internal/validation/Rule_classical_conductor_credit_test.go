@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_ConductorCredited(t *testing.T) {
+	rules := NewRules()
+
+	beethoven := domain.Artist{Name: "Ludwig van Beethoven", Role: domain.RoleComposer}
+	orchestra := domain.Artist{Name: "Berliner Philharmoniker", Role: domain.RoleEnsemble}
+	conductor := domain.Artist{Name: "Herbert von Karajan", Role: domain.RoleConductor}
+	quartet := domain.Artist{Name: "Emerson String Quartet", Role: domain.RoleEnsemble}
+	ensemble := domain.Artist{Name: "Ensemble Modern", Role: domain.RoleEnsemble}
+
+	symphony := "Symphony No. 5: I. Allegro con brio"
+	chamber := "Grosse Fuge, Op. 133"
+	type track struct {
+		Title   string
+		Artists []domain.Artist
+	}
+	build := func(tracks ...track) *domain.Torrent {
+		b := NewTorrent().ClearTracks()
+		for i, tr := range tracks {
+			b = b.AddTrack().WithTrack(i + 1).WithTitle(tr.Title).ClearArtists().WithArtists(tr.Artists...).Build()
+		}
+		return b.Build()
+	}
+
+	tests := []struct {
+		Name       string
+		Actual     *domain.Torrent
+		WantPass   bool
+		WantIssues int
+	}{
+		{
+			Name:     "pass - orchestra with conductor",
+			Actual:   build(track{symphony, []domain.Artist{beethoven, orchestra, conductor}}, track{symphony, []domain.Artist{beethoven, orchestra, conductor}}),
+			WantPass: true,
+		},
+		{
+			Name:     "pass - string quartet without conductor, even in a symphony arrangement",
+			Actual:   build(track{symphony, []domain.Artist{beethoven, quartet}}, track{chamber, []domain.Artist{beethoven, quartet}}),
+			WantPass: true,
+		},
+		{
+			Name:     "pass - ensemble of unknown size outside orchestral works",
+			Actual:   build(track{symphony, []domain.Artist{beethoven, orchestra, conductor}}, track{chamber, []domain.Artist{beethoven, ensemble}}),
+			WantPass: true,
+		},
+		{
+			Name:       "warning - album never credits the conductor",
+			Actual:     build(track{symphony, []domain.Artist{beethoven, orchestra}}, track{symphony, []domain.Artist{beethoven, orchestra}}, track{chamber, []domain.Artist{beethoven, orchestra}}),
+			WantPass:   false,
+			WantIssues: 1,
+		},
+		{
+			Name:       "warning - one track lacks the conductor",
+			Actual:     build(track{symphony, []domain.Artist{beethoven, orchestra, conductor}}, track{symphony, []domain.Artist{beethoven, orchestra}}, track{symphony, []domain.Artist{beethoven, orchestra, conductor}}),
+			WantPass:   false,
+			WantIssues: 1,
+		},
+		{
+			Name:       "warning - ensemble of unknown size playing a symphony",
+			Actual:     build(track{symphony, []domain.Artist{beethoven, ensemble}}),
+			WantPass:   false,
+			WantIssues: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := rules.ConductorCredited(tt.Actual, nil)
+			if result.Passed() != tt.WantPass {
+				t.Errorf("Passed = %v, want %v: %v", result.Passed(), tt.WantPass, result.Issues)
+			}
+			if len(result.Issues) != tt.WantIssues {
+				t.Errorf("Issues = %d, want %d: %v", len(result.Issues), tt.WantIssues, result.Issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// ConsistentDefiniteArticle checks that an artist is spelled the same way throughout
+// the album, with or without a leading "The" (classical.article)
+func (r *Rules) ConsistentDefiniteArticle(actualTorrent, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "classical.article",
+		Name:   "Artist names use a leading \"The\" consistently",
+		Level:  domain.LevelWarning,
+		Weight: 0.5,
+	}
+
+	var issues []domain.ValidationIssue
+
+	spellings := make(map[string]map[string]bool) // ArticleKey -> spellings
+	add := func(a domain.Artist) {
+		key := domain.ArticleKey(a.Name)
+		if spellings[key] == nil {
+			spellings[key] = make(map[string]bool)
+		}
+		spellings[key][a.Name] = true
+	}
+	for _, a := range actualTorrent.AlbumArtist {
+		add(a)
+	}
+	for _, track := range actualTorrent.Tracks() {
+		for _, a := range track.Artists {
+			add(a)
+		}
+	}
+
+	keys := make([]string, 0, len(spellings))
+	for key := range spellings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if len(spellings[key]) < 2 {
+			continue
+		}
+		names := make([]string, 0, len(spellings[key]))
+		for name := range spellings[key] {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+		sort.Strings(names)
+		issues = append(issues, domain.ValidationIssue{
+			Level:   domain.LevelWarning,
+			Track:   0,
+			Rule:    meta.ID,
+			Message: fmt.Sprintf("Artist spelled inconsistently: %s (use one form, e.g. via artists.aliases in config)", strings.Join(names, ", ")),
+		})
+	}
+
+	return RuleResult{Meta: meta, Issues: issues}
+}
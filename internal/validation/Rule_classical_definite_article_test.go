@@ -0,0 +1,53 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_ConsistentDefiniteArticle(t *testing.T) {
+	rules := NewRules()
+
+	tests := []struct {
+		Name         string
+		Actual       *domain.Torrent
+		WantPass     bool
+		WantWarnings int
+	}{
+		{
+			Name:     "pass - consistent spelling",
+			Actual:   NewTorrent().WithArtist("The English Concert", domain.RoleEnsemble).AddTrack().WithTrack(2).WithFilename("02.flac").WithArtist("The English Concert", domain.RoleEnsemble).Build().Build(),
+			WantPass: true,
+		},
+		{
+			Name: "warning - mixed article across tracks",
+			Actual: NewTorrent().WithArtist("The English Concert", domain.RoleEnsemble).
+				AddTrack().WithTrack(2).WithFilename("02.flac").WithArtist("English Concert", domain.RoleEnsemble).Build().Build(),
+			WantPass:     false,
+			WantWarnings: 1,
+		},
+		{
+			Name: "warning - album artist differs from track artist",
+			Actual: func() *domain.Torrent {
+				torrent := NewTorrent().WithArtist("The Sixteen", domain.RoleEnsemble).Build()
+				torrent.AlbumArtist = []domain.Artist{{Name: "Sixteen", Role: domain.RoleEnsemble}}
+				return torrent
+			}(),
+			WantPass:     false,
+			WantWarnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := rules.ConsistentDefiniteArticle(tt.Actual, nil)
+			if result.Passed() != tt.WantPass {
+				t.Errorf("Passed = %v, want %v (issues: %v)", result.Passed(), tt.WantPass, result.Issues)
+			}
+			if len(result.Issues) != tt.WantWarnings {
+				t.Errorf("got %d issues, want %d", len(result.Issues), tt.WantWarnings)
+			}
+		})
+	}
+}
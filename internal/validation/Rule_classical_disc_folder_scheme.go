@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// DiscFolderScheme checks that the disc subdirectories of a multi-disc album all follow
+// one naming scheme ("CD1", "CD 01", "Disc 1 - Subtitle", ...) with consistent
+// zero-padding, and that each folder's number matches the disc it holds.
+// WARNING level - mixed layouts are usually left over from merging rips.
+func (r *Rules) DiscFolderScheme(actualTorrent, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "classical.disc_folders",
+		Name:   "Disc folders should share one naming scheme and match their disc numbers",
+		Level:  domain.LevelWarning,
+		Weight: 0.5,
+	}
+
+	if actualTorrent == nil || !actualTorrent.IsMultiDisc() {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	// First directory component per disc
+	folders := make(map[int]string)
+	for _, track := range actualTorrent.Tracks() {
+		components := strings.Split(filepath.ToSlash(filepath.Clean(track.File.Path)), "/")
+		if len(components) < 2 {
+			continue
+		}
+		if _, ok := folders[track.Disc]; !ok {
+			folders[track.Disc] = components[0]
+		}
+	}
+	if len(folders) <= 1 {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	discs := make([]int, 0, len(folders))
+	for disc := range folders {
+		discs = append(discs, disc)
+	}
+	sort.Ints(discs)
+
+	type parsedFolder struct {
+		scheme domain.DiscFolderScheme
+		number int
+		padded bool
+	}
+	parsed := make(map[int]parsedFolder)
+	counts := make(map[domain.DiscFolderScheme]int)
+	paddedCount := 0
+	for _, disc := range discs {
+		scheme, number, ok := domain.ParseDiscFolder(folders[disc])
+		if !ok {
+			continue
+		}
+		padded := scheme.Width > len(strconv.Itoa(number))
+		if padded {
+			paddedCount++
+		}
+		// Schemes differing only in width are compared through the padded flag
+		scheme.Width = 0
+		parsed[disc] = parsedFolder{scheme: scheme, number: number, padded: padded}
+		counts[scheme]++
+	}
+
+	// Subtitle-only or otherwise free-form folder names can't be judged
+	if len(parsed) == 0 {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	// The most common scheme (first disc's on a tie) is taken as the intended one
+	var majority domain.DiscFolderScheme
+	for _, disc := range discs {
+		if p, ok := parsed[disc]; ok && counts[p.scheme] > counts[majority] {
+			majority = p.scheme
+		}
+	}
+	wantPadded := paddedCount*2 > len(parsed)
+	example := majority
+	example.Width = 1
+	if wantPadded {
+		example.Width = 2
+	}
+
+	var issues []domain.ValidationIssue
+	for _, disc := range discs {
+		folder := folders[disc]
+		p, ok := parsed[disc]
+		switch {
+		case !ok || p.scheme != majority:
+			issues = append(issues, domain.ValidationIssue{
+				Level:   meta.Level,
+				Track:   0,
+				Rule:    meta.ID,
+				Message: fmt.Sprintf("Disc %d folder '%s' does not follow the '%s' naming used by the other discs", disc, folder, example),
+			})
+		case p.number != disc:
+			issues = append(issues, domain.ValidationIssue{
+				Level:   meta.Level,
+				Track:   0,
+				Rule:    meta.ID,
+				Message: fmt.Sprintf("Folder '%s' holds disc %d", folder, disc),
+			})
+		case p.padded != wantPadded && len(strconv.Itoa(p.number)) < 2:
+			issues = append(issues, domain.ValidationIssue{
+				Level:   meta.Level,
+				Track:   0,
+				Rule:    meta.ID,
+				Message: fmt.Sprintf("Disc %d folder '%s' is zero-padded inconsistently with the other discs (expected like '%s')", disc, folder, example),
+			})
+		}
+	}
+
+	return RuleResult{Meta: meta, Issues: issues}
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_DiscFolderScheme(t *testing.T) {
+	rules := NewRules()
+
+	tests := []struct {
+		Name      string
+		Actual    *domain.Torrent
+		WantPass  bool
+		WantCount int
+	}{
+		{
+			Name:     "pass - single disc",
+			Actual:   buildTorrentWithFilenames("01 - Track.flac"),
+			WantPass: true,
+		},
+		{
+			Name:     "pass - no folders",
+			Actual:   buildTorrentWithDiscTracks([]discTrack{{1, 1}, {2, 1}}),
+			WantPass: true,
+		},
+		{
+			Name: "pass - consistent CD folders",
+			Actual: buildTorrentWithFilenamesAndDiscs(
+				[]string{"CD1/01 - Track.flac", "CD2/01 - Track.flac"},
+				[]int{1, 2},
+			),
+			WantPass: true,
+		},
+		{
+			Name: "pass - padded with subtitles",
+			Actual: buildTorrentWithFilenamesAndDiscs(
+				[]string{"Disc 01 - Act I/01 - Track.flac", "Disc 02 - Act II/01 - Track.flac", "Disc 10 - Act X/01 - Track.flac"},
+				[]int{1, 2, 10},
+			),
+			WantPass: true,
+		},
+		{
+			Name: "pass - free-form folder names",
+			Actual: buildTorrentWithFilenamesAndDiscs(
+				[]string{"Act I/01 - Track.flac", "Act II/01 - Track.flac"},
+				[]int{1, 2},
+			),
+			WantPass: true,
+		},
+		{
+			Name: "warning - mixed schemes",
+			Actual: buildTorrentWithFilenamesAndDiscs(
+				[]string{"CD1/01 - Track.flac", "CD2/01 - Track.flac", "Disc 3/01 - Track.flac"},
+				[]int{1, 2, 3},
+			),
+			WantPass:  false,
+			WantCount: 1,
+		},
+		{
+			Name: "warning - mixed padding",
+			Actual: buildTorrentWithFilenamesAndDiscs(
+				[]string{"CD01/01 - Track.flac", "CD02/01 - Track.flac", "CD3/01 - Track.flac"},
+				[]int{1, 2, 3},
+			),
+			WantPass:  false,
+			WantCount: 1,
+		},
+		{
+			Name: "warning - folder number differs from disc",
+			Actual: buildTorrentWithFilenamesAndDiscs(
+				[]string{"CD1/01 - Track.flac", "CD3/01 - Track.flac"},
+				[]int{1, 2},
+			),
+			WantPass:  false,
+			WantCount: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := rules.DiscFolderScheme(tt.Actual, nil)
+			if result.Passed() != tt.WantPass {
+				t.Errorf("Passed = %v, want %v: %v", result.Passed(), tt.WantPass, result.Issues)
+			}
+			if len(result.Issues) != tt.WantCount {
+				t.Errorf("Issues = %d, want %d: %v", len(result.Issues), tt.WantCount, result.Issues)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 )
 
 // ComposerInFolderName checks that folder name contains composer (classical.folder_name)
@@ -35,9 +36,10 @@ func (r *Rules) ComposerInFolderName(actual, _ *domain.Torrent) RuleResult {
 
 	for _, track := range tracks {
 		for _, artist := range track.Artists {
-			if artist.Role == domain.RoleComposer {
+			// Special composers are not named in album titles
+			if artist.Role == domain.RoleComposer && !artist.IsSpecialComposer() {
 				name := artist.Name
-				lastName := lastName(name)
+				lastName := artistLastName(artist)
 				composerCounts[lastName]++
 				composerFullNames[lastName] = name
 			}
@@ -79,11 +81,12 @@ func (r *Rules) ComposerInFolderName(actual, _ *domain.Torrent) RuleResult {
 		return RuleResult{Meta: meta, Issues: nil}
 	}
 
-	albumTitleLower := strings.ToLower(albumTitle)
-	composerLower := strings.ToLower(primaryComposer)
+	// Compared case- and diacritic-insensitively, so "Dvorak" matches "Dvořák"
+	albumTitleLower := normalize.Name(albumTitle)
+	composerLower := normalize.Name(primaryComposer)
 	fullNameStr := composerFullNames[primaryComposer]
-	fullNameLower := strings.ToLower(fullNameStr)
-	base := strings.ToLower(composerBaseSurname(fullNameStr))
+	fullNameLower := normalize.Name(fullNameStr)
+	base := normalize.Name(composerBaseSurname(fullNameStr))
 
 	// Check for composer mention
 	if !strings.Contains(albumTitleLower, composerLower) && !strings.Contains(albumTitleLower, fullNameLower) && !strings.Contains(albumTitleLower, base) {
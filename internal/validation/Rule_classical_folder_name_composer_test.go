@@ -68,6 +68,18 @@ func TestRules_ComposerInFolderName(t *testing.T) {
 			ComposerName: "Wolfgang Amadeus Mozart",
 			WantPass:     true,
 		},
+		{
+			Name:         "valid - full name spelled without diacritics",
+			AlbumTitle:   "Antonin Dvorak - Symphony No. 9 [1961] [FLAC]",
+			ComposerName: "Antonín Dvořák",
+			WantPass:     true,
+		},
+		{
+			Name:         "valid - decomposed (NFD) title",
+			AlbumTitle:   "Anto\u0301ni\u0301n Dvor\u030ca\u0301k - Symphony No. 9 [1961] [FLAC]",
+			ComposerName: "Antonín Dvořák",
+			WantPass:     true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// keyStyleNames name key styles in messages.
+var keyStyleNames = map[domain.KeyStyle]string{
+	domain.KeyStyleEnglish: "English",
+	domain.KeyStyleSymbols: "symbol",
+	domain.KeyStyleGerman:  "German",
+}
+
+// TitleNotation checks that the track titles write key signatures ("C-sharp
+// minor", "C# minor", "cis-Moll") and work numbers ("No.", "Nr.", "no.") one
+// way across the album. The most common notation (the first track's on a tie)
+// is taken as the intended one.
+// WARNING level - mixed notation usually comes from merging sources.
+func (r *Rules) TitleNotation(actualTorrent, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "classical.title_notation",
+		Name:   "Key signatures and work numbers should be written one way across the album",
+		Level:  domain.LevelWarning,
+		Weight: 0.3,
+	}
+
+	if actualTorrent == nil {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	tracks := actualTorrent.Tracks()
+	keyStyles := make([][]domain.KeyNotation, len(tracks))
+	numbers := make([][]domain.NumberNotation, len(tracks))
+	var keyOrder []domain.KeyStyle
+	var numberOrder []string
+	keyCounts := make(map[domain.KeyStyle]int)
+	numberCounts := make(map[string]int)
+	for i, track := range tracks {
+		for _, k := range domain.FindKeys(track.Title) {
+			if k.Style == "" {
+				continue // English and symbol notation write natural keys alike
+			}
+			keyStyles[i] = append(keyStyles[i], k)
+			if keyCounts[k.Style] == 0 {
+				keyOrder = append(keyOrder, k.Style)
+			}
+			keyCounts[k.Style]++
+		}
+		for _, n := range domain.FindNumbers(track.Title) {
+			numbers[i] = append(numbers[i], n)
+			if numberCounts[n.Abbreviation] == 0 {
+				numberOrder = append(numberOrder, n.Abbreviation)
+			}
+			numberCounts[n.Abbreviation]++
+		}
+	}
+
+	var issues []domain.ValidationIssue
+	issue := func(track *domain.Track, message string) {
+		issues = append(issues, domain.ValidationIssue{
+			Level:   meta.Level,
+			Track:   track.Track,
+			Disc:    track.Disc,
+			Path:    track.File.Path,
+			Rule:    meta.ID,
+			Message: message,
+		})
+	}
+
+	if len(keyOrder) > 1 {
+		majority := keyOrder[0]
+		for _, style := range keyOrder {
+			if keyCounts[style] > keyCounts[majority] {
+				majority = style
+			}
+		}
+		for i, track := range tracks {
+			for _, k := range keyStyles[i] {
+				if k.Style != majority {
+					issue(track, fmt.Sprintf("Track %s writes the key '%s' in %s notation; the other tracks use %s notation ('%s')",
+						formatTrackNumber(track), k.Text, keyStyleNames[k.Style], keyStyleNames[majority], k.Key.Format(majority)))
+					break
+				}
+			}
+		}
+	}
+
+	if len(numberOrder) > 1 {
+		majority := numberOrder[0]
+		for _, abbreviation := range numberOrder {
+			if numberCounts[abbreviation] > numberCounts[majority] {
+				majority = abbreviation
+			}
+		}
+		for i, track := range tracks {
+			for _, n := range numbers[i] {
+				if n.Abbreviation != majority {
+					issue(track, fmt.Sprintf("Track %s numbers a work '%s'; the other tracks write '%s %s'",
+						formatTrackNumber(track), n.Text, majority, n.Number))
+					break
+				}
+			}
+		}
+	}
+
+	return RuleResult{Meta: meta, Issues: issues}
+}
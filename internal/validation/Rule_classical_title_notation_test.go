@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_TitleNotation(t *testing.T) {
+	rules := NewRules()
+
+	build := func(titles ...string) *domain.Torrent {
+		files := make([]domain.FileLike, len(titles))
+		for i, title := range titles {
+			files[i] = &domain.Track{Disc: 1, Track: i + 1, Title: title}
+		}
+		return &domain.Torrent{Title: "Album", Files: files}
+	}
+
+	tests := []struct {
+		Name   string
+		Actual *domain.Torrent
+		Want   []string
+	}{
+		{
+			Name:   "pass - consistent English notation",
+			Actual: build("Sonata No. 1 in B-flat major", "Sonata No. 2 in C-sharp minor", "Sonata No. 3 in D major"),
+		},
+		{
+			Name:   "pass - natural keys only",
+			Actual: build("Symphony No. 5 in C minor", "Symphony No. 6 in F major"),
+		},
+		{
+			Name:   "warning - German key among English ones",
+			Actual: build("Sonata in B-flat major", "Sonata in cis-Moll", "Sonata in E-flat major"),
+			Want:   []string{"Track 2 writes the key 'cis-Moll' in German notation; the other tracks use English notation ('C-sharp minor')"},
+		},
+		{
+			Name:   "warning - mixed numbering",
+			Actual: build("Prelude No. 1", "Prelude Nr. 2", "Prelude no. 3", "Prelude No. 4"),
+			Want: []string{
+				"Track 2 numbers a work 'Nr. 2'; the other tracks write 'No. 2'",
+				"Track 3 numbers a work 'no. 3'; the other tracks write 'No. 3'",
+			},
+		},
+		{
+			Name:   "warning - tie goes to the first track's notation",
+			Actual: build("Sonata in C# minor", "Sonata in E-flat major"),
+			Want:   []string{"Track 2 writes the key 'E-flat major' in English notation; the other tracks use symbol notation ('Eb major')"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			result := rules.TitleNotation(tt.Actual, nil)
+			var got []string
+			for _, issue := range result.Issues {
+				got = append(got, issue.Message)
+			}
+			if !slices.Equal(got, tt.Want) {
+				t.Errorf("Issues = %q, want %q", got, tt.Want)
+			}
+		})
+	}
+}
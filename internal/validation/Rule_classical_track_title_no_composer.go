@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 )
 
 // ComposerNotInTitle checks that composer name is NOT in track title (classical.track_title)
@@ -35,11 +36,15 @@ func (r *Rules) ComposerNotInTitle(actualTrack, _ *domain.Track, _, _ *domain.To
 	}
 
 	for _, composer := range composers {
+		// "Gregorian Chant" or "Traditional" in a title describes the music, not a credit
+		if composer.IsSpecialComposer() {
+			continue
+		}
 		// Detect using base surname (particle-independent), e.g., "Beethoven", "Bach"
 		base := baseSurnameFromFullName(composer.Name)
 		// Use word boundaries to avoid false positives
-		titleLower := strings.ToLower(title)
-		baseLower := strings.ToLower(base)
+		titleLower := normalize.Name(title)
+		baseLower := normalize.Name(base)
 
 		// Create a word boundary pattern
 		// Match: "Bach: Symphony" or "Symphony (Bach)" or "Bach's"
@@ -71,17 +76,17 @@ func (r *Rules) ComposerNotInTitle(actualTrack, _ *domain.Track, _, _ *domain.To
 func isComposerPartOfWorkTitle(title, composerLastName string) bool {
 	// Common patterns where composer is legitimately part of the work title
 	patterns := []string{
-		"on a theme by " + strings.ToLower(composerLastName),
-		"variations on " + strings.ToLower(composerLastName),
-		"after " + strings.ToLower(composerLastName),
-		"hommage to " + strings.ToLower(composerLastName),
-		"hommage à " + strings.ToLower(composerLastName),
-		"in memory of " + strings.ToLower(composerLastName),
+		"on a theme by " + composerLastName,
+		"variations on " + composerLastName,
+		"after " + composerLastName,
+		"hommage to " + composerLastName,
+		"hommage à " + composerLastName,
+		"in memory of " + composerLastName,
 	}
 
-	titleLower := strings.ToLower(title)
+	titleLower := normalize.Name(title)
 	for _, pattern := range patterns {
-		if strings.Contains(titleLower, pattern) {
+		if strings.Contains(titleLower, normalize.Name(pattern)) {
 			return true
 		}
 	}
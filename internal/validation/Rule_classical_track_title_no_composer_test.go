@@ -54,6 +54,16 @@ func TestRules_ComposerNotInTitle(t *testing.T) {
 			Actual: NewTorrent().ClearTracks().AddTrack().WithTitle("Symphony No. 40 (Mozart)").ClearArtists().WithArtist("Wolfgang Amadeus Mozart", domain.RoleComposer).Build().Build(),
 			Expect: CaseExpectation{{Errors: 1, Warnings: 0, Info: 0}},
 		},
+		{
+			Name:   "invalid - composer spelled without diacritics in title",
+			Actual: NewTorrent().ClearTracks().AddTrack().WithTitle("Dvorak: Symphony No. 9").ClearArtists().WithArtist("Antonín Dvořák", domain.RoleComposer).Build().Build(),
+			Expect: CaseExpectation{{Errors: 1, Warnings: 0, Info: 0}},
+		},
+		{
+			Name:   "valid - decomposed (NFD) hommage title",
+			Actual: NewTorrent().ClearTracks().AddTrack().WithTitle("Hommage a\u0300 Dvor\u030ca\u0301k").ClearArtists().WithArtist("Antonín Dvořák", domain.RoleComposer).Build().Build(),
+			Expect: CaseExpectation{{Errors: 0, Warnings: 0, Info: 0}},
+		},
 		{
 			Name:   "valid - different word containing composer name",
 			Actual: NewTorrent().ClearTracks().AddTrack().WithTitle("Bacharach Suite").ClearArtists().WithArtist("Johann Sebastian Bach", domain.RoleComposer).Build().Build(),
@@ -0,0 +1,34 @@
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// FilenamePolicy checks track filenames against the tracker's filename
+// conventions (domain.DefaultFilenamePolicy), the same policy the tag command
+// generates filenames with
+func (r *Rules) FilenamePolicy(actualTrack, _ *domain.Track, actualTorrent, _ *domain.Torrent) RuleResult {
+	meta := RuleMetadata{
+		ID:     "naming.filename_policy",
+		Name:   "File names must follow the tracker's naming conventions",
+		Level:  domain.LevelWarning,
+		Weight: 0.5,
+	}
+
+	if actualTrack.File.Path == "" {
+		return RuleResult{Meta: meta, Issues: nil}
+	}
+
+	var issues []domain.ValidationIssue
+	fileName := filepath.Base(actualTrack.File.Path)
+	for _, issue := range domain.DefaultFilenamePolicy.Check(fileName, actualTrack, actualTorrent) {
+		issue.Track = actualTrack.Track
+		issue.Rule = meta.ID
+		issue.Message = fmt.Sprintf("Track %s: '%s': %s", formatTrackNumber(actualTrack), fileName, issue.Message)
+		issues = append(issues, issue)
+	}
+	return RuleResult{Meta: meta, Issues: issues}
+}
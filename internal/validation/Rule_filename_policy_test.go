@@ -0,0 +1,46 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+func TestRules_FilenamePolicy(t *testing.T) {
+	rules := NewRules()
+	bach := domain.Artist{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}
+	handel := domain.Artist{Name: "George Frideric Handel", Role: domain.RoleComposer}
+
+	build := func(composers []domain.Artist, paths ...string) *domain.Torrent {
+		files := make([]domain.FileLike, len(paths))
+		for i, path := range paths {
+			files[i] = &domain.Track{File: domain.File{Path: path}, Disc: 1, Track: i + 1, Title: "Track",
+				Artists: []domain.Artist{composers[i%len(composers)]}}
+		}
+		return &domain.Torrent{Title: "Album", Files: files}
+	}
+
+	tests := []struct {
+		Name       string
+		Actual     *domain.Torrent
+		WantIssues int
+	}{
+		{Name: "pass - conventional names", Actual: build([]domain.Artist{bach}, "01 - Toccata.flac", "CD1/02 - Fugue.flac")},
+		{Name: "pass - no filename", Actual: build([]domain.Artist{bach}, "")},
+		{Name: "error - number runs into title", Actual: build([]domain.Artist{bach}, "01Toccata.flac", "02 - Fugue.flac"), WantIssues: 1},
+		{Name: "warning - single digits", Actual: build([]domain.Artist{bach}, "1 - Toccata.flac", "2 - Fugue.flac"), WantIssues: 2},
+		{Name: "info - composers missing", Actual: build([]domain.Artist{bach, handel}, "01 - Bach - Toccata.flac", "02 - Passacaglia.flac"), WantIssues: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			var issues []domain.ValidationIssue
+			for _, track := range tt.Actual.Tracks() {
+				issues = append(issues, rules.FilenamePolicy(track, nil, tt.Actual, nil).Issues...)
+			}
+			if len(issues) != tt.WantIssues {
+				t.Errorf("Issues = %d, want %d: %v", len(issues), tt.WantIssues, issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+	"github.com/cehbz/classical-tagger/internal/tagging"
+)
+
+// CheckCueSheets checks that the FILE entries of the CUE sheets under dir name
+// files beside them, so that the cue sheets shipped in a torrent still load.
+// An entry naming a file that was renamed, or the image of a rip since split
+// into tracks, is reported.
+// WARNING level - the audio is fine; only the cue sheet is stale.
+func CheckCueSheets(files fsys.FS, dir string) ([]domain.ValidationIssue, error) {
+	files = fsys.Or(files)
+	var issues []domain.ValidationIssue
+	err := files.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".cue") {
+			return nil
+		}
+		data, err := files.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		for _, name := range tagging.CueFiles(tagging.DecodeCueText(data)) {
+			target := filepath.Join(filepath.Dir(path), filepath.FromSlash(strings.ReplaceAll(name, `\`, "/")))
+			if _, err := files.Stat(target); err == nil {
+				continue
+			}
+			issues = append(issues, domain.ValidationIssue{
+				Level:   domain.LevelWarning,
+				Track:   -1, // directory-level
+				Path:    rel,
+				Rule:    "classical.cue_file_entries",
+				Message: fmt.Sprintf("CUE sheet names FILE %q, which is not in the torrent", name),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check cue sheets in %s: %w", dir, err)
+	}
+	return issues, nil
+}
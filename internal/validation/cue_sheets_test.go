@@ -0,0 +1,25 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+func TestCheckCueSheets(t *testing.T) {
+	mem := fsys.NewMem()
+	mem.MkdirAll("/album/Disc 1", 0755)
+	mem.WriteFile("/album/Disc 1/01 - Pie Jesu.flac", nil, 0644)
+	mem.WriteFile("/album/Disc 1/rip.cue", []byte("FILE \"01 - Pie Jesu.flac\" WAVE\r\nFILE \"Range.wav\" WAVE\r\n"), 0644)
+
+	issues, err := CheckCueSheets(mem, "/album")
+	if err != nil {
+		t.Fatalf("CheckCueSheets() error = %v", err)
+	}
+	if len(issues) != 1 {
+		t.Fatalf("CheckCueSheets() = %v, want one issue", issues)
+	}
+	if got, want := issues[0].String(), `[WARNING] Directory (Disc 1/rip.cue): classical.cue_file_entries - CUE sheet names FILE "Range.wav", which is not in the torrent`; got != want {
+		t.Errorf("issue = %q, want %q", got, want)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/filesystem"
+	"github.com/cehbz/classical-tagger/internal/normalize"
 )
 
 // DirectoryValidator validates directory and file naming conventions.
@@ -25,6 +27,7 @@ func (v *DirectoryValidator) ValidatePath(path string) []domain.ValidationIssue
 		issues = append(issues, domain.ValidationIssue{
 			Level:   domain.LevelError,
 			Track:   -1, // directory-level
+			Path:    path,
 			Rule:    "2.3.12",
 			Message: fmt.Sprintf("Path exceeds 180 characters (%d)", len(path)),
 		})
@@ -37,6 +40,7 @@ func (v *DirectoryValidator) ValidatePath(path string) []domain.ValidationIssue
 			issues = append(issues, domain.ValidationIssue{
 				Level:   domain.LevelError,
 				Track:   -1,
+				Path:    path,
 				Rule:    "2.3.20",
 				Message: fmt.Sprintf("Leading space not allowed in path component: %q", part),
 			})
@@ -48,20 +52,29 @@ func (v *DirectoryValidator) ValidatePath(path string) []domain.ValidationIssue
 }
 
 // ValidateStructure checks directory organization (single disc vs multi-disc).
+// Extras folders (a bonus DVD or other video) are not part of the audio layout
+// and are skipped.
 func (v *DirectoryValidator) ValidateStructure(basePath string, files []string) []domain.ValidationIssue {
 	var issues []domain.ValidationIssue
 
+	relPaths := make([]string, 0, len(files))
+	for _, file := range files {
+		relPath := strings.TrimPrefix(file, basePath)
+		relPaths = append(relPaths, strings.TrimPrefix(relPath, string(filepath.Separator)))
+	}
+	folderKinds := filesystem.ClassifyFolders(relPaths)
+
 	// Analyze directory structure
 	hasSubdirs := false
 	nestedLevels := 0
 	discDirs := make(map[string]bool)
 
-	for _, file := range files {
-		relPath := strings.TrimPrefix(file, basePath)
-		relPath = strings.TrimPrefix(relPath, string(filepath.Separator))
-
+	for _, relPath := range relPaths {
 		parts := strings.Split(relPath, string(filepath.Separator))
 		depth := len(parts) - 1 // subtract 1 for the filename itself
+		if depth > 0 && folderKinds[parts[0]] == filesystem.FolderExtras {
+			continue
+		}
 
 		if depth > nestedLevels {
 			nestedLevels = depth
@@ -119,8 +132,9 @@ func (v *DirectoryValidator) ValidateFolderName(folderName string, album *domain
 
 	// Check if folder name is meaningful (2.3.2)
 	// Minimum is "Album" title, but preferred is "Artist - Album (Year) - Format"
-	lowerFolder := strings.ToLower(folderName)
-	lowerAlbum := strings.ToLower(album.Title)
+	// Folder names read from macOS filesystems are often decomposed (NFD)
+	lowerFolder := normalize.Key(folderName, 0)
+	lowerAlbum := normalize.Key(album.Title, 0)
 
 	if !strings.Contains(lowerFolder, lowerAlbum) {
 		issues = append(issues, domain.ValidationIssue{
@@ -135,17 +149,17 @@ func (v *DirectoryValidator) ValidateFolderName(folderName string, album *domain
 	tracks := album.Tracks()
 	if len(tracks) > 0 {
 		// Find the first composer in the track artists
-		var composerName string
+		var composer domain.Artist
 		for _, artist := range tracks[0].Artists {
 			if artist.Role == domain.RoleComposer {
-				composerName = artist.Name
+				composer = artist
 				break
 			}
 		}
 
-		if composerName != "" {
-			composerLastName := lastName(composerName)
-			if !strings.Contains(lowerFolder, strings.ToLower(composerLastName)) {
+		if composerName := composer.Name; composerName != "" {
+			composerLastName := artistLastName(composer)
+			if !strings.Contains(lowerFolder, normalize.Key(composerLastName, 0)) {
 				issues = append(issues, domain.ValidationIssue{
 					Level:   domain.LevelWarning,
 					Track:   -1,
@@ -177,4 +191,3 @@ func isDiscDirectory(name string) bool {
 
 	return false
 }
-
@@ -88,6 +88,17 @@ func TestDirectoryValidator_ValidateStructure(t *testing.T) {
 			IsMultiDisc:    true,
 			WantErrorCount: 1,
 		},
+		{
+			Name: "multi disc with bonus DVD",
+			Files: []string{
+				"CD1/01 Track One.flac",
+				"CD2/01 Track One.flac",
+				"Bonus DVD/VIDEO_TS/VTS_01_1.VOB",
+				"Bonus DVD/VIDEO_TS/VIDEO_TS.IFO",
+			},
+			IsMultiDisc:    true,
+			WantErrorCount: 0,
+		},
 	}
 
 	for _, tt := range tests {
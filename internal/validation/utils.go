@@ -3,7 +3,6 @@ package validation
 import (
 	"fmt"
 	"strings"
-	"unicode"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
 )
@@ -220,6 +219,15 @@ func (tb *TrackBuilder) Build() *TorrentBuilder {
 	return tb.builder
 }
 
+// artistLastName returns the family name of an artist, using an explicit SortName
+// when present and falling back to the lastName heuristic otherwise.
+func artistLastName(artist domain.Artist) string {
+	if artist.SortName != "" {
+		return artist.LastName()
+	}
+	return lastName(artist.Name)
+}
+
 // lastNames extracts last name(s) from a composer name
 // "Ludwig van Beethoven" -> ["Beethoven"]
 // "J.S. Bach" -> ["Bach"]
@@ -276,7 +284,7 @@ func buildTorrentWithTitlesAndFilenames(titleFiles []titleFile) *domain.Torrent
 
 // isAudioFile checks if a filename is an audio file based on extension
 func isAudioFile(filename string) bool {
-	audioExtensions := []string{".flac", ".mp3", ".wav", ".m4a", ".aac", ".ogg", ".wma", ".ape"}
+	audioExtensions := []string{".flac", ".dsf", ".dff", ".mp3", ".wav", ".m4a", ".aac", ".ogg", ".wma", ".ape"}
 	filenameLower := strings.ToLower(filename)
 	for _, ext := range audioExtensions {
 		if strings.HasSuffix(filenameLower, ext) {
@@ -585,20 +593,6 @@ func buildTorrentWithGoodCaps() *domain.Torrent {
 	return builder.Build()
 }
 
-// normalizeNameForInclusion normalizes a name for inclusion checks:
-// - lowercases letters
-// - strips spaces and punctuation (keeps letters with diacritics and digits)
-func normalizeNameForInclusion(s string) string {
-	var out []rune
-	for _, r := range s {
-		lr := unicode.ToLower(r)
-		if unicode.IsLetter(lr) || unicode.IsDigit(lr) {
-			out = append(out, lr)
-		}
-	}
-	return string(out)
-}
-
 // buildTorrentWithBadCaps creates torrent with poor capitalization
 func buildTorrentWithBadCaps() *domain.Torrent {
 	builder := NewTorrent().
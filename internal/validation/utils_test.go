@@ -1,6 +1,10 @@
 package validation
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
 
 
 func TestLastName(t *testing.T) {
@@ -28,3 +32,22 @@ func TestLastName(t *testing.T) {
 		})
 	}
 }
+
+func TestArtistLastName(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Artist domain.Artist
+		Want   string
+	}{
+		{"heuristic fallback", domain.Artist{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}, "Bach"},
+		{"sort name", domain.Artist{Name: "Felix Mendelssohn Bartholdy", Role: domain.RoleComposer, SortName: "Mendelssohn Bartholdy, Felix"}, "Mendelssohn Bartholdy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := artistLastName(tt.Artist); got != tt.Want {
+				t.Errorf("artistLastName() = %v, want %v", got, tt.Want)
+			}
+		})
+	}
+}
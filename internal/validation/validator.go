@@ -1,43 +1,138 @@
 package validation
 
-import "github.com/cehbz/classical-tagger/internal/domain"
+import (
+	"runtime"
+	"sync"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/storage"
+)
 
 // Check validates a torrent's metadata against validation rules.
 // If reference is nil, only non-reference-dependent validations are performed.
-// Returns all validation issues found.
+// Returns all validation issues found: the torrent-level rules' first, then
+// each track's, in the same order however the work was scheduled.
+//
+// Rules only read the torrents, so they run concurrently in independent
+// groups: each torrent-level rule, and the track-level rules for each track.
 func Check(actual, reference *domain.Torrent) []domain.ValidationIssue {
-	var issues []domain.ValidationIssue
 	rules := NewRules()
-
-	// Run all torrent-level rules
 	torrentRules := rules.TorrentRules()
-	for _, rule := range torrentRules {
-		result := rule(actual, reference)
-		issues = append(issues, result.Issues...)
-	}
-
-	// Run all track-level rules
 	trackRules := rules.TrackRules()
 
-	// Iterate through tracks and validate each one
 	actualTracks := actual.Tracks()
 	refTracks := []*domain.Track(nil)
 	if reference != nil {
 		refTracks = reference.Tracks()
 	}
 
-	for i, actualTrack := range actualTracks {
+	// Each group writes its issues to its own slot: torrent rules first, then
+	// tracks, so joining the slots gives the serial order
+	groups := make([][]domain.ValidationIssue, len(torrentRules)+len(actualTracks))
+	run := func(group int) {
+		if group < len(torrentRules) {
+			groups[group] = torrentRules[group](actual, reference).Issues
+			return
+		}
+		i := group - len(torrentRules)
 		var refTrack *domain.Track
 		if i < len(refTracks) {
 			refTrack = refTracks[i]
 		}
+		groups[group] = checkTrack(actualTracks[i], refTrack, actual, reference, trackRules)
+	}
+	runGroups(len(groups), run)
 
-		// Run each track rule for this track
-		for _, rule := range trackRules {
-			result := rule(actualTrack, refTrack, actual, reference)
-			issues = append(issues, result.Issues...)
-		}
+	var issues []domain.ValidationIssue
+	for _, group := range groups {
+		issues = append(issues, group...)
 	}
+	locateIssues(issues, actual)
+	return issues
+}
 
+// checkTrack runs each track rule for one track.
+func checkTrack(actualTrack, refTrack *domain.Track, actual, reference *domain.Torrent, trackRules []TrackRuleFunc) []domain.ValidationIssue {
+	var issues []domain.ValidationIssue
+	for _, rule := range trackRules {
+		result := rule(actualTrack, refTrack, actual, reference)
+		for _, issue := range result.Issues {
+			if issue.Path == "" && issue.Track == actualTrack.Track {
+				issue = issue.ForTrack(actualTrack)
+			}
+			issues = append(issues, issue)
+		}
+	}
 	return issues
 }
+
+// runGroups calls run for each group index in 0..n-1 on up to GOMAXPROCS
+// goroutines, returning when all have finished.
+func runGroups(n int, run func(group int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		for group := range n {
+			run(group)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	jobs := make(chan int, workers)
+	for range workers {
+		wg.Go(func() {
+			for group := range jobs {
+				run(group)
+			}
+		})
+	}
+	for group := range n {
+		jobs <- group
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// locateIssues gives track-level issues that name only a track number the disc
+// and file path of that track, when the number is unambiguous, so reports point
+// at a file. Discs are kept only on multi-disc albums.
+func locateIssues(issues []domain.ValidationIssue, actual *domain.Torrent) {
+	multiDisc := actual.IsMultiDisc()
+	for i := range issues {
+		issue := &issues[i]
+		if issue.Track > 0 && issue.Path == "" {
+			var match *domain.Track
+			for _, track := range actual.Tracks() {
+				if track.Track != issue.Track || (issue.Disc > 0 && track.Disc != issue.Disc) {
+					continue
+				}
+				if match != nil {
+					match = nil
+					break
+				}
+				match = track
+			}
+			if match != nil {
+				*issue = issue.ForTrack(match)
+			}
+		}
+		if !multiDisc {
+			issue.Disc = 0
+		}
+	}
+}
+
+// CheckJSON validates a metadata JSON document, such as an editor buffer being
+// saved, without touching the file system. Documents written with an older
+// schema are migrated first. The error is for data that is not metadata JSON;
+// it wraps a *json.SyntaxError, whose Offset locates malformed JSON.
+func CheckJSON(data []byte) ([]domain.ValidationIssue, error) {
+	torrent, err := storage.NewRepository().LoadFromJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return Check(torrent, nil), nil
+}
@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cehbz/classical-tagger/internal/domain"
+)
+
+// largeTorrent builds a well-tagged 500-track album on five discs, the size of
+// a complete-works box set.
+func largeTorrent() *domain.Torrent {
+	torrent := &domain.Torrent{
+		RootPath:     "Bach - Complete Cantatas (2000) [FLAC]",
+		Title:        "Complete Cantatas",
+		OriginalYear: 2000,
+		Edition:      &domain.Edition{Label: "Brilliant Classics", CatalogNumber: "99363", Year: 2000},
+		AlbumArtist: []domain.Artist{
+			{Name: "Netherlands Bach Collegium", Role: domain.RoleEnsemble},
+			{Name: "Pieter Jan Leusink", Role: domain.RoleConductor},
+		},
+	}
+	for i := range 500 {
+		disc, track := i/100+1, i%100+1
+		title := fmt.Sprintf("Cantata BWV %d: %d. Aria", i+1, track%7+1)
+		torrent.Files = append(torrent.Files, &domain.Track{
+			File:  domain.File{Path: fmt.Sprintf("CD%d/%02d - %s.flac", disc, track, title)},
+			Disc:  disc,
+			Track: track,
+			Title: title,
+			Artists: []domain.Artist{
+				{Name: "Johann Sebastian Bach", Role: domain.RoleComposer},
+				{Name: "Netherlands Bach Collegium", Role: domain.RoleEnsemble},
+				{Name: "Pieter Jan Leusink", Role: domain.RoleConductor},
+			},
+		})
+	}
+	return torrent
+}
+
+// BenchmarkCheck measures running every rule over a large album, alone and
+// against a reference.
+func BenchmarkCheck(b *testing.B) {
+	actual := largeTorrent()
+	for _, bc := range []struct {
+		name      string
+		reference *domain.Torrent
+	}{
+		{"NoReference", nil},
+		{"Reference", largeTorrent()},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				Check(actual, bc.reference)
+			}
+		})
+	}
+}
@@ -1,9 +1,15 @@
 package validation
 
 import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"runtime"
+	"slices"
 	"testing"
 
 	"github.com/cehbz/classical-tagger/internal/domain"
+	"github.com/cehbz/classical-tagger/internal/storage"
 )
 
 func TestCheck(t *testing.T) {
@@ -38,7 +44,7 @@ func TestCheck(t *testing.T) {
 				},
 			},
 			WantErrorCount: 0, // No errors - RIAS is now recognized as acronym
-			WantWarnCount:  3, // Rule 2.3.2: missing separator and year (2 warnings) + other warnings
+			WantWarnCount:  4, // Rule 2.3.2: missing separator and year (2 warnings) + "01 " filename separator + other warnings
 		},
 		{
 			Name: "missing edition",
@@ -107,3 +113,123 @@ func TestCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckJSON(t *testing.T) {
+	data := []byte(`{
+  "schema_version": 1,
+  "root_path": "Mendelssohn - Christmas Motets (2013) [FLAC]",
+  "title": "Christmas Motets",
+  "original_year": 2013,
+  "files": [
+    {"path": "01 - Frohlocket.flac", "disc": 1, "track": 1, "title": "Frohlocket", "artists": []}
+  ]
+}`)
+	issues, err := CheckJSON(data)
+	if err != nil {
+		t.Fatalf("CheckJSON() error = %v", err)
+	}
+	if !slices.ContainsFunc(issues, func(i domain.ValidationIssue) bool { return i.Level == domain.LevelError }) {
+		t.Errorf("CheckJSON() = %v, want an error for the track without a composer", issues)
+	}
+	if want := Check(mustLoad(t, data), nil); len(issues) != len(want) {
+		t.Errorf("CheckJSON() found %d issues, Check() %d", len(issues), len(want))
+	}
+
+	var syntax *json.SyntaxError
+	if _, err := CheckJSON([]byte(`{"title": "Unterminated`)); !errors.As(err, &syntax) {
+		t.Errorf("CheckJSON(malformed) error = %v, want a *json.SyntaxError", err)
+	}
+}
+
+func TestCheck_IssueLocations(t *testing.T) {
+	composer := domain.Artist{Name: "Johann Sebastian Bach", Role: domain.RoleComposer}
+	torrent := &domain.Torrent{
+		Title:        "Cantatas",
+		RootPath:     "Bach - Cantatas (2000) [FLAC]",
+		OriginalYear: 2000,
+		Files: []domain.FileLike{
+			&domain.Track{File: domain.File{Path: "CD1/01 - Aria.flac"}, Disc: 1, Track: 1, Title: "Aria", Artists: []domain.Artist{composer}},
+			&domain.Track{File: domain.File{Path: "CD2/01 - Chorale.flac"}, Disc: 2, Track: 1, Title: "Chorale", Artists: []domain.Artist{composer}},
+		},
+	}
+
+	// Both tracks lack performers: each issue names its own disc and file
+	var located []domain.ValidationIssue
+	for _, issue := range Check(torrent, nil) {
+		if issue.Track > 0 {
+			located = append(located, issue)
+			if issue.Path == "" || issue.Disc == 0 {
+				t.Errorf("issue %v has no file path or disc", issue)
+			}
+		}
+	}
+	if !slices.ContainsFunc(located, func(i domain.ValidationIssue) bool { return i.Disc == 2 && i.Path == "CD2/01 - Chorale.flac" }) {
+		t.Errorf("issues = %v, want one located at disc 2 track 1", located)
+	}
+
+	// Single-disc albums leave the disc out
+	torrent.Files = torrent.Files[:1]
+	for _, issue := range Check(torrent, nil) {
+		if issue.Disc != 0 {
+			t.Errorf("single-disc issue %v has disc %d, want 0", issue, issue.Disc)
+		}
+	}
+}
+
+// TestCheck_DeterministicOrder runs the rule groups concurrently and expects
+// the issues in the same order as running them one at a time.
+func TestCheck_DeterministicOrder(t *testing.T) {
+	actual, reference := largeTorrent(), largeTorrent()
+	for i, track := range actual.Tracks() {
+		switch i % 7 {
+		case 0:
+			track.Artists = track.Artists[1:] // No composer
+		case 3:
+			track.Title = "  " + track.Title
+		case 5:
+			reference.Tracks()[i].Title = "Cantata"
+		}
+	}
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	want := Check(actual, reference)
+	if len(want) == 0 {
+		t.Fatal("Check() found no issues in the defective album")
+	}
+	runtime.GOMAXPROCS(8)
+	for range 5 {
+		if got := Check(actual, reference); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Check() issues differ from the serial run: got %d, want %d", len(got), len(want))
+		}
+	}
+}
+
+func TestLocateIssues(t *testing.T) {
+	torrent := &domain.Torrent{Files: []domain.FileLike{
+		&domain.Track{File: domain.File{Path: "CD1/01.flac"}, Disc: 1, Track: 1},
+		&domain.Track{File: domain.File{Path: "CD1/02.flac"}, Disc: 1, Track: 2},
+		&domain.Track{File: domain.File{Path: "CD2/01.flac"}, Disc: 2, Track: 1},
+	}}
+	issues := []domain.ValidationIssue{
+		{Track: 2},          // unique number
+		{Track: 1},          // on both discs
+		{Track: 1, Disc: 2}, // disc given
+		{Track: 0},          // album-level
+	}
+	locateIssues(issues, torrent)
+	want := []string{"CD1/02.flac", "", "CD2/01.flac", ""}
+	for i, issue := range issues {
+		if issue.Path != want[i] {
+			t.Errorf("issue %d Path = %q, want %q", i, issue.Path, want[i])
+		}
+	}
+}
+
+func mustLoad(t *testing.T, data []byte) *domain.Torrent {
+	t.Helper()
+	torrent, err := storage.NewRepository().LoadFromJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return torrent
+}
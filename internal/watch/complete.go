@@ -0,0 +1,172 @@
+// Package watch tells when an album directory being copied or downloaded into
+// a watched folder is complete, so extract -wait-complete does not process it
+// half written. A directory is complete once its files have kept the same sizes and
+// modification times for a settle period (debouncing bursts of writes), no
+// process holds one of them open for writing, no partial download file remains,
+// and, when a marker is configured, the marker file exists.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
+	"github.com/cehbz/classical-tagger/internal/config"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+// Options are the tunables of completion detection.
+type Options struct {
+	Settle       time.Duration // How long the files must stay unchanged
+	Poll         time.Duration // Interval between checks while waiting
+	Marker       string        // File that must exist, e.g. ".complete" ("": none)
+	CheckWriters bool          // Wait while a process has a file open for writing
+}
+
+// DefaultOptions suit copies over a network share: a half-minute lull in writes
+// is taken as the end of the copy.
+var DefaultOptions = Options{
+	Settle:       30 * time.Second,
+	Poll:         2 * time.Second,
+	CheckWriters: true,
+}
+
+// LoadOptions returns the options in the config file's watch section, over
+// DefaultOptions.
+func LoadOptions() Options {
+	cfg := config.LoadWatch()
+	opts := DefaultOptions
+	if cfg.SettleSeconds > 0 {
+		opts.Settle = time.Duration(cfg.SettleSeconds) * time.Second
+	}
+	if cfg.PollSeconds > 0 {
+		opts.Poll = time.Duration(cfg.PollSeconds) * time.Second
+	}
+	opts.Marker = cfg.Marker
+	opts.CheckWriters = !cfg.IgnoreWriters
+	return opts
+}
+
+// partialSuffixes end the names of files downloaders and copy tools write
+// before renaming them into place.
+var partialSuffixes = []string{".part", ".partial", ".crdownload", ".!qb", ".tmp"}
+
+// fileState is what a snapshot records of one file.
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// Detector checks one directory for completion. Checks must be made in turn:
+// the settle period runs from the first check to see the files as they are.
+type Detector struct {
+	Dir     string
+	Options Options
+	FS      fsys.FS     // nil: the operating system
+	Clock   clock.Clock // nil: the system clock
+	// Writers lists the files under a directory open for writing (nil: the
+	// platform's openWriters)
+	Writers func(dir string) ([]string, error)
+
+	last   map[string]fileState
+	stable time.Time // When the files were first seen as in last
+}
+
+// NewDetector returns a detector for dir.
+func NewDetector(dir string, opts Options) *Detector {
+	return &Detector{Dir: dir, Options: opts}
+}
+
+// Status is the outcome of a check.
+type Status struct {
+	Complete bool
+	Reason   string // Why the directory is not complete yet
+}
+
+// Check reports whether the directory is complete.
+func (d *Detector) Check() (Status, error) {
+	files := fsys.Or(d.FS)
+	now := clock.Or(d.Clock).Now()
+
+	snapshot := make(map[string]fileState)
+	var partial string
+	err := files.WalkDir(d.Dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		snapshot[path] = fileState{info.Size(), info.ModTime()}
+		name := strings.ToLower(entry.Name())
+		for _, suffix := range partialSuffixes {
+			if partial == "" && strings.HasSuffix(name, suffix) {
+				partial = path
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to scan %s: %w", d.Dir, err)
+	}
+
+	if d.last == nil || !maps.Equal(snapshot, d.last) {
+		d.last = snapshot
+		d.stable = now
+	}
+	if len(snapshot) == 0 {
+		return Status{Reason: "no files yet"}, nil
+	}
+	if partial != "" {
+		return Status{Reason: fmt.Sprintf("partial file %s", filepath.Base(partial))}, nil
+	}
+	if d.Options.Marker != "" {
+		if _, ok := snapshot[filepath.Join(d.Dir, d.Options.Marker)]; !ok {
+			return Status{Reason: fmt.Sprintf("no %s marker", d.Options.Marker)}, nil
+		}
+	}
+	if settled := now.Sub(d.stable); settled < d.Options.Settle {
+		return Status{Reason: fmt.Sprintf("files changed %s ago", settled.Round(time.Second))}, nil
+	}
+	if d.Options.CheckWriters {
+		writers := d.Writers
+		if writers == nil {
+			writers = openWriters
+		}
+		open, err := writers(d.Dir)
+		if err != nil {
+			return Status{}, fmt.Errorf("failed to check writers in %s: %w", d.Dir, err)
+		}
+		if len(open) > 0 {
+			// A writer may be about to resume, so settle again once it closes
+			d.stable = now
+			return Status{Reason: fmt.Sprintf("%s is open for writing", filepath.Base(open[0]))}, nil
+		}
+	}
+	return Status{Complete: true}, nil
+}
+
+// Wait checks the directory every Poll until it is complete, ctx is done or a
+// check fails.
+func (d *Detector) Wait(ctx context.Context) error {
+	for {
+		status, err := d.Check()
+		if err != nil || status.Complete {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not complete (%s): %w", d.Dir, status.Reason, ctx.Err())
+		case <-clock.Or(d.Clock).After(d.Options.Poll):
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cehbz/classical-tagger/internal/clock"
+	"github.com/cehbz/classical-tagger/internal/fsys"
+)
+
+// newTestDetector returns a detector over an in-memory album directory whose
+// file modification times follow a fake clock, with no open writers.
+func newTestDetector(t *testing.T, opts Options) (*Detector, *fsys.Mem, *clock.Fake) {
+	t.Helper()
+	now := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	files := &fsys.Mem{Clock: now}
+	if err := files.MkdirAll("/incoming/Album/CD1", 0755); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDetector("/incoming/Album", opts)
+	d.FS = files
+	d.Clock = now
+	d.Writers = func(string) ([]string, error) { return nil, nil }
+	return d, files, now
+}
+
+func checkStatus(t *testing.T, d *Detector, wantComplete bool, wantReason string) {
+	t.Helper()
+	status, err := d.Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if status.Complete != wantComplete || !strings.Contains(status.Reason, wantReason) {
+		t.Errorf("Check() = %+v, want complete %v with reason containing %q", status, wantComplete, wantReason)
+	}
+}
+
+func TestDetector_SlowCopy(t *testing.T) {
+	d, files, now := newTestDetector(t, Options{Settle: 30 * time.Second})
+	checkStatus(t, d, false, "no files yet")
+
+	// A copy writing a track in bursts, pausing up to 20 seconds between them
+	data := []byte{}
+	for range 4 {
+		data = append(data, make([]byte, 1<<10)...)
+		files.WriteFile("/incoming/Album/CD1/01 Allegro.flac", data, 0644)
+		checkStatus(t, d, false, "files changed 0s ago")
+		now.Advance(20 * time.Second)
+		checkStatus(t, d, false, "files changed 20s ago")
+	}
+
+	// A new file restarts the settle period even when the others are unchanged
+	files.WriteFile("/incoming/Album/CD1/02 Adagio.flac", make([]byte, 1<<10), 0644)
+	now.Advance(29 * time.Second)
+	checkStatus(t, d, false, "files changed 0s ago")
+	now.Advance(29 * time.Second)
+	checkStatus(t, d, false, "files changed 29s ago")
+	now.Advance(time.Second)
+	checkStatus(t, d, true, "")
+}
+
+func TestDetector_PartialFile(t *testing.T) {
+	d, files, now := newTestDetector(t, Options{Settle: 30 * time.Second})
+	files.WriteFile("/incoming/Album/CD1/01 Allegro.flac", []byte("fLaC"), 0644)
+	files.WriteFile("/incoming/Album/CD1/02 Adagio.flac.part", []byte("fL"), 0644)
+	checkStatus(t, d, false, "partial file 02 Adagio.flac.part")
+	now.Advance(time.Minute)
+	checkStatus(t, d, false, "partial file")
+
+	// Renamed into place, the file settles like any other change
+	files.Rename("/incoming/Album/CD1/02 Adagio.flac.part", "/incoming/Album/CD1/02 Adagio.flac")
+	checkStatus(t, d, false, "files changed 0s ago")
+	now.Advance(30 * time.Second)
+	checkStatus(t, d, true, "")
+}
+
+func TestDetector_Marker(t *testing.T) {
+	d, files, now := newTestDetector(t, Options{Settle: 30 * time.Second, Marker: ".complete"})
+	files.WriteFile("/incoming/Album/CD1/01 Allegro.flac", []byte("fLaC"), 0644)
+	now.Advance(time.Hour)
+	checkStatus(t, d, false, "no .complete marker")
+
+	files.WriteFile("/incoming/Album/.complete", nil, 0644)
+	checkStatus(t, d, false, "files changed 0s ago")
+	now.Advance(30 * time.Second)
+	checkStatus(t, d, true, "")
+}
+
+func TestDetector_OpenWriters(t *testing.T) {
+	d, files, now := newTestDetector(t, Options{Settle: 30 * time.Second, CheckWriters: true})
+	files.WriteFile("/incoming/Album/CD1/01 Allegro.flac", []byte("fLaC"), 0644)
+
+	// A copy that keeps the file open while stalled
+	open := []string{"/incoming/Album/CD1/01 Allegro.flac"}
+	d.Writers = func(dir string) ([]string, error) { return open, nil }
+	checkStatus(t, d, false, "files changed 0s ago")
+	now.Advance(time.Minute)
+	checkStatus(t, d, false, "01 Allegro.flac is open for writing")
+
+	// Once it closes the file, the settle period starts over
+	open = nil
+	now.Advance(10 * time.Second)
+	checkStatus(t, d, false, "files changed 10s ago")
+	now.Advance(20 * time.Second)
+	checkStatus(t, d, true, "")
+
+	// Without CheckWriters open files are not looked for
+	d.Options.CheckWriters = false
+	open = []string{"/incoming/Album/CD1/01 Allegro.flac"}
+	checkStatus(t, d, true, "")
+
+	d.Options.CheckWriters = true
+	d.Writers = func(string) ([]string, error) { return nil, errors.New("no /proc") }
+	if _, err := d.Check(); err == nil {
+		t.Error("Check() error = nil, want the writer listing's error")
+	}
+}
+
+func TestDetector_Wait(t *testing.T) {
+	d, files, now := newTestDetector(t, Options{Settle: 30 * time.Second, Poll: 5 * time.Second})
+	files.WriteFile("/incoming/Album/CD1/01 Allegro.flac", []byte("fLaC"), 0644)
+
+	done := make(chan error, 1)
+	go func() { done <- d.Wait(context.Background()) }()
+
+	// Keep appending for a while, then stop; Wait returns a settle period later
+	data := []byte("fLaC")
+	polls := 0
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Wait() error = %v", err)
+			}
+			if polls != 16 {
+				t.Errorf("Wait() returned after %d polls, want 16 (10 while copying, 6 to settle)", polls)
+			}
+			return
+		default:
+		}
+		if now.Waiters() == 0 {
+			time.Sleep(time.Millisecond)
+			continue
+		}
+		if polls < 10 {
+			data = append(data, 0)
+			files.WriteFile("/incoming/Album/CD1/01 Allegro.flac", data, 0644)
+		}
+		polls++
+		now.Advance(5 * time.Second)
+	}
+}
+
+func TestDetector_WaitCancelled(t *testing.T) {
+	d, files, _ := newTestDetector(t, Options{Settle: 30 * time.Second, Poll: 5 * time.Second})
+	files.WriteFile("/incoming/Album/CD1/01 Allegro.flac.part", []byte("fL"), 0644)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := d.Wait(ctx)
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "partial file") {
+		t.Errorf("Wait() error = %v, want cancellation naming the partial file", err)
+	}
+}
@@ -0,0 +1,75 @@
+package watch
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/cehbz/classical-tagger/internal/filesystem"
+)
+
+// openWriters lists the files under dir some process has open for writing,
+// from each process's file descriptors in /proc. Processes of other users,
+// whose descriptors cannot be read, are skipped.
+func openWriters(dir string) ([]string, error) {
+	// Descriptors name files by their resolved paths
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return nil, err
+	}
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	var open []string
+	for _, proc := range procs {
+		if _, err := strconv.Atoi(proc.Name()); err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", proc.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !filepath.IsAbs(target) || !filesystem.IsWithin(target, dir) {
+				continue
+			}
+			if writable(filepath.Join("/proc", proc.Name(), "fdinfo", fd.Name())) {
+				open = append(open, target)
+			}
+		}
+	}
+	return open, nil
+}
+
+// writable reports whether the descriptor an fdinfo file describes was opened
+// for writing.
+func writable(fdinfo string) bool {
+	f, err := os.Open(fdinfo)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "flags:")
+		if !ok {
+			continue
+		}
+		flags, err := strconv.ParseInt(strings.TrimSpace(value), 8, 64)
+		if err != nil {
+			return false
+		}
+		mode := flags & syscall.O_ACCMODE
+		return mode == syscall.O_WRONLY || mode == syscall.O_RDWR
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestOpenWriters(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "01 Allegro.flac")
+	if err := os.WriteFile(path, []byte("fLaC"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	if open, err := openWriters(dir); err != nil || len(open) > 0 {
+		t.Errorf("openWriters() with a reader = %q, %v; want none", open, err)
+	}
+
+	writer, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open, err := openWriters(dir); err != nil || !slices.Contains(open, resolved) {
+		t.Errorf("openWriters() with a writer = %q, %v; want %q", open, err, resolved)
+	}
+	if open, err := openWriters(t.TempDir()); err != nil || len(open) > 0 {
+		t.Errorf("openWriters() of another directory = %q, %v; want none", open, err)
+	}
+
+	writer.Close()
+	if open, err := openWriters(dir); err != nil || len(open) > 0 {
+		t.Errorf("openWriters() after closing = %q, %v; want none", open, err)
+	}
+}